@@ -59,7 +59,11 @@ func init() {
 		operatorCmd,
 		osdCmd,
 		mgrCmd,
-		configCmd)
+		configCmd,
+		exportExternalCmd,
+		preflightCmd,
+		prepareHostPathCmd,
+		rgwCmd)
 }
 
 func createContext() *clusterd.Context {
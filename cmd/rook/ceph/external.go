@@ -0,0 +1,92 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ceph
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/cmd/rook/rook"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/daemon/ceph/externalbundle"
+	"github.com/spf13/cobra"
+)
+
+var exportExternalCmd = &cobra.Command{
+	Use:   "export-external-cluster",
+	Short: "Exports the connection bundle of an external cluster",
+	Long: `Creates the users of the consumer cluster and prints the resources needed to
+connect a Rook cluster in external mode to this cluster. The command runs in the
+operator pod, e.g.:
+
+  kubectl -n rook-ceph exec deploy/rook-ceph-operator -- rook ceph export-external-cluster \
+    --cluster-namespace rook-ceph --consumer-namespace rook-ceph-external --rbd-pool replicapool
+
+The JSON output has the format of the output of create-external-cluster-resources.py
+and the bash output is sourced before running import-external-cluster.sh.`,
+}
+
+var (
+	exportClusterNamespace string
+	exportFormat           string
+	exportConfig           externalbundle.Config
+)
+
+func init() {
+	exportExternalCmd.Flags().StringVar(&exportClusterNamespace, "cluster-namespace", "", "the namespace of the CephCluster to export")
+	if err := exportExternalCmd.MarkFlagRequired("cluster-namespace"); err != nil {
+		panic(err)
+	}
+	exportExternalCmd.Flags().StringVar(&exportConfig.Namespace, "consumer-namespace", "rook-ceph-external", "the namespace of the CephCluster in the consumer cluster")
+	exportExternalCmd.Flags().StringVar(&exportConfig.ConsumerName, "consumer-name", "", "the name of the consumer cluster added to the restricted users, defaults to the consumer namespace")
+	exportExternalCmd.Flags().StringVar(&exportConfig.RBDPool, "rbd-pool", "", "the pool of the rbd volumes")
+	exportExternalCmd.Flags().StringVar(&exportConfig.RBDMetadataECPool, "rbd-metadata-ec-pool", "", "the replicated metadata pool when the rbd pool is erasure coded")
+	exportExternalCmd.Flags().StringVar(&exportConfig.CephFSFilesystem, "cephfs-filesystem", "", "the filesystem of the cephfs volumes")
+	exportExternalCmd.Flags().StringVar(&exportConfig.CephFSDataPool, "cephfs-data-pool", "", "the data pool of the cephfs filesystem")
+	exportExternalCmd.Flags().StringVar(&exportConfig.RGWEndpoint, "rgw-endpoint", "", "the endpoint of the object store as <host>:<port>")
+	exportExternalCmd.Flags().StringVar(&exportConfig.RGWPoolPrefix, "rgw-pool-prefix", "default", "the prefix of the pools of the object store")
+	exportExternalCmd.Flags().BoolVar(&exportConfig.RestrictedAuth, "restricted-auth", false, "restrict the csi users to the rbd pool and the cephfs filesystem")
+	exportExternalCmd.Flags().StringVar(&exportFormat, "format", "json", "the format of the bundle (json or bash)")
+	exportExternalCmd.RunE = exportExternalCluster
+}
+
+func exportExternalCluster(cmd *cobra.Command, args []string) error {
+	rook.SetLogLevel()
+
+	if exportFormat != "json" && exportFormat != "bash" {
+		return errors.Errorf("invalid format %q, expected json or bash", exportFormat)
+	}
+
+	clusterInfo := cephclient.AdminClusterInfo(cmd.Context(), exportClusterNamespace, "")
+	bundle, err := externalbundle.Generate(rook.NewContext(), clusterInfo, exportConfig)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate the external cluster bundle")
+	}
+
+	// the bundle is printed to stdout to be redirected to a file, the logs go to stderr
+	if exportFormat == "bash" {
+		fmt.Print(bundle.Exports())
+		return nil
+	}
+	output, err := json.Marshal(bundle.Resources)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the external cluster bundle")
+	}
+	fmt.Println(string(output))
+	return nil
+}
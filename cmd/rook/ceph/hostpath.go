@@ -0,0 +1,59 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ceph
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/cmd/rook/rook"
+	"github.com/rook/rook/pkg/daemon/ceph/hostpath"
+	"github.com/spf13/cobra"
+)
+
+var prepareHostPathCmd = &cobra.Command{
+	Use:   "prepare-host-path",
+	Short: "Prepares the dataDirHostPath of the node",
+	Long: `Prepares the dataDirHostPath of the node and prints the reasons of the failures
+as a JSON list. The operator runs the command in a job on each node with the
+cmd-reporter to collect the results.`,
+}
+
+var hostPathConfig hostpath.Config
+
+func init() {
+	prepareHostPathCmd.Flags().StringVar(&hostPathConfig.Path, "path", "", "the dataDirHostPath")
+	prepareHostPathCmd.Flags().StringVar(&hostPathConfig.NodePath, "node-path", "", "the directory of the data on the node, the dataDirHostPath is linked to it")
+	prepareHostPathCmd.Flags().StringVar(&hostPathConfig.SELinuxType, "selinux-type", "", "the SELinux type of the data directory")
+	prepareHostPathCmd.Flags().BoolVar(&hostPathConfig.AllowOverlay, "allow-overlay", false, "allow the data directory on an overlay filesystem")
+	prepareHostPathCmd.RunE = runPrepareHostPath
+}
+
+func runPrepareHostPath(cmd *cobra.Command, args []string) error {
+	rook.SetLogLevel()
+	rook.LogStartupInfo(prepareHostPathCmd.Flags())
+
+	failures := hostpath.Prepare(hostPathConfig)
+	output, err := json.Marshal(failures)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the failures of the preparation")
+	}
+	// the failures are printed to stdout to be collected by the cmd-reporter, the logs go to stderr
+	fmt.Println(string(output))
+	return nil
+}
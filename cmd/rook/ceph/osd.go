@@ -62,6 +62,7 @@ var osdRemoveCmd = &cobra.Command{
 var (
 	osdDataDeviceFilter     string
 	osdDataDevicePathFilter string
+	osdDriveGroups          string
 	ownerRefID              string
 	clusterName             string
 	osdID                   int
@@ -90,6 +91,7 @@ func addOSDFlags(command *cobra.Command) {
 	provisionCmd.Flags().StringVar(&cfg.devices, "data-devices", "", "comma separated list of devices to use for storage")
 	provisionCmd.Flags().StringVar(&osdDataDeviceFilter, "data-device-filter", "", "a regex filter for the device names to use, or \"all\"")
 	provisionCmd.Flags().StringVar(&osdDataDevicePathFilter, "data-device-path-filter", "", "a regex filter for the device path names to use")
+	provisionCmd.Flags().StringVar(&osdDriveGroups, "drive-groups", "", "JSON list of ceph-volume drive groups to provision the OSDs from")
 	provisionCmd.Flags().StringVar(&cfg.metadataDevice, "metadata-device", "", "device to use for metadata (e.g. a high performance SSD/NVMe device)")
 	provisionCmd.Flags().BoolVar(&cfg.forceFormat, "force-format", false,
 		"true to force the format of any specified devices, even if they already have a filesystem.  BE CAREFUL!")
@@ -202,6 +204,11 @@ func prepareOSD(cmd *cobra.Command, args []string) error {
 		rook.TerminateFatal(err)
 	}
 
+	driveGroups, err := parseDriveGroups(osdDriveGroups)
+	if err != nil {
+		rook.TerminateFatal(errors.Wrapf(err, "failed to parse drive groups (%q)", osdDriveGroups))
+	}
+
 	var dataDevices []osddaemon.DesiredDevice
 	if osdDataDeviceFilter != "" {
 		if cfg.devices != "" || osdDataDevicePathFilter != "" {
@@ -220,7 +227,6 @@ func prepareOSD(cmd *cobra.Command, args []string) error {
 			{Name: osdDataDevicePathFilter, IsDevicePathFilter: true, OSDsPerDevice: cfg.storeConfig.OSDsPerDevice},
 		}
 	} else {
-		var err error
 		dataDevices, err = parseDevices(cfg.devices)
 		if err != nil {
 			rook.TerminateFatal(errors.Wrapf(err, "failed to parse device list (%q)", cfg.devices))
@@ -242,7 +248,7 @@ func prepareOSD(cmd *cobra.Command, args []string) error {
 	clusterInfo.OwnerInfo = ownerInfo
 	clusterInfo.Context = cmd.Context()
 	kv := k8sutil.NewConfigMapKVStore(clusterInfo.Namespace, context.Clientset, ownerInfo)
-	agent := osddaemon.NewAgent(context, dataDevices, cfg.metadataDevice, forceFormat,
+	agent := osddaemon.NewAgent(context, dataDevices, driveGroups, cfg.metadataDevice, forceFormat,
 		cfg.storeConfig, &clusterInfo, cfg.nodeName, kv, cfg.pvcBacked)
 
 	err = osddaemon.Provision(context, agent, crushLocation, topologyAffinity)
@@ -344,7 +350,8 @@ func parseDevices(devices string) ([]osddaemon.DesiredDevice, error) {
 	var result []osddaemon.DesiredDevice
 	for _, cd := range configuredDevices {
 		d := osddaemon.DesiredDevice{
-			Name: cd.ID,
+			Name:     cd.ID,
+			IsFilter: cd.IsFilter,
 		}
 		d.OSDsPerDevice = cd.StoreConfig.OSDsPerDevice
 		d.DatabaseSizeMB = cd.StoreConfig.DatabaseSizeMB
@@ -363,6 +370,26 @@ func parseDevices(devices string) ([]osddaemon.DesiredDevice, error) {
 	return result, nil
 }
 
+// parseDriveGroups parses the JSON list of drive groups set by the operator on the prepare job
+func parseDriveGroups(driveGroups string) ([]osdcfg.DriveGroup, error) {
+	if driveGroups == "" {
+		return nil, nil
+	}
+
+	result := []osdcfg.DriveGroup{}
+	if err := json.Unmarshal([]byte(driveGroups), &result); err != nil {
+		return nil, errors.Wrap(err, "failed to JSON unmarshal drive groups")
+	}
+	for _, driveGroup := range result {
+		if driveGroup.Name == "" || len(driveGroup.Spec) == 0 {
+			return nil, errors.Errorf("drive group must have a name and a spec (%+v)", driveGroup)
+		}
+	}
+
+	logger.Infof("drive groups to configure osds: %d", len(result))
+	return result, nil
+}
+
 // Populate the ceph admin secret from a file
 // This is more secret than using an environment variable for the secret
 // since environment variables are easier to access than a file inside the container.
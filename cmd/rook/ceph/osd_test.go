@@ -169,12 +169,68 @@ func TestParseDesiredDevices(t *testing.T) {
 	assert.False(t, result[2].IsDevicePathFilter)
 	assert.False(t, result[3].IsDevicePathFilter)
 
+	// device filters carry their own configuration
+	configuredDevices = []osdcfg.ConfiguredDevice{
+		{
+			ID: "^nvme",
+			StoreConfig: osdcfg.StoreConfig{
+				OSDsPerDevice: 2,
+				DeviceClass:   "nvme",
+			},
+			IsFilter: true,
+		},
+		{
+			ID: "^sd[b-d]",
+			StoreConfig: osdcfg.StoreConfig{
+				OSDsPerDevice:  1,
+				MetadataDevice: "nvme0n1",
+			},
+			IsFilter: true,
+		},
+	}
+	marshalledDevices, err = json.Marshal(configuredDevices)
+	assert.NoError(t, err)
+	devices = string(marshalledDevices)
+
+	result, err = parseDevices(devices)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(result))
+	assert.Equal(t, "^nvme", result[0].Name)
+	assert.Equal(t, "^sd[b-d]", result[1].Name)
+	assert.True(t, result[0].IsFilter)
+	assert.True(t, result[1].IsFilter)
+	assert.Equal(t, 2, result[0].OSDsPerDevice)
+	assert.Equal(t, "nvme", result[0].DeviceClass)
+	assert.Equal(t, "", result[1].DeviceClass)
+	assert.Equal(t, "", result[0].MetadataDevice)
+	assert.Equal(t, "nvme0n1", result[1].MetadataDevice)
+
 	// check empty devices list
 	result, err = parseDevices("")
 	assert.NoError(t, err)
 	assert.Equal(t, []osddaemon.DesiredDevice{}, result)
 }
 
+func TestParseDriveGroups(t *testing.T) {
+	result, err := parseDriveGroups("")
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+
+	result, err = parseDriveGroups(`[{"name":"hdd","spec":{"data_devices":{"rotational":1}}},{"name":"ssd","spec":{"data_devices":{"rotational":0}}}]`)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(result))
+	assert.Equal(t, "hdd", result[0].Name)
+	assert.JSONEq(t, `{"data_devices":{"rotational":1}}`, string(result[0].Spec))
+	assert.Equal(t, "ssd", result[1].Name)
+
+	// a drive group without spec is not allowed
+	_, err = parseDriveGroups(`[{"name":"hdd"}]`)
+	assert.Error(t, err)
+
+	_, err = parseDriveGroups(`not json`)
+	assert.Error(t, err)
+}
+
 func TestReadSecretFile(t *testing.T) {
 	// Fail if the file does not exist
 	badPath := "/tmp/badpath"
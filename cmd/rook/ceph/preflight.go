@@ -0,0 +1,81 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ceph
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/cmd/rook/rook"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/daemon/ceph/preflight"
+	"github.com/spf13/cobra"
+)
+
+var preflightCmd = &cobra.Command{
+	Use:   "preflight",
+	Short: "Runs the checks of the node before the ceph cluster is created",
+	Long: `Runs the checks of the node before the ceph cluster is created and prints the
+failures and the warnings of the checks as JSON. The operator runs the command in a
+job on each storage node with the cmd-reporter to collect the results.`,
+}
+
+var (
+	preflightChecks        []string
+	preflightKernelModules []string
+	preflightSysctls       map[string]string
+	preflightPorts         []int
+	preflightDevices       preflight.DeviceSelection
+)
+
+func init() {
+	preflightCmd.Flags().StringSliceVar(&preflightChecks, "checks", []string{}, "the checks to run")
+	preflightCmd.Flags().StringSliceVar(&preflightKernelModules, "kernel-modules", []string{}, "the kernel modules that must be loaded or available")
+	preflightCmd.Flags().StringToStringVar(&preflightSysctls, "sysctls", map[string]string{}, "the required values of the kernel parameters")
+	preflightCmd.Flags().IntSliceVar(&preflightPorts, "ports", []int{}, "the ports that must be free on the host network")
+	preflightCmd.Flags().BoolVar(&preflightDevices.AllDevices, "all-devices", false, "whether all the devices are used by the OSDs")
+	preflightCmd.Flags().StringSliceVar(&preflightDevices.Names, "devices", []string{}, "the names of the devices used by the OSDs")
+	// the filters are regular expressions that can contain commas
+	preflightCmd.Flags().StringArrayVar(&preflightDevices.Filters, "device-filter", []string{}, "a filter of the names of the devices used by the OSDs")
+	preflightCmd.Flags().StringArrayVar(&preflightDevices.PathFilters, "device-path-filter", []string{}, "a filter of the paths of the devices used by the OSDs")
+	preflightCmd.RunE = runPreflight
+}
+
+func runPreflight(cmd *cobra.Command, args []string) error {
+	rook.SetLogLevel()
+	rook.LogStartupInfo(preflightCmd.Flags())
+
+	config := preflight.Config{
+		KernelModules: preflightKernelModules,
+		Sysctls:       preflightSysctls,
+		Ports:         preflightPorts,
+		Devices:       preflightDevices,
+	}
+	for _, check := range preflightChecks {
+		config.Checks = append(config.Checks, cephv1.PreflightCheck(check))
+	}
+
+	result := preflight.Run(config)
+	output, err := json.Marshal(result)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the preflight result")
+	}
+	// the result is printed to stdout to be collected by the cmd-reporter, the logs go to stderr
+	fmt.Println(string(output))
+	return nil
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ceph
+
+import (
+	"os"
+
+	"github.com/rook/rook/cmd/rook/rook"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/daemon/ceph/rgw"
+	"github.com/spf13/cobra"
+)
+
+var rgwCmd = &cobra.Command{
+	Use: "rgw",
+}
+
+var rgwOpsLogCmd = &cobra.Command{
+	Use:   "ops-log",
+	Short: "Prints the ops log of an rgw daemon",
+	Long: `Prints the requests that an rgw daemon writes to its ops log socket to stdout,
+one line per request. The command runs in a sidecar of the rgw pods.`,
+}
+
+var (
+	opsLogSocketPath string
+	opsLogFormat     string
+)
+
+func init() {
+	rgwCmd.AddCommand(rgwOpsLogCmd)
+
+	rgwOpsLogCmd.Flags().StringVar(&opsLogSocketPath, "socket", "", "the path of the ops log socket of the rgw daemon")
+	rgwOpsLogCmd.Flags().StringVar(&opsLogFormat, "format", string(cephv1.OpsLogFormatJSON), "the format of the lines of the ops log, json or combined")
+	rgwOpsLogCmd.RunE = runRGWOpsLog
+}
+
+func runRGWOpsLog(cmd *cobra.Command, args []string) error {
+	rook.SetLogLevel()
+	rook.LogStartupInfo(rgwOpsLogCmd.Flags())
+
+	// the ops log is printed to stdout, the logs go to stderr
+	return rgw.PrintOpsLog(cmd.Context(), opsLogSocketPath, cephv1.OpsLogFormat(opsLogFormat), os.Stdout)
+}
@@ -22,6 +22,7 @@ import (
 	"os"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/coreos/pkg/capnslog"
 	netclient "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/clientset/versioned/typed/k8s.cni.cncf.io/v1"
@@ -45,6 +46,8 @@ import (
 const (
 	RookEnvVarPrefix = "ROOK"
 	terminationLog   = "/dev/termination-log"
+	// the cluster info and the ceph versions are loaded again at least this often
+	clusterCacheTTL = time.Minute
 )
 
 var RootCmd = &cobra.Command{
@@ -92,6 +95,7 @@ func NewContext() *clusterd.Context {
 	context := &clusterd.Context{
 		Executor:  &exec.CommandExecutor{},
 		ConfigDir: k8sutil.DataDir,
+		Cache:     clusterd.NewCache(clusterCacheTTL),
 	}
 
 	// Try to read config from in-cluster env
@@ -20,6 +20,8 @@ import (
 	"context"
 	"os"
 	"os/signal"
+	"path"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/rook/rook/cmd/rook/rook"
@@ -47,6 +49,7 @@ func init() {
 	KeyManagementCmd.AddCommand(
 		cliGetSecret(),
 		cliRotateSecret(),
+		cliDecryptKeyring(),
 	)
 }
 
@@ -149,3 +152,70 @@ func rotateSecret(cmd *cobra.Command, args []string) {
 		rook.TerminateFatal(errors.Wrapf(err, "failed to rotate secret %q", secretName))
 	}
 }
+
+// cliDecryptKeyring is the Cobra CLI call
+func cliDecryptKeyring() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "decrypt-keyring encrypted-dir decrypted-dir [encrypted-dir decrypted-dir...]",
+		Short: "Decrypt the keyrings encrypted with the keyring encryption key",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 || len(args)%2 != 0 {
+				return errors.New("expected pairs of encrypted and decrypted dirs")
+			}
+			return nil
+		},
+		Run: decryptKeyring,
+	}
+	return cmd
+}
+
+// decryptKeyring decrypts the files of the encrypted dirs to the decrypted dirs. The decrypted dirs are memory backed
+// so the decrypted keyrings are never written to a disk.
+func decryptKeyring(cmd *cobra.Command, args []string) {
+	rook.SetLogLevel()
+	ctx, cancel := signal.NotifyContext(context.Background(), operator.ShutdownSignals...)
+	defer cancel()
+
+	namespace := os.Getenv(k8sutil.PodNamespaceEnvVar)
+	if namespace == "" {
+		rook.TerminateFatal(errors.New("failed to find pod namespace"))
+	}
+	clusterInfo := client.NewClusterInfo(namespace, "")
+	clusterInfo.Context = ctx
+
+	// the key is only fetched from the kms if a keyring is encrypted
+	kek := ""
+	for i := 0; i < len(args); i += 2 {
+		encryptedDir, decryptedDir := args[i], args[i+1]
+		files, err := os.ReadDir(encryptedDir)
+		if err != nil {
+			rook.TerminateFatal(errors.Wrapf(err, "failed to read encrypted dir %q", encryptedDir))
+		}
+		for _, file := range files {
+			// skip the hidden entries of the secret volumes
+			if strings.HasPrefix(file.Name(), ".") {
+				continue
+			}
+			value, err := os.ReadFile(path.Join(encryptedDir, file.Name()))
+			if err != nil {
+				rook.TerminateFatal(errors.Wrapf(err, "failed to read %q", file.Name()))
+			}
+			if kms.IsEncryptedKeyringSecret(string(value)) && kek == "" {
+				kmsConfig, err := kms.NewKeyringEncryptionConfigFromEnv(rook.NewContext(), clusterInfo)
+				if err != nil {
+					rook.TerminateFatal(err)
+				}
+				if kek, err = kmsConfig.GetKeyringEncryptionKey(); err != nil {
+					rook.TerminateFatal(err)
+				}
+			}
+			decrypted, err := kms.DecryptKeyringSecret(kek, string(value))
+			if err != nil {
+				rook.TerminateFatal(errors.Wrapf(err, "failed to decrypt %q", file.Name()))
+			}
+			if err := os.WriteFile(path.Join(decryptedDir, file.Name()), []byte(decrypted), 0444); err != nil {
+				rook.TerminateFatal(errors.Wrapf(err, "failed to write decrypted %q", file.Name()))
+			}
+		}
+	}
+}
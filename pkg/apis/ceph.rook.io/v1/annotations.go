@@ -67,6 +67,21 @@ func GetCephExporterAnnotations(a AnnotationsSpec) Annotations {
 	return mergeAllAnnotationsWithKey(a, KeyCephExporter)
 }
 
+// GetRgwAnnotations returns the default annotations of the rgw daemons of the object stores
+func GetRgwAnnotations(a AnnotationsSpec) Annotations {
+	return a[KeyRgw]
+}
+
+// GetMdsAnnotations returns the default annotations of the mds daemons of the filesystems
+func GetMdsAnnotations(a AnnotationsSpec) Annotations {
+	return a[KeyMds]
+}
+
+// GetNFSAnnotations returns the default annotations of the NFS servers
+func GetNFSAnnotations(a AnnotationsSpec) Annotations {
+	return a[KeyNFS]
+}
+
 func GetClusterMetadataAnnotations(a AnnotationsSpec) Annotations {
 	return a[KeyClusterMetadata]
 }
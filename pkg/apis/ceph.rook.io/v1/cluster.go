@@ -18,6 +18,7 @@ package v1
 
 import (
 	"reflect"
+	"regexp"
 
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -28,6 +29,11 @@ import (
 // will be registered for the validating webhook.
 var _ webhook.Validator = &CephCluster{}
 
+var (
+	cephConfigSectionRegex = regexp.MustCompile(`^(global|mon|mgr|osd|mds|client)(\.[a-zA-Z0-9_.-]+)?$`)
+	cephConfigOptionRegex  = regexp.MustCompile(`^[a-zA-Z0-9_ -]+$`)
+)
+
 // RequireMsgr2 checks if the network settings require the msgr2 protocol
 func (c *ClusterSpec) RequireMsgr2() bool {
 	if c.Network.Connections == nil {
@@ -49,6 +55,22 @@ func (c *ClusterSpec) IsStretchCluster() bool {
 	return c.Mon.StretchCluster != nil && len(c.Mon.StretchCluster.Zones) > 0
 }
 
+// ValidateCephConfig returns an error if a section or an option of the Ceph config of the cluster is invalid. The
+// sections are the daemon types, optionally followed by a daemon name, e.g. "osd" or "osd.1".
+func ValidateCephConfig(cephConfig map[string]map[string]string) error {
+	for section, options := range cephConfig {
+		if !cephConfigSectionRegex.MatchString(section) {
+			return errors.Errorf("invalid ceph config section %q, expecting global, mon, mgr, osd, mds or client optionally followed by a daemon name", section)
+		}
+		for option := range options {
+			if !cephConfigOptionRegex.MatchString(option) {
+				return errors.Errorf("invalid ceph config option %q in section %q", option, section)
+			}
+		}
+	}
+	return nil
+}
+
 func (c *CephCluster) ValidateCreate() error {
 	logger.Infof("validate create cephcluster %q", c.ObjectMeta.Name)
 	//If external mode enabled, then check if other fields are empty
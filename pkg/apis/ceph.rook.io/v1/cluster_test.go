@@ -111,3 +111,16 @@ func TestCephClusterValidateUpdate(t *testing.T) {
 	err = uc.ValidateUpdate(c)
 	assert.NoError(t, err)
 }
+
+func TestValidateCephConfig(t *testing.T) {
+	assert.NoError(t, ValidateCephConfig(nil))
+	assert.NoError(t, ValidateCephConfig(map[string]map[string]string{
+		"global":           {"osd_pool_default_size": "3"},
+		"osd.1":            {"osd memory target": "4294967296"},
+		"client.rgw.store": {"rgw-thread-pool-size": "512"},
+	}))
+	assert.Error(t, ValidateCephConfig(map[string]map[string]string{"rgw": {"rgw_thread_pool_size": "512"}}))
+	assert.Error(t, ValidateCephConfig(map[string]map[string]string{"osd/class:ssd": {"osd_memory_target": "1"}}))
+	assert.Error(t, ValidateCephConfig(map[string]map[string]string{"osd": {"": "1"}}))
+	assert.Error(t, ValidateCephConfig(map[string]map[string]string{"osd": {"osd_memory_target=1": "1"}}))
+}
@@ -16,6 +16,87 @@ limitations under the License.
 
 package v1
 
+import (
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// compile-time assertions ensures CephFilesystem implements webhook.Validator so a webhook builder
+// will be registered for the validating webhook.
+var _ webhook.Validator = &CephFilesystem{}
+
+func (f *CephFilesystem) ValidateCreate() error {
+	logger.Infof("validate create cephfilesystem %v", f)
+	return ValidateFilesystemSpec(f)
+}
+
+// ValidateFilesystemSpec validates the settings of a CephFilesystem that do not depend on the state
+// of the ceph cluster
+func ValidateFilesystemSpec(f *CephFilesystem) error {
+	if f.Spec.MetadataServer.ActiveCount < 1 {
+		return errors.New("metadataServer.activeCount must be at least 1")
+	}
+	// No data pool means that we expect the fs to exist already
+	if len(f.Spec.DataPools) == 0 {
+		return nil
+	}
+	if f.Spec.MetadataPool.IsErasureCoded() {
+		return errors.New("invalid metadata pool: the metadata pool cannot be erasure coded")
+	}
+	if err := validatePoolSpec(NamedPoolSpec{PoolSpec: f.Spec.MetadataPool}); err != nil {
+		return errors.Wrap(err, "invalid metadata pool")
+	}
+	for i, p := range f.Spec.DataPools {
+		if err := validatePoolSpec(p); err != nil {
+			return errors.Wrapf(err, "invalid data pool %d", i)
+		}
+	}
+	return nil
+}
+
+func (f *CephFilesystem) ValidateUpdate(old runtime.Object) error {
+	logger.Info("validate update cephfilesystem")
+	if err := ValidateFilesystemSpec(f); err != nil {
+		return err
+	}
+	ofs := old.(*CephFilesystem)
+	if err := ValidatePoolSpecUpdate(&ofs.Spec.MetadataPool, &f.Spec.MetadataPool); err != nil {
+		return errors.Wrap(err, "invalid update of metadata pool")
+	}
+	for i, p := range f.Spec.DataPools {
+		oldPool, ok := ofs.findDataPool(i, p.Name)
+		if !ok {
+			continue
+		}
+		if err := ValidatePoolSpecUpdate(&oldPool.PoolSpec, &p.PoolSpec); err != nil {
+			return errors.Wrapf(err, "invalid update of data pool %d", i)
+		}
+	}
+	return nil
+}
+
+// findDataPool returns the data pool with the given name, or the one at the given index when the
+// pools are not named
+func (f *CephFilesystem) findDataPool(index int, name string) (NamedPoolSpec, bool) {
+	if name != "" {
+		for _, p := range f.Spec.DataPools {
+			if p.Name == name {
+				return p, true
+			}
+		}
+		return NamedPoolSpec{}, false
+	}
+	if index < len(f.Spec.DataPools) && f.Spec.DataPools[index].Name == "" {
+		return f.Spec.DataPools[index], true
+	}
+	return NamedPoolSpec{}, false
+}
+
+func (f *CephFilesystem) ValidateDelete() error {
+	return nil
+}
+
 func (c *CephFilesystem) GetStatusConditions() *[]Condition {
 	return &c.Status.Conditions
 }
@@ -0,0 +1,105 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateFilesystemSpec(t *testing.T) {
+	f := &CephFilesystem{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myfs",
+			Namespace: "rook-ceph",
+		},
+		Spec: FilesystemSpec{
+			MetadataPool: PoolSpec{Replicated: ReplicatedSpec{Size: 3}},
+			DataPools: []NamedPoolSpec{
+				{Name: "replicated", PoolSpec: PoolSpec{Replicated: ReplicatedSpec{Size: 3}}},
+				{Name: "ec", PoolSpec: PoolSpec{ErasureCoded: ErasureCodedSpec{DataChunks: 2, CodingChunks: 1}}},
+			},
+			MetadataServer: MetadataServerSpec{ActiveCount: 1},
+		},
+	}
+	assert.NoError(t, f.ValidateCreate())
+
+	t.Run("no active mds", func(t *testing.T) {
+		fs := f.DeepCopy()
+		fs.Spec.MetadataServer.ActiveCount = 0
+		assert.Error(t, fs.ValidateCreate())
+	})
+
+	t.Run("erasure coded metadata pool", func(t *testing.T) {
+		fs := f.DeepCopy()
+		fs.Spec.MetadataPool = PoolSpec{ErasureCoded: ErasureCodedSpec{DataChunks: 2, CodingChunks: 1}}
+		assert.Error(t, fs.ValidateCreate())
+	})
+
+	t.Run("invalid data pool", func(t *testing.T) {
+		fs := f.DeepCopy()
+		fs.Spec.DataPools[1].ErasureCoded.DataChunks = 1
+		assert.Error(t, fs.ValidateCreate())
+	})
+
+	t.Run("existing filesystem", func(t *testing.T) {
+		fs := f.DeepCopy()
+		fs.Spec.DataPools = nil
+		fs.Spec.MetadataPool = PoolSpec{}
+		assert.NoError(t, fs.ValidateCreate())
+	})
+}
+
+func TestCephFilesystemValidateUpdate(t *testing.T) {
+	f := &CephFilesystem{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myfs",
+			Namespace: "rook-ceph",
+		},
+		Spec: FilesystemSpec{
+			MetadataPool: PoolSpec{Replicated: ReplicatedSpec{Size: 3}},
+			DataPools: []NamedPoolSpec{
+				{PoolSpec: PoolSpec{Replicated: ReplicatedSpec{Size: 3}}},
+				{Name: "ec", PoolSpec: PoolSpec{ErasureCoded: ErasureCodedSpec{DataChunks: 2, CodingChunks: 1}}},
+			},
+			MetadataServer: MetadataServerSpec{ActiveCount: 1},
+		},
+	}
+
+	up := f.DeepCopy()
+	up.Spec.MetadataServer.ActiveCount = 2
+	up.Spec.DataPools = append(up.Spec.DataPools, NamedPoolSpec{Name: "new", PoolSpec: PoolSpec{Replicated: ReplicatedSpec{Size: 2}}})
+	assert.NoError(t, up.ValidateUpdate(f))
+
+	// the type of the metadata pool cannot be changed
+	up = f.DeepCopy()
+	up.Spec.MetadataPool = PoolSpec{ErasureCoded: ErasureCodedSpec{DataChunks: 2, CodingChunks: 1}}
+	assert.Error(t, up.ValidateUpdate(f))
+
+	// the type of an unnamed data pool cannot be changed
+	up = f.DeepCopy()
+	up.Spec.DataPools[0].PoolSpec = PoolSpec{ErasureCoded: ErasureCodedSpec{DataChunks: 2, CodingChunks: 1}}
+	assert.Error(t, up.ValidateUpdate(f))
+
+	// the chunks of a named data pool cannot be changed, even when the pools are reordered
+	up = f.DeepCopy()
+	up.Spec.DataPools[1].ErasureCoded.DataChunks = 4
+	up.Spec.DataPools[0], up.Spec.DataPools[1] = up.Spec.DataPools[1], up.Spec.DataPools[0]
+	assert.Error(t, up.ValidateUpdate(f))
+}
@@ -20,6 +20,7 @@ const (
 	KeyAll                     = "all"
 	KeyMds             KeyType = "mds"
 	KeyRgw             KeyType = "rgw"
+	KeyNFS             KeyType = "nfs"
 	KeyMon             KeyType = "mon"
 	KeyMonArbiter      KeyType = "arbiter"
 	KeyMgr             KeyType = "mgr"
@@ -24,3 +24,11 @@ func (m *MirroringPeerSpec) HasPeers() bool {
 func (m *FSMirroringSpec) SnapShotScheduleEnabled() bool {
 	return len(m.SnapshotSchedules) != 0
 }
+
+func (m *CephRBDMirror) GetStatusConditions() *[]Condition {
+	return &m.Status.Conditions
+}
+
+func (m *CephFilesystemMirror) GetStatusConditions() *[]Condition {
+	return &m.Status.Conditions
+}
@@ -116,3 +116,7 @@ func (sec *NFSSecuritySpec) Validate() error {
 func volSourceExistsAndIsEmpty(v *v1.VolumeSource) bool {
 	return v != nil && reflect.DeepEqual(*v, v1.VolumeSource{})
 }
+
+func (n *CephNFS) GetStatusConditions() *[]Condition {
+	return &n.Status.Conditions
+}
@@ -51,3 +51,7 @@ func (n *CephBucketNotification) ValidateDelete() error {
 	logger.Infof("validate delete cephbucketnotification %v", n)
 	return nil
 }
+
+func (n *CephBucketNotification) GetStatusConditions() *[]Condition {
+	return &n.Status.Conditions
+}
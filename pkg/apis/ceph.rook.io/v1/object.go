@@ -17,8 +17,17 @@ limitations under the License.
 package v1
 
 import (
+	"fmt"
+	"net"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
@@ -33,10 +42,60 @@ const ServiceServingCertKey = "service.beta.openshift.io/serving-cert-secret-nam
 // so over all it brings up to (63-14-11 = 38) characters for the store name
 const objectStoreNameMaxLen = 38
 
+// the placement target created with the zone of an object store
+const defaultPlacementTarget = "default-placement"
+
+var (
+	// the names of the placement targets and storage classes are joined with dots in the names of their data pools,
+	// e.g. "<store>.rgw.<target>.<class>.data", so they cannot contain dots
+	placementNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+	// the placement targets whose data pool would be a pool of the store, e.g. "<store>.rgw.buckets.data"
+	reservedPlacementTargets = map[string]bool{"buckets": true}
+)
+
 func (s *ObjectStoreSpec) IsMultisite() bool {
 	return s.Zone.Name != ""
 }
 
+// UsesSharedPools returns whether the store keeps its data and metadata in existing pools
+func (s *ObjectStoreSpec) UsesSharedPools() bool {
+	return s.SharedPools.MetadataPoolName != "" || s.SharedPools.DataPoolName != ""
+}
+
+// AllowsUsersInNamespace returns whether the CephObjectStoreUsers of the store can be created in the namespace
+func (s *ObjectStoreSpec) AllowsUsersInNamespace(namespace string) bool {
+	for _, allowed := range s.AllowUsersInNamespaces {
+		if allowed == "*" || allowed == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// StoreNamespacedName returns the namespaced name of the store of the user. The store is in the namespace of the
+// user, unless the store of the spec is set as "<namespace>/<name>".
+func (u *CephObjectStoreUser) StoreNamespacedName() types.NamespacedName {
+	if namespace, name, found := strings.Cut(u.Spec.Store, "/"); found {
+		return types.NamespacedName{Namespace: namespace, Name: name}
+	}
+	return types.NamespacedName{Namespace: u.Namespace, Name: u.Spec.Store}
+}
+
+// CephUserID returns the id of the ceph user of the CephObjectStoreUser. The users in another namespace than their
+// store are created in the rgw tenant named after their namespace, so that the CephObjectStoreUsers of different
+// namespaces with the same name do not share a ceph user.
+func (u *CephObjectStoreUser) CephUserID() string {
+	if u.StoreNamespacedName().Namespace != u.Namespace {
+		return fmt.Sprintf("%s$%s", u.Namespace, u.Name)
+	}
+	return u.Name
+}
+
+// IsAdopted returns whether the store adopts an existing realm, zone group and zone
+func (s *ObjectStoreSpec) IsAdopted() bool {
+	return s.Adopt != nil
+}
+
 func (s *ObjectStoreSpec) IsTLSEnabled() bool {
 	return s.Gateway.SecurePort != 0 && (s.Gateway.SSLCertificateRef != "" || s.GetServiceServingCert() != "")
 }
@@ -58,6 +117,29 @@ func (s *ObjectStoreSpec) IsExternal() bool {
 	return len(s.Gateway.ExternalRgwEndpoints) != 0
 }
 
+// IsSTSEnabled returns whether the Security Token Service of the gateways is enabled
+func (s *ObjectStoreSpec) IsSTSEnabled() bool {
+	return s.STS != nil && s.STS.Enabled
+}
+
+// IsOpsLogEnabled returns whether the ops log sidecar runs in the rgw pods
+func (s *ObjectStoreSpec) IsOpsLogEnabled() bool {
+	return s.Gateway.OpsLog != nil && s.Gateway.OpsLog.Enabled
+}
+
+// IsTracingEnabled returns whether the requests served by the rgw daemons are traced
+func (s *ObjectStoreSpec) IsTracingEnabled() bool {
+	return s.Gateway.Tracing != nil && s.Gateway.Tracing.Enabled
+}
+
+// GetFormat returns the format of the lines of the ops log, "json" by default
+func (o *GatewayOpsLogSpec) GetFormat() OpsLogFormat {
+	if o.Format == "" {
+		return OpsLogFormatJSON
+	}
+	return o.Format
+}
+
 func (s *ObjectStoreSpec) IsHostNetwork(c *ClusterSpec) bool {
 	if s.Gateway.HostNetwork != nil {
 		return *s.Gateway.HostNetwork
@@ -102,6 +184,408 @@ func ValidateObjectSpec(gs *CephObjectStore) error {
 	if gs.Spec.Gateway.Port <= 0 && gs.Spec.Gateway.SecurePort <= 0 {
 		return errors.New("invalid create: either of port or securePort fields should be not be zero")
 	}
+	if gs.Spec.Gateway.Port > 0 && gs.Spec.Gateway.Port == gs.Spec.Gateway.SecurePort {
+		return errors.Errorf("port and securePort cannot both be %d", gs.Spec.Gateway.Port)
+	}
+	if err := validateCloudTiers(gs.Spec.CloudTiers); err != nil {
+		return errors.Wrap(err, "invalid cloud tiers")
+	}
+	if err := validateGatewayConfig(gs.Spec.Gateway.Config); err != nil {
+		return errors.Wrap(err, "invalid gateway config")
+	}
+	if len(gs.Spec.PlacementTargets) > 0 && gs.Spec.IsMultisite() {
+		return errors.New("placement targets cannot be set on an object store in a ceph-object-zone")
+	}
+	if err := validatePlacementTargets(gs.Spec.PlacementTargets, gs.Spec.CloudTiers); err != nil {
+		return errors.Wrap(err, "invalid placement targets")
+	}
+	if err := validateSharedPools(&gs.Spec); err != nil {
+		return errors.Wrap(err, "invalid shared pools")
+	}
+	if err := validateAdopt(&gs.Spec); err != nil {
+		return errors.Wrap(err, "invalid adopt")
+	}
+	if err := validateHosting(&gs.Spec); err != nil {
+		return errors.Wrap(err, "invalid hosting")
+	}
+	if err := validateSTS(&gs.Spec); err != nil {
+		return errors.Wrap(err, "invalid sts")
+	}
+	if err := validateGatewayAllNodes(&gs.Spec); err != nil {
+		return errors.Wrap(err, "invalid gateway")
+	}
+	if err := validateGatewayIngress(&gs.Spec); err != nil {
+		return errors.Wrap(err, "invalid gateway ingress")
+	}
+	if err := validateGatewayProxy(&gs.Spec); err != nil {
+		return errors.Wrap(err, "invalid gateway proxy")
+	}
+	if err := validateGatewayTracing(&gs.Spec); err != nil {
+		return errors.Wrap(err, "invalid gateway tracing")
+	}
+	if err := validateSwift(&gs.Spec); err != nil {
+		return errors.Wrap(err, "invalid swift protocol")
+	}
+	if err := validateBucketIndex(&gs.Spec); err != nil {
+		return errors.Wrap(err, "invalid bucket index")
+	}
+	if err := validateGarbageCollection(&gs.Spec); err != nil {
+		return errors.Wrap(err, "invalid garbage collection")
+	}
+	if err := validateDefaultBucketEncryption(&gs.Spec); err != nil {
+		return errors.Wrap(err, "invalid security")
+	}
+	return nil
+}
+
+func validateDefaultBucketEncryption(spec *ObjectStoreSpec) error {
+	if spec.Security == nil || !spec.Security.DefaultBucketEncryption {
+		return nil
+	}
+	// the rgw of an external store is not configured by rook
+	if !spec.IsExternal() && !spec.Security.ServerSideEncryptionS3.IsEnabled() {
+		return errors.New("the default bucket encryption requires the s3 settings of the server side encryption")
+	}
+	return nil
+}
+
+func validateHosting(spec *ObjectStoreSpec) error {
+	if spec.Hosting == nil {
+		return nil
+	}
+	if spec.IsMultisite() {
+		return errors.New("the dns names of an object store in a ceph-object-zone are set in its zonegroup")
+	}
+	names := map[string]bool{}
+	for _, name := range append([]string{spec.Hosting.DNSName}, spec.Hosting.AdditionalDNSNames...) {
+		if name == "" {
+			continue
+		}
+		if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+			return errors.Errorf("invalid dns name %q: %s", name, strings.Join(errs, ", "))
+		}
+		if names[name] {
+			return errors.Errorf("duplicate dns name %q", name)
+		}
+		names[name] = true
+	}
+	if spec.Hosting.DNSName != "" {
+		for option := range spec.Gateway.Config {
+			if strings.NewReplacer(" ", "_", "-", "_").Replace(option) == "rgw_dns_name" {
+				return errors.Errorf("config option %q cannot be set with the dns name of the hosting", option)
+			}
+		}
+	}
+	return nil
+}
+
+func validateSTS(spec *ObjectStoreSpec) error {
+	if !spec.IsSTSEnabled() {
+		return nil
+	}
+	if spec.IsExternal() {
+		return errors.New("the sts of the gateways of an external object store cannot be configured")
+	}
+	if spec.STS.KeySecretName != "" {
+		if errs := validation.IsDNS1123Subdomain(spec.STS.KeySecretName); len(errs) > 0 {
+			return errors.Errorf("invalid key secret name %q: %s", spec.STS.KeySecretName, strings.Join(errs, ", "))
+		}
+	}
+	for option := range spec.Gateway.Config {
+		switch strings.NewReplacer(" ", "_", "-", "_").Replace(option) {
+		case "rgw_s3_auth_use_sts", "rgw_sts_key":
+			return errors.Errorf("config option %q cannot be set with the sts", option)
+		}
+	}
+	return nil
+}
+
+func validateSharedPools(spec *ObjectStoreSpec) error {
+	if !spec.UsesSharedPools() {
+		return nil
+	}
+	if spec.SharedPools.MetadataPoolName == "" || spec.SharedPools.DataPoolName == "" {
+		return errors.New("both the metadata and the data pool names must be set")
+	}
+	if !reflect.DeepEqual(spec.MetadataPool, PoolSpec{}) || !reflect.DeepEqual(spec.DataPool, PoolSpec{}) {
+		return errors.New("the metadata and data pools of the store cannot be set with shared pools")
+	}
+	if spec.IsMultisite() {
+		return errors.New("the pools of an object store in a ceph-object-zone are set by the zone")
+	}
+	if len(spec.PlacementTargets) > 0 {
+		return errors.New("placement targets cannot be set with shared pools")
+	}
+	return nil
+}
+
+func validateAdopt(spec *ObjectStoreSpec) error {
+	if !spec.IsAdopted() {
+		return nil
+	}
+	if spec.Adopt.Realm == "" || spec.Adopt.ZoneGroup == "" || spec.Adopt.Zone == "" {
+		return errors.New("the realm, zone group and zone names must all be set")
+	}
+	if spec.IsExternal() {
+		return errors.New("an external object store cannot adopt a zone")
+	}
+	if spec.IsMultisite() {
+		return errors.New("an object store in a ceph-object-zone cannot adopt a zone")
+	}
+	if spec.UsesSharedPools() {
+		return errors.New("an object store adopting a zone cannot use shared pools")
+	}
+	if !reflect.DeepEqual(spec.MetadataPool, PoolSpec{}) || !reflect.DeepEqual(spec.DataPool, PoolSpec{}) {
+		return errors.New("the metadata and data pools of the store cannot be set when adopting a zone")
+	}
+	return nil
+}
+
+func validatePlacementTargets(targets []PlacementTargetSpec, tiers []CloudTierSpec) error {
+	names := map[string]bool{}
+	for _, target := range targets {
+		if target.Name == "" {
+			return errors.New("missing placement target name")
+		}
+		if !placementNameRegex.MatchString(target.Name) {
+			return errors.Errorf("invalid placement target name %q, only letters, digits, '_' and '-' are allowed", target.Name)
+		}
+		if reservedPlacementTargets[target.Name] {
+			return errors.Errorf("placement target name %q is reserved for the pools of the object store", target.Name)
+		}
+		if names[target.Name] {
+			return errors.Errorf("duplicate placement target %q", target.Name)
+		}
+		names[target.Name] = true
+		if target.Name == defaultPlacementTarget {
+			if !reflect.DeepEqual(target.DataPool, PoolSpec{}) {
+				return errors.Errorf("the STANDARD storage class of placement target %q is backed by the data pool of the object store", target.Name)
+			}
+		} else if reflect.DeepEqual(target.DataPool, PoolSpec{}) {
+			return errors.Errorf("missing data pool of placement target %q", target.Name)
+		}
+
+		classes := map[string]bool{}
+		for _, class := range target.StorageClasses {
+			if class.Name == "" {
+				return errors.Errorf("missing storage class name in placement target %q", target.Name)
+			}
+			if !placementNameRegex.MatchString(class.Name) {
+				return errors.Errorf("invalid storage class name %q in placement target %q, only letters, digits, '_' and '-' are allowed", class.Name, target.Name)
+			}
+			if class.Name == "STANDARD" {
+				return errors.Errorf("the STANDARD storage class of placement target %q is set with its data pool", target.Name)
+			}
+			if classes[class.Name] {
+				return errors.Errorf("duplicate storage class %q in placement target %q", class.Name, target.Name)
+			}
+			if tier := cloudTierOfStorageClass(tiers, target.Name, class.Name); tier != nil {
+				return errors.Errorf("storage class %q in placement target %q is also a cloud tier", class.Name, target.Name)
+			}
+			classes[class.Name] = true
+			if reflect.DeepEqual(class.DataPool, PoolSpec{}) {
+				return errors.Errorf("missing data pool of storage class %q in placement target %q", class.Name, target.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// cloudTierOfStorageClass returns the cloud tier added as the given storage class of the placement target, if any
+func cloudTierOfStorageClass(tiers []CloudTierSpec, target, class string) *CloudTierSpec {
+	for i, tier := range tiers {
+		tierTarget := tier.PlacementTarget
+		if tierTarget == "" {
+			tierTarget = defaultPlacementTarget
+		}
+		if tierTarget == target && tier.Name == class {
+			return &tiers[i]
+		}
+	}
+	return nil
+}
+
+// validateGatewayConfig rejects the options that Rook sets itself to run the rgw daemons in the zone of the store
+func validateGatewayConfig(config map[string]string) error {
+	for option := range config {
+		if strings.TrimSpace(option) == "" {
+			return errors.New("config option name cannot be empty")
+		}
+		switch strings.NewReplacer(" ", "_", "-", "_").Replace(option) {
+		case "rgw_realm", "rgw_zonegroup", "rgw_zone":
+			return errors.Errorf("config option %q is managed by rook and cannot be overridden", option)
+		}
+	}
+	return nil
+}
+
+func validateGatewayAllNodes(spec *ObjectStoreSpec) error {
+	if spec.Gateway.AllNodes && spec.IsExternal() {
+		return errors.New("the gateways of an external object store cannot run on all the nodes")
+	}
+	return nil
+}
+
+func validateGatewayTracing(spec *ObjectStoreSpec) error {
+	if !spec.IsTracingEnabled() {
+		return nil
+	}
+	if spec.IsExternal() {
+		return errors.New("the requests of an external object store cannot be traced")
+	}
+	if spec.Gateway.Tracing.CollectorSecretName == "" {
+		return errors.New("the secret of the collector must be set to trace the requests")
+	}
+	return nil
+}
+
+func validateGatewayIngress(spec *ObjectStoreSpec) error {
+	ingress := spec.Gateway.Ingress
+	if ingress == nil {
+		return nil
+	}
+	if spec.IsExternal() {
+		return errors.New("the ingress of the gateways of an external object store cannot be configured")
+	}
+	if errs := validation.IsDNS1123Subdomain(ingress.Host); len(errs) > 0 {
+		return errors.Errorf("invalid ingress host %q. %v", ingress.Host, errs)
+	}
+	if spec.Gateway.Port == 0 && spec.Gateway.SecurePort == 0 {
+		return errors.New("the ingress needs a port of the gateways")
+	}
+	return nil
+}
+
+func validateGatewayProxy(spec *ObjectStoreSpec) error {
+	proxy := spec.Gateway.Proxy
+	if proxy == nil {
+		return nil
+	}
+	if spec.IsExternal() {
+		return errors.New("the proxy of the gateways of an external object store cannot be configured")
+	}
+	for _, cidr := range proxy.TrustedProxies {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return errors.Errorf("invalid trusted proxy CIDR %q", cidr)
+		}
+	}
+	if proxy.RemoteAddrHeader == "" {
+		return nil
+	}
+	// the header would be trusted from any client that reaches the gateways
+	if len(proxy.TrustedProxies) == 0 {
+		return errors.New("the trusted proxies must be set with the remote addr header")
+	}
+	for option := range spec.Gateway.Config {
+		if strings.NewReplacer(" ", "_", "-", "_").Replace(option) == "rgw_remote_addr_param" {
+			return errors.Errorf("config option %q cannot be set with the remote addr header", option)
+		}
+	}
+	return nil
+}
+
+func validateSwift(spec *ObjectStoreSpec) error {
+	swift := spec.Protocols.Swift
+	if swift == nil {
+		return nil
+	}
+	if spec.IsExternal() {
+		return errors.New("the swift protocol of the gateways of an external object store cannot be configured")
+	}
+	// the gateways serve the s3 and admin apis at the root, and the swift api and auth at their own prefix
+	urlPrefix, authEntry := "swift", "auth"
+	if swift.UrlPrefix != nil {
+		urlPrefix = *swift.UrlPrefix
+	}
+	if swift.AuthEntry != nil {
+		authEntry = *swift.AuthEntry
+	}
+	for _, prefix := range []string{urlPrefix, authEntry} {
+		if prefix == "" || prefix == "admin" || strings.Contains(prefix, "/") {
+			return errors.Errorf("url prefix %q is reserved or invalid", prefix)
+		}
+	}
+	if urlPrefix == authEntry {
+		return errors.Errorf("url prefix and auth entry %q must be different", urlPrefix)
+	}
+	for option := range spec.Gateway.Config {
+		if strings.HasPrefix(strings.NewReplacer(" ", "_", "-", "_").Replace(option), "rgw_swift_") {
+			return errors.Errorf("config option %q cannot be set with the swift protocol", option)
+		}
+	}
+	return nil
+}
+
+func validateBucketIndex(spec *ObjectStoreSpec) error {
+	bucketIndex := spec.BucketIndex
+	if bucketIndex == nil {
+		return nil
+	}
+	if spec.IsExternal() {
+		return errors.New("the bucket index of the gateways of an external object store cannot be configured")
+	}
+	options := map[string]bool{
+		"rgw_dynamic_resharding":               bucketIndex.DynamicResharding != nil,
+		"rgw_override_bucket_index_max_shards": bucketIndex.OverrideMaxShards != nil,
+	}
+	for option := range spec.Gateway.Config {
+		if options[strings.NewReplacer(" ", "_", "-", "_").Replace(option)] {
+			return errors.Errorf("config option %q cannot be set with the bucket index", option)
+		}
+	}
+	return nil
+}
+
+func validateGarbageCollection(spec *ObjectStoreSpec) error {
+	gc := spec.GarbageCollection
+	if gc == nil {
+		return nil
+	}
+	if spec.IsExternal() {
+		return errors.New("the garbage collection of the gateways of an external object store cannot be configured")
+	}
+	if gc.ProcessorPeriod != nil && gc.ProcessorPeriod.Duration < time.Second {
+		return errors.Errorf("processor period %q must be at least one second", gc.ProcessorPeriod.Duration)
+	}
+	options := map[string]bool{
+		"rgw_gc_max_objs":          gc.MaxObjects != nil,
+		"rgw_gc_processor_period":  gc.ProcessorPeriod != nil,
+		"rgw_gc_max_concurrent_io": gc.MaxConcurrentIO != nil,
+	}
+	for option := range spec.Gateway.Config {
+		if options[strings.NewReplacer(" ", "_", "-", "_").Replace(option)] {
+			return errors.Errorf("config option %q cannot be set with the garbage collection", option)
+		}
+	}
+	return nil
+}
+
+func validateCloudTiers(tiers []CloudTierSpec) error {
+	names := map[string]bool{}
+	for _, tier := range tiers {
+		if tier.Name == "" {
+			return errors.New("missing cloud tier name")
+		}
+		if tier.Name == "STANDARD" {
+			return errors.New("cloud tier cannot replace the STANDARD storage class")
+		}
+		if names[tier.Name] {
+			return errors.Errorf("duplicate cloud tier %q", tier.Name)
+		}
+		names[tier.Name] = true
+		if tier.Endpoint == "" {
+			return errors.Errorf("missing endpoint of cloud tier %q", tier.Name)
+		}
+		if tier.CredentialsSecretName == "" {
+			return errors.Errorf("missing credentials secret of cloud tier %q", tier.Name)
+		}
+		// the settings are passed to rgw as a comma separated list
+		for _, value := range []string{tier.Endpoint, tier.Region, tier.TargetPath} {
+			if strings.ContainsAny(value, ",=") {
+				return errors.Errorf("settings of cloud tier %q cannot contain ',' or '='", tier.Name)
+			}
+		}
+	}
 	return nil
 }
 
@@ -111,6 +595,39 @@ func (o *CephObjectStore) ValidateUpdate(old runtime.Object) error {
 	if err != nil {
 		return err
 	}
+	oos := old.(*CephObjectStore)
+	if err := ValidatePoolSpecUpdate(&oos.Spec.MetadataPool, &o.Spec.MetadataPool); err != nil {
+		return errors.Wrap(err, "invalid update of metadata pool")
+	}
+	if err := ValidatePoolSpecUpdate(&oos.Spec.DataPool, &o.Spec.DataPool); err != nil {
+		return errors.Wrap(err, "invalid update of data pool")
+	}
+	if oos.Spec.SharedPools != o.Spec.SharedPools {
+		return errors.New("the shared pools of an object store cannot be changed")
+	}
+	if oos.Spec.Zone.Name != o.Spec.Zone.Name {
+		return errors.Errorf("the zone of an object store cannot be changed from %q to %q", oos.Spec.Zone.Name, o.Spec.Zone.Name)
+	}
+	for _, oldTarget := range oos.Spec.PlacementTargets {
+		for _, target := range o.Spec.PlacementTargets {
+			if target.Name != oldTarget.Name {
+				continue
+			}
+			if err := ValidatePoolSpecUpdate(&oldTarget.DataPool, &target.DataPool); err != nil {
+				return errors.Wrapf(err, "invalid update of data pool of placement target %q", target.Name)
+			}
+			for _, oldClass := range oldTarget.StorageClasses {
+				for _, class := range target.StorageClasses {
+					if class.Name != oldClass.Name {
+						continue
+					}
+					if err := ValidatePoolSpecUpdate(&oldClass.DataPool, &class.DataPool); err != nil {
+						return errors.Wrapf(err, "invalid update of data pool of storage class %q in placement target %q", class.Name, target.Name)
+					}
+				}
+			}
+		}
+	}
 	return nil
 }
 
@@ -141,3 +658,15 @@ func (e *EndpointAddress) String() string {
 	}
 	return e.IP
 }
+
+func (u *CephObjectStoreUser) GetStatusConditions() *[]Condition {
+	return &u.Status.Conditions
+}
+
+func (r *CephObjectRealm) GetStatusConditions() *[]Condition {
+	return &r.Status.Conditions
+}
+
+func (z *CephObjectZoneGroup) GetStatusConditions() *[]Condition {
+	return &z.Status.Conditions
+}
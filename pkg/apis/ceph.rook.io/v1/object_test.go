@@ -18,9 +18,11 @@ package v1
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 func TestValidateObjectStoreSpec(t *testing.T) {
@@ -39,6 +41,12 @@ func TestValidateObjectStoreSpec(t *testing.T) {
 	err := ValidateObjectSpec(o)
 	assert.NoError(t, err)
 
+	// when port and securePort are the same
+	o.Spec.Gateway.SecurePort = 1
+	err = ValidateObjectSpec(o)
+	assert.Error(t, err)
+	o.Spec.Gateway.SecurePort = 0
+
 	// when both port and securePort are o
 	o.Spec.Gateway.Port = 0
 	err = ValidateObjectSpec(o)
@@ -59,6 +67,395 @@ func TestValidateObjectStoreSpec(t *testing.T) {
 	err = ValidateObjectSpec(o)
 	assert.Error(t, err)
 }
+
+func TestCephObjectStoreValidateUpdate(t *testing.T) {
+	o := &CephObjectStore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-store",
+			Namespace: "rook-ceph",
+		},
+		Spec: ObjectStoreSpec{
+			Gateway: GatewaySpec{Port: 80},
+			Zone:    ZoneSpec{Name: "zone-a"},
+		},
+	}
+	up := o.DeepCopy()
+	up.Spec.Gateway.Port = 8080
+	assert.NoError(t, up.ValidateUpdate(o))
+
+	// the zone cannot be changed
+	up.Spec.Zone.Name = "zone-b"
+	assert.Error(t, up.ValidateUpdate(o))
+}
+
+func TestValidateCloudTiers(t *testing.T) {
+	tier := CloudTierSpec{Name: "CLOUDTIER", Endpoint: "https://s3.amazonaws.com", CredentialsSecretName: "aws-creds"}
+	assert.NoError(t, validateCloudTiers([]CloudTierSpec{tier}))
+
+	// duplicate name
+	assert.Error(t, validateCloudTiers([]CloudTierSpec{tier, tier}))
+
+	// the default storage class
+	standard := tier
+	standard.Name = "STANDARD"
+	assert.Error(t, validateCloudTiers([]CloudTierSpec{standard}))
+
+	// missing endpoint
+	noEndpoint := tier
+	noEndpoint.Endpoint = ""
+	assert.Error(t, validateCloudTiers([]CloudTierSpec{noEndpoint}))
+
+	// missing credentials
+	noCredentials := tier
+	noCredentials.CredentialsSecretName = ""
+	assert.Error(t, validateCloudTiers([]CloudTierSpec{noCredentials}))
+
+	// separator in a setting
+	badPath := tier
+	badPath.TargetPath = "a,b"
+	assert.Error(t, validateCloudTiers([]CloudTierSpec{badPath}))
+}
+
+func TestValidatePlacementTargets(t *testing.T) {
+	pool := PoolSpec{ErasureCoded: ErasureCodedSpec{DataChunks: 2, CodingChunks: 1}, DeviceClass: "hdd"}
+	coldClass := PlacementStorageClassSpec{Name: "STANDARD_IA", DataPool: pool}
+	defaultTarget := PlacementTargetSpec{Name: "default-placement", StorageClasses: []PlacementStorageClassSpec{coldClass}}
+	archiveTarget := PlacementTargetSpec{Name: "archive", DataPool: pool}
+	assert.NoError(t, validatePlacementTargets([]PlacementTargetSpec{defaultTarget, archiveTarget}, nil))
+
+	// duplicate name
+	assert.Error(t, validatePlacementTargets([]PlacementTargetSpec{archiveTarget, archiveTarget}, nil))
+
+	// the data pool of the default placement target is the data pool of the store
+	badDefault := defaultTarget
+	badDefault.DataPool = pool
+	assert.Error(t, validatePlacementTargets([]PlacementTargetSpec{badDefault}, nil))
+
+	// missing data pool of a new placement target
+	noPool := archiveTarget
+	noPool.DataPool = PoolSpec{}
+	assert.Error(t, validatePlacementTargets([]PlacementTargetSpec{noPool}, nil))
+
+	// the STANDARD storage class
+	standard := defaultTarget
+	standard.StorageClasses = []PlacementStorageClassSpec{{Name: "STANDARD", DataPool: pool}}
+	assert.Error(t, validatePlacementTargets([]PlacementTargetSpec{standard}, nil))
+
+	// duplicate storage class
+	duplicate := defaultTarget
+	duplicate.StorageClasses = []PlacementStorageClassSpec{coldClass, coldClass}
+	assert.Error(t, validatePlacementTargets([]PlacementTargetSpec{duplicate}, nil))
+
+	// missing data pool of a storage class
+	noClassPool := defaultTarget
+	noClassPool.StorageClasses = []PlacementStorageClassSpec{{Name: "STANDARD_IA"}}
+	assert.Error(t, validatePlacementTargets([]PlacementTargetSpec{noClassPool}, nil))
+
+	// the data pool of the "buckets" target would be the data pool of the store
+	reserved := archiveTarget
+	reserved.Name = "buckets"
+	assert.Error(t, validatePlacementTargets([]PlacementTargetSpec{reserved}, nil))
+
+	// dots would make the pool names of the targets and classes collide
+	dotted := archiveTarget
+	dotted.Name = "archive.cold"
+	assert.Error(t, validatePlacementTargets([]PlacementTargetSpec{dotted}, nil))
+	dottedClass := defaultTarget
+	dottedClass.StorageClasses = []PlacementStorageClassSpec{{Name: "COLD.IA", DataPool: pool}}
+	assert.Error(t, validatePlacementTargets([]PlacementTargetSpec{dottedClass}, nil))
+
+	// a storage class that is also a cloud tier of the same placement target
+	tiers := []CloudTierSpec{{Name: "STANDARD_IA"}}
+	assert.Error(t, validatePlacementTargets([]PlacementTargetSpec{defaultTarget}, tiers))
+	tiers[0].PlacementTarget = "archive"
+	assert.NoError(t, validatePlacementTargets([]PlacementTargetSpec{defaultTarget}, tiers))
+}
+
+func TestValidateSharedPools(t *testing.T) {
+	spec := &ObjectStoreSpec{}
+	assert.NoError(t, validateSharedPools(spec))
+
+	spec.SharedPools = ObjectSharedPoolsSpec{MetadataPoolName: "rgw-meta", DataPoolName: "rgw-data"}
+	assert.True(t, spec.UsesSharedPools())
+	assert.NoError(t, validateSharedPools(spec))
+
+	// missing data pool
+	spec.SharedPools.DataPoolName = ""
+	assert.Error(t, validateSharedPools(spec))
+	spec.SharedPools.DataPoolName = "rgw-data"
+
+	// pools of the store
+	spec.DataPool = PoolSpec{Replicated: ReplicatedSpec{Size: 3}}
+	assert.Error(t, validateSharedPools(spec))
+	spec.DataPool = PoolSpec{}
+
+	// store in a zone
+	spec.Zone.Name = "zone-a"
+	assert.Error(t, validateSharedPools(spec))
+}
+
+func TestValidateAdopt(t *testing.T) {
+	spec := &ObjectStoreSpec{}
+	assert.NoError(t, validateAdopt(spec))
+
+	spec.Adopt = &ObjectStoreAdoptSpec{Realm: "default", ZoneGroup: "default", Zone: "default"}
+	assert.True(t, spec.IsAdopted())
+	assert.NoError(t, validateAdopt(spec))
+
+	// missing zone
+	spec.Adopt.Zone = ""
+	assert.Error(t, validateAdopt(spec))
+	spec.Adopt.Zone = "default"
+
+	// pools of the store
+	spec.MetadataPool = PoolSpec{Replicated: ReplicatedSpec{Size: 3}}
+	assert.Error(t, validateAdopt(spec))
+	spec.MetadataPool = PoolSpec{}
+
+	// shared pools
+	spec.SharedPools = ObjectSharedPoolsSpec{MetadataPoolName: "rgw-meta", DataPoolName: "rgw-data"}
+	assert.Error(t, validateAdopt(spec))
+	spec.SharedPools = ObjectSharedPoolsSpec{}
+
+	// store in a zone
+	spec.Zone.Name = "zone-a"
+	assert.Error(t, validateAdopt(spec))
+}
+
+func TestValidateHosting(t *testing.T) {
+	spec := &ObjectStoreSpec{}
+	assert.NoError(t, validateHosting(spec))
+
+	spec.Hosting = &ObjectStoreHostingSpec{DNSName: "s3.example.com", AdditionalDNSNames: []string{"s3.internal.example.com"}}
+	assert.NoError(t, validateHosting(spec))
+
+	// additional names only
+	spec.Hosting.DNSName = ""
+	assert.NoError(t, validateHosting(spec))
+	spec.Hosting.DNSName = "s3.example.com"
+
+	// invalid name
+	spec.Hosting.AdditionalDNSNames = []string{"S3_example"}
+	assert.Error(t, validateHosting(spec))
+
+	// duplicate name
+	spec.Hosting.AdditionalDNSNames = []string{"s3.example.com"}
+	assert.Error(t, validateHosting(spec))
+	spec.Hosting.AdditionalDNSNames = nil
+
+	// dns name in the gateway config
+	spec.Gateway.Config = map[string]string{"rgw dns name": "other.example.com"}
+	assert.Error(t, validateHosting(spec))
+	spec.Gateway.Config = nil
+
+	// store in a zone
+	spec.Zone.Name = "zone-a"
+	assert.Error(t, validateHosting(spec))
+}
+
+func TestValidateSTS(t *testing.T) {
+	spec := &ObjectStoreSpec{}
+	assert.NoError(t, validateSTS(spec))
+
+	spec.STS = &ObjectStoreSTSSpec{Enabled: true}
+	assert.NoError(t, validateSTS(spec))
+
+	spec.STS.KeySecretName = "my-sts-key"
+	assert.NoError(t, validateSTS(spec))
+
+	// invalid secret name
+	spec.STS.KeySecretName = "My_Key"
+	assert.Error(t, validateSTS(spec))
+	spec.STS.KeySecretName = ""
+
+	// sts options in the gateway config
+	spec.Gateway.Config = map[string]string{"rgw sts key": "abcdefghijklmnop"}
+	assert.Error(t, validateSTS(spec))
+	spec.Gateway.Config = nil
+
+	// external gateways
+	spec.Gateway.ExternalRgwEndpoints = []EndpointAddress{{IP: "192.168.0.1"}}
+	assert.Error(t, validateSTS(spec))
+
+	// disabled
+	spec.STS.Enabled = false
+	assert.NoError(t, validateSTS(spec))
+}
+
+func TestValidateGatewayProxy(t *testing.T) {
+	spec := &ObjectStoreSpec{}
+	assert.NoError(t, validateGatewayProxy(spec))
+
+	spec.Gateway.Proxy = &GatewayProxySpec{RemoteAddrHeader: "X-Forwarded-For", TrustedProxies: []string{"10.0.0.0/8", "fd00::/8"}}
+	assert.NoError(t, validateGatewayProxy(spec))
+
+	// invalid cidr
+	spec.Gateway.Proxy.TrustedProxies = []string{"10.0.0.1"}
+	assert.Error(t, validateGatewayProxy(spec))
+
+	// remote addr header without trusted proxies
+	spec.Gateway.Proxy.TrustedProxies = nil
+	assert.Error(t, validateGatewayProxy(spec))
+	spec.Gateway.Proxy.TrustedProxies = []string{"10.0.0.0/8"}
+
+	// remote addr param in the gateway config
+	spec.Gateway.Config = map[string]string{"rgw remote addr param": "HTTP_X_REAL_IP"}
+	assert.Error(t, validateGatewayProxy(spec))
+	spec.Gateway.Config = nil
+
+	// external gateways
+	spec.Gateway.ExternalRgwEndpoints = []EndpointAddress{{IP: "192.168.0.1"}}
+	assert.Error(t, validateGatewayProxy(spec))
+}
+
+func TestValidateSwift(t *testing.T) {
+	spec := &ObjectStoreSpec{}
+	assert.NoError(t, validateSwift(spec))
+
+	prefix := func(p string) *string { return &p }
+	spec.Protocols.Swift = &SwiftSpec{UrlPrefix: prefix("object"), AuthEntry: prefix("swift")}
+	assert.NoError(t, validateSwift(spec))
+
+	// reserved prefix
+	spec.Protocols.Swift.UrlPrefix = prefix("/")
+	assert.Error(t, validateSwift(spec))
+	spec.Protocols.Swift.UrlPrefix = prefix("admin")
+	assert.Error(t, validateSwift(spec))
+
+	// auth entry of the default url prefix
+	spec.Protocols.Swift.UrlPrefix = nil
+	assert.Error(t, validateSwift(spec))
+	spec.Protocols.Swift.AuthEntry = nil
+	assert.NoError(t, validateSwift(spec))
+
+	// swift options in the gateway config
+	spec.Gateway.Config = map[string]string{"rgw swift url prefix": "object"}
+	assert.Error(t, validateSwift(spec))
+	spec.Gateway.Config = nil
+
+	// external gateways
+	spec.Gateway.ExternalRgwEndpoints = []EndpointAddress{{IP: "192.168.0.1"}}
+	assert.Error(t, validateSwift(spec))
+}
+
+func TestValidateBucketIndex(t *testing.T) {
+	spec := &ObjectStoreSpec{}
+	assert.NoError(t, validateBucketIndex(spec))
+
+	disabled := false
+	spec.BucketIndex = &BucketIndexSpec{DynamicResharding: &disabled}
+	assert.NoError(t, validateBucketIndex(spec))
+
+	// the options of the settings that are not set can be in the gateway config
+	spec.Gateway.Config = map[string]string{"rgw override bucket index max shards": "11"}
+	assert.NoError(t, validateBucketIndex(spec))
+	spec.Gateway.Config = map[string]string{"rgw-dynamic-resharding": "true"}
+	assert.Error(t, validateBucketIndex(spec))
+	spec.Gateway.Config = nil
+
+	// external gateways
+	spec.Gateway.ExternalRgwEndpoints = []EndpointAddress{{IP: "192.168.0.1"}}
+	assert.Error(t, validateBucketIndex(spec))
+}
+
+func TestValidateGarbageCollection(t *testing.T) {
+	spec := &ObjectStoreSpec{}
+	assert.NoError(t, validateGarbageCollection(spec))
+
+	maxObjects := int32(64)
+	spec.GarbageCollection = &GarbageCollectionSpec{MaxObjects: &maxObjects, ProcessorPeriod: &metav1.Duration{Duration: time.Hour}}
+	assert.NoError(t, validateGarbageCollection(spec))
+
+	// the options of the settings that are not set can be in the gateway config
+	spec.Gateway.Config = map[string]string{"rgw gc max concurrent io": "20"}
+	assert.NoError(t, validateGarbageCollection(spec))
+	spec.Gateway.Config = map[string]string{"rgw-gc-max-objs": "32"}
+	assert.Error(t, validateGarbageCollection(spec))
+	spec.Gateway.Config = nil
+
+	spec.GarbageCollection.ProcessorPeriod.Duration = time.Millisecond
+	assert.Error(t, validateGarbageCollection(spec))
+	spec.GarbageCollection.ProcessorPeriod = nil
+
+	// external gateways
+	spec.Gateway.ExternalRgwEndpoints = []EndpointAddress{{IP: "192.168.0.1"}}
+	assert.Error(t, validateGarbageCollection(spec))
+}
+
+func TestValidateGatewayAllNodes(t *testing.T) {
+	spec := &ObjectStoreSpec{}
+	assert.NoError(t, validateGatewayAllNodes(spec))
+	spec.Gateway.AllNodes = true
+	assert.NoError(t, validateGatewayAllNodes(spec))
+
+	// external gateways
+	spec.Gateway.ExternalRgwEndpoints = []EndpointAddress{{IP: "192.168.0.1"}}
+	assert.Error(t, validateGatewayAllNodes(spec))
+}
+
+func TestValidateGatewayTracing(t *testing.T) {
+	spec := &ObjectStoreSpec{}
+	assert.NoError(t, validateGatewayTracing(spec))
+	spec.Gateway.Tracing = &GatewayTracingSpec{Enabled: true}
+	assert.Error(t, validateGatewayTracing(spec))
+	spec.Gateway.Tracing.CollectorSecretName = "jaeger-collector"
+	assert.NoError(t, validateGatewayTracing(spec))
+
+	// external gateways
+	spec.Gateway.ExternalRgwEndpoints = []EndpointAddress{{IP: "192.168.0.1"}}
+	assert.Error(t, validateGatewayTracing(spec))
+}
+
+func TestValidateGatewayIngress(t *testing.T) {
+	spec := &ObjectStoreSpec{Gateway: GatewaySpec{Port: 80}}
+	assert.NoError(t, validateGatewayIngress(spec))
+	spec.Gateway.Ingress = &RGWIngressSpec{Host: "s3.example.com"}
+	assert.NoError(t, validateGatewayIngress(spec))
+
+	spec.Gateway.Ingress.Host = "s3_example"
+	assert.Error(t, validateGatewayIngress(spec))
+	spec.Gateway.Ingress.Host = "s3.example.com"
+
+	// the ingress routes to a port of the gateways
+	spec.Gateway.Port = 0
+	assert.Error(t, validateGatewayIngress(spec))
+	spec.Gateway.SecurePort = 443
+	assert.NoError(t, validateGatewayIngress(spec))
+
+	// external gateways
+	spec.Gateway.ExternalRgwEndpoints = []EndpointAddress{{IP: "192.168.0.1"}}
+	assert.Error(t, validateGatewayIngress(spec))
+}
+
+func TestValidateGatewayConfig(t *testing.T) {
+	assert.NoError(t, validateGatewayConfig(nil))
+	assert.NoError(t, validateGatewayConfig(map[string]string{"rgw_thread_pool_size": "1024", "rgw max chunk size": "8388608"}))
+	assert.Error(t, validateGatewayConfig(map[string]string{"": "1"}))
+	assert.Error(t, validateGatewayConfig(map[string]string{"rgw_zone": "other"}))
+	assert.Error(t, validateGatewayConfig(map[string]string{"rgw-zonegroup": "other"}))
+}
+
+func TestAllowsUsersInNamespace(t *testing.T) {
+	spec := &ObjectStoreSpec{}
+	assert.False(t, spec.AllowsUsersInNamespace("tenant-a"))
+
+	spec.AllowUsersInNamespaces = []string{"tenant-a"}
+	assert.True(t, spec.AllowsUsersInNamespace("tenant-a"))
+	assert.False(t, spec.AllowsUsersInNamespace("tenant-b"))
+
+	spec.AllowUsersInNamespaces = []string{"*"}
+	assert.True(t, spec.AllowsUsersInNamespace("tenant-b"))
+}
+
+func TestStoreNamespacedName(t *testing.T) {
+	u := &CephObjectStoreUser{ObjectMeta: metav1.ObjectMeta{Name: "my-user", Namespace: "tenant-a"}}
+	u.Spec.Store = "my-store"
+	assert.Equal(t, types.NamespacedName{Namespace: "tenant-a", Name: "my-store"}, u.StoreNamespacedName())
+
+	u.Spec.Store = "rook-ceph/my-store"
+	assert.Equal(t, types.NamespacedName{Namespace: "rook-ceph", Name: "my-store"}, u.StoreNamespacedName())
+}
+
 func TestIsTLSEnabled(t *testing.T) {
 	objStore := &CephObjectStore{
 		ObjectMeta: metav1.ObjectMeta{
@@ -96,3 +493,32 @@ func TestIsTLSEnabled(t *testing.T) {
 	IsTLS = objStore.Spec.IsTLSEnabled()
 	assert.False(t, IsTLS)
 }
+
+func TestOpsLog(t *testing.T) {
+	spec := &ObjectStoreSpec{}
+	assert.False(t, spec.IsOpsLogEnabled())
+
+	spec.Gateway.OpsLog = &GatewayOpsLogSpec{}
+	assert.False(t, spec.IsOpsLogEnabled())
+	assert.Equal(t, OpsLogFormatJSON, spec.Gateway.OpsLog.GetFormat())
+
+	spec.Gateway.OpsLog = &GatewayOpsLogSpec{Enabled: true, Format: OpsLogFormatCombined}
+	assert.True(t, spec.IsOpsLogEnabled())
+	assert.Equal(t, OpsLogFormatCombined, spec.Gateway.OpsLog.GetFormat())
+}
+
+func TestValidateDefaultBucketEncryption(t *testing.T) {
+	spec := &ObjectStoreSpec{}
+	assert.NoError(t, validateDefaultBucketEncryption(spec))
+
+	spec.Security = &ObjectStoreSecuritySpec{DefaultBucketEncryption: true}
+	assert.Error(t, validateDefaultBucketEncryption(spec))
+
+	spec.Security.ServerSideEncryptionS3 = KeyManagementServiceSpec{ConnectionDetails: map[string]string{"KMS_PROVIDER": "vault"}}
+	assert.NoError(t, validateDefaultBucketEncryption(spec))
+
+	// the rgw of an external store is configured out of rook
+	spec.Security.ServerSideEncryptionS3 = KeyManagementServiceSpec{}
+	spec.Gateway.ExternalRgwEndpoints = []EndpointAddress{{IP: "192.168.1.1"}}
+	assert.NoError(t, validateDefaultBucketEncryption(spec))
+}
@@ -145,3 +145,20 @@ func GetArbiterPlacement(p PlacementSpec) Placement {
 func GetOSDPlacement(p PlacementSpec) Placement {
 	return p.All().Merge(p[KeyOSD])
 }
+
+// GetRgwPlacement returns the default placement of the rgw daemons of the object stores, which the placement
+// of an object store overrides
+func GetRgwPlacement(p PlacementSpec) Placement {
+	return p[KeyRgw]
+}
+
+// GetMdsPlacement returns the default placement of the mds daemons of the filesystems, which the placement
+// of a filesystem overrides
+func GetMdsPlacement(p PlacementSpec) Placement {
+	return p[KeyMds]
+}
+
+// GetNFSPlacement returns the default placement of the NFS servers, which the placement of a CephNFS overrides
+func GetNFSPlacement(p PlacementSpec) Placement {
+	return p[KeyNFS]
+}
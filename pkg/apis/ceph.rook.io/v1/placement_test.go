@@ -358,3 +358,22 @@ func TestMergeToleration(t *testing.T) {
 	assert.Equal(t, placementToleration[0].Key, result[0].Key)
 	assert.Equal(t, newToleration[0].Key, result[1].Key)
 }
+
+func TestDaemonDefaultPlacement(t *testing.T) {
+	p := PlacementSpec{
+		KeyAll: Placement{Tolerations: []v1.Toleration{{Key: "all"}}},
+		KeyRgw: Placement{Tolerations: []v1.Toleration{{Key: "rgw"}}},
+		KeyNFS: Placement{Tolerations: []v1.Toleration{{Key: "nfs"}}},
+	}
+
+	// the placement of "all" is not inherited by the daemons of the other CRs
+	assert.Equal(t, "rgw", GetRgwPlacement(p).Tolerations[0].Key)
+	assert.Len(t, GetRgwPlacement(p).Tolerations, 1)
+	assert.Equal(t, "nfs", GetNFSPlacement(p).Tolerations[0].Key)
+	assert.Equal(t, Placement{}, GetMdsPlacement(p))
+
+	// the placement of the CR overrides the default of the cluster
+	crPlacement := Placement{Tolerations: []v1.Toleration{{Key: "store"}}}
+	merged := GetRgwPlacement(p).Merge(crPlacement)
+	assert.Len(t, merged.Tolerations, 2)
+}
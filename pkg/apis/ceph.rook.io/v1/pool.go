@@ -30,6 +30,13 @@ var (
 
 var _ webhook.Validator = &CephBlockPool{}
 
+// ImmutablePoolSettingRemediation is the hint given when a pool setting that Ceph cannot change in
+// place is modified
+const ImmutablePoolSettingRemediation = "to change it, create a new pool with the desired settings and migrate the data to it"
+
+// defaultErasureCodeAlgorithm is the plugin of the default erasure code profile of ceph
+const defaultErasureCodeAlgorithm = "jerasure"
+
 func (p *PoolSpec) IsReplicated() bool {
 	return p.Replicated.Size > 0
 }
@@ -38,6 +45,18 @@ func (p *PoolSpec) IsErasureCoded() bool {
 	return p.ErasureCoded.CodingChunks > 0 || p.ErasureCoded.DataChunks > 0
 }
 
+// IsUnsafe returns true if the pool keeps a single copy of the data, so that the loss of any OSD
+// loses data
+func (p *PoolSpec) IsUnsafe() bool {
+	if p.IsReplicated() {
+		return p.Replicated.Size == 1
+	}
+	if p.IsErasureCoded() {
+		return p.ErasureCoded.CodingChunks == 0
+	}
+	return false
+}
+
 func (p *PoolSpec) IsHybridStoragePool() bool {
 	return p.Replicated.HybridStorage != nil
 }
@@ -95,6 +114,23 @@ func validatePoolSpec(ps NamedPoolSpec) error {
 			return errors.New("invalid pool spec: erasurecoded.codingchunks needs minimum value of 1")
 		}
 	}
+
+	if ps.FailureDomain != "" && ps.FailureDomain == ps.Replicated.SubFailureDomain {
+		return errors.New("failure and subfailure domain cannot be identical")
+	}
+	if ps.IsReplicated() {
+		if ps.Replicated.Size == 1 && ps.Replicated.RequireSafeReplicaSize {
+			return errors.New("invalid pool spec: replicated.requireSafeReplicaSize must be false for a pool of size 1")
+		}
+		if ps.Replicated.ReplicasPerFailureDomain > 0 {
+			if ps.Replicated.Size <= ps.Replicated.ReplicasPerFailureDomain {
+				return errors.Errorf("invalid pool spec: replicated.size %d must be greater than replicated.replicasPerFailureDomain %d", ps.Replicated.Size, ps.Replicated.ReplicasPerFailureDomain)
+			}
+			if ps.Replicated.Size%ps.Replicated.ReplicasPerFailureDomain != 0 {
+				return errors.Errorf("invalid pool spec: replicated.replicasPerFailureDomain %d must be a factor of replicated.size %d", ps.Replicated.ReplicasPerFailureDomain, ps.Replicated.Size)
+			}
+		}
+	}
 	return nil
 }
 
@@ -121,20 +157,43 @@ func (p *CephBlockPool) ValidateUpdate(old runtime.Object) error {
 	if ocbp.Spec.Name != p.Spec.Name {
 		return errors.New("invalid update: pool name cannot be changed")
 	}
-	if p.Spec.ErasureCoded.CodingChunks > 0 || p.Spec.ErasureCoded.DataChunks > 0 || p.Spec.ErasureCoded.Algorithm != "" {
-		if ocbp.Spec.Replicated.Size > 0 || ocbp.Spec.Replicated.TargetSizeRatio > 0 {
-			return errors.New("invalid update: replicated field is set already in previous object. cannot be changed to use erasurecoded")
-		}
+	if err := ValidatePoolSpecUpdate(&ocbp.Spec.PoolSpec, &p.Spec.PoolSpec); err != nil {
+		return errors.Wrapf(err, "invalid update of pool %q", p.Name)
 	}
+	return nil
+}
 
-	if p.Spec.Replicated.Size > 0 || p.Spec.Replicated.TargetSizeRatio > 0 {
-		if ocbp.Spec.ErasureCoded.CodingChunks > 0 || ocbp.Spec.ErasureCoded.DataChunks > 0 || ocbp.Spec.ErasureCoded.Algorithm != "" {
-			return errors.New("invalid update: erasurecoded field is set already in previous object. cannot be changed to use replicated")
+// ValidatePoolSpecUpdate returns an error if the update of a pool spec changes a setting that Ceph
+// cannot change on an existing pool. Such changes were previously accepted but never applied.
+func ValidatePoolSpecUpdate(oldSpec, newSpec *PoolSpec) error {
+	if oldSpec.IsReplicated() && newSpec.IsErasureCoded() {
+		return errors.Errorf("pool type cannot be changed from replicated to erasure coded. %s", ImmutablePoolSettingRemediation)
+	}
+	if oldSpec.IsErasureCoded() && newSpec.IsReplicated() {
+		return errors.Errorf("pool type cannot be changed from erasure coded to replicated. %s", ImmutablePoolSettingRemediation)
+	}
+	if oldSpec.IsErasureCoded() && newSpec.IsErasureCoded() {
+		if oldSpec.ErasureCoded.DataChunks != newSpec.ErasureCoded.DataChunks || oldSpec.ErasureCoded.CodingChunks != newSpec.ErasureCoded.CodingChunks {
+			return errors.Errorf("erasure coded dataChunks and codingChunks cannot be changed from %d+%d to %d+%d. %s",
+				oldSpec.ErasureCoded.DataChunks, oldSpec.ErasureCoded.CodingChunks,
+				newSpec.ErasureCoded.DataChunks, newSpec.ErasureCoded.CodingChunks, ImmutablePoolSettingRemediation)
+		}
+		if erasureCodeAlgorithm(oldSpec.ErasureCoded) != erasureCodeAlgorithm(newSpec.ErasureCoded) {
+			return errors.Errorf("erasure coded algorithm cannot be changed from %q to %q. %s",
+				oldSpec.ErasureCoded.Algorithm, newSpec.ErasureCoded.Algorithm, ImmutablePoolSettingRemediation)
 		}
 	}
 	return nil
 }
 
+// erasureCodeAlgorithm returns the algorithm of the erasure coded pool, where an empty algorithm is the default
+func erasureCodeAlgorithm(spec ErasureCodedSpec) string {
+	if spec.Algorithm == "" {
+		return defaultErasureCodeAlgorithm
+	}
+	return spec.Algorithm
+}
+
 func (p *CephBlockPool) ValidateDelete() error {
 	return nil
 }
@@ -43,6 +43,36 @@ func TestValidatePoolSpec(t *testing.T) {
 	p.Spec.ErasureCoded.DataChunks = 1
 	err = validatePoolSpec(p.ToNamedPoolSpec())
 	assert.Error(t, err)
+
+	t.Run("replicated", func(t *testing.T) {
+		r := &CephBlockPool{
+			ObjectMeta: metav1.ObjectMeta{Name: "replicapool"},
+			Spec: NamedBlockPoolSpec{
+				PoolSpec: PoolSpec{
+					FailureDomain: "host",
+					Replicated:    ReplicatedSpec{Size: 4, ReplicasPerFailureDomain: 2, SubFailureDomain: "rack"},
+				},
+			},
+		}
+		assert.NoError(t, validatePoolSpec(r.ToNamedPoolSpec()))
+
+		r.Spec.Replicated.SubFailureDomain = "host"
+		assert.Error(t, validatePoolSpec(r.ToNamedPoolSpec()))
+		r.Spec.Replicated.SubFailureDomain = "rack"
+
+		r.Spec.Replicated.Size = 3
+		assert.Error(t, validatePoolSpec(r.ToNamedPoolSpec()))
+
+		r.Spec.Replicated.Size = 2
+		assert.Error(t, validatePoolSpec(r.ToNamedPoolSpec()))
+
+		r.Spec.Replicated.Size = 1
+		r.Spec.Replicated.ReplicasPerFailureDomain = 0
+		r.Spec.Replicated.RequireSafeReplicaSize = true
+		assert.Error(t, validatePoolSpec(r.ToNamedPoolSpec()))
+		r.Spec.Replicated.RequireSafeReplicaSize = false
+		assert.NoError(t, validatePoolSpec(r.ToNamedPoolSpec()))
+	})
 }
 
 func TestCephBlockPoolValidateUpdate(t *testing.T) {
@@ -69,6 +99,58 @@ func TestCephBlockPoolValidateUpdate(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestValidatePoolSpecUpdate(t *testing.T) {
+	replicated := PoolSpec{Replicated: ReplicatedSpec{Size: 3}}
+	ec := PoolSpec{ErasureCoded: ErasureCodedSpec{DataChunks: 2, CodingChunks: 1}}
+
+	t.Run("mutable settings can change", func(t *testing.T) {
+		updated := replicated
+		updated.Replicated.Size = 2
+		updated.FailureDomain = "rack"
+		assert.NoError(t, ValidatePoolSpecUpdate(&replicated, &updated))
+
+		updated = ec
+		updated.CompressionMode = "aggressive"
+		assert.NoError(t, ValidatePoolSpecUpdate(&ec, &updated))
+	})
+
+	t.Run("pool type cannot change", func(t *testing.T) {
+		err := ValidatePoolSpecUpdate(&replicated, &ec)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), ImmutablePoolSettingRemediation)
+
+		err = ValidatePoolSpecUpdate(&ec, &replicated)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), ImmutablePoolSettingRemediation)
+	})
+
+	t.Run("erasure coded chunks cannot change", func(t *testing.T) {
+		updated := ec
+		updated.ErasureCoded.CodingChunks = 2
+		err := ValidatePoolSpecUpdate(&ec, &updated)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "from 2+1 to 2+2")
+	})
+
+	t.Run("erasure coded algorithm cannot change", func(t *testing.T) {
+		updated := ec
+		updated.ErasureCoded.Algorithm = "isa"
+		assert.Error(t, ValidatePoolSpecUpdate(&ec, &updated))
+	})
+
+	t.Run("the default erasure coded algorithm is jerasure", func(t *testing.T) {
+		defaulted := ec
+		defaulted.ErasureCoded.Algorithm = ""
+		explicit := ec
+		explicit.ErasureCoded.Algorithm = "jerasure"
+		assert.NoError(t, ValidatePoolSpecUpdate(&defaulted, &explicit))
+		assert.NoError(t, ValidatePoolSpecUpdate(&explicit, &defaulted))
+
+		explicit.ErasureCoded.Algorithm = "isa"
+		assert.Error(t, ValidatePoolSpecUpdate(&defaulted, &explicit))
+	})
+}
+
 func TestMirroringSpec_SnapshotSchedulesEnabled(t *testing.T) {
 	type fields struct {
 		Enabled           bool
@@ -40,3 +40,7 @@ func (c *CephBlockPoolRadosNamespace) ValidateUpdate(old runtime.Object) error {
 func (c *CephBlockPoolRadosNamespace) ValidateDelete() error {
 	return nil
 }
+
+func (c *CephBlockPoolRadosNamespace) GetStatusConditions() *[]Condition {
+	return &c.Status.Conditions
+}
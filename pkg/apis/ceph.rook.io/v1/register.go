@@ -87,6 +87,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&CephFilesystemSubVolumeGroupList{},
 		&CephBlockPoolRadosNamespace{},
 		&CephBlockPoolRadosNamespaceList{},
+		&CephCOSIDriver{},
+		&CephCOSIDriverList{},
+		&CephOperation{},
+		&CephOperationList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	scheme.AddKnownTypes(bktv1alpha1.SchemeGroupVersion,
@@ -45,6 +45,10 @@ const (
 	ResourcesKeyCleanup = "cleanup"
 	// ResourcesKeyCleanup represents the name of resource in the CR for ceph-exporter
 	ResourcesKeyCephExporter = "exporter"
+	// ResourcesKeyRgw represents the name of resource in the CR for the rgw daemons of the object stores
+	ResourcesKeyRgw = "rgw"
+	// ResourcesKeyNFS represents the name of resource in the CR for the NFS servers
+	ResourcesKeyNFS = "nfs"
 )
 
 // GetMgrResources returns the placement for the MGR service
@@ -104,3 +108,27 @@ func GetCleanupResources(p ResourceSpec) v1.ResourceRequirements {
 func GetCephExporterResources(p ResourceSpec) v1.ResourceRequirements {
 	return p[ResourcesKeyCephExporter]
 }
+
+// GetRgwResources returns the default resources of the rgw daemons of the object stores
+func GetRgwResources(p ResourceSpec) v1.ResourceRequirements {
+	return p[ResourcesKeyRgw]
+}
+
+// GetMdsResources returns the default resources of the mds daemons of the filesystems
+func GetMdsResources(p ResourceSpec) v1.ResourceRequirements {
+	return p[ResourcesKeyMDS]
+}
+
+// GetNFSResources returns the default resources of the NFS servers
+func GetNFSResources(p ResourceSpec) v1.ResourceRequirements {
+	return p[ResourcesKeyNFS]
+}
+
+// InheritResources returns the resources of a daemon, or the default resources from the cluster if the daemon
+// does not set any
+func InheritResources(resources, defaults v1.ResourceRequirements) v1.ResourceRequirements {
+	if len(resources.Limits) > 0 || len(resources.Requests) > 0 {
+		return resources
+	}
+	return defaults
+}
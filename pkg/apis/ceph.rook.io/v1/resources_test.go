@@ -0,0 +1,44 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestInheritResources(t *testing.T) {
+	defaults := v1.ResourceRequirements{
+		Limits: v1.ResourceList{v1.ResourceMemory: resource.MustParse("2Gi")},
+	}
+	spec := ResourceSpec{ResourcesKeyRgw: defaults}
+
+	// the daemon does not set any resources
+	assert.Equal(t, defaults, InheritResources(v1.ResourceRequirements{}, GetRgwResources(spec)))
+
+	// the resources of the daemon are not merged with the defaults
+	own := v1.ResourceRequirements{
+		Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+	}
+	assert.Equal(t, own, InheritResources(own, GetRgwResources(spec)))
+
+	// no defaults in the cluster
+	assert.Equal(t, v1.ResourceRequirements{}, InheritResources(v1.ResourceRequirements{}, GetNFSResources(spec)))
+}
@@ -76,3 +76,8 @@ func getParam(kmsConfig map[string]string, param string) string {
 	}
 	return ""
 }
+
+// IsEnabled return whether the cluster keys are encrypted with the KMS
+func (k *KeyringEncryptionSpec) IsEnabled() bool {
+	return k.Enabled
+}
@@ -17,8 +17,10 @@ limitations under the License.
 package v1
 
 import (
+	"fmt"
 	"time"
 
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -84,3 +86,37 @@ func FindStatusCondition(conditions []Condition, conditionType ConditionType) *C
 
 	return nil
 }
+
+// SetPhaseConditions sets the Ready, Progressing and Failure conditions to match the phase of a
+// resource, so that tools that only read the conditions can tell when the resource is ready. The
+// phase is left unchanged for compatibility. Unknown phases do not change the conditions.
+func SetPhaseConditions(conditions *[]Condition, phase string) {
+	var current ConditionType
+	var reason ConditionReason
+	switch phase {
+	case string(ConditionReady), string(ConditionConnected):
+		current, reason = ConditionReady, ReconcileSucceeded
+	case string(ConditionProgressing), string(ConditionConnecting), "Processing", "Reconciling":
+		current, reason = ConditionProgressing, ReconcileStarted
+	case string(ConditionFailure), "Failed", "ReconcileFailed":
+		current, reason = ConditionFailure, ReconcileFailed
+	case string(ConditionDeleting):
+		reason = DeletingReason
+	default:
+		return
+	}
+
+	message := fmt.Sprintf("phase is %q", phase)
+	for _, conditionType := range []ConditionType{ConditionReady, ConditionProgressing, ConditionFailure} {
+		status := v1.ConditionFalse
+		if conditionType == current {
+			status = v1.ConditionTrue
+		}
+		SetStatusCondition(conditions, Condition{
+			Type:    conditionType,
+			Status:  status,
+			Reason:  reason,
+			Message: message,
+		})
+	}
+}
@@ -133,3 +133,48 @@ func TestFindStatusCondition(t *testing.T) {
 		})
 	}
 }
+
+func TestSetPhaseConditions(t *testing.T) {
+	statuses := func(conditions []Condition) map[ConditionType]v1.ConditionStatus {
+		result := map[ConditionType]v1.ConditionStatus{}
+		for _, c := range conditions {
+			result[c.Type] = c.Status
+		}
+		return result
+	}
+
+	conditions := []Condition{{Type: ConditionDeletionIsBlocked, Status: v1.ConditionFalse}}
+	SetPhaseConditions(&conditions, string(ConditionProgressing))
+	expected := map[ConditionType]v1.ConditionStatus{
+		ConditionDeletionIsBlocked: v1.ConditionFalse,
+		ConditionReady:             v1.ConditionFalse,
+		ConditionProgressing:       v1.ConditionTrue,
+		ConditionFailure:           v1.ConditionFalse,
+	}
+	if !reflect.DeepEqual(statuses(conditions), expected) {
+		t.Error(conditions)
+	}
+
+	SetPhaseConditions(&conditions, "ReconcileFailed")
+	expected[ConditionProgressing] = v1.ConditionFalse
+	expected[ConditionFailure] = v1.ConditionTrue
+	if !reflect.DeepEqual(statuses(conditions), expected) {
+		t.Error(conditions)
+	}
+	if c := FindStatusCondition(conditions, ConditionFailure); c.Reason != ReconcileFailed {
+		t.Error(c)
+	}
+
+	SetPhaseConditions(&conditions, string(ConditionReady))
+	expected[ConditionFailure] = v1.ConditionFalse
+	expected[ConditionReady] = v1.ConditionTrue
+	if !reflect.DeepEqual(statuses(conditions), expected) {
+		t.Error(conditions)
+	}
+
+	// an unknown phase leaves the conditions unchanged
+	SetPhaseConditions(&conditions, "Unknown")
+	if !reflect.DeepEqual(statuses(conditions), expected) {
+		t.Error(conditions)
+	}
+}
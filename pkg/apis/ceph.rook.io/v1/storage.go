@@ -15,6 +15,13 @@ limitations under the License.
 */
 package v1
 
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
 // AnyUseAllDevices gets whether to use all devices
 func (s *StorageScopeSpec) AnyUseAllDevices() bool {
 	if s.Selection.GetUseAllDevices() {
@@ -98,6 +105,10 @@ func (s *StorageScopeSpec) resolveNodeSelection(node *Node) {
 		node.Selection.Devices = s.Devices
 	}
 
+	if len(node.Selection.DeviceFilters) == 0 {
+		node.Selection.DeviceFilters = s.DeviceFilters
+	}
+
 	if len(node.Selection.VolumeClaimTemplates) == 0 {
 		node.Selection.VolumeClaimTemplates = s.VolumeClaimTemplates
 	}
@@ -168,3 +179,70 @@ func (s *StorageScopeSpec) IsOnPVCEncrypted() bool {
 
 	return false
 }
+
+// IsOnHostEncrypted returns whether any host-based OSD is configured with "encryptedDevice". The keys of those OSDs
+// are stored by ceph-volume in the monitors, never in the key management service.
+func (s *StorageScopeSpec) IsOnHostEncrypted() bool {
+	isEncrypted := func(config map[string]string) bool {
+		return config["encryptedDevice"] == "true"
+	}
+	selectionEncrypted := func(selection Selection) bool {
+		for _, device := range selection.Devices {
+			if isEncrypted(device.Config) {
+				return true
+			}
+		}
+		for _, filter := range selection.DeviceFilters {
+			if isEncrypted(filter.Config) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if isEncrypted(s.Config) || selectionEncrypted(s.Selection) {
+		return true
+	}
+	for _, node := range s.Nodes {
+		if isEncrypted(node.Config) || selectionEncrypted(node.Selection) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ValidateDriveGroups returns an error if a drive group has no name, a duplicate name, or a spec that does not select
+// data devices
+func (s *StorageScopeSpec) ValidateDriveGroups() error {
+	names := map[string]bool{}
+	for _, driveGroup := range s.DriveGroups {
+		if driveGroup.Name == "" {
+			return errors.New("drive group name must be set")
+		}
+		if names[driveGroup.Name] {
+			return errors.Errorf("drive group name %q is not unique", driveGroup.Name)
+		}
+		names[driveGroup.Name] = true
+
+		spec := map[string]interface{}{}
+		if err := json.Unmarshal(driveGroup.Spec.Raw, &spec); err != nil {
+			return errors.Wrapf(err, "failed to parse the spec of drive group %q", driveGroup.Name)
+		}
+		if _, ok := spec["data_devices"]; !ok {
+			return errors.Errorf("spec of drive group %q must set data_devices", driveGroup.Name)
+		}
+	}
+	return nil
+}
+
+// DriveGroupsForNode returns the drive groups whose node selector matches the labels of a node
+func (s *StorageScopeSpec) DriveGroupsForNode(nodeLabels map[string]string) []DriveGroup {
+	driveGroups := []DriveGroup{}
+	for _, driveGroup := range s.DriveGroups {
+		if labels.SelectorFromSet(driveGroup.NodeSelector).Matches(labels.Set(nodeLabels)) {
+			driveGroups = append(driveGroups, driveGroup)
+		}
+	}
+	return driveGroups
+}
@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 func TestNodeExists(t *testing.T) {
@@ -83,6 +84,7 @@ func TestResolveNodeInherentFromCluster(t *testing.T) {
 			DeviceFilter:     "^sd.",
 			DevicePathFilter: "^/dev/disk/by-path/pci-.*",
 			Devices:          []Device{{Name: "sda"}},
+			DeviceFilters:    []DeviceFilter{{Filter: "^nvme", Config: map[string]string{"deviceClass": "nvme"}}},
 		},
 		Config: map[string]string{
 			"foo": "bar",
@@ -99,6 +101,7 @@ func TestResolveNodeInherentFromCluster(t *testing.T) {
 	assert.False(t, node.Selection.GetUseAllDevices())
 	assert.Equal(t, "bar", node.Config["foo"])
 	assert.Equal(t, []Device{{Name: "sda"}}, node.Devices)
+	assert.Equal(t, []DeviceFilter{{Filter: "^nvme", Config: map[string]string{"deviceClass": "nvme"}}}, node.DeviceFilters)
 }
 
 func TestResolveNodeSpecificProperties(t *testing.T) {
@@ -287,3 +290,77 @@ func TestIsOnPVCEncrypted(t *testing.T) {
 	}
 	assert.True(t, s.IsOnPVCEncrypted())
 }
+
+func TestIsOnHostEncrypted(t *testing.T) {
+	s := &StorageScopeSpec{}
+	assert.False(t, s.IsOnHostEncrypted())
+
+	s.Config = map[string]string{"encryptedDevice": "false"}
+	assert.False(t, s.IsOnHostEncrypted())
+
+	s.Config["encryptedDevice"] = "true"
+	assert.True(t, s.IsOnHostEncrypted())
+
+	s.Config = nil
+	s.Nodes = []Node{{Name: "a", Selection: Selection{Devices: []Device{{Name: "sdb", Config: map[string]string{"encryptedDevice": "true"}}}}}}
+	assert.True(t, s.IsOnHostEncrypted())
+
+	s.Nodes = []Node{{Name: "a", Config: map[string]string{"encryptedDevice": "true"}}}
+	assert.True(t, s.IsOnHostEncrypted())
+
+	s.Nodes = nil
+	s.DeviceFilters = []DeviceFilter{{Filter: "^sd.", Config: map[string]string{"encryptedDevice": "true"}}}
+	assert.True(t, s.IsOnHostEncrypted())
+}
+
+func TestValidateDriveGroups(t *testing.T) {
+	spec := func(raw string) runtime.RawExtension {
+		return runtime.RawExtension{Raw: []byte(raw)}
+	}
+	s := StorageScopeSpec{}
+	assert.NoError(t, s.ValidateDriveGroups())
+
+	s.DriveGroups = []DriveGroup{
+		{Name: "hdd", Spec: spec(`{"data_devices":{"rotational":1},"db_devices":{"rotational":0}}`)},
+		{Name: "ssd", Spec: spec(`{"data_devices":{"rotational":0}}`)},
+	}
+	assert.NoError(t, s.ValidateDriveGroups())
+
+	// duplicate name
+	s.DriveGroups[1].Name = "hdd"
+	assert.Error(t, s.ValidateDriveGroups())
+
+	// no name
+	s.DriveGroups[1].Name = ""
+	assert.Error(t, s.ValidateDriveGroups())
+
+	// no data devices
+	s.DriveGroups[1] = DriveGroup{Name: "ssd", Spec: spec(`{"db_devices":{"rotational":0}}`)}
+	assert.Error(t, s.ValidateDriveGroups())
+
+	// not an object
+	s.DriveGroups[1] = DriveGroup{Name: "ssd", Spec: spec(`["sdb"]`)}
+	assert.Error(t, s.ValidateDriveGroups())
+}
+
+func TestDriveGroupsForNode(t *testing.T) {
+	s := StorageScopeSpec{
+		DriveGroups: []DriveGroup{
+			{Name: "all"},
+			{Name: "nvme", NodeSelector: map[string]string{"disk": "nvme"}},
+			{Name: "node1", NodeSelector: map[string]string{"kubernetes.io/hostname": "node1"}},
+		},
+	}
+
+	names := func(driveGroups []DriveGroup) []string {
+		result := []string{}
+		for _, driveGroup := range driveGroups {
+			result = append(result, driveGroup.Name)
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"all"}, names(s.DriveGroupsForNode(map[string]string{"kubernetes.io/hostname": "node2"})))
+	assert.Equal(t, []string{"all", "nvme", "node1"}, names(s.DriveGroupsForNode(map[string]string{"kubernetes.io/hostname": "node1", "disk": "nvme"})))
+	assert.Equal(t, []string{}, names((&StorageScopeSpec{}).DriveGroupsForNode(nil)))
+}
@@ -40,3 +40,7 @@ func (c *CephFilesystemSubVolumeGroup) ValidateUpdate(old runtime.Object) error
 func (c *CephFilesystemSubVolumeGroup) ValidateDelete() error {
 	return nil
 }
+
+func (c *CephFilesystemSubVolumeGroup) GetStatusConditions() *[]Condition {
+	return &c.Status.Conditions
+}
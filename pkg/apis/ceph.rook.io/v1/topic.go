@@ -109,3 +109,7 @@ func (t *CephBucketTopic) ValidateDelete() error {
 	logger.Info("validate delete cephbuckettopic")
 	return nil
 }
+
+func (t *CephBucketTopic) GetStatusConditions() *[]Condition {
+	return &t.Status.Conditions
+}
@@ -22,6 +22,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // ***************************************************************************
@@ -66,6 +67,89 @@ type CephClusterHealthCheckSpec struct {
 	// StartupProbe allows changing the startupProbe configuration for a given daemon
 	// +optional
 	StartupProbe map[KeyType]*ProbeSpec `json:"startupProbe,omitempty"`
+	// ClockSkew is the monitoring of the clock skew between the mons and of the clocks of their nodes
+	// +optional
+	ClockSkew ClockSkewHealthSpec `json:"clockSkew,omitempty"`
+	// ScrubReport is the periodic report of the scrubs of the placement groups in the status
+	// +optional
+	ScrubReport ScrubReportSpec `json:"scrubReport,omitempty"`
+	// MonFailover is the failover of the mons out of quorum for longer than the timeout of the mon health check
+	// +optional
+	MonFailover MonFailoverSpec `json:"monFailover,omitempty"`
+}
+
+// ScrubReportSpec represents the periodic report of the scrubs of the placement groups of the cluster in its status,
+// to prove that the integrity of the data is verified without parsing the logs of ceph
+type ScrubReportSpec struct {
+	// Enabled reports the last scrubs and the inconsistencies found by the scrubs of each pool in the status
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// Interval is the interval at which the report is refreshed, e.g. 30m. Defaults to 1h.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// ScrubSpec represents the scheduling of the scrubs of the placement groups, to confine the scrub IO to the off-peak
+// hours. The hours and week days are in the local time of the OSDs.
+type ScrubSpec struct {
+	// BeginHour is the hour of the day from which the scrubs are scheduled (osd_scrub_begin_hour)
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=23
+	// +optional
+	BeginHour *int `json:"beginHour,omitempty"`
+	// EndHour is the hour of the day at which the scrubs stop being scheduled (osd_scrub_end_hour). The window wraps
+	// around midnight when it is lower than the begin hour.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=23
+	// +optional
+	EndHour *int `json:"endHour,omitempty"`
+	// BeginWeekDay is the day of the week, from 0 for Sunday, from which the scrubs are scheduled
+	// (osd_scrub_begin_week_day)
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=6
+	// +optional
+	BeginWeekDay *int `json:"beginWeekDay,omitempty"`
+	// EndWeekDay is the day of the week, from 0 for Sunday, at which the scrubs stop being scheduled
+	// (osd_scrub_end_week_day)
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=6
+	// +optional
+	EndWeekDay *int `json:"endWeekDay,omitempty"`
+	// MaxScrubs is the maximum number of simultaneous scrubs of an OSD (osd_max_scrubs)
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxScrubs *int `json:"maxScrubs,omitempty"`
+	// DeepScrubInterval is the interval at which each placement group is deep scrubbed, e.g. 168h
+	// (osd_deep_scrub_interval)
+	// +optional
+	DeepScrubInterval *metav1.Duration `json:"deepScrubInterval,omitempty"`
+}
+
+// ClockSkewHealthSpec represents the monitoring of the clock skew between the mons. The skew of each mon from the
+// leader mon is checked with the ceph status, and the error of the clock of each node of the mons from its NTP
+// sources is checked periodically. The cluster is degraded when they exceed the threshold, before ceph raises
+// MON_CLOCK_SKEW and the elections start failing.
+type ClockSkewHealthSpec struct {
+	// Disabled disables the monitoring of the clock skew
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+	// Threshold is the skew of a mon, or the error of the clock of a node from its NTP sources, above which the
+	// cluster is degraded. Defaults to 25ms, half of the default mon_clock_drift_allowed of ceph.
+	// +optional
+	Threshold *metav1.Duration `json:"threshold,omitempty"`
+}
+
+// MonFailoverSpec represents the failover of the mons out of quorum. A mon is failed over when it is out of quorum for
+// longer than the timeout of the mon health check, i.e. a new mon is started and the unhealthy mon is removed.
+type MonFailoverSpec struct {
+	// Disabled disables the failover of the mons, the mons out of quorum are only reported
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+	// ExcludedNodes are the nodes on which the new mons are not scheduled when the mons out of quorum are failed over,
+	// e.g. the nodes under a planned maintenance.
+	// +optional
+	// +nullable
+	ExcludedNodes []string `json:"excludedNodes,omitempty"`
 }
 
 // DaemonHealthSpec is a daemon health check
@@ -100,6 +184,18 @@ type ClusterSpec struct {
 	// +nullable
 	CephVersion CephVersionSpec `json:"cephVersion,omitempty"`
 
+	// CephConfig are the Ceph config options set in the centralized mon configuration database, by section (e.g.
+	// "global", "osd" or "client.rgw.my-store") and option. Options removed from the spec are removed from the
+	// database.
+	// +optional
+	// +nullable
+	CephConfig map[string]map[string]string `json:"cephConfig,omitempty"`
+
+	// Scrub is the scheduling of the scrubs of the placement groups, set as options of the "osd" section in the mon
+	// configuration database. The options of the "osd" section of the CephConfig take precedence.
+	// +optional
+	Scrub ScrubSpec `json:"scrub,omitempty"`
+
 	// A spec for available storage in the cluster and how it should be used
 	// +optional
 	// +nullable
@@ -201,6 +297,23 @@ type ClusterSpec struct {
 	// +optional
 	RemoveOSDsIfOutAndSafeToRemove bool `json:"removeOSDsIfOutAndSafeToRemove,omitempty"`
 
+	// AllowUnsafePools allows pools that keep a single copy of the data, i.e. replicated pools of size 1
+	// and erasure coded pools without coding chunks. The data of such pools is lost with any single OSD.
+	// +optional
+	AllowUnsafePools bool `json:"allowUnsafePools,omitempty"`
+
+	// The CRUSH settings of the cluster
+	// +optional
+	Crush CrushSpec `json:"crush,omitempty"`
+
+	// Preflight validates the nodes before the cluster is created
+	// +optional
+	Preflight PreflightSpec `json:"preflight,omitempty"`
+
+	// DataDirHostPathPreparation prepares the dataDirHostPath on the nodes before the daemons start
+	// +optional
+	DataDirHostPathPreparation DataDirHostPathPreparationSpec `json:"dataDirHostPathPreparation,omitempty"`
+
 	// Indicates user intent when deleting a cluster; blocks orchestration and should not be set if cluster
 	// deletion is not imminent.
 	// +optional
@@ -247,6 +360,40 @@ type SecuritySpec struct {
 	// +optional
 	// +nullable
 	KeyRotation KeyRotationSpec `json:"keyRotation,omitempty"`
+	// DaemonHardening defines the security context of the stateless daemons (rgw, mgr and mds).
+	// +optional
+	// +nullable
+	DaemonHardening DaemonHardeningSpec `json:"daemonHardening,omitempty"`
+	// KeyringEncryption defines the encryption of the cluster keys at rest with the KMS.
+	// +optional
+	// +nullable
+	KeyringEncryption KeyringEncryptionSpec `json:"keyringEncryption,omitempty"`
+}
+
+// KeyringEncryptionSpec represents the encryption of the mon and admin keys stored in the Kubernetes secrets with a key
+// kept in the KMS. The keys are only decrypted in memory by the operator and the Rook containers reading them.
+type KeyringEncryptionSpec struct {
+	// Enabled encrypts the keys, the KMS must be configured in the security settings.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+}
+
+// DaemonHardeningSpec represents the hardening of the security context of the stateless daemons. By default, the
+// daemons run as the ceph user with a read-only root filesystem and without any capabilities.
+type DaemonHardeningSpec struct {
+	// Disabled runs the daemons with the security context of the previous releases.
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+	// AllowWritableRootFilesystem allows the daemons to write to their root filesystem.
+	// +optional
+	AllowWritableRootFilesystem bool `json:"allowWritableRootFilesystem,omitempty"`
+	// RunAsRoot starts the daemons as root, they still switch to the ceph user once started.
+	// +optional
+	RunAsRoot bool `json:"runAsRoot,omitempty"`
+	// AddCapabilities are the capabilities added back to the daemons after dropping all the others.
+	// +optional
+	// +nullable
+	AddCapabilities []v1.Capability `json:"addCapabilities,omitempty"`
 }
 
 // ObjectStoreSecuritySpec is spec to define security features like encryption
@@ -259,6 +406,12 @@ type ObjectStoreSecuritySpec struct {
 	// +optional
 	// +nullable
 	ServerSideEncryptionS3 KeyManagementServiceSpec `json:"s3,omitempty"`
+
+	// DefaultBucketEncryption enables the default encryption with AWS-SSE:S3 of the buckets of the object bucket
+	// claims, so the objects are encrypted without the encryption headers in the requests. The claims can override
+	// it with their bucketEncryption setting. It requires the s3 settings unless the object store is external.
+	// +optional
+	DefaultBucketEncryption bool `json:"defaultBucketEncryption,omitempty"`
 }
 
 // KeyManagementServiceSpec represent various details of the KMS server
@@ -348,11 +501,214 @@ type ClusterStatus struct {
 	CephStatus  *CephStatus     `json:"ceph,omitempty"`
 	CephStorage *CephStorage    `json:"storage,omitempty"`
 	CephVersion *ClusterVersion `json:"version,omitempty"`
+	// CrushTunables is the status of the last change of the CRUSH tunables
+	// +optional
+	CrushTunables *CrushTunablesStatus `json:"crushTunables,omitempty"`
+	// Preflight is the result of the last preflight checks of the nodes
+	// +optional
+	Preflight *PreflightStatus `json:"preflight,omitempty"`
+	// ScrubReport is the last report of the scrubs of the placement groups
+	// +optional
+	// +nullable
+	ScrubReport *ScrubReport `json:"scrubReport,omitempty"`
 	// ObservedGeneration is the latest generation observed by the controller.
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
+// CrushSpec represents the CRUSH settings of the cluster
+type CrushSpec struct {
+	// TunablesProfile is the profile of the CRUSH tunables. A change of the profile remaps placement groups to
+	// other OSDs. The remapped PGs are pinned to their current OSDs, and the balancer moves them gradually.
+	// +kubebuilder:validation:Enum=legacy;argonaut;bobtail;firefly;hammer;jewel;optimal;default
+	// +optional
+	TunablesProfile string `json:"tunablesProfile,omitempty"`
+
+	// MigrateToStraw2 converts the buckets of the CRUSH map that use the legacy straw algorithm to straw2, with
+	// the same throttled data movement as a change of the tunables profile
+	// +optional
+	MigrateToStraw2 bool `json:"migrateToStraw2,omitempty"`
+
+	// PreviewOnly only computes the number of placement groups that a change of the tunables profile or the
+	// straw2 migration would remap, on a copy of the OSD map without applying the change
+	// +optional
+	PreviewOnly bool `json:"previewOnly,omitempty"`
+}
+
+// CrushTunablesStatus represents the status of the last change of the CRUSH tunables
+type CrushTunablesStatus struct {
+	// Profile is the CRUSH tunables profile of the cluster
+	// +optional
+	Profile string `json:"profile,omitempty"`
+	// PreviewedProfile is the profile of the last preview
+	// +optional
+	PreviewedProfile string `json:"previewedProfile,omitempty"`
+	// RemappedPGs is the number of placement groups remapped by the last change or preview
+	// +optional
+	RemappedPGs int `json:"remappedPGs,omitempty"`
+	// PinnedPGs is the number of remapped placement groups pinned to their current OSDs by the last change,
+	// which the balancer moves gradually
+	// +optional
+	PinnedPGs int `json:"pinnedPGs,omitempty"`
+	// LastChanged is the time of the last change or preview
+	// +optional
+	LastChanged string `json:"lastChanged,omitempty"`
+	// RebalancePaused is true while the rebalance is paused until the PGs remapped by the last change are pinned
+	// +optional
+	RebalancePaused bool `json:"rebalancePaused,omitempty"`
+}
+
+// PreflightCheck is a check of the nodes run by the preflight validation
+// +kubebuilder:validation:Enum=kernel-modules;time-sync;ports;sysctls;disk-write-cache
+type PreflightCheck string
+
+const (
+	// PreflightCheckKernelModules checks that the kernel modules are loaded or can be loaded
+	PreflightCheckKernelModules PreflightCheck = "kernel-modules"
+	// PreflightCheckTimeSync checks that the clock is synchronized by NTP, e.g. by chrony
+	PreflightCheckTimeSync PreflightCheck = "time-sync"
+	// PreflightCheckPorts checks that the ports of the mons are free on the host network
+	PreflightCheckPorts PreflightCheck = "ports"
+	// PreflightCheckSysctls checks the values of the kernel parameters
+	PreflightCheckSysctls PreflightCheck = "sysctls"
+	// PreflightCheckDiskWriteCache warns about the OSD devices with a volatile write cache
+	PreflightCheckDiskWriteCache PreflightCheck = "disk-write-cache"
+)
+
+// PreflightSpec represents the preflight validation of the nodes. A job runs the checks on each storage
+// node before the mons of a new cluster are created, and the orchestration is blocked until all the checks
+// pass on all the nodes.
+type PreflightSpec struct {
+	// Enabled runs the preflight checks before the cluster is created
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// SkipChecks are the checks that are not run
+	// +optional
+	SkipChecks []PreflightCheck `json:"skipChecks,omitempty"`
+
+	// KernelModules are the kernel modules that must be loaded or available on the nodes.
+	// Defaults to rbd and nbd.
+	// +optional
+	KernelModules []string `json:"kernelModules,omitempty"`
+
+	// Sysctls are the values of the kernel parameters required on the nodes, such as vm.nr_hugepages
+	// +optional
+	Sysctls map[string]string `json:"sysctls,omitempty"`
+
+	// Timeout is the time to wait for the checks to complete on each node. Defaults to 5 minutes.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// DataDirHostPathPreparationSpec represents the preparation of the dataDirHostPath on the nodes. A job prepares
+// the dataDirHostPath on each node at each orchestration of the cluster. The failures of some nodes are reported as
+// warnings, the orchestration is only blocked if the preparation failed on all the nodes.
+type DataDirHostPathPreparationSpec struct {
+	// Enabled runs the preparation of the dataDirHostPath on the nodes
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// SELinuxType is the SELinux type the dataDirHostPath is labeled with on the nodes where SELinux is
+	// enabled, so that the containers can write to it, e.g. container_file_t. The dataDirHostPath is not
+	// labeled if empty.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9_]+$`
+	// +optional
+	SELinuxType string `json:"seLinuxType,omitempty"`
+
+	// AllowOverlay allows the dataDirHostPath on an overlay filesystem. By default the preparation fails on
+	// the nodes where the dataDirHostPath is on an overlay filesystem, e.g. of a live OS image, since its
+	// data is lost when the node restarts.
+	// +optional
+	AllowOverlay bool `json:"allowOverlay,omitempty"`
+
+	// Nodes are the nodes where the data is stored in another directory than the dataDirHostPath, e.g. on
+	// the nodes of another OS image. The dataDirHostPath is created on these nodes as a link to the directory.
+	// +optional
+	Nodes []DataDirHostPathNodeSpec `json:"nodes,omitempty"`
+
+	// Timeout is the time to wait for the preparation to complete on each node. Defaults to 5 minutes.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// DataDirHostPathNodeSpec represents the directory of the data of the cluster on a node
+type DataDirHostPathNodeSpec struct {
+	// Name is the hostname of the node
+	Name string `json:"name"`
+	// Path is the absolute path of the directory on the node
+	// +kubebuilder:validation:Pattern=`^/`
+	Path string `json:"path"`
+}
+
+// PreflightStatus represents the result of the preflight checks of the nodes
+type PreflightStatus struct {
+	// Passed is true when all the checks passed on all the nodes
+	// +optional
+	Passed bool `json:"passed,omitempty"`
+	// FailedNodes are the nodes where a check failed
+	// +optional
+	FailedNodes []PreflightNodeStatus `json:"failedNodes,omitempty"`
+	// LastChecked is the time of the last checks
+	// +optional
+	LastChecked string `json:"lastChecked,omitempty"`
+}
+
+// ScrubReport represents the scrubs of the placement groups of the cluster
+type ScrubReport struct {
+	// Pools are the scrub reports of the pools
+	// +optional
+	Pools []PoolScrubReport `json:"pools,omitempty"`
+	// InconsistentPGs is the number of placement groups with inconsistencies that are not repaired
+	// +optional
+	InconsistentPGs int `json:"inconsistentPGs"`
+	// ScrubErrors is the number of errors found by the last scrubs of the placement groups
+	// +optional
+	ScrubErrors int `json:"scrubErrors"`
+	// ObjectsRepaired is the number of objects repaired in the placement groups
+	// +optional
+	ObjectsRepaired int `json:"objectsRepaired"`
+	// LastChecked is the time the report was last refreshed
+	// +optional
+	LastChecked string `json:"lastChecked,omitempty"`
+	// Details is the error of the last refresh, if it failed
+	// +optional
+	Details string `json:"details,omitempty"`
+}
+
+// PoolScrubReport represents the scrubs of the placement groups of a pool
+type PoolScrubReport struct {
+	// Name is the name of the pool
+	Name string `json:"name"`
+	// PGs is the number of placement groups of the pool
+	PGs int `json:"pgs"`
+	// LastScrub is the oldest last scrub of the placement groups, the time since which the whole pool was scrubbed
+	// +optional
+	LastScrub string `json:"lastScrub,omitempty"`
+	// LastDeepScrub is the oldest last deep scrub of the placement groups, the time since which all the data of
+	// the pool was read and verified
+	// +optional
+	LastDeepScrub string `json:"lastDeepScrub,omitempty"`
+	// InconsistentPGs is the number of placement groups of the pool with inconsistencies that are not repaired
+	// +optional
+	InconsistentPGs int `json:"inconsistentPGs"`
+	// ScrubErrors is the number of errors found by the last scrubs of the placement groups of the pool
+	// +optional
+	ScrubErrors int `json:"scrubErrors"`
+	// ObjectsRepaired is the number of objects repaired in the placement groups of the pool
+	// +optional
+	ObjectsRepaired int `json:"objectsRepaired"`
+}
+
+// PreflightNodeStatus represents the failed preflight checks of a node
+type PreflightNodeStatus struct {
+	// Name is the name of the node
+	Name string `json:"name"`
+	// Failures are the reasons of the failed checks
+	// +optional
+	Failures []string `json:"failures,omitempty"`
+}
+
 // CephDaemonsVersions show the current ceph version for different ceph daemons
 type CephDaemonsVersions struct {
 	// Mon shows Mon Ceph version
@@ -392,6 +748,56 @@ type CephStatus struct {
 	// +optional
 	Versions *CephDaemonsVersions `json:"versions,omitempty"`
 	FSID     string               `json:"fsid,omitempty"`
+	// UnsafePools lists the pools that keep a single copy of the data and cannot tolerate the loss of any OSD
+	// +optional
+	UnsafePools []string `json:"unsafePools,omitempty"`
+	// FullEmergency is set while OSDs are full and the full emergency handling is enabled
+	// +optional
+	FullEmergency *FullEmergencyStatus `json:"fullEmergency,omitempty"`
+	// MgrModules is set while mgr modules have failed
+	// +optional
+	MgrModules *MgrModulesStatus `json:"mgrModules,omitempty"`
+}
+
+// MgrModulesStatus represents the mgr modules that have failed and their recovery by the operator
+type MgrModulesStatus struct {
+	// Failed are the mgr modules that have failed
+	// +optional
+	Failed []FailedMgrModule `json:"failed,omitempty"`
+	// Since is the time when the modules were found failed
+	Since string `json:"since,omitempty"`
+	// RecoveryAttempts is the number of failovers of the mgr to recover the modules
+	// +optional
+	RecoveryAttempts int `json:"recoveryAttempts,omitempty"`
+	// LastRecoveryAttempt is the time of the last failover of the mgr to recover the modules
+	// +optional
+	LastRecoveryAttempt string `json:"lastRecoveryAttempt,omitempty"`
+}
+
+// FailedMgrModule represents a mgr module that has failed
+type FailedMgrModule struct {
+	// Name is the name of the module
+	Name string `json:"name"`
+	// Error is the error of the module
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// FullEmergencyStatus represents the handling of full OSDs by the operator
+type FullEmergencyStatus struct {
+	// Since is the time when the OSDs were found full
+	Since string `json:"since,omitempty"`
+	// Message is the health message of the full OSDs
+	Message string `json:"message,omitempty"`
+	// ProtectedPools are the pools of which the PG autoscaler was turned off by the operator
+	// +optional
+	ProtectedPools []string `json:"protectedPools,omitempty"`
+	// FrozenPools are the pools on which the operator set the nopgchange and nosizechange flags
+	// +optional
+	FrozenPools []string `json:"frozenPools,omitempty"`
+	// NoRebalance is whether the operator set the norebalance flag of the OSDs
+	// +optional
+	NoRebalance bool `json:"noRebalance,omitempty"`
 }
 
 // Capacity is the capacity information of a Ceph Cluster
@@ -464,6 +870,51 @@ const (
 	// ObjectHasNoDependentsReason represents when a resource object has no dependents that are
 	// blocking deletion.
 	ObjectHasNoDependentsReason ConditionReason = "ObjectHasNoDependents"
+
+	// ImmutableSettingChangedReason represents when the spec requests a change to a setting that
+	// Ceph cannot change on the existing resource.
+	ImmutableSettingChangedReason ConditionReason = "ImmutableSettingChanged"
+	// ImmutableSettingsUnchangedReason represents when the spec matches the settings that Ceph
+	// cannot change on the existing resource.
+	ImmutableSettingsUnchangedReason ConditionReason = "ImmutableSettingsUnchanged"
+
+	// MonClockSkewReason represents when the clock skew of a mon exceeds the threshold of the health check.
+	MonClockSkewReason ConditionReason = "MonClockSkew"
+	// MonClocksSynchronizedReason represents when the clock skew of all the mons is within the threshold.
+	MonClocksSynchronizedReason ConditionReason = "MonClocksSynchronized"
+	// CephHealthWarningReason represents when the health of ceph is not HEALTH_OK
+	CephHealthWarningReason ConditionReason = "CephHealthWarning"
+	// CephHealthOKReason represents when the health of ceph is back to HEALTH_OK
+	CephHealthOKReason ConditionReason = "CephHealthOK"
+
+	// ReconcilePausedReason represents when the reconcile of the cluster is paused by the "do_not_reconcile" label.
+	ReconcilePausedReason ConditionReason = "ReconcilePaused"
+
+	// ClusterNearFullReason represents when OSDs are nearly full or too full to be backfilled.
+	ClusterNearFullReason ConditionReason = "ClusterNearFull"
+	// ClusterFullReason represents when OSDs are full and the writes to the cluster are blocked.
+	ClusterFullReason ConditionReason = "ClusterFull"
+	// ClusterNoLongerFullReason represents when the OSDs are no longer full after an emergency.
+	ClusterNoLongerFullReason ConditionReason = "ClusterNoLongerFull"
+	// MgrModuleFailedReason represents when mgr modules have failed.
+	MgrModuleFailedReason ConditionReason = "MgrModuleFailed"
+	// MgrModuleRecoveryReason represents when the operator fails over the mgr to recover the failed mgr modules.
+	MgrModuleRecoveryReason ConditionReason = "MgrModuleRecovery"
+	// MgrModulesRecoveredReason represents when the mgr modules no longer fail.
+	MgrModulesRecoveredReason ConditionReason = "MgrModulesRecovered"
+	// OSDVolumeWarningReason represents a warning event of a PVC of an OSD forwarded to the cluster.
+	OSDVolumeWarningReason ConditionReason = "OSDVolumeWarning"
+	// DeploymentUpdatedReason represents when the operator updates the deployment of a daemon.
+	DeploymentUpdatedReason ConditionReason = "DeploymentUpdated"
+	// UpgradeChecksBypassedReason represents when the update of a daemon continues although it is not safe, since
+	// continueUpgradeAfterChecksEvenIfNotHealthy is set.
+	UpgradeChecksBypassedReason ConditionReason = "UpgradeChecksBypassed"
+	// ValidationFailedReason represents when the spec of a resource is rejected by the operator.
+	ValidationFailedReason ConditionReason = "ValidationFailed"
+	// PoolsCreatedReason represents when the operator created the pools of a resource.
+	PoolsCreatedReason ConditionReason = "PoolsCreated"
+	// RealmCreatedReason represents when the operator created the realm, zone group and zone of an object store.
+	RealmCreatedReason ConditionReason = "RealmCreated"
 )
 
 // ConditionType represent a resource's status
@@ -485,6 +936,12 @@ const (
 
 	// ConditionDeletionIsBlocked represents when deletion of the object is blocked.
 	ConditionDeletionIsBlocked ConditionType = "DeletionIsBlocked"
+
+	// ConditionSpecConflict represents when the spec cannot be applied to the existing object.
+	ConditionSpecConflict ConditionType = "SpecConflict"
+
+	// ConditionDegraded represents Degraded state of an object, which keeps working with a reduced margin
+	ConditionDegraded ConditionType = "Degraded"
 )
 
 // ClusterState represents the state of a Ceph Cluster
@@ -522,6 +979,10 @@ type MonSpec struct {
 	// +kubebuilder:pruning:PreserveUnknownFields
 	// +optional
 	VolumeClaimTemplate *v1.PersistentVolumeClaim `json:"volumeClaimTemplate,omitempty"`
+	// DNSDiscovery publishes the mons behind a headless service with DNS SRV records, so that clients can find
+	// the mons by name instead of by IP. The CSI drivers are configured with the name of the service.
+	// +optional
+	DNSDiscovery bool `json:"dnsDiscovery,omitempty"`
 }
 
 // StretchClusterSpec represents the specification of a stretched Ceph Cluster
@@ -566,8 +1027,66 @@ type MgrSpec struct {
 	// +optional
 	// +nullable
 	Modules []Module `json:"modules,omitempty"`
+	// Telemetry is the configuration of the telemetry of the cluster. The telemetry is not managed by the
+	// operator if not set.
+	// +optional
+	// +nullable
+	Telemetry *TelemetrySpec `json:"telemetry,omitempty"`
+	// ModuleRecovery configures the recovery of the mgr modules that have failed
+	// +optional
+	ModuleRecovery MgrModuleRecoverySpec `json:"moduleRecovery,omitempty"`
+}
+
+// MgrModuleRecoverySpec configures the recovery of the mgr modules that have failed, such as a prometheus module
+// that stopped serving the metrics of the cluster. The failed modules are only restarted with the mgr.
+type MgrModuleRecoverySpec struct {
+	// Enabled fails over the active mgr when mgr modules have failed, which restarts the modules
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// Backoff is the time to wait before failing over the mgr again if the modules are still failed, which
+	// doubles after each failover, up to one hour. Defaults to 5 minutes.
+	// +optional
+	Backoff *metav1.Duration `json:"backoff,omitempty"`
+}
+
+// TelemetrySpec represents the telemetry reports of the cluster sent by the telemetry mgr module
+type TelemetrySpec struct {
+	// Enabled sends the telemetry reports of the cluster, which accepts the Community Data License Agreement -
+	// Sharing - Version 1.0 of the reports. The telemetry is turned off if false.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// Channels are the channels of the reports that are sent, the other channels are disabled.
+	// Defaults to the basic, crash and device channels.
+	// +optional
+	Channels []TelemetryChannel `json:"channels,omitempty"`
+	// Contact is the contact of the cluster, only sent with the ident channel
+	// +optional
+	Contact string `json:"contact,omitempty"`
+	// Description is the description of the cluster, only sent with the ident channel
+	// +optional
+	Description string `json:"description,omitempty"`
+	// Organization is the organization of the cluster, only sent with the ident channel
+	// +optional
+	Organization string `json:"organization,omitempty"`
 }
 
+// TelemetryChannel is a channel of the telemetry reports
+// +kubebuilder:validation:Enum=basic;crash;device;ident;perf
+type TelemetryChannel string
+
+const (
+	// TelemetryChannelBasic reports the basic information of the cluster
+	TelemetryChannelBasic TelemetryChannel = "basic"
+	// TelemetryChannelCrash reports the crashes of the daemons
+	TelemetryChannelCrash TelemetryChannel = "crash"
+	// TelemetryChannelDevice reports the health metrics of the devices
+	TelemetryChannelDevice TelemetryChannel = "device"
+	// TelemetryChannelIdent reports the contact, description and organization of the cluster
+	TelemetryChannelIdent TelemetryChannel = "ident"
+	// TelemetryChannelPerf reports the performance metrics of the cluster
+	TelemetryChannelPerf TelemetryChannel = "perf"
+)
+
 // Module represents mgr modules that the user wants to enable or disable
 type Module struct {
 	// Name is the name of the ceph manager module
@@ -631,6 +1150,7 @@ const (
 // PoolSpec represents the spec of ceph pool
 type PoolSpec struct {
 	// The failure domain: osd/host/(region or zone if available) - technically also any type in the crush map
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_.-]*$`
 	// +optional
 	FailureDomain string `json:"failureDomain,omitempty"`
 
@@ -680,6 +1200,30 @@ type PoolSpec struct {
 	// +optional
 	// +nullable
 	Quotas QuotaSpec `json:"quotas,omitempty"`
+
+	// The scrub settings of the pool, which override the scrub settings of the OSDs for the pool
+	// +optional
+	// +nullable
+	Scrub *PoolScrubSpec `json:"scrub,omitempty"`
+}
+
+// PoolScrubSpec represents the scrubbing of a pool, e.g. to scrub a latency-sensitive pool less often or with a
+// lower priority than the other pools. The settings that are not set keep their current value.
+type PoolScrubSpec struct {
+	// MinInterval is the minimum interval between the scrubs of a PG of the pool when the cluster is not busy
+	// +optional
+	MinInterval *metav1.Duration `json:"minInterval,omitempty"`
+	// MaxInterval is the maximum interval between the scrubs of a PG of the pool, regardless of the load
+	// +optional
+	MaxInterval *metav1.Duration `json:"maxInterval,omitempty"`
+	// DeepInterval is the interval between the deep scrubs of a PG of the pool
+	// +optional
+	DeepInterval *metav1.Duration `json:"deepInterval,omitempty"`
+	// Priority is the priority of the scrub operations of the pool compared to the client operations
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=63
+	// +optional
+	Priority *int `json:"priority,omitempty"`
 }
 
 // NamedBlockPoolSpec allows a block pool to be created with a non-default name.
@@ -894,9 +1438,11 @@ type Status struct {
 type ReplicatedSpec struct {
 	// Size - Number of copies per object in a replicated storage pool, including the object itself (required for replicated pool type)
 	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=10
 	Size uint `json:"size"`
 
 	// TargetSizeRatio gives a hint (%) to Ceph in terms of expected consumption of the total cluster capacity
+	// +kubebuilder:validation:Minimum=0
 	// +optional
 	TargetSizeRatio float64 `json:"targetSizeRatio,omitempty"`
 
@@ -910,6 +1456,7 @@ type ReplicatedSpec struct {
 	ReplicasPerFailureDomain uint `json:"replicasPerFailureDomain,omitempty"`
 
 	// SubFailureDomain the name of the sub-failure domain
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_.-]*$`
 	// +optional
 	SubFailureDomain string `json:"subFailureDomain,omitempty"`
 
@@ -940,6 +1487,7 @@ type MirroringSpec struct {
 	Enabled bool `json:"enabled,omitempty"`
 
 	// Mode is the mirroring mode: either pool or image
+	// +kubebuilder:validation:Enum=pool;image;""
 	// +optional
 	Mode string `json:"mode,omitempty"`
 
@@ -1337,10 +1885,51 @@ type ObjectStoreSpec struct {
 	// +nullable
 	DataPool PoolSpec `json:"dataPool,omitempty"`
 
+	// The existing pools the store keeps its data and metadata in, instead of creating its own pools
+	// +optional
+	// +nullable
+	SharedPools ObjectSharedPoolsSpec `json:"sharedPools,omitempty"`
+
+	// The existing realm, zone group and zone of a gateway deployment not created by Rook, adopted by the store
+	// together with their pools instead of creating new ones
+	// +optional
+	// +nullable
+	Adopt *ObjectStoreAdoptSpec `json:"adopt,omitempty"`
+
+	// The DNS names the S3 clients address the store with, so that the buckets can be addressed as virtual hosts
+	// +optional
+	// +nullable
+	Hosting *ObjectStoreHostingSpec `json:"hosting,omitempty"`
+
+	// The Security Token Service of the gateways, with which the applications get temporary credentials
+	// +optional
+	// +nullable
+	STS *ObjectStoreSTSSpec `json:"sts,omitempty"`
+
+	// The protocols served by the gateways in addition to S3
+	// +optional
+	Protocols ProtocolSpec `json:"protocols,omitempty"`
+
+	// The settings of the bucket indexes of the store, applied to the gateways of the store
+	// +optional
+	// +nullable
+	BucketIndex *BucketIndexSpec `json:"bucketIndex,omitempty"`
+
+	// The settings of the garbage collection of the deleted objects, applied to the gateways of the store
+	// +optional
+	// +nullable
+	GarbageCollection *GarbageCollectionSpec `json:"garbageCollection,omitempty"`
+
 	// Preserve pools on object store deletion
 	// +optional
 	PreservePoolsOnDelete bool `json:"preservePoolsOnDelete,omitempty"`
 
+	// The namespaces other than the namespace of the store in which the CephObjectStoreUsers of the store can be
+	// created, or "*" for all the namespaces
+	// +optional
+	// +nullable
+	AllowUsersInNamespaces []string `json:"allowUsersInNamespaces,omitempty"`
+
 	// The rgw pod info
 	// +optional
 	// +nullable
@@ -1360,48 +1949,283 @@ type ObjectStoreSpec struct {
 	// +optional
 	// +nullable
 	Security *ObjectStoreSecuritySpec `json:"security,omitempty"`
-}
 
-// ObjectHealthCheckSpec represents the health check of an object store
-type ObjectHealthCheckSpec struct {
-	// livenessProbe field is no longer used
-	// +kubebuilder:pruning:PreserveUnknownFields
+	// The cloud tiers the objects of the store can be transitioned to by bucket lifecycle rules
+	// +optional
+	// +nullable
+	CloudTiers []CloudTierSpec `json:"cloudTiers,omitempty"`
 
+	// The additional placement targets and storage classes of the store, each backed by its own data pool
 	// +optional
-	ReadinessProbe *ProbeSpec `json:"readinessProbe,omitempty"`
+	// +nullable
+	PlacementTargets []PlacementTargetSpec `json:"placementTargets,omitempty"`
+
+	// The summary of the quotas and usage of the users and buckets of the store, reported in the status
 	// +optional
-	StartupProbe *ProbeSpec `json:"startupProbe,omitempty"`
+	UsageSummary ObjectUsageSummarySpec `json:"usageSummary,omitempty"`
 }
 
-// HealthCheckSpec represents the health check of an object store bucket
-type HealthCheckSpec struct {
-	// +optional
-	Disabled bool `json:"disabled,omitempty"`
-	// Interval is the internal in second or minute for the health check to run like 60s for 60 seconds
+// ProtocolSpec represents the protocols served by the gateways of an object store
+type ProtocolSpec struct {
+	// The settings of the Swift API. The gateways serve the Swift API with the default settings of Ceph if not set.
 	// +optional
-	Interval *metav1.Duration `json:"interval,omitempty"`
-	// +optional
-	Timeout string `json:"timeout,omitempty"`
+	// +nullable
+	Swift *SwiftSpec `json:"swift,omitempty"`
 }
 
-// GatewaySpec represents the specification of Ceph Object Store Gateway
-type GatewaySpec struct {
-	// The port the rgw service will be listening on (http)
+// SwiftSpec represents the settings of the Swift API of the gateways
+type SwiftSpec struct {
+	// The URL prefix of the Swift API, e.g. "swift" for the API at http://<endpoint>/swift/v1. Defaults to "swift".
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_-]+$`
 	// +optional
-	Port int32 `json:"port,omitempty"`
+	// +nullable
+	UrlPrefix *string `json:"urlPrefix,omitempty"`
 
-	// The port the rgw service will be listening on (https)
-	// +kubebuilder:validation:Minimum=0
-	// +kubebuilder:validation:Maximum=65535
+	// Whether the account of the Swift API is in the URL after the prefix, e.g. http://<endpoint>/swift/v1/AUTH_<tenant>,
+	// as expected by the clients that get the URL from a Keystone catalog
+	// +optional
 	// +nullable
+	AccountInUrl *bool `json:"accountInUrl,omitempty"`
+
+	// Whether the object versioning of the Swift API is enabled, with the X-Versions-Location and X-History-Location
+	// headers of the containers
 	// +optional
-	SecurePort int32 `json:"securePort,omitempty"`
+	// +nullable
+	VersioningEnabled *bool `json:"versioningEnabled,omitempty"`
 
-	// The number of pods in the rgw replicaset.
+	// The entry point of the Swift auth, where the clients get a token with the Swift key of a subuser, e.g. "auth" for
+	// http://<endpoint>/auth/1.0. Defaults to "auth".
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_-]+$`
+	// +optional
+	// +nullable
+	AuthEntry *string `json:"authEntry,omitempty"`
+}
+
+// BucketIndexSpec represents the settings of the bucket indexes of the gateways
+type BucketIndexSpec struct {
+	// Whether the gateways reshard the indexes of the buckets that grow large. Ceph enables the dynamic resharding by
+	// default, but it is often disabled in multisite configurations.
+	// +optional
+	// +nullable
+	DynamicResharding *bool `json:"dynamicResharding,omitempty"`
+
+	// The number of shards of the indexes of the new buckets, instead of the default number of shards of the gateways.
+	// 0 keeps the default.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	// +nullable
+	OverrideMaxShards *int32 `json:"overrideMaxShards,omitempty"`
+}
+
+// GarbageCollectionSpec represents the settings of the garbage collection of the gateways, which removes the data of
+// the deleted objects
+type GarbageCollectionSpec struct {
+	// The number of shards of the garbage collection queue. More shards let more gateways collect in parallel.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	// +nullable
+	MaxObjects *int32 `json:"maxObjects,omitempty"`
+
+	// The period between the runs of the garbage collection, e.g. "1h". It is rounded to seconds.
+	// +optional
+	// +nullable
+	ProcessorPeriod *metav1.Duration `json:"processorPeriod,omitempty"`
+
+	// The maximum number of concurrent requests to the OSDs during a run of the garbage collection
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	// +nullable
+	MaxConcurrentIO *int32 `json:"maxConcurrentIO,omitempty"`
+}
+
+// ObjectStoreAdoptSpec represents the existing realm, zone group and zone adopted by an object store. Their pools
+// are neither created nor deleted with the store, and the zone keeps its configuration and system keys.
+type ObjectStoreAdoptSpec struct {
+	// The name of the existing realm
+	// +kubebuilder:validation:MinLength=1
+	Realm string `json:"realm"`
+
+	// The name of the existing zone group in the realm
+	// +kubebuilder:validation:MinLength=1
+	ZoneGroup string `json:"zoneGroup"`
+
+	// The name of the existing zone in the zone group
+	// +kubebuilder:validation:MinLength=1
+	Zone string `json:"zone"`
+}
+
+// ObjectSharedPoolsSpec represents the pools created outside of the object store that its data and metadata are kept
+// in. Each store keeps its objects in rados namespaces named after its zone, so the pools can be shared by many stores.
+type ObjectSharedPoolsSpec struct {
+	// The name of the pool of the metadata of the store
+	// +optional
+	MetadataPoolName string `json:"metadataPoolName,omitempty"`
+
+	// The name of the pool of the data of the store
+	// +optional
+	DataPoolName string `json:"dataPoolName,omitempty"`
+}
+
+// ObjectStoreHostingSpec represents the DNS names of an object store. With virtual-hosted-style addressing, the
+// S3 clients address a bucket as a subdomain of the DNS name of the store, e.g. bucket.store.example.com.
+type ObjectStoreHostingSpec struct {
+	// DNSName is the main DNS name of the store, set as the rgw_dns_name of the gateways
+	// +optional
+	DNSName string `json:"dnsName,omitempty"`
+
+	// AdditionalDNSNames are the other DNS names of the store, set as the hostnames of its zonegroup
+	// +optional
+	AdditionalDNSNames []string `json:"additionalDNSNames,omitempty"`
+}
+
+// ObjectStoreSTSSpec represents the Security Token Service (STS) of the gateways. The STS API is served on the S3
+// endpoint of the store, so that the applications can get temporary credentials with AssumeRole and
+// AssumeRoleWithWebIdentity.
+type ObjectStoreSTSSpec struct {
+	// Enabled enables the STS API of the gateways
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// KeySecretName is the name of a secret in the namespace of the store, with the key that encrypts the session
+	// tokens in its "key" data. The key must be 16 alphanumeric characters. If not set, a key is generated in a secret
+	// managed by the operator.
+	// +optional
+	KeySecretName string `json:"keySecretName,omitempty"`
+}
+
+// ObjectUsageSummarySpec represents the periodic report of the quotas and usage of the users and buckets of an
+// object store in its status
+type ObjectUsageSummarySpec struct {
+	// Enabled reports the quotas and usage of all the users and buckets of the store in the status
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// Interval is the interval at which the summary is refreshed, e.g. 10m. Defaults to 5m.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	// MaxUsers is the number of users with the largest usage listed in the summary. The totals of the summary always
+	// cover all the users. Defaults to 20.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxUsers int `json:"maxUsers,omitempty"`
+	// MaxBucketsPerUser is the number of buckets with the largest usage listed for each user. Defaults to 10.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxBucketsPerUser int `json:"maxBucketsPerUser,omitempty"`
+}
+
+// PlacementTargetSpec represents a placement target of the zone of an object store. Buckets are created in a
+// placement target, and their objects are written to the data pool of one of its storage classes.
+type PlacementTargetSpec struct {
+	// The name of the placement target. The storage classes of the "default-placement" target are added to the
+	// placement target created with the object store.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The data pool of the STANDARD storage class of the placement target. Required for all placement targets
+	// except "default-placement", whose STANDARD storage class is backed by the data pool of the object store.
+	// +optional
+	// +nullable
+	DataPool PoolSpec `json:"dataPool,omitempty"`
+
+	// The additional storage classes of the placement target
+	// +optional
+	// +nullable
+	StorageClasses []PlacementStorageClassSpec `json:"storageClasses,omitempty"`
+}
+
+// PlacementStorageClassSpec represents an S3 storage class of a placement target backed by its own data pool
+type PlacementStorageClassSpec struct {
+	// The name of the storage class, e.g. STANDARD_IA
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The data pool of the storage class
+	// +nullable
+	DataPool PoolSpec `json:"dataPool"`
+}
+
+// CloudTierSpec represents a remote S3 service added as a storage class of the placement target of an object
+// store, so that bucket lifecycle rules can transition objects to it
+type CloudTierSpec struct {
+	// The name of the storage class that lifecycle rules reference to transition objects to the cloud tier
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The placement target of the zonegroup the storage class is added to. Defaults to "default-placement".
+	// +optional
+	PlacementTarget string `json:"placementTarget,omitempty"`
+
+	// The endpoint of the remote S3 service, e.g. https://s3.amazonaws.com
+	// +kubebuilder:validation:MinLength=1
+	Endpoint string `json:"endpoint"`
+
+	// The region of the remote S3 service
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// The name of the secret in the namespace of the object store with the "access-key" and "secret-key" of the
+	// remote S3 service
+	// +kubebuilder:validation:MinLength=1
+	CredentialsSecretName string `json:"credentialsSecretName"`
+
+	// The bucket of the remote S3 service where the objects are transitioned to. Defaults to a bucket named after
+	// the zonegroup and the storage class.
+	// +optional
+	TargetPath string `json:"targetPath,omitempty"`
+
+	// Whether to keep the head of the transitioned objects in the object store, so they are still listed
+	// +optional
+	RetainHeadObject bool `json:"retainHeadObject,omitempty"`
+}
+
+// ObjectHealthCheckSpec represents the health check of an object store
+type ObjectHealthCheckSpec struct {
+	// livenessProbe field is no longer used
+	// +kubebuilder:pruning:PreserveUnknownFields
+
+	// +optional
+	ReadinessProbe *ProbeSpec `json:"readinessProbe,omitempty"`
+	// +optional
+	StartupProbe *ProbeSpec `json:"startupProbe,omitempty"`
+}
+
+// HealthCheckSpec represents the health check of an object store bucket
+type HealthCheckSpec struct {
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+	// Interval is the internal in second or minute for the health check to run like 60s for 60 seconds
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	// +optional
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// GatewaySpec represents the specification of Ceph Object Store Gateway
+type GatewaySpec struct {
+	// The port the rgw service will be listening on (http)
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// The port the rgw service will be listening on (https)
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=65535
+	// +nullable
+	// +optional
+	SecurePort int32 `json:"securePort,omitempty"`
+
+	// The number of pods in the rgw replicaset.
+	// +kubebuilder:validation:Minimum=0
 	// +nullable
 	// +optional
 	Instances int32 `json:"instances,omitempty"`
 
+	// Whether a gateway runs on every node matching the placement, with a daemonset instead of a deployment. The
+	// instances are ignored.
+	// +optional
+	AllNodes bool `json:"allNodes,omitempty"`
+
 	// The name of the secret that stores the ssl certificate for secure rgw connections
 	// +nullable
 	// +optional
@@ -1452,6 +2276,12 @@ type GatewaySpec struct {
 	// +nullable
 	Service *RGWServiceSpec `json:"service,omitempty"`
 
+	// The ingress of the rgw service, which the operator creates to expose the gateways outside of the cluster.
+	// On OpenShift, a route is created instead unless an ingress class is set.
+	// +optional
+	// +nullable
+	Ingress *RGWIngressSpec `json:"ingress,omitempty"`
+
 	// Whether host networking is enabled for the rgw daemon. If not set, the network settings from the cluster CR will be applied.
 	// +kubebuilder:pruning:PreserveUnknownFields
 	// +nullable
@@ -1463,6 +2293,132 @@ type GatewaySpec struct {
 	// +nullable
 	// +optional
 	DashboardEnabled *bool `json:"dashboardEnabled,omitempty"`
+
+	// Config is a map of Ceph config options applied to the rgw daemons of the object store in the
+	// centralized mon configuration database, e.g. "rgw_thread_pool_size". Options removed from the map
+	// are removed from the database.
+	// +optional
+	// +nullable
+	Config map[string]string `json:"config,omitempty"`
+
+	// Proxy is the handling of the requests that reach the rgw daemons through proxies, such as ingress
+	// controllers or L7 load balancers
+	// +optional
+	// +nullable
+	Proxy *GatewayProxySpec `json:"proxy,omitempty"`
+
+	// OpsLog is the log of the S3 and Swift requests served by the rgw daemons, which a sidecar of the rgw pods
+	// prints to its stdout
+	// +optional
+	// +nullable
+	OpsLog *GatewayOpsLogSpec `json:"opsLog,omitempty"`
+
+	// Frontend is the tuning of the beast frontend of the rgw daemons, e.g. for the S3 clients that send large
+	// headers or keep their connections idle for long
+	// +optional
+	// +nullable
+	Frontend *GatewayFrontendSpec `json:"frontend,omitempty"`
+
+	// Tracing is the tracing of the S3 and Swift requests served by the rgw daemons, which a jaeger agent sidecar
+	// of the rgw pods forwards to a collector. There is no sampling rate since rgw traces all the requests and the
+	// agent forwards all the traces, the traces are sampled by the collector.
+	// +optional
+	// +nullable
+	Tracing *GatewayTracingSpec `json:"tracing,omitempty"`
+}
+
+// GatewayTracingSpec represents the tracing of the requests served by the rgw daemons
+type GatewayTracingSpec struct {
+	// Enabled traces the requests served by the rgw daemons and runs the agent sidecar in the rgw pods.
+	// Requires a Ceph image built with the jaeger tracing.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// CollectorSecretName is the name of the secret with the host:port of the gRPC endpoint of the collector
+	// the traces are sent to in its "endpoint" key, e.g. of a jaeger or an OpenTelemetry collector
+	// +optional
+	CollectorSecretName string `json:"collectorSecretName,omitempty"`
+
+	// AgentImage is the image of the jaeger agent sidecar. The default is jaegertracing/jaeger-agent:1.47.0.
+	// +optional
+	AgentImage string `json:"agentImage,omitempty"`
+
+	// Resources are the resource requirements of the agent sidecar
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +nullable
+	// +optional
+	Resources v1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// GatewayFrontendSpec represents the options of the beast frontend of the rgw daemons
+type GatewayFrontendSpec struct {
+	// RequestTimeout is how long a connection may wait for the data of a request or stay idle between the requests
+	// of a keepalive connection before it is closed. The default of beast is 65s, 0 disables the timeout.
+	// +optional
+	// +nullable
+	RequestTimeout *metav1.Duration `json:"requestTimeout,omitempty"`
+
+	// MaxHeaderSize is the maximum size in bytes of the headers of a request. The default of beast is 16384.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65536
+	// +optional
+	MaxHeaderSize int32 `json:"maxHeaderSize,omitempty"`
+
+	// MaxConnectionBacklog is the maximum number of the connections waiting to be accepted. The default of beast
+	// is the maximum of the system.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxConnectionBacklog int32 `json:"maxConnectionBacklog,omitempty"`
+
+	// TCPNoDelay disables the Nagle algorithm on the connections. The default of beast is to disable it.
+	// +optional
+	// +nullable
+	TCPNoDelay *bool `json:"tcpNoDelay,omitempty"`
+}
+
+// GatewayOpsLogSpec represents the log of the requests served by the rgw daemons
+type GatewayOpsLogSpec struct {
+	// Enabled runs the ops log sidecar in the rgw pods. The requests are not logged in the log pool of the zone.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Format is the format of the lines of the ops log, "json" for the ops log entries of rgw or "combined" for the
+	// combined log format of the http servers. The default is "json".
+	// +kubebuilder:validation:Enum=json;combined
+	// +optional
+	Format OpsLogFormat `json:"format,omitempty"`
+
+	// Resources are the resource requirements of the ops log sidecar
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +nullable
+	// +optional
+	Resources v1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// OpsLogFormat is the format of the lines of the ops log
+type OpsLogFormat string
+
+const (
+	// OpsLogFormatJSON prints an ops log entry of rgw as a JSON object per line
+	OpsLogFormatJSON OpsLogFormat = "json"
+	// OpsLogFormatCombined prints a line per request in the combined log format of the http servers
+	OpsLogFormatCombined OpsLogFormat = "combined"
+)
+
+// GatewayProxySpec represents the handling of the requests that reach the rgw daemons through proxies
+type GatewayProxySpec struct {
+	// RemoteAddrHeader is the HTTP header the proxies set with the address of the client, e.g. "X-Forwarded-For".
+	// The rgw daemons use the address of the header instead of the address of the proxy in their logs and in the
+	// "aws:SourceIp" conditions of the bucket policies.
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9-]+$`
+	// +optional
+	RemoteAddrHeader string `json:"remoteAddrHeader,omitempty"`
+
+	// TrustedProxies is the list of the CIDRs of the proxies, e.g. "10.0.0.0/8", required with the remote addr
+	// header. The rgw daemons trust the remote addr header of any client, so the ingress to the rgw pods from outside
+	// of the pod network is restricted with a network policy to the trusted proxies.
+	// +optional
+	TrustedProxies []string `json:"trustedProxies,omitempty"`
 }
 
 // EndpointAddress is a tuple that describes a single IP address or host name. This is a subset of
@@ -1498,6 +2454,98 @@ type ObjectStoreStatus struct {
 	// ObservedGeneration is the latest generation observed by the controller.
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// UsageSummary is the summary of the quotas and usage of the users and buckets of the store
+	// +optional
+	// +nullable
+	UsageSummary *ObjectUsageSummary `json:"usageSummary,omitempty"`
+	// Realm is the realm topology of the store
+	// +optional
+	// +nullable
+	Realm *ObjectRealmTopology `json:"realm,omitempty"`
+}
+
+// ObjectRealmTopology represents the realm, zone group and zone of an object store, and the other object stores of
+// the namespace in the same realm
+type ObjectRealmTopology struct {
+	// Name is the name of the realm
+	Name string `json:"name"`
+	// ZoneGroup is the name of the zone group of the store
+	ZoneGroup string `json:"zoneGroup"`
+	// Zone is the name of the zone of the store
+	Zone string `json:"zone"`
+	// Stores are the names of the object stores in the realm, including this store
+	// +optional
+	Stores []string `json:"stores,omitempty"`
+}
+
+// ObjectUsageSummary represents the quotas and usage of the users and buckets of an object store
+type ObjectUsageSummary struct {
+	// Users are the users of the store with the largest usage, with the buckets they own
+	// +optional
+	Users []ObjectUserUsage `json:"users,omitempty"`
+	// TotalUsers is the number of users of the store
+	// +optional
+	TotalUsers int `json:"totalUsers"`
+	// TotalBuckets is the number of buckets of the store
+	// +optional
+	TotalBuckets int `json:"totalBuckets"`
+	// TotalSize is the size in bytes of the objects of all the buckets
+	// +optional
+	TotalSize uint64 `json:"totalSize"`
+	// TotalObjects is the number of objects of all the buckets
+	// +optional
+	TotalObjects uint64 `json:"totalObjects"`
+	// LastChecked is the time the summary was last refreshed
+	// +optional
+	LastChecked string `json:"lastChecked,omitempty"`
+	// Details is the error of the last refresh, if it failed
+	// +optional
+	Details string `json:"details,omitempty"`
+}
+
+// ObjectUserUsage represents the quotas and usage of a user of an object store
+type ObjectUserUsage struct {
+	// ID is the id of the user, prefixed with its tenant, e.g. "tenant$user"
+	ID string `json:"id"`
+	// MaxBuckets is the maximum number of buckets the user can own
+	// +optional
+	MaxBuckets *int `json:"maxBuckets,omitempty"`
+	// Quota is the quota of the user, if enabled
+	// +optional
+	Quota *ObjectQuotaStatus `json:"quota,omitempty"`
+	// Size is the size in bytes of the objects of the buckets of the user
+	Size uint64 `json:"size"`
+	// Objects is the number of objects of the buckets of the user
+	Objects uint64 `json:"objects"`
+	// BucketCount is the number of buckets owned by the user
+	// +optional
+	BucketCount int `json:"bucketCount"`
+	// Buckets are the buckets owned by the user with the largest usage
+	// +optional
+	Buckets []ObjectBucketUsage `json:"buckets,omitempty"`
+}
+
+// ObjectBucketUsage represents the quota and usage of a bucket of an object store
+type ObjectBucketUsage struct {
+	// Name is the name of the bucket
+	Name string `json:"name"`
+	// Quota is the quota of the bucket, if enabled
+	// +optional
+	Quota *ObjectQuotaStatus `json:"quota,omitempty"`
+	// Size is the size in bytes of the objects of the bucket
+	Size uint64 `json:"size"`
+	// Objects is the number of objects of the bucket
+	Objects uint64 `json:"objects"`
+}
+
+// ObjectQuotaStatus represents an enabled quota of a user or a bucket
+type ObjectQuotaStatus struct {
+	// MaxSize is the maximum size in bytes, if limited
+	// +optional
+	MaxSize *int64 `json:"maxSize,omitempty"`
+	// MaxObjects is the maximum number of objects, if limited
+	// +optional
+	MaxObjects *int64 `json:"maxObjects,omitempty"`
 }
 
 type ObjectEndpoints struct {
@@ -1534,7 +2582,8 @@ type ObjectStoreUserStatus struct {
 	Info map[string]string `json:"info,omitempty"`
 	// ObservedGeneration is the latest generation observed by the controller.
 	// +optional
-	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	ObservedGeneration int64       `json:"observedGeneration,omitempty"`
+	Conditions         []Condition `json:"conditions,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -1548,7 +2597,8 @@ type CephObjectStoreUserList struct {
 
 // ObjectStoreUserSpec represent the spec of an Objectstoreuser
 type ObjectStoreUserSpec struct {
-	// The store the user will be created in
+	// The store the user will be created in, as "<namespace>/<name>" when the store is in another namespace than the
+	// user. The namespace of the user must be allowed by the store.
 	// +optional
 	Store string `json:"store,omitempty"`
 	// The display name for the ceph users
@@ -1560,10 +2610,70 @@ type ObjectStoreUserSpec struct {
 	// +optional
 	// +nullable
 	Quotas *ObjectUserQuotaSpec `json:"quotas,omitempty"`
+	// The subusers of the user, each with a Swift key generated by the operator, with which the Swift clients access
+	// the buckets of the user
+	// +optional
+	// +nullable
+	SubUsers []ObjectUserSubUserSpec `json:"subUsers,omitempty"`
+	// The rate limit of the requests of the user, to throttle the noisy tenants
+	// +optional
+	// +nullable
+	RateLimit *ObjectRateLimitSpec `json:"rateLimit,omitempty"`
+	// The expiration of the user, after which the operator suspends or deletes the user and deletes its secrets, for
+	// the short-lived credentials of CI pipelines and jobs
+	// +optional
+	// +nullable
+	Expiration *ObjectUserExpirationSpec `json:"expiration,omitempty"`
+}
+
+// ObjectUserExpirationSpec represents the expiration of an object store user
+type ObjectUserExpirationSpec struct {
+	// The time to live of the user from the creation of the CephObjectStoreUser, e.g. "24h"
+	TTL metav1.Duration `json:"ttl"`
+	// The action taken when the user expires. "Suspend" suspends the user and deletes its secrets, keeping its
+	// buckets. "Delete" deletes the CephObjectStoreUser, together with the user and its secrets.
+	// +kubebuilder:validation:Enum=Suspend;Delete
+	// +optional
+	Action ObjectUserExpirationAction `json:"action,omitempty"`
 }
 
+// ObjectUserExpirationAction is the action taken when an object store user expires
+type ObjectUserExpirationAction string
+
+const (
+	// ObjectUserExpirationSuspend suspends the expired user and deletes its secrets
+	ObjectUserExpirationSuspend ObjectUserExpirationAction = "Suspend"
+	// ObjectUserExpirationDelete deletes the expired CephObjectStoreUser
+	ObjectUserExpirationDelete ObjectUserExpirationAction = "Delete"
+)
+
+// ObjectUserSubUserSpec represents a subuser of an object store user
+type ObjectUserSubUserSpec struct {
+	// The name of the subuser, the Swift user is "<user>:<name>"
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+	// The access of the subuser to the buckets of the user
+	// +kubebuilder:validation:Enum=read;write;readwrite;full
+	Access SubUserAccess `json:"access"`
+}
+
+// SubUserAccess is the access of a subuser to the buckets of its user
+type SubUserAccess string
+
+const (
+	SubUserAccessRead      SubUserAccess = "read"
+	SubUserAccessWrite     SubUserAccess = "write"
+	SubUserAccessReadWrite SubUserAccess = "readwrite"
+	SubUserAccessFull      SubUserAccess = "full"
+)
+
 // Additional admin-level capabilities for the Ceph object store user
 type ObjectUserCapSpec struct {
+	// +optional
+	// +kubebuilder:validation:Enum=rgw-admin-ops;monitoring-readonly
+	// The named profile of the admin capabilities, maintained by the operator. The capabilities set below override the
+	// capabilities of the profile for the same type.
+	Profile CapsProfile `json:"profile,omitempty"`
 	// +optional
 	// +kubebuilder:validation:Enum={"*","read","write","read, write"}
 	// Admin capabilities to read/write Ceph object store users. Documented in https://docs.ceph.com/en/latest/radosgw/admin/?#add-remove-admin-capabilities
@@ -1603,6 +2713,29 @@ type ObjectUserQuotaSpec struct {
 	MaxObjects *int64 `json:"maxObjects,omitempty"`
 }
 
+// ObjectRateLimitSpec represents the rate limit of the requests of an object store user. The limits apply per minute
+// on each gateway of the store, and the limits that are not set are unlimited.
+type ObjectRateLimitSpec struct {
+	// Maximum number of read requests per minute
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	// +nullable
+	MaxReadOps *int64 `json:"maxReadOps,omitempty"`
+	// Maximum number of write requests per minute
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	// +nullable
+	MaxWriteOps *int64 `json:"maxWriteOps,omitempty"`
+	// Maximum size of the data read per minute
+	// +optional
+	// +nullable
+	MaxReadBytes *resource.Quantity `json:"maxReadBytes,omitempty"`
+	// Maximum size of the data written per minute
+	// +optional
+	// +nullable
+	MaxWriteBytes *resource.Quantity `json:"maxWriteBytes,omitempty"`
+}
+
 // CephObjectRealm represents a Ceph Object Store Gateway Realm
 // +genclient
 // +genclient:noStatus
@@ -1650,21 +2783,75 @@ type CephObjectZoneGroup struct {
 	Spec              ObjectZoneGroupSpec `json:"spec"`
 	// +kubebuilder:pruning:PreserveUnknownFields
 	// +optional
-	Status *Status `json:"status,omitempty"`
-}
-
-// CephObjectZoneGroupList represents a list Ceph Object Store Gateway Zone Groups
-// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
-type CephObjectZoneGroupList struct {
-	metav1.TypeMeta `json:",inline"`
-	metav1.ListMeta `json:"metadata"`
-	Items           []CephObjectZoneGroup `json:"items"`
+	Status *Status `json:"status,omitempty"`
+}
+
+// CephObjectZoneGroupList represents a list Ceph Object Store Gateway Zone Groups
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type CephObjectZoneGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []CephObjectZoneGroup `json:"items"`
+}
+
+// ObjectZoneGroupSpec represent the spec of an ObjectZoneGroup
+type ObjectZoneGroupSpec struct {
+	//The display name for the ceph users
+	// +kubebuilder:validation:MinLength=1
+	Realm string `json:"realm"`
+
+	// EnabledFeatures are the features of rgw enabled in the zone group, e.g. "resharding". The features removed
+	// from the list are not disabled. Requires Ceph Reef (v18) or newer.
+	// +optional
+	// +nullable
+	EnabledFeatures []string `json:"enabledFeatures,omitempty"`
+
+	// Defaults are the defaults of the users and buckets created by the gateways of the object stores in the zone
+	// group, instead of setting them on every CephObjectStoreUser
+	// +optional
+	// +nullable
+	Defaults *ZoneGroupDefaultsSpec `json:"defaults,omitempty"`
+}
+
+// ZoneGroupDefaultsSpec represents the defaults of the users and buckets created in a zone group
+type ZoneGroupDefaultsSpec struct {
+	// MaxBuckets is the default maximum number of buckets of a user
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	// +nullable
+	MaxBuckets *int `json:"maxBuckets,omitempty"`
+	// UserQuota is the default quota of a user
+	// +optional
+	// +nullable
+	UserQuota *ObjectQuotaDefaultsSpec `json:"userQuota,omitempty"`
+	// BucketQuota is the default quota of a bucket
+	// +optional
+	// +nullable
+	BucketQuota *ObjectQuotaDefaultsSpec `json:"bucketQuota,omitempty"`
+	// UserRateLimit is the default rate limit of the requests of a user on each gateway. The rate limits are global
+	// to the realm, so only one zone group of the realm can set them. Removing it does not disable the rate limit.
+	// +optional
+	// +nullable
+	UserRateLimit *ObjectRateLimitSpec `json:"userRateLimit,omitempty"`
+	// BucketRateLimit is the default rate limit of the requests to a bucket on each gateway. The rate limits are
+	// global to the realm, so only one zone group of the realm can set them. Removing it does not disable the rate limit.
+	// +optional
+	// +nullable
+	BucketRateLimit *ObjectRateLimitSpec `json:"bucketRateLimit,omitempty"`
 }
 
-// ObjectZoneGroupSpec represent the spec of an ObjectZoneGroup
-type ObjectZoneGroupSpec struct {
-	//The display name for the ceph users
-	Realm string `json:"realm"`
+// ObjectQuotaDefaultsSpec represents a default quota of the users or buckets
+type ObjectQuotaDefaultsSpec struct {
+	// Maximum size of the objects
+	// See https://pkg.go.dev/k8s.io/apimachinery/pkg/api/resource#Quantity for more info.
+	// +optional
+	// +nullable
+	MaxSize *resource.Quantity `json:"maxSize,omitempty"`
+	// Maximum number of objects
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	// +nullable
+	MaxObjects *int64 `json:"maxObjects,omitempty"`
 }
 
 // CephObjectZone represents a Ceph Object Store Gateway Zone
@@ -1693,6 +2880,7 @@ type CephObjectZoneList struct {
 // ObjectZoneSpec represent the spec of an ObjectZone
 type ObjectZoneSpec struct {
 	//The display name for the ceph users
+	// +kubebuilder:validation:MinLength=1
 	ZoneGroup string `json:"zoneGroup"`
 
 	// The metadata pool settings
@@ -1713,10 +2901,112 @@ type ObjectZoneSpec struct {
 	// +optional
 	CustomEndpoints []string `json:"customEndpoints,omitempty"`
 
+	// GatewayPodEndpoints sets the endpoints of the zone to the addresses of the ready gateway pods of the
+	// object stores in the zone, instead of the ClusterIP Service endpoint. The gateway pods are watched, and
+	// the pods that are not ready are removed from the endpoints until they are ready again. This is meant for
+	// the gateways on the host network, whose addresses are reachable from the peer Ceph clusters.
+	// Cannot be set with customEndpoints.
+	// +optional
+	GatewayPodEndpoints bool `json:"gatewayPodEndpoints,omitempty"`
+
 	// Preserve pools on object zone deletion
 	// +optional
 	// +kubebuilder:default=true
 	PreservePoolsOnDelete bool `json:"preservePoolsOnDelete"`
+
+	// MetadataSearch configures the zone to sync the object metadata to Elasticsearch, so that the
+	// metadata of the objects can be searched. The zone must not be the master zone of the zone group.
+	// +optional
+	// +nullable
+	MetadataSearch *ZoneMetadataSearchSpec `json:"metadataSearch,omitempty"`
+
+	// SyncModule configures the zone to run the cloud or the pubsub sync module, which exports the data of
+	// the objects of the other zones of the zone group to an external service instead of storing the objects.
+	// The zone must not be the master zone of the zone group. Cannot be set with metadataSearch.
+	// +optional
+	// +nullable
+	SyncModule *ZoneSyncModuleSpec `json:"syncModule,omitempty"`
+}
+
+// ZoneSyncModuleSpec represents the sync module of a zone. Exactly one sync module must be set.
+type ZoneSyncModuleSpec struct {
+	// Cloud syncs the objects to an external S3 compatible object store, e.g. AWS S3
+	// +optional
+	// +nullable
+	Cloud *CloudSyncModuleSpec `json:"cloud,omitempty"`
+
+	// PubSub publishes the events of the changes of the objects
+	// +optional
+	// +nullable
+	PubSub *PubSubSyncModuleSpec `json:"pubsub,omitempty"`
+}
+
+// ZoneMetadataSearchSpec represents the Elasticsearch sync module of a zone
+type ZoneMetadataSearchSpec struct {
+	// Endpoint is the url of the Elasticsearch server, e.g. "http://elasticsearch.example.com:9200"
+	// +kubebuilder:validation:MinLength=1
+	Endpoint string `json:"endpoint"`
+
+	// NumShards is the number of shards of the index of the zone in Elasticsearch
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	// +nullable
+	NumShards *int32 `json:"numShards,omitempty"`
+
+	// NumReplicas is the number of replicas of the index of the zone in Elasticsearch
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	// +nullable
+	NumReplicas *int32 `json:"numReplicas,omitempty"`
+
+	// ExplicitCustomMeta only indexes the custom metadata of the objects that is configured on their bucket
+	// when true, and all the custom metadata otherwise
+	// +optional
+	// +nullable
+	ExplicitCustomMeta *bool `json:"explicitCustomMeta,omitempty"`
+}
+
+// CloudSyncModuleSpec represents the cloud sync module of a zone
+type CloudSyncModuleSpec struct {
+	// Endpoint is the url of the target object store, e.g. "https://s3.us-east-1.amazonaws.com"
+	// +kubebuilder:validation:MinLength=1
+	Endpoint string `json:"endpoint"`
+
+	// CredentialsSecretName is the name of the secret in the namespace of the zone with the "access-key"
+	// and the "secret-key" of the target object store
+	// +kubebuilder:validation:MinLength=1
+	CredentialsSecretName string `json:"credentialsSecretName"`
+
+	// HostStyle is the style of the requests to the buckets of the target object store
+	// +kubebuilder:validation:Enum=path;virtual
+	// +optional
+	HostStyle string `json:"hostStyle,omitempty"`
+
+	// TargetPath is the name of the bucket the objects are synced to in the target object store. It can
+	// contain the ${zonegroup}, ${sid}, ${bucket} and ${owner} variables.
+	// +optional
+	TargetPath string `json:"targetPath,omitempty"`
+}
+
+// PubSubSyncModuleSpec represents the pubsub sync module of a zone
+type PubSubSyncModuleSpec struct {
+	// UID is the user of the pubsub sync module, which owns the buckets of the events
+	// +kubebuilder:validation:MinLength=1
+	UID string `json:"uid"`
+
+	// DataBucketPrefix is the prefix of the names of the buckets of the events
+	// +optional
+	DataBucketPrefix string `json:"dataBucketPrefix,omitempty"`
+
+	// DataOIDPrefix is the prefix of the names of the objects of the events
+	// +optional
+	DataOIDPrefix string `json:"dataOIDPrefix,omitempty"`
+
+	// EventsRetentionDays is the number of days the events are kept
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	// +nullable
+	EventsRetentionDays *int32 `json:"eventsRetentionDays,omitempty"`
 }
 
 // CephBucketTopic represents a Ceph Object Topic for Bucket Notifications
@@ -1744,7 +3034,8 @@ type BucketTopicStatus struct {
 	ARN *string `json:"ARN,omitempty"`
 	// ObservedGeneration is the latest generation observed by the controller.
 	// +optional
-	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	ObservedGeneration int64       `json:"observedGeneration,omitempty"`
+	Conditions         []Condition `json:"conditions,omitempty"`
 }
 
 // CephBucketTopicList represents a list Ceph Object Store Bucket Notification Topics
@@ -1914,6 +3205,30 @@ type RGWServiceSpec struct {
 	Annotations Annotations `json:"annotations,omitempty"`
 }
 
+// RGWIngressSpec represents the ingress of the rgw service
+type RGWIngressSpec struct {
+	// The host name the gateways are reachable at through the ingress
+	// +kubebuilder:validation:MinLength=1
+	Host string `json:"host"`
+
+	// The name of the secret in the namespace of the store with the TLS certificate of the host. The TLS is
+	// terminated by the ingress controller, or by the router of OpenShift, in which case the certificate is copied
+	// in the route.
+	// +optional
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
+
+	// The name of the ingress class of the ingress. The default ingress class of the cluster is used if not set.
+	// On OpenShift, an ingress is only created instead of a route when the class is set.
+	// +optional
+	// +nullable
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+
+	// The annotations of the ingress, e.g. to configure the ingress controller
+	// +optional
+	// +nullable
+	Annotations Annotations `json:"annotations,omitempty"`
+}
+
 // CephNFS represents a Ceph NFS
 // +genclient
 // +genclient:noStatus
@@ -1971,6 +3286,7 @@ type GaneshaRADOSSpec struct {
 // GaneshaServerSpec represents the specification of a Ganesha Server
 type GaneshaServerSpec struct {
 	// The number of active Ganesha servers
+	// +kubebuilder:validation:Minimum=1
 	Active int `json:"active"`
 
 	// The affinity to place the ganesha pods
@@ -2293,8 +3609,41 @@ type CephClientList struct {
 type ClientSpec struct {
 	// +optional
 	Name string `json:"name,omitempty"`
+	// The caps of the client by daemon type. They override the caps of the profile for the same daemon type.
+	// +optional
 	// +kubebuilder:pruning:PreserveUnknownFields
-	Caps map[string]string `json:"caps"`
+	Caps map[string]string `json:"caps,omitempty"`
+	// The named caps profile of the client, maintained by the operator for the ceph version of the cluster
+	// +optional
+	// +nullable
+	Profile *ClientCapsProfileSpec `json:"profile,omitempty"`
+}
+
+// CapsProfile is the name of a set of caps maintained by the operator
+type CapsProfile string
+
+const (
+	// CapsProfileRBDUser allows mapping and managing the rbd images of pools
+	CapsProfileRBDUser CapsProfile = "rbd-user"
+	// CapsProfileCephFSUser allows mounting a filesystem and managing its subvolumes
+	CapsProfileCephFSUser CapsProfile = "cephfs-user"
+	// CapsProfileRGWAdminOps allows managing the users and buckets of the object stores
+	CapsProfileRGWAdminOps CapsProfile = "rgw-admin-ops"
+	// CapsProfileMonitoringReadOnly allows reading the status and metrics of the cluster
+	CapsProfileMonitoringReadOnly CapsProfile = "monitoring-readonly"
+)
+
+// ClientCapsProfileSpec represents the named caps profile of a Ceph client
+type ClientCapsProfileSpec struct {
+	// The name of the profile
+	// +kubebuilder:validation:Enum=rbd-user;cephfs-user;rgw-admin-ops;monitoring-readonly
+	Name CapsProfile `json:"name"`
+	// The pools the rbd-user profile is restricted to. All pools if empty.
+	// +optional
+	Pools []string `json:"pools,omitempty"`
+	// The filesystem the cephfs-user profile is restricted to. All filesystems if empty.
+	// +optional
+	Filesystem string `json:"filesystem,omitempty"`
 }
 
 // CephClientStatus represents the Status of Ceph Client
@@ -2306,7 +3655,8 @@ type CephClientStatus struct {
 	Info map[string]string `json:"info,omitempty"`
 	// ObservedGeneration is the latest generation observed by the controller.
 	// +optional
-	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	ObservedGeneration int64       `json:"observedGeneration,omitempty"`
+	Conditions         []Condition `json:"conditions,omitempty"`
 }
 
 // CleanupPolicySpec represents a Ceph Cluster cleanup policy
@@ -2344,7 +3694,8 @@ type SanitizeDisksSpec struct {
 	// +optional
 	// +kubebuilder:validation:Enum=zero;random
 	DataSource SanitizeDataSourceProperty `json:"dataSource,omitempty"`
-	// Iteration is the number of pass to apply the sanitizing
+	// Iteration is the number of pass to apply the sanitizing, one pass if not set
+	// +kubebuilder:validation:Minimum=0
 	// +optional
 	Iteration int32 `json:"iteration,omitempty"`
 }
@@ -2497,6 +3848,111 @@ type StorageScopeSpec struct {
 	// +nullable
 	// +optional
 	StorageClassDeviceSets []StorageClassDeviceSet `json:"storageClassDeviceSets,omitempty"`
+	// EphemeralDevices configures the automatic replacement of OSDs on devices that are lost with their node
+	// +optional
+	EphemeralDevices EphemeralDevicesSpec `json:"ephemeralDevices,omitempty"`
+	// SwappedDevices configures the automatic replacement of OSDs whose device was swapped for a new device
+	// +optional
+	SwappedDevices SwappedDevicesSpec `json:"swappedDevices,omitempty"`
+	// FullRatio is the ratio of used space of an OSD at which the cluster is full and the writes are blocked.
+	// Ceph defaults to 0.95.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1
+	// +nullable
+	// +optional
+	FullRatio *float64 `json:"fullRatio,omitempty"`
+	// BackfillFullRatio is the ratio of used space of an OSD above which the data is not backfilled to it.
+	// Ceph defaults to 0.90.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1
+	// +nullable
+	// +optional
+	BackfillFullRatio *float64 `json:"backfillFullRatio,omitempty"`
+	// NearFullRatio is the ratio of used space of an OSD above which the cluster health warns that it is nearly
+	// full. Ceph defaults to 0.85.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1
+	// +nullable
+	// +optional
+	NearFullRatio *float64 `json:"nearFullRatio,omitempty"`
+	// FullEmergency configures the handling of the cluster by the operator when OSDs are full
+	// +optional
+	FullEmergency FullEmergencySpec `json:"fullEmergency,omitempty"`
+	// NodeOnboarding configures the automatic addition of the labeled nodes to the storage nodes
+	// +optional
+	NodeOnboarding NodeOnboardingSpec `json:"nodeOnboarding,omitempty"`
+	// DriveGroups are ceph-volume drive group specs applied to the storage nodes they select. The OSDs of the nodes
+	// selected by a drive group are provisioned from its spec only, ignoring the device selection of the nodes.
+	// +nullable
+	// +optional
+	DriveGroups []DriveGroup `json:"driveGroups,omitempty"`
+}
+
+// DriveGroup is a ceph-volume drive group spec applied to the storage nodes matching its node selector, giving access
+// to the layouts of ceph-volume batch such as the data devices sharing the db devices by size or model
+type DriveGroup struct {
+	// Name is the unique name of the drive group, used as the service_id of its spec
+	Name string `json:"name"`
+	// Spec is the ceph-volume drive group spec, e.g. data_devices, db_devices, wal_devices or osds_per_device
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	Spec runtime.RawExtension `json:"spec"`
+	// NodeSelector is the labels of the storage nodes the drive group applies to. The drive group applies to all
+	// the storage nodes when it is empty.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+}
+
+// NodeOnboardingSpec configures the automatic addition of the Kubernetes nodes with a label to the storage nodes of
+// the cluster, so that the nodes of autoscaled pools do not have to be added to the CephCluster one by one
+type NodeOnboardingSpec struct {
+	// Enabled adds the schedulable and ready nodes matching the node selector to the storage nodes
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// NodeSelector is the labels of the nodes to add to the storage nodes, e.g. storage-role: ceph
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// RemoveNodes removes the added nodes from the storage nodes when they are deleted from Kubernetes or no longer
+	// match the node selector. The nodes that are cordoned, e.g. while they are drained, are kept. The OSDs of the
+	// removed nodes are not purged.
+	// +optional
+	RemoveNodes bool `json:"removeNodes,omitempty"`
+}
+
+// FullEmergencySpec configures the handling of the cluster by the operator when OSDs are full
+type FullEmergencySpec struct {
+	// Enabled raises warning events on the CephCluster when OSDs are nearly full or full, with the steps
+	// to reclaim space, and reports the emergency in the status
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// ProtectPools puts the pools in a safe state while OSDs are full: the PG autoscaler of the pools is turned
+	// off and the nopgchange and nosizechange flags of the pools are set, so that no PG is split or merged and no
+	// replica is added, and the norebalance flag of the OSDs is set, so that the misplaced data is not moved to
+	// the OSDs that still have space. The changes are reverted when the OSDs are no longer full.
+	// +optional
+	ProtectPools bool `json:"protectPools,omitempty"`
+}
+
+// SwappedDevicesSpec configures the replacement of the OSDs on nodes whose failed device was swapped for a new device
+type SwappedDevicesSpec struct {
+	// Enabled purges the OSDs that are down and no longer found on their node when a new OSD uses a device in the
+	// same slot of the node, once they are safe to destroy, so that the new OSD replaces them without manual removal steps
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+}
+
+// EphemeralDevicesSpec configures OSDs on ephemeral local devices (e.g., cloud instances with local NVMe)
+// that are expected to be lost when the underlying node is replaced
+type EphemeralDevicesSpec struct {
+	// Enabled indicates that OSDs that are down because their node was removed or recreated should be purged
+	// from the cluster once they are safe to destroy, so that replacement OSDs can be provisioned on the new node
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// MaxConcurrentReplacements is the maximum number of lost OSDs that will be purged at once. Lost OSDs are
+	// only purged while no PGs are recovering or backfilling. Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxConcurrentReplacements int `json:"maxConcurrentReplacements,omitempty"`
 }
 
 // Node is a storage nodes
@@ -2527,6 +3983,17 @@ type Device struct {
 	Config map[string]string `json:"config,omitempty"`
 }
 
+// DeviceFilter is a regular expression selecting devices by name, along with the OSD configuration
+// applied to the devices it matches
+type DeviceFilter struct {
+	// Filter is the regular expression matched against the device names
+	Filter string `json:"filter"`
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +nullable
+	// +optional
+	Config map[string]string `json:"config,omitempty"`
+}
+
 type Selection struct {
 	// Whether to consume all the storage devices found on a machine
 	// +optional
@@ -2537,6 +4004,10 @@ type Selection struct {
 	// A regular expression to allow more fine-grained selection of devices with path names
 	// +optional
 	DevicePathFilter string `json:"devicePathFilter,omitempty"`
+	// List of regular expressions selecting devices on nodes, each with its own OSD configuration
+	// +nullable
+	// +optional
+	DeviceFilters []DeviceFilter `json:"deviceFilters,omitempty"`
 	// List of devices to use as storage devices
 	// +kubebuilder:pruning:PreserveUnknownFields
 	// +nullable
@@ -2629,6 +4100,30 @@ type StorageClassDeviceSet struct {
 	// Whether to encrypt the deviceSet
 	// +optional
 	Encrypted bool `json:"encrypted,omitempty"`
+	// Provider configures the lifecycle of the cloud provider disks of the PVCs of the device set
+	// +optional
+	// +nullable
+	Provider *DeviceSetProviderSpec `json:"provider,omitempty"`
+}
+
+// DeviceSetProviderSpec configures the cloud provider disks (e.g. EBS or Azure Disk) of the PVCs of a device set
+type DeviceSetProviderSpec struct {
+	// Annotations are the provider parameters of the disks read by the CSI driver, such as their IOPS or throughput.
+	// They are added to the new PVCs and kept up to date on the existing PVCs so the driver can modify their disks.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Labels are added to the PVCs and to their PVs, e.g. to attribute the cost of the disks
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// ReclaimPolicy is set on the PVs of the device set once their PVCs are bound.
+	// Retain keeps the provider disks when their PVC is deleted.
+	// +kubebuilder:validation:Enum=Retain;Delete
+	// +optional
+	ReclaimPolicy v1.PersistentVolumeReclaimPolicy `json:"reclaimPolicy,omitempty"`
+	// ReportEvents reports the warning events of the PVCs of the device set on the CephCluster, such as the
+	// throttling or the failed modifications of their disks reported by the provider
+	// +optional
+	ReportEvents bool `json:"reportEvents,omitempty"`
 }
 
 // +genclient
@@ -2664,6 +4159,7 @@ type CephFilesystemSubVolumeGroupSpec struct {
 	// the CephFilesystem CR. If not coming from the CephFilesystem CR, it can be retrieved from the
 	// list of Ceph Filesystem volumes with `ceph fs volume ls`. To learn more about Ceph Filesystem
 	// abstractions see https://docs.ceph.com/en/latest/cephfs/fs-volumes/#fs-volumes-and-subvolumes
+	// +kubebuilder:validation:MinLength=1
 	FilesystemName string `json:"filesystemName"`
 }
 
@@ -2676,7 +4172,8 @@ type CephFilesystemSubVolumeGroupStatus struct {
 	Info map[string]string `json:"info,omitempty"`
 	// ObservedGeneration is the latest generation observed by the controller.
 	// +optional
-	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	ObservedGeneration int64       `json:"observedGeneration,omitempty"`
+	Conditions         []Condition `json:"conditions,omitempty"`
 }
 
 // +genclient
@@ -2709,6 +4206,7 @@ type CephBlockPoolRadosNamespaceList struct {
 type CephBlockPoolRadosNamespaceSpec struct {
 	// BlockPoolName is the name of Ceph BlockPool. Typically it's the name of
 	// the CephBlockPool CR.
+	// +kubebuilder:validation:MinLength=1
 	BlockPoolName string `json:"blockPoolName"`
 }
 
@@ -2720,4 +4218,202 @@ type CephBlockPoolRadosNamespaceStatus struct {
 	// +optional
 	// +nullable
 	Info map[string]string `json:"info,omitempty"`
+	// ObservedGeneration is the latest generation observed by the controller.
+	// +optional
+	ObservedGeneration int64       `json:"observedGeneration,omitempty"`
+	Conditions         []Condition `json:"conditions,omitempty"`
+}
+
+// CephCOSIDriver represents the Ceph COSI driver, which provisions the buckets and the bucket accesses of the
+// Kubernetes Container Object Storage Interface in the object stores of the cluster
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:shortName=cephcosi
+type CephCOSIDriver struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	// Spec represents the specification of the Ceph COSI driver
+	Spec CephCOSIDriverSpec `json:"spec"`
+}
+
+// CephCOSIDriverList represents a list of Ceph COSI drivers
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type CephCOSIDriverList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []CephCOSIDriver `json:"items"`
+}
+
+// CephCOSIDriverSpec represents the specification of the Ceph COSI driver
+type CephCOSIDriverSpec struct {
+	// Image is the container image of the Ceph COSI driver
+	// +optional
+	Image string `json:"image,omitempty"`
+	// ObjectProvisionerImage is the container image of the COSI provisioner sidecar of the driver
+	// +optional
+	ObjectProvisionerImage string `json:"objectProvisionerImage,omitempty"`
+	// DeploymentStrategy is the strategy to deploy the COSI driver
+	// +optional
+	// +kubebuilder:validation:Enum=Never;Auto;Always
+	DeploymentStrategy COSIDeploymentStrategy `json:"deploymentStrategy,omitempty"`
+	// Placement is the placement of the COSI driver
+	// +optional
+	// +nullable
+	Placement Placement `json:"placement,omitempty"`
+	// Resources is the resource requirements of the COSI driver
+	// +optional
+	// +nullable
+	Resources v1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// COSIDeploymentStrategy represents the strategy to deploy the Ceph COSI driver
+type COSIDeploymentStrategy string
+
+const (
+	// COSIDeploymentStrategyNever never deploys the COSI driver
+	COSIDeploymentStrategyNever COSIDeploymentStrategy = "Never"
+	// COSIDeploymentStrategyAuto deploys the COSI driver when an object store exists, and is the default
+	COSIDeploymentStrategyAuto COSIDeploymentStrategy = "Auto"
+	// COSIDeploymentStrategyAlways always deploys the COSI driver
+	COSIDeploymentStrategyAlways COSIDeploymentStrategy = "Always"
+)
+
+// CephOperation represents a long-running operation on the Ceph cluster, e.g. the purge of OSDs. The operator runs
+// the steps of the operation one at a time and records the progress in the status, so that the operation resumes
+// after a restart of the operator and can be cancelled between two steps.
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:shortName=cephop
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Completed",type=integer,JSONPath=`.status.completedSteps`
+// +kubebuilder:printcolumn:name="Total",type=integer,JSONPath=`.status.totalSteps`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type CephOperation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	// Spec represents the specification of the operation
+	Spec CephOperationSpec `json:"spec"`
+	// Status represents the progress of the operation
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Status *CephOperationStatus `json:"status,omitempty"`
+}
+
+// CephOperationList represents a list of Ceph operations
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type CephOperationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []CephOperation `json:"items"`
+}
+
+// CephOperationSpec represents the specification of a Ceph operation. Exactly one operation must be set.
+type CephOperationSpec struct {
+	// OSDPurge purges OSDs from the cluster, one OSD per step
+	// +optional
+	// +nullable
+	OSDPurge *OSDPurgeOperationSpec `json:"osdPurge,omitempty"`
+	// Compaction compacts the databases of daemons, one daemon per step
+	// +optional
+	// +nullable
+	Compaction *CompactionOperationSpec `json:"compaction,omitempty"`
+	// DataMigration live migrates RBD images to another pool, one image per step
+	// +optional
+	// +nullable
+	DataMigration *DataMigrationOperationSpec `json:"dataMigration,omitempty"`
+	// OrphanScan scans the data pools of object stores for the RADOS objects that no bucket references, one
+	// object store per step
+	// +optional
+	// +nullable
+	OrphanScan *OrphanScanOperationSpec `json:"orphanScan,omitempty"`
+	// Cancel stops the operation before its next step. A command that the step in progress runs in the background,
+	// e.g. a compaction, keeps running in the cluster until it completes, but its result is ignored.
+	// +optional
+	Cancel bool `json:"cancel,omitempty"`
+}
+
+// OSDPurgeOperationSpec represents the purge of OSDs
+type OSDPurgeOperationSpec struct {
+	// OSDIDs are the IDs of the OSDs to purge. The OSDs must be down.
+	// +kubebuilder:validation:MinItems=1
+	OSDIDs []int `json:"osdIDs"`
+	// Force purges the OSDs even if they are not safe to destroy, at the risk of losing data
+	// +optional
+	Force bool `json:"force,omitempty"`
+	// PreservePVC only detaches the PVCs of the OSDs on PVC from Rook instead of deleting them
+	// +optional
+	PreservePVC bool `json:"preservePVC,omitempty"`
+}
+
+// CompactionOperationSpec represents the compaction of the databases of daemons
+type CompactionOperationSpec struct {
+	// Daemons are the OSDs and mons to compact one after the other, e.g. "osd.0" or "mon.a"
+	// +kubebuilder:validation:MinItems=1
+	Daemons []string `json:"daemons"`
+}
+
+// DataMigrationOperationSpec represents the live migration of RBD images to another pool. The images must not be
+// in use while the migration is prepared.
+type DataMigrationOperationSpec struct {
+	// SourcePool is the pool of the images to migrate
+	// +kubebuilder:validation:MinLength=1
+	SourcePool string `json:"sourcePool"`
+	// TargetPool is the pool the images are migrated to
+	// +kubebuilder:validation:MinLength=1
+	TargetPool string `json:"targetPool"`
+	// Images are the names of the images to migrate
+	// +kubebuilder:validation:MinItems=1
+	Images []string `json:"images"`
+}
+
+// OrphanScanOperationSpec represents the scan of the data pools of object stores for the orphan RADOS objects.
+// The orphan objects found are reported in the events of the operation, they are not deleted.
+type OrphanScanOperationSpec struct {
+	// ObjectStores are the names of the CephObjectStores to scan, in the namespace of the operation
+	// +kubebuilder:validation:MinItems=1
+	ObjectStores []string `json:"objectStores"`
 }
+
+// CephOperationStatus represents the progress of a Ceph operation
+type CephOperationStatus struct {
+	// Phase is the phase of the operation
+	// +optional
+	Phase CephOperationPhase `json:"phase,omitempty"`
+	// TotalSteps is the number of steps of the operation
+	// +optional
+	TotalSteps int `json:"totalSteps,omitempty"`
+	// CompletedSteps is the number of steps completed. The operation resumes from the next step.
+	// +optional
+	CompletedSteps int `json:"completedSteps,omitempty"`
+	// CurrentStep is the step in progress
+	// +optional
+	CurrentStep string `json:"currentStep,omitempty"`
+	// Message is the reason of the failure of the operation, or what the current step is waiting for
+	// +optional
+	Message string `json:"message,omitempty"`
+	// StartTime is the time the operation started
+	// +optional
+	// +nullable
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is the time the operation succeeded, failed or was cancelled
+	// +optional
+	// +nullable
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// CephOperationPhase represents the phase of a Ceph operation
+type CephOperationPhase string
+
+const (
+	// CephOperationRunning is the phase of an operation whose steps are running
+	CephOperationRunning CephOperationPhase = "Running"
+	// CephOperationSucceeded is the phase of an operation whose steps all completed
+	CephOperationSucceeded CephOperationPhase = "Succeeded"
+	// CephOperationFailed is the phase of an operation whose step failed
+	CephOperationFailed CephOperationPhase = "Failed"
+	// CephOperationCancelled is the phase of an operation cancelled before all its steps completed
+	CephOperationCancelled CephOperationPhase = "Cancelled"
+)
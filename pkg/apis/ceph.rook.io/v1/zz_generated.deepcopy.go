@@ -97,6 +97,32 @@ func (in AnnotationsSpec) DeepCopy() AnnotationsSpec {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BucketIndexSpec) DeepCopyInto(out *BucketIndexSpec) {
+	*out = *in
+	if in.DynamicResharding != nil {
+		in, out := &in.DynamicResharding, &out.DynamicResharding
+		*out = new(bool)
+		**out = **in
+	}
+	if in.OverrideMaxShards != nil {
+		in, out := &in.OverrideMaxShards, &out.OverrideMaxShards
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BucketIndexSpec.
+func (in *BucketIndexSpec) DeepCopy() *BucketIndexSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BucketIndexSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BucketNotificationSpec) DeepCopyInto(out *BucketNotificationSpec) {
 	*out = *in
@@ -148,6 +174,13 @@ func (in *BucketTopicStatus) DeepCopyInto(out *BucketTopicStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -333,6 +366,13 @@ func (in *CephBlockPoolRadosNamespaceStatus) DeepCopyInto(out *CephBlockPoolRado
 			(*out)[key] = val
 		}
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -521,6 +561,84 @@ func (in *CephBucketTopicList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephCOSIDriver) DeepCopyInto(out *CephCOSIDriver) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephCOSIDriver.
+func (in *CephCOSIDriver) DeepCopy() *CephCOSIDriver {
+	if in == nil {
+		return nil
+	}
+	out := new(CephCOSIDriver)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephCOSIDriver) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephCOSIDriverList) DeepCopyInto(out *CephCOSIDriverList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CephCOSIDriver, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephCOSIDriverList.
+func (in *CephCOSIDriverList) DeepCopy() *CephCOSIDriverList {
+	if in == nil {
+		return nil
+	}
+	out := new(CephCOSIDriverList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephCOSIDriverList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephCOSIDriverSpec) DeepCopyInto(out *CephCOSIDriverSpec) {
+	*out = *in
+	in.Placement.DeepCopyInto(&out.Placement)
+	in.Resources.DeepCopyInto(&out.Resources)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephCOSIDriverSpec.
+func (in *CephCOSIDriverSpec) DeepCopy() *CephCOSIDriverSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CephCOSIDriverSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CephClient) DeepCopyInto(out *CephClient) {
 	*out = *in
@@ -596,6 +714,13 @@ func (in *CephClientStatus) DeepCopyInto(out *CephClientStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -671,6 +796,9 @@ func (in *CephClusterHealthCheckSpec) DeepCopyInto(out *CephClusterHealthCheckSp
 			(*out)[key] = outVal
 		}
 	}
+	in.ClockSkew.DeepCopyInto(&out.ClockSkew)
+	in.ScrubReport.DeepCopyInto(&out.ScrubReport)
+	in.MonFailover.DeepCopyInto(&out.MonFailover)
 	return
 }
 
@@ -1050,6 +1178,13 @@ func (in *CephFilesystemSubVolumeGroupStatus) DeepCopyInto(out *CephFilesystemSu
 			(*out)[key] = val
 		}
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -1376,7 +1511,7 @@ func (in *CephObjectZoneGroup) DeepCopyInto(out *CephObjectZoneGroup) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	if in.Status != nil {
 		in, out := &in.Status, &out.Status
 		*out = new(Status)
@@ -1469,6 +1604,131 @@ func (in *CephObjectZoneList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephOperation) DeepCopyInto(out *CephOperation) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(CephOperationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephOperation.
+func (in *CephOperation) DeepCopy() *CephOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(CephOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephOperation) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephOperationList) DeepCopyInto(out *CephOperationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CephOperation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephOperationList.
+func (in *CephOperationList) DeepCopy() *CephOperationList {
+	if in == nil {
+		return nil
+	}
+	out := new(CephOperationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephOperationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephOperationSpec) DeepCopyInto(out *CephOperationSpec) {
+	*out = *in
+	if in.OSDPurge != nil {
+		in, out := &in.OSDPurge, &out.OSDPurge
+		*out = new(OSDPurgeOperationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Compaction != nil {
+		in, out := &in.Compaction, &out.Compaction
+		*out = new(CompactionOperationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DataMigration != nil {
+		in, out := &in.DataMigration, &out.DataMigration
+		*out = new(DataMigrationOperationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OrphanScan != nil {
+		in, out := &in.OrphanScan, &out.OrphanScan
+		*out = new(OrphanScanOperationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephOperationSpec.
+func (in *CephOperationSpec) DeepCopy() *CephOperationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CephOperationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephOperationStatus) DeepCopyInto(out *CephOperationStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephOperationStatus.
+func (in *CephOperationStatus) DeepCopy() *CephOperationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CephOperationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CephRBDMirror) DeepCopyInto(out *CephRBDMirror) {
 	*out = *in
@@ -1550,6 +1810,21 @@ func (in *CephStatus) DeepCopyInto(out *CephStatus) {
 		*out = new(CephDaemonsVersions)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.UnsafePools != nil {
+		in, out := &in.UnsafePools, &out.UnsafePools
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FullEmergency != nil {
+		in, out := &in.FullEmergency, &out.FullEmergency
+		*out = new(FullEmergencyStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MgrModules != nil {
+		in, out := &in.MgrModules, &out.MgrModules
+		*out = new(MgrModulesStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1617,6 +1892,27 @@ func (in *CleanupPolicySpec) DeepCopy() *CleanupPolicySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientCapsProfileSpec) DeepCopyInto(out *ClientCapsProfileSpec) {
+	*out = *in
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientCapsProfileSpec.
+func (in *ClientCapsProfileSpec) DeepCopy() *ClientCapsProfileSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientCapsProfileSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClientSpec) DeepCopyInto(out *ClientSpec) {
 	*out = *in
@@ -1627,6 +1923,11 @@ func (in *ClientSpec) DeepCopyInto(out *ClientSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.Profile != nil {
+		in, out := &in.Profile, &out.Profile
+		*out = new(ClientCapsProfileSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1641,41 +1942,112 @@ func (in *ClientSpec) DeepCopy() *ClientSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
+func (in *ClockSkewHealthSpec) DeepCopyInto(out *ClockSkewHealthSpec) {
 	*out = *in
-	out.CephVersion = in.CephVersion
-	in.Storage.DeepCopyInto(&out.Storage)
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(AnnotationsSpec, len(*in))
-		for key, val := range *in {
-			var outVal map[string]string
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				in, out := &val, &outVal
-				*out = make(Annotations, len(*in))
-				for key, val := range *in {
-					(*out)[key] = val
-				}
-			}
-			(*out)[key] = outVal
-		}
+	if in.Threshold != nil {
+		in, out := &in.Threshold, &out.Threshold
+		*out = new(metav1.Duration)
+		**out = **in
 	}
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make(LabelsSpec, len(*in))
-		for key, val := range *in {
-			var outVal map[string]string
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				in, out := &val, &outVal
-				*out = make(Labels, len(*in))
-				for key, val := range *in {
-					(*out)[key] = val
-				}
-			}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClockSkewHealthSpec.
+func (in *ClockSkewHealthSpec) DeepCopy() *ClockSkewHealthSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClockSkewHealthSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudSyncModuleSpec) DeepCopyInto(out *CloudSyncModuleSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudSyncModuleSpec.
+func (in *CloudSyncModuleSpec) DeepCopy() *CloudSyncModuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudSyncModuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudTierSpec) DeepCopyInto(out *CloudTierSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudTierSpec.
+func (in *CloudTierSpec) DeepCopy() *CloudTierSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudTierSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
+	*out = *in
+	out.CephVersion = in.CephVersion
+	if in.CephConfig != nil {
+		in, out := &in.CephConfig, &out.CephConfig
+		*out = make(map[string]map[string]string, len(*in))
+		for key, val := range *in {
+			var outVal map[string]string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(map[string]string, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	in.Scrub.DeepCopyInto(&out.Scrub)
+	in.Storage.DeepCopyInto(&out.Storage)
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(AnnotationsSpec, len(*in))
+		for key, val := range *in {
+			var outVal map[string]string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(Annotations, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(LabelsSpec, len(*in))
+		for key, val := range *in {
+			var outVal map[string]string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(Labels, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
 			(*out)[key] = outVal
 		}
 	}
@@ -1708,6 +2080,9 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 	in.Monitoring.DeepCopyInto(&out.Monitoring)
 	out.External = in.External
 	in.Mgr.DeepCopyInto(&out.Mgr)
+	out.Crush = in.Crush
+	in.Preflight.DeepCopyInto(&out.Preflight)
+	in.DataDirHostPathPreparation.DeepCopyInto(&out.DataDirHostPathPreparation)
 	out.CleanupPolicy = in.CleanupPolicy
 	in.HealthCheck.DeepCopyInto(&out.HealthCheck)
 	in.Security.DeepCopyInto(&out.Security)
@@ -1750,6 +2125,21 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		*out = new(ClusterVersion)
 		**out = **in
 	}
+	if in.CrushTunables != nil {
+		in, out := &in.CrushTunables, &out.CrushTunables
+		*out = new(CrushTunablesStatus)
+		**out = **in
+	}
+	if in.Preflight != nil {
+		in, out := &in.Preflight, &out.Preflight
+		*out = new(PreflightStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScrubReport != nil {
+		in, out := &in.ScrubReport, &out.ScrubReport
+		*out = new(ScrubReport)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1779,6 +2169,27 @@ func (in *ClusterVersion) DeepCopy() *ClusterVersion {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CompactionOperationSpec) DeepCopyInto(out *CompactionOperationSpec) {
+	*out = *in
+	if in.Daemons != nil {
+		in, out := &in.Daemons, &out.Daemons
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompactionOperationSpec.
+func (in *CompactionOperationSpec) DeepCopy() *CompactionOperationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CompactionOperationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CompressionSpec) DeepCopyInto(out *CompressionSpec) {
 	*out = *in
@@ -1856,254 +2267,479 @@ func (in *CrashCollectorSpec) DeepCopy() *CrashCollectorSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DaemonHealthSpec) DeepCopyInto(out *DaemonHealthSpec) {
+func (in *CrushSpec) DeepCopyInto(out *CrushSpec) {
 	*out = *in
-	in.Status.DeepCopyInto(&out.Status)
-	in.Monitor.DeepCopyInto(&out.Monitor)
-	in.ObjectStorageDaemon.DeepCopyInto(&out.ObjectStorageDaemon)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DaemonHealthSpec.
-func (in *DaemonHealthSpec) DeepCopy() *DaemonHealthSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrushSpec.
+func (in *CrushSpec) DeepCopy() *CrushSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(DaemonHealthSpec)
+	out := new(CrushSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DashboardSpec) DeepCopyInto(out *DashboardSpec) {
+func (in *CrushTunablesStatus) DeepCopyInto(out *CrushTunablesStatus) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardSpec.
-func (in *DashboardSpec) DeepCopy() *DashboardSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrushTunablesStatus.
+func (in *CrushTunablesStatus) DeepCopy() *CrushTunablesStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(DashboardSpec)
+	out := new(CrushTunablesStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Device) DeepCopyInto(out *Device) {
+func (in *DaemonHardeningSpec) DeepCopyInto(out *DaemonHardeningSpec) {
 	*out = *in
-	if in.Config != nil {
-		in, out := &in.Config, &out.Config
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.AddCapabilities != nil {
+		in, out := &in.AddCapabilities, &out.AddCapabilities
+		*out = make([]corev1.Capability, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Device.
-func (in *Device) DeepCopy() *Device {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DaemonHardeningSpec.
+func (in *DaemonHardeningSpec) DeepCopy() *DaemonHardeningSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Device)
+	out := new(DaemonHardeningSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DeviceClasses) DeepCopyInto(out *DeviceClasses) {
+func (in *DaemonHealthSpec) DeepCopyInto(out *DaemonHealthSpec) {
 	*out = *in
+	in.Status.DeepCopyInto(&out.Status)
+	in.Monitor.DeepCopyInto(&out.Monitor)
+	in.ObjectStorageDaemon.DeepCopyInto(&out.ObjectStorageDaemon)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceClasses.
-func (in *DeviceClasses) DeepCopy() *DeviceClasses {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DaemonHealthSpec.
+func (in *DaemonHealthSpec) DeepCopy() *DaemonHealthSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(DeviceClasses)
+	out := new(DaemonHealthSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DisruptionManagementSpec) DeepCopyInto(out *DisruptionManagementSpec) {
+func (in *DashboardSpec) DeepCopyInto(out *DashboardSpec) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisruptionManagementSpec.
-func (in *DisruptionManagementSpec) DeepCopy() *DisruptionManagementSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardSpec.
+func (in *DashboardSpec) DeepCopy() *DashboardSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(DisruptionManagementSpec)
+	out := new(DashboardSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EncryptionSpec) DeepCopyInto(out *EncryptionSpec) {
+func (in *DataDirHostPathNodeSpec) DeepCopyInto(out *DataDirHostPathNodeSpec) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EncryptionSpec.
-func (in *EncryptionSpec) DeepCopy() *EncryptionSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataDirHostPathNodeSpec.
+func (in *DataDirHostPathNodeSpec) DeepCopy() *DataDirHostPathNodeSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(EncryptionSpec)
+	out := new(DataDirHostPathNodeSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EndpointAddress) DeepCopyInto(out *EndpointAddress) {
+func (in *DataDirHostPathPreparationSpec) DeepCopyInto(out *DataDirHostPathPreparationSpec) {
 	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]DataDirHostPathNodeSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointAddress.
-func (in *EndpointAddress) DeepCopy() *EndpointAddress {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataDirHostPathPreparationSpec.
+func (in *DataDirHostPathPreparationSpec) DeepCopy() *DataDirHostPathPreparationSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(EndpointAddress)
+	out := new(DataDirHostPathPreparationSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ErasureCodedSpec) DeepCopyInto(out *ErasureCodedSpec) {
+func (in *DataMigrationOperationSpec) DeepCopyInto(out *DataMigrationOperationSpec) {
 	*out = *in
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ErasureCodedSpec.
-func (in *ErasureCodedSpec) DeepCopy() *ErasureCodedSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataMigrationOperationSpec.
+func (in *DataMigrationOperationSpec) DeepCopy() *DataMigrationOperationSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ErasureCodedSpec)
+	out := new(DataMigrationOperationSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ExternalSpec) DeepCopyInto(out *ExternalSpec) {
+func (in *Device) DeepCopyInto(out *Device) {
 	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSpec.
-func (in *ExternalSpec) DeepCopy() *ExternalSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Device.
+func (in *Device) DeepCopy() *Device {
 	if in == nil {
 		return nil
 	}
-	out := new(ExternalSpec)
+	out := new(Device)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FSMirroringSpec) DeepCopyInto(out *FSMirroringSpec) {
+func (in *DeviceClasses) DeepCopyInto(out *DeviceClasses) {
 	*out = *in
-	if in.Peers != nil {
-		in, out := &in.Peers, &out.Peers
-		*out = new(MirroringPeerSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.SnapshotSchedules != nil {
-		in, out := &in.SnapshotSchedules, &out.SnapshotSchedules
-		*out = make([]SnapshotScheduleSpec, len(*in))
-		copy(*out, *in)
-	}
-	if in.SnapshotRetention != nil {
-		in, out := &in.SnapshotRetention, &out.SnapshotRetention
-		*out = make([]SnapshotScheduleRetentionSpec, len(*in))
-		copy(*out, *in)
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FSMirroringSpec.
-func (in *FSMirroringSpec) DeepCopy() *FSMirroringSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceClasses.
+func (in *DeviceClasses) DeepCopy() *DeviceClasses {
 	if in == nil {
 		return nil
 	}
-	out := new(FSMirroringSpec)
+	out := new(DeviceClasses)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FilesystemMirrorInfoPeerSpec) DeepCopyInto(out *FilesystemMirrorInfoPeerSpec) {
+func (in *DeviceFilter) DeepCopyInto(out *DeviceFilter) {
 	*out = *in
-	if in.Remote != nil {
-		in, out := &in.Remote, &out.Remote
-		*out = new(PeerRemoteSpec)
-		**out = **in
-	}
-	if in.Stats != nil {
-		in, out := &in.Stats, &out.Stats
-		*out = new(PeerStatSpec)
-		**out = **in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemMirrorInfoPeerSpec.
-func (in *FilesystemMirrorInfoPeerSpec) DeepCopy() *FilesystemMirrorInfoPeerSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceFilter.
+func (in *DeviceFilter) DeepCopy() *DeviceFilter {
 	if in == nil {
 		return nil
 	}
-	out := new(FilesystemMirrorInfoPeerSpec)
+	out := new(DeviceFilter)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FilesystemMirroringInfo) DeepCopyInto(out *FilesystemMirroringInfo) {
+func (in *DeviceSetProviderSpec) DeepCopyInto(out *DeviceSetProviderSpec) {
 	*out = *in
-	if in.Filesystems != nil {
-		in, out := &in.Filesystems, &out.Filesystems
-		*out = make([]FilesystemsSpec, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemMirroringInfo.
-func (in *FilesystemMirroringInfo) DeepCopy() *FilesystemMirroringInfo {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceSetProviderSpec.
+func (in *DeviceSetProviderSpec) DeepCopy() *DeviceSetProviderSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(FilesystemMirroringInfo)
+	out := new(DeviceSetProviderSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FilesystemMirroringInfoSpec) DeepCopyInto(out *FilesystemMirroringInfoSpec) {
+func (in *DisruptionManagementSpec) DeepCopyInto(out *DisruptionManagementSpec) {
 	*out = *in
-	if in.FilesystemMirroringAllInfo != nil {
-		in, out := &in.FilesystemMirroringAllInfo, &out.FilesystemMirroringAllInfo
-		*out = make([]FilesystemMirroringInfo, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemMirroringInfoSpec.
-func (in *FilesystemMirroringInfoSpec) DeepCopy() *FilesystemMirroringInfoSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisruptionManagementSpec.
+func (in *DisruptionManagementSpec) DeepCopy() *DisruptionManagementSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DisruptionManagementSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriveGroup) DeepCopyInto(out *DriveGroup) {
+	*out = *in
+	in.Spec.DeepCopyInto(&out.Spec)
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriveGroup.
+func (in *DriveGroup) DeepCopy() *DriveGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(DriveGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EncryptionSpec) DeepCopyInto(out *EncryptionSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EncryptionSpec.
+func (in *EncryptionSpec) DeepCopy() *EncryptionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EncryptionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EndpointAddress) DeepCopyInto(out *EndpointAddress) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointAddress.
+func (in *EndpointAddress) DeepCopy() *EndpointAddress {
+	if in == nil {
+		return nil
+	}
+	out := new(EndpointAddress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EphemeralDevicesSpec) DeepCopyInto(out *EphemeralDevicesSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EphemeralDevicesSpec.
+func (in *EphemeralDevicesSpec) DeepCopy() *EphemeralDevicesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EphemeralDevicesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ErasureCodedSpec) DeepCopyInto(out *ErasureCodedSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ErasureCodedSpec.
+func (in *ErasureCodedSpec) DeepCopy() *ErasureCodedSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ErasureCodedSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSpec) DeepCopyInto(out *ExternalSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSpec.
+func (in *ExternalSpec) DeepCopy() *ExternalSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FSMirroringSpec) DeepCopyInto(out *FSMirroringSpec) {
+	*out = *in
+	if in.Peers != nil {
+		in, out := &in.Peers, &out.Peers
+		*out = new(MirroringPeerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SnapshotSchedules != nil {
+		in, out := &in.SnapshotSchedules, &out.SnapshotSchedules
+		*out = make([]SnapshotScheduleSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.SnapshotRetention != nil {
+		in, out := &in.SnapshotRetention, &out.SnapshotRetention
+		*out = make([]SnapshotScheduleRetentionSpec, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FSMirroringSpec.
+func (in *FSMirroringSpec) DeepCopy() *FSMirroringSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FSMirroringSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailedMgrModule) DeepCopyInto(out *FailedMgrModule) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailedMgrModule.
+func (in *FailedMgrModule) DeepCopy() *FailedMgrModule {
+	if in == nil {
+		return nil
+	}
+	out := new(FailedMgrModule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilesystemMirrorInfoPeerSpec) DeepCopyInto(out *FilesystemMirrorInfoPeerSpec) {
+	*out = *in
+	if in.Remote != nil {
+		in, out := &in.Remote, &out.Remote
+		*out = new(PeerRemoteSpec)
+		**out = **in
+	}
+	if in.Stats != nil {
+		in, out := &in.Stats, &out.Stats
+		*out = new(PeerStatSpec)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemMirrorInfoPeerSpec.
+func (in *FilesystemMirrorInfoPeerSpec) DeepCopy() *FilesystemMirrorInfoPeerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FilesystemMirrorInfoPeerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilesystemMirroringInfo) DeepCopyInto(out *FilesystemMirroringInfo) {
+	*out = *in
+	if in.Filesystems != nil {
+		in, out := &in.Filesystems, &out.Filesystems
+		*out = make([]FilesystemsSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemMirroringInfo.
+func (in *FilesystemMirroringInfo) DeepCopy() *FilesystemMirroringInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(FilesystemMirroringInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilesystemMirroringInfoSpec) DeepCopyInto(out *FilesystemMirroringInfoSpec) {
+	*out = *in
+	if in.FilesystemMirroringAllInfo != nil {
+		in, out := &in.FilesystemMirroringAllInfo, &out.FilesystemMirroringAllInfo
+		*out = make([]FilesystemMirroringInfo, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemMirroringInfoSpec.
+func (in *FilesystemMirroringInfoSpec) DeepCopy() *FilesystemMirroringInfoSpec {
 	if in == nil {
 		return nil
 	}
@@ -2252,6 +2888,48 @@ func (in *FilesystemsSpec) DeepCopy() *FilesystemsSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FullEmergencySpec) DeepCopyInto(out *FullEmergencySpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FullEmergencySpec.
+func (in *FullEmergencySpec) DeepCopy() *FullEmergencySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FullEmergencySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FullEmergencyStatus) DeepCopyInto(out *FullEmergencyStatus) {
+	*out = *in
+	if in.ProtectedPools != nil {
+		in, out := &in.ProtectedPools, &out.ProtectedPools
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FrozenPools != nil {
+		in, out := &in.FrozenPools, &out.FrozenPools
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FullEmergencyStatus.
+func (in *FullEmergencyStatus) DeepCopy() *FullEmergencyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FullEmergencyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GaneshaRADOSSpec) DeepCopyInto(out *GaneshaRADOSSpec) {
 	*out = *in
@@ -2287,6 +2965,11 @@ func (in *GaneshaServerSpec) DeepCopyInto(out *GaneshaServerSpec) {
 		}
 	}
 	in.Resources.DeepCopyInto(&out.Resources)
+	if in.HostNetwork != nil {
+		in, out := &in.HostNetwork, &out.HostNetwork
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -2300,6 +2983,101 @@ func (in *GaneshaServerSpec) DeepCopy() *GaneshaServerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GarbageCollectionSpec) DeepCopyInto(out *GarbageCollectionSpec) {
+	*out = *in
+	if in.MaxObjects != nil {
+		in, out := &in.MaxObjects, &out.MaxObjects
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ProcessorPeriod != nil {
+		in, out := &in.ProcessorPeriod, &out.ProcessorPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxConcurrentIO != nil {
+		in, out := &in.MaxConcurrentIO, &out.MaxConcurrentIO
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GarbageCollectionSpec.
+func (in *GarbageCollectionSpec) DeepCopy() *GarbageCollectionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GarbageCollectionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayFrontendSpec) DeepCopyInto(out *GatewayFrontendSpec) {
+	*out = *in
+	if in.RequestTimeout != nil {
+		in, out := &in.RequestTimeout, &out.RequestTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.TCPNoDelay != nil {
+		in, out := &in.TCPNoDelay, &out.TCPNoDelay
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayFrontendSpec.
+func (in *GatewayFrontendSpec) DeepCopy() *GatewayFrontendSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayFrontendSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayOpsLogSpec) DeepCopyInto(out *GatewayOpsLogSpec) {
+	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayOpsLogSpec.
+func (in *GatewayOpsLogSpec) DeepCopy() *GatewayOpsLogSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayOpsLogSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayProxySpec) DeepCopyInto(out *GatewayProxySpec) {
+	*out = *in
+	if in.TrustedProxies != nil {
+		in, out := &in.TrustedProxies, &out.TrustedProxies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayProxySpec.
+func (in *GatewayProxySpec) DeepCopy() *GatewayProxySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayProxySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GatewaySpec) DeepCopyInto(out *GatewaySpec) {
 	*out = *in
@@ -2329,11 +3107,48 @@ func (in *GatewaySpec) DeepCopyInto(out *GatewaySpec) {
 		*out = new(RGWServiceSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(RGWIngressSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.HostNetwork != nil {
 		in, out := &in.HostNetwork, &out.HostNetwork
 		*out = new(bool)
 		**out = **in
 	}
+	if in.DashboardEnabled != nil {
+		in, out := &in.DashboardEnabled, &out.DashboardEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(GatewayProxySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OpsLog != nil {
+		in, out := &in.OpsLog, &out.OpsLog
+		*out = new(GatewayOpsLogSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Frontend != nil {
+		in, out := &in.Frontend, &out.Frontend
+		*out = new(GatewayFrontendSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tracing != nil {
+		in, out := &in.Tracing, &out.Tracing
+		*out = new(GatewayTracingSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -2347,6 +3162,23 @@ func (in *GatewaySpec) DeepCopy() *GatewaySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayTracingSpec) DeepCopyInto(out *GatewayTracingSpec) {
+	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayTracingSpec.
+func (in *GatewayTracingSpec) DeepCopy() *GatewayTracingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayTracingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HTTPEndpointSpec) DeepCopyInto(out *HTTPEndpointSpec) {
 	*out = *in
@@ -2489,12 +3321,44 @@ func (in *KeyManagementServiceSpec) DeepCopyInto(out *KeyManagementServiceSpec)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyManagementServiceSpec.
-func (in *KeyManagementServiceSpec) DeepCopy() *KeyManagementServiceSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyManagementServiceSpec.
+func (in *KeyManagementServiceSpec) DeepCopy() *KeyManagementServiceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyManagementServiceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyRotationSpec) DeepCopyInto(out *KeyRotationSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyRotationSpec.
+func (in *KeyRotationSpec) DeepCopy() *KeyRotationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyRotationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyringEncryptionSpec) DeepCopyInto(out *KeyringEncryptionSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyringEncryptionSpec.
+func (in *KeyringEncryptionSpec) DeepCopy() *KeyringEncryptionSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(KeyManagementServiceSpec)
+	out := new(KeyringEncryptionSpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -2616,6 +3480,48 @@ func (in *MetadataServerSpec) DeepCopy() *MetadataServerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MgrModuleRecoverySpec) DeepCopyInto(out *MgrModuleRecoverySpec) {
+	*out = *in
+	if in.Backoff != nil {
+		in, out := &in.Backoff, &out.Backoff
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MgrModuleRecoverySpec.
+func (in *MgrModuleRecoverySpec) DeepCopy() *MgrModuleRecoverySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MgrModuleRecoverySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MgrModulesStatus) DeepCopyInto(out *MgrModulesStatus) {
+	*out = *in
+	if in.Failed != nil {
+		in, out := &in.Failed, &out.Failed
+		*out = make([]FailedMgrModule, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MgrModulesStatus.
+func (in *MgrModulesStatus) DeepCopy() *MgrModulesStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MgrModulesStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MgrSpec) DeepCopyInto(out *MgrSpec) {
 	*out = *in
@@ -2624,6 +3530,12 @@ func (in *MgrSpec) DeepCopyInto(out *MgrSpec) {
 		*out = make([]Module, len(*in))
 		copy(*out, *in)
 	}
+	if in.Telemetry != nil {
+		in, out := &in.Telemetry, &out.Telemetry
+		*out = new(TelemetrySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ModuleRecovery.DeepCopyInto(&out.ModuleRecovery)
 	return
 }
 
@@ -2755,6 +3667,27 @@ func (in *Module) DeepCopy() *Module {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonFailoverSpec) DeepCopyInto(out *MonFailoverSpec) {
+	*out = *in
+	if in.ExcludedNodes != nil {
+		in, out := &in.ExcludedNodes, &out.ExcludedNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonFailoverSpec.
+func (in *MonFailoverSpec) DeepCopy() *MonFailoverSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonFailoverSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MonSpec) DeepCopyInto(out *MonSpec) {
 	*out = *in
@@ -2957,6 +3890,29 @@ func (in *Node) DeepCopy() *Node {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeOnboardingSpec) DeepCopyInto(out *NodeOnboardingSpec) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeOnboardingSpec.
+func (in *NodeOnboardingSpec) DeepCopy() *NodeOnboardingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeOnboardingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in NodesByName) DeepCopyInto(out *NodesByName) {
 	{
@@ -3042,6 +3998,48 @@ func (in *NotificationKeyFilterRule) DeepCopy() *NotificationKeyFilterRule {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSDPurgeOperationSpec) DeepCopyInto(out *OSDPurgeOperationSpec) {
+	*out = *in
+	if in.OSDIDs != nil {
+		in, out := &in.OSDIDs, &out.OSDIDs
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSDPurgeOperationSpec.
+func (in *OSDPurgeOperationSpec) DeepCopy() *OSDPurgeOperationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OSDPurgeOperationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectBucketUsage) DeepCopyInto(out *ObjectBucketUsage) {
+	*out = *in
+	if in.Quota != nil {
+		in, out := &in.Quota, &out.Quota
+		*out = new(ObjectQuotaStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectBucketUsage.
+func (in *ObjectBucketUsage) DeepCopy() *ObjectBucketUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectBucketUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObjectEndpoints) DeepCopyInto(out *ObjectEndpoints) {
 	*out = *in
@@ -3094,6 +4092,94 @@ func (in *ObjectHealthCheckSpec) DeepCopy() *ObjectHealthCheckSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectQuotaDefaultsSpec) DeepCopyInto(out *ObjectQuotaDefaultsSpec) {
+	*out = *in
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxObjects != nil {
+		in, out := &in.MaxObjects, &out.MaxObjects
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectQuotaDefaultsSpec.
+func (in *ObjectQuotaDefaultsSpec) DeepCopy() *ObjectQuotaDefaultsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectQuotaDefaultsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectQuotaStatus) DeepCopyInto(out *ObjectQuotaStatus) {
+	*out = *in
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxObjects != nil {
+		in, out := &in.MaxObjects, &out.MaxObjects
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectQuotaStatus.
+func (in *ObjectQuotaStatus) DeepCopy() *ObjectQuotaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectQuotaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectRateLimitSpec) DeepCopyInto(out *ObjectRateLimitSpec) {
+	*out = *in
+	if in.MaxReadOps != nil {
+		in, out := &in.MaxReadOps, &out.MaxReadOps
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxWriteOps != nil {
+		in, out := &in.MaxWriteOps, &out.MaxWriteOps
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxReadBytes != nil {
+		in, out := &in.MaxReadBytes, &out.MaxReadBytes
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxWriteBytes != nil {
+		in, out := &in.MaxWriteBytes, &out.MaxWriteBytes
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectRateLimitSpec.
+func (in *ObjectRateLimitSpec) DeepCopy() *ObjectRateLimitSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectRateLimitSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObjectRealmSpec) DeepCopyInto(out *ObjectRealmSpec) {
 	*out = *in
@@ -3111,6 +4197,96 @@ func (in *ObjectRealmSpec) DeepCopy() *ObjectRealmSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectRealmTopology) DeepCopyInto(out *ObjectRealmTopology) {
+	*out = *in
+	if in.Stores != nil {
+		in, out := &in.Stores, &out.Stores
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectRealmTopology.
+func (in *ObjectRealmTopology) DeepCopy() *ObjectRealmTopology {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectRealmTopology)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectSharedPoolsSpec) DeepCopyInto(out *ObjectSharedPoolsSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectSharedPoolsSpec.
+func (in *ObjectSharedPoolsSpec) DeepCopy() *ObjectSharedPoolsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectSharedPoolsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectStoreAdoptSpec) DeepCopyInto(out *ObjectStoreAdoptSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectStoreAdoptSpec.
+func (in *ObjectStoreAdoptSpec) DeepCopy() *ObjectStoreAdoptSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectStoreAdoptSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectStoreHostingSpec) DeepCopyInto(out *ObjectStoreHostingSpec) {
+	*out = *in
+	if in.AdditionalDNSNames != nil {
+		in, out := &in.AdditionalDNSNames, &out.AdditionalDNSNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectStoreHostingSpec.
+func (in *ObjectStoreHostingSpec) DeepCopy() *ObjectStoreHostingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectStoreHostingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectStoreSTSSpec) DeepCopyInto(out *ObjectStoreSTSSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectStoreSTSSpec.
+func (in *ObjectStoreSTSSpec) DeepCopy() *ObjectStoreSTSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectStoreSTSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObjectStoreSecuritySpec) DeepCopyInto(out *ObjectStoreSecuritySpec) {
 	*out = *in
@@ -3134,6 +4310,38 @@ func (in *ObjectStoreSpec) DeepCopyInto(out *ObjectStoreSpec) {
 	*out = *in
 	in.MetadataPool.DeepCopyInto(&out.MetadataPool)
 	in.DataPool.DeepCopyInto(&out.DataPool)
+	out.SharedPools = in.SharedPools
+	if in.Adopt != nil {
+		in, out := &in.Adopt, &out.Adopt
+		*out = new(ObjectStoreAdoptSpec)
+		**out = **in
+	}
+	if in.Hosting != nil {
+		in, out := &in.Hosting, &out.Hosting
+		*out = new(ObjectStoreHostingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.STS != nil {
+		in, out := &in.STS, &out.STS
+		*out = new(ObjectStoreSTSSpec)
+		**out = **in
+	}
+	in.Protocols.DeepCopyInto(&out.Protocols)
+	if in.BucketIndex != nil {
+		in, out := &in.BucketIndex, &out.BucketIndex
+		*out = new(BucketIndexSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GarbageCollection != nil {
+		in, out := &in.GarbageCollection, &out.GarbageCollection
+		*out = new(GarbageCollectionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AllowUsersInNamespaces != nil {
+		in, out := &in.AllowUsersInNamespaces, &out.AllowUsersInNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	in.Gateway.DeepCopyInto(&out.Gateway)
 	out.Zone = in.Zone
 	in.HealthCheck.DeepCopyInto(&out.HealthCheck)
@@ -3142,6 +4350,19 @@ func (in *ObjectStoreSpec) DeepCopyInto(out *ObjectStoreSpec) {
 		*out = new(ObjectStoreSecuritySpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CloudTiers != nil {
+		in, out := &in.CloudTiers, &out.CloudTiers
+		*out = make([]CloudTierSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.PlacementTargets != nil {
+		in, out := &in.PlacementTargets, &out.PlacementTargets
+		*out = make([]PlacementTargetSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.UsageSummary.DeepCopyInto(&out.UsageSummary)
 	return
 }
 
@@ -3173,6 +4394,16 @@ func (in *ObjectStoreStatus) DeepCopyInto(out *ObjectStoreStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.UsageSummary != nil {
+		in, out := &in.UsageSummary, &out.UsageSummary
+		*out = new(ObjectUsageSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Realm != nil {
+		in, out := &in.Realm, &out.Realm
+		*out = new(ObjectRealmTopology)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -3199,6 +4430,21 @@ func (in *ObjectStoreUserSpec) DeepCopyInto(out *ObjectStoreUserSpec) {
 		*out = new(ObjectUserQuotaSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.SubUsers != nil {
+		in, out := &in.SubUsers, &out.SubUsers
+		*out = make([]ObjectUserSubUserSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(ObjectRateLimitSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Expiration != nil {
+		in, out := &in.Expiration, &out.Expiration
+		*out = new(ObjectUserExpirationSpec)
+		**out = **in
+	}
 	return
 }
 
@@ -3222,6 +4468,13 @@ func (in *ObjectStoreUserStatus) DeepCopyInto(out *ObjectStoreUserStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -3235,6 +4488,50 @@ func (in *ObjectStoreUserStatus) DeepCopy() *ObjectStoreUserStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectUsageSummary) DeepCopyInto(out *ObjectUsageSummary) {
+	*out = *in
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]ObjectUserUsage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectUsageSummary.
+func (in *ObjectUsageSummary) DeepCopy() *ObjectUsageSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectUsageSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectUsageSummarySpec) DeepCopyInto(out *ObjectUsageSummarySpec) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectUsageSummarySpec.
+func (in *ObjectUsageSummarySpec) DeepCopy() *ObjectUsageSummarySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectUsageSummarySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObjectUserCapSpec) DeepCopyInto(out *ObjectUserCapSpec) {
 	*out = *in
@@ -3251,6 +4548,23 @@ func (in *ObjectUserCapSpec) DeepCopy() *ObjectUserCapSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectUserExpirationSpec) DeepCopyInto(out *ObjectUserExpirationSpec) {
+	*out = *in
+	out.TTL = in.TTL
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectUserExpirationSpec.
+func (in *ObjectUserExpirationSpec) DeepCopy() *ObjectUserExpirationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectUserExpirationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObjectUserQuotaSpec) DeepCopyInto(out *ObjectUserQuotaSpec) {
 	*out = *in
@@ -3282,9 +4596,68 @@ func (in *ObjectUserQuotaSpec) DeepCopy() *ObjectUserQuotaSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectUserSubUserSpec) DeepCopyInto(out *ObjectUserSubUserSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectUserSubUserSpec.
+func (in *ObjectUserSubUserSpec) DeepCopy() *ObjectUserSubUserSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectUserSubUserSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectUserUsage) DeepCopyInto(out *ObjectUserUsage) {
+	*out = *in
+	if in.MaxBuckets != nil {
+		in, out := &in.MaxBuckets, &out.MaxBuckets
+		*out = new(int)
+		**out = **in
+	}
+	if in.Quota != nil {
+		in, out := &in.Quota, &out.Quota
+		*out = new(ObjectQuotaStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Buckets != nil {
+		in, out := &in.Buckets, &out.Buckets
+		*out = make([]ObjectBucketUsage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectUserUsage.
+func (in *ObjectUserUsage) DeepCopy() *ObjectUserUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectUserUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObjectZoneGroupSpec) DeepCopyInto(out *ObjectZoneGroupSpec) {
 	*out = *in
+	if in.EnabledFeatures != nil {
+		in, out := &in.EnabledFeatures, &out.EnabledFeatures
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Defaults != nil {
+		in, out := &in.Defaults, &out.Defaults
+		*out = new(ZoneGroupDefaultsSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -3308,6 +4681,16 @@ func (in *ObjectZoneSpec) DeepCopyInto(out *ObjectZoneSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.MetadataSearch != nil {
+		in, out := &in.MetadataSearch, &out.MetadataSearch
+		*out = new(ZoneMetadataSearchSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SyncModule != nil {
+		in, out := &in.SyncModule, &out.SyncModule
+		*out = new(ZoneSyncModuleSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -3321,6 +4704,27 @@ func (in *ObjectZoneSpec) DeepCopy() *ObjectZoneSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrphanScanOperationSpec) DeepCopyInto(out *OrphanScanOperationSpec) {
+	*out = *in
+	if in.ObjectStores != nil {
+		in, out := &in.ObjectStores, &out.ObjectStores
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrphanScanOperationSpec.
+func (in *OrphanScanOperationSpec) DeepCopy() *OrphanScanOperationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OrphanScanOperationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PeerRemoteSpec) DeepCopyInto(out *PeerRemoteSpec) {
 	*out = *in
@@ -3436,6 +4840,47 @@ func (in PlacementSpec) DeepCopy() PlacementSpec {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementStorageClassSpec) DeepCopyInto(out *PlacementStorageClassSpec) {
+	*out = *in
+	in.DataPool.DeepCopyInto(&out.DataPool)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementStorageClassSpec.
+func (in *PlacementStorageClassSpec) DeepCopy() *PlacementStorageClassSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementStorageClassSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementTargetSpec) DeepCopyInto(out *PlacementTargetSpec) {
+	*out = *in
+	in.DataPool.DeepCopyInto(&out.DataPool)
+	if in.StorageClasses != nil {
+		in, out := &in.StorageClasses, &out.StorageClasses
+		*out = make([]PlacementStorageClassSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementTargetSpec.
+func (in *PlacementTargetSpec) DeepCopy() *PlacementTargetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementTargetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PoolMirroringInfo) DeepCopyInto(out *PoolMirroringInfo) {
 	*out = *in
@@ -3485,40 +4930,179 @@ func (in *PoolMirroringStatusSummarySpec) DeepCopyInto(out *PoolMirroringStatusS
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolMirroringStatusSummarySpec.
-func (in *PoolMirroringStatusSummarySpec) DeepCopy() *PoolMirroringStatusSummarySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolMirroringStatusSummarySpec.
+func (in *PoolMirroringStatusSummarySpec) DeepCopy() *PoolMirroringStatusSummarySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolMirroringStatusSummarySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolScrubReport) DeepCopyInto(out *PoolScrubReport) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolScrubReport.
+func (in *PoolScrubReport) DeepCopy() *PoolScrubReport {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolScrubReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolScrubSpec) DeepCopyInto(out *PoolScrubSpec) {
+	*out = *in
+	if in.MinInterval != nil {
+		in, out := &in.MinInterval, &out.MinInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxInterval != nil {
+		in, out := &in.MaxInterval, &out.MaxInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.DeepInterval != nil {
+		in, out := &in.DeepInterval, &out.DeepInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolScrubSpec.
+func (in *PoolScrubSpec) DeepCopy() *PoolScrubSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolScrubSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolSpec) DeepCopyInto(out *PoolSpec) {
+	*out = *in
+	in.Replicated.DeepCopyInto(&out.Replicated)
+	out.ErasureCoded = in.ErasureCoded
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Mirroring.DeepCopyInto(&out.Mirroring)
+	in.StatusCheck.DeepCopyInto(&out.StatusCheck)
+	in.Quotas.DeepCopyInto(&out.Quotas)
+	if in.Scrub != nil {
+		in, out := &in.Scrub, &out.Scrub
+		*out = new(PoolScrubSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolSpec.
+func (in *PoolSpec) DeepCopy() *PoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreflightNodeStatus) DeepCopyInto(out *PreflightNodeStatus) {
+	*out = *in
+	if in.Failures != nil {
+		in, out := &in.Failures, &out.Failures
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreflightNodeStatus.
+func (in *PreflightNodeStatus) DeepCopy() *PreflightNodeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PreflightNodeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreflightSpec) DeepCopyInto(out *PreflightSpec) {
+	*out = *in
+	if in.SkipChecks != nil {
+		in, out := &in.SkipChecks, &out.SkipChecks
+		*out = make([]PreflightCheck, len(*in))
+		copy(*out, *in)
+	}
+	if in.KernelModules != nil {
+		in, out := &in.KernelModules, &out.KernelModules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Sysctls != nil {
+		in, out := &in.Sysctls, &out.Sysctls
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreflightSpec.
+func (in *PreflightSpec) DeepCopy() *PreflightSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(PoolMirroringStatusSummarySpec)
+	out := new(PreflightSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PoolSpec) DeepCopyInto(out *PoolSpec) {
+func (in *PreflightStatus) DeepCopyInto(out *PreflightStatus) {
 	*out = *in
-	in.Replicated.DeepCopyInto(&out.Replicated)
-	out.ErasureCoded = in.ErasureCoded
-	if in.Parameters != nil {
-		in, out := &in.Parameters, &out.Parameters
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
+	if in.FailedNodes != nil {
+		in, out := &in.FailedNodes, &out.FailedNodes
+		*out = make([]PreflightNodeStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	in.Mirroring.DeepCopyInto(&out.Mirroring)
-	in.StatusCheck.DeepCopyInto(&out.StatusCheck)
-	in.Quotas.DeepCopyInto(&out.Quotas)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolSpec.
-func (in *PoolSpec) DeepCopy() *PoolSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreflightStatus.
+func (in *PreflightStatus) DeepCopy() *PreflightStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PoolSpec)
+	out := new(PreflightStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -3566,6 +5150,48 @@ func (in *ProbeSpec) DeepCopy() *ProbeSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProtocolSpec) DeepCopyInto(out *ProtocolSpec) {
+	*out = *in
+	if in.Swift != nil {
+		in, out := &in.Swift, &out.Swift
+		*out = new(SwiftSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProtocolSpec.
+func (in *ProtocolSpec) DeepCopy() *ProtocolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProtocolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PubSubSyncModuleSpec) DeepCopyInto(out *PubSubSyncModuleSpec) {
+	*out = *in
+	if in.EventsRetentionDays != nil {
+		in, out := &in.EventsRetentionDays, &out.EventsRetentionDays
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PubSubSyncModuleSpec.
+func (in *PubSubSyncModuleSpec) DeepCopy() *PubSubSyncModuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PubSubSyncModuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PullSpec) DeepCopyInto(out *PullSpec) {
 	*out = *in
@@ -3646,6 +5272,34 @@ func (in *RBDMirroringSpec) DeepCopy() *RBDMirroringSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RGWIngressSpec) DeepCopyInto(out *RGWIngressSpec) {
+	*out = *in
+	if in.IngressClassName != nil {
+		in, out := &in.IngressClassName, &out.IngressClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(Annotations, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RGWIngressSpec.
+func (in *RGWIngressSpec) DeepCopy() *RGWIngressSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RGWIngressSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RGWServiceSpec) DeepCopyInto(out *RGWServiceSpec) {
 	*out = *in
@@ -3816,10 +5470,101 @@ func (in *SanitizeDisksSpec) DeepCopy() *SanitizeDisksSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScrubReport) DeepCopyInto(out *ScrubReport) {
+	*out = *in
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]PoolScrubReport, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScrubReport.
+func (in *ScrubReport) DeepCopy() *ScrubReport {
+	if in == nil {
+		return nil
+	}
+	out := new(ScrubReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScrubReportSpec) DeepCopyInto(out *ScrubReportSpec) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScrubReportSpec.
+func (in *ScrubReportSpec) DeepCopy() *ScrubReportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ScrubReportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScrubSpec) DeepCopyInto(out *ScrubSpec) {
+	*out = *in
+	if in.BeginHour != nil {
+		in, out := &in.BeginHour, &out.BeginHour
+		*out = new(int)
+		**out = **in
+	}
+	if in.EndHour != nil {
+		in, out := &in.EndHour, &out.EndHour
+		*out = new(int)
+		**out = **in
+	}
+	if in.BeginWeekDay != nil {
+		in, out := &in.BeginWeekDay, &out.BeginWeekDay
+		*out = new(int)
+		**out = **in
+	}
+	if in.EndWeekDay != nil {
+		in, out := &in.EndWeekDay, &out.EndWeekDay
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxScrubs != nil {
+		in, out := &in.MaxScrubs, &out.MaxScrubs
+		*out = new(int)
+		**out = **in
+	}
+	if in.DeepScrubInterval != nil {
+		in, out := &in.DeepScrubInterval, &out.DeepScrubInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScrubSpec.
+func (in *ScrubSpec) DeepCopy() *ScrubSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ScrubSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecuritySpec) DeepCopyInto(out *SecuritySpec) {
 	*out = *in
 	in.KeyManagementService.DeepCopyInto(&out.KeyManagementService)
+	out.KeyRotation = in.KeyRotation
+	in.DaemonHardening.DeepCopyInto(&out.DaemonHardening)
+	out.KeyringEncryption = in.KeyringEncryption
 	return
 }
 
@@ -3841,6 +5586,13 @@ func (in *Selection) DeepCopyInto(out *Selection) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.DeviceFilters != nil {
+		in, out := &in.DeviceFilters, &out.DeviceFilters
+		*out = make([]DeviceFilter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Devices != nil {
 		in, out := &in.Devices, &out.Devices
 		*out = make([]Device, len(*in))
@@ -4023,6 +5775,11 @@ func (in *StorageClassDeviceSet) DeepCopyInto(out *StorageClassDeviceSet) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Provider != nil {
+		in, out := &in.Provider, &out.Provider
+		*out = new(DeviceSetProviderSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -4061,6 +5818,32 @@ func (in *StorageScopeSpec) DeepCopyInto(out *StorageScopeSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	out.EphemeralDevices = in.EphemeralDevices
+	out.SwappedDevices = in.SwappedDevices
+	if in.FullRatio != nil {
+		in, out := &in.FullRatio, &out.FullRatio
+		*out = new(float64)
+		**out = **in
+	}
+	if in.BackfillFullRatio != nil {
+		in, out := &in.BackfillFullRatio, &out.BackfillFullRatio
+		*out = new(float64)
+		**out = **in
+	}
+	if in.NearFullRatio != nil {
+		in, out := &in.NearFullRatio, &out.NearFullRatio
+		*out = new(float64)
+		**out = **in
+	}
+	out.FullEmergency = in.FullEmergency
+	in.NodeOnboarding.DeepCopyInto(&out.NodeOnboarding)
+	if in.DriveGroups != nil {
+		in, out := &in.DriveGroups, &out.DriveGroups
+		*out = make([]DriveGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -4118,6 +5901,79 @@ func (in *StretchClusterZoneSpec) DeepCopy() *StretchClusterZoneSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwappedDevicesSpec) DeepCopyInto(out *SwappedDevicesSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwappedDevicesSpec.
+func (in *SwappedDevicesSpec) DeepCopy() *SwappedDevicesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SwappedDevicesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwiftSpec) DeepCopyInto(out *SwiftSpec) {
+	*out = *in
+	if in.UrlPrefix != nil {
+		in, out := &in.UrlPrefix, &out.UrlPrefix
+		*out = new(string)
+		**out = **in
+	}
+	if in.AccountInUrl != nil {
+		in, out := &in.AccountInUrl, &out.AccountInUrl
+		*out = new(bool)
+		**out = **in
+	}
+	if in.VersioningEnabled != nil {
+		in, out := &in.VersioningEnabled, &out.VersioningEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AuthEntry != nil {
+		in, out := &in.AuthEntry, &out.AuthEntry
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwiftSpec.
+func (in *SwiftSpec) DeepCopy() *SwiftSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SwiftSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TelemetrySpec) DeepCopyInto(out *TelemetrySpec) {
+	*out = *in
+	if in.Channels != nil {
+		in, out := &in.Channels, &out.Channels
+		*out = make([]TelemetryChannel, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TelemetrySpec.
+func (in *TelemetrySpec) DeepCopy() *TelemetrySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TelemetrySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TopicEndpointSpec) DeepCopyInto(out *TopicEndpointSpec) {
 	*out = *in
@@ -4149,6 +6005,47 @@ func (in *TopicEndpointSpec) DeepCopy() *TopicEndpointSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZoneGroupDefaultsSpec) DeepCopyInto(out *ZoneGroupDefaultsSpec) {
+	*out = *in
+	if in.MaxBuckets != nil {
+		in, out := &in.MaxBuckets, &out.MaxBuckets
+		*out = new(int)
+		**out = **in
+	}
+	if in.UserQuota != nil {
+		in, out := &in.UserQuota, &out.UserQuota
+		*out = new(ObjectQuotaDefaultsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BucketQuota != nil {
+		in, out := &in.BucketQuota, &out.BucketQuota
+		*out = new(ObjectQuotaDefaultsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UserRateLimit != nil {
+		in, out := &in.UserRateLimit, &out.UserRateLimit
+		*out = new(ObjectRateLimitSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BucketRateLimit != nil {
+		in, out := &in.BucketRateLimit, &out.BucketRateLimit
+		*out = new(ObjectRateLimitSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneGroupDefaultsSpec.
+func (in *ZoneGroupDefaultsSpec) DeepCopy() *ZoneGroupDefaultsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ZoneGroupDefaultsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ZoneSpec) DeepCopyInto(out *ZoneSpec) {
 	*out = *in
@@ -4164,3 +6061,60 @@ func (in *ZoneSpec) DeepCopy() *ZoneSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZoneMetadataSearchSpec) DeepCopyInto(out *ZoneMetadataSearchSpec) {
+	*out = *in
+	if in.NumShards != nil {
+		in, out := &in.NumShards, &out.NumShards
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NumReplicas != nil {
+		in, out := &in.NumReplicas, &out.NumReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ExplicitCustomMeta != nil {
+		in, out := &in.ExplicitCustomMeta, &out.ExplicitCustomMeta
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneMetadataSearchSpec.
+func (in *ZoneMetadataSearchSpec) DeepCopy() *ZoneMetadataSearchSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ZoneMetadataSearchSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZoneSyncModuleSpec) DeepCopyInto(out *ZoneSyncModuleSpec) {
+	*out = *in
+	if in.Cloud != nil {
+		in, out := &in.Cloud, &out.Cloud
+		*out = new(CloudSyncModuleSpec)
+		**out = **in
+	}
+	if in.PubSub != nil {
+		in, out := &in.PubSub, &out.PubSub
+		*out = new(PubSubSyncModuleSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneSyncModuleSpec.
+func (in *ZoneSyncModuleSpec) DeepCopy() *ZoneSyncModuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ZoneSyncModuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -30,6 +30,7 @@ type CephV1Interface interface {
 	CephBlockPoolRadosNamespacesGetter
 	CephBucketNotificationsGetter
 	CephBucketTopicsGetter
+	CephCOSIDriversGetter
 	CephClientsGetter
 	CephClustersGetter
 	CephFilesystemsGetter
@@ -41,6 +42,7 @@ type CephV1Interface interface {
 	CephObjectStoreUsersGetter
 	CephObjectZonesGetter
 	CephObjectZoneGroupsGetter
+	CephOperationsGetter
 	CephRBDMirrorsGetter
 }
 
@@ -65,6 +67,10 @@ func (c *CephV1Client) CephBucketTopics(namespace string) CephBucketTopicInterfa
 	return newCephBucketTopics(c, namespace)
 }
 
+func (c *CephV1Client) CephCOSIDrivers(namespace string) CephCOSIDriverInterface {
+	return newCephCOSIDrivers(c, namespace)
+}
+
 func (c *CephV1Client) CephClients(namespace string) CephClientInterface {
 	return newCephClients(c, namespace)
 }
@@ -109,6 +115,10 @@ func (c *CephV1Client) CephObjectZoneGroups(namespace string) CephObjectZoneGrou
 	return newCephObjectZoneGroups(c, namespace)
 }
 
+func (c *CephV1Client) CephOperations(namespace string) CephOperationInterface {
+	return newCephOperations(c, namespace)
+}
+
 func (c *CephV1Client) CephRBDMirrors(namespace string) CephRBDMirrorInterface {
 	return newCephRBDMirrors(c, namespace)
 }
@@ -44,6 +44,10 @@ func (c *FakeCephV1) CephBucketTopics(namespace string) v1.CephBucketTopicInterf
 	return &FakeCephBucketTopics{c, namespace}
 }
 
+func (c *FakeCephV1) CephCOSIDrivers(namespace string) v1.CephCOSIDriverInterface {
+	return &FakeCephCOSIDrivers{c, namespace}
+}
+
 func (c *FakeCephV1) CephClients(namespace string) v1.CephClientInterface {
 	return &FakeCephClients{c, namespace}
 }
@@ -88,6 +92,10 @@ func (c *FakeCephV1) CephObjectZoneGroups(namespace string) v1.CephObjectZoneGro
 	return &FakeCephObjectZoneGroups{c, namespace}
 }
 
+func (c *FakeCephV1) CephOperations(namespace string) v1.CephOperationInterface {
+	return &FakeCephOperations{c, namespace}
+}
+
 func (c *FakeCephV1) CephRBDMirrors(namespace string) v1.CephRBDMirrorInterface {
 	return &FakeCephRBDMirrors{c, namespace}
 }
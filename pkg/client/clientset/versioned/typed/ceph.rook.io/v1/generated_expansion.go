@@ -26,6 +26,8 @@ type CephBucketNotificationExpansion interface{}
 
 type CephBucketTopicExpansion interface{}
 
+type CephCOSIDriverExpansion interface{}
+
 type CephClientExpansion interface{}
 
 type CephClusterExpansion interface{}
@@ -48,4 +50,6 @@ type CephObjectZoneExpansion interface{}
 
 type CephObjectZoneGroupExpansion interface{}
 
+type CephOperationExpansion interface{}
+
 type CephRBDMirrorExpansion interface{}
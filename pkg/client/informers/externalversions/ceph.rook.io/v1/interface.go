@@ -32,6 +32,8 @@ type Interface interface {
 	CephBucketNotifications() CephBucketNotificationInformer
 	// CephBucketTopics returns a CephBucketTopicInformer.
 	CephBucketTopics() CephBucketTopicInformer
+	// CephCOSIDrivers returns a CephCOSIDriverInformer.
+	CephCOSIDrivers() CephCOSIDriverInformer
 	// CephClients returns a CephClientInformer.
 	CephClients() CephClientInformer
 	// CephClusters returns a CephClusterInformer.
@@ -54,6 +56,8 @@ type Interface interface {
 	CephObjectZones() CephObjectZoneInformer
 	// CephObjectZoneGroups returns a CephObjectZoneGroupInformer.
 	CephObjectZoneGroups() CephObjectZoneGroupInformer
+	// CephOperations returns a CephOperationInformer.
+	CephOperations() CephOperationInformer
 	// CephRBDMirrors returns a CephRBDMirrorInformer.
 	CephRBDMirrors() CephRBDMirrorInformer
 }
@@ -89,6 +93,11 @@ func (v *version) CephBucketTopics() CephBucketTopicInformer {
 	return &cephBucketTopicInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// CephCOSIDrivers returns a CephCOSIDriverInformer.
+func (v *version) CephCOSIDrivers() CephCOSIDriverInformer {
+	return &cephCOSIDriverInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // CephClients returns a CephClientInformer.
 func (v *version) CephClients() CephClientInformer {
 	return &cephClientInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -144,6 +153,11 @@ func (v *version) CephObjectZoneGroups() CephObjectZoneGroupInformer {
 	return &cephObjectZoneGroupInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// CephOperations returns a CephOperationInformer.
+func (v *version) CephOperations() CephOperationInformer {
+	return &cephOperationInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // CephRBDMirrors returns a CephRBDMirrorInformer.
 func (v *version) CephRBDMirrors() CephRBDMirrorInformer {
 	return &cephRBDMirrorInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -50,6 +50,14 @@ type CephBucketTopicListerExpansion interface{}
 // CephBucketTopicNamespaceLister.
 type CephBucketTopicNamespaceListerExpansion interface{}
 
+// CephCOSIDriverListerExpansion allows custom methods to be added to
+// CephCOSIDriverLister.
+type CephCOSIDriverListerExpansion interface{}
+
+// CephCOSIDriverNamespaceListerExpansion allows custom methods to be added to
+// CephCOSIDriverNamespaceLister.
+type CephCOSIDriverNamespaceListerExpansion interface{}
+
 // CephClientListerExpansion allows custom methods to be added to
 // CephClientLister.
 type CephClientListerExpansion interface{}
@@ -138,6 +146,14 @@ type CephObjectZoneGroupListerExpansion interface{}
 // CephObjectZoneGroupNamespaceLister.
 type CephObjectZoneGroupNamespaceListerExpansion interface{}
 
+// CephOperationListerExpansion allows custom methods to be added to
+// CephOperationLister.
+type CephOperationListerExpansion interface{}
+
+// CephOperationNamespaceListerExpansion allows custom methods to be added to
+// CephOperationNamespaceLister.
+type CephOperationNamespaceListerExpansion interface{}
+
 // CephRBDMirrorListerExpansion allows custom methods to be added to
 // CephRBDMirrorLister.
 type CephRBDMirrorListerExpansion interface{}
@@ -0,0 +1,93 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterd
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache holds the values that the controllers would otherwise load at every reconcile, such as the
+// cluster info and the ceph version, so that many resources reconciling at the same time do not query
+// the mons and the secrets of the cluster each time. The entries expire after a TTL and are invalidated
+// per namespace when the cluster changes. A nil cache is valid and never holds any entry.
+type Cache struct {
+	mutex   sync.Mutex
+	ttl     time.Duration
+	entries map[cacheKey]cacheEntry
+}
+
+type cacheKey struct {
+	namespace string
+	name      string
+}
+
+type cacheEntry struct {
+	value      interface{}
+	expiration time.Time
+}
+
+// NewCache returns a cache whose entries expire after the given TTL
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{ttl: ttl, entries: map[cacheKey]cacheEntry{}}
+}
+
+// Get returns the value cached for the namespace under the given name, and whether it was found
+func (c *Cache) Get(namespace, name string) (interface{}, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	key := cacheKey{namespace: namespace, name: name}
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expiration) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// Set caches a value for the namespace under the given name
+func (c *Cache) Set(namespace, name string, value interface{}) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries[cacheKey{namespace: namespace, name: name}] = cacheEntry{value: value, expiration: time.Now().Add(c.ttl)}
+}
+
+// Invalidate removes all the values cached for the namespace
+func (c *Cache) Invalidate(namespace string) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for key := range c.entries {
+		if key.namespace == namespace {
+			delete(c.entries, key)
+		}
+	}
+}
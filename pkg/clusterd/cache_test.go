@@ -0,0 +1,61 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	t.Run("nil cache", func(t *testing.T) {
+		var cache *Cache
+		cache.Set("rook-ceph", "version", "17.2.6")
+		_, ok := cache.Get("rook-ceph", "version")
+		assert.False(t, ok)
+		cache.Invalidate("rook-ceph")
+	})
+
+	t.Run("get and invalidate", func(t *testing.T) {
+		cache := NewCache(time.Minute)
+		cache.Set("rook-ceph", "version", "17.2.6")
+		cache.Set("other-ceph", "version", "18.2.0")
+
+		value, ok := cache.Get("rook-ceph", "version")
+		assert.True(t, ok)
+		assert.Equal(t, "17.2.6", value)
+		_, ok = cache.Get("rook-ceph", "clusterinfo")
+		assert.False(t, ok)
+
+		// only the values of the namespace are invalidated
+		cache.Invalidate("rook-ceph")
+		_, ok = cache.Get("rook-ceph", "version")
+		assert.False(t, ok)
+		value, ok = cache.Get("other-ceph", "version")
+		assert.True(t, ok)
+		assert.Equal(t, "18.2.0", value)
+	})
+
+	t.Run("expired entries", func(t *testing.T) {
+		cache := NewCache(-time.Second)
+		cache.Set("rook-ceph", "version", "17.2.6")
+		_, ok := cache.Get("rook-ceph", "version")
+		assert.False(t, ok)
+	})
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/rook/rook/pkg/util/sys"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -57,4 +58,11 @@ type Context struct {
 
 	// The local devices detected on the node
 	Devices []*sys.LocalDisk
+
+	// Cache of the cluster info and ceph versions shared by the reconciles of the controllers
+	Cache *Cache
+
+	// Recorder records the events of the operator on the resources that are not reconciled by the calling
+	// controller, e.g. the events of the daemons on the CephCluster. Nil until the controllers are started.
+	Recorder record.EventRecorder
 }
@@ -20,11 +20,14 @@ import (
 	"fmt"
 	"path"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rook/rook/pkg/clusterd"
 	"github.com/rook/rook/pkg/util/exec"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
 // RunAllCephCommandsInToolboxPod - when running the e2e tests, all ceph commands need to be run in the toolbox.
@@ -45,6 +48,8 @@ const (
 	Kubectl = "kubectl"
 	// CrushTool is the name of the CLI tool for 'crushtool'
 	CrushTool = "crushtool"
+	// OSDMapTool is the name of the CLI tool for 'osdmaptool'
+	OSDMapTool = "osdmaptool"
 	// GaneshaRadosGraceTool is the name of the CLI tool for 'ganesha-rados-grace'
 	GaneshaRadosGraceTool = "ganesha-rados-grace"
 	// DefaultPGCount will cause Ceph to use the internal default PG count
@@ -55,6 +60,31 @@ const (
 	ProxyAppLabel = "rook-ceph-mgr"
 )
 
+var cephCommandDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "rook_ceph_command_duration_seconds",
+	Help:    "Duration of the ceph, rbd and rados commands run by the operator",
+	Buckets: prometheus.ExponentialBuckets(0.05, 2, 12),
+}, []string{"tool", "command", "result"})
+
+func init() {
+	// the metrics are served by the metrics endpoint of the controller-runtime manager of the operator
+	metrics.Registry.MustRegister(cephCommandDuration)
+}
+
+// observeCommandDuration records the duration of a command. Only the first argument of the command
+// is kept, so that the names of the pools, images and daemons do not end up in the labels.
+func observeCommandDuration(tool string, args []string, err error, duration time.Duration) {
+	command := ""
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command = args[0]
+	}
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	cephCommandDuration.WithLabelValues(tool, command, result).Observe(duration.Seconds())
+}
+
 // CephConfFilePath returns the location to the cluster's config file in the operator container.
 func CephConfFilePath(configDir, clusterName string) string {
 	confFile := fmt.Sprintf("%s.config", clusterName)
@@ -92,11 +122,12 @@ func FinalizeCephCommandArgs(command string, clusterInfo *ClusterInfo, args []st
 		// ganesha-rados-grace does not accept any standard flags
 	default:
 		// Append the standard flags for config and keyring
-		keyringFile := fmt.Sprintf("%s.keyring", clusterInfo.CephCred.Username)
+		username := clusterInfo.Cred().Username
+		keyringFile := fmt.Sprintf("%s.keyring", username)
 		configArgs = []string{
 			fmt.Sprintf("--cluster=%s", clusterInfo.Namespace),
 			fmt.Sprintf("--conf=%s", cephConfPath),
-			fmt.Sprintf("--name=%s", clusterInfo.CephCred.Username),
+			fmt.Sprintf("--name=%s", username),
 			fmt.Sprintf("--keyring=%s", path.Join(configDir, clusterInfo.Namespace, keyringFile)),
 		}
 	}
@@ -202,6 +233,10 @@ func (c *CephToolCommand) run() ([]byte, error) {
 
 	var output, stderr string
 	var err error
+	start := time.Now()
+	defer func() {
+		observeCommandDuration(c.tool, c.args, err, time.Since(start))
+	}()
 
 	// NewRBDCommand does not use the --out-file option so we only check for remote execution here
 	// Still forcing the check for the command if the behavior changes in the future
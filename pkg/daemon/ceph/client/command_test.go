@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/rook/rook/pkg/clusterd"
 	"github.com/rook/rook/pkg/operator/test"
 	"github.com/rook/rook/pkg/util/exec"
@@ -228,3 +229,27 @@ func TestNewGaneshaRadosGraceCommand(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestCommandDurationMetric(t *testing.T) {
+	cephCommandDuration.Reset()
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "status" {
+				return "", errors.New("induced error")
+			}
+			return "", nil
+		},
+	}
+	context := &clusterd.Context{Executor: executor}
+	clusterInfo := AdminTestClusterInfo("rook-ceph")
+
+	_, err := NewCephCommand(context, clusterInfo, []string{"osd", "pool", "ls"}).Run()
+	assert.NoError(t, err)
+	_, err = NewCephCommand(context, clusterInfo, []string{"osd", "pool", "stats", "replicapool"}).Run()
+	assert.NoError(t, err)
+	_, err = NewCephCommand(context, clusterInfo, []string{"status"}).Run()
+	assert.Error(t, err)
+
+	// the commands are grouped by their first argument and their result
+	assert.Equal(t, 2, testutil.CollectAndCount(cephCommandDuration))
+}
@@ -47,6 +47,10 @@ const (
 	Msgr2port = 3300
 	// Msgr1port is the listening port of the messenger v1 protocol
 	Msgr1port = 6789
+	// MonDNSDiscoveryServiceName is the name of the headless service publishing the mons in DNS
+	MonDNSDiscoveryServiceName = "rook-ceph-mon-headless"
+	// MonDNSSrvPortName is the name of the port of the headless service looked up by Ceph in the SRV records
+	MonDNSSrvPortName = "ceph-mon"
 )
 
 var (
@@ -92,10 +96,11 @@ func GenerateConnectionConfig(context *clusterd.Context, cluster *ClusterInfo) (
 // some subset of settings.
 func GenerateConnectionConfigWithSettings(context *clusterd.Context, clusterInfo *ClusterInfo, settings *CephConfig) (string, error) {
 	root := path.Join(context.ConfigDir, clusterInfo.Namespace)
-	keyringPath := path.Join(root, fmt.Sprintf("%s.keyring", clusterInfo.CephCred.Username))
-	err := writeKeyring(CephKeyring(clusterInfo.CephCred), keyringPath)
+	cred := clusterInfo.Cred()
+	keyringPath := path.Join(root, fmt.Sprintf("%s.keyring", cred.Username))
+	err := writeKeyring(CephKeyring(cred), keyringPath)
 	if err != nil {
-		return "", errors.Wrapf(err, "failed to write keyring %q to %s", clusterInfo.CephCred.Username, root)
+		return "", errors.Wrapf(err, "failed to write keyring %q to %s", cred.Username, root)
 	}
 
 	filePath, err := generateConfigFile(context, clusterInfo, root, keyringPath, settings, nil)
@@ -123,7 +128,7 @@ func generateConfigFile(context *clusterd.Context, clusterInfo *ClusterInfo, pat
 		return "", errors.Wrapf(err, "failed to merge global config with %q", k8sutil.ConfigOverrideName)
 	}
 
-	qualifiedUser := getQualifiedUser(clusterInfo.CephCred.Username)
+	qualifiedUser := getQualifiedUser(clusterInfo.Cred().Username)
 	if err := addClientConfigFileSection(configFile, qualifiedUser, keyringPath, clientSettings); err != nil {
 		return "", errors.Wrap(err, "failed to add admin client config section")
 	}
@@ -272,6 +277,18 @@ func PopulateMonHostMembers(clusterInfo *ClusterInfo) ([]string, []string) {
 	return monMembers, monHosts
 }
 
+// MonDNSDiscoveryEndpoint returns the endpoint of the mons published by the headless service in the namespace.
+// The name resolves to the addresses of all the mons.
+func MonDNSDiscoveryEndpoint(namespace string, port int32) string {
+	return fmt.Sprintf("%s.%s.svc:%d", MonDNSDiscoveryServiceName, namespace, port)
+}
+
+// MonDNSSrvName returns the value of "mon_dns_srv_name" that lets Ceph clients without "mon_host" find the mons
+// published by the headless service in the namespace
+func MonDNSSrvName(namespace string) string {
+	return fmt.Sprintf("%s_%s.%s.svc", MonDNSSrvPortName, MonDNSDiscoveryServiceName, namespace)
+}
+
 // WriteCephConfig writes the ceph config so ceph commands can be executed
 func WriteCephConfig(context *clusterd.Context, clusterInfo *ClusterInfo) error {
 	// create the ceph.conf with the default settings
@@ -20,6 +20,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -261,3 +263,175 @@ func buildDecompileCRUSHFileName(crushMapPath string) string {
 func buildCompileCRUSHFileName(crushMapPath string) string {
 	return fmt.Sprintf("%s.compiled", crushMapPath)
 }
+
+// GetCrushTunablesProfile returns the profile of the CRUSH tunables, which is "unknown" if the tunables were set
+// individually
+func GetCrushTunablesProfile(context *clusterd.Context, clusterInfo *ClusterInfo) (string, error) {
+	args := []string{"osd", "crush", "show-tunables"}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get crush tunables. %s", string(buf))
+	}
+
+	var tunables struct {
+		Profile string `json:"profile"`
+	}
+	if err := json.Unmarshal(buf, &tunables); err != nil {
+		return "", errors.Wrap(err, "failed to unmarshal crush tunables")
+	}
+	return tunables.Profile, nil
+}
+
+// SetCrushTunablesProfile sets the profile of the CRUSH tunables
+func SetCrushTunablesProfile(context *clusterd.Context, clusterInfo *ClusterInfo, profile string) error {
+	args := []string{"osd", "crush", "tunables", profile}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to set crush tunables profile %q. %s", profile, string(buf))
+	}
+	return nil
+}
+
+// crushTunablesProfiles are the crushtool arguments setting the tunables of each profile that change the mapping of
+// the PGs, to the values set by "ceph osd crush tunables <profile>"
+var crushTunablesProfiles = func() map[string][]string {
+	tunables := func(localTries, localFallbackTries, totalTries, descendOnce, varyR, stable int) []string {
+		return []string{
+			"--set-choose-local-tries", strconv.Itoa(localTries),
+			"--set-choose-local-fallback-tries", strconv.Itoa(localFallbackTries),
+			"--set-choose-total-tries", strconv.Itoa(totalTries),
+			"--set-chooseleaf-descend-once", strconv.Itoa(descendOnce),
+			"--set-chooseleaf-vary-r", strconv.Itoa(varyR),
+			"--set-chooseleaf-stable", strconv.Itoa(stable),
+		}
+	}
+	argonaut := tunables(2, 5, 19, 0, 0, 0)
+	firefly := tunables(0, 0, 50, 1, 1, 0)
+	jewel := tunables(0, 0, 50, 1, 1, 1)
+	return map[string][]string{
+		"legacy":   argonaut,
+		"argonaut": argonaut,
+		"bobtail":  tunables(0, 0, 50, 1, 0, 0),
+		"firefly":  firefly,
+		// hammer only allows the straw2 buckets in addition to firefly
+		"hammer":  firefly,
+		"jewel":   jewel,
+		"optimal": jewel,
+		"default": jewel,
+	}
+}()
+
+// strawBucketAlg matches the algorithm of the straw buckets of a decompiled CRUSH map
+var strawBucketAlg = regexp.MustCompile(`(?m)^(\s*alg\s+)straw$`)
+
+// pgMappingLine matches the lines of "osdmaptool --test-map-pgs-dump", e.g. "1.1f	[2,0,1]	2"
+var pgMappingLine = regexp.MustCompile(`^([0-9]+\.[0-9a-f]+)\s+(\[[0-9,]*\])`)
+
+// CountCrushChangeRemappedPGs returns the number of PGs that a change of the CRUSH tunables profile, and the migration
+// of the straw buckets to straw2, would remap. The change is applied with crushtool to a copy of the CRUSH map
+// exported from the OSD map, and the PGs are mapped with osdmaptool, so the cluster is not changed.
+func CountCrushChangeRemappedPGs(context *clusterd.Context, clusterInfo *ClusterInfo, tunablesProfile string, migrateToStraw2 bool) (int, error) {
+	dir, err := os.MkdirTemp("", "crush-preview")
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to create directory for the crush preview")
+	}
+	defer os.RemoveAll(dir)
+
+	osdMapPath := filepath.Join(dir, "osdmap")
+	getMap := NewCephCommand(context, clusterInfo, []string{"osd", "getmap", "--out-file", osdMapPath})
+	getMap.JsonOutput = false
+	if buf, err := getMap.Run(); err != nil {
+		return 0, errors.Wrapf(err, "failed to get the osd map. %s", string(buf))
+	}
+	crushMapPath := filepath.Join(dir, "crushmap")
+	if output, err := context.Executor.ExecuteCommandWithOutput(OSDMapTool, osdMapPath, "--export-crush", crushMapPath); err != nil {
+		return 0, errors.Wrapf(err, "failed to export the crush map of the osd map. %s", output)
+	}
+
+	if tunablesProfile != "" {
+		tunables, ok := crushTunablesProfiles[tunablesProfile]
+		if !ok {
+			return 0, errors.Errorf("unknown crush tunables profile %q", tunablesProfile)
+		}
+		newCrushMapPath := crushMapPath + ".tunables"
+		args := append([]string{"-i", crushMapPath}, tunables...)
+		args = append(args, "-o", newCrushMapPath)
+		if output, err := context.Executor.ExecuteCommandWithOutput(CrushTool, args...); err != nil {
+			return 0, errors.Wrapf(err, "failed to set the tunables of profile %q. %s", tunablesProfile, output)
+		}
+		crushMapPath = newCrushMapPath
+	}
+	if migrateToStraw2 {
+		if err := decompileCRUSHMap(context, crushMapPath); err != nil {
+			return 0, err
+		}
+		decompiledPath := buildDecompileCRUSHFileName(crushMapPath)
+		decompiled, err := os.ReadFile(filepath.Clean(decompiledPath))
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to read the decompiled crush map")
+		}
+		if err := os.WriteFile(decompiledPath, strawBucketAlg.ReplaceAll(decompiled, []byte("${1}straw2")), 0600); err != nil {
+			return 0, errors.Wrap(err, "failed to write the decompiled crush map")
+		}
+		if err := compileCRUSHMap(context, decompiledPath); err != nil {
+			return 0, err
+		}
+		crushMapPath = buildCompileCRUSHFileName(decompiledPath)
+	}
+
+	before, err := mapPGs(context, osdMapPath)
+	if err != nil {
+		return 0, err
+	}
+	if output, err := context.Executor.ExecuteCommandWithOutput(OSDMapTool, osdMapPath, "--import-crush", crushMapPath); err != nil {
+		return 0, errors.Wrapf(err, "failed to import the changed crush map in the osd map. %s", output)
+	}
+	after, err := mapPGs(context, osdMapPath)
+	if err != nil {
+		return 0, err
+	}
+
+	remapped := 0
+	for pgID, osds := range after {
+		if before[pgID] != osds {
+			remapped++
+		}
+	}
+	return remapped, nil
+}
+
+// mapPGs returns the OSDs the PGs are mapped to by an OSD map
+func mapPGs(context *clusterd.Context, osdMapPath string) (map[string]string, error) {
+	output, err := context.Executor.ExecuteCommandWithOutput(OSDMapTool, osdMapPath, "--test-map-pgs-dump")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to map the pgs of the osd map. %s", output)
+	}
+	mappings := map[string]string{}
+	for _, line := range strings.Split(output, "\n") {
+		if match := pgMappingLine.FindStringSubmatch(line); match != nil {
+			mappings[match[1]] = match[2]
+		}
+	}
+	return mappings, nil
+}
+
+// StrawBuckets returns the names of the buckets of the CRUSH map that use the legacy straw algorithm
+func (m *CrushMap) StrawBuckets() []string {
+	buckets := []string{}
+	for _, bucket := range m.Buckets {
+		if bucket.Alg == "straw" {
+			buckets = append(buckets, bucket.Name)
+		}
+	}
+	return buckets
+}
+
+// SetAllStrawBucketsToStraw2 converts all the straw buckets of the CRUSH map to straw2
+func SetAllStrawBucketsToStraw2(context *clusterd.Context, clusterInfo *ClusterInfo) error {
+	args := []string{"osd", "crush", "set-all-straw-buckets-to-straw2"}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to convert straw buckets to straw2. %s", string(buf))
+	}
+	return nil
+}
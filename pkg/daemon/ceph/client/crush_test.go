@@ -17,6 +17,8 @@ package client
 
 import (
 	"fmt"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/pkg/errors"
@@ -335,3 +337,63 @@ func TestBuildCompiledDecompileCRUSHFileName(t *testing.T) {
 	assert.Equal(t, "/tmp/06399022.decompiled", buildDecompileCRUSHFileName("/tmp/06399022"))
 	assert.Equal(t, "/tmp/06399022.compiled", buildCompileCRUSHFileName("/tmp/06399022"))
 }
+
+func TestCountCrushChangeRemappedPGs(t *testing.T) {
+	newExecutor := func(commands *[]string, decompiled *string) *exectest.MockExecutor {
+		imported := false
+		return &exectest.MockExecutor{
+			MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+				*commands = append(*commands, command+" "+strings.Join(args, " "))
+				switch {
+				case command == "crushtool" && args[0] == "--decompile":
+					return "", os.WriteFile(args[3], []byte("host a {\n\talg straw\n}\n"), 0600)
+				case command == "crushtool" && args[0] == "--compile":
+					content, err := os.ReadFile(args[1])
+					*decompiled = string(content)
+					return "", err
+				case command == "osdmaptool" && args[1] == "--import-crush":
+					imported = true
+				case command == "osdmaptool" && args[1] == "--test-map-pgs-dump":
+					if imported {
+						return "pool 1 pg_num 4\n1.0\t[0,1,2]\t0\n1.1\t[2,1,3]\t2\n1.2\t[1,0,2]\t1\n1.3\t[0,3,1]\t0\n", nil
+					}
+					return "pool 1 pg_num 4\n1.0\t[0,1,2]\t0\n1.1\t[2,1,0]\t2\n1.2\t[1,0,3]\t1\n1.3\t[0,3,1]\t0\n", nil
+				}
+				return "", nil
+			},
+		}
+	}
+
+	t.Run("tunables profile", func(t *testing.T) {
+		commands := []string{}
+		decompiled := ""
+		context := &clusterd.Context{Executor: newExecutor(&commands, &decompiled)}
+		remapped, err := CountCrushChangeRemappedPGs(context, AdminTestClusterInfo("mycluster"), "optimal", false)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, remapped)
+		all := strings.Join(commands, ";")
+		assert.Contains(t, all, "osd getmap --out-file")
+		assert.Contains(t, all, "--set-chooseleaf-stable 1")
+		assert.NotContains(t, all, "--decompile")
+		assert.NotContains(t, all, "osd crush tunables")
+	})
+
+	t.Run("straw2 migration", func(t *testing.T) {
+		commands := []string{}
+		decompiled := ""
+		context := &clusterd.Context{Executor: newExecutor(&commands, &decompiled)}
+		remapped, err := CountCrushChangeRemappedPGs(context, AdminTestClusterInfo("mycluster"), "", true)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, remapped)
+		assert.Equal(t, "host a {\n\talg straw2\n}\n", decompiled)
+		assert.NotContains(t, strings.Join(commands, ";"), "--set-choose")
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		commands := []string{}
+		decompiled := ""
+		context := &clusterd.Context{Executor: newExecutor(&commands, &decompiled)}
+		_, err := CountCrushChangeRemappedPGs(context, AdminTestClusterInfo("mycluster"), "unknown", false)
+		assert.Error(t, err)
+	})
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"sync"
 	"testing"
 	"time"
 
@@ -56,6 +57,9 @@ type ClusterInfo struct {
 	// Whereas if passed through clusterInfo, we don't have that problem since clusterInfo is
 	// re-hydrated when a context is cancelled.
 	Context context.Context
+	// credMutex guards the CephCred while it is updated on a cluster info in use, e.g. after the imported
+	// credentials of an external cluster are rotated
+	credMutex sync.RWMutex
 }
 
 // MonInfo is a collection of information about a Ceph mon.
@@ -82,6 +86,42 @@ func (c *ClusterInfo) SetName(name string) {
 	c.name = name
 }
 
+// Cred returns the credentials used to connect to the cluster
+func (c *ClusterInfo) Cred() CephCred {
+	c.credMutex.RLock()
+	defer c.credMutex.RUnlock()
+	return c.CephCred
+}
+
+// SetCred updates the credentials used to connect to the cluster while the cluster info may be in use
+func (c *ClusterInfo) SetCred(cred CephCred) {
+	c.credMutex.Lock()
+	defer c.credMutex.Unlock()
+	c.CephCred = cred
+}
+
+// Copy returns a copy of the cluster info that does not share its mons with the original
+func (c *ClusterInfo) Copy() *ClusterInfo {
+	clusterInfoCopy := &ClusterInfo{
+		FSID:              c.FSID,
+		MonitorSecret:     c.MonitorSecret,
+		CephCred:          c.Cred(),
+		Monitors:          make(map[string]*MonInfo, len(c.Monitors)),
+		CephVersion:       c.CephVersion,
+		Namespace:         c.Namespace,
+		OwnerInfo:         c.OwnerInfo,
+		name:              c.name,
+		OsdUpgradeTimeout: c.OsdUpgradeTimeout,
+		NetworkSpec:       c.NetworkSpec,
+		Context:           c.Context,
+	}
+	for name, mon := range c.Monitors {
+		monCopy := *mon
+		clusterInfoCopy.Monitors[name] = &monCopy
+	}
+	return clusterInfoCopy
+}
+
 func (c *ClusterInfo) NamespacedName() types.NamespacedName {
 	if c.name == "" {
 		panic("name is not set on the clusterInfo")
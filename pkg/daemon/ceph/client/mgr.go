@@ -64,6 +64,16 @@ func CephMgrStat(context *clusterd.Context, clusterInfo *ClusterInfo) (*MgrStat,
 	return &mgrStat, nil
 }
 
+// MgrFail fails over the active mgr with the name to a standby mgr, or restarts it if there is no standby
+func MgrFail(context *clusterd.Context, clusterInfo *ClusterInfo, name string) error {
+	args := []string{"mgr", "fail", name}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to fail over mgr %q. %s", name, string(buf))
+	}
+	return nil
+}
+
 // MgrEnableModule enables a mgr module
 func MgrEnableModule(context *clusterd.Context, clusterInfo *ClusterInfo, name string, force bool) error {
 	retryCount := 5
@@ -73,6 +73,36 @@ type MonDumpEntry struct {
 	CrushLocation string `json:"crush_location"`
 }
 
+// TimeSyncStatus represents the response from a time-sync-status mon_command
+type TimeSyncStatus struct {
+	TimeSkewStatus map[string]MonTimeSkew `json:"time_skew_status"`
+}
+
+// MonTimeSkew represents the skew of the clock of a mon from the leader mon, in seconds
+type MonTimeSkew struct {
+	Skew    float64 `json:"skew"`
+	Latency float64 `json:"latency"`
+	Health  string  `json:"health"`
+}
+
+// GetTimeSyncStatus calls time-sync-status mon_command
+func GetTimeSyncStatus(context *clusterd.Context, clusterInfo *ClusterInfo) (TimeSyncStatus, error) {
+	args := []string{"time-sync-status"}
+	cmd := NewCephCommand(context, clusterInfo, args)
+	buf, err := cmd.Run()
+	if err != nil {
+		return TimeSyncStatus{}, errors.Wrap(err, "time sync status failed")
+	}
+
+	var resp TimeSyncStatus
+	err = json.Unmarshal(buf, &resp)
+	if err != nil {
+		return TimeSyncStatus{}, errors.Wrapf(err, "unmarshal failed. raw buffer response: %s", buf)
+	}
+
+	return resp, nil
+}
+
 // GetMonQuorumStatus calls quorum_status mon_command
 func GetMonQuorumStatus(context *clusterd.Context, clusterInfo *ClusterInfo) (MonStatusResponse, error) {
 	args := []string{"quorum_status"}
@@ -152,3 +152,22 @@ func TestMonDump(t *testing.T) {
 	assert.Equal(t, 3, len(dump.Mons))
 	assert.Equal(t, 3, len(dump.Quorum))
 }
+
+func TestGetTimeSyncStatus(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		logger.Infof("Command: %s %v", command, args)
+		if args[0] == "time-sync-status" {
+			return `{"time_skew_status":{"a":{"skew":0,"latency":0,"health":"HEALTH_OK"},"b":{"skew":-0.031,"latency":0.0004,"health":"HEALTH_OK"}},"timechecks":{"epoch":6,"round":48,"round_status":"finished"}}`, nil
+		}
+		return "", errors.Errorf("unexpected ceph command %q", args)
+	}
+	context := &clusterd.Context{Executor: executor}
+	clusterInfo := AdminTestClusterInfo("mycluster")
+
+	status, err := GetTimeSyncStatus(context, clusterInfo)
+	assert.NoError(t, err)
+	assert.Len(t, status.TimeSkewStatus, 2)
+	assert.Equal(t, -0.031, status.TimeSkewStatus["b"].Skew)
+	assert.Equal(t, "HEALTH_OK", status.TimeSkewStatus["b"].Health)
+}
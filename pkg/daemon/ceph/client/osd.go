@@ -65,8 +65,16 @@ type OSDDump struct {
 		Up  json.Number `json:"up"`
 		In  json.Number `json:"in"`
 	} `json:"osds"`
-	Flags          string              `json:"flags"`
-	CrushNodeFlags map[string][]string `json:"crush_node_flags"`
+	Flags             string              `json:"flags"`
+	CrushNodeFlags    map[string][]string `json:"crush_node_flags"`
+	FullRatio         float64             `json:"full_ratio"`
+	BackfillFullRatio float64             `json:"backfillfull_ratio"`
+	NearFullRatio     float64             `json:"nearfull_ratio"`
+	Pools             []struct {
+		Name            string `json:"pool_name"`
+		PgAutoscaleMode string `json:"pg_autoscale_mode"`
+		FlagsNames      string `json:"flags_names"`
+	} `json:"pools"`
 }
 
 // IsFlagSet checks if an OSD flag is set
@@ -253,6 +261,16 @@ func OSDOut(context *clusterd.Context, clusterInfo *ClusterInfo, osdID int) (str
 	return string(buf), err
 }
 
+// PurgeOSD removes the OSD from the CRUSH map, deletes its auth key and removes it from the OSD map
+func PurgeOSD(context *clusterd.Context, clusterInfo *ClusterInfo, osdID int) error {
+	args := []string{"osd", "purge", strconv.Itoa(osdID), "--force", "--yes-i-really-mean-it"}
+	_, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to purge osd.%d", osdID)
+	}
+	return nil
+}
+
 func OsdSafeToDestroy(context *clusterd.Context, clusterInfo *ClusterInfo, osdID int) (bool, error) {
 	args := []string{"osd", "safe-to-destroy", strconv.Itoa(osdID)}
 	cmd := NewCephCommand(context, clusterInfo, args)
@@ -271,6 +289,31 @@ func OsdSafeToDestroy(context *clusterd.Context, clusterInfo *ClusterInfo, osdID
 	return false, nil
 }
 
+// OSDDevicePaths returns the persistent paths of the devices of an OSD recorded in its metadata, e.g.
+// "/dev/disk/by-path/pci-0000:03:00.0-scsi-0:0:1:0", which identify the slots of the devices
+func OSDDevicePaths(context *clusterd.Context, clusterInfo *ClusterInfo, osdID int) ([]string, error) {
+	args := []string{"osd", "metadata", strconv.Itoa(osdID)}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the metadata of osd.%d", osdID)
+	}
+
+	var metadata struct {
+		// e.g. "sdb=/dev/disk/by-path/pci-0000:03:00.0-scsi-0:0:1:0,sdc=..."
+		DevicePaths string `json:"device_paths"`
+	}
+	if err := json.Unmarshal(buf, &metadata); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal the metadata of osd.%d. %s", osdID, string(buf))
+	}
+	paths := []string{}
+	for _, entry := range strings.Split(metadata.DevicePaths, ",") {
+		if _, path, ok := strings.Cut(entry, "="); ok && path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
 // HostTree returns the osd tree
 func HostTree(context *clusterd.Context, clusterInfo *ClusterInfo) (OsdTree, error) {
 	var output OsdTree
@@ -383,3 +426,37 @@ func SetPrimaryAffinity(context *clusterd.Context, clusterInfo *ClusterInfo, osd
 	logger.Infof("successfully applied osd.%d primary-affinity %q", osdID, affinity)
 	return nil
 }
+
+// SetOSDFlag sets the specified cluster-wide osd flag, e.g. "norebalance"
+func SetOSDFlag(context *clusterd.Context, clusterInfo *ClusterInfo, flag string) error {
+	args := []string{"osd", "set", flag}
+	cmd := NewCephCommand(context, clusterInfo, args)
+	_, err := cmd.Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to set osd flag %s", flag)
+	}
+	return nil
+}
+
+// SetOSDRatio sets the ratio of used space of the OSDs at which they are considered full, backfillfull or
+// nearfull. The ratio name is "full", "backfillfull" or "nearfull".
+func SetOSDRatio(context *clusterd.Context, clusterInfo *ClusterInfo, ratioName string, ratio float64) error {
+	args := []string{"osd", fmt.Sprintf("set-%s-ratio", ratioName), strconv.FormatFloat(ratio, 'f', -1, 64)}
+	cmd := NewCephCommand(context, clusterInfo, args)
+	_, err := cmd.Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to set osd %s ratio to %v", ratioName, ratio)
+	}
+	return nil
+}
+
+// UnsetOSDFlag unsets the specified cluster-wide osd flag
+func UnsetOSDFlag(context *clusterd.Context, clusterInfo *ClusterInfo, flag string) error {
+	args := []string{"osd", "unset", flag}
+	cmd := NewCephCommand(context, clusterInfo, args)
+	_, err := cmd.Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to unset osd flag %s", flag)
+	}
+	return nil
+}
@@ -141,6 +141,32 @@ func TestOSDDeviceClasses(t *testing.T) {
 	})
 }
 
+func TestOSDDevicePaths(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		if args[0] == "osd" && args[1] == "metadata" && args[2] == "1" {
+			return `{"id": 1, "devices": "sdb,sdc", "device_paths": "sdb=/dev/disk/by-path/pci-0000:03:00.0-scsi-0:0:1:0,sdc=/dev/disk/by-path/pci-0000:03:00.0-scsi-0:0:2:0"}`, nil
+		}
+		if args[0] == "osd" && args[1] == "metadata" && args[2] == "2" {
+			return `{"id": 2}`, nil
+		}
+		return "", errors.Errorf("unexpected ceph command %q", args)
+	}
+	context := &clusterd.Context{Executor: executor}
+	clusterInfo := AdminTestClusterInfo("mycluster")
+
+	paths, err := OSDDevicePaths(context, clusterInfo, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/dev/disk/by-path/pci-0000:03:00.0-scsi-0:0:1:0", "/dev/disk/by-path/pci-0000:03:00.0-scsi-0:0:2:0"}, paths)
+
+	paths, err = OSDDevicePaths(context, clusterInfo, 2)
+	assert.NoError(t, err)
+	assert.Empty(t, paths)
+
+	_, err = OSDDevicePaths(context, clusterInfo, 3)
+	assert.Error(t, err)
+}
+
 func TestOSDOkToStop(t *testing.T) {
 	returnString := ""
 	returnOkResult := true
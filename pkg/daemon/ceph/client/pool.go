@@ -28,6 +28,7 @@ import (
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/clusterd"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
@@ -37,6 +38,11 @@ const (
 	CompressionModeProperty = "compression_mode"
 	PgAutoscaleModeProperty = "pg_autoscale_mode"
 	PgAutoscaleModeOn       = "on"
+
+	scrubMinIntervalProperty  = "scrub_min_interval"
+	scrubMaxIntervalProperty  = "scrub_max_interval"
+	deepScrubIntervalProperty = "deep_scrub_interval"
+	scrubPriorityProperty     = "scrub_priority"
 )
 
 type CephStoragePoolSummary struct {
@@ -73,6 +79,15 @@ type CephStoragePoolStats struct {
 			WriteBytes   float64 `json:"wr_bytes"`
 		} `json:"stats"`
 	} `json:"pools"`
+	Stats        CephStorageUsage            `json:"stats"`
+	StatsByClass map[string]CephStorageUsage `json:"stats_by_class"`
+}
+
+// CephStorageUsage is the raw capacity of the cluster or of a device class
+type CephStorageUsage struct {
+	TotalBytes        uint64 `json:"total_bytes"`
+	TotalAvailBytes   uint64 `json:"total_avail_bytes"`
+	TotalUsedRawBytes uint64 `json:"total_used_raw_bytes"`
 }
 
 type PoolStatistics struct {
@@ -186,6 +201,9 @@ func CreatePoolWithPGs(context *clusterd.Context, clusterInfo *ClusterInfo, clus
 	if pool.Name == "" {
 		return errors.New("pool name must be specified")
 	}
+	if err := CheckUnsafePool(context, clusterInfo, clusterSpec, pool); err != nil {
+		return err
+	}
 	if pool.IsReplicated() {
 		return createReplicatedPoolForApp(context, clusterInfo, clusterSpec, pool, pgCount, appName)
 	}
@@ -280,6 +298,28 @@ func givePoolAppTag(context *clusterd.Context, clusterInfo *ClusterInfo, poolNam
 	return nil
 }
 
+// scrubPoolProperties returns the pool properties of the scrub settings that are set. The intervals are in seconds.
+func scrubPoolProperties(scrub *cephv1.PoolScrubSpec) map[string]string {
+	properties := map[string]string{}
+	if scrub == nil {
+		return properties
+	}
+	intervals := map[string]*metav1.Duration{
+		scrubMinIntervalProperty:  scrub.MinInterval,
+		scrubMaxIntervalProperty:  scrub.MaxInterval,
+		deepScrubIntervalProperty: scrub.DeepInterval,
+	}
+	for propName, interval := range intervals {
+		if interval != nil {
+			properties[propName] = strconv.FormatInt(int64(interval.Duration.Seconds()), 10)
+		}
+	}
+	if scrub.Priority != nil {
+		properties[scrubPriorityProperty] = strconv.Itoa(*scrub.Priority)
+	}
+	return properties
+}
+
 func setCommonPoolProperties(context *clusterd.Context, clusterInfo *ClusterInfo, pool cephv1.NamedPoolSpec, appName string) error {
 	if len(pool.Parameters) == 0 {
 		pool.Parameters = make(map[string]string)
@@ -293,6 +333,10 @@ func setCommonPoolProperties(context *clusterd.Context, clusterInfo *ClusterInfo
 		pool.Parameters[CompressionModeProperty] = pool.CompressionMode
 	}
 
+	for propName, propValue := range scrubPoolProperties(pool.Scrub) {
+		pool.Parameters[propName] = propValue
+	}
+
 	// Apply properties
 	for propName, propValue := range pool.Parameters {
 		err := SetPoolProperty(context, clusterInfo, pool.Name, propName, propValue)
@@ -774,6 +818,71 @@ func GetPoolStatistics(context *clusterd.Context, clusterInfo *ClusterInfo, name
 	return &poolStats, nil
 }
 
+// CheckUnsafePool returns an error if the pool keeps a single copy of the data and does not exist yet, unless such
+// pools are allowed in the cluster spec. The existing pools are only reported with a warning so that they keep working.
+func CheckUnsafePool(context *clusterd.Context, clusterInfo *ClusterInfo, clusterSpec *cephv1.ClusterSpec, pool cephv1.NamedPoolSpec) error {
+	if !pool.IsUnsafe() || clusterSpec.AllowUnsafePools {
+		return nil
+	}
+
+	pools, err := ListPoolSummaries(context, clusterInfo)
+	if err != nil {
+		return errors.Wrapf(err, "failed to check whether unsafe pool %q exists", pool.Name)
+	}
+	for _, p := range pools {
+		if p.Name == pool.Name {
+			logger.Warningf("pool %q keeps a single copy of the data (replicated size 1 or erasure coded without coding chunks) "+
+				"and loses data if any OSD is lost. set allowUnsafePools in the CephCluster spec to acknowledge it", pool.Name)
+			return nil
+		}
+	}
+	return errors.Errorf("pool %q would keep a single copy of the data (replicated size 1 or erasure coded without coding chunks), "+
+		"which is not allowed unless allowUnsafePools is set in the CephCluster spec", pool.Name)
+}
+
+// GetUnsafePools returns the names of the pools that keep a single copy of the data, i.e. the
+// replicated pools of size 1 and the erasure coded pools without coding chunks
+func GetUnsafePools(context *clusterd.Context, clusterInfo *ClusterInfo) ([]string, error) {
+	args := []string{"osd", "pool", "ls", "detail"}
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list pool details")
+	}
+
+	var pools []struct {
+		Name               string `json:"pool_name"`
+		Size               uint   `json:"size"`
+		ErasureCodeProfile string `json:"erasure_code_profile"`
+	}
+	if err := json.Unmarshal(output, &pools); err != nil {
+		return nil, errors.Wrapf(err, "unmarshal failed raw buffer response %s", string(output))
+	}
+
+	unsafePools := []string{}
+	ecProfiles := map[string]CephErasureCodeProfile{}
+	for _, pool := range pools {
+		if pool.ErasureCodeProfile == "" {
+			if pool.Size == 1 {
+				unsafePools = append(unsafePools, pool.Name)
+			}
+			continue
+		}
+		profile, ok := ecProfiles[pool.ErasureCodeProfile]
+		if !ok {
+			profile, err = GetErasureCodeProfileDetails(context, clusterInfo, pool.ErasureCodeProfile)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get erasure code profile of pool %q", pool.Name)
+			}
+			ecProfiles[pool.ErasureCodeProfile] = profile
+		}
+		if profile.CodingChunkCount == 0 {
+			unsafePools = append(unsafePools, pool.Name)
+		}
+	}
+
+	return unsafePools, nil
+}
+
 func crushRuleExists(crushMap CrushMap, ruleName string) bool {
 	// Check if the crush rule already exists
 	for _, rule := range crushMap.Rules {
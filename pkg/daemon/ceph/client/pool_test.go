@@ -21,12 +21,14 @@ import (
 	"reflect"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/clusterd"
 	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const emptyApplicationName = `{"":{}}`
@@ -608,3 +610,44 @@ func hasCrushtool() bool {
 	_, err := exec.LookPath("crushtool")
 	return err == nil
 }
+
+func TestGetUnsafePools(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	context := &clusterd.Context{Executor: executor}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		logger.Infof("Command: %s %v", command, args)
+		if args[0] == "osd" && args[1] == "pool" && args[2] == "ls" && args[3] == "detail" {
+			return `[{"pool_name":"replicapool","size":3,"erasure_code_profile":""},
+				{"pool_name":"single","size":1,"erasure_code_profile":""},
+				{"pool_name":"ecpool","size":3,"erasure_code_profile":"ecpool_ecprofile"},
+				{"pool_name":"ecnocoding","size":2,"erasure_code_profile":"ecnocoding_ecprofile"}]`, nil
+		}
+		if args[0] == "osd" && args[1] == "erasure-code-profile" && args[2] == "get" {
+			if args[3] == "ecpool_ecprofile" {
+				return `{"k":"2","m":"1","plugin":"jerasure"}`, nil
+			}
+			return `{"k":"2","m":"0","plugin":"jerasure"}`, nil
+		}
+		return "", errors.Errorf("unexpected ceph command %q", args)
+	}
+
+	pools, err := GetUnsafePools(context, AdminTestClusterInfo("mycluster"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"single", "ecnocoding"}, pools)
+}
+
+func TestScrubPoolProperties(t *testing.T) {
+	assert.Empty(t, scrubPoolProperties(nil))
+
+	priority := 1
+	scrub := &cephv1.PoolScrubSpec{
+		MaxInterval:  &metav1.Duration{Duration: 14 * 24 * time.Hour},
+		DeepInterval: &metav1.Duration{Duration: 30 * 24 * time.Hour},
+		Priority:     &priority,
+	}
+	assert.Equal(t, map[string]string{
+		"scrub_max_interval":  "1209600",
+		"deep_scrub_interval": "2592000",
+		"scrub_priority":      "1",
+	}, scrubPoolProperties(scrub))
+}
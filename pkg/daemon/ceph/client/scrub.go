@@ -0,0 +1,94 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+)
+
+// the layouts of the scrub stamps of the placement groups, the layout changed in pacific
+var pgStampLayouts = []string{"2006-01-02T15:04:05.999999-0700", "2006-01-02 15:04:05.999999"}
+
+const pgZeroStamp = "0.000000"
+
+// PGScrubStats is the scrub state of a placement group
+type PGScrubStats struct {
+	PGID               string `json:"pgid"`
+	State              string `json:"state"`
+	LastScrubStamp     string `json:"last_scrub_stamp"`
+	LastDeepScrubStamp string `json:"last_deep_scrub_stamp"`
+	StatSum            struct {
+		NumScrubErrors     int `json:"num_scrub_errors"`
+		NumObjectsRepaired int `json:"num_objects_repaired"`
+	} `json:"stat_sum"`
+}
+
+// GetPGScrubStats returns the scrub state of all the placement groups of the cluster
+func GetPGScrubStats(context *clusterd.Context, clusterInfo *ClusterInfo) ([]PGScrubStats, error) {
+	args := []string{"pg", "ls"}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list pgs. %s", string(buf))
+	}
+
+	var pgs struct {
+		PGStats []PGScrubStats `json:"pg_stats"`
+	}
+	if err := json.Unmarshal(buf, &pgs); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal pgs")
+	}
+	return pgs.PGStats, nil
+}
+
+// PoolID returns the id of the pool of the placement group
+func (pg *PGScrubStats) PoolID() (int, error) {
+	pool, _, found := strings.Cut(pg.PGID, ".")
+	if !found {
+		return 0, errors.Errorf("invalid pg id %q", pg.PGID)
+	}
+	return strconv.Atoi(pool)
+}
+
+// IsInconsistent returns whether a scrub found an inconsistency in the placement group that is not repaired yet
+func (pg *PGScrubStats) IsInconsistent() bool {
+	for _, state := range strings.Split(pg.State, "+") {
+		if state == "inconsistent" {
+			return true
+		}
+	}
+	return false
+}
+
+// ParsePGStamp parses a scrub stamp of a placement group. The zero stamp of the placement groups that were never
+// scrubbed is the epoch.
+func ParsePGStamp(stamp string) (time.Time, error) {
+	if stamp == pgZeroStamp {
+		return time.Unix(0, 0).UTC(), nil
+	}
+	for _, layout := range pgStampLayouts {
+		if t, err := time.Parse(layout, stamp); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, errors.Errorf("invalid pg stamp %q", stamp)
+}
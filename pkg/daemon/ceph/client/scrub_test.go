@@ -0,0 +1,77 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPGScrubStats(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		logger.Infof("Command: %s %v", command, args)
+		if args[0] == "pg" && args[1] == "ls" {
+			return `{"pg_ready":true,"pg_stats":[{"pgid":"12.1f","state":"active+clean+inconsistent",
+				"last_scrub_stamp":"2023-06-01T10:00:00.123456+0000","last_deep_scrub_stamp":"2023-05-28T08:30:00.000000+0200",
+				"stat_sum":{"num_objects":10,"num_scrub_errors":2,"num_objects_repaired":1}}]}`, nil
+		}
+		return "", errors.Errorf("unexpected ceph command %q", args)
+	}
+
+	pgs, err := GetPGScrubStats(&clusterd.Context{Executor: executor}, AdminTestClusterInfo("mycluster"))
+	assert.NoError(t, err)
+	require.Len(t, pgs, 1)
+	pg := pgs[0]
+	poolID, err := pg.PoolID()
+	assert.NoError(t, err)
+	assert.Equal(t, 12, poolID)
+	assert.True(t, pg.IsInconsistent())
+	assert.Equal(t, 2, pg.StatSum.NumScrubErrors)
+	assert.Equal(t, 1, pg.StatSum.NumObjectsRepaired)
+
+	stamp, err := ParsePGStamp(pg.LastDeepScrubStamp)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 5, 28, 6, 30, 0, 0, time.UTC), stamp)
+}
+
+func TestPGScrubStats(t *testing.T) {
+	pg := PGScrubStats{PGID: "1.0", State: "active+clean+scrubbing+deep"}
+	assert.False(t, pg.IsInconsistent())
+
+	pg.PGID = "invalid"
+	_, err := pg.PoolID()
+	assert.Error(t, err)
+
+	// the layout of the stamps before pacific
+	stamp, err := ParsePGStamp("2021-03-01 10:00:00.500000")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2021, 3, 1, 10, 0, 0, 500000000, time.UTC), stamp)
+
+	stamp, err = ParsePGStamp("0.000000")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), stamp.Unix())
+
+	_, err = ParsePGStamp("yesterday")
+	assert.Error(t, err)
+}
@@ -18,6 +18,7 @@ package client
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/rook/rook/pkg/clusterd"
@@ -62,6 +63,8 @@ type HealthStatus struct {
 type CheckMessage struct {
 	Severity string  `json:"severity"`
 	Summary  Summary `json:"summary"`
+	// Detail is only reported by the health detail
+	Detail []Summary `json:"detail,omitempty"`
 }
 
 type Summary struct {
@@ -91,6 +94,8 @@ type MgrMap struct {
 	ActiveAddr string       `json:"active_addr"`
 	Available  bool         `json:"available"`
 	Standbys   []MgrStandby `json:"standbys"`
+	// Services are the URLs of the services of the mgr modules by module, e.g. prometheus
+	Services map[string]string `json:"services,omitempty"`
 }
 
 type MgrStandby struct {
@@ -186,6 +191,22 @@ func StatusWithUser(context *clusterd.Context, clusterInfo *ClusterInfo) (CephSt
 	return status, nil
 }
 
+// HealthDetail returns the health of the cluster with the detail of the health checks
+func HealthDetail(context *clusterd.Context, clusterInfo *ClusterInfo) (HealthStatus, error) {
+	args := []string{"health", "detail"}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return HealthStatus{}, errors.Wrapf(err, "failed to get health detail. %s", string(buf))
+	}
+
+	var health HealthStatus
+	if err := json.Unmarshal(buf, &health); err != nil {
+		return HealthStatus{}, errors.Wrap(err, "failed to unmarshal health detail response")
+	}
+
+	return health, nil
+}
+
 // IsClusterClean returns msg (string), clean (bool), err (error)
 // msg describes the state of the PGs
 // clean is true if the cluster is clean
@@ -238,6 +259,33 @@ func isClusterClean(status CephStatus) (string, bool) {
 	return fmt.Sprintf("cluster is not fully clean. PGs: %+v", status.PgMap.PgsByState), false
 }
 
+// IsRecoveryInProgress returns true if any PGs are recovering or backfilling
+func IsRecoveryInProgress(status CephStatus) bool {
+	for _, pg := range status.PgMap.PgsByState {
+		if pg.Count == 0 {
+			continue
+		}
+		if strings.Contains(pg.StateName, "recovering") || strings.Contains(pg.StateName, "backfilling") {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPeeringInProgress returns true if any PGs are not mapped to their osds yet, e.g. after a change of the
+// CRUSH map
+func IsPeeringInProgress(status CephStatus) bool {
+	for _, pg := range status.PgMap.PgsByState {
+		if pg.Count == 0 {
+			continue
+		}
+		if strings.Contains(pg.StateName, "peering") || strings.Contains(pg.StateName, "activating") || strings.Contains(pg.StateName, "unknown") {
+			return true
+		}
+	}
+	return false
+}
+
 // getMDSRank returns the rank of a given MDS
 func getMDSRank(status CephStatus, fsName string) (int, error) {
 	// dummy rank
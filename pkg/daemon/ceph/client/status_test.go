@@ -110,6 +110,28 @@ func TestIsClusterClean(t *testing.T) {
 	assert.False(t, clean)
 }
 
+func TestIsRecoveryInProgress(t *testing.T) {
+	status := CephStatus{
+		PgMap: PgMap{
+			PgsByState: []PgStateEntry{
+				{StateName: activeClean, Count: 3},
+				{StateName: "active+undersized+degraded", Count: 2},
+			},
+			NumPgs: 5,
+		},
+	}
+	assert.False(t, IsRecoveryInProgress(status))
+
+	status.PgMap.PgsByState[1].StateName = "active+undersized+degraded+remapped+backfilling"
+	assert.True(t, IsRecoveryInProgress(status))
+
+	status.PgMap.PgsByState[1].StateName = "active+recovering+degraded"
+	assert.True(t, IsRecoveryInProgress(status))
+
+	status.PgMap.PgsByState[1].Count = 0
+	assert.False(t, IsRecoveryInProgress(status))
+}
+
 func TestGetMDSRank(t *testing.T) {
 	var statusFake CephStatus
 	err := json.Unmarshal(statusFakeRaw, &statusFake)
@@ -0,0 +1,93 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+)
+
+// crushItemNone is the osd id of a missing shard of an erasure coded PG
+const crushItemNone = 2147483647
+
+// PGMapping is the up and acting sets of a placement group
+type PGMapping struct {
+	PGID   string `json:"pgid"`
+	Up     []int  `json:"up"`
+	Acting []int  `json:"acting"`
+}
+
+// GetRemappedPGs returns the placement groups whose up set differs from their acting set, i.e. the PGs whose
+// data is moving or waiting to move to other OSDs
+func GetRemappedPGs(context *clusterd.Context, clusterInfo *ClusterInfo) ([]PGMapping, error) {
+	args := []string{"pg", "ls", "remapped"}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list remapped pgs. %s", string(buf))
+	}
+
+	var pgs struct {
+		PGStats []PGMapping `json:"pg_stats"`
+	}
+	if err := json.Unmarshal(buf, &pgs); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal remapped pgs")
+	}
+	return pgs.PGStats, nil
+}
+
+// upmapItems returns the pairs of osds that map the up set of the PG back to its acting set. Only the positions
+// where an osd is replaced by an osd outside of the up set are mapped, since upmaps cannot swap osds of the set.
+func (pg *PGMapping) upmapItems() []string {
+	items := []string{}
+	if len(pg.Up) != len(pg.Acting) {
+		return items
+	}
+	inSet := func(set []int, osd int) bool {
+		for _, id := range set {
+			if id == osd {
+				return true
+			}
+		}
+		return false
+	}
+	for i := range pg.Up {
+		from, to := pg.Up[i], pg.Acting[i]
+		if from == to || to == crushItemNone || from == crushItemNone || inSet(pg.Acting, from) || inSet(pg.Up, to) {
+			continue
+		}
+		items = append(items, strconv.Itoa(from), strconv.Itoa(to))
+	}
+	return items
+}
+
+// PinPGToActingSet adds an upmap that maps the PG back to the osds of its acting set, so that its data stays where
+// it is until the balancer removes the upmap. It returns false if the PG cannot be mapped back.
+func PinPGToActingSet(context *clusterd.Context, clusterInfo *ClusterInfo, pg PGMapping) (bool, error) {
+	items := pg.upmapItems()
+	if len(items) == 0 {
+		return false, nil
+	}
+	args := append([]string{"osd", "pg-upmap-items", pg.PGID}, items...)
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to pin pg %q to its acting set. %s", pg.PGID, string(buf))
+	}
+	return true, nil
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpmapItems(t *testing.T) {
+	// a replaced osd is mapped back
+	pg := PGMapping{PGID: "1.0", Up: []int{0, 1, 2}, Acting: []int{0, 1, 3}}
+	assert.Equal(t, []string{"2", "3"}, pg.upmapItems())
+
+	// the order of the osds changed, which cannot be mapped back
+	pg = PGMapping{PGID: "1.1", Up: []int{1, 2, 4}, Acting: []int{2, 1, 4}}
+	assert.Empty(t, pg.upmapItems())
+
+	// a missing shard of an erasure coded pg is not mapped
+	pg = PGMapping{PGID: "2.0", Up: []int{0, 5, 2}, Acting: []int{0, crushItemNone, 3}}
+	assert.Equal(t, []string{"2", "3"}, pg.upmapItems())
+
+	// the size of the pg is changing
+	pg = PGMapping{PGID: "1.2", Up: []int{0, 1, 2}, Acting: []int{0, 1}}
+	assert.Empty(t, pg.upmapItems())
+}
+
+func TestGetRemappedPGs(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		logger.Infof("Command: %s %v", command, args)
+		if args[0] == "pg" && args[1] == "ls" && args[2] == "remapped" {
+			return `{"pg_ready":true,"pg_stats":[{"pgid":"1.0","state":"active+remapped+backfill_wait","up":[0,1,2],"acting":[0,1,3]}]}`, nil
+		}
+		return "", errors.Errorf("unexpected ceph command %q", args)
+	}
+
+	pgs, err := GetRemappedPGs(&clusterd.Context{Executor: executor}, AdminTestClusterInfo("mycluster"))
+	assert.NoError(t, err)
+	assert.Len(t, pgs, 1)
+	assert.Equal(t, "1.0", pgs[0].PGID)
+	assert.Equal(t, []int{0, 1, 2}, pgs[0].Up)
+	assert.Equal(t, []int{0, 1, 3}, pgs[0].Acting)
+}
+
+func TestPinPGToActingSet(t *testing.T) {
+	commands := []string{}
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		commands = append(commands, strings.Join(args, " "))
+		return "", nil
+	}
+	context := &clusterd.Context{Executor: executor}
+	clusterInfo := AdminTestClusterInfo("mycluster")
+
+	pinned, err := PinPGToActingSet(context, clusterInfo, PGMapping{PGID: "1.0", Up: []int{0, 1, 2}, Acting: []int{0, 1, 3}})
+	assert.NoError(t, err)
+	assert.True(t, pinned)
+	assert.Len(t, commands, 1)
+	assert.True(t, strings.HasPrefix(commands[0], "osd pg-upmap-items 1.0 2 3"))
+
+	pinned, err = PinPGToActingSet(context, clusterInfo, PGMapping{PGID: "1.1", Up: []int{1, 2}, Acting: []int{2, 1}})
+	assert.NoError(t, err)
+	assert.False(t, pinned)
+	assert.Len(t, commands, 1)
+}
@@ -0,0 +1,364 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package externalbundle generates the resources needed by another Kubernetes cluster to consume a Rook cluster
+// in external mode.
+package externalbundle
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+)
+
+var logger = capnslog.NewPackageLogger("github.com/rook/rook", "external-bundle")
+
+const (
+	// HealthCheckerUser is the user of the operator of the consumer cluster
+	HealthCheckerUser = "client.healthchecker"
+
+	csiRBDNodeUser          = "client.csi-rbd-node"
+	csiRBDProvisionerUser   = "client.csi-rbd-provisioner"
+	csiCephFSNodeUser       = "client.csi-cephfs-node"
+	csiCephFSProvisionerUsr = "client.csi-cephfs-provisioner"
+
+	defaultRGWPoolPrefix          = "default"
+	defaultMonitoringEndpointPort = "9283"
+)
+
+// Config is the configuration of the bundle
+type Config struct {
+	// Namespace is the namespace of the CephCluster of the consumer cluster
+	Namespace string
+	// ConsumerName is the name of the consumer cluster, added to the names of the restricted users
+	ConsumerName string
+	// RBDPool is the pool of the rbd volumes of the consumer
+	RBDPool string
+	// RBDMetadataECPool is the replicated pool of the metadata of the rbd volumes when RBDPool is erasure coded
+	RBDMetadataECPool string
+	// CephFSFilesystem is the filesystem of the cephfs volumes of the consumer
+	CephFSFilesystem string
+	// CephFSDataPool is the data pool of the cephfs volumes of the consumer
+	CephFSDataPool string
+	// RGWEndpoint is the endpoint of the object store of the consumer, as <host>:<port>
+	RGWEndpoint string
+	// RGWPoolPrefix is the prefix of the pools of the object store
+	RGWPoolPrefix string
+	// RestrictedAuth restricts the csi users to the pool and the filesystem of the consumer
+	RestrictedAuth bool
+}
+
+// Resource is a resource created in the consumer cluster, in the format of the output of the
+// create-external-cluster-resources.py script
+type Resource struct {
+	Name string            `json:"name"`
+	Kind string            `json:"kind"`
+	Data map[string]string `json:"data"`
+}
+
+// Bundle is the connection information of the consumer cluster
+type Bundle struct {
+	// Variables are the environment variables read by the import-external-cluster.sh script
+	Variables map[string]string
+	// Resources are the resources to create in the consumer cluster
+	Resources []Resource
+}
+
+type cephUser struct {
+	entity string
+	caps   []string
+}
+
+// Generate creates or gets the users of the consumer cluster and returns its connection bundle
+func Generate(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, config Config) (*Bundle, error) {
+	if err := validateConfig(&config); err != nil {
+		return nil, err
+	}
+
+	status, err := cephclient.Status(context, clusterInfo)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the status of the cluster")
+	}
+	monData, err := monEndpoints(context, clusterInfo)
+	if err != nil {
+		return nil, err
+	}
+	mgrMap, err := cephclient.CephMgrMap(context, clusterInfo)
+	if err != nil {
+		return nil, err
+	}
+	monitoringEndpoint, monitoringPort, err := monitoringEndpoint(mgrMap)
+	if err != nil {
+		return nil, err
+	}
+
+	healthChecker := healthCheckerUser(config)
+	healthCheckerKey, err := cephclient.AuthGetOrCreateKey(context, clusterInfo, healthChecker.entity, healthChecker.caps)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the key of user %q", healthChecker.entity)
+	}
+
+	vars := map[string]string{
+		"NAMESPACE":                    config.Namespace,
+		"ROOK_EXTERNAL_FSID":           status.FSID,
+		"ROOK_EXTERNAL_USERNAME":       healthChecker.entity,
+		"ROOK_EXTERNAL_USER_SECRET":    healthCheckerKey,
+		"ROOK_EXTERNAL_CEPH_MON_DATA":  monData,
+		"MONITORING_ENDPOINT":          monitoringEndpoint,
+		"MONITORING_ENDPOINT_PORT":     monitoringPort,
+		"RBD_POOL_NAME":                config.RBDPool,
+		"RBD_METADATA_EC_POOL_NAME":    config.RBDMetadataECPool,
+		"CEPHFS_FS_NAME":               config.CephFSFilesystem,
+		"CEPHFS_POOL_NAME":             config.CephFSDataPool,
+		"RGW_ENDPOINT":                 config.RGWEndpoint,
+		"RGW_POOL_PREFIX":              config.RGWPoolPrefix,
+		"ROOK_EXTERNAL_DASHBOARD_LINK": mgrMap.Services["dashboard"],
+	}
+
+	users := map[string]cephUser{}
+	if config.RBDPool != "" {
+		users["CSI_RBD_NODE_SECRET"] = rbdNodeUser(config)
+		users["CSI_RBD_PROVISIONER_SECRET"] = rbdProvisionerUser(config)
+	}
+	if config.CephFSFilesystem != "" {
+		users["CSI_CEPHFS_NODE_SECRET"] = cephFSNodeUser(config)
+		users["CSI_CEPHFS_PROVISIONER_SECRET"] = cephFSProvisionerUser(config)
+	}
+	for variable, user := range users {
+		key, err := cephclient.AuthGetOrCreateKey(context, clusterInfo, user.entity, user.caps)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the key of user %q", user.entity)
+		}
+		vars[variable] = key
+		// the names of the secrets are the names of the users without the "client." prefix
+		vars[variable+"_NAME"] = strings.TrimPrefix(user.entity, "client.")
+	}
+
+	return &Bundle{Variables: vars, Resources: resources(vars)}, nil
+}
+
+// Exports returns the variables of the bundle as the exports of a shell script
+func (b *Bundle) Exports() string {
+	names := []string{}
+	for name, value := range b.Variables {
+		if value != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	var exports strings.Builder
+	for _, name := range names {
+		exports.WriteString(fmt.Sprintf("export %s=%s\n", name, b.Variables[name]))
+	}
+	return exports.String()
+}
+
+func validateConfig(config *Config) error {
+	if config.Namespace == "" {
+		return errors.New("the namespace of the consumer cluster is required")
+	}
+	if config.ConsumerName == "" {
+		config.ConsumerName = config.Namespace
+	}
+	if config.RGWPoolPrefix == "" {
+		config.RGWPoolPrefix = defaultRGWPoolPrefix
+	}
+	if config.CephFSFilesystem != "" && config.CephFSDataPool == "" {
+		return errors.Errorf("the data pool of filesystem %q is required", config.CephFSFilesystem)
+	}
+	if config.RestrictedAuth && config.RBDPool == "" && config.CephFSFilesystem == "" {
+		return errors.New("the restricted users require the rbd pool or the cephfs filesystem")
+	}
+	if config.RGWEndpoint != "" {
+		if _, _, err := net.SplitHostPort(config.RGWEndpoint); err != nil {
+			return errors.Wrapf(err, "invalid rgw endpoint %q, expected <host>:<port>", config.RGWEndpoint)
+		}
+	}
+	return nil
+}
+
+// monEndpoints returns the endpoints of the mons in quorum in the format of the rook-ceph-mon-endpoints configmap
+func monEndpoints(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo) (string, error) {
+	quorum, err := cephclient.GetMonQuorumStatus(context, clusterInfo)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get the mons in quorum")
+	}
+	inQuorum := map[int]bool{}
+	for _, rank := range quorum.Quorum {
+		inQuorum[rank] = true
+	}
+	endpoints := []string{}
+	for _, mon := range quorum.MonMap.Mons {
+		if !inQuorum[mon.Rank] {
+			continue
+		}
+		// the address is followed by the nonce, e.g. 10.0.0.1:6789/0
+		endpoints = append(endpoints, fmt.Sprintf("%s=%s", mon.Name, strings.Split(mon.PublicAddr, "/")[0]))
+	}
+	if len(endpoints) == 0 {
+		return "", errors.New("no mons in quorum")
+	}
+	return strings.Join(endpoints, ","), nil
+}
+
+// monitoringEndpoint returns the address of the active mgr and the port of its prometheus module
+func monitoringEndpoint(mgrMap *cephclient.MgrMap) (string, string, error) {
+	if mgrMap.ActiveAddr == "" {
+		return "", "", errors.New("no active mgr")
+	}
+	// the address of the mgr is followed by the nonce, e.g. 10.0.0.1:6800/123
+	host, _, err := net.SplitHostPort(strings.Split(mgrMap.ActiveAddr, "/")[0])
+	if err != nil {
+		return "", "", errors.Wrapf(err, "invalid address %q of the active mgr", mgrMap.ActiveAddr)
+	}
+	port := defaultMonitoringEndpointPort
+	if prometheus, ok := mgrMap.Services["prometheus"]; ok {
+		if u, err := url.Parse(prometheus); err == nil && u.Port() != "" {
+			port = u.Port()
+		}
+	} else {
+		logger.Warning("the prometheus module of the mgr is not enabled, the consumer cluster will not collect the metrics")
+	}
+	return host, port, nil
+}
+
+func healthCheckerUser(config Config) cephUser {
+	prefix := config.RGWPoolPrefix
+	return cephUser{entity: HealthCheckerUser, caps: []string{
+		"mon", "allow r, allow command quorum_status, allow command version",
+		"mgr", "allow command config",
+		"osd", fmt.Sprintf("allow rwx pool=%[1]s.rgw.meta, allow r pool=.rgw.root, allow rw pool=%[1]s.rgw.control, "+
+			"allow rx pool=%[1]s.rgw.log, allow x pool=%[1]s.rgw.buckets.index", prefix),
+	}}
+}
+
+func rbdNodeUser(config Config) cephUser {
+	user := cephUser{entity: csiRBDNodeUser, caps: []string{"mon", "profile rbd, allow command 'osd blocklist'", "osd", "profile rbd"}}
+	if config.RestrictedAuth {
+		user.entity = fmt.Sprintf("%s-%s-%s", csiRBDNodeUser, config.ConsumerName, config.RBDPool)
+		user.caps[3] = fmt.Sprintf("profile rbd pool=%s", config.RBDPool)
+	}
+	return user
+}
+
+func rbdProvisionerUser(config Config) cephUser {
+	user := cephUser{entity: csiRBDProvisionerUser, caps: []string{
+		"mon", "profile rbd, allow command 'osd blocklist'", "mgr", "allow rw", "osd", "profile rbd",
+	}}
+	if config.RestrictedAuth {
+		user.entity = fmt.Sprintf("%s-%s-%s", csiRBDProvisionerUser, config.ConsumerName, config.RBDPool)
+		user.caps[5] = fmt.Sprintf("profile rbd pool=%s", config.RBDPool)
+	}
+	return user
+}
+
+func cephFSNodeUser(config Config) cephUser {
+	user := cephUser{entity: csiCephFSNodeUser, caps: []string{
+		"mon", "allow r, allow command 'osd blocklist'", "mgr", "allow rw", "osd", "allow rw tag cephfs *=*", "mds", "allow rw",
+	}}
+	if config.RestrictedAuth {
+		user.entity = fmt.Sprintf("%s-%s-%s", csiCephFSNodeUser, config.ConsumerName, config.CephFSFilesystem)
+		user.caps[5] = fmt.Sprintf("allow rw tag cephfs *=%s", config.CephFSFilesystem)
+	}
+	return user
+}
+
+func cephFSProvisionerUser(config Config) cephUser {
+	user := cephUser{entity: csiCephFSProvisionerUsr, caps: []string{
+		"mon", "allow r, allow command 'osd blocklist'", "mgr", "allow rw", "osd", "allow rw tag cephfs metadata=*",
+	}}
+	if config.RestrictedAuth {
+		user.entity = fmt.Sprintf("%s-%s-%s", csiCephFSProvisionerUsr, config.ConsumerName, config.CephFSFilesystem)
+		user.caps[5] = fmt.Sprintf("allow rw tag cephfs metadata=%s", config.CephFSFilesystem)
+	}
+	return user
+}
+
+// resources returns the resources of the consumer cluster from the variables of the bundle
+func resources(vars map[string]string) []Resource {
+	resources := []Resource{
+		{Name: "rook-ceph-mon-endpoints", Kind: "ConfigMap", Data: map[string]string{
+			"data": vars["ROOK_EXTERNAL_CEPH_MON_DATA"], "maxMonId": "0", "mapping": "{}",
+		}},
+		{Name: "rook-ceph-mon", Kind: "Secret", Data: map[string]string{
+			"admin-secret": "admin-secret", "fsid": vars["ROOK_EXTERNAL_FSID"], "mon-secret": "mon-secret",
+		}},
+		{Name: "rook-ceph-operator-creds", Kind: "Secret", Data: map[string]string{
+			"userID": vars["ROOK_EXTERNAL_USERNAME"], "userKey": vars["ROOK_EXTERNAL_USER_SECRET"],
+		}},
+		{Name: "monitoring-endpoint", Kind: "CephCluster", Data: map[string]string{
+			"MonitoringEndpoint": vars["MONITORING_ENDPOINT"], "MonitoringPort": vars["MONITORING_ENDPOINT_PORT"],
+		}},
+	}
+
+	csiSecrets := []struct {
+		variable, idKey, keyKey string
+	}{
+		{"CSI_RBD_NODE_SECRET", "userID", "userKey"},
+		{"CSI_RBD_PROVISIONER_SECRET", "userID", "userKey"},
+		{"CSI_CEPHFS_PROVISIONER_SECRET", "adminID", "adminKey"},
+		{"CSI_CEPHFS_NODE_SECRET", "adminID", "adminKey"},
+	}
+	for _, secret := range csiSecrets {
+		if vars[secret.variable] == "" {
+			continue
+		}
+		resources = append(resources, Resource{Name: "rook-" + vars[secret.variable+"_NAME"], Kind: "Secret", Data: map[string]string{
+			secret.idKey: vars[secret.variable+"_NAME"], secret.keyKey: vars[secret.variable],
+		}})
+	}
+	if vars["ROOK_EXTERNAL_DASHBOARD_LINK"] != "" {
+		resources = append(resources, Resource{Name: "rook-ceph-dashboard-link", Kind: "Secret", Data: map[string]string{
+			"userID": "ceph-dashboard-link", "userKey": vars["ROOK_EXTERNAL_DASHBOARD_LINK"],
+		}})
+	}
+
+	if vars["RBD_POOL_NAME"] != "" {
+		data := map[string]string{
+			"pool": vars["RBD_POOL_NAME"],
+			"csi.storage.k8s.io/provisioner-secret-name":       "rook-" + vars["CSI_RBD_PROVISIONER_SECRET_NAME"],
+			"csi.storage.k8s.io/controller-expand-secret-name": "rook-" + vars["CSI_RBD_PROVISIONER_SECRET_NAME"],
+			"csi.storage.k8s.io/node-stage-secret-name":        "rook-" + vars["CSI_RBD_NODE_SECRET_NAME"],
+		}
+		if vars["RBD_METADATA_EC_POOL_NAME"] != "" {
+			data["dataPool"] = vars["RBD_POOL_NAME"]
+			data["pool"] = vars["RBD_METADATA_EC_POOL_NAME"]
+		}
+		resources = append(resources, Resource{Name: "ceph-rbd", Kind: "StorageClass", Data: data})
+	}
+	if vars["CEPHFS_FS_NAME"] != "" {
+		resources = append(resources, Resource{Name: "cephfs", Kind: "StorageClass", Data: map[string]string{
+			"fsName": vars["CEPHFS_FS_NAME"],
+			"pool":   vars["CEPHFS_POOL_NAME"],
+			"csi.storage.k8s.io/provisioner-secret-name":       "rook-" + vars["CSI_CEPHFS_PROVISIONER_SECRET_NAME"],
+			"csi.storage.k8s.io/controller-expand-secret-name": "rook-" + vars["CSI_CEPHFS_PROVISIONER_SECRET_NAME"],
+			"csi.storage.k8s.io/node-stage-secret-name":        "rook-" + vars["CSI_CEPHFS_NODE_SECRET_NAME"],
+		}})
+	}
+	if vars["RGW_ENDPOINT"] != "" {
+		resources = append(resources, Resource{Name: "ceph-rgw", Kind: "StorageClass", Data: map[string]string{
+			"endpoint": vars["RGW_ENDPOINT"], "poolPrefix": vars["RGW_POOL_PREFIX"],
+		}})
+	}
+	return resources
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalbundle
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestContext(createdUsers map[string][]string) *clusterd.Context {
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		switch {
+		case args[0] == "status":
+			return `{"fsid":"6a1b9e4c-2a52-4d4d-8a8e-0a4a4d1e2f3c"}`, nil
+		case args[0] == "quorum_status":
+			return `{"quorum":[0,1],"monmap":{"mons":[
+				{"name":"a","rank":0,"public_addr":"10.0.0.1:6789/0"},
+				{"name":"b","rank":1,"public_addr":"10.0.0.2:6789/0"},
+				{"name":"c","rank":2,"public_addr":"10.0.0.3:6789/0"}]}}`, nil
+		case args[0] == "mgr" && args[1] == "dump":
+			return `{"active_addr":"10.0.0.4:6800/123","services":{"prometheus":"http://10.0.0.4:9284/"}}`, nil
+		case args[0] == "auth" && args[1] == "get-or-create-key":
+			caps := []string{}
+			for _, arg := range args[3:] {
+				if strings.HasPrefix(arg, "--") {
+					break
+				}
+				caps = append(caps, arg)
+			}
+			createdUsers[args[2]] = caps
+			return `{"key":"key-` + args[2] + `"}`, nil
+		}
+		return "", errors.Errorf("unexpected command %v", args)
+	}
+	return &clusterd.Context{Executor: executor}
+}
+
+func findResource(resources []Resource, name string) *Resource {
+	for i := range resources {
+		if resources[i].Name == name {
+			return &resources[i]
+		}
+	}
+	return nil
+}
+
+func TestGenerate(t *testing.T) {
+	clusterInfo := cephclient.AdminTestClusterInfo("rook-ceph")
+
+	t.Run("rbd only", func(t *testing.T) {
+		users := map[string][]string{}
+		bundle, err := Generate(newTestContext(users), clusterInfo, Config{Namespace: "rook-ceph-external", RBDPool: "replicapool"})
+		assert.NoError(t, err)
+		assert.Len(t, users, 3)
+		assert.Contains(t, users, HealthCheckerUser)
+		assert.Equal(t, []string{"mon", "profile rbd, allow command 'osd blocklist'", "osd", "profile rbd"}, users["client.csi-rbd-node"])
+
+		vars := bundle.Variables
+		assert.Equal(t, "6a1b9e4c-2a52-4d4d-8a8e-0a4a4d1e2f3c", vars["ROOK_EXTERNAL_FSID"])
+		assert.Equal(t, "a=10.0.0.1:6789,b=10.0.0.2:6789", vars["ROOK_EXTERNAL_CEPH_MON_DATA"])
+		assert.Equal(t, "10.0.0.4", vars["MONITORING_ENDPOINT"])
+		assert.Equal(t, "9284", vars["MONITORING_ENDPOINT_PORT"])
+		assert.Equal(t, "csi-rbd-node", vars["CSI_RBD_NODE_SECRET_NAME"])
+		assert.Equal(t, "key-client.csi-rbd-node", vars["CSI_RBD_NODE_SECRET"])
+		assert.NotContains(t, vars, "CSI_CEPHFS_NODE_SECRET")
+
+		secret := findResource(bundle.Resources, "rook-csi-rbd-provisioner")
+		assert.NotNil(t, secret)
+		assert.Equal(t, map[string]string{"userID": "csi-rbd-provisioner", "userKey": "key-client.csi-rbd-provisioner"}, secret.Data)
+		storageClass := findResource(bundle.Resources, "ceph-rbd")
+		assert.NotNil(t, storageClass)
+		assert.Equal(t, "replicapool", storageClass.Data["pool"])
+		assert.Nil(t, findResource(bundle.Resources, "cephfs"))
+		assert.Nil(t, findResource(bundle.Resources, "ceph-rgw"))
+
+		exports := bundle.Exports()
+		assert.Contains(t, exports, "export NAMESPACE=rook-ceph-external\n")
+		assert.Contains(t, exports, "export RBD_POOL_NAME=replicapool\n")
+		assert.NotContains(t, exports, "CEPHFS_FS_NAME")
+	})
+
+	t.Run("restricted users", func(t *testing.T) {
+		users := map[string][]string{}
+		config := Config{Namespace: "rook-ceph-external", ConsumerName: "k8s2", RBDPool: "replicapool",
+			CephFSFilesystem: "myfs", CephFSDataPool: "myfs-replicated", RestrictedAuth: true}
+		bundle, err := Generate(newTestContext(users), clusterInfo, config)
+		assert.NoError(t, err)
+		assert.Len(t, users, 5)
+		assert.Equal(t, "profile rbd pool=replicapool", users["client.csi-rbd-provisioner-k8s2-replicapool"][5])
+		assert.Equal(t, "allow rw tag cephfs *=myfs", users["client.csi-cephfs-node-k8s2-myfs"][5])
+		assert.Equal(t, "csi-cephfs-provisioner-k8s2-myfs", bundle.Variables["CSI_CEPHFS_PROVISIONER_SECRET_NAME"])
+
+		secret := findResource(bundle.Resources, "rook-csi-cephfs-node-k8s2-myfs")
+		assert.NotNil(t, secret)
+		assert.Equal(t, "csi-cephfs-node-k8s2-myfs", secret.Data["adminID"])
+		storageClass := findResource(bundle.Resources, "cephfs")
+		assert.NotNil(t, storageClass)
+		assert.Equal(t, "rook-csi-cephfs-node-k8s2-myfs", storageClass.Data["csi.storage.k8s.io/node-stage-secret-name"])
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := Generate(newTestContext(map[string][]string{}), clusterInfo, Config{})
+		assert.Error(t, err)
+		_, err = Generate(newTestContext(map[string][]string{}), clusterInfo, Config{Namespace: "ns", CephFSFilesystem: "myfs"})
+		assert.Error(t, err)
+		_, err = Generate(newTestContext(map[string][]string{}), clusterInfo, Config{Namespace: "ns", RGWEndpoint: "10.0.0.5"})
+		assert.Error(t, err)
+		_, err = Generate(newTestContext(map[string][]string{}), clusterInfo, Config{Namespace: "ns", RestrictedAuth: true})
+		assert.Error(t, err)
+	})
+}
+
+func TestMonitoringEndpoint(t *testing.T) {
+	host, port, err := monitoringEndpoint(&cephclient.MgrMap{ActiveAddr: "10.0.0.4:6800/123"})
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.4", host)
+	assert.Equal(t, "9283", port)
+
+	_, _, err = monitoringEndpoint(&cephclient.MgrMap{})
+	assert.Error(t, err)
+}
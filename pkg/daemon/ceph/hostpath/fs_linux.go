@@ -0,0 +1,31 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostpath
+
+import "syscall"
+
+// the filesystem type of overlayfs returned by statfs
+const overlayfsSuperMagic = 0x794c7630
+
+// isOverlay returns whether the path is on an overlay filesystem. Overridden for unit testing.
+var isOverlay = func(path string) (bool, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return false, err
+	}
+	return stat.Type == overlayfsSuperMagic, nil
+}
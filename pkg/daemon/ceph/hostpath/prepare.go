@@ -0,0 +1,159 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package hostpath prepares the dataDirHostPath of a Ceph cluster on a node.
+package hostpath
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/coreos/pkg/capnslog"
+	"github.com/rook/rook/pkg/util/exec"
+)
+
+// HostRoot is where the root of the host is mounted in the container preparing the node
+const HostRoot = "/rootfs"
+
+var (
+	logger = capnslog.NewPackageLogger("github.com/rook/rook", "hostpath")
+
+	// the paths read and written on the host, overridden for unit testing
+	hostRoot       = HostRoot
+	seLinuxEnforce = "/sys/fs/selinux/enforce"
+
+	executor exec.Executor = &exec.CommandExecutor{}
+)
+
+// Config is the preparation of the dataDirHostPath of a node
+type Config struct {
+	// Path is the dataDirHostPath
+	Path string
+	// NodePath is the directory of the data on the node, the dataDirHostPath is a link to it if set
+	NodePath string
+	// SELinuxType is the SELinux type the data directory is labeled with if SELinux is enabled
+	SELinuxType string
+	// AllowOverlay allows the data directory on an overlay filesystem
+	AllowOverlay bool
+}
+
+// Prepare prepares the dataDirHostPath of the node and returns the reasons of the failures
+func Prepare(config Config) []string {
+	if !filepath.IsAbs(config.Path) || (config.NodePath != "" && !filepath.IsAbs(config.NodePath)) {
+		return []string{fmt.Sprintf("the paths must be absolute, got %q and %q", config.Path, config.NodePath)}
+	}
+
+	dataDir := config.Path
+	if config.NodePath != "" {
+		if failure := linkDataDir(config.Path, config.NodePath); failure != "" {
+			return []string{failure}
+		}
+		dataDir = config.NodePath
+	} else if err := os.MkdirAll(onHost(dataDir), 0755); err != nil {
+		return []string{fmt.Sprintf("failed to create %q. %v", dataDir, err)}
+	}
+
+	failures := []string{}
+	if !config.AllowOverlay {
+		overlay, err := isOverlay(onHost(dataDir))
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("failed to get the filesystem of %q. %v", dataDir, err))
+		} else if overlay {
+			failures = append(failures, fmt.Sprintf("%q is on an overlay filesystem, whose data is lost when the node restarts", dataDir))
+		}
+	}
+
+	if config.SELinuxType != "" && isSELinuxEnabled() {
+		if failure := labelDataDir(dataDir, config.SELinuxType); failure != "" {
+			failures = append(failures, failure)
+		}
+	}
+
+	for _, failure := range failures {
+		logger.Warningf("failed to prepare the data dir: %s", failure)
+	}
+	return failures
+}
+
+// linkDataDir creates the dataDirHostPath as a link to the directory of the data on the node. An existing
+// dataDirHostPath is never replaced since it may contain the data of the cluster.
+func linkDataDir(path, nodePath string) string {
+	if err := os.MkdirAll(onHost(nodePath), 0755); err != nil {
+		return fmt.Sprintf("failed to create %q. %v", nodePath, err)
+	}
+
+	info, err := os.Lstat(onHost(path))
+	if os.IsNotExist(err) {
+		logger.Infof("linking %q to %q", path, nodePath)
+		if err := os.MkdirAll(filepath.Dir(onHost(path)), 0755); err != nil {
+			return fmt.Sprintf("failed to create the parent of %q. %v", path, err)
+		}
+		// the target of the link is the path on the host, where the link is followed
+		if err := os.Symlink(nodePath, onHost(path)); err != nil {
+			return fmt.Sprintf("failed to link %q to %q. %v", path, nodePath, err)
+		}
+		return ""
+	}
+	if err != nil {
+		return fmt.Sprintf("failed to get the status of %q. %v", path, err)
+	}
+
+	if info.Mode()&os.ModeSymlink == 0 {
+		return fmt.Sprintf("%q already exists and is not a link to %q, its content must be moved to %q and it must be removed", path, nodePath, nodePath)
+	}
+	target, err := os.Readlink(onHost(path))
+	if err != nil {
+		return fmt.Sprintf("failed to read the link %q. %v", path, err)
+	}
+	if filepath.Clean(target) != filepath.Clean(nodePath) {
+		return fmt.Sprintf("%q is a link to %q instead of %q", path, target, nodePath)
+	}
+	return ""
+}
+
+// labelDataDir labels the data directory with the SELinux type. The preparation runs at each orchestration, so the
+// data directory is only relabeled when its type differs, instead of relabeling all the data of the daemons each time.
+func labelDataDir(dataDir, seLinuxType string) string {
+	output, err := executor.ExecuteCommandWithCombinedOutput("stat", "-c", "%C", onHost(dataDir))
+	if err != nil {
+		return fmt.Sprintf("failed to get the SELinux context of %q. %v. %s", dataDir, err, output)
+	}
+	// the context is "user:role:type:level"
+	context := strings.Split(strings.TrimSpace(output), ":")
+	if len(context) >= 3 && context[2] == seLinuxType {
+		logger.Debugf("%q is already labeled with the SELinux type %q", dataDir, seLinuxType)
+		return ""
+	}
+
+	logger.Infof("labeling %q with the SELinux type %q", dataDir, seLinuxType)
+	output, err = executor.ExecuteCommandWithCombinedOutput("chcon", "-R", "-t", seLinuxType, onHost(dataDir))
+	if err != nil {
+		return fmt.Sprintf("failed to label %q with the SELinux type %q. %v. %s", dataDir, seLinuxType, err, output)
+	}
+	return ""
+}
+
+func isSELinuxEnabled() bool {
+	_, err := os.Stat(seLinuxEnforce)
+	return err == nil
+}
+
+// onHost returns the path of the container where the path of the host is mounted
+func onHost(path string) string {
+	return filepath.Join(hostRoot, path)
+}
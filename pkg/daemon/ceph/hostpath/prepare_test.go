@@ -0,0 +1,116 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostpath
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// setHost sets a temporary host root, without SELinux and overlay filesystem
+func setHost(t *testing.T) {
+	oldRoot, oldEnforce, oldOverlay, oldExecutor := hostRoot, seLinuxEnforce, isOverlay, executor
+	hostRoot = t.TempDir()
+	seLinuxEnforce = filepath.Join(t.TempDir(), "enforce")
+	isOverlay = func(path string) (bool, error) { return false, nil }
+	t.Cleanup(func() {
+		hostRoot, seLinuxEnforce, isOverlay, executor = oldRoot, oldEnforce, oldOverlay, oldExecutor
+	})
+}
+
+func TestPrepare(t *testing.T) {
+	t.Run("the data dir is created", func(t *testing.T) {
+		setHost(t)
+		assert.Empty(t, Prepare(Config{Path: "/var/lib/rook"}))
+		info, err := os.Stat(filepath.Join(hostRoot, "var/lib/rook"))
+		assert.NoError(t, err)
+		assert.True(t, info.IsDir())
+
+		assert.NotEmpty(t, Prepare(Config{Path: "var/lib/rook"}))
+	})
+
+	t.Run("the data dir is linked to the dir of the node", func(t *testing.T) {
+		setHost(t)
+		config := Config{Path: "/var/lib/rook", NodePath: "/mnt/data/rook"}
+		assert.Empty(t, Prepare(config))
+		target, err := os.Readlink(filepath.Join(hostRoot, "var/lib/rook"))
+		assert.NoError(t, err)
+		assert.Equal(t, "/mnt/data/rook", target)
+		_, err = os.Stat(filepath.Join(hostRoot, "mnt/data/rook"))
+		assert.NoError(t, err)
+
+		// the preparation runs again at each orchestration
+		assert.Empty(t, Prepare(config))
+
+		failures := Prepare(Config{Path: "/var/lib/rook", NodePath: "/mnt/other"})
+		assert.Equal(t, []string{`"/var/lib/rook" is a link to "/mnt/data/rook" instead of "/mnt/other"`}, failures)
+	})
+
+	t.Run("an existing data dir is not replaced by a link", func(t *testing.T) {
+		setHost(t)
+		assert.NoError(t, os.MkdirAll(filepath.Join(hostRoot, "var/lib/rook/mon-a"), 0755))
+		failures := Prepare(Config{Path: "/var/lib/rook", NodePath: "/mnt/data/rook"})
+		assert.Len(t, failures, 1)
+		assert.Contains(t, failures[0], "is not a link")
+		_, err := os.Stat(filepath.Join(hostRoot, "var/lib/rook/mon-a"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("a data dir on an overlay filesystem fails unless allowed", func(t *testing.T) {
+		setHost(t)
+		isOverlay = func(path string) (bool, error) { return true, nil }
+		assert.Equal(t, []string{`"/var/lib/rook" is on an overlay filesystem, whose data is lost when the node restarts`},
+			Prepare(Config{Path: "/var/lib/rook"}))
+		assert.Empty(t, Prepare(Config{Path: "/var/lib/rook", AllowOverlay: true}))
+	})
+
+	t.Run("the data dir is labeled when selinux is enabled", func(t *testing.T) {
+		setHost(t)
+		labeled := []string{}
+		seLinuxContext := "system_u:object_r:var_lib_t:s0"
+		executor = &exectest.MockExecutor{
+			MockExecuteCommandWithCombinedOutput: func(command string, args ...string) (string, error) {
+				switch command {
+				case "stat":
+					return seLinuxContext + "\n", nil
+				case "chcon":
+					labeled = append(labeled, args[len(args)-1])
+					seLinuxContext = "system_u:object_r:container_file_t:s0"
+					return "", nil
+				}
+				return "", errors.Errorf("unexpected command %q", command)
+			},
+		}
+		config := Config{Path: "/var/lib/rook", NodePath: "/mnt/data/rook", SELinuxType: "container_file_t"}
+
+		assert.Empty(t, Prepare(config))
+		assert.Empty(t, labeled)
+
+		assert.NoError(t, os.WriteFile(seLinuxEnforce, []byte("1"), 0600))
+		assert.Empty(t, Prepare(config))
+		assert.Equal(t, []string{filepath.Join(hostRoot, "mnt/data/rook")}, labeled)
+
+		// the data dir is not relabeled once it has the type
+		assert.Empty(t, Prepare(config))
+		assert.Len(t, labeled, 1)
+	})
+}
@@ -33,6 +33,7 @@ type OsdAgent struct {
 	nodeName       string
 	forceFormat    bool
 	devices        []DesiredDevice
+	driveGroups    []config.DriveGroup
 	metadataDevice string
 	storeConfig    config.StoreConfig
 	kv             *k8sutil.ConfigMapKVStore
@@ -40,11 +41,12 @@ type OsdAgent struct {
 }
 
 // NewAgent is the instantiation of the OSD agent
-func NewAgent(context *clusterd.Context, devices []DesiredDevice, metadataDevice string, forceFormat bool,
+func NewAgent(context *clusterd.Context, devices []DesiredDevice, driveGroups []config.DriveGroup, metadataDevice string, forceFormat bool,
 	storeConfig config.StoreConfig, clusterInfo *cephclient.ClusterInfo, nodeName string, kv *k8sutil.ConfigMapKVStore, pvcBacked bool) *OsdAgent {
 
 	return &OsdAgent{
 		devices:        devices,
+		driveGroups:    driveGroups,
 		metadataDevice: metadataDevice,
 		forceFormat:    forceFormat,
 		storeConfig:    storeConfig,
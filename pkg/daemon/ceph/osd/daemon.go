@@ -185,6 +185,10 @@ func Provision(context *clusterd.Context, agent *OsdAgent, crushLocation, topolo
 		return errors.Wrap(err, "failed to generate ceph config")
 	}
 
+	if len(agent.driveGroups) > 0 {
+		return provisionDriveGroups(context, agent, crushLocation, topologyAffinity)
+	}
+
 	logger.Infof("discovering hardware")
 
 	var rawDevices []*sys.LocalDisk
@@ -0,0 +1,144 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	oposd "github.com/rook/rook/pkg/operator/ceph/cluster/osd"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/osd/config"
+)
+
+// provisionDriveGroups prepares the OSDs of the drive groups of the node instead of the OSDs of its desired devices
+func provisionDriveGroups(context *clusterd.Context, agent *OsdAgent, crushLocation, topologyAffinity string) error {
+	logger.Infof("configuring osds from %d drive group(s)", len(agent.driveGroups))
+	if err := agent.configureDriveGroups(context); err != nil {
+		return errors.Wrap(err, "failed to configure drive groups")
+	}
+
+	// list the OSDs prepared by ceph-volume on the node, including the ones of the previous reconciles
+	deviceOSDs, err := agent.configureCVDevices(context, &DeviceOsdMapping{Entries: map[string]*DeviceOsdIDEntry{}})
+	if err != nil {
+		return errors.Wrap(err, "failed to list the osds of the drive groups")
+	}
+	for i := range deviceOSDs {
+		deviceOSDs[i].Location = crushLocation
+		deviceOSDs[i].TopologyAffinity = topologyAffinity
+	}
+	logger.Infof("devices = %+v", deviceOSDs)
+
+	status := oposd.OrchestrationStatus{OSDs: deviceOSDs, Status: oposd.OrchestrationStatusCompleted}
+	oposd.UpdateNodeOrPVCStatus(agent.clusterInfo.Context, agent.kv, agent.nodeName, status)
+	return nil
+}
+
+// configureDriveGroups prepares the OSDs of the drive groups. ceph-volume translates each drive group into the
+// ceph-volume lvm batch commands for the devices of the node matching its spec, which are run with --prepare so the
+// OSDs are activated by their deployments like the other OSDs. The devices already used by OSDs are skipped by
+// ceph-volume, so the drive groups are applied again at every reconcile.
+func (a *OsdAgent) configureDriveGroups(context *clusterd.Context) error {
+	if err := createOSDBootstrapKeyring(context, a.clusterInfo, cephConfigDir); err != nil {
+		return errors.Wrap(err, "failed to generate osd keyring")
+	}
+	if err := lvmPreReq(context); err != nil {
+		return errors.Wrap(err, "failed to check lvm prerequisites")
+	}
+
+	logPath := "/tmp/ceph-log"
+	if err := os.MkdirAll(logPath, 0700); err != nil {
+		return errors.Wrapf(err, "failed to create dir %q", logPath)
+	}
+
+	for _, driveGroup := range a.driveGroups {
+		spec, err := driveGroupSpec(driveGroup)
+		if err != nil {
+			return err
+		}
+
+		output, err := callCephVolume(context, "drive-group", "--spec", string(spec), "--dry-run")
+		if err != nil {
+			return errors.Wrapf(err, "failed to get the ceph-volume commands of drive group %q", driveGroup.Name)
+		}
+
+		batches := driveGroupBatchArgs(output)
+		if len(batches) == 0 {
+			logger.Infof("no available devices of drive group %q on this node", driveGroup.Name)
+			continue
+		}
+		for _, batchArgs := range batches {
+			logger.Infof("preparing the osds of drive group %q: %v", driveGroup.Name, batchArgs)
+			args := append([]string{"-oL", cephVolumeCmd, "--log-path", logPath}, batchArgs...)
+			if err := context.Executor.ExecuteCommand("stdbuf", args...); err != nil {
+				cvLog := readCVLogContent("/tmp/ceph-log/ceph-volume.log")
+				if cvLog != "" {
+					logger.Errorf("%s", cvLog)
+				}
+				return errors.Wrapf(err, "failed to prepare the osds of drive group %q", driveGroup.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// driveGroupSpec returns the spec of a drive group with the service fields required by ceph-volume. The devices
+// are selected on the node of the prepare job, so the placement matches any host unless the spec sets one.
+func driveGroupSpec(driveGroup config.DriveGroup) ([]byte, error) {
+	spec := map[string]interface{}{}
+	if err := json.Unmarshal(driveGroup.Spec, &spec); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the spec of drive group %q", driveGroup.Name)
+	}
+
+	spec["service_type"] = "osd"
+	spec["service_id"] = driveGroup.Name
+	if _, ok := spec["placement"]; !ok {
+		spec["placement"] = map[string]interface{}{"host_pattern": "*"}
+	}
+
+	return json.Marshal(spec)
+}
+
+// driveGroupBatchArgs parses the ceph-volume lvm batch commands printed by the dry run of a drive group, adding
+// --prepare so that the OSDs are not activated by the prepare job
+func driveGroupBatchArgs(output string) [][]string {
+	batches := [][]string{}
+	for _, line := range strings.Split(output, "\n") {
+		args := strings.Fields(line)
+		if len(args) > 0 && args[0] == cephVolumeCmd {
+			args = args[1:]
+		}
+		if len(args) < 2 || args[0] != "lvm" || args[1] != "batch" {
+			continue
+		}
+
+		prepare := false
+		for _, arg := range args {
+			if arg == "--prepare" {
+				prepare = true
+			}
+		}
+		if !prepare {
+			args = append(args[:2], append([]string{"--prepare"}, args[2:]...)...)
+		}
+		batches = append(batches, args)
+	}
+	return batches
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/rook/rook/pkg/operator/ceph/cluster/osd/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDriveGroupSpec(t *testing.T) {
+	driveGroup := config.DriveGroup{Name: "hdd-with-nvme-db", Spec: json.RawMessage(`{"data_devices":{"rotational":1},"db_devices":{"rotational":0}}`)}
+	raw, err := driveGroupSpec(driveGroup)
+	assert.NoError(t, err)
+	spec := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(raw, &spec))
+	assert.Equal(t, "osd", spec["service_type"])
+	assert.Equal(t, "hdd-with-nvme-db", spec["service_id"])
+	assert.Equal(t, map[string]interface{}{"host_pattern": "*"}, spec["placement"])
+	assert.Equal(t, map[string]interface{}{"rotational": float64(1)}, spec["data_devices"])
+
+	// the placement of the spec is kept
+	driveGroup.Spec = json.RawMessage(`{"data_devices":{"all":true},"placement":{"host_pattern":"node1"}}`)
+	raw, err = driveGroupSpec(driveGroup)
+	assert.NoError(t, err)
+	spec = map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(raw, &spec))
+	assert.Equal(t, map[string]interface{}{"host_pattern": "node1"}, spec["placement"])
+
+	// invalid spec
+	driveGroup.Spec = json.RawMessage(`["sdb"]`)
+	_, err = driveGroupSpec(driveGroup)
+	assert.Error(t, err)
+}
+
+func TestDriveGroupBatchArgs(t *testing.T) {
+	// nothing to do on the node
+	assert.Equal(t, [][]string{}, driveGroupBatchArgs(""))
+	assert.Equal(t, [][]string{}, driveGroupBatchArgs("--> All data devices are unavailable\n"))
+
+	output := `lvm batch --no-auto /dev/sdb /dev/sdc --db-devices /dev/nvme0n1 --yes --no-systemd
+ceph-volume lvm batch --prepare --no-auto /dev/sdd --yes --no-systemd
+`
+	batches := driveGroupBatchArgs(output)
+	assert.Equal(t, [][]string{
+		{"lvm", "batch", "--prepare", "--no-auto", "/dev/sdb", "/dev/sdc", "--db-devices", "/dev/nvme0n1", "--yes", "--no-systemd"},
+		{"lvm", "batch", "--prepare", "--no-auto", "/dev/sdd", "--yes", "--no-systemd"},
+	}, batches)
+}
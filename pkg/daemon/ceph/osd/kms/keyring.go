@@ -0,0 +1,161 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kms
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+)
+
+const (
+	//nolint:gosec // since this is not leaking any hardcoded credentials, it's just the prefix of the key name
+	keyringEncryptionKeyPrefix = "rook-ceph-keyring-encryption-key"
+	// the prefix of the keys encrypted with the keyring encryption key, the version allows changing the cipher later
+	encryptedKeyringPrefix   = "kms-aes256gcm-v1:"
+	keyringEncryptionKeySize = 32
+)
+
+// KeyringEncryptionKeyName returns the name of the key encrypting the cluster keys in the KMS
+func KeyringEncryptionKeyName(namespace string) string {
+	return fmt.Sprintf("%s-%s", keyringEncryptionKeyPrefix, namespace)
+}
+
+// IsEncryptedKeyringSecret returns whether a key stored in a Kubernetes secret is encrypted with the KMS
+func IsEncryptedKeyringSecret(value string) bool {
+	return strings.HasPrefix(value, encryptedKeyringPrefix)
+}
+
+// GetKeyringEncryptionKey returns the key encrypting the cluster keys from the KMS
+func (c *Config) GetKeyringEncryptionKey() (string, error) {
+	name := KeyringEncryptionKeyName(c.ClusterInfo.Namespace)
+	kek, err := c.GetSecret(name)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get keyring encryption key %q", name)
+	}
+	if kek == "" {
+		return "", errors.Errorf("keyring encryption key %q is empty", name)
+	}
+	return kek, nil
+}
+
+// GetOrCreateKeyringEncryptionKey returns the key encrypting the cluster keys, the key is generated and stored in the
+// KMS if it cannot be retrieved. It must only be called when no key is encrypted yet, since a key that could not be
+// retrieved because of a transient error would be overwritten.
+func (c *Config) GetOrCreateKeyringEncryptionKey() (string, error) {
+	kek, err := c.GetKeyringEncryptionKey()
+	if err == nil {
+		return kek, nil
+	}
+	logger.Infof("generating the keyring encryption key. %v", err)
+
+	key := make([]byte, keyringEncryptionKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", errors.Wrap(err, "failed to generate keyring encryption key")
+	}
+	name := KeyringEncryptionKeyName(c.ClusterInfo.Namespace)
+	if err := c.PutSecret(name, base64.StdEncoding.EncodeToString(key)); err != nil {
+		return "", errors.Wrapf(err, "failed to store keyring encryption key %q", name)
+	}
+
+	// read back the key since some KMS do not overwrite an existing key
+	return c.GetKeyringEncryptionKey()
+}
+
+// NewKeyringEncryptionConfigFromEnv returns the KMS config of the keyring encryption in a Rook container, the KMS
+// details are passed by the operator as env variables
+func NewKeyringEncryptionConfigFromEnv(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo) (*Config, error) {
+	clusterSpec := &cephv1.ClusterSpec{Security: cephv1.SecuritySpec{KeyManagementService: cephv1.KeyManagementServiceSpec{ConnectionDetails: ConfigEnvsToMapString()}}}
+	if !clusterSpec.Security.KeyManagementService.IsEnabled() {
+		return nil, errors.New("failed to find the kms connection details of the keyring encryption")
+	}
+
+	// the ibm key protect library does not read the environment variables, the service api key is mounted from the
+	// token secret as an environment variable
+	if clusterSpec.Security.KeyManagementService.IsIBMKeyProtectKMS() {
+		ibmServiceApiKey := os.Getenv(IbmKeyProtectServiceApiKey)
+		if ibmServiceApiKey == "" {
+			return nil, errors.Errorf("ibm key protect %q environment variable is not set", IbmKeyProtectServiceApiKey)
+		}
+		clusterSpec.Security.KeyManagementService.ConnectionDetails[IbmKeyProtectServiceApiKey] = ibmServiceApiKey
+	}
+
+	return NewConfig(context, clusterSpec, clusterInfo), nil
+}
+
+// EncryptKeyringSecret encrypts a cluster key with the keyring encryption key
+func EncryptKeyringSecret(kek, value string) (string, error) {
+	gcm, err := keyringCipher(kek)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", errors.Wrap(err, "failed to generate nonce")
+	}
+	encrypted := gcm.Seal(nonce, nonce, []byte(value), nil)
+	return encryptedKeyringPrefix + base64.StdEncoding.EncodeToString(encrypted), nil
+}
+
+// DecryptKeyringSecret decrypts a cluster key encrypted with EncryptKeyringSecret. The keys that are not encrypted are
+// returned as is.
+func DecryptKeyringSecret(kek, value string) (string, error) {
+	if !IsEncryptedKeyringSecret(value) {
+		return value, nil
+	}
+	gcm, err := keyringCipher(kek)
+	if err != nil {
+		return "", err
+	}
+	encrypted, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedKeyringPrefix))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decode encrypted key")
+	}
+	if len(encrypted) < gcm.NonceSize() {
+		return "", errors.New("encrypted key is too short")
+	}
+	nonce, encrypted := encrypted[:gcm.NonceSize()], encrypted[gcm.NonceSize():]
+	decrypted, err := gcm.Open(nil, nonce, encrypted, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decrypt key")
+	}
+	return string(decrypted), nil
+}
+
+func keyringCipher(kek string) (cipher.AEAD, error) {
+	key, err := base64.StdEncoding.DecodeString(kek)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode keyring encryption key")
+	}
+	if len(key) != keyringEncryptionKeySize {
+		return nil, errors.Errorf("invalid keyring encryption key size %d, expected %d", len(key), keyringEncryptionKeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create keyring cipher")
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kms
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyringEncryption(t *testing.T) {
+	kek := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", keyringEncryptionKeySize)))
+	secret := "AQBsPaxgJU2JMxAAOjeUvAXg1rPnfAsBmwbn3Q=="
+
+	t.Run("round trip", func(t *testing.T) {
+		encrypted, err := EncryptKeyringSecret(kek, secret)
+		assert.NoError(t, err)
+		assert.True(t, IsEncryptedKeyringSecret(encrypted))
+		assert.NotContains(t, encrypted, secret)
+
+		decrypted, err := DecryptKeyringSecret(kek, encrypted)
+		assert.NoError(t, err)
+		assert.Equal(t, secret, decrypted)
+
+		// the nonce is random
+		again, err := EncryptKeyringSecret(kek, secret)
+		assert.NoError(t, err)
+		assert.NotEqual(t, encrypted, again)
+	})
+
+	t.Run("not encrypted", func(t *testing.T) {
+		assert.False(t, IsEncryptedKeyringSecret(secret))
+		decrypted, err := DecryptKeyringSecret("", secret)
+		assert.NoError(t, err)
+		assert.Equal(t, secret, decrypted)
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		encrypted, err := EncryptKeyringSecret(kek, secret)
+		assert.NoError(t, err)
+		other := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("o", keyringEncryptionKeySize)))
+		_, err = DecryptKeyringSecret(other, encrypted)
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		_, err := EncryptKeyringSecret(base64.StdEncoding.EncodeToString([]byte("short")), secret)
+		assert.Error(t, err)
+		_, err = EncryptKeyringSecret("not base64!", secret)
+		assert.Error(t, err)
+	})
+
+	t.Run("corrupted", func(t *testing.T) {
+		_, err := DecryptKeyringSecret(kek, encryptedKeyringPrefix+"AAAA")
+		assert.Error(t, err)
+	})
+}
+
+func TestKeyringEncryptionKeyName(t *testing.T) {
+	assert.Equal(t, "rook-ceph-keyring-encryption-key-rook-ceph", KeyringEncryptionKeyName("rook-ceph"))
+}
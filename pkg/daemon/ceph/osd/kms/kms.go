@@ -215,6 +215,20 @@ func (c *Config) UpdateSecret(secretName, secretValue string) error {
 
 		return nil
 	}
+	if c.IsVault() {
+		// Update the secret in Vault
+		v, err := InitVault(c.ClusterInfo.Context, c.context, c.ClusterInfo.Namespace, c.clusterSpec.Security.KeyManagementService.ConnectionDetails)
+		if err != nil {
+			return errors.Wrap(err, "failed to init vault kms")
+		}
+		k := buildVaultKeyContext(c.clusterSpec.Security.KeyManagementService.ConnectionDetails)
+		err = update(v, GenerateOSDEncryptionSecretName(secretName), secretValue, k)
+		if err != nil {
+			return errors.Wrap(err, "failed to update secret in vault")
+		}
+
+		return nil
+	}
 
 	return errors.Errorf("update secret is not supported for the %q KMS", c.Provider)
 }
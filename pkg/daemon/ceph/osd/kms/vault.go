@@ -197,12 +197,18 @@ func put(v secrets.Secrets, secretName, secretValue string, keyContext map[strin
 		return nil
 	}
 
+	return update(v, secretName, secretValue, keyContext)
+}
+
+// update writes the secret in Vault even if it already exists. With the K/V version 2 the previous value is kept as
+// an older version of the secret.
+func update(v secrets.Secrets, secretName, secretValue string, keyContext map[string]string) error {
 	// Build Secret
 	data := make(map[string]interface{})
 	data[secretName] = secretValue
 
 	//nolint:gosec // Write the encryption key in Vault
-	err = v.PutSecret(secretName, data, keyContext)
+	err := v.PutSecret(secretName, data, keyContext)
 	if err != nil {
 		return errors.Wrapf(err, "failed to put secret %q in vault", secretName)
 	}
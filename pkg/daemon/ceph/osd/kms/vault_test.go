@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/coreos/pkg/capnslog"
+	"github.com/libopenstorage/secrets"
 	"github.com/pkg/errors"
 	"github.com/rook/rook/pkg/clusterd"
 	"github.com/rook/rook/pkg/operator/test"
@@ -275,3 +276,46 @@ func Test_buildVaultKeyContext(t *testing.T) {
 		assert.Len(t, context, 2)
 	})
 }
+
+// fakeSecrets stores the secrets in memory
+type fakeSecrets struct {
+	secrets.Secrets
+	data map[string]map[string]interface{}
+}
+
+func (f *fakeSecrets) GetSecret(secretID string, keyContext map[string]string) (map[string]interface{}, error) {
+	data, ok := f.data[secretID]
+	if !ok {
+		return nil, secrets.ErrInvalidSecretId
+	}
+	return data, nil
+}
+
+func (f *fakeSecrets) PutSecret(secretID string, plainText map[string]interface{}, keyContext map[string]string) error {
+	f.data[secretID] = plainText
+	return nil
+}
+
+func Test_putAndUpdate(t *testing.T) {
+	v := &fakeSecrets{data: map[string]map[string]interface{}{}}
+
+	err := put(v, "rook-ceph-osd-encryption-key-pvc", "key1", map[string]string{})
+	assert.NoError(t, err)
+	value, err := get(v, "rook-ceph-osd-encryption-key-pvc", map[string]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, "key1", value)
+
+	// put never overwrites an existing key
+	err = put(v, "rook-ceph-osd-encryption-key-pvc", "key2", map[string]string{})
+	assert.NoError(t, err)
+	value, err = get(v, "rook-ceph-osd-encryption-key-pvc", map[string]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, "key1", value)
+
+	// update overwrites it when the key is rotated
+	err = update(v, "rook-ceph-osd-encryption-key-pvc", "key2", map[string]string{})
+	assert.NoError(t, err)
+	value, err = get(v, "rook-ceph-osd-encryption-key-pvc", map[string]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, "key2", value)
+}
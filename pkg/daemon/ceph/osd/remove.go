@@ -116,33 +116,42 @@ func removeOSD(clusterdContext *clusterd.Context, clusterInfo *client.ClusterInf
 		}
 	}
 
-	// Remove the OSD deployment
+	RemoveOSDDeployment(clusterdContext, clusterInfo, osdID, preservePVC)
+	if err := PurgeOSD(clusterdContext, clusterInfo, osdID, hostName); err != nil {
+		logger.Errorf("%v", err)
+	}
+
+	logger.Infof("completed removal of OSD %d", osdID)
+}
+
+// RemoveOSDDeployment removes the deployment of an OSD, and its prepare job and PVCs for an OSD on PVC
+func RemoveOSDDeployment(clusterdContext *clusterd.Context, clusterInfo *client.ClusterInfo, osdID int, preservePVC bool) {
 	deploymentName := fmt.Sprintf("rook-ceph-osd-%d", osdID)
 	deployment, err := clusterdContext.Clientset.AppsV1().Deployments(clusterInfo.Namespace).Get(clusterInfo.Context, deploymentName, metav1.GetOptions{})
 	if err != nil {
 		logger.Errorf("failed to fetch the deployment %q. %v", deploymentName, err)
+		return
+	}
+	logger.Infof("removing the OSD deployment %q", deploymentName)
+	if err := k8sutil.DeleteDeployment(clusterInfo.Context, clusterdContext.Clientset, clusterInfo.Namespace, deploymentName); err != nil {
+		// Continue purging the OSD even if the deployment fails to be deleted
+		logger.Errorf("failed to delete deployment for OSD %d. %v", osdID, err)
+	}
+	if pvcName, ok := deployment.GetLabels()[osd.OSDOverPVCLabelKey]; ok {
+		removeOSDPrepareJob(clusterdContext, clusterInfo, pvcName)
+		removePVCs(clusterdContext, clusterInfo, pvcName, preservePVC)
 	} else {
-		logger.Infof("removing the OSD deployment %q", deploymentName)
-		if err := k8sutil.DeleteDeployment(clusterInfo.Context, clusterdContext.Clientset, clusterInfo.Namespace, deploymentName); err != nil {
-			if err != nil {
-				// Continue purging the OSD even if the deployment fails to be deleted
-				logger.Errorf("failed to delete deployment for OSD %d. %v", osdID, err)
-			}
-		}
-		if pvcName, ok := deployment.GetLabels()[osd.OSDOverPVCLabelKey]; ok {
-			removeOSDPrepareJob(clusterdContext, clusterInfo, pvcName)
-			removePVCs(clusterdContext, clusterInfo, pvcName, preservePVC)
-		} else {
-			logger.Infof("did not find a pvc name to remove for osd %q", deploymentName)
-		}
+		logger.Infof("did not find a pvc name to remove for osd %q", deploymentName)
 	}
+}
 
-	// purge the osd
+// PurgeOSD purges an OSD from the cluster, and removes its CRUSH host if the host has no other OSD
+func PurgeOSD(clusterdContext *clusterd.Context, clusterInfo *client.ClusterInfo, osdID int, hostName string) error {
 	logger.Infof("purging osd.%d", osdID)
 	purgeOSDArgs := []string{"osd", "purge", fmt.Sprintf("osd.%d", osdID), "--force", "--yes-i-really-mean-it"}
-	_, err = client.NewCephCommand(clusterdContext, clusterInfo, purgeOSDArgs).Run()
+	_, err := client.NewCephCommand(clusterdContext, clusterInfo, purgeOSDArgs).Run()
 	if err != nil {
-		logger.Errorf("failed to purge osd.%d. %v", osdID, err)
+		return errors.Wrapf(err, "failed to purge osd.%d", osdID)
 	}
 
 	// Attempting to remove the parent host. Errors can be ignored if there are other OSDs on the same host
@@ -157,8 +166,7 @@ func removeOSD(clusterdContext *clusterd.Context, clusterInfo *client.ClusterInf
 
 	// call archiveCrash to silence crash warning in ceph health if any
 	archiveCrash(clusterdContext, clusterInfo, osdID)
-
-	logger.Infof("completed removal of OSD %d", osdID)
+	return nil
 }
 
 func removeOSDPrepareJob(clusterdContext *clusterd.Context, clusterInfo *client.ClusterInfo, pvcName string) {
@@ -0,0 +1,37 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preflight
+
+import (
+	"syscall"
+	"time"
+)
+
+// the clock state returned by adjtimex when the clock is not synchronized
+const clockStateError = 5
+
+// getClockStatus returns whether the kernel clock is synchronized, the same way as timedatectl, and the error of the
+// clock from its NTP sources as estimated by the NTP service. Overridden for unit testing.
+var getClockStatus = func() (bool, time.Duration, error) {
+	timex := &syscall.Timex{}
+	state, err := syscall.Adjtimex(timex)
+	if err != nil {
+		return false, 0, err
+	}
+	// the estimated error is in microseconds
+	return state != clockStateError, time.Duration(timex.Esterror) * time.Microsecond, nil
+}
@@ -0,0 +1,304 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package preflight runs the checks of a node before a Ceph cluster is created on it.
+package preflight
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/coreos/pkg/capnslog"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+)
+
+const (
+	writeBackCache = "write back"
+)
+
+var (
+	logger = capnslog.NewPackageLogger("github.com/rook/rook", "preflight")
+
+	// the host paths read by the checks, overridden for unit testing
+	sysRoot     = "/sys"
+	procSysRoot = "/proc/sys"
+	modulesRoot = "/lib/modules"
+
+	// the devices without a physical disk, which do not have a write cache
+	virtualDevicePrefixes = []string{"loop", "ram", "zram", "dm-", "md", "rbd", "nbd", "sr"}
+
+	// whether the port is free on the host network, overridden for unit testing
+	isPortFree = func(port int) bool {
+		listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+		if err != nil {
+			return false
+		}
+		listener.Close()
+		return true
+	}
+)
+
+// Config is the configuration of the checks of a node
+type Config struct {
+	Checks        []cephv1.PreflightCheck
+	KernelModules []string
+	Sysctls       map[string]string
+	Ports         []int
+	Devices       DeviceSelection
+}
+
+// DeviceSelection selects the OSD devices of the node, the same way as the storage selection of the CephCluster
+type DeviceSelection struct {
+	AllDevices  bool
+	Names       []string
+	Filters     []string
+	PathFilters []string
+}
+
+// Result is the result of the checks of a node. The failures block the creation of the cluster, while the
+// warnings are only reported.
+type Result struct {
+	Failures []string `json:"failures"`
+	Warnings []string `json:"warnings"`
+	// ClockErrorSeconds is the error of the clock of the node from its NTP sources estimated by the NTP service,
+	// reported by the time-sync check when the clock is synchronized
+	ClockErrorSeconds *float64 `json:"clockErrorSeconds,omitempty"`
+}
+
+// Run runs the checks of the config and returns the reasons of the failed checks
+func Run(config Config) Result {
+	failures := []string{}
+	warnings := []string{}
+	var clockError *float64
+	for _, check := range config.Checks {
+		logger.Infof("running preflight check %q", check)
+		switch check {
+		case cephv1.PreflightCheckKernelModules:
+			failures = append(failures, checkKernelModules(config.KernelModules)...)
+		case cephv1.PreflightCheckTimeSync:
+			timeSyncFailures, estimatedError := checkTimeSync()
+			failures = append(failures, timeSyncFailures...)
+			clockError = estimatedError
+		case cephv1.PreflightCheckPorts:
+			failures = append(failures, checkPorts(config.Ports)...)
+		case cephv1.PreflightCheckSysctls:
+			failures = append(failures, checkSysctls(config.Sysctls)...)
+		case cephv1.PreflightCheckDiskWriteCache:
+			// a volatile write cache only impacts the performance of the OSDs
+			warnings = append(warnings, checkDiskWriteCache(config.Devices)...)
+		default:
+			failures = append(failures, fmt.Sprintf("unknown preflight check %q", check))
+		}
+	}
+	for _, failure := range failures {
+		logger.Warningf("preflight check failed: %s", failure)
+	}
+	for _, warning := range warnings {
+		logger.Warningf("preflight check warning: %s", warning)
+	}
+	return Result{Failures: failures, Warnings: warnings, ClockErrorSeconds: clockError}
+}
+
+// checkKernelModules checks that each module is loaded, or is built in or available to be loaded by the
+// kernel of the node
+func checkKernelModules(modules []string) []string {
+	failures := []string{}
+	var available map[string]bool
+	for _, module := range modules {
+		// the kernel uses underscores in the names of the loaded modules
+		module = strings.ReplaceAll(module, "-", "_")
+		if _, err := os.Stat(filepath.Join(sysRoot, "module", module)); err == nil {
+			continue
+		}
+		if available == nil {
+			var err error
+			available, err = availableKernelModules()
+			if err != nil {
+				return append(failures, fmt.Sprintf("failed to list the available kernel modules. %v", err))
+			}
+		}
+		if !available[module] {
+			failures = append(failures, fmt.Sprintf("kernel module %q is not loaded and is not available", module))
+		}
+	}
+	return failures
+}
+
+// availableKernelModules returns the modules built in the kernel and the modules that can be loaded
+func availableKernelModules() (map[string]bool, error) {
+	release, err := os.ReadFile(filepath.Join(procSysRoot, "kernel", "osrelease"))
+	if err != nil {
+		return nil, err
+	}
+	modules := map[string]bool{}
+	for _, file := range []string{"modules.builtin", "modules.dep"} {
+		content, err := os.ReadFile(filepath.Join(modulesRoot, strings.TrimSpace(string(release)), file))
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range strings.Split(string(content), "\n") {
+			// modules.dep lists the dependencies of the module after a colon
+			path := strings.SplitN(line, ":", 2)[0]
+			if path == "" {
+				continue
+			}
+			name := filepath.Base(path)
+			name = name[:strings.Index(name+".ko", ".ko")]
+			modules[strings.ReplaceAll(name, "-", "_")] = true
+		}
+	}
+	return modules, nil
+}
+
+// checkTimeSync checks that the clock of the node is synchronized by NTP, and returns the estimated error of the
+// synchronized clock in seconds
+func checkTimeSync() ([]string, *float64) {
+	synchronized, estimatedError, err := getClockStatus()
+	if err != nil {
+		return []string{fmt.Sprintf("failed to get the clock synchronization status. %v", err)}, nil
+	}
+	if !synchronized {
+		return []string{"the clock is not synchronized, chrony or another NTP service must be running"}, nil
+	}
+	seconds := estimatedError.Seconds()
+	return nil, &seconds
+}
+
+// checkPorts checks that the ports are not used by other processes on the host network
+func checkPorts(ports []int) []string {
+	failures := []string{}
+	for _, port := range ports {
+		if !isPortFree(port) {
+			failures = append(failures, fmt.Sprintf("port %d is already in use", port))
+		}
+	}
+	return failures
+}
+
+// checkSysctls checks that the kernel parameters have the required values
+func checkSysctls(sysctls map[string]string) []string {
+	failures := []string{}
+	names := make([]string, 0, len(sysctls))
+	for name := range sysctls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if name == "" || strings.Contains(name, "/") || strings.Contains(name, "..") {
+			failures = append(failures, fmt.Sprintf("invalid sysctl name %q", name))
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(procSysRoot, strings.ReplaceAll(name, ".", "/")))
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("failed to read sysctl %q. %v", name, err))
+			continue
+		}
+		// sysctls with several values are separated by tabs
+		value := strings.Join(strings.Fields(string(content)), " ")
+		expected := strings.Join(strings.Fields(sysctls[name]), " ")
+		if value != expected {
+			failures = append(failures, fmt.Sprintf("sysctl %q is %q instead of %q", name, value, expected))
+		}
+	}
+	return failures
+}
+
+// checkDiskWriteCache checks that the OSD devices of the node do not have a volatile write cache, which
+// increases the latency of the flushes of the OSDs
+func checkDiskWriteCache(selection DeviceSelection) []string {
+	warnings := []string{}
+	devices, err := os.ReadDir(filepath.Join(sysRoot, "block"))
+	if err != nil {
+		return []string{fmt.Sprintf("failed to list the block devices. %v", err)}
+	}
+	matchers, err := deviceMatchers(selection)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	for _, device := range devices {
+		if isVirtualDevice(device.Name()) || !isSelectedDevice(device.Name(), selection, matchers) {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(sysRoot, "block", device.Name(), "queue", "write_cache"))
+		if err != nil {
+			logger.Debugf("skipping write cache check of device %q. %v", device.Name(), err)
+			continue
+		}
+		if strings.TrimSpace(string(content)) == writeBackCache {
+			warnings = append(warnings, fmt.Sprintf("device %q has a volatile write cache (%s)", device.Name(), writeBackCache))
+		}
+	}
+	return warnings
+}
+
+type deviceMatcher struct {
+	filter *regexp.Regexp
+	// whether the filter matches the path of the device instead of its name
+	path bool
+}
+
+func deviceMatchers(selection DeviceSelection) ([]deviceMatcher, error) {
+	matchers := []deviceMatcher{}
+	for _, filter := range selection.Filters {
+		re, err := regexp.Compile(filter)
+		if err != nil {
+			return nil, fmt.Errorf("invalid device filter %q. %v", filter, err)
+		}
+		matchers = append(matchers, deviceMatcher{filter: re})
+	}
+	for _, filter := range selection.PathFilters {
+		re, err := regexp.Compile(filter)
+		if err != nil {
+			return nil, fmt.Errorf("invalid device path filter %q. %v", filter, err)
+		}
+		matchers = append(matchers, deviceMatcher{filter: re, path: true})
+	}
+	return matchers, nil
+}
+
+// isSelectedDevice returns whether the device is selected for the OSDs of the node. The devices are matched by
+// their kernel name, since the links of /dev/disk are not available to the check.
+func isSelectedDevice(name string, selection DeviceSelection, matchers []deviceMatcher) bool {
+	if selection.AllDevices {
+		return true
+	}
+	for _, device := range selection.Names {
+		if strings.TrimPrefix(device, "/dev/") == name {
+			return true
+		}
+	}
+	for _, matcher := range matchers {
+		if (matcher.path && matcher.filter.MatchString("/dev/"+name)) || (!matcher.path && matcher.filter.MatchString(name)) {
+			return true
+		}
+	}
+	return false
+}
+
+func isVirtualDevice(name string) bool {
+	for _, prefix := range virtualDevicePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
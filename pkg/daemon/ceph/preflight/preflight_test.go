@@ -0,0 +1,153 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preflight
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0600))
+}
+
+func setRoots(t *testing.T) string {
+	root := t.TempDir()
+	oldSys, oldProcSys, oldModules := sysRoot, procSysRoot, modulesRoot
+	sysRoot = filepath.Join(root, "sys")
+	procSysRoot = filepath.Join(root, "proc", "sys")
+	modulesRoot = filepath.Join(root, "lib", "modules")
+	t.Cleanup(func() {
+		sysRoot, procSysRoot, modulesRoot = oldSys, oldProcSys, oldModules
+	})
+	return root
+}
+
+func TestCheckKernelModules(t *testing.T) {
+	setRoots(t)
+	assert.NoError(t, os.MkdirAll(filepath.Join(sysRoot, "module", "rbd"), 0755))
+	writeFile(t, filepath.Join(procSysRoot, "kernel", "osrelease"), "5.14.0-test\n")
+	writeFile(t, filepath.Join(modulesRoot, "5.14.0-test", "modules.builtin"), "kernel/drivers/block/loop.ko\n")
+	writeFile(t, filepath.Join(modulesRoot, "5.14.0-test", "modules.dep"),
+		"kernel/drivers/block/nbd.ko.xz:\nkernel/fs/ceph/ceph.ko.zst: kernel/net/ceph/libceph.ko.zst\n")
+
+	assert.Empty(t, checkKernelModules([]string{"rbd", "nbd", "ceph", "loop"}))
+
+	failures := checkKernelModules([]string{"rbd", "dm-crypt"})
+	assert.Equal(t, []string{`kernel module "dm_crypt" is not loaded and is not available`}, failures)
+
+	// the modules of the kernel are not mounted
+	assert.NoError(t, os.RemoveAll(modulesRoot))
+	assert.Empty(t, checkKernelModules([]string{"rbd"}))
+	failures = checkKernelModules([]string{"nbd"})
+	assert.Len(t, failures, 1)
+	assert.Contains(t, failures[0], "failed to list the available kernel modules")
+}
+
+func TestCheckTimeSync(t *testing.T) {
+	oldClock := getClockStatus
+	defer func() { getClockStatus = oldClock }()
+
+	getClockStatus = func() (bool, time.Duration, error) { return true, 1500 * time.Microsecond, nil }
+	failures, clockError := checkTimeSync()
+	assert.Empty(t, failures)
+	assert.Equal(t, 0.0015, *clockError)
+
+	getClockStatus = func() (bool, time.Duration, error) { return false, 0, nil }
+	failures, clockError = checkTimeSync()
+	assert.Equal(t, []string{"the clock is not synchronized, chrony or another NTP service must be running"}, failures)
+	assert.Nil(t, clockError)
+}
+
+func TestCheckPorts(t *testing.T) {
+	oldPortFree := isPortFree
+	defer func() { isPortFree = oldPortFree }()
+	isPortFree = func(port int) bool { return port != 6789 }
+
+	assert.Empty(t, checkPorts([]int{3300}))
+	assert.Equal(t, []string{"port 6789 is already in use"}, checkPorts([]int{3300, 6789}))
+}
+
+func TestCheckSysctls(t *testing.T) {
+	setRoots(t)
+	writeFile(t, filepath.Join(procSysRoot, "vm", "nr_hugepages"), "1024\n")
+	writeFile(t, filepath.Join(procSysRoot, "net", "ipv4", "ip_local_port_range"), "32768\t60999\n")
+
+	assert.Empty(t, checkSysctls(map[string]string{
+		"vm.nr_hugepages":              "1024",
+		"net.ipv4.ip_local_port_range": "32768 60999",
+	}))
+
+	failures := checkSysctls(map[string]string{
+		"vm.nr_hugepages": "2048",
+		"kernel.pid_max":  "4194304",
+		"../etc/passwd":   "",
+	})
+	assert.Len(t, failures, 3)
+	assert.Equal(t, `invalid sysctl name "../etc/passwd"`, failures[0])
+	assert.Contains(t, failures[1], `failed to read sysctl "kernel.pid_max"`)
+	assert.Equal(t, `sysctl "vm.nr_hugepages" is "1024" instead of "2048"`, failures[2])
+}
+
+func TestCheckDiskWriteCache(t *testing.T) {
+	setRoots(t)
+	writeFile(t, filepath.Join(sysRoot, "block", "sda", "queue", "write_cache"), "write through\n")
+	writeFile(t, filepath.Join(sysRoot, "block", "loop0", "queue", "write_cache"), "write back\n")
+	writeFile(t, filepath.Join(sysRoot, "block", "sdb", "queue", "write_cache"), "write back\n")
+	writeFile(t, filepath.Join(sysRoot, "block", "nvme0n1", "queue", "write_cache"), "write back\n")
+
+	// only the OSD devices are checked
+	assert.Empty(t, checkDiskWriteCache(DeviceSelection{}))
+	assert.Empty(t, checkDiskWriteCache(DeviceSelection{Names: []string{"sda"}}))
+	assert.Equal(t, []string{`device "sdb" has a volatile write cache (write back)`}, checkDiskWriteCache(DeviceSelection{Names: []string{"/dev/sdb"}}))
+	assert.Equal(t, []string{`device "sdb" has a volatile write cache (write back)`}, checkDiskWriteCache(DeviceSelection{Filters: []string{"^sd[a-z]{1,2}$"}}))
+	assert.Equal(t, []string{`device "nvme0n1" has a volatile write cache (write back)`}, checkDiskWriteCache(DeviceSelection{PathFilters: []string{"^/dev/nvme"}}))
+	assert.Equal(t, []string{
+		`device "nvme0n1" has a volatile write cache (write back)`,
+		`device "sdb" has a volatile write cache (write back)`,
+	}, checkDiskWriteCache(DeviceSelection{AllDevices: true}))
+
+	assert.Equal(t, []string{"invalid device filter \"[\". error parsing regexp: missing closing ]: `[`"}, checkDiskWriteCache(DeviceSelection{Filters: []string{"["}}))
+}
+
+func TestRun(t *testing.T) {
+	setRoots(t)
+	oldPortFree := isPortFree
+	defer func() { isPortFree = oldPortFree }()
+	isPortFree = func(port int) bool { return false }
+
+	writeFile(t, filepath.Join(sysRoot, "block", "sdb", "queue", "write_cache"), "write back\n")
+
+	result := Run(Config{
+		Checks:  []cephv1.PreflightCheck{cephv1.PreflightCheckPorts, cephv1.PreflightCheckDiskWriteCache, "unknown"},
+		Ports:   []int{3300},
+		Devices: DeviceSelection{Names: []string{"sdb"}},
+	})
+	assert.Equal(t, []string{"port 3300 is already in use", `unknown preflight check "unknown"`}, result.Failures)
+	// the write cache of the devices is only a warning
+	assert.Equal(t, []string{`device "sdb" has a volatile write cache (write back)`}, result.Warnings)
+
+	result = Run(Config{})
+	assert.Empty(t, result.Failures)
+	assert.Empty(t, result.Warnings)
+}
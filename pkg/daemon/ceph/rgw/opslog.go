@@ -0,0 +1,132 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rgw prints the ops log of the rgw daemons.
+package rgw
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+)
+
+var (
+	logger = capnslog.NewPackageLogger("github.com/rook/rook", "rgw")
+
+	// the interval between the connections to the ops log socket, overridden for unit testing
+	reconnectInterval = 5 * time.Second
+)
+
+// PrintOpsLog prints the entries of the ops log that the rgw daemon writes to its ops log socket, one line per
+// request. The socket is reconnected until the context is canceled, since it only exists while rgw is running.
+func PrintOpsLog(ctx context.Context, socketPath string, format cephv1.OpsLogFormat, out io.Writer) error {
+	for {
+		err := printOpsLogFromSocket(ctx, socketPath, format, out)
+		if ctx.Err() != nil {
+			return nil
+		}
+		logger.Infof("reconnecting to the ops log socket %q in %s. %v", socketPath, reconnectInterval, err)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(reconnectInterval):
+		}
+	}
+}
+
+func printOpsLogFromSocket(ctx context.Context, socketPath string, format cephv1.OpsLogFormat, out io.Writer) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "unix", socketPath)
+	if err != nil {
+		return errors.Wrapf(err, "failed to connect to the ops log socket %q", socketPath)
+	}
+	defer conn.Close()
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	logger.Infof("printing the ops log of socket %q", socketPath)
+	return PrintOpsLogEntries(conn, format, out)
+}
+
+// PrintOpsLogEntries prints the entries of an ops log stream. The stream of rgw is a JSON array of the entries that
+// is never closed.
+func PrintOpsLogEntries(in io.Reader, format cephv1.OpsLogFormat, out io.Writer) error {
+	decoder := json.NewDecoder(bufio.NewReader(in))
+	if _, err := decoder.Token(); err != nil {
+		return errors.Wrap(err, "failed to read the start of the ops log")
+	}
+	for decoder.More() {
+		var entry json.RawMessage
+		if err := decoder.Decode(&entry); err != nil {
+			return errors.Wrap(err, "failed to read ops log entry")
+		}
+		line, err := FormatOpsLogEntry(entry, format)
+		if err != nil {
+			// an invalid entry is skipped instead of losing the following entries
+			logger.Errorf("failed to format ops log entry. %v", err)
+			continue
+		}
+		if _, err := fmt.Fprintln(out, line); err != nil {
+			return errors.Wrap(err, "failed to print ops log entry")
+		}
+	}
+	return io.EOF
+}
+
+// FormatOpsLogEntry formats an entry of the ops log of rgw in a line of the ops log format
+func FormatOpsLogEntry(entry []byte, format cephv1.OpsLogFormat) (string, error) {
+	switch format {
+	case cephv1.OpsLogFormatJSON, "":
+		var line bytes.Buffer
+		if err := json.Compact(&line, entry); err != nil {
+			return "", errors.Wrap(err, "failed to compact ops log entry")
+		}
+		return line.String(), nil
+
+	case cephv1.OpsLogFormatCombined:
+		fields := map[string]interface{}{}
+		// the numbers are kept as is, instead of being printed as floats
+		decoder := json.NewDecoder(bytes.NewReader(entry))
+		decoder.UseNumber()
+		if err := decoder.Decode(&fields); err != nil {
+			return "", errors.Wrap(err, "failed to parse ops log entry")
+		}
+		field := func(names ...string) string {
+			for _, name := range names {
+				if value, ok := fields[name]; ok && value != nil && fmt.Sprint(value) != "" {
+					return fmt.Sprint(value)
+				}
+			}
+			return "-"
+		}
+		// the uri of the entries is the request line, e.g. "GET /bucket/object HTTP/1.1"
+		return fmt.Sprintf("%s - %s [%s] %q %s %s %q %q",
+			field("remote_addr"), field("user"), field("time_local", "time"), field("uri"),
+			field("http_status"), field("bytes_sent"), field("referrer"), field("user_agent")), nil
+	}
+	return "", errors.Errorf("unknown ops log format %q", format)
+}
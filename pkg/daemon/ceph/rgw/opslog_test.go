@@ -0,0 +1,134 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rgw
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const opsLogEntry = `{
+    "bucket": "my-bucket",
+    "time": "2023-06-01T10:00:00.000000Z",
+    "time_local": "2023-06-01T10:00:00.000000+0000",
+    "remote_addr": "10.0.0.1",
+    "user": "my-user",
+    "operation": "get_obj",
+    "uri": "GET /my-bucket/my-object HTTP/1.1",
+    "http_status": "200",
+    "error_code": "",
+    "bytes_sent": 1048576,
+    "bytes_received": 0,
+    "total_time": 3,
+    "user_agent": "aws-cli/2.0",
+    "referrer": ""
+}`
+
+func TestFormatOpsLogEntry(t *testing.T) {
+	t.Run("json", func(t *testing.T) {
+		line, err := FormatOpsLogEntry([]byte(opsLogEntry), cephv1.OpsLogFormatJSON)
+		assert.NoError(t, err)
+		assert.NotContains(t, line, "\n")
+		assert.Contains(t, line, `"uri":"GET /my-bucket/my-object HTTP/1.1"`)
+	})
+
+	t.Run("combined", func(t *testing.T) {
+		line, err := FormatOpsLogEntry([]byte(opsLogEntry), cephv1.OpsLogFormatCombined)
+		assert.NoError(t, err)
+		assert.Equal(t, `10.0.0.1 - my-user [2023-06-01T10:00:00.000000+0000] "GET /my-bucket/my-object HTTP/1.1" 200 1048576 "-" "aws-cli/2.0"`, line)
+
+		// the missing fields are printed as "-"
+		line, err = FormatOpsLogEntry([]byte(`{"time": "2023-06-01T10:00:00Z"}`), cephv1.OpsLogFormatCombined)
+		assert.NoError(t, err)
+		assert.Equal(t, `- - - [2023-06-01T10:00:00Z] "-" - - "-" "-"`, line)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := FormatOpsLogEntry([]byte(`{"time"`), cephv1.OpsLogFormatCombined)
+		assert.Error(t, err)
+		_, err = FormatOpsLogEntry([]byte(opsLogEntry), "xml")
+		assert.Error(t, err)
+	})
+}
+
+func TestPrintOpsLogEntries(t *testing.T) {
+	// the stream of rgw is never closed and has a trailing comma
+	stream := "[" + opsLogEntry + "," + opsLogEntry + ","
+	var out bytes.Buffer
+	err := PrintOpsLogEntries(strings.NewReader(stream), cephv1.OpsLogFormatCombined, &out)
+	assert.Error(t, err)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.True(t, strings.HasPrefix(lines[1], "10.0.0.1 - my-user"))
+
+	out.Reset()
+	assert.Error(t, PrintOpsLogEntries(strings.NewReader(""), cephv1.OpsLogFormatJSON, &out))
+	assert.Empty(t, out.String())
+}
+
+func TestPrintOpsLog(t *testing.T) {
+	interval := reconnectInterval
+	defer func() { reconnectInterval = interval }()
+	reconnectInterval = 10 * time.Millisecond
+
+	socketPath := filepath.Join(t.TempDir(), "ops-log.sock")
+	ctx, cancel := context.WithCancel(context.TODO())
+	out := &syncBuffer{}
+	done := make(chan error)
+	// the socket does not exist yet when the sidecar starts
+	go func() { done <- PrintOpsLog(ctx, socketPath, cephv1.OpsLogFormatJSON, out) }()
+
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	defer listener.Close()
+	conn, err := listener.Accept()
+	require.NoError(t, err)
+	_, err = conn.Write([]byte("[" + opsLogEntry + ","))
+	assert.NoError(t, err)
+
+	assert.Eventually(t, func() bool { return strings.Contains(out.String(), `"user":"my-user"`) }, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	assert.NoError(t, <-done)
+	conn.Close()
+}
+
+type syncBuffer struct {
+	mutex sync.Mutex
+	buf   bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.String()
+}
@@ -39,6 +39,7 @@ import (
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
 	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
 	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	v1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -75,7 +76,7 @@ type ReconcileCephClient struct {
 // Add creates a new CephClient Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
-	return add(mgr, newReconciler(mgr, context, opManagerContext))
+	return add(mgr, newReconciler(mgr, context, opManagerContext), opConfig.CanWatchSecrets)
 }
 
 // newReconciler returns a new reconcile.Reconciler
@@ -89,7 +90,7 @@ func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerCont
 	}
 }
 
-func add(mgr manager.Manager, r reconcile.Reconciler) error {
+func add(mgr manager.Manager, r reconcile.Reconciler, watchSecrets bool) error {
 	// Create a new controller
 	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
 	if err != nil {
@@ -103,7 +104,12 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
-	// Watch secrets
+	// Watch secrets, unless the operator is not allowed to, in which case changes to the secrets are only
+	// reverted on the next reconcile
+	if !watchSecrets {
+		logger.Info("not watching secrets since the operator is not allowed to watch them")
+		return nil
+	}
 	err = c.Watch(&source.Kind{Type: &v1.Secret{TypeMeta: metav1.TypeMeta{Kind: "Secret", APIVersion: v1.SchemeGroupVersion.String()}}}, &handler.EnqueueRequestForOwner{
 		IsController: true,
 		OwnerType:    &cephv1.CephClient{},
@@ -205,9 +211,20 @@ func (r *ReconcileCephClient) reconcile(request reconcile.Request) (reconcile.Re
 	// validate the client settings
 	err = ValidateClient(r.context, cephClient)
 	if err != nil {
+		reporting.ReportValidationFailed(r.recorder, cephClient, err)
 		return reconcile.Result{}, *cephClient, errors.Wrapf(err, "failed to validate client %q arguments", cephClient.Name)
 	}
 
+	// The caps of a profile depend on the ceph version
+	if cephClient.Spec.Profile != nil {
+		cephVersion, err := opcontroller.GetImageVersion(cephCluster)
+		if err != nil {
+			logger.Infof("waiting for the ceph version of the cluster to generate the caps of client %q. %v", cephClient.Name, err)
+			return opcontroller.WaitForRequeueIfCephClusterNotReady, *cephClient, nil
+		}
+		r.clusterInfo.CephVersion = *cephVersion
+	}
+
 	// Create or Update client
 	err = r.createOrUpdateClient(cephClient)
 	if err != nil {
@@ -233,7 +250,10 @@ func (r *ReconcileCephClient) createOrUpdateClient(cephClient *cephv1.CephClient
 	logger.Infof("creating client %s in namespace %s", cephClient.Name, cephClient.Namespace)
 
 	// Generate the CephX details
-	clientEntity, caps := genClientEntity(cephClient)
+	clientEntity, caps, err := genClientEntity(cephClient, r.clusterInfo.CephVersion)
+	if err != nil {
+		return errors.Wrapf(err, "failed to generate the caps of client %q", cephClient.Name)
+	}
 
 	// Check if client was created manually, create if necessary or update caps and create secret
 	key, err := cephclient.AuthGetKey(r.context, r.clusterInfo, clientEntity)
@@ -319,7 +339,7 @@ func ValidateClient(context *clusterd.Context, cephClient *cephv1.CephClient) er
 	}
 
 	// Validate Spec
-	if cephClient.Spec.Caps == nil {
+	if cephClient.Spec.Caps == nil && cephClient.Spec.Profile == nil {
 		return errors.New("no caps specified")
 	}
 	for _, cap := range cephClient.Spec.Caps {
@@ -327,17 +347,38 @@ func ValidateClient(context *clusterd.Context, cephClient *cephv1.CephClient) er
 			return errors.New("no caps specified")
 		}
 	}
+	if cephClient.Spec.Profile != nil {
+		if err := validateProfile(cephClient.Spec.Profile); err != nil {
+			return errors.Wrap(err, "invalid caps profile")
+		}
+	}
 
 	return nil
 }
 
-func genClientEntity(cephClient *cephv1.CephClient) (string, []string) {
-	caps := []string{}
+// genClientEntity returns the name of the client and its caps. The caps set in the spec override the caps of the
+// profile for the same daemon type.
+func genClientEntity(cephClient *cephv1.CephClient, cephVersion cephver.CephVersion) (string, []string, error) {
+	allCaps := map[string]string{}
+	if cephClient.Spec.Profile != nil {
+		profile, err := profileCaps(cephClient.Spec.Profile, cephVersion)
+		if err != nil {
+			return "", nil, err
+		}
+		for name, cap := range profile {
+			allCaps[name] = cap
+		}
+	}
 	for name, cap := range cephClient.Spec.Caps {
+		allCaps[name] = cap
+	}
+
+	caps := []string{}
+	for name, cap := range allCaps {
 		caps = append(caps, name, cap)
 	}
 
-	return generateClientName(cephClient.Name), caps
+	return generateClientName(cephClient.Name), caps, nil
 }
 
 func generateClientName(name string) string {
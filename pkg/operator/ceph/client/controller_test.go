@@ -28,6 +28,7 @@ import (
 	rookclient "github.com/rook/rook/pkg/client/clientset/versioned/fake"
 	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
 	"github.com/rook/rook/pkg/clusterd"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	testop "github.com/rook/rook/pkg/operator/test"
 	exectest "github.com/rook/rook/pkg/util/exec/test"
@@ -68,6 +69,17 @@ func TestValidateClient(t *testing.T) {
 	}
 	err = ValidateClient(context, &p)
 	assert.Nil(t, err)
+
+	// succeed with a caps profile
+	p = cephv1.CephClient{ObjectMeta: metav1.ObjectMeta{Name: "client1", Namespace: "myns"}}
+	p.Spec.Profile = &cephv1.ClientCapsProfileSpec{Name: cephv1.CapsProfileRBDUser, Pools: []string{"replicapool"}}
+	err = ValidateClient(context, &p)
+	assert.NoError(t, err)
+
+	// the filesystem is not a setting of the rbd-user profile
+	p.Spec.Profile.Filesystem = "myfs"
+	err = ValidateClient(context, &p)
+	assert.Error(t, err)
 }
 
 func TestGenerateClient(t *testing.T) {
@@ -81,7 +93,8 @@ func TestGenerateClient(t *testing.T) {
 		},
 	}
 
-	client, caps := genClientEntity(p)
+	client, caps, err := genClientEntity(p, cephver.Quincy)
+	assert.NoError(t, err)
 	equal := bytes.Compare([]byte(client), []byte("client.client1"))
 	var res bool = equal == 0
 	assert.True(t, res)
@@ -99,12 +112,50 @@ func TestGenerateClient(t *testing.T) {
 		},
 	}
 
-	client, _ = genClientEntity(p2)
+	client, _, err = genClientEntity(p2, cephver.Quincy)
+	assert.NoError(t, err)
 	equal = bytes.Compare([]byte(client), []byte("client.client2"))
 	res = equal == 0
 	assert.True(t, res)
 }
 
+func TestGenerateClientWithProfile(t *testing.T) {
+	p := &cephv1.CephClient{ObjectMeta: metav1.ObjectMeta{Name: "client1", Namespace: "myns"},
+		Spec: cephv1.ClientSpec{
+			Profile: &cephv1.ClientCapsProfileSpec{Name: cephv1.CapsProfileCephFSUser, Filesystem: "myfs"},
+			Caps:    map[string]string{"mgr": "allow r"},
+		},
+	}
+
+	_, caps, err := genClientEntity(p, cephver.Quincy)
+	assert.NoError(t, err)
+	assert.Len(t, caps, 8)
+	joined := strings.Join(caps, " ")
+	assert.Contains(t, joined, "mon allow r fsname=myfs")
+	assert.Contains(t, joined, "mds allow rw fsname=myfs")
+	assert.Contains(t, joined, "osd allow rw tag cephfs data=myfs")
+	// the caps of the spec override the profile
+	assert.Contains(t, joined, "mgr allow r")
+	assert.NotContains(t, joined, "mgr allow rw")
+
+	// the profile is not available before the minimum version
+	_, _, err = genClientEntity(p, cephver.CephVersion{Major: 15, Minor: 2, Extra: 0})
+	assert.Error(t, err)
+}
+
+func TestProfileCaps(t *testing.T) {
+	caps, err := profileCaps(&cephv1.ClientCapsProfileSpec{Name: cephv1.CapsProfileRBDUser, Pools: []string{"a", "b"}}, cephver.Quincy)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"mon": "profile rbd", "osd": "profile rbd pool=a, profile rbd pool=b", "mgr": "profile rbd pool=a, profile rbd pool=b"}, caps)
+
+	caps, err = profileCaps(&cephv1.ClientCapsProfileSpec{Name: cephv1.CapsProfileMonitoringReadOnly}, cephver.Quincy)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"mon": "allow r", "mgr": "allow r"}, caps)
+
+	_, err = profileCaps(&cephv1.ClientCapsProfileSpec{Name: "unknown"}, cephver.Quincy)
+	assert.Error(t, err)
+}
+
 func TestCephClientController(t *testing.T) {
 	ctx := context.TODO()
 	// Set DEBUG logging
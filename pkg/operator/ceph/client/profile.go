@@ -0,0 +1,98 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+)
+
+// profileCaps returns the caps of the profile for the ceph version of the cluster. The caps are generated again on
+// each reconcile, so the clients get the caps of the new ceph version when the cluster is upgraded.
+func profileCaps(profile *cephv1.ClientCapsProfileSpec, cephVersion cephver.CephVersion) (map[string]string, error) {
+	if !cephVersion.IsAtLeast(cephver.Minimum) {
+		return nil, errors.Errorf("caps profile %q requires ceph %q or newer", profile.Name, cephver.Minimum.String())
+	}
+
+	switch profile.Name {
+	case cephv1.CapsProfileRBDUser:
+		if len(profile.Pools) == 0 {
+			return map[string]string{
+				"mon": "profile rbd",
+				"osd": "profile rbd",
+				"mgr": "profile rbd",
+			}, nil
+		}
+		caps := []string{}
+		for _, pool := range profile.Pools {
+			caps = append(caps, fmt.Sprintf("profile rbd pool=%s", pool))
+		}
+		return map[string]string{
+			"mon": "profile rbd",
+			"osd": strings.Join(caps, ", "),
+			"mgr": strings.Join(caps, ", "),
+		}, nil
+
+	case cephv1.CapsProfileCephFSUser:
+		if profile.Filesystem == "" {
+			return map[string]string{
+				"mon": "allow r",
+				"mds": "allow rw",
+				"osd": "allow rw tag cephfs *=*",
+				"mgr": "allow rw",
+			}, nil
+		}
+		return map[string]string{
+			"mon": fmt.Sprintf("allow r fsname=%s", profile.Filesystem),
+			"mds": fmt.Sprintf("allow rw fsname=%s", profile.Filesystem),
+			"osd": fmt.Sprintf("allow rw tag cephfs data=%s", profile.Filesystem),
+			"mgr": "allow rw",
+		}, nil
+
+	case cephv1.CapsProfileRGWAdminOps:
+		// radosgw-admin reads and updates the realm configuration and the rgw pools
+		return map[string]string{
+			"mon": "allow rw",
+			"osd": "allow rwx",
+			"mgr": "allow rw",
+		}, nil
+
+	case cephv1.CapsProfileMonitoringReadOnly:
+		return map[string]string{
+			"mon": "allow r",
+			"mgr": "allow r",
+		}, nil
+	}
+
+	return nil, errors.Errorf("unknown caps profile %q", profile.Name)
+}
+
+// validateProfile checks that only the settings of the profile are set
+func validateProfile(profile *cephv1.ClientCapsProfileSpec) error {
+	if len(profile.Pools) > 0 && profile.Name != cephv1.CapsProfileRBDUser {
+		return errors.Errorf("pools can only be set with the %q caps profile", cephv1.CapsProfileRBDUser)
+	}
+	if profile.Filesystem != "" && profile.Name != cephv1.CapsProfileCephFSUser {
+		return errors.Errorf("filesystem can only be set with the %q caps profile", cephv1.CapsProfileCephFSUser)
+	}
+	_, err := profileCaps(profile, cephver.Minimum)
+	return err
+}
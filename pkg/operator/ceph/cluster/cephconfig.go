@@ -0,0 +1,129 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/operator/ceph/config"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// appliedCephConfigName is the configmap recording the ceph config of the cluster spec that was applied to the
+	// mon database, so that the options removed from the spec can be removed from the database
+	appliedCephConfigName = "rook-ceph-applied-config"
+	appliedCephConfigKey  = "config"
+	// appliedMsgr1DisabledKey records if the msgr1 port was disabled by the operator since msgr2 is required
+	appliedMsgr1DisabledKey = "msgr1Disabled"
+)
+
+// reconcileCephConfig sets the ceph config and the scrub scheduling of the cluster spec in the mon database, and
+// removes the options that were applied before but are not in the spec anymore. The options set in the database by
+// other means are kept.
+func (c *cluster) reconcileCephConfig() error {
+	cm, applied, err := c.getAppliedCephConfig()
+	if err != nil {
+		return err
+	}
+	desired := c.desiredCephConfig()
+
+	monStore := config.GetMonStore(c.context, c.ClusterInfo)
+	for section, options := range desired {
+		for option, value := range options {
+			if _, err := monStore.SetIfChanged(section, option, value); err != nil {
+				// the current value of some options cannot be read, e.g. the options of the "global" section
+				logger.Debugf("failed to check the ceph config option %q of section %q, setting it. %v", option, section, err)
+				if err := monStore.Set(section, option, value); err != nil {
+					return errors.Wrapf(err, "failed to set the ceph config option %q of section %q", option, section)
+				}
+			}
+		}
+	}
+	for section, options := range applied {
+		for option := range options {
+			if _, ok := desired[section][option]; ok {
+				continue
+			}
+			if err := monStore.Delete(section, option); err != nil {
+				return errors.Wrapf(err, "failed to remove the ceph config option %q of section %q", option, section)
+			}
+		}
+	}
+
+	if reflect.DeepEqual(applied, desired) || (len(applied) == 0 && len(desired) == 0) {
+		return nil
+	}
+	return c.saveAppliedCephConfig(cm, desired)
+}
+
+// getAppliedCephConfig returns the configmap of the applied ceph config, if it exists, and the applied ceph config
+func (c *cluster) getAppliedCephConfig() (*v1.ConfigMap, map[string]map[string]string, error) {
+	applied := map[string]map[string]string{}
+	cm, err := c.context.Clientset.CoreV1().ConfigMaps(c.Namespace).Get(c.ClusterInfo.Context, appliedCephConfigName, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, applied, nil
+		}
+		return nil, nil, errors.Wrapf(err, "failed to get configmap %q", appliedCephConfigName)
+	}
+	if data := cm.Data[appliedCephConfigKey]; data != "" {
+		if err := json.Unmarshal([]byte(data), &applied); err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to parse the applied ceph config in configmap %q", appliedCephConfigName)
+		}
+	}
+	return cm, applied, nil
+}
+
+// saveAppliedCephConfig records the ceph config applied from the cluster spec in the configmap of the applied ceph
+// config
+func (c *cluster) saveAppliedCephConfig(cm *v1.ConfigMap, applied map[string]map[string]string) error {
+	data, err := json.Marshal(applied)
+	if err != nil {
+		return errors.Wrap(err, "failed to serialize the ceph config")
+	}
+
+	if cm != nil {
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[appliedCephConfigKey] = string(data)
+		if _, err := c.context.Clientset.CoreV1().ConfigMaps(c.Namespace).Update(c.ClusterInfo.Context, cm, metav1.UpdateOptions{}); err != nil {
+			return errors.Wrapf(err, "failed to update configmap %q", appliedCephConfigName)
+		}
+		return nil
+	}
+
+	cm = &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      appliedCephConfigName,
+			Namespace: c.Namespace,
+		},
+		Data: map[string]string{appliedCephConfigKey: string(data)},
+	}
+	if err := c.ownerInfo.SetControllerReference(cm); err != nil {
+		return errors.Wrapf(err, "failed to set owner reference to configmap %q", appliedCephConfigName)
+	}
+	if _, err := c.context.Clientset.CoreV1().ConfigMaps(c.Namespace).Create(c.ClusterInfo.Context, cm, metav1.CreateOptions{}); err != nil {
+		return errors.Wrapf(err, "failed to create configmap %q", appliedCephConfigName)
+	}
+	return nil
+}
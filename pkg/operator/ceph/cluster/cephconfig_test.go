@@ -0,0 +1,137 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	testop "github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReconcileCephConfig(t *testing.T) {
+	db := map[string]string{}
+	commands := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			if args[0] != "config" {
+				return "", errors.Errorf("unexpected ceph command %q", args)
+			}
+			key := args[2] + "/" + args[3]
+			switch args[1] {
+			case "get":
+				if args[2] == "global" {
+					return "", errors.New("unrecognized entity 'global'")
+				}
+				return db[key], nil
+			case "set":
+				db[key] = args[4]
+			case "rm":
+				delete(db, key)
+			}
+			commands = append(commands, strings.Join(args[1:4], " "))
+			return "", nil
+		},
+	}
+	clientset := testop.New(t, 1)
+	c := &cluster{
+		context:     &clusterd.Context{Executor: executor, Clientset: clientset},
+		ClusterInfo: cephclient.AdminTestClusterInfo("ns"),
+		Namespace:   "ns",
+		ownerInfo:   k8sutil.NewOwnerInfoWithOwnerRef(&metav1.OwnerReference{UID: "test-id"}, ""),
+		Spec:        &cephv1.ClusterSpec{},
+	}
+
+	t.Run("no config", func(t *testing.T) {
+		assert.NoError(t, c.reconcileCephConfig())
+		assert.Empty(t, commands)
+		_, err := clientset.CoreV1().ConfigMaps("ns").Get(c.ClusterInfo.Context, appliedCephConfigName, metav1.GetOptions{})
+		assert.Error(t, err)
+	})
+
+	t.Run("options are set", func(t *testing.T) {
+		c.Spec.CephConfig = map[string]map[string]string{
+			"global": {"osd_pool_default_size": "2"},
+			"osd":    {"osd_max_backfills": "2", "osd_recovery_sleep": "0.1"},
+		}
+		assert.NoError(t, c.reconcileCephConfig())
+		assert.Equal(t, map[string]string{"global/osd_pool_default_size": "2", "osd/osd_max_backfills": "2", "osd/osd_recovery_sleep": "0.1"}, db)
+		cm, err := clientset.CoreV1().ConfigMaps("ns").Get(c.ClusterInfo.Context, appliedCephConfigName, metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Contains(t, cm.Data[appliedCephConfigKey], "osd_max_backfills")
+	})
+
+	t.Run("unchanged options are not set again", func(t *testing.T) {
+		commands = []string{}
+		assert.NoError(t, c.reconcileCephConfig())
+		// the options of the global section cannot be read back
+		assert.Equal(t, []string{"set global osd_pool_default_size"}, commands)
+	})
+
+	t.Run("options removed from the spec are removed", func(t *testing.T) {
+		db["osd/osd_op_queue"] = "wpq" // set outside the spec
+		c.Spec.CephConfig = map[string]map[string]string{
+			"osd": {"osd_max_backfills": "4"},
+		}
+		assert.NoError(t, c.reconcileCephConfig())
+		assert.Equal(t, map[string]string{"osd/osd_max_backfills": "4", "osd/osd_op_queue": "wpq"}, db)
+
+		c.Spec.CephConfig = nil
+		assert.NoError(t, c.reconcileCephConfig())
+		assert.Equal(t, map[string]string{"osd/osd_op_queue": "wpq"}, db)
+		cm, err := clientset.CoreV1().ConfigMaps("ns").Get(c.ClusterInfo.Context, appliedCephConfigName, metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, "null", cm.Data[appliedCephConfigKey])
+	})
+}
+
+func TestDesiredCephConfigScrub(t *testing.T) {
+	beginHour, endHour, maxScrubs := 22, 6, 2
+	c := &cluster{Spec: &cephv1.ClusterSpec{}}
+	assert.Nil(t, c.desiredCephConfig())
+
+	c.Spec.Scrub = cephv1.ScrubSpec{
+		BeginHour:         &beginHour,
+		EndHour:           &endHour,
+		MaxScrubs:         &maxScrubs,
+		DeepScrubInterval: &metav1.Duration{Duration: 168 * time.Hour},
+	}
+	c.Spec.CephConfig = map[string]map[string]string{
+		"global": {"osd_pool_default_size": "2"},
+		"osd":    {"osd_max_scrubs": "3"},
+	}
+	assert.Equal(t, map[string]map[string]string{
+		"global": {"osd_pool_default_size": "2"},
+		"osd": {
+			"osd_scrub_begin_hour":    "22",
+			"osd_scrub_end_hour":      "6",
+			"osd_max_scrubs":          "3", // the ceph config takes precedence
+			"osd_deep_scrub_interval": "604800",
+		},
+	}, c.desiredCephConfig())
+	// the spec is not modified
+	assert.Equal(t, map[string]string{"osd_max_scrubs": "3"}, c.Spec.CephConfig["osd"])
+}
@@ -21,6 +21,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"reflect"
 	"strings"
 	"time"
 
@@ -28,6 +29,8 @@ import (
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/clusterd"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/daemon/ceph/preflight"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
 	"github.com/rook/rook/pkg/operator/ceph/config"
 	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
 	"github.com/rook/rook/pkg/operator/ceph/reporting"
@@ -36,6 +39,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -46,21 +50,40 @@ var (
 
 // cephStatusChecker aggregates the mon/cluster info needed to check the health of the monitors
 type cephStatusChecker struct {
-	context     *clusterd.Context
-	clusterInfo *cephclient.ClusterInfo
-	interval    *time.Duration
-	client      client.Client
-	isExternal  bool
+	context       *clusterd.Context
+	clusterInfo   *cephclient.ClusterInfo
+	interval      *time.Duration
+	client        client.Client
+	isExternal    bool
+	clockSkew     cephv1.ClockSkewHealthSpec
+	fullEmergency cephv1.FullEmergencySpec
+	mgrModules    cephv1.MgrModuleRecoverySpec
+	recorder      record.EventRecorder
+	// the warning events of the OSD PVCs are forwarded to the cluster since this time
+	osdVolumeEventsSince time.Time
+	// externalSpec is the spec of an external cluster, to reload the imported connection info
+	externalSpec *cephv1.ClusterSpec
+	// checkNodeClock runs the time-sync check on a node of the mons, the clocks of the nodes are not checked if nil
+	checkNodeClock func(node string) (preflight.Result, error)
+	// the last check of the clocks of the nodes, and the nodes whose clock was skewed
+	nodeClocksChecked time.Time
+	skewedNodes       []string
 }
 
 // newCephStatusChecker creates a new HealthChecker object
 func newCephStatusChecker(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, clusterSpec *cephv1.ClusterSpec) *cephStatusChecker {
 	c := &cephStatusChecker{
-		context:     context,
-		clusterInfo: clusterInfo,
-		interval:    &defaultStatusCheckInterval,
-		client:      context.Client,
-		isExternal:  clusterSpec.External.Enable,
+		context:       context,
+		clusterInfo:   clusterInfo,
+		interval:      &defaultStatusCheckInterval,
+		client:        context.Client,
+		isExternal:    clusterSpec.External.Enable,
+		clockSkew:     clusterSpec.HealthCheck.ClockSkew,
+		fullEmergency: clusterSpec.Storage.FullEmergency,
+		mgrModules:    clusterSpec.Mgr.ModuleRecovery,
+	}
+	if clusterSpec.External.Enable {
+		c.externalSpec = clusterSpec
 	}
 
 	// allow overriding the check interval with an env var on the operator
@@ -121,6 +144,15 @@ func (c *cephStatusChecker) checkStatus(ctx context.Context) {
 		reason = cephv1.ClusterConnectedReason
 	}
 
+	if c.isExternal {
+		if err := c.refreshExternalCredentials(ctx); err != nil {
+			logger.Errorf("failed to validate the imported credentials of the external cluster. %v", err)
+			status := cephStatusOnError(err.Error())
+			c.updateCephStatus(status, condition, reason, "Failed to validate the imported credentials of the external ceph cluster", v1.ConditionFalse)
+			return
+		}
+	}
+
 	// Check ceph's status
 	status, err = cephclient.StatusWithUser(c.context, c.clusterInfo)
 	if err != nil {
@@ -156,6 +188,34 @@ func (c *cephStatusChecker) checkStatus(ctx context.Context) {
 	c.configureHealthSettings(status)
 }
 
+// refreshExternalCredentials reloads the credentials imported to connect to the external cluster. When the connection
+// info is imported again, e.g. after the rotation of the key of the user, the connection config of the operator is
+// updated so that the new credentials are used, and validated, by the status check without restarting the operator.
+func (c *cephStatusChecker) refreshExternalCredentials(ctx context.Context) error {
+	if c.externalSpec == nil {
+		return nil
+	}
+	imported, _, _, err := opcontroller.CreateOrLoadClusterInfo(c.context, ctx, c.clusterInfo.Namespace, nil, c.externalSpec)
+	if err != nil {
+		return errors.Wrap(err, "failed to load the imported connection info")
+	}
+	if !cephclient.IsKeyringBase64Encoded(imported.CephCred.Secret) {
+		return errors.Errorf("invalid imported key for user %q", imported.CephCred.Username)
+	}
+	if imported.CephCred == c.clusterInfo.Cred() {
+		return nil
+	}
+
+	logger.Infof("imported credentials of the external cluster changed, connecting with user %q", imported.CephCred.Username)
+	c.clusterInfo.SetCred(imported.CephCred)
+	// the controllers must not keep connecting with the cached credentials
+	c.context.Cache.Invalidate(c.clusterInfo.Namespace)
+	if err := mon.WriteConnectionConfig(c.context, c.clusterInfo); err != nil {
+		return errors.Wrap(err, "failed to write the connection config")
+	}
+	return nil
+}
+
 func (c *cephStatusChecker) configureHealthSettings(status cephclient.CephStatus) {
 	// loop through the health codes and log what we find
 	for healthCode, check := range status.Health.Checks {
@@ -187,6 +247,11 @@ func (c *cephStatusChecker) updateCephStatus(status *cephclient.CephStatus, cond
 	}
 
 	// Update with Ceph Status
+	var previousUnsafePools []string
+	previousStatus := cephCluster.Status.CephStatus
+	if previousStatus != nil {
+		previousUnsafePools = previousStatus.UnsafePools
+	}
 	cephCluster.Status.CephStatus = toCustomResourceStatus(cephCluster.Status, status)
 
 	// versions store the ceph version of all the ceph daemons and overall cluster version
@@ -198,6 +263,32 @@ func (c *cephStatusChecker) updateCephStatus(status *cephclient.CephStatus, cond
 		cephCluster.Status.CephStatus.Versions = versions
 	}
 
+	// flag the pools that would lose data with any single OSD
+	unsafePools, err := cephclient.GetUnsafePools(c.context, c.clusterInfo)
+	if err != nil {
+		logger.Errorf("failed to check for pools that keep a single copy of the data. %v", err)
+		cephCluster.Status.CephStatus.UnsafePools = previousUnsafePools
+	} else {
+		if len(unsafePools) > 0 && !reflect.DeepEqual(unsafePools, previousUnsafePools) {
+			logger.Warningf("pools %v keep a single copy of the data and cannot tolerate the loss of any OSD", unsafePools)
+		}
+		cephCluster.Status.CephStatus.UnsafePools = unsafePools
+	}
+
+	// raise the Degraded condition before the clock skew of the mons disrupts the quorum
+	c.checkClockSkew(cephCluster)
+
+	// report the health checks of ceph in the Degraded condition, unless the status could not be retrieved
+	if conditionStatus == v1.ConditionTrue {
+		checkHealthDegraded(cephCluster, status)
+	}
+
+	if !c.isExternal {
+		c.checkFullEmergency(cephCluster, status, previousStatus)
+		c.checkMgrModules(cephCluster, status, previousStatus)
+		c.checkOSDVolumes(cephCluster)
+	}
+
 	// Update condition
 	logger.Debugf("updating ceph cluster %q status and condition to %+v, %v, %s, %s", clusterName.Namespace, status, conditionStatus, reason, message)
 	opcontroller.UpdateClusterCondition(c.context, cephCluster, c.clusterInfo.NamespacedName(), k8sutil.ObservedGenerationNotAvailable, condition, conditionStatus, reason, message, true)
@@ -29,6 +29,7 @@ import (
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/clusterd"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
 	"github.com/rook/rook/pkg/operator/ceph/version"
 	optest "github.com/rook/rook/pkg/operator/test"
 	exectest "github.com/rook/rook/pkg/util/exec/test"
@@ -133,6 +134,7 @@ func TestNewCephStatusChecker(t *testing.T) {
 	c := &clusterd.Context{}
 	time10s, err := time.ParseDuration("10s")
 	assert.NoError(t, err)
+	externalSpec := &cephv1.ClusterSpec{External: cephv1.ExternalSpec{Enable: true}, HealthCheck: cephv1.CephClusterHealthCheckSpec{DaemonHealth: cephv1.DaemonHealthSpec{Status: cephv1.HealthCheckSpec{Interval: &metav1.Duration{Duration: time10s}}}}}
 
 	type args struct {
 		context     *clusterd.Context
@@ -144,9 +146,9 @@ func TestNewCephStatusChecker(t *testing.T) {
 		args args
 		want *cephStatusChecker
 	}{
-		{"default-interval", args{c, clusterInfo, &cephv1.ClusterSpec{}}, &cephStatusChecker{c, clusterInfo, &defaultStatusCheckInterval, c.Client, false}},
-		{"10s-interval", args{c, clusterInfo, &cephv1.ClusterSpec{HealthCheck: cephv1.CephClusterHealthCheckSpec{DaemonHealth: cephv1.DaemonHealthSpec{Status: cephv1.HealthCheckSpec{Interval: &metav1.Duration{Duration: time10s}}}}}}, &cephStatusChecker{c, clusterInfo, &time10s, c.Client, false}},
-		{"10s-interval-external", args{c, clusterInfo, &cephv1.ClusterSpec{External: cephv1.ExternalSpec{Enable: true}, HealthCheck: cephv1.CephClusterHealthCheckSpec{DaemonHealth: cephv1.DaemonHealthSpec{Status: cephv1.HealthCheckSpec{Interval: &metav1.Duration{Duration: time10s}}}}}}, &cephStatusChecker{c, clusterInfo, &time10s, c.Client, true}},
+		{"default-interval", args{c, clusterInfo, &cephv1.ClusterSpec{}}, &cephStatusChecker{c, clusterInfo, &defaultStatusCheckInterval, c.Client, false, cephv1.ClockSkewHealthSpec{}, cephv1.FullEmergencySpec{}, cephv1.MgrModuleRecoverySpec{}, nil, time.Time{}, nil, nil, time.Time{}, nil}},
+		{"10s-interval", args{c, clusterInfo, &cephv1.ClusterSpec{HealthCheck: cephv1.CephClusterHealthCheckSpec{DaemonHealth: cephv1.DaemonHealthSpec{Status: cephv1.HealthCheckSpec{Interval: &metav1.Duration{Duration: time10s}}}}}}, &cephStatusChecker{c, clusterInfo, &time10s, c.Client, false, cephv1.ClockSkewHealthSpec{}, cephv1.FullEmergencySpec{}, cephv1.MgrModuleRecoverySpec{}, nil, time.Time{}, nil, nil, time.Time{}, nil}},
+		{"10s-interval-external", args{c, clusterInfo, externalSpec}, &cephStatusChecker{c, clusterInfo, &time10s, c.Client, true, cephv1.ClockSkewHealthSpec{}, cephv1.FullEmergencySpec{}, cephv1.MgrModuleRecoverySpec{}, nil, time.Time{}, externalSpec, nil, time.Time{}, nil}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -375,3 +377,44 @@ func TestGetRookPodsOnNode(t *testing.T) {
 	sort.Strings(podNames)
 	assert.Equal(t, expectedPodNames, podNames)
 }
+
+func TestRefreshExternalCredentials(t *testing.T) {
+	ctx := context.TODO()
+	clientset := optest.New(t, 1)
+	clusterInfo := cephclient.NewClusterInfo("ns", "test")
+	clusterInfo.CephCred = cephclient.CephCred{Username: "client.healthchecker", Secret: "QVFCMUxzMWtzL2hOR3hBQW5VOGZ1MnhTc3J5OE4zaXRiWG12WlE9PQ=="}
+	context := &clusterd.Context{Clientset: clientset, ConfigDir: t.TempDir(), Cache: clusterd.NewCache(time.Hour)}
+	c := newCephStatusChecker(context, clusterInfo, &cephv1.ClusterSpec{External: cephv1.ExternalSpec{Enable: true}})
+
+	// the connection info was not imported
+	assert.Error(t, c.refreshExternalCredentials(ctx))
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: opcontroller.AppName, Namespace: "ns"},
+		Data: map[string][]byte{
+			opcontroller.CephUsernameKey:   []byte("client.healthchecker"),
+			opcontroller.CephUserSecretKey: []byte(clusterInfo.CephCred.Secret),
+		},
+	}
+	secret, err := clientset.CoreV1().Secrets("ns").Create(ctx, secret, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	assert.NoError(t, c.refreshExternalCredentials(ctx))
+
+	// the rotated key is used, and the cluster info cached with the old key is invalidated
+	context.Cache.Set("ns", "clusterinfo", clusterInfo.Copy())
+	rotated := "QVFBYXpNMWtUTDJxQ0JBQXhCbW1WNmFqV0V5Z2RtS2dQWVVvTWc9PQ=="
+	secret.Data[opcontroller.CephUserSecretKey] = []byte(rotated)
+	_, err = clientset.CoreV1().Secrets("ns").Update(ctx, secret, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	assert.NoError(t, c.refreshExternalCredentials(ctx))
+	assert.Equal(t, rotated, clusterInfo.Cred().Secret)
+	_, cached := context.Cache.Get("ns", "clusterinfo")
+	assert.False(t, cached)
+
+	// an invalid key is reported
+	secret.Data[opcontroller.CephUserSecretKey] = []byte("not a key")
+	_, err = clientset.CoreV1().Secrets("ns").Update(ctx, secret, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	assert.Error(t, c.refreshExternalCredentials(ctx))
+	assert.Equal(t, rotated, clusterInfo.Cred().Secret)
+}
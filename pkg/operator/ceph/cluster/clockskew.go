@@ -0,0 +1,226 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// defaultClockSkewThreshold is half of the default mon_clock_drift_allowed of ceph, so that the skew is reported
+	// before ceph raises MON_CLOCK_SKEW
+	defaultClockSkewThreshold = 25 * time.Millisecond
+	// nodeClockCheckInterval is the interval of the checks of the clocks of the nodes of the mons, which run a job
+	// on each node
+	nodeClockCheckInterval = 10 * time.Minute
+	nodeClockCheckTimeout  = 2 * time.Minute
+)
+
+var (
+	monClockSkewSeconds = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rook_ceph_mon_clock_skew_seconds",
+		Help: "Absolute skew of the clock of the mon from the leader mon, as measured by the time checks of the mons",
+	}, []string{"namespace", "mon"})
+	monClockSkewThresholdSeconds = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rook_ceph_mon_clock_skew_threshold_seconds",
+		Help: "Clock skew of a mon above which the cluster is degraded",
+	}, []string{"namespace"})
+	nodeClockErrorSeconds = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rook_ceph_node_clock_error_seconds",
+		Help: "Error of the clock of the node of a mon from its NTP sources, as estimated by the NTP service of the node",
+	}, []string{"namespace", "node"})
+)
+
+func init() {
+	// the metrics are served by the metrics endpoint of the controller-runtime manager of the operator
+	metrics.Registry.MustRegister(monClockSkewSeconds, monClockSkewThresholdSeconds, nodeClockErrorSeconds)
+}
+
+// checkClockSkew reports the clock skew of the mons and the clock error of their nodes from the NTP sources in the
+// metrics, and sets the Degraded condition of the cluster when the skew of a mon or the error of a node exceeds the
+// threshold. The condition is cleared once all the mons and their nodes are within the threshold.
+func (c *cephStatusChecker) checkClockSkew(cephCluster *cephv1.CephCluster) {
+	namespace := c.clusterInfo.Namespace
+	if c.clockSkew.Disabled || c.isExternal {
+		monClockSkewSeconds.DeletePartialMatch(prometheus.Labels{"namespace": namespace})
+		monClockSkewThresholdSeconds.DeletePartialMatch(prometheus.Labels{"namespace": namespace})
+		nodeClockErrorSeconds.DeletePartialMatch(prometheus.Labels{"namespace": namespace})
+		return
+	}
+
+	status, err := cephclient.GetTimeSyncStatus(c.context, c.clusterInfo)
+	if err != nil {
+		logger.Errorf("failed to get the clock skew of the mons. %v", err)
+		return
+	}
+
+	threshold := defaultClockSkewThreshold
+	if c.clockSkew.Threshold != nil {
+		threshold = c.clockSkew.Threshold.Duration
+	}
+	monClockSkewThresholdSeconds.WithLabelValues(namespace).Set(threshold.Seconds())
+
+	// the mons removed from the cluster are removed from the metrics
+	monClockSkewSeconds.DeletePartialMatch(prometheus.Labels{"namespace": namespace})
+	skewedMons := []string{}
+	for name, monSkew := range status.TimeSkewStatus {
+		skew := math.Abs(monSkew.Skew)
+		monClockSkewSeconds.WithLabelValues(namespace, name).Set(skew)
+		if skew > threshold.Seconds() {
+			skewedMons = append(skewedMons, fmt.Sprintf("mon.%s (%s)", name, time.Duration(skew*float64(time.Second)).Round(time.Millisecond)))
+		}
+	}
+	sort.Strings(skewedMons)
+	skewedNodes := c.checkNodeClocks(threshold)
+
+	if len(skewedMons) > 0 || len(skewedNodes) > 0 {
+		messages := []string{}
+		if len(skewedMons) > 0 {
+			messages = append(messages, fmt.Sprintf("clock skew of %s exceeds %s", strings.Join(skewedMons, ", "), threshold))
+		}
+		if len(skewedNodes) > 0 {
+			messages = append(messages, fmt.Sprintf("clock of %s is not synchronized with the NTP sources within %s", strings.Join(skewedNodes, ", "), threshold))
+		}
+		message := fmt.Sprintf("%s, check the time synchronization of their nodes", strings.Join(messages, " and "))
+		logger.Warning(message)
+		setDegradedCondition(cephCluster, v1.ConditionTrue, cephv1.MonClockSkewReason, message)
+		return
+	}
+	if condition := findCondition(cephCluster.Status.Conditions, cephv1.ConditionDegraded); condition != nil && condition.Reason == cephv1.MonClockSkewReason {
+		logger.Info("clock skew of the mons is back within the threshold")
+		setDegradedCondition(cephCluster, v1.ConditionFalse, cephv1.MonClocksSynchronizedReason, fmt.Sprintf("clock skew of the mons is within %s", threshold))
+	}
+}
+
+// checkNodeClocks checks the clocks of the nodes of the mons against their NTP sources with the time-sync preflight
+// check, and returns the nodes whose clock is not synchronized or whose estimated error exceeds the threshold. Since
+// a job runs on each node, the nodes are checked at most every nodeClockCheckInterval, and the result of the last
+// check is returned in between.
+func (c *cephStatusChecker) checkNodeClocks(threshold time.Duration) []string {
+	if c.checkNodeClock == nil {
+		return nil
+	}
+	if !c.nodeClocksChecked.IsZero() && time.Since(c.nodeClocksChecked) < nodeClockCheckInterval {
+		return c.skewedNodes
+	}
+	c.nodeClocksChecked = time.Now()
+
+	nodes, err := c.monNodes()
+	if err != nil {
+		logger.Errorf("failed to get the nodes of the mons to check their clocks. %v", err)
+		return c.skewedNodes
+	}
+
+	namespace := c.clusterInfo.Namespace
+	// the nodes without mons anymore are removed from the metrics
+	nodeClockErrorSeconds.DeletePartialMatch(prometheus.Labels{"namespace": namespace})
+	skewedNodes := []string{}
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for _, node := range nodes {
+		wg.Add(1)
+		go func(node string) {
+			defer wg.Done()
+			result, err := c.checkNodeClock(node)
+			if err != nil {
+				// the node is not reported as skewed if its clock cannot be checked
+				logger.Errorf("failed to check the clock of node %q. %v", node, err)
+				return
+			}
+			mutex.Lock()
+			defer mutex.Unlock()
+			if result.ClockErrorSeconds == nil {
+				logger.Warningf("clock of node %q is not synchronized. %v", node, result.Failures)
+				skewedNodes = append(skewedNodes, fmt.Sprintf("node %q (not synchronized)", node))
+				return
+			}
+			clockError := math.Abs(*result.ClockErrorSeconds)
+			nodeClockErrorSeconds.WithLabelValues(namespace, node).Set(clockError)
+			if clockError > threshold.Seconds() {
+				skewedNodes = append(skewedNodes, fmt.Sprintf("node %q (%s)", node, time.Duration(clockError*float64(time.Second)).Round(time.Millisecond)))
+			}
+		}(node)
+	}
+	wg.Wait()
+
+	sort.Strings(skewedNodes)
+	c.skewedNodes = skewedNodes
+	return skewedNodes
+}
+
+// monNodes returns the nodes where the mon pods are running
+func (c *cephStatusChecker) monNodes() ([]string, error) {
+	opts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", k8sutil.AppAttr, mon.AppName)}
+	pods, err := c.context.Clientset.CoreV1().Pods(c.clusterInfo.Namespace).List(c.clusterInfo.Context, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the mon pods")
+	}
+	nodes := []string{}
+	seen := map[string]bool{}
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName == "" || seen[pod.Spec.NodeName] {
+			continue
+		}
+		seen[pod.Spec.NodeName] = true
+		nodes = append(nodes, pod.Spec.NodeName)
+	}
+	sort.Strings(nodes)
+	return nodes, nil
+}
+
+// setDegradedCondition sets the Degraded condition of the cluster without changing its phase, since the cluster
+// keeps working
+func setDegradedCondition(cephCluster *cephv1.CephCluster, status v1.ConditionStatus, reason cephv1.ConditionReason, message string) {
+	now := metav1.NewTime(time.Now())
+	condition := findCondition(cephCluster.Status.Conditions, cephv1.ConditionDegraded)
+	if condition == nil {
+		cephCluster.Status.Conditions = append(cephCluster.Status.Conditions, cephv1.Condition{Type: cephv1.ConditionDegraded})
+		condition = &cephCluster.Status.Conditions[len(cephCluster.Status.Conditions)-1]
+	}
+	// the message changes with the skew of the mons, only the status is a transition
+	if condition.Status != status {
+		condition.LastTransitionTime = now
+	}
+	condition.Status = status
+	condition.Reason = reason
+	condition.Message = message
+	condition.LastHeartbeatTime = now
+}
+
+func findCondition(conditions []cephv1.Condition, conditionType cephv1.ConditionType) *cephv1.Condition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/daemon/ceph/preflight"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCheckClockSkew(t *testing.T) {
+	timeSyncStatus := ""
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "time-sync-status" {
+				return timeSyncStatus, nil
+			}
+			return "", nil
+		},
+	}
+	c := &cephStatusChecker{
+		context:     &clusterd.Context{Executor: executor},
+		clusterInfo: cephclient.AdminTestClusterInfo("rook-ceph"),
+	}
+	cephCluster := &cephv1.CephCluster{}
+	cephCluster.Status.Conditions = []cephv1.Condition{{Type: cephv1.ConditionReady, Status: v1.ConditionTrue, Reason: cephv1.ClusterCreatedReason}}
+	cephCluster.Status.Phase = cephv1.ConditionReady
+
+	// the mons are synchronized
+	timeSyncStatus = `{"time_skew_status":{"a":{"skew":0,"latency":0,"health":"HEALTH_OK"},"b":{"skew":-0.004,"latency":0.0005,"health":"HEALTH_OK"}}}`
+	c.checkClockSkew(cephCluster)
+	assert.Nil(t, findCondition(cephCluster.Status.Conditions, cephv1.ConditionDegraded))
+	assert.Equal(t, 0.004, testutil.ToFloat64(monClockSkewSeconds.WithLabelValues("rook-ceph", "b")))
+	assert.Equal(t, 0.025, testutil.ToFloat64(monClockSkewThresholdSeconds.WithLabelValues("rook-ceph")))
+
+	// the skew of a mon exceeds the threshold
+	timeSyncStatus = `{"time_skew_status":{"a":{"skew":0,"latency":0,"health":"HEALTH_OK"},"b":{"skew":-0.031,"latency":0.0005,"health":"HEALTH_OK"}}}`
+	c.checkClockSkew(cephCluster)
+	condition := findCondition(cephCluster.Status.Conditions, cephv1.ConditionDegraded)
+	assert.NotNil(t, condition)
+	assert.Equal(t, v1.ConditionTrue, condition.Status)
+	assert.Equal(t, cephv1.MonClockSkewReason, condition.Reason)
+	assert.Equal(t, "clock skew of mon.b (31ms) exceeds 25ms, check the time synchronization of their nodes", condition.Message)
+	// the phase of the cluster is unchanged
+	assert.Equal(t, cephv1.ConditionReady, cephCluster.Status.Phase)
+	assert.Len(t, cephCluster.Status.Conditions, 2)
+
+	// the skew is within a higher threshold
+	c.clockSkew.Threshold = &metav1.Duration{Duration: 40 * time.Millisecond}
+	c.checkClockSkew(cephCluster)
+	condition = findCondition(cephCluster.Status.Conditions, cephv1.ConditionDegraded)
+	assert.Equal(t, v1.ConditionFalse, condition.Status)
+	assert.Equal(t, cephv1.MonClocksSynchronizedReason, condition.Reason)
+	assert.Len(t, cephCluster.Status.Conditions, 2)
+
+	// the metrics of a removed mon are removed
+	timeSyncStatus = `{"time_skew_status":{"a":{"skew":0,"latency":0,"health":"HEALTH_OK"}}}`
+	c.checkClockSkew(cephCluster)
+	assert.Equal(t, 1, testutil.CollectAndCount(monClockSkewSeconds))
+
+	// the monitoring is disabled
+	c.clockSkew.Disabled = true
+	c.checkClockSkew(cephCluster)
+	assert.Equal(t, 0, testutil.CollectAndCount(monClockSkewSeconds))
+	assert.Equal(t, 0, testutil.CollectAndCount(monClockSkewThresholdSeconds))
+}
+
+func TestCheckNodeClocks(t *testing.T) {
+	monPod := func(name, node string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "rook-ceph", Labels: map[string]string{"app": "rook-ceph-mon"}},
+			Spec:       v1.PodSpec{NodeName: node},
+		}
+	}
+	clientset := fake.NewSimpleClientset(monPod("mon-a", "node-a"), monPod("mon-b", "node-b"), monPod("mon-c", "node-c"))
+	timeSyncStatus := `{"time_skew_status":{"a":{"skew":0,"latency":0,"health":"HEALTH_OK"}}}`
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			return timeSyncStatus, nil
+		},
+	}
+	clockErrors := map[string]float64{"node-a": 0.001, "node-b": 0.002, "node-c": 0.003}
+	var checkedNodes int32
+	c := &cephStatusChecker{
+		context:     &clusterd.Context{Executor: executor, Clientset: clientset},
+		clusterInfo: cephclient.AdminTestClusterInfo("rook-ceph"),
+		checkNodeClock: func(node string) (preflight.Result, error) {
+			atomic.AddInt32(&checkedNodes, 1)
+			if node == "node-c" {
+				return preflight.Result{}, errors.New("job failed")
+			}
+			clockError, ok := clockErrors[node]
+			if !ok {
+				return preflight.Result{Failures: []string{"the clock is not synchronized"}}, nil
+			}
+			return preflight.Result{ClockErrorSeconds: &clockError}, nil
+		},
+	}
+	cephCluster := &cephv1.CephCluster{}
+
+	// the clocks of the nodes are synchronized, the node whose clock cannot be checked is not skewed
+	c.checkClockSkew(cephCluster)
+	assert.Nil(t, findCondition(cephCluster.Status.Conditions, cephv1.ConditionDegraded))
+	assert.Equal(t, int32(3), checkedNodes)
+	assert.Equal(t, 0.002, testutil.ToFloat64(nodeClockErrorSeconds.WithLabelValues("rook-ceph", "node-b")))
+
+	// the nodes are not checked again before the interval
+	clockErrors["node-b"] = 0.030
+	c.checkClockSkew(cephCluster)
+	assert.Equal(t, int32(3), checkedNodes)
+	assert.Nil(t, findCondition(cephCluster.Status.Conditions, cephv1.ConditionDegraded))
+
+	// the error of a node exceeds the threshold and another node is not synchronized
+	delete(clockErrors, "node-a")
+	c.nodeClocksChecked = time.Now().Add(-nodeClockCheckInterval)
+	c.checkClockSkew(cephCluster)
+	assert.Equal(t, int32(6), checkedNodes)
+	condition := findCondition(cephCluster.Status.Conditions, cephv1.ConditionDegraded)
+	assert.Equal(t, v1.ConditionTrue, condition.Status)
+	assert.Equal(t, cephv1.MonClockSkewReason, condition.Reason)
+	assert.Equal(t, `clock of node "node-a" (not synchronized), node "node-b" (30ms) is not synchronized with the NTP sources within 25ms, check the time synchronization of their nodes`, condition.Message)
+
+	// the skewed nodes are still reported before the next check
+	c.checkClockSkew(cephCluster)
+	assert.Equal(t, int32(6), checkedNodes)
+	assert.Equal(t, v1.ConditionTrue, findCondition(cephCluster.Status.Conditions, cephv1.ConditionDegraded).Status)
+
+	// the monitoring is disabled
+	c.clockSkew.Disabled = true
+	c.checkClockSkew(cephCluster)
+	assert.Equal(t, 0, testutil.CollectAndCount(nodeClockErrorSeconds))
+}
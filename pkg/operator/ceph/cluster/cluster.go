@@ -25,6 +25,7 @@ import (
 	"strconv"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -67,6 +68,8 @@ type cluster struct {
 	isUpgrade          bool
 	monitoringRoutines map[string]*controller.ClusterHealth
 	observedGeneration int64
+	// requeueInterval is set by the orchestration steps waiting for the cluster without blocking the reconcile
+	requeueInterval time.Duration
 }
 
 func newCluster(ctx context.Context, c *cephv1.CephCluster, context *clusterd.Context, ownerInfo *k8sutil.OwnerInfo) *cluster {
@@ -90,6 +93,13 @@ func newCluster(ctx context.Context, c *cephv1.CephCluster, context *clusterd.Co
 	}
 }
 
+// requeueAfter requests the cluster to be reconciled again after the interval
+func (c *cluster) requeueAfter(interval time.Duration) {
+	if c.requeueInterval == 0 || interval < c.requeueInterval {
+		c.requeueInterval = interval
+	}
+}
+
 func (c *cluster) reconcileCephDaemons(rookImage string, cephVersion cephver.CephVersion) error {
 	// Create a configmap for overriding ceph config settings
 	// These settings should only be modified by a user after they are initialized
@@ -151,6 +161,11 @@ func (c *cluster) reconcileCephDaemons(rookImage string, cephVersion cephver.Cep
 		return errors.Wrap(err, "failed to start ceph osds")
 	}
 
+	// Apply the CRUSH tunables after the OSDs are created, since the change remaps their PGs
+	if err := c.reconcileCrushTunables(); err != nil {
+		return errors.Wrap(err, "failed to reconcile crush tunables")
+	}
+
 	// If a stretch cluster, enable the arbiter after the OSDs are created with the CRUSH map
 	if c.Spec.IsStretchCluster() {
 		if err := c.mons.ConfigureArbiter(); err != nil {
@@ -237,6 +252,22 @@ func (c *ClusterController) configureLocalCephCluster(cluster *cluster) error {
 		return errors.Wrap(err, "failed to perform validation before cluster creation")
 	}
 
+	// Prepare the dataDirHostPath on the nodes before the daemons start
+	if cluster.Spec.DataDirHostPathPreparation.Enabled {
+		controller.UpdateCondition(c.OpManagerCtx, c.context, c.namespacedName, k8sutil.ObservedGenerationNotAvailable, cephv1.ConditionProgressing, v1.ConditionTrue, cephv1.ClusterProgressingReason, "Preparing the dataDirHostPath of the nodes")
+		if err := cluster.prepareDataDirHostPath(c.rookImage); err != nil {
+			return errors.Wrap(err, "failed to prepare the dataDirHostPath of the nodes")
+		}
+	}
+
+	// Validate the nodes of a new cluster
+	if cluster.Spec.Preflight.Enabled {
+		controller.UpdateCondition(c.OpManagerCtx, c.context, c.namespacedName, k8sutil.ObservedGenerationNotAvailable, cephv1.ConditionProgressing, v1.ConditionTrue, cephv1.ClusterProgressingReason, "Running preflight checks")
+		if err := cluster.runPreflightChecks(c.rookImage); err != nil {
+			return errors.Wrap(err, "failed the preflight checks of the nodes")
+		}
+	}
+
 	// Run image validation job
 	controller.UpdateCondition(c.OpManagerCtx, c.context, c.namespacedName, k8sutil.ObservedGenerationNotAvailable, cephv1.ConditionProgressing, v1.ConditionTrue, cephv1.ClusterProgressingReason, "Detecting Ceph version")
 	cephVersion, isUpgrade, err := c.detectAndValidateCephVersion(cluster)
@@ -290,6 +321,15 @@ func preClusterStartValidation(cluster *cluster) error {
 	if err := validateStretchCluster(cluster); err != nil {
 		return err
 	}
+	if err := cephv1.ValidateCephConfig(cluster.Spec.CephConfig); err != nil {
+		return err
+	}
+	if err := cluster.Spec.Storage.ValidateDriveGroups(); err != nil {
+		return err
+	}
+	if err := controller.ValidateKeyringEncryption(cluster.Spec); err != nil {
+		return err
+	}
 	if cluster.Spec.Network.IsMultus() {
 		_, isPublic := cluster.Spec.Network.Selectors[config.PublicNetworkSelectorKeyName]
 		_, isCluster := cluster.Spec.Network.Selectors[config.ClusterNetworkSelectorKeyName]
@@ -329,6 +369,10 @@ func preClusterStartValidation(cluster *cluster) error {
 		}
 	}
 
+	if cluster.Spec.Storage.IsOnHostEncrypted() && cluster.Spec.Security.KeyManagementService.IsEnabled() {
+		logger.Warning("kms is configured but host-based osds with \"encryptedDevice\" store their encryption keys in the ceph monitors, only encrypted storageClassDeviceSets use the kms")
+	}
+
 	logger.Debug("cluster spec successfully validated")
 	return nil
 }
@@ -344,6 +388,7 @@ func validateStretchCluster(cluster *cluster) error {
 		return errors.Errorf("invalid number of mons %d for a stretch cluster, expecting 5 (recommended) or 3 (minimal)", cluster.Spec.Mon.Count)
 	}
 	arbitersFound := 0
+	zoneNames := map[string]bool{}
 	for _, zone := range cluster.Spec.Mon.StretchCluster.Zones {
 		if zone.Arbiter {
 			arbitersFound++
@@ -351,6 +396,11 @@ func validateStretchCluster(cluster *cluster) error {
 		if zone.Name == "" {
 			return errors.New("missing zone name for the stretch cluster")
 		}
+		// the mons of a duplicated zone would be placed in the same site, which would not survive a site failure
+		if zoneNames[zone.Name] {
+			return errors.Errorf("duplicate zone %q for the stretch cluster", zone.Name)
+		}
+		zoneNames[zone.Name] = true
 	}
 	if arbitersFound != 1 {
 		return errors.Errorf("expecting to find exactly one arbiter zone, but found %d", arbitersFound)
@@ -528,6 +578,14 @@ func (c *cluster) postMonStartupActions() error {
 		return errors.Wrap(err, "failed to configure msgr2")
 	}
 
+	if err := c.reconcileCephConfig(); err != nil {
+		return errors.Wrap(err, "failed to reconcile the ceph config")
+	}
+
+	if err := c.reconcileFullRatios(); err != nil {
+		return errors.Wrap(err, "failed to set osd full ratios")
+	}
+
 	crushRoot := client.GetCrushRootFromSpec(c.Spec)
 	if crushRoot != "default" {
 		// Remove the root=default and replicated_rule which are created by
@@ -606,6 +664,7 @@ func (c *cluster) reportTelemetry() {
 func (c *cluster) configureMsgr2() error {
 	encryptionSetting := "secure"
 	rbdMapOptions := "rbd_default_map_options"
+	bindMsgr1 := "ms_bind_msgr1"
 	encryptionGlobalConfigSettings := map[string]string{
 		"ms_cluster_mode": encryptionSetting,
 		"ms_service_mode": encryptionSetting,
@@ -640,6 +699,33 @@ func (c *cluster) configureMsgr2() error {
 			}
 		}
 	}
+	// Stop the daemons from listening on the msgr1 port when requireMsgr2 is set, but not when msgr2 is only implied
+	// by the encryption or the compression. The setting is read by the daemons when they start, so it applies to the
+	// daemons restarted after it changed. It is only removed if it was set by the operator, so that a value set by
+	// the admin is kept.
+	appliedConfig := k8sutil.NewConfigMapKVStore(c.Namespace, c.context.Clientset, c.ownerInfo)
+	if c.Spec.Network.Connections != nil && c.Spec.Network.Connections.RequireMsgr2 {
+		logger.Infof("disabling the msgr1 port of the daemons since msgr2 is required")
+		if err := monStore.Set("global", bindMsgr1, "false"); err != nil {
+			return errors.Wrap(err, "failed to disable the msgr1 port")
+		}
+		if err := appliedConfig.SetValue(c.ClusterInfo.Context, appliedCephConfigName, appliedMsgr1DisabledKey, "true"); err != nil {
+			return errors.Wrap(err, "failed to record the disabled msgr1 port")
+		}
+	} else {
+		msgr1Disabled, err := appliedConfig.GetValue(c.ClusterInfo.Context, appliedCephConfigName, appliedMsgr1DisabledKey)
+		if err != nil && !kerrors.IsNotFound(err) {
+			return errors.Wrap(err, "failed to check if the msgr1 port was disabled")
+		}
+		if msgr1Disabled == "true" {
+			if err := monStore.Delete("global", bindMsgr1); err != nil {
+				return errors.Wrap(err, "failed to delete the msgr1 port setting")
+			}
+			if err := appliedConfig.SetValue(c.ClusterInfo.Context, appliedCephConfigName, appliedMsgr1DisabledKey, "false"); err != nil {
+				return errors.Wrap(err, "failed to record the enabled msgr1 port")
+			}
+		}
+	}
 	// Set network compression
 	if c.ClusterInfo.CephVersion.IsAtLeastQuincy() {
 		if c.Spec.Network.Connections == nil || c.Spec.Network.Connections.Compression == nil || !c.Spec.Network.Connections.Compression.Enabled {
@@ -62,6 +62,11 @@ func TestPreClusterStartValidation(t *testing.T) {
 			{Name: "b"},
 			{Name: "c"},
 		}}}}}}, true},
+		{"duplicate zone name", args{&cluster{ClusterInfo: cephclient.AdminTestClusterInfo("rook-ceph"), context: &clusterd.Context{Clientset: testop.New(t, 3)}, Spec: &cephv1.ClusterSpec{Mon: cephv1.MonSpec{Count: 3, StretchCluster: &cephv1.StretchClusterSpec{Zones: []cephv1.StretchClusterZoneSpec{
+			{Name: "a", Arbiter: true},
+			{Name: "b"},
+			{Name: "b"},
+		}}}}}}, true},
 		{"valid stretch cluster", args{&cluster{ClusterInfo: cephclient.AdminTestClusterInfo("rook-ceph"), context: &clusterd.Context{Clientset: testop.New(t, 3)}, Spec: &cephv1.ClusterSpec{Mon: cephv1.MonSpec{Count: 3, StretchCluster: &cephv1.StretchClusterSpec{Zones: []cephv1.StretchClusterZoneSpec{
 			{Name: "a", Arbiter: true},
 			{Name: "b"},
@@ -159,6 +164,7 @@ func TestPreMonChecks(t *testing.T) {
 func TestConfigureMsgr2(t *testing.T) {
 	type fields struct {
 		expectedGlobalConfigSettings map[string]string
+		expectedMsgr1Disabled        bool
 		cephVersion                  cephver.CephVersion
 		Spec                         *cephv1.ClusterSpec
 	}
@@ -232,11 +238,30 @@ func TestConfigureMsgr2(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "msgr2 required",
+			fields: fields{
+				expectedGlobalConfigSettings: map[string]string{
+					"rbd_default_map_options": "ms_mode=prefer-crc",
+				},
+				expectedMsgr1Disabled: true,
+				cephVersion:           cephver.CephVersion{Major: 17},
+				Spec: &cephv1.ClusterSpec{
+					Network: cephv1.NetworkSpec{
+						Connections: &cephv1.ConnectionsSpec{
+							RequireMsgr2: true,
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			var configFile *ini.File
+			msgr1Disabled := false
+			msgr1Enabled := false
 
 			clusterInfo := cephclient.AdminTestClusterInfo("rook-ceph")
 			clusterInfo.CephVersion = tt.fields.cephVersion
@@ -245,12 +270,19 @@ func TestConfigureMsgr2(t *testing.T) {
 				ClusterInfo: clusterInfo,
 				Namespace:   "rook-ceph",
 				Spec:        tt.fields.Spec,
+				ownerInfo:   cephclient.NewMinimumOwnerInfoWithOwnerRef(),
 				context: &clusterd.Context{
 					Clientset: testop.New(t, 3),
 					Executor: &exectest.MockExecutor{
 						MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
 							joinedArgs := strings.Join(args, " ")
 							switch {
+							case strings.HasPrefix(joinedArgs, "config set global ms_bind_msgr1 false"):
+								msgr1Disabled = true
+								return "", nil
+							case strings.HasPrefix(joinedArgs, "config rm global ms_bind_msgr1"):
+								msgr1Enabled = true
+								return "", nil
 							case strings.HasPrefix(joinedArgs, "config assimilate-conf"):
 								fs := flag.NewFlagSet("", flag.ContinueOnError)
 								inputFile := fs.String("i", "", "")
@@ -279,6 +311,9 @@ func TestConfigureMsgr2(t *testing.T) {
 
 			err := c.configureMsgr2()
 			require.NoError(t, err)
+			assert.Equal(t, tt.fields.expectedMsgr1Disabled, msgr1Disabled)
+			// the msgr1 port setting was not set by the operator, so it is kept
+			assert.False(t, msgr1Enabled)
 
 			if assert.Equal(t, tt.fields.expectedGlobalConfigSettings == nil, configFile == nil) {
 				return
@@ -293,6 +328,42 @@ func TestConfigureMsgr2(t *testing.T) {
 	}
 }
 
+func TestConfigureMsgr2RestoresMsgr1(t *testing.T) {
+	msgr1Enabled := false
+	clusterInfo := cephclient.AdminTestClusterInfo("rook-ceph")
+	clusterInfo.CephVersion = cephver.CephVersion{Major: 17}
+	c := &cluster{
+		ClusterInfo: clusterInfo,
+		Namespace:   "rook-ceph",
+		Spec:        &cephv1.ClusterSpec{Network: cephv1.NetworkSpec{Connections: &cephv1.ConnectionsSpec{RequireMsgr2: true}}},
+		ownerInfo:   cephclient.NewMinimumOwnerInfoWithOwnerRef(),
+		context: &clusterd.Context{
+			Clientset: testop.New(t, 3),
+			Executor: &exectest.MockExecutor{
+				MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+					if strings.HasPrefix(strings.Join(args, " "), "config rm global ms_bind_msgr1") {
+						msgr1Enabled = true
+					}
+					return "", nil
+				},
+			},
+		},
+	}
+
+	require.NoError(t, c.configureMsgr2())
+	assert.False(t, msgr1Enabled)
+
+	// the msgr1 port disabled by the operator is enabled again when msgr2 is not required anymore
+	c.Spec = &cephv1.ClusterSpec{}
+	require.NoError(t, c.configureMsgr2())
+	assert.True(t, msgr1Enabled)
+
+	// and is not removed again
+	msgr1Enabled = false
+	require.NoError(t, c.configureMsgr2())
+	assert.False(t, msgr1Enabled)
+}
+
 func TestTelemetry(t *testing.T) {
 	var expectedSettings map[string]string
 	context := &clusterd.Context{Clientset: testop.New(t, 3)}
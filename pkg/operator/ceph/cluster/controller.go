@@ -21,6 +21,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/coreos/pkg/capnslog"
 	"github.com/pkg/errors"
@@ -242,6 +243,9 @@ func (r *ReconcileCephCluster) reconcile(request reconcile.Request) (reconcile.R
 		return reconcile.Result{}, *cephCluster, errors.Wrap(err, "failed to get cephCluster")
 	}
 
+	// The cluster info and the ceph versions cached for the other controllers may change with the cluster
+	r.clusterController.context.Cache.Invalidate(request.Namespace)
+
 	// Set a finalizer so we can do cleanup before the object goes away
 	err = opcontroller.AddFinalizerIfNotPresent(r.opManagerContext, r.client, cephCluster)
 	if err != nil {
@@ -253,9 +257,26 @@ func (r *ReconcileCephCluster) reconcile(request reconcile.Request) (reconcile.R
 		return r.reconcileDelete(cephCluster)
 	}
 
+	// PAUSED: the admin is doing manual maintenance, the status is still updated by the health checks
+	if opcontroller.IsDoNotReconcile(cephCluster.GetLabels()) {
+		logger.Infof("skipping reconcile of cluster %q since it is labeled with %q", cephCluster.Name, opcontroller.DoNotReconcileLabelName)
+		r.clusterController.recorder.Event(cephCluster, corev1.EventTypeNormal, string(cephv1.ReconcilePausedReason),
+			fmt.Sprintf("reconcile is paused by the %q label", opcontroller.DoNotReconcileLabelName))
+		return reconcile.Result{}, *cephCluster, nil
+	}
+
+	// The storage nodes are updated with the onboarded nodes before the orchestration
+	if updated, err := r.reconcileNodeOnboarding(cephCluster); err != nil {
+		return reconcile.Result{}, *cephCluster, errors.Wrapf(err, "failed to onboard the nodes of cluster %q", cephCluster.Name)
+	} else if updated {
+		// the update of the storage nodes triggers a new reconcile
+		return reconcile.Result{}, *cephCluster, nil
+	}
+
 	// Do reconcile here!
 	ownerInfo := k8sutil.NewOwnerInfo(cephCluster, r.scheme)
-	if err := r.clusterController.reconcileCephCluster(cephCluster, ownerInfo); err != nil {
+	requeueInterval, err := r.clusterController.reconcileCephCluster(cephCluster, ownerInfo)
+	if err != nil {
 		// If the error has a context cancelled let's return a success result so that the controller can
 		// exit gracefully and the goroutine (the one the manager runs in) won't block retrying even if the parent context has been
 		// cancelled.
@@ -271,8 +292,8 @@ func (r *ReconcileCephCluster) reconcile(request reconcile.Request) (reconcile.R
 		return reconcile.Result{}, *cephCluster, errors.Wrapf(err, "failed to reconcile cluster %q", cephCluster.Name)
 	}
 
-	// Return and do not requeue
-	return reconcile.Result{}, *cephCluster, nil
+	// Requeue only if the orchestration is waiting for the cluster
+	return reconcile.Result{RequeueAfter: requeueInterval}, *cephCluster, nil
 }
 
 func (r *ReconcileCephCluster) reconcileDelete(cephCluster *cephv1.CephCluster) (reconcile.Result, cephv1.CephCluster, error) {
@@ -296,9 +317,15 @@ func (r *ReconcileCephCluster) reconcileDelete(cephCluster *cephv1.CephCluster)
 
 	doCleanup := true
 
+	// The cleanup jobs cannot be created in a terminating namespace
+	namespaceTerminating := opcontroller.IsNamespaceTerminating(r.opManagerContext, r.client, cephCluster.Namespace)
+	if namespaceTerminating && cephCluster.Spec.CleanupPolicy.HasDataDirCleanPolicy() {
+		logger.Warningf("skipping the cleanup of the data dirs on the hosts since namespace %q is terminating", cephCluster.Namespace)
+	}
+
 	// Start cluster clean up only if cleanupPolicy is applied to the ceph cluster
 	internalCtx := context.Context(r.opManagerContext)
-	if cephCluster.Spec.CleanupPolicy.HasDataDirCleanPolicy() && !cephCluster.Spec.External.Enable {
+	if cephCluster.Spec.CleanupPolicy.HasDataDirCleanPolicy() && !cephCluster.Spec.External.Enable && !namespaceTerminating {
 		monSecret, clusterFSID, err := r.clusterController.getCleanUpDetails(&cephCluster.Spec, cephCluster.Namespace)
 		if err != nil {
 			logger.Warningf("failed to get mon secret. skip cluster cleanup. remove finalizer. %v", err)
@@ -316,7 +343,7 @@ func (r *ReconcileCephCluster) reconcileDelete(cephCluster *cephv1.CephCluster)
 
 	if doCleanup {
 		// Run delete sequence
-		response, err := r.clusterController.requestClusterDelete(cephCluster)
+		response, err := r.clusterController.requestClusterDelete(cephCluster, namespaceTerminating)
 		if err != nil {
 			// If the cluster cannot be deleted, requeue the request for deletion to see if the conditions
 			// will eventually be satisfied such as the volumes being removed
@@ -343,10 +370,10 @@ func NewClusterController(context *clusterd.Context, rookImage string) *ClusterC
 	}
 }
 
-func (c *ClusterController) reconcileCephCluster(clusterObj *cephv1.CephCluster, ownerInfo *k8sutil.OwnerInfo) error {
+func (c *ClusterController) reconcileCephCluster(clusterObj *cephv1.CephCluster, ownerInfo *k8sutil.OwnerInfo) (time.Duration, error) {
 	if clusterObj.Spec.CleanupPolicy.HasDataDirCleanPolicy() {
 		logger.Infof("skipping orchestration for cluster object %q in namespace %q because its cleanup policy is set", clusterObj.Name, clusterObj.Namespace)
-		return nil
+		return 0, nil
 	}
 
 	cluster, ok := c.clusterMap[clusterObj.Namespace]
@@ -364,15 +391,19 @@ func (c *ClusterController) reconcileCephCluster(clusterObj *cephv1.CephCluster,
 
 	// Set the spec
 	cluster.Spec = &clusterObj.Spec
+	cluster.requeueInterval = 0
 
 	c.clusterMap[cluster.Namespace] = cluster
 	logger.Infof("reconciling ceph cluster in namespace %q", cluster.Namespace)
 
 	// Start the main ceph cluster orchestration
-	return c.initializeCluster(cluster)
+	if err := c.initializeCluster(cluster); err != nil {
+		return 0, err
+	}
+	return cluster.requeueInterval, nil
 }
 
-func (c *ClusterController) requestClusterDelete(cluster *cephv1.CephCluster) (reconcile.Result, error) {
+func (c *ClusterController) requestClusterDelete(cluster *cephv1.CephCluster, namespaceTerminating bool) (reconcile.Result, error) {
 	nsName := fmt.Sprintf("%s/%s", cluster.Namespace, cluster.Name)
 
 	if existing, ok := c.clusterMap[cluster.Namespace]; ok && existing.namespacedName.Name != cluster.Name {
@@ -401,6 +432,10 @@ func (c *ClusterController) requestClusterDelete(cluster *cephv1.CephCluster) (r
 
 	if cluster.Spec.CleanupPolicy.AllowUninstallWithVolumes {
 		logger.Info("skipping check for existing PVs as allowUninstallWithVolumes is set to true")
+	} else if namespaceTerminating {
+		// the daemons serving the volumes are deleted with the namespace, so waiting for the volumes would only leave
+		// the namespace stuck in terminating
+		logger.Warningf("skipping check for existing PVs since namespace %q is terminating", cluster.Namespace)
 	} else {
 		err := c.checkIfVolumesExist(cluster)
 		if err != nil {
@@ -0,0 +1,222 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+)
+
+const (
+	noRebalanceFlag     = "norebalance"
+	straw2PreviewSuffix = "+straw2"
+)
+
+// allow the requeue while the PGs peer to be overridden for unit testing
+var crushPeeringRequeueInterval = 5 * time.Second
+
+// reconcileCrushTunables applies the CRUSH tunables profile and the straw2 migration of the spec. Both remap
+// placement groups to other OSDs, which would move a large part of the data at once. Instead, the change is
+// applied with the "norebalance" flag, and the reconcile is requeued until the PGs are peered. The remapped PGs
+// are then pinned to their current OSDs with upmaps before the rebalance is resumed, and the balancer removes the
+// upmaps gradually, within its limit of misplaced objects. The number of remapped PGs is reported in the status
+// as a measure of the data movement, and can be previewed offline without changing the cluster.
+func (c *cluster) reconcileCrushTunables() error {
+	crushSpec := c.Spec.Crush
+
+	cephCluster := &cephv1.CephCluster{}
+	if err := c.context.Client.Get(c.ClusterInfo.Context, c.namespacedName, cephCluster); err != nil {
+		return errors.Wrap(err, "failed to get cluster to check the crush tunables status")
+	}
+	status := cephCluster.Status.CrushTunables
+	if status != nil && status.RebalancePaused {
+		// a change was applied by a previous reconcile, the rebalance is resumed once its PGs are peered
+		return c.resumeRebalance(status)
+	}
+
+	if crushSpec.TunablesProfile == "" && !crushSpec.MigrateToStraw2 {
+		return nil
+	}
+
+	currentProfile, err := cephclient.GetCrushTunablesProfile(c.context, c.ClusterInfo)
+	if err != nil {
+		return err
+	}
+	targetProfile := currentProfile
+	if crushSpec.TunablesProfile != "" {
+		targetProfile = crushSpec.TunablesProfile
+	}
+	changeProfile := targetProfile != currentProfile
+
+	migrateToStraw2 := false
+	if crushSpec.MigrateToStraw2 {
+		crushMap, err := cephclient.GetCrushMap(c.context, c.ClusterInfo)
+		if err != nil {
+			return errors.Wrap(err, "failed to get crush map to find straw buckets")
+		}
+		migrateToStraw2 = len(crushMap.StrawBuckets()) > 0
+	}
+
+	if !changeProfile && !migrateToStraw2 {
+		return nil
+	}
+	if crushSpec.PreviewOnly {
+		return c.previewCrushTunables(status, currentProfile, targetProfile, changeProfile, migrateToStraw2)
+	}
+
+	_, clean, err := cephclient.IsClusterClean(c.context, c.ClusterInfo)
+	if err != nil {
+		return errors.Wrap(err, "failed to check if the cluster is clean before changing the crush tunables")
+	}
+	if !clean {
+		logger.Info("waiting for all the PGs to be clean before changing the crush tunables")
+		return nil
+	}
+
+	if err := cephclient.SetOSDFlag(c.context, c.ClusterInfo, noRebalanceFlag); err != nil {
+		return err
+	}
+	// the paused rebalance is recorded before the change, so that it is resumed even if the operator restarts
+	err = c.updateCrushTunablesStatus(&cephv1.CrushTunablesStatus{
+		Profile:         currentProfile,
+		RebalancePaused: true,
+		LastChanged:     formatTime(time.Now().UTC()),
+	})
+	if err == nil {
+		err = c.applyCrushChange(currentProfile, targetProfile, changeProfile, migrateToStraw2)
+	}
+	if err != nil {
+		if unsetErr := cephclient.UnsetOSDFlag(c.context, c.ClusterInfo, noRebalanceFlag); unsetErr != nil {
+			logger.Errorf("failed to resume the rebalance after the failed crush change. %v", unsetErr)
+		}
+		if statusErr := c.updateCrushTunablesStatus(status); statusErr != nil {
+			logger.Errorf("failed to restore the crush tunables status after the failed crush change. %v", statusErr)
+		}
+		return err
+	}
+
+	logger.Info("waiting for the PGs to peer after the crush change before pinning the remapped PGs")
+	c.requeueAfter(crushPeeringRequeueInterval)
+	return c.updateCrushTunablesStatus(&cephv1.CrushTunablesStatus{
+		Profile:         targetProfile,
+		RebalancePaused: true,
+		LastChanged:     formatTime(time.Now().UTC()),
+	})
+}
+
+func (c *cluster) applyCrushChange(currentProfile, targetProfile string, changeProfile, migrateToStraw2 bool) error {
+	if changeProfile {
+		logger.Infof("changing crush tunables profile from %q to %q", currentProfile, targetProfile)
+		if err := cephclient.SetCrushTunablesProfile(c.context, c.ClusterInfo, targetProfile); err != nil {
+			return err
+		}
+	}
+	if migrateToStraw2 {
+		logger.Info("converting the straw buckets of the crush map to straw2")
+		if err := cephclient.SetAllStrawBucketsToStraw2(c.context, c.ClusterInfo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resumeRebalance pins the PGs remapped by the last crush change to their current OSDs once they are peered, so
+// the balancer moves them gradually, then resumes the rebalance
+func (c *cluster) resumeRebalance(status *cephv1.CrushTunablesStatus) error {
+	cephStatus, err := cephclient.Status(c.context, c.ClusterInfo)
+	if err != nil {
+		return errors.Wrap(err, "failed to get ceph status after the crush change")
+	}
+	if cephclient.IsPeeringInProgress(cephStatus) {
+		logger.Debug("waiting for the PGs to peer after the crush change")
+		c.requeueAfter(crushPeeringRequeueInterval)
+		return nil
+	}
+
+	pgs, err := cephclient.GetRemappedPGs(c.context, c.ClusterInfo)
+	if err != nil {
+		return err
+	}
+	pinnedPGs := 0
+	for _, pg := range pgs {
+		pinned, err := cephclient.PinPGToActingSet(c.context, c.ClusterInfo, pg)
+		if err != nil {
+			logger.Warningf("pg %q will be moved without throttling. %v", pg.PGID, err)
+			continue
+		}
+		if pinned {
+			pinnedPGs++
+		}
+	}
+	if err := cephclient.UnsetOSDFlag(c.context, c.ClusterInfo, noRebalanceFlag); err != nil {
+		return errors.Wrap(err, "failed to resume the rebalance after the crush change")
+	}
+	logger.Infof("crush change remapped %d PGs, %d of them are pinned to their current osds and will be moved gradually by the balancer", len(pgs), pinnedPGs)
+
+	return c.updateCrushTunablesStatus(&cephv1.CrushTunablesStatus{
+		Profile:     status.Profile,
+		RemappedPGs: len(pgs),
+		PinnedPGs:   pinnedPGs,
+		LastChanged: formatTime(time.Now().UTC()),
+	})
+}
+
+// previewCrushTunables counts the PGs that the change of the tunables profile and the straw2 migration would
+// remap. The change is applied to a copy of the OSD map, so the cluster is not changed.
+func (c *cluster) previewCrushTunables(status *cephv1.CrushTunablesStatus, currentProfile, targetProfile string, changeProfile, migrateToStraw2 bool) error {
+	previewedProfile := targetProfile
+	if migrateToStraw2 {
+		previewedProfile += straw2PreviewSuffix
+	}
+	if status != nil && status.PreviewedProfile == previewedProfile {
+		// the preview is only computed once for each change
+		return nil
+	}
+
+	profile := ""
+	if changeProfile {
+		profile = targetProfile
+	}
+	remappedPGs, err := cephclient.CountCrushChangeRemappedPGs(c.context, c.ClusterInfo, profile, migrateToStraw2)
+	if err != nil {
+		return errors.Wrap(err, "failed to preview the crush change")
+	}
+	logger.Infof("changing the crush tunables profile from %q to %q would remap %d PGs", currentProfile, previewedProfile, remappedPGs)
+
+	return c.updateCrushTunablesStatus(&cephv1.CrushTunablesStatus{
+		Profile:          currentProfile,
+		PreviewedProfile: previewedProfile,
+		RemappedPGs:      remappedPGs,
+		LastChanged:      formatTime(time.Now().UTC()),
+	})
+}
+
+func (c *cluster) updateCrushTunablesStatus(status *cephv1.CrushTunablesStatus) error {
+	cephCluster := &cephv1.CephCluster{}
+	if err := c.context.Client.Get(c.ClusterInfo.Context, c.namespacedName, cephCluster); err != nil {
+		return errors.Wrap(err, "failed to get cluster to update the crush tunables status")
+	}
+	cephCluster.Status.CrushTunables = status
+	if err := reporting.UpdateStatus(c.context.Client, cephCluster); err != nil {
+		return errors.Wrap(err, "failed to update the crush tunables status")
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileCrushTunables(t *testing.T) {
+	nsName := types.NamespacedName{Namespace: "rook-ceph", Name: "my-cluster"}
+	scheme := runtime.NewScheme()
+	assert.NoError(t, cephv1.AddToScheme(scheme))
+
+	const cleanStatus = `{"pgmap":{"num_pgs":2,"pgs_by_state":[{"state_name":"active+clean","count":2}]}}`
+	const peeringStatus = `{"pgmap":{"num_pgs":2,"pgs_by_state":[{"state_name":"peering","count":2}]}}`
+	const remappedPGs = `{"pg_stats":[{"pgid":"1.0","up":[0,1,2],"acting":[0,1,3]},{"pgid":"1.1","up":[1,2,4],"acting":[2,1,4]}]}`
+
+	newTestCluster := func(t *testing.T, spec cephv1.CrushSpec, status *string, commands *[]string) *cluster {
+		cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: nsName.Name, Namespace: nsName.Namespace}}
+		imported := false
+		mockCommand := func(command string, args ...string) (string, error) {
+			*commands = append(*commands, strings.Join(args, " "))
+			switch {
+			case command == "osdmaptool" && args[1] == "--import-crush":
+				imported = true
+			case command == "osdmaptool" && args[1] == "--test-map-pgs-dump":
+				if imported {
+					return "1.0\t[0,1,2]\t0\n1.1\t[1,2,4]\t1\n", nil
+				}
+				return "1.0\t[0,1,3]\t0\n1.1\t[2,1,4]\t2\n", nil
+			case args[0] == "status":
+				return *status, nil
+			case args[0] == "pg" && args[1] == "ls":
+				return remappedPGs, nil
+			case args[0] == "osd" && args[1] == "crush" && args[2] == "show-tunables":
+				return `{"profile":"firefly"}`, nil
+			case args[0] == "osd" && args[1] == "crush" && args[2] == "dump":
+				return `{"buckets":[{"name":"default","alg":"straw"}]}`, nil
+			}
+			return "", nil
+		}
+		executor := &exectest.MockExecutor{
+			MockExecuteCommandWithOutput: mockCommand,
+			MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+				return mockCommand(command, args...)
+			},
+		}
+		clusterInfo := cephclient.AdminTestClusterInfo(nsName.Namespace)
+		clusterInfo.Context = context.TODO()
+		return &cluster{
+			ClusterInfo:    clusterInfo,
+			context:        &clusterd.Context{Executor: executor, Client: clientfake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(cephCluster).Build()},
+			Namespace:      nsName.Namespace,
+			Spec:           &cephv1.ClusterSpec{Crush: spec},
+			namespacedName: nsName,
+		}
+	}
+	getStatus := func(t *testing.T, c *cluster) *cephv1.CrushTunablesStatus {
+		cephCluster := &cephv1.CephCluster{}
+		assert.NoError(t, c.context.Client.Get(context.TODO(), nsName, cephCluster))
+		return cephCluster.Status.CrushTunables
+	}
+
+	t.Run("no crush settings", func(t *testing.T) {
+		commands := []string{}
+		status := cleanStatus
+		c := newTestCluster(t, cephv1.CrushSpec{}, &status, &commands)
+		assert.NoError(t, c.reconcileCrushTunables())
+		assert.Empty(t, commands)
+	})
+
+	t.Run("profile is unchanged", func(t *testing.T) {
+		commands := []string{}
+		status := cleanStatus
+		c := newTestCluster(t, cephv1.CrushSpec{TunablesProfile: "firefly"}, &status, &commands)
+		assert.NoError(t, c.reconcileCrushTunables())
+		assert.Len(t, commands, 1)
+		assert.Nil(t, getStatus(t, c))
+	})
+
+	t.Run("wait for the cluster to be clean", func(t *testing.T) {
+		commands := []string{}
+		status := `{"pgmap":{"num_pgs":2,"pgs_by_state":[{"state_name":"active+recovering","count":2}]}}`
+		c := newTestCluster(t, cephv1.CrushSpec{TunablesProfile: "optimal"}, &status, &commands)
+		assert.NoError(t, c.reconcileCrushTunables())
+		assert.NotContains(t, strings.Join(commands, ";"), "osd crush tunables")
+	})
+
+	t.Run("profile is changed and the remapped pgs are pinned", func(t *testing.T) {
+		commands := []string{}
+		status := cleanStatus
+		c := newTestCluster(t, cephv1.CrushSpec{TunablesProfile: "optimal", MigrateToStraw2: true}, &status, &commands)
+		assert.NoError(t, c.reconcileCrushTunables())
+		all := strings.Join(commands, ";")
+		assert.Contains(t, all, "osd set norebalance")
+		assert.Contains(t, all, "osd crush tunables optimal")
+		assert.Contains(t, all, "osd crush set-all-straw-buckets-to-straw2")
+		assert.NotContains(t, all, "pg-upmap-items")
+		assert.NotContains(t, all, "osd unset norebalance")
+		assert.Equal(t, crushPeeringRequeueInterval, c.requeueInterval)
+		assert.True(t, getStatus(t, c).RebalancePaused)
+
+		// the rebalance is not resumed while the pgs are peering
+		commands = []string{}
+		status = peeringStatus
+		c.requeueInterval = 0
+		assert.NoError(t, c.reconcileCrushTunables())
+		all = strings.Join(commands, ";")
+		assert.NotContains(t, all, "osd crush tunables")
+		assert.NotContains(t, all, "pg-upmap-items")
+		assert.NotContains(t, all, "osd unset norebalance")
+		assert.Equal(t, crushPeeringRequeueInterval, c.requeueInterval)
+
+		// the remapped pgs are pinned once peered
+		commands = []string{}
+		status = cleanStatus
+		c.requeueInterval = 0
+		assert.NoError(t, c.reconcileCrushTunables())
+		all = strings.Join(commands, ";")
+		assert.Contains(t, all, "osd pg-upmap-items 1.0 2 3")
+		assert.NotContains(t, all, "osd pg-upmap-items 1.1")
+		assert.Contains(t, all, "osd unset norebalance")
+		assert.True(t, strings.Index(all, "osd pg-upmap-items") < strings.Index(all, "osd unset norebalance"))
+		assert.Zero(t, c.requeueInterval)
+
+		crushStatus := getStatus(t, c)
+		assert.Equal(t, "optimal", crushStatus.Profile)
+		assert.Equal(t, 2, crushStatus.RemappedPGs)
+		assert.Equal(t, 1, crushStatus.PinnedPGs)
+		assert.False(t, crushStatus.RebalancePaused)
+	})
+
+	t.Run("preview does not change the cluster", func(t *testing.T) {
+		commands := []string{}
+		status := cleanStatus
+		c := newTestCluster(t, cephv1.CrushSpec{TunablesProfile: "optimal", PreviewOnly: true}, &status, &commands)
+		assert.NoError(t, c.reconcileCrushTunables())
+		all := strings.Join(commands, ";")
+		assert.Contains(t, all, "osd getmap")
+		assert.NotContains(t, all, "osd crush tunables")
+		assert.NotContains(t, all, "norebalance")
+		assert.NotContains(t, all, "pg-upmap-items")
+
+		crushStatus := getStatus(t, c)
+		assert.Equal(t, "firefly", crushStatus.Profile)
+		assert.Equal(t, "optimal", crushStatus.PreviewedProfile)
+		assert.Equal(t, 2, crushStatus.RemappedPGs)
+
+		// the preview is not computed again
+		commands = []string{}
+		assert.NoError(t, c.reconcileCrushTunables())
+		assert.NotContains(t, strings.Join(commands, ";"), "osd getmap")
+	})
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	osdFullCheck         = "OSD_FULL"
+	osdBackfillFullCheck = "OSD_BACKFILLFULL"
+	osdNearFullCheck     = "OSD_NEARFULL"
+	pgAutoscaleModeOff   = "off"
+	noPGChangeFlag       = "nopgchange"
+	noSizeChangeFlag     = "nosizechange"
+
+	reclaimSpaceGuidance = "Reclaim space by deleting unneeded data, snapshots or images (deletes are allowed while the OSDs are full), " +
+		"or add OSDs to the cluster. As a last resort, raise storage.fullRatio temporarily (at most 0.97) for the deletes that need space."
+)
+
+// reconcileFullRatios sets the full, backfillfull and nearfull ratios of the spec in the OSD map. The ratios that
+// are not set in the spec keep their current value, and must remain in increasing order with the others.
+func (c *cluster) reconcileFullRatios() error {
+	storage := c.Spec.Storage
+	if storage.FullRatio == nil && storage.BackfillFullRatio == nil && storage.NearFullRatio == nil {
+		return nil
+	}
+
+	osdDump, err := cephclient.GetOSDDump(c.context, c.ClusterInfo)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the current osd ratios")
+	}
+	ratios := []struct {
+		name    string
+		desired *float64
+		current float64
+	}{
+		{"nearfull", storage.NearFullRatio, osdDump.NearFullRatio},
+		{"backfillfull", storage.BackfillFullRatio, osdDump.BackfillFullRatio},
+		{"full", storage.FullRatio, osdDump.FullRatio},
+	}
+
+	// ceph accepts the ratios out of order, but then raises the OSD_OUT_OF_ORDER_FULL health error
+	effective := make([]float64, len(ratios))
+	for i, ratio := range ratios {
+		effective[i] = ratio.current
+		if ratio.desired != nil {
+			effective[i] = *ratio.desired
+		}
+	}
+	if effective[0] > effective[1] || effective[1] > effective[2] {
+		return errors.Errorf("nearfull ratio %v, backfillfull ratio %v and full ratio %v must be in increasing order", effective[0], effective[1], effective[2])
+	}
+
+	for _, ratio := range ratios {
+		if ratio.desired == nil || *ratio.desired == ratio.current {
+			continue
+		}
+		logger.Infof("setting osd %s ratio from %v to %v", ratio.name, ratio.current, *ratio.desired)
+		if err := cephclient.SetOSDRatio(c.context, c.ClusterInfo, ratio.name, *ratio.desired); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkFullEmergency handles the OSDs that are nearly full or full. Warning events are raised on the CephCluster
+// with the steps to reclaim space, and the emergency is reported in the status while the OSDs are full. The pools
+// are put in a safe state during the emergency if they are protected.
+func (c *cephStatusChecker) checkFullEmergency(cephCluster *cephv1.CephCluster, status *cephclient.CephStatus, previousStatus *cephv1.CephStatus) {
+	var previousEmergency *cephv1.FullEmergencyStatus
+	previousChecks := map[string]cephv1.CephHealthMessage{}
+	if previousStatus != nil {
+		previousEmergency = previousStatus.FullEmergency
+		previousChecks = previousStatus.Details
+	}
+	cephCluster.Status.CephStatus.FullEmergency = previousEmergency
+
+	fullCheck, full := status.Health.Checks[osdFullCheck]
+	if !c.fullEmergency.Enabled || !full {
+		if previousEmergency != nil {
+			c.endFullEmergency(cephCluster, previousEmergency, !full)
+		}
+		if !c.fullEmergency.Enabled {
+			return
+		}
+	}
+
+	for _, checkName := range []string{osdNearFullCheck, osdBackfillFullCheck} {
+		check, ok := status.Health.Checks[checkName]
+		if _, wasRaised := previousChecks[checkName]; ok && !wasRaised {
+			c.recordEvent(cephCluster, v1.EventTypeWarning, cephv1.ClusterNearFullReason,
+				fmt.Sprintf("%s. The writes will be blocked when the OSDs are full. %s", check.Summary.Message, reclaimSpaceGuidance))
+		}
+	}
+
+	if !full {
+		return
+	}
+	if previousEmergency == nil {
+		logger.Errorf("cluster is full, the writes are blocked. %s", fullCheck.Summary.Message)
+		cephCluster.Status.CephStatus.FullEmergency = &cephv1.FullEmergencyStatus{
+			Since:   formatTime(time.Now().UTC()),
+			Message: fullCheck.Summary.Message,
+		}
+		c.recordEvent(cephCluster, v1.EventTypeWarning, cephv1.ClusterFullReason,
+			fmt.Sprintf("%s and the writes to the cluster are blocked. %s", fullCheck.Summary.Message, reclaimSpaceGuidance))
+	}
+	emergency := cephCluster.Status.CephStatus.FullEmergency
+	emergency.Message = fullCheck.Summary.Message
+
+	if c.fullEmergency.ProtectPools {
+		c.protectPools(emergency)
+	}
+}
+
+// protectPools puts the pools in a safe state during the emergency. The PG autoscaler of the pools is turned off
+// and the nopgchange and nosizechange flags are set on the pools, so that no PG is split or merged and no replica
+// is added, which would need space. The norebalance flag of the OSDs is set so that the misplaced data is not moved
+// to the OSDs that still have space, while the degraded data is still recovered. The changes are recorded in the
+// emergency to be reverted when it ends, and the pools created during the emergency are protected at the next check.
+func (c *cephStatusChecker) protectPools(emergency *cephv1.FullEmergencyStatus) {
+	osdDump, err := cephclient.GetOSDDump(c.context, c.clusterInfo)
+	if err != nil {
+		logger.Errorf("failed to list the pools to protect. %v", err)
+		return
+	}
+
+	if !emergency.NoRebalance && !osdDump.IsFlagSet(noRebalanceFlag) {
+		if err := cephclient.SetOSDFlag(c.context, c.clusterInfo, noRebalanceFlag); err != nil {
+			logger.Errorf("failed to stop the rebalancing of the osds. %v", err)
+		} else {
+			emergency.NoRebalance = true
+		}
+	}
+
+	// the pools already protected may not be seen with their changes yet
+	protected := sets.New(emergency.ProtectedPools...)
+	frozen := sets.New(emergency.FrozenPools...)
+	for _, pool := range osdDump.Pools {
+		if pool.PgAutoscaleMode == cephclient.PgAutoscaleModeOn && !protected.Has(pool.Name) {
+			if err := cephclient.SetPoolProperty(c.context, c.clusterInfo, pool.Name, cephclient.PgAutoscaleModeProperty, pgAutoscaleModeOff); err != nil {
+				logger.Errorf("failed to turn off the pg autoscaler of pool %q. %v", pool.Name, err)
+			} else {
+				emergency.ProtectedPools = append(emergency.ProtectedPools, pool.Name)
+			}
+		}
+
+		// the pools with a flag set by the admin are left as they are
+		if frozen.Has(pool.Name) || poolFlagSet(pool.FlagsNames, noPGChangeFlag) || poolFlagSet(pool.FlagsNames, noSizeChangeFlag) {
+			continue
+		}
+		if err := setPoolFlags(c.context, c.clusterInfo, pool.Name, true); err != nil {
+			logger.Errorf("failed to freeze pool %q. %v", pool.Name, err)
+			// a flag left alone would be taken for a flag of the admin at the next check
+			if err := setPoolFlags(c.context, c.clusterInfo, pool.Name, false); err != nil {
+				logger.Errorf("failed to unfreeze pool %q. %v", pool.Name, err)
+			}
+			continue
+		}
+		emergency.FrozenPools = append(emergency.FrozenPools, pool.Name)
+	}
+}
+
+// endFullEmergency reverts the changes to the pools and the OSDs and clears the emergency from the status, when the
+// OSDs are no longer full or the handling of the emergency is disabled
+func (c *cephStatusChecker) endFullEmergency(cephCluster *cephv1.CephCluster, emergency *cephv1.FullEmergencyStatus, noLongerFull bool) {
+	remaining := &cephv1.FullEmergencyStatus{Since: emergency.Since, Message: emergency.Message}
+	for _, pool := range emergency.ProtectedPools {
+		if err := cephclient.SetPoolProperty(c.context, c.clusterInfo, pool, cephclient.PgAutoscaleModeProperty, cephclient.PgAutoscaleModeOn); err != nil {
+			logger.Errorf("failed to turn the pg autoscaler of pool %q back on. %v", pool, err)
+			remaining.ProtectedPools = append(remaining.ProtectedPools, pool)
+		}
+	}
+	for _, pool := range emergency.FrozenPools {
+		if err := setPoolFlags(c.context, c.clusterInfo, pool, false); err != nil {
+			logger.Errorf("failed to unfreeze pool %q. %v", pool, err)
+			remaining.FrozenPools = append(remaining.FrozenPools, pool)
+		}
+	}
+	if emergency.NoRebalance {
+		if err := cephclient.UnsetOSDFlag(c.context, c.clusterInfo, noRebalanceFlag); err != nil {
+			logger.Errorf("failed to resume the rebalancing of the osds. %v", err)
+			remaining.NoRebalance = true
+		}
+	}
+	if len(remaining.ProtectedPools) > 0 || len(remaining.FrozenPools) > 0 || remaining.NoRebalance {
+		// the remaining changes are retried at the next check
+		cephCluster.Status.CephStatus.FullEmergency = remaining
+		return
+	}
+
+	cephCluster.Status.CephStatus.FullEmergency = nil
+	if !noLongerFull {
+		logger.Info("full emergency handling is disabled")
+		return
+	}
+	logger.Info("cluster is no longer full")
+	c.recordEvent(cephCluster, v1.EventTypeNormal, cephv1.ClusterNoLongerFullReason, fmt.Sprintf("OSDs are no longer full, after being full since %s", emergency.Since))
+}
+
+// setPoolFlags sets or unsets the flags of the pool that prevent the changes of its PG count and replica size
+func setPoolFlags(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, pool string, set bool) error {
+	for _, flag := range []string{noPGChangeFlag, noSizeChangeFlag} {
+		if err := cephclient.SetPoolProperty(context, clusterInfo, pool, flag, strconv.FormatBool(set)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func poolFlagSet(flagsNames, flag string) bool {
+	for _, name := range strings.Split(flagsNames, ",") {
+		if name == flag {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *cephStatusChecker) recordEvent(cephCluster *cephv1.CephCluster, eventType string, reason cephv1.ConditionReason, message string) {
+	if c.recorder == nil {
+		return
+	}
+	c.recorder.Event(cephCluster, eventType, string(reason), message)
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"strings"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/tools/record"
+)
+
+const testOSDDump = `{"full_ratio":0.95,"backfillfull_ratio":0.9,"nearfull_ratio":0.85,
+	"pools":[{"pool_name":".mgr","pg_autoscale_mode":"on","flags_names":"hashpspool"},
+	{"pool_name":"replicapool","pg_autoscale_mode":"warn","flags_names":"hashpspool,selfmanaged_snaps"},
+	{"pool_name":"frozenpool","pg_autoscale_mode":"off","flags_names":"hashpspool,nopgchange"}]}`
+
+func newFullTestExecutor(commands *[]string) *exectest.MockExecutor {
+	return &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			// the command without the connection flags
+			for i, arg := range args {
+				if strings.HasPrefix(arg, "--") {
+					*commands = append(*commands, strings.Join(args[:i], " "))
+					break
+				}
+			}
+			if args[0] == "osd" && args[1] == "dump" {
+				return testOSDDump, nil
+			}
+			return "", nil
+		},
+	}
+}
+
+func TestReconcileFullRatios(t *testing.T) {
+	commands := []string{}
+	c := &cluster{
+		ClusterInfo: cephclient.AdminTestClusterInfo("rook-ceph"),
+		context:     &clusterd.Context{Executor: newFullTestExecutor(&commands)},
+		Spec:        &cephv1.ClusterSpec{},
+	}
+	ratio := func(r float64) *float64 { return &r }
+
+	t.Run("no ratios", func(t *testing.T) {
+		assert.NoError(t, c.reconcileFullRatios())
+		assert.Empty(t, commands)
+	})
+
+	t.Run("changed ratios", func(t *testing.T) {
+		commands = []string{}
+		c.Spec.Storage.FullRatio = ratio(0.97)
+		c.Spec.Storage.BackfillFullRatio = ratio(0.9)
+		assert.NoError(t, c.reconcileFullRatios())
+		assert.Equal(t, []string{"osd dump", "osd set-full-ratio 0.97"}, commands)
+	})
+
+	t.Run("ratios out of order with the current ratios", func(t *testing.T) {
+		commands = []string{}
+		c.Spec.Storage.FullRatio = nil
+		c.Spec.Storage.BackfillFullRatio = nil
+		c.Spec.Storage.NearFullRatio = ratio(0.92)
+		err := c.reconcileFullRatios()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "must be in increasing order")
+		assert.Equal(t, []string{"osd dump"}, commands)
+	})
+}
+
+func TestCheckFullEmergency(t *testing.T) {
+	commands := []string{}
+	recorder := record.NewFakeRecorder(10)
+	c := &cephStatusChecker{
+		context:       &clusterd.Context{Executor: newFullTestExecutor(&commands)},
+		clusterInfo:   cephclient.AdminTestClusterInfo("rook-ceph"),
+		fullEmergency: cephv1.FullEmergencySpec{Enabled: true, ProtectPools: true},
+		recorder:      recorder,
+	}
+	cephCluster := &cephv1.CephCluster{}
+	check := func(checks map[string]cephclient.CheckMessage) {
+		status := &cephclient.CephStatus{Health: cephclient.HealthStatus{Checks: checks}}
+		previousStatus := cephCluster.Status.CephStatus
+		cephCluster.Status.CephStatus = toCustomResourceStatus(cephCluster.Status, status)
+		c.checkFullEmergency(cephCluster, status, previousStatus)
+	}
+	nearFull := cephclient.CheckMessage{Severity: "HEALTH_WARN", Summary: cephclient.Summary{Message: "1 nearfull osd(s)"}}
+	full := cephclient.CheckMessage{Severity: "HEALTH_ERR", Summary: cephclient.Summary{Message: "1 full osd(s)"}}
+
+	// a warning is raised once when the OSDs become nearly full
+	check(map[string]cephclient.CheckMessage{osdNearFullCheck: nearFull})
+	check(map[string]cephclient.CheckMessage{osdNearFullCheck: nearFull})
+	assert.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "Warning ClusterNearFull 1 nearfull osd(s)")
+	assert.Nil(t, cephCluster.Status.CephStatus.FullEmergency)
+
+	// the emergency starts when the OSDs are full, and the pools are protected
+	commands = []string{}
+	check(map[string]cephclient.CheckMessage{osdFullCheck: full})
+	assert.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "Warning ClusterFull 1 full osd(s) and the writes to the cluster are blocked")
+	emergency := cephCluster.Status.CephStatus.FullEmergency
+	assert.NotNil(t, emergency)
+	assert.NotEmpty(t, emergency.Since)
+	assert.Equal(t, "1 full osd(s)", emergency.Message)
+	assert.Equal(t, []string{".mgr"}, emergency.ProtectedPools)
+	// the flags of the pool set by the admin are left as they are
+	assert.Equal(t, []string{".mgr", "replicapool"}, emergency.FrozenPools)
+	assert.True(t, emergency.NoRebalance)
+	assert.Equal(t, []string{
+		"osd dump",
+		"osd set norebalance",
+		"osd pool set .mgr pg_autoscale_mode off",
+		"osd pool set .mgr nopgchange true",
+		"osd pool set .mgr nosizechange true",
+		"osd pool set replicapool nopgchange true",
+		"osd pool set replicapool nosizechange true",
+	}, commands)
+
+	// the emergency continues without new events
+	full.Summary.Message = "2 full osd(s)"
+	check(map[string]cephclient.CheckMessage{osdFullCheck: full})
+	assert.Len(t, recorder.Events, 0)
+	assert.Equal(t, emergency.Since, cephCluster.Status.CephStatus.FullEmergency.Since)
+	assert.Equal(t, "2 full osd(s)", cephCluster.Status.CephStatus.FullEmergency.Message)
+
+	// the changes are reverted when the OSDs are no longer full
+	commands = []string{}
+	check(map[string]cephclient.CheckMessage{osdNearFullCheck: nearFull})
+	assert.Nil(t, cephCluster.Status.CephStatus.FullEmergency)
+	assert.Equal(t, []string{
+		"osd pool set .mgr pg_autoscale_mode on",
+		"osd pool set .mgr nopgchange false",
+		"osd pool set .mgr nosizechange false",
+		"osd pool set replicapool nopgchange false",
+		"osd pool set replicapool nosizechange false",
+		"osd unset norebalance",
+	}, commands)
+	assert.Len(t, recorder.Events, 2)
+	assert.Contains(t, <-recorder.Events, "Normal ClusterNoLongerFull")
+	assert.Contains(t, <-recorder.Events, "Warning ClusterNearFull")
+
+	// no events are raised when the handling is disabled
+	c.fullEmergency.Enabled = false
+	check(map[string]cephclient.CheckMessage{osdFullCheck: full})
+	assert.Len(t, recorder.Events, 0)
+	assert.Nil(t, cephCluster.Status.CephStatus.FullEmergency)
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	v1 "k8s.io/api/core/v1"
+)
+
+// checkHealthDegraded sets the Degraded condition of the cluster while ceph reports health checks, with the summary of
+// the checks as message. The Degraded condition raised by the clock skew of the mons is more specific and is kept.
+func checkHealthDegraded(cephCluster *cephv1.CephCluster, status *cephclient.CephStatus) {
+	condition := findCondition(cephCluster.Status.Conditions, cephv1.ConditionDegraded)
+	if condition != nil && condition.Status == v1.ConditionTrue && condition.Reason == cephv1.MonClockSkewReason {
+		return
+	}
+
+	if status.Health.Status != "HEALTH_OK" && len(status.Health.Checks) > 0 {
+		setDegradedCondition(cephCluster, v1.ConditionTrue, cephv1.CephHealthWarningReason,
+			fmt.Sprintf("%s: %s", status.Health.Status, healthSummary(status.Health.Checks)))
+		return
+	}
+	if condition != nil && condition.Status == v1.ConditionTrue {
+		setDegradedCondition(cephCluster, v1.ConditionFalse, cephv1.CephHealthOKReason, "ceph health is HEALTH_OK")
+	}
+}
+
+// healthSummary returns the messages of the health checks, the errors first
+func healthSummary(checks map[string]cephclient.CheckMessage) string {
+	names := make([]string, 0, len(checks))
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if checks[names[i]].Severity != checks[names[j]].Severity {
+			return checks[names[i]].Severity == "HEALTH_ERR"
+		}
+		return names[i] < names[j]
+	})
+
+	messages := make([]string, 0, len(names))
+	for _, name := range names {
+		messages = append(messages, fmt.Sprintf("%s (%s)", checks[name].Summary.Message, name))
+	}
+	return strings.Join(messages, "; ")
+}
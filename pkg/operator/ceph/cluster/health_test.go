@@ -0,0 +1,64 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestCheckHealthDegraded(t *testing.T) {
+	cephCluster := &cephv1.CephCluster{}
+	healthy := &cephclient.CephStatus{Health: cephclient.HealthStatus{Status: "HEALTH_OK"}}
+	warning := &cephclient.CephStatus{Health: cephclient.HealthStatus{
+		Status: "HEALTH_ERR",
+		Checks: map[string]cephclient.CheckMessage{
+			"OSD_DOWN":       {Severity: "HEALTH_WARN", Summary: cephclient.Summary{Message: "1 osds down"}},
+			"PG_DEGRADED":    {Severity: "HEALTH_WARN", Summary: cephclient.Summary{Message: "Degraded data redundancy"}},
+			"OSD_FULL":       {Severity: "HEALTH_ERR", Summary: cephclient.Summary{Message: "1 full osd(s)"}},
+			"MON_CLOCK_SKEW": {Severity: "HEALTH_WARN", Summary: cephclient.Summary{Message: "clock skew detected"}},
+		},
+	}}
+
+	// healthy cluster
+	checkHealthDegraded(cephCluster, healthy)
+	assert.Nil(t, findCondition(cephCluster.Status.Conditions, cephv1.ConditionDegraded))
+
+	// the health checks are summarized, the errors first
+	checkHealthDegraded(cephCluster, warning)
+	condition := findCondition(cephCluster.Status.Conditions, cephv1.ConditionDegraded)
+	assert.Equal(t, v1.ConditionTrue, condition.Status)
+	assert.Equal(t, cephv1.CephHealthWarningReason, condition.Reason)
+	assert.Equal(t, "HEALTH_ERR: 1 full osd(s) (OSD_FULL); clock skew detected (MON_CLOCK_SKEW); 1 osds down (OSD_DOWN); Degraded data redundancy (PG_DEGRADED)", condition.Message)
+
+	// back to healthy
+	checkHealthDegraded(cephCluster, healthy)
+	condition = findCondition(cephCluster.Status.Conditions, cephv1.ConditionDegraded)
+	assert.Equal(t, v1.ConditionFalse, condition.Status)
+	assert.Equal(t, cephv1.CephHealthOKReason, condition.Reason)
+
+	// the clock skew of the mons is kept
+	setDegradedCondition(cephCluster, v1.ConditionTrue, cephv1.MonClockSkewReason, "clock skew")
+	checkHealthDegraded(cephCluster, warning)
+	condition = findCondition(cephCluster.Status.Conditions, cephv1.ConditionDegraded)
+	assert.Equal(t, cephv1.MonClockSkewReason, condition.Reason)
+	assert.Len(t, cephCluster.Status.Conditions, 1)
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/daemon/ceph/hostpath"
+	"github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/operator/k8sutil/cmdreporter"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	prepareHostPathAppName        = "rook-ceph-prepare-host-path"
+	prepareHostPathRootVolume     = "rootfs"
+	defaultPrepareHostPathTimeout = 5 * time.Minute
+)
+
+// runPrepareHostPathJob prepares the dataDirHostPath in a job on the node with the hostname and returns the
+// failures. Overridden for unit testing.
+var runPrepareHostPathJob = func(c *cluster, rookImage, hostname string, args []string, timeout time.Duration) ([]string, error) {
+	jobName := k8sutil.TruncateNodeNameForJob("rook-ceph-prepare-host-path-%s", hostname)
+	reporter, err := cmdreporter.New(
+		c.context.Clientset,
+		c.ownerInfo,
+		prepareHostPathAppName,
+		jobName,
+		c.Namespace,
+		[]string{"rook"},
+		args,
+		rookImage,
+		rookImage,
+		c.Spec.CephVersion.ImagePullPolicy,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to set up the job preparing the dataDirHostPath")
+	}
+
+	job := reporter.Job()
+	podSpec := &job.Spec.Template.Spec
+	podSpec.ServiceAccountName = "rook-ceph-cmd-reporter"
+	podSpec.NodeSelector = map[string]string{v1.LabelHostname: hostname}
+	podSpec.Tolerations = getCleanupPlacement(*c.Spec).Tolerations
+	// the root of the host is mounted to create the directories and the links of the data anywhere on the host
+	podSpec.Volumes = append(podSpec.Volumes, v1.Volume{
+		Name:         prepareHostPathRootVolume,
+		VolumeSource: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: "/"}},
+	})
+	for i := range podSpec.Containers {
+		podSpec.Containers[i].VolumeMounts = append(podSpec.Containers[i].VolumeMounts,
+			v1.VolumeMount{Name: prepareHostPathRootVolume, MountPath: hostpath.HostRoot})
+		podSpec.Containers[i].SecurityContext = controller.PrivilegedContext(true)
+	}
+
+	stdout, stderr, retcode, err := reporter.Run(c.ClusterInfo.Context, timeout)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to complete the job preparing the dataDirHostPath")
+	}
+	if retcode != 0 {
+		return nil, errors.Errorf("the job preparing the dataDirHostPath returned failure with retcode %d. stdout: %s. stderr: %s", retcode, stdout, stderr)
+	}
+	failures := []string{}
+	if err := json.Unmarshal([]byte(stdout), &failures); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal the failures of the preparation %q", stdout)
+	}
+	return failures, nil
+}
+
+// prepareDataDirHostPath prepares the dataDirHostPath on the nodes before the daemons start. A job prepares the
+// dataDirHostPath on each node. The failures of the nodes are reported as warnings so that a single node cannot block
+// the orchestration of the cluster, which is only blocked if the preparation failed on all the nodes.
+func (c *cluster) prepareDataDirHostPath(rookImage string) error {
+	preparation := c.Spec.DataDirHostPathPreparation
+	if !preparation.Enabled {
+		return nil
+	}
+	nodePaths, err := dataDirHostPathNodePaths(c.Spec)
+	if err != nil {
+		return err
+	}
+
+	hostnames, err := c.dataDirHostPathHostnames()
+	if err != nil {
+		return err
+	}
+	if len(hostnames) == 0 {
+		logger.Warning("no valid nodes to prepare the dataDirHostPath")
+		return nil
+	}
+
+	timeout := defaultPrepareHostPathTimeout
+	if preparation.Timeout != nil {
+		timeout = preparation.Timeout.Duration
+	}
+	logger.Infof("preparing the dataDirHostPath %q on nodes %v", c.Spec.DataDirHostPath, hostnames)
+
+	failedNodes := []cephv1.PreflightNodeStatus{}
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for _, hostname := range hostnames {
+		wg.Add(1)
+		go func(hostname string) {
+			defer wg.Done()
+			args := prepareHostPathArgs(c.Spec, nodePaths[hostname])
+			failures, err := runPrepareHostPathJob(c, rookImage, hostname, args, timeout)
+			if err != nil {
+				failures = []string{err.Error()}
+			}
+			if len(failures) == 0 {
+				return
+			}
+			mutex.Lock()
+			defer mutex.Unlock()
+			failedNodes = append(failedNodes, cephv1.PreflightNodeStatus{Name: hostname, Failures: failures})
+		}(hostname)
+	}
+	wg.Wait()
+
+	sort.Slice(failedNodes, func(i, j int) bool { return failedNodes[i].Name < failedNodes[j].Name })
+	if len(failedNodes) == len(hostnames) {
+		return errors.Errorf("failed to prepare the dataDirHostPath on all the nodes. %s", preflightReport(failedNodes))
+	}
+	if len(failedNodes) > 0 {
+		logger.Warningf("failed to prepare the dataDirHostPath on %d of %d node(s), the preparation is retried at the next orchestration. %s", len(failedNodes), len(hostnames), preflightReport(failedNodes))
+	}
+	logger.Infof("dataDirHostPath prepared on %d node(s)", len(hostnames)-len(failedNodes))
+	return nil
+}
+
+// dataDirHostPathNodePaths returns the directories of the data of the nodes by hostname
+func dataDirHostPathNodePaths(spec *cephv1.ClusterSpec) (map[string]string, error) {
+	nodePaths := map[string]string{}
+	for _, node := range spec.DataDirHostPathPreparation.Nodes {
+		if _, ok := nodePaths[node.Name]; ok {
+			return nil, errors.Errorf("the dataDirHostPath of node %q is set more than once", node.Name)
+		}
+		if !filepath.IsAbs(node.Path) {
+			return nil, errors.Errorf("the dataDirHostPath of node %q must be an absolute path, got %q", node.Name, node.Path)
+		}
+		if filepath.Clean(node.Path) == filepath.Clean(spec.DataDirHostPath) {
+			return nil, errors.Errorf("the dataDirHostPath of node %q must differ from the dataDirHostPath %q", node.Name, spec.DataDirHostPath)
+		}
+		nodePaths[node.Name] = node.Path
+	}
+	return nodePaths, nil
+}
+
+// dataDirHostPathHostnames returns the hostnames of the valid nodes where the daemons can run
+func (c *cluster) dataDirHostPathHostnames() ([]string, error) {
+	hostnameMap, err := k8sutil.GetNodeHostNames(c.ClusterInfo.Context, c.context.Clientset)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get node hostnames to prepare the dataDirHostPath")
+	}
+	storage := cephv1.StorageScopeSpec{}
+	for _, hostname := range hostnameMap {
+		storage.Nodes = append(storage.Nodes, cephv1.Node{Name: hostname})
+	}
+
+	hostnames := []string{}
+	for _, node := range k8sutil.GetValidNodes(c.ClusterInfo.Context, storage, c.context.Clientset, getCleanupPlacement(*c.Spec)) {
+		hostnames = append(hostnames, node.Name)
+	}
+	sort.Strings(hostnames)
+	return hostnames, nil
+}
+
+// prepareHostPathArgs returns the args of the command preparing the dataDirHostPath of a node
+func prepareHostPathArgs(spec *cephv1.ClusterSpec, nodePath string) []string {
+	args := []string{"ceph", "prepare-host-path", "--path", spec.DataDirHostPath}
+	if nodePath != "" {
+		args = append(args, "--node-path", nodePath)
+	}
+	if spec.DataDirHostPathPreparation.SELinuxType != "" {
+		args = append(args, "--selinux-type", spec.DataDirHostPathPreparation.SELinuxType)
+	}
+	if spec.DataDirHostPathPreparation.AllowOverlay {
+		args = append(args, "--allow-overlay")
+	}
+	return args
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	testop "github.com/rook/rook/pkg/operator/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrepareHostPathArgs(t *testing.T) {
+	spec := &cephv1.ClusterSpec{DataDirHostPath: "/var/lib/rook"}
+	assert.Equal(t, []string{"ceph", "prepare-host-path", "--path", "/var/lib/rook"}, prepareHostPathArgs(spec, ""))
+
+	spec.DataDirHostPathPreparation = cephv1.DataDirHostPathPreparationSpec{SELinuxType: "container_file_t", AllowOverlay: true}
+	assert.Equal(t, []string{
+		"ceph", "prepare-host-path", "--path", "/var/lib/rook", "--node-path", "/mnt/rook",
+		"--selinux-type", "container_file_t", "--allow-overlay",
+	}, prepareHostPathArgs(spec, "/mnt/rook"))
+}
+
+func TestPrepareDataDirHostPath(t *testing.T) {
+	oldRunJob := runPrepareHostPathJob
+	defer func() { runPrepareHostPathJob = oldRunJob }()
+	var mutex sync.Mutex
+	ranOnNodes := map[string][]string{}
+	failAll := false
+	runPrepareHostPathJob = func(c *cluster, rookImage, hostname string, args []string, timeout time.Duration) ([]string, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		ranOnNodes[hostname] = args
+		assert.Equal(t, defaultPrepareHostPathTimeout, timeout)
+		switch hostname {
+		case "node1":
+			return []string{`"/var/lib/rook" is on an overlay filesystem, whose data is lost when the node restarts`}, nil
+		case "node2":
+			return nil, errors.New("failed to complete the job preparing the dataDirHostPath")
+		}
+		if failAll {
+			return []string{`failed to create "/var/lib/rook"`}, nil
+		}
+		return nil, nil
+	}
+
+	newTestCluster := func(t *testing.T, preparation cephv1.DataDirHostPathPreparationSpec) *cluster {
+		clusterInfo := cephclient.AdminTestClusterInfo("rook-ceph")
+		clusterInfo.Context = context.TODO()
+		ranOnNodes = map[string][]string{}
+		return &cluster{
+			ClusterInfo: clusterInfo,
+			context:     &clusterd.Context{Clientset: testop.New(t, 3)},
+			Namespace:   "rook-ceph",
+			Spec:        &cephv1.ClusterSpec{DataDirHostPath: "/var/lib/rook", DataDirHostPathPreparation: preparation},
+		}
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		c := newTestCluster(t, cephv1.DataDirHostPathPreparationSpec{})
+		assert.NoError(t, c.prepareDataDirHostPath("rook/ceph:master"))
+		assert.Empty(t, ranOnNodes)
+	})
+
+	t.Run("the failures of some nodes do not block the orchestration", func(t *testing.T) {
+		c := newTestCluster(t, cephv1.DataDirHostPathPreparationSpec{
+			Enabled: true,
+			Nodes:   []cephv1.DataDirHostPathNodeSpec{{Name: "node0", Path: "/mnt/rook"}},
+		})
+		assert.NoError(t, c.prepareDataDirHostPath("rook/ceph:master"))
+		assert.Len(t, ranOnNodes, 3)
+		assert.Equal(t, []string{"ceph", "prepare-host-path", "--path", "/var/lib/rook", "--node-path", "/mnt/rook"}, ranOnNodes["node0"])
+		assert.Equal(t, []string{"ceph", "prepare-host-path", "--path", "/var/lib/rook"}, ranOnNodes["node1"])
+	})
+
+	t.Run("the failures of all the nodes are reported", func(t *testing.T) {
+		c := newTestCluster(t, cephv1.DataDirHostPathPreparationSpec{Enabled: true})
+		failAll = true
+		defer func() { failAll = false }()
+		err := c.prepareDataDirHostPath("rook/ceph:master")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), `node "node1": "/var/lib/rook" is on an overlay filesystem`)
+		assert.Contains(t, err.Error(), `node "node2": failed to complete the job`)
+		assert.Contains(t, err.Error(), `node "node0": failed to create`)
+	})
+
+	t.Run("invalid node paths", func(t *testing.T) {
+		for _, nodes := range [][]cephv1.DataDirHostPathNodeSpec{
+			{{Name: "node0", Path: "/mnt/rook"}, {Name: "node0", Path: "/mnt/other"}},
+			{{Name: "node0", Path: "mnt/rook"}},
+			{{Name: "node0", Path: "/var/lib/rook/"}},
+		} {
+			c := newTestCluster(t, cephv1.DataDirHostPathPreparationSpec{Enabled: true, Nodes: nodes})
+			assert.Error(t, c.prepareDataDirHostPath("rook/ceph:master"))
+			assert.Empty(t, ranOnNodes)
+		}
+	})
+}
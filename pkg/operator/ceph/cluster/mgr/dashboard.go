@@ -20,6 +20,7 @@ package mgr
 import (
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	"fmt"
 	"os"
 	"strconv"
@@ -29,6 +30,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/rook/rook/pkg/daemon/ceph/client"
 	"github.com/rook/rook/pkg/operator/ceph/config"
+	"github.com/rook/rook/pkg/operator/ceph/controller"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	"github.com/rook/rook/pkg/util"
 	"github.com/rook/rook/pkg/util/exec"
@@ -46,8 +48,20 @@ const (
 	dashboardPasswordName          = "rook-ceph-dashboard-password"
 	passwordLength                 = 20
 	passwordKeyName                = "password"
+	usernameKeyName                = "username"
 	certAlreadyConfiguredErrorCode = 5
 	invalidArgErrorCode            = int(syscall.EINVAL)
+	userAlreadyExistsErrorCode     = int(syscall.EEXIST)
+
+	// CredentialsLabel is set on the secrets with the credentials published for external systems, with the kind of
+	// credentials as value
+	CredentialsLabel = "ceph.rook.io/credentials"
+	// CredentialsRevisionAnnotation is bumped on the secrets with the published credentials each time the credentials
+	// change, so that external systems can watch the secrets and reload the credentials
+	CredentialsRevisionAnnotation = "ceph.rook.io/credentials-revision"
+	// the hash of the password set in the dashboard, to detect the rotation of the password in the secret
+	credentialsHashAnnotation = "ceph.rook.io/credentials-hash"
+	dashboardCredentials      = "dashboard"
 )
 
 var (
@@ -72,9 +86,31 @@ func (c *Cluster) configureDashboardService(activeDaemon string) error {
 		}
 	}
 
+	// publish the dashboard url in the connection info of the cluster
+	err = controller.UpdateConnectionInfo(c.clusterInfo.Context, c.context.Clientset, c.clusterInfo.Namespace, c.clusterInfo.OwnerInfo, func(data map[string]string) error {
+		if c.spec.Dashboard.Enabled {
+			data[controller.ConnectionInfoDashboardURLKey] = c.dashboardURL(dashboardService.Name)
+		} else {
+			delete(data, controller.ConnectionInfoDashboardURLKey)
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to update the dashboard url of the connection info")
+	}
+
 	return nil
 }
 
+// dashboardURL returns the in-cluster url of the dashboard service
+func (c *Cluster) dashboardURL(serviceName string) string {
+	scheme := "http"
+	if c.spec.Dashboard.SSL {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s.%s.svc:%d%s", scheme, serviceName, c.clusterInfo.Namespace, c.dashboardPublicPort(), c.spec.Dashboard.URLPrefix)
+}
+
 // Ceph docs about the dashboard module: http://docs.ceph.com/docs/nautilus/mgr/dashboard/
 func (c *Cluster) configureDashboardModules() error {
 	if c.spec.Dashboard.Enabled {
@@ -162,7 +198,7 @@ func (c *Cluster) initializeSecureDashboard() (bool, error) {
 		if err != nil {
 			return false, errors.Wrap(err, "failed to create a self signed cert for the ceph dashboard")
 		}
-		if alreadyCreated {
+		if alreadyCreated && c.dashboardPasswordApplied(password) {
 			return false, nil
 		}
 	}
@@ -171,6 +207,10 @@ func (c *Cluster) initializeSecureDashboard() (bool, error) {
 		return false, errors.Wrap(err, "failed to set login credentials for the ceph dashboard")
 	}
 
+	if err := c.publishDashboardCredentials(password); err != nil {
+		return false, errors.Wrap(err, "failed to publish the credentials of the ceph dashboard")
+	}
+
 	return false, nil
 }
 
@@ -228,7 +268,14 @@ func (c *Cluster) setLoginCredentials(password string) error {
 		return "set dashboard creds", output, err
 	}, c.exitCode, 5, invalidArgErrorCode, dashboardInitWaitTime)
 	if err != nil {
-		return errors.Wrap(err, "failed to set login creds on mgr")
+		// the password of the existing user is set when the password was rotated in the secret
+		if exitCode, parsed := c.exitCode(errors.Cause(err)); !parsed || exitCode != userAlreadyExistsErrorCode {
+			return errors.Wrap(err, "failed to set login creds on mgr")
+		}
+		args = []string{"dashboard", "ac-user-set-password", dashboardUsername, "-i", file.Name()}
+		if _, err := client.NewCephCommand(c.context, c.clusterInfo, args).RunWithTimeout(exec.CephCommandsTimeout); err != nil {
+			return errors.Wrap(err, "failed to set the password of the login creds on mgr")
+		}
 	}
 
 	logger.Info("successfully set ceph dashboard creds")
@@ -259,6 +306,7 @@ func (c *Cluster) getOrGenerateDashboardPassword() (string, error) {
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      dashboardPasswordName,
 			Namespace: c.clusterInfo.Namespace,
+			Labels:    map[string]string{CredentialsLabel: dashboardCredentials},
 		},
 		Data: secrets,
 		Type: k8sutil.RookType,
@@ -275,6 +323,62 @@ func (c *Cluster) getOrGenerateDashboardPassword() (string, error) {
 	return password, nil
 }
 
+func credentialsHash(password string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(password)))
+}
+
+// dashboardPasswordApplied returns whether the password of the secret is the password last set in the dashboard
+func (c *Cluster) dashboardPasswordApplied(password string) bool {
+	secret, err := c.context.Clientset.CoreV1().Secrets(c.clusterInfo.Namespace).Get(c.clusterInfo.Context, dashboardPasswordName, metav1.GetOptions{})
+	if err != nil {
+		logger.Warningf("failed to get dashboard secret to check if its password was rotated. %v", err)
+		return false
+	}
+	return secret.Annotations[credentialsHashAnnotation] == credentialsHash(password)
+}
+
+// publishDashboardCredentials completes the dashboard secret with the username and the labels of the published
+// credentials, and bumps its revision when the password set in the dashboard changed, e.g. when the password was
+// regenerated or rotated in the secret
+func (c *Cluster) publishDashboardCredentials(password string) error {
+	secrets := c.context.Clientset.CoreV1().Secrets(c.clusterInfo.Namespace)
+	secret, err := secrets.Get(c.clusterInfo.Context, dashboardPasswordName, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to get dashboard secret")
+	}
+
+	hash := credentialsHash(password)
+	if secret.Annotations[credentialsHashAnnotation] == hash && string(secret.Data[usernameKeyName]) == dashboardUsername &&
+		secret.Labels[CredentialsLabel] == dashboardCredentials {
+		return nil
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	if secret.Labels == nil {
+		secret.Labels = map[string]string{}
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	if secret.Annotations[credentialsHashAnnotation] != hash {
+		revision, err := strconv.Atoi(secret.Annotations[CredentialsRevisionAnnotation])
+		if err != nil {
+			revision = 0
+		}
+		secret.Annotations[CredentialsRevisionAnnotation] = strconv.Itoa(revision + 1)
+		secret.Annotations[credentialsHashAnnotation] = hash
+	}
+	secret.Labels[CredentialsLabel] = dashboardCredentials
+	secret.Data[usernameKeyName] = []byte(dashboardUsername)
+
+	if _, err := secrets.Update(c.clusterInfo.Context, secret, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrap(err, "failed to update dashboard secret")
+	}
+	logger.Infof("published the credentials of the ceph dashboard in secret %q at revision %s", dashboardPasswordName, secret.Annotations[CredentialsRevisionAnnotation])
+	return nil
+}
+
 func GeneratePassword(length int) (string, error) {
 	//nolint:gosec // because of the word password
 	const passwordChars = "!\"#$%&'()*+,-./0123456789:;<=>?@ABCDEFGHIJKLMNOPQRSTUVWXYZ[\\]^_`abcdefghijklmnopqrstuvwxyz{|}~"
@@ -29,6 +29,7 @@ import (
 	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -184,3 +185,44 @@ func TestStartSecureDashboard(t *testing.T) {
 	assert.Equal(t, 8443, int(svc.Spec.Ports[0].Port))
 	assert.Equal(t, 8443, int(svc.Spec.Ports[0].TargetPort.IntVal))
 }
+
+func TestPublishDashboardCredentials(t *testing.T) {
+	ctx := context.TODO()
+	clientset := test.New(t, 3)
+	ownerInfo := cephclient.NewMinimumOwnerInfoWithOwnerRef()
+	clusterInfo := &cephclient.ClusterInfo{Namespace: "myns", OwnerInfo: ownerInfo, Context: ctx}
+	c := &Cluster{context: &clusterd.Context{Clientset: clientset}, clusterInfo: clusterInfo}
+	getSecret := func() *v1.Secret {
+		secret, err := clientset.CoreV1().Secrets(clusterInfo.Namespace).Get(ctx, dashboardPasswordName, metav1.GetOptions{})
+		require.NoError(t, err)
+		return secret
+	}
+
+	password, err := c.getOrGenerateDashboardPassword()
+	require.NoError(t, err)
+	assert.False(t, c.dashboardPasswordApplied(password))
+
+	// the first password is published at the first revision
+	require.NoError(t, c.publishDashboardCredentials(password))
+	secret := getSecret()
+	assert.Equal(t, "1", secret.Annotations[CredentialsRevisionAnnotation])
+	assert.Equal(t, dashboardCredentials, secret.Labels[CredentialsLabel])
+	assert.Equal(t, dashboardUsername, string(secret.Data[usernameKeyName]))
+	assert.True(t, c.dashboardPasswordApplied(password))
+
+	// the revision is unchanged when the password did not change
+	require.NoError(t, c.publishDashboardCredentials(password))
+	assert.Equal(t, "1", getSecret().Annotations[CredentialsRevisionAnnotation])
+
+	// the revision is bumped when the password is rotated in the secret
+	secret.Data[passwordKeyName] = []byte("rotated")
+	_, err = clientset.CoreV1().Secrets(clusterInfo.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	password, err = c.getOrGenerateDashboardPassword()
+	require.NoError(t, err)
+	assert.Equal(t, "rotated", password)
+	assert.False(t, c.dashboardPasswordApplied(password))
+	require.NoError(t, c.publishDashboardCredentials(password))
+	assert.Equal(t, "2", getSecret().Annotations[CredentialsRevisionAnnotation])
+	assert.True(t, c.dashboardPasswordApplied(password))
+}
@@ -325,6 +325,7 @@ func (c *Cluster) configureModules(daemonIDs []string) {
 	// are "just" enabled, but still they must be configured to work properly
 	startModuleConfiguration("balancer", c.enableBalancerModule)
 	startModuleConfiguration("mgr module(s) from the spec", c.configureMgrModules)
+	startModuleConfiguration("telemetry", c.configureTelemetry)
 }
 
 func startModuleConfiguration(description string, configureModules func() error) {
@@ -44,6 +44,7 @@ func (c *Cluster) makeDeployment(mgrConfig *mgrConfig) (*apps.Deployment, error)
 	logger.Debugf("mgrConfig: %+v", mgrConfig)
 
 	volumes := controller.DaemonVolumes(mgrConfig.DataPathMap, mgrConfig.ResourceName, c.spec.DataDirHostPath)
+	volumes = append(volumes, controller.HardenedDaemonVolumes(&c.spec, mgrConfig.DataPathMap)...)
 	if c.spec.Network.IsMultus() {
 		adminKeyringVol, _ := keyring.Volume().Admin(), keyring.VolumeMount().Admin()
 		volumes = append(volumes, adminKeyringVol)
@@ -103,6 +104,8 @@ func (c *Cluster) makeDeployment(mgrConfig *mgrConfig) (*apps.Deployment, error)
 		podSpec.Spec.Containers = append(podSpec.Spec.Containers, c.makeCmdProxySidecarContainer(mgrConfig))
 	}
 
+	controller.DecryptKeyringVolumes(&c.spec, c.rookVersion, &podSpec.Spec, keyring.Volume().Admin().Secret.SecretName, mon.AppName)
+
 	cephv1.GetMgrAnnotations(c.spec.Annotations).ApplyToObjectMeta(&podSpec.ObjectMeta)
 	c.applyPrometheusAnnotations(&podSpec.ObjectMeta)
 	cephv1.GetMgrLabels(c.spec.Labels).ApplyToObjectMeta(&podSpec.ObjectMeta)
@@ -141,14 +144,17 @@ func (c *Cluster) makeChownInitContainer(mgrConfig *mgrConfig) v1.Container {
 		*mgrConfig.DataPathMap,
 		c.spec.CephVersion.Image,
 		controller.GetContainerImagePullPolicy(c.spec.CephVersion.ImagePullPolicy),
-		controller.DaemonVolumeMounts(mgrConfig.DataPathMap, mgrConfig.ResourceName, c.spec.DataDirHostPath),
+		append(controller.DaemonVolumeMounts(mgrConfig.DataPathMap, mgrConfig.ResourceName, c.spec.DataDirHostPath),
+			controller.HardenedDaemonVolumeMounts(&c.spec, mgrConfig.DataPathMap)...),
 		cephv1.GetMgrResources(c.spec.Resources),
-		controller.PodSecurityContext(),
+		controller.ChownSecurityContext(&c.spec),
 		"",
 	)
 }
 
 func (c *Cluster) makeMgrDaemonContainer(mgrConfig *mgrConfig) v1.Container {
+	// the dashboard is never bound to a port below 1024, so the mgr does not need to start as root
+	securityContext := controller.DaemonSecurityContext(&c.spec, false)
 
 	container := v1.Container{
 		Name: "mgr",
@@ -156,7 +162,7 @@ func (c *Cluster) makeMgrDaemonContainer(mgrConfig *mgrConfig) v1.Container {
 			"ceph-mgr",
 		},
 		Args: append(
-			controller.DaemonFlags(c.clusterInfo, &c.spec, mgrConfig.DaemonID),
+			controller.HardenedDaemonFlags(controller.DaemonFlags(c.clusterInfo, &c.spec, mgrConfig.DaemonID), securityContext),
 			// for ceph-mgr cephfs
 			// see https://github.com/ceph/ceph-csi/issues/486 for more details
 			config.NewFlag("client-mount-uid", "0"),
@@ -165,7 +171,8 @@ func (c *Cluster) makeMgrDaemonContainer(mgrConfig *mgrConfig) v1.Container {
 		),
 		Image:           c.spec.CephVersion.Image,
 		ImagePullPolicy: controller.GetContainerImagePullPolicy(c.spec.CephVersion.ImagePullPolicy),
-		VolumeMounts:    controller.DaemonVolumeMounts(mgrConfig.DataPathMap, mgrConfig.ResourceName, c.spec.DataDirHostPath),
+		VolumeMounts: append(controller.DaemonVolumeMounts(mgrConfig.DataPathMap, mgrConfig.ResourceName, c.spec.DataDirHostPath),
+			controller.HardenedDaemonVolumeMounts(&c.spec, mgrConfig.DataPathMap)...),
 		Ports: []v1.ContainerPort{
 			{
 				Name:          "mgr",
@@ -188,7 +195,7 @@ func (c *Cluster) makeMgrDaemonContainer(mgrConfig *mgrConfig) v1.Container {
 			c.cephMgrOrchestratorModuleEnvs()...,
 		),
 		Resources:       cephv1.GetMgrResources(c.spec.Resources),
-		SecurityContext: controller.PodSecurityContext(),
+		SecurityContext: securityContext,
 		StartupProbe:    controller.GenerateStartupProbeExecDaemon(config.MgrType, mgrConfig.DaemonID),
 		LivenessProbe:   controller.GenerateLivenessProbeExecDaemon(config.MgrType, mgrConfig.DaemonID),
 		WorkingDir:      config.VarLogCephDir,
@@ -279,6 +286,7 @@ func (c *Cluster) MakeMetricsService(name, activeDaemon, servicePortMetricName s
 	// If the cluster is external we don't need to add the selector
 	if name != controller.ExternalMgrAppName {
 		svc.Spec.Selector = labels
+		controller.ApplyServiceIPFamily(&c.spec, svc)
 	}
 
 	err := c.clusterInfo.OwnerInfo.SetControllerReference(svc)
@@ -316,6 +324,7 @@ func (c *Cluster) makeDashboardService(name, activeDaemon string) (*v1.Service,
 			},
 		},
 	}
+	controller.ApplyServiceIPFamily(&c.spec, svc)
 	err := c.clusterInfo.OwnerInfo.SetControllerReference(svc)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to set owner reference to dashboard service %q", svc.Name)
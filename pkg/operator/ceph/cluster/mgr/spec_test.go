@@ -78,7 +78,24 @@ func TestPodSpec(t *testing.T) {
 			"my-priority-class", "test", "cephclusters.ceph.rook.io", "ceph-mgr")
 		assert.Equal(t, 2, len(d.Spec.Template.Annotations))
 		assert.Equal(t, 1, len(d.Spec.Template.Spec.Containers))
+		assert.Equal(t, 7, len(d.Spec.Template.Spec.Containers[0].VolumeMounts)) // + tmp of the hardened daemon
+
+		// the mgr runs as the ceph user without switching user
+		mgrContainer := d.Spec.Template.Spec.Containers[0]
+		assert.Equal(t, int64(controller.CephUserID), *mgrContainer.SecurityContext.RunAsUser)
+		assert.True(t, *mgrContainer.SecurityContext.ReadOnlyRootFilesystem)
+		assert.NotContains(t, mgrContainer.Args, "--setuser=ceph")
+		assert.Nil(t, d.Spec.Template.Spec.InitContainers[0].SecurityContext.RunAsUser)
+	})
+
+	t.Run("deployment without daemon hardening", func(t *testing.T) {
+		c.spec.Security.DaemonHardening.Disabled = true
+		defer func() { c.spec.Security.DaemonHardening.Disabled = false }()
+		d, err := c.makeDeployment(&mgrTestConfig)
+		assert.NoError(t, err)
 		assert.Equal(t, 6, len(d.Spec.Template.Spec.Containers[0].VolumeMounts))
+		assert.Equal(t, controller.PodSecurityContext(), d.Spec.Template.Spec.Containers[0].SecurityContext)
+		assert.Contains(t, d.Spec.Template.Spec.Containers[0].Args, "--setuser=ceph")
 	})
 
 	t.Run("deployment with multus with new sidecar proxy command container", func(t *testing.T) {
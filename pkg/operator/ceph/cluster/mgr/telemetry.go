@@ -0,0 +1,119 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/config"
+	"github.com/rook/rook/pkg/util/exec"
+)
+
+const (
+	// the license the telemetry reports are shared with
+	telemetryLicense = "sharing-1-0"
+)
+
+var (
+	telemetryInitWaitTime = 5 * time.Second
+
+	allTelemetryChannels = []cephv1.TelemetryChannel{
+		cephv1.TelemetryChannelBasic,
+		cephv1.TelemetryChannelCrash,
+		cephv1.TelemetryChannelDevice,
+		cephv1.TelemetryChannelIdent,
+		cephv1.TelemetryChannelPerf,
+	}
+	// the channels enabled by default by ceph
+	defaultTelemetryChannels = []cephv1.TelemetryChannel{
+		cephv1.TelemetryChannelBasic,
+		cephv1.TelemetryChannelCrash,
+		cephv1.TelemetryChannelDevice,
+	}
+)
+
+// Ceph docs about the telemetry module: https://docs.ceph.com/en/latest/mgr/telemetry/
+func (c *Cluster) configureTelemetry() error {
+	telemetry := c.spec.Mgr.Telemetry
+	if telemetry == nil {
+		return nil
+	}
+
+	// the options of the module are set one by one since they are not accepted by assimilate-conf
+	monStore := config.GetMonStore(c.context, c.clusterInfo)
+	settings := telemetrySettings(telemetry)
+	options := make([]string, 0, len(settings))
+	for option := range settings {
+		options = append(options, option)
+	}
+	sort.Strings(options)
+	for _, option := range options {
+		var err error
+		if settings[option] == "" {
+			// the empty ident settings are removed so that they are not reported
+			err = monStore.Delete("mgr", option)
+		} else {
+			err = monStore.Set("mgr", option, settings[option])
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to configure telemetry setting %q", option)
+		}
+	}
+
+	args := []string{"telemetry", "off"}
+	if telemetry.Enabled {
+		args = []string{"telemetry", "on", "--license", telemetryLicense}
+	}
+	// retry a few times in the case that the mgr module is not ready to accept commands
+	_, err := client.ExecuteCephCommandWithRetry(func() (string, []byte, error) {
+		output, err := client.NewCephCommand(c.context, c.clusterInfo, args).RunWithTimeout(exec.CephCommandsTimeout)
+		return fmt.Sprintf("turn %s telemetry", args[1]), output, err
+	}, c.exitCode, 5, invalidArgErrorCode, telemetryInitWaitTime)
+	if err != nil {
+		return errors.Wrapf(err, "failed to turn %s the telemetry", args[1])
+	}
+	return nil
+}
+
+// telemetrySettings returns the settings of the channels and the ident of the telemetry module. The ident
+// settings that are not set are empty.
+func telemetrySettings(telemetry *cephv1.TelemetrySpec) map[string]string {
+	channels := telemetry.Channels
+	if len(channels) == 0 {
+		channels = defaultTelemetryChannels
+	}
+	enabled := map[cephv1.TelemetryChannel]bool{}
+	for _, channel := range channels {
+		enabled[channel] = true
+	}
+
+	settings := map[string]string{
+		"mgr/telemetry/contact":      telemetry.Contact,
+		"mgr/telemetry/description":  telemetry.Description,
+		"mgr/telemetry/organization": telemetry.Organization,
+	}
+	for _, channel := range allTelemetryChannels {
+		settings[fmt.Sprintf("mgr/telemetry/channel_%s", channel)] = strconv.FormatBool(enabled[channel])
+	}
+	return settings
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigureTelemetry(t *testing.T) {
+	settings := map[string]string{}
+	removed := []string{}
+	telemetryCommand := ""
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			logger.Infof("Command: %s %v", command, args)
+			switch {
+			case args[0] == "config" && args[1] == "set" && args[2] == "mgr":
+				settings[args[3]] = args[4]
+				return "", nil
+			case args[0] == "config" && args[1] == "rm" && args[2] == "mgr":
+				removed = append(removed, args[3])
+				return "", nil
+			case args[0] == "telemetry":
+				telemetryCommand = strings.Join(args[:4], " ")
+				return "", nil
+			}
+			return "", errors.Errorf("unexpected ceph command %q", args)
+		},
+	}
+	clusterInfo := &cephclient.ClusterInfo{CephVersion: cephver.Quincy, Context: context.TODO()}
+	c := &Cluster{clusterInfo: clusterInfo, context: &clusterd.Context{Executor: executor}}
+	c.exitCode = func(err error) (int, bool) {
+		return invalidArgErrorCode, true
+	}
+	telemetryInitWaitTime = 0
+
+	t.Run("not managed", func(t *testing.T) {
+		assert.NoError(t, c.configureTelemetry())
+		assert.Empty(t, settings)
+		assert.Empty(t, telemetryCommand)
+	})
+
+	t.Run("enabled with the ident channel", func(t *testing.T) {
+		c.spec.Mgr.Telemetry = &cephv1.TelemetrySpec{
+			Enabled:  true,
+			Channels: []cephv1.TelemetryChannel{cephv1.TelemetryChannelBasic, cephv1.TelemetryChannelIdent},
+			Contact:  "storage@example.com",
+		}
+		assert.NoError(t, c.configureTelemetry())
+		assert.Equal(t, map[string]string{
+			"mgr/telemetry/channel_basic":  "true",
+			"mgr/telemetry/channel_crash":  "false",
+			"mgr/telemetry/channel_device": "false",
+			"mgr/telemetry/channel_ident":  "true",
+			"mgr/telemetry/channel_perf":   "false",
+			"mgr/telemetry/contact":        "storage@example.com",
+		}, settings)
+		assert.ElementsMatch(t, []string{"mgr/telemetry/description", "mgr/telemetry/organization"}, removed)
+		assert.Equal(t, "telemetry on --license sharing-1-0", telemetryCommand)
+	})
+
+	t.Run("disabled with the default channels", func(t *testing.T) {
+		settings = map[string]string{}
+		removed = []string{}
+		c.spec.Mgr.Telemetry = &cephv1.TelemetrySpec{}
+		assert.NoError(t, c.configureTelemetry())
+		assert.Equal(t, "true", settings["mgr/telemetry/channel_basic"])
+		assert.Equal(t, "true", settings["mgr/telemetry/channel_crash"])
+		assert.Equal(t, "true", settings["mgr/telemetry/channel_device"])
+		assert.Equal(t, "false", settings["mgr/telemetry/channel_ident"])
+		assert.Len(t, removed, 3)
+		assert.True(t, strings.HasPrefix(telemetryCommand, "telemetry off"))
+	})
+}
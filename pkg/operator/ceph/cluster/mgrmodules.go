@@ -0,0 +1,153 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	mgrModuleErrorCheck           = "MGR_MODULE_ERROR"
+	defaultMgrModuleRecoveryDelay = 5 * time.Minute
+	maxMgrModuleRecoveryDelay     = time.Hour
+)
+
+// the messages of the failed modules are "Module '<name>' has failed: <error>"
+var failedMgrModuleRegex = regexp.MustCompile(`^Module '([^']+)' has failed:?\s*(.*)$`)
+
+// checkMgrModules reports the mgr modules that have failed in the status, such as a prometheus module that no
+// longer serves the metrics. The failed modules are only restarted with the mgr, so the active mgr is failed
+// over with a backoff to recover the modules if the recovery is enabled.
+func (c *cephStatusChecker) checkMgrModules(cephCluster *cephv1.CephCluster, status *cephclient.CephStatus, previousStatus *cephv1.CephStatus) {
+	var previous *cephv1.MgrModulesStatus
+	if previousStatus != nil {
+		previous = previousStatus.MgrModules
+	}
+
+	check, failed := status.Health.Checks[mgrModuleErrorCheck]
+	if !failed {
+		cephCluster.Status.CephStatus.MgrModules = nil
+		if previous != nil {
+			logger.Info("mgr modules no longer fail")
+			c.recordEvent(cephCluster, v1.EventTypeNormal, cephv1.MgrModulesRecoveredReason,
+				fmt.Sprintf("mgr modules no longer fail, after failing since %s", previous.Since))
+		}
+		return
+	}
+
+	modules := &cephv1.MgrModulesStatus{Failed: c.failedMgrModules(check), Since: formatTime(time.Now().UTC())}
+	if previous != nil {
+		modules.Since = previous.Since
+		modules.RecoveryAttempts = previous.RecoveryAttempts
+		modules.LastRecoveryAttempt = previous.LastRecoveryAttempt
+	}
+	cephCluster.Status.CephStatus.MgrModules = modules
+	if previous == nil || !equalFailedMgrModules(previous.Failed, modules.Failed) {
+		logger.Errorf("mgr modules have failed. %s", check.Summary.Message)
+		c.recordEvent(cephCluster, v1.EventTypeWarning, cephv1.MgrModuleFailedReason,
+			fmt.Sprintf("%s. The failed modules only restart with the mgr.", check.Summary.Message))
+	}
+
+	if !c.mgrModules.Enabled || !c.mgrModuleRecoveryDue(modules, time.Now().UTC()) {
+		return
+	}
+	if status.MgrMap.ActiveName == "" {
+		logger.Warning("no active mgr to fail over to recover the failed mgr modules")
+		return
+	}
+	logger.Infof("failing over mgr %q to recover the failed mgr modules %s", status.MgrMap.ActiveName, failedMgrModuleNames(modules.Failed))
+	if err := cephclient.MgrFail(c.context, c.clusterInfo, status.MgrMap.ActiveName); err != nil {
+		logger.Errorf("failed to recover the failed mgr modules. %v", err)
+		return
+	}
+	modules.RecoveryAttempts++
+	modules.LastRecoveryAttempt = formatTime(time.Now().UTC())
+	c.recordEvent(cephCluster, v1.EventTypeWarning, cephv1.MgrModuleRecoveryReason,
+		fmt.Sprintf("failed over mgr %q to recover the failed mgr modules %s (attempt %d)",
+			status.MgrMap.ActiveName, failedMgrModuleNames(modules.Failed), modules.RecoveryAttempts))
+}
+
+// mgrModuleRecoveryDue returns whether the backoff since the last recovery of the modules has elapsed. The backoff
+// doubles after each recovery that did not recover the modules.
+func (c *cephStatusChecker) mgrModuleRecoveryDue(modules *cephv1.MgrModulesStatus, now time.Time) bool {
+	if modules.LastRecoveryAttempt == "" {
+		return true
+	}
+	lastAttempt, err := time.Parse(time.RFC3339, modules.LastRecoveryAttempt)
+	if err != nil {
+		logger.Warningf("failed to parse the time of the last recovery of the mgr modules %q. %v", modules.LastRecoveryAttempt, err)
+		return true
+	}
+
+	backoff := defaultMgrModuleRecoveryDelay
+	if c.mgrModules.Backoff != nil {
+		backoff = c.mgrModules.Backoff.Duration
+	}
+	for i := 1; i < modules.RecoveryAttempts && backoff < maxMgrModuleRecoveryDelay; i++ {
+		backoff *= 2
+	}
+	if backoff > maxMgrModuleRecoveryDelay {
+		backoff = maxMgrModuleRecoveryDelay
+	}
+	return !now.Before(lastAttempt.Add(backoff))
+}
+
+// failedMgrModules returns the failed modules from the detail of the health check, or from its summary if the
+// health detail is not available
+func (c *cephStatusChecker) failedMgrModules(check cephclient.CheckMessage) []cephv1.FailedMgrModule {
+	messages := []string{check.Summary.Message}
+	health, err := cephclient.HealthDetail(c.context, c.clusterInfo)
+	if err != nil {
+		logger.Errorf("failed to get the failed mgr modules. %v", err)
+	} else if detail, ok := health.Checks[mgrModuleErrorCheck]; ok && len(detail.Detail) > 0 {
+		messages = []string{}
+		for _, message := range detail.Detail {
+			messages = append(messages, message.Message)
+		}
+	}
+
+	modules := []cephv1.FailedMgrModule{}
+	for _, message := range messages {
+		match := failedMgrModuleRegex.FindStringSubmatch(message)
+		if match == nil {
+			continue
+		}
+		modules = append(modules, cephv1.FailedMgrModule{Name: match[1], Error: match[2]})
+	}
+	sort.Slice(modules, func(i, j int) bool { return modules[i].Name < modules[j].Name })
+	return modules
+}
+
+func equalFailedMgrModules(a, b []cephv1.FailedMgrModule) bool {
+	return failedMgrModuleNames(a) == failedMgrModuleNames(b)
+}
+
+func failedMgrModuleNames(modules []cephv1.FailedMgrModule) string {
+	names := make([]string, 0, len(modules))
+	for _, module := range modules {
+		names = append(names, module.Name)
+	}
+	return fmt.Sprintf("%q", strings.Join(names, ", "))
+}
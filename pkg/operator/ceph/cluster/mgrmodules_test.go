@@ -0,0 +1,117 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+const testMgrModulesHealthDetail = `{"status":"HEALTH_ERR","checks":{"MGR_MODULE_ERROR":{"severity":"HEALTH_ERR",
+	"summary":{"message":"2 mgr modules have failed"},
+	"detail":[{"message":"Module 'prometheus' has failed: OSError(\"No socket could be created\")"},{"message":"Module 'dashboard' has failed: "}]}}}`
+
+func TestCheckMgrModules(t *testing.T) {
+	commands := []string{}
+	recorder := record.NewFakeRecorder(10)
+	c := &cephStatusChecker{
+		context: &clusterd.Context{Executor: &exectest.MockExecutor{
+			MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+				commands = append(commands, strings.Join(args[:2], " "))
+				if args[0] == "health" && args[1] == "detail" {
+					return testMgrModulesHealthDetail, nil
+				}
+				return "", nil
+			},
+		}},
+		clusterInfo: cephclient.AdminTestClusterInfo("rook-ceph"),
+		mgrModules:  cephv1.MgrModuleRecoverySpec{Enabled: true},
+		recorder:    recorder,
+	}
+	cephCluster := &cephv1.CephCluster{}
+	check := func(checks map[string]cephclient.CheckMessage) {
+		status := &cephclient.CephStatus{Health: cephclient.HealthStatus{Checks: checks}, MgrMap: cephclient.MgrMap{ActiveName: "a"}}
+		previousStatus := cephCluster.Status.CephStatus
+		cephCluster.Status.CephStatus = toCustomResourceStatus(cephCluster.Status, status)
+		c.checkMgrModules(cephCluster, status, previousStatus)
+	}
+	failed := map[string]cephclient.CheckMessage{
+		mgrModuleErrorCheck: {Severity: "HEALTH_ERR", Summary: cephclient.Summary{Message: "2 mgr modules have failed"}},
+	}
+
+	// the failed modules are reported and the mgr is failed over
+	check(failed)
+	modules := cephCluster.Status.CephStatus.MgrModules
+	assert.NotNil(t, modules)
+	assert.Equal(t, []cephv1.FailedMgrModule{
+		{Name: "dashboard"},
+		{Name: "prometheus", Error: `OSError("No socket could be created")`},
+	}, modules.Failed)
+	assert.Equal(t, 1, modules.RecoveryAttempts)
+	assert.NotEmpty(t, modules.LastRecoveryAttempt)
+	assert.Equal(t, []string{"health detail", "mgr fail"}, commands)
+	assert.Len(t, recorder.Events, 2)
+	assert.Contains(t, <-recorder.Events, "Warning MgrModuleFailed 2 mgr modules have failed")
+	assert.Contains(t, <-recorder.Events, `Warning MgrModuleRecovery failed over mgr "a"`)
+
+	// the mgr is not failed over again before the backoff
+	commands = []string{}
+	check(failed)
+	assert.Equal(t, []string{"health detail"}, commands)
+	assert.Equal(t, 1, cephCluster.Status.CephStatus.MgrModules.RecoveryAttempts)
+	assert.Len(t, recorder.Events, 0)
+
+	// the modules recovered
+	check(map[string]cephclient.CheckMessage{})
+	assert.Nil(t, cephCluster.Status.CephStatus.MgrModules)
+	assert.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "Normal MgrModulesRecovered")
+
+	// the modules are only reported when the recovery is disabled
+	commands = []string{}
+	c.mgrModules.Enabled = false
+	check(failed)
+	assert.Equal(t, []string{"health detail"}, commands)
+	assert.Equal(t, 0, cephCluster.Status.CephStatus.MgrModules.RecoveryAttempts)
+}
+
+func TestMgrModuleRecoveryDue(t *testing.T) {
+	c := &cephStatusChecker{mgrModules: cephv1.MgrModuleRecoverySpec{Enabled: true, Backoff: &metav1.Duration{Duration: 10 * time.Minute}}}
+	now := time.Now().UTC()
+	attempt := func(attempts int, ago time.Duration) *cephv1.MgrModulesStatus {
+		return &cephv1.MgrModulesStatus{RecoveryAttempts: attempts, LastRecoveryAttempt: formatTime(now.Add(-ago))}
+	}
+
+	assert.True(t, c.mgrModuleRecoveryDue(&cephv1.MgrModulesStatus{}, now))
+	assert.False(t, c.mgrModuleRecoveryDue(attempt(1, 9*time.Minute), now))
+	assert.True(t, c.mgrModuleRecoveryDue(attempt(1, 10*time.Minute), now))
+	// the backoff doubles after each attempt
+	assert.False(t, c.mgrModuleRecoveryDue(attempt(2, 19*time.Minute), now))
+	assert.True(t, c.mgrModuleRecoveryDue(attempt(2, 20*time.Minute), now))
+	// up to one hour
+	assert.False(t, c.mgrModuleRecoveryDue(attempt(10, 59*time.Minute), now))
+	assert.True(t, c.mgrModuleRecoveryDue(attempt(10, time.Hour), now))
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	cephutil "github.com/rook/rook/pkg/daemon/ceph/util"
+	"github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const dnsMsgr1PortName = "ceph-mon-msgr1"
+
+// reconcileDNSDiscoveryService publishes the mons behind a headless service when DNS discovery is enabled, and
+// removes the service otherwise. The service has no selector: its endpoints are the addresses of the mons in
+// the monmap, so that the names resolve to the same addresses whether the mons run on the host network, behind
+// a service, or are exported to other clusters.
+func (c *Cluster) reconcileDNSDiscoveryService() error {
+	if !c.spec.Mon.DNSDiscovery {
+		err := k8sutil.DeleteService(c.ClusterInfo.Context, c.context.Clientset, c.Namespace, cephclient.MonDNSDiscoveryServiceName)
+		if err != nil {
+			return errors.Wrapf(err, "failed to delete mon discovery service %q", cephclient.MonDNSDiscoveryServiceName)
+		}
+		return nil
+	}
+
+	// Ceph looks up the port named after "mon_dns_srv_name", and uses msgr2 for the IANA port
+	ports := []v1.ServicePort{
+		{Name: cephclient.MonDNSSrvPortName, Port: DefaultMsgr2Port, Protocol: v1.ProtocolTCP},
+	}
+	// the msgr1 port is not published when msgr2 is required
+	if !c.spec.RequireMsgr2() {
+		ports = append(ports, v1.ServicePort{Name: dnsMsgr1PortName, Port: DefaultMsgr1Port, Protocol: v1.ProtocolTCP})
+	}
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cephclient.MonDNSDiscoveryServiceName,
+			Namespace: c.Namespace,
+			Labels:    map[string]string{k8sutil.AppAttr: AppName, monClusterAttr: c.Namespace},
+		},
+		Spec: v1.ServiceSpec{
+			ClusterIP:                v1.ClusterIPNone,
+			PublishNotReadyAddresses: true,
+			Ports:                    ports,
+		},
+	}
+	// the dns records of the mons are of the ip families of the service
+	controller.ApplyServiceIPFamily(&c.spec, svc)
+	if err := c.ownerInfo.SetControllerReference(svc); err != nil {
+		return errors.Wrapf(err, "failed to set owner reference to mon discovery service %q", svc.Name)
+	}
+	if _, err := k8sutil.CreateOrUpdateService(c.ClusterInfo.Context, c.context.Clientset, c.Namespace, svc); err != nil {
+		return errors.Wrapf(err, "failed to create mon discovery service %q", svc.Name)
+	}
+
+	endpoints := &v1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      svc.Name,
+			Namespace: c.Namespace,
+			Labels:    svc.Labels,
+		},
+	}
+	if addresses := monDiscoveryAddresses(c.ClusterInfo.Monitors); len(addresses) > 0 {
+		subset := v1.EndpointSubset{Addresses: addresses}
+		for _, port := range ports {
+			subset.Ports = append(subset.Ports, v1.EndpointPort{Name: port.Name, Port: port.Port, Protocol: port.Protocol})
+		}
+		endpoints.Subsets = []v1.EndpointSubset{subset}
+	}
+	if err := c.ownerInfo.SetControllerReference(endpoints); err != nil {
+		return errors.Wrapf(err, "failed to set owner reference to mon discovery endpoints %q", endpoints.Name)
+	}
+	if _, err := k8sutil.CreateOrUpdateEndpoint(c.ClusterInfo.Context, c.context.Clientset, c.Namespace, endpoints); err != nil {
+		return errors.Wrapf(err, "failed to update mon discovery endpoints %q", endpoints.Name)
+	}
+
+	logger.Infof("mons published for dns discovery with %q", cephclient.MonDNSSrvName(c.Namespace))
+	return nil
+}
+
+// monDiscoveryAddresses returns the addresses of the mons in quorum, sorted so the endpoints are only updated when
+// the mons change
+func monDiscoveryAddresses(mons map[string]*cephclient.MonInfo) []v1.EndpointAddress {
+	addresses := []v1.EndpointAddress{}
+	for _, mon := range mons {
+		if mon.OutOfQuorum {
+			continue
+		}
+		addresses = append(addresses, v1.EndpointAddress{IP: cephutil.GetIPFromEndpoint(mon.Endpoint)})
+	}
+	sort.Slice(addresses, func(i, j int) bool { return addresses[i].IP < addresses[j].IP })
+	return addresses
+}
@@ -262,6 +262,11 @@ func (c *Cluster) checkHealth(ctx context.Context) error {
 			return errors.Wrapf(err, "failed to track out of quorum mon %q", mon.Name)
 		}
 
+		if c.spec.HealthCheck.MonFailover.Disabled {
+			logger.Warningf("mon %q NOT found in quorum and mon failover is disabled, mon will never fail over", mon.Name)
+			continue
+		}
+
 		// if the time out is set to 0 this indicate that we don't want to trigger mon failover
 		if MonOutTimeout == timeZero {
 			logger.Warningf("mon %q NOT found in quorum and health timeout is 0, mon will never fail over", mon.Name)
@@ -623,8 +628,9 @@ func (c *Cluster) failoverMon(name string) error {
 		return errors.Wrap(err, "failed to find available stretch zone")
 	}
 
-	// Start a new monitor
+	// Start a new monitor, which is not scheduled on the nodes excluded from the failover
 	m := c.newMonConfig(c.maxMonID+1, zone)
+	m.ExcludedNodes = c.spec.HealthCheck.MonFailover.ExcludedNodes
 	logger.Infof("starting new mon: %+v", m)
 
 	// Scale down the failed mon to allow a new one to start
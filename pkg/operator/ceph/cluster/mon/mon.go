@@ -138,6 +138,8 @@ type monConfig struct {
 	// from the cephcluster host network setting. If the cluster setting changes,
 	// each individual mon must keep running with the same network settings.
 	UseHostNetwork bool
+	// The nodes where the mon must not be scheduled, i.e. the nodes excluded from the mon failover
+	ExcludedNodes []string
 }
 
 type SchedulingResult struct {
@@ -506,7 +508,11 @@ func (c *Cluster) initClusterInfo(cephVersion cephver.CephVersion, clusterName s
 		return errors.Wrap(err, "failed to save mons")
 	}
 
-	k := keyring.GetSecretStore(c.context, c.ClusterInfo, c.ownerInfo)
+	encrypt, err := controller.NewKeyringEncrypter(c.context, c.ClusterInfo, &c.spec)
+	if err != nil {
+		return errors.Wrap(err, "failed to initialize keyring encryption")
+	}
+	k := keyring.GetSecretStore(c.context, c.ClusterInfo, c.ownerInfo).WithEncryption(encrypt)
 	// store the keyring which all mons share
 	if err := k.CreateOrUpdate(keyringStoreName, c.genMonSharedKeyring()); err != nil {
 		return errors.Wrap(err, "failed to save mon keyring secret")
@@ -659,6 +665,7 @@ func scheduleMonitor(c *Cluster, mon *monConfig) (*apps.Deployment, error) {
 	p.ApplyToPodSpec(&d.Spec.Template.Spec)
 	k8sutil.SetNodeAntiAffinityForPod(&d.Spec.Template.Spec, requiredDuringScheduling(&c.spec), v1.LabelHostname,
 		map[string]string{k8sutil.AppAttr: AppName}, nil)
+	excludeNodesForPod(&d.Spec.Template.Spec, mon.ExcludedNodes)
 
 	// setup storage on the canary since scheduling will be affected when
 	// monitors are configured to use persistent volumes. the pvcName is set to
@@ -739,6 +746,38 @@ func (c *Cluster) getMonPlacement(zone string) cephv1.Placement {
 	return cephv1.GetMonPlacement(c.spec.Placement)
 }
 
+// excludeNodesForPod requires the pod not to be scheduled on the nodes, in addition to all the required node affinity
+// terms of the pod
+func excludeNodesForPod(pod *v1.PodSpec, nodes []string) {
+	if len(nodes) == 0 {
+		return
+	}
+	requirement := v1.NodeSelectorRequirement{
+		Key:      metav1.ObjectNameField,
+		Operator: v1.NodeSelectorOpNotIn,
+		Values:   nodes,
+	}
+
+	if pod.Affinity == nil {
+		pod.Affinity = &v1.Affinity{}
+	}
+	if pod.Affinity.NodeAffinity == nil {
+		pod.Affinity.NodeAffinity = &v1.NodeAffinity{}
+	}
+	nodeAffinity := pod.Affinity.NodeAffinity
+	if nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &v1.NodeSelector{}
+	}
+	required := nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if len(required.NodeSelectorTerms) == 0 {
+		required.NodeSelectorTerms = []v1.NodeSelectorTerm{{}}
+	}
+	// the terms are ORed, the nodes are excluded from each of them
+	for i := range required.NodeSelectorTerms {
+		required.NodeSelectorTerms[i].MatchFields = append(required.NodeSelectorTerms[i].MatchFields, requirement)
+	}
+}
+
 func realWaitForMonitorScheduling(c *Cluster, d *apps.Deployment) (SchedulingResult, error) {
 	// target node decision, and deployment/pvc to cleanup
 	result := SchedulingResult{}
@@ -1078,14 +1117,35 @@ func (c *Cluster) saveMonConfig() error {
 		return errors.Wrap(err, "failed to write connection config for new mons")
 	}
 
-	monEndpoints := csi.MonEndpoints(c.ClusterInfo.Monitors, c.spec.RequireMsgr2())
+	if err := c.reconcileDNSDiscoveryService(); err != nil {
+		return errors.Wrap(err, "failed to reconcile mon dns discovery")
+	}
+
+	monEndpoints := csi.ClusterMonEndpoints(c.Namespace, c.ClusterInfo.Monitors, &c.spec)
 	if err := csi.SaveClusterConfig(c.context.Clientset, c.Namespace, c.ClusterInfo, &csi.CsiClusterConfigEntry{Namespace: c.ClusterInfo.Namespace, Monitors: monEndpoints}); err != nil {
 		return errors.Wrap(err, "failed to update csi cluster config")
 	}
 
+	if err := c.saveConnectionInfo(monEndpoints); err != nil {
+		return errors.Wrap(err, "failed to update connection info")
+	}
+
+	// the controllers must load the cluster info with the new mons
+	c.context.Cache.Invalidate(c.Namespace)
+
 	return nil
 }
 
+// saveConnectionInfo publishes the mon endpoints, fsid and ceph version in the connection info of the cluster
+func (c *Cluster) saveConnectionInfo(monEndpoints []string) error {
+	return controller.UpdateConnectionInfo(c.ClusterInfo.Context, c.context.Clientset, c.Namespace, c.ownerInfo, func(data map[string]string) error {
+		data[controller.ConnectionInfoFSIDKey] = c.ClusterInfo.FSID
+		data[controller.ConnectionInfoMonEndpointsKey] = strings.Join(monEndpoints, ",")
+		data[controller.ConnectionInfoCephVersionKey] = controller.GetCephVersionLabel(c.ClusterInfo.CephVersion)
+		return nil
+	})
+}
+
 func (c *Cluster) persistExpectedMonDaemons() error {
 	configMap := &v1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1232,6 +1292,34 @@ func (c *Cluster) updateMon(m *monConfig, d *apps.Deployment) error {
 	return nil
 }
 
+// createMonPVC creates the PVC of a new mon deployment. A PVC left over by a previous attempt to start the mon, e.g. a
+// failover that was reverted, is reused and expanded if needed, unless it is still being deleted. The name of the mon
+// is reused by the next failover attempt, and its pod would never start with a PVC being deleted.
+func (c *Cluster) createMonPVC(m *monConfig) error {
+	pvc, err := c.makeDeploymentPVC(m, false)
+	if err != nil {
+		return errors.Wrapf(err, "failed to make mon %s pvc", m.ResourceName)
+	}
+	_, err = c.context.Clientset.CoreV1().PersistentVolumeClaims(c.Namespace).Create(c.ClusterInfo.Context, pvc, metav1.CreateOptions{})
+	if err == nil {
+		return nil
+	}
+	if !kerrors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "failed to create mon %s pvc %s", m.ResourceName, pvc.Name)
+	}
+
+	existingPVC, err := c.context.Clientset.CoreV1().PersistentVolumeClaims(c.Namespace).Get(c.ClusterInfo.Context, pvc.Name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get existing mon %s pvc %s", m.ResourceName, pvc.Name)
+	}
+	if existingPVC.DeletionTimestamp != nil {
+		return errors.Errorf("mon %s pvc %s is still being deleted, retrying the mon start once it is gone", m.ResourceName, pvc.Name)
+	}
+	logger.Infof("reusing the existing mon %s pvc %s", m.ResourceName, pvc.Name)
+	k8sutil.ExpandPVCIfRequired(c.ClusterInfo.Context, c.context.Client, pvc, existingPVC)
+	return nil
+}
+
 // startMon creates or updates a monitor deployment.
 //
 // The node parameter specifies the node to be used as a node selector on the
@@ -1329,17 +1417,8 @@ func (c *Cluster) startMon(m *monConfig, schedule *controller.MonScheduleInfo) e
 
 	monVolumeClaim := c.monVolumeClaimTemplate(m)
 	if monVolumeClaim != nil {
-		pvc, err := c.makeDeploymentPVC(m, false)
-		if err != nil {
-			return errors.Wrapf(err, "failed to make mon %s pvc", d.Name)
-		}
-		_, err = c.context.Clientset.CoreV1().PersistentVolumeClaims(c.Namespace).Create(c.ClusterInfo.Context, pvc, metav1.CreateOptions{})
-		if err != nil {
-			if kerrors.IsAlreadyExists(err) {
-				logger.Debugf("cannot create mon %s pvc %s: already exists.", d.Name, pvc.Name)
-			} else {
-				return errors.Wrapf(err, "failed to create mon %s pvc %s", d.Name, pvc.Name)
-			}
+		if err := c.createMonPVC(m); err != nil {
+			return err
 		}
 	}
 
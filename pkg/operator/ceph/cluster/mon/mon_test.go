@@ -171,6 +171,32 @@ func TestStartMonDeployment(t *testing.T) {
 	assert.Equal(t, 0, len(deployment.Spec.Template.Spec.NodeSelector))
 }
 
+func TestCreateMonPVC(t *testing.T) {
+	namespace := "ns"
+	context, err := newTestStartCluster(t, namespace)
+	assert.NoError(t, err)
+	c := newCluster(context, namespace, true, v1.ResourceRequirements{})
+	c.ClusterInfo = clienttest.CreateTestClusterInfo(1)
+	c.spec.Mon.VolumeClaimTemplate = &v1.PersistentVolumeClaim{}
+	m := &monConfig{ResourceName: "rook-ceph-mon-d", DaemonName: "d"}
+
+	// new pvc
+	assert.NoError(t, c.createMonPVC(m))
+	pvc, err := c.context.Clientset.CoreV1().PersistentVolumeClaims(namespace).Get(c.ClusterInfo.Context, m.ResourceName, metav1.GetOptions{})
+	assert.NoError(t, err)
+
+	// the pvc left over by a previous attempt is reused
+	assert.NoError(t, c.createMonPVC(m))
+
+	// the pvc being deleted by a reverted failover is not reused
+	pvc.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	_, err = c.context.Clientset.CoreV1().PersistentVolumeClaims(namespace).Update(c.ClusterInfo.Context, pvc, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	err = c.createMonPVC(m)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "still being deleted")
+}
+
 func TestStartMonPods(t *testing.T) {
 	capnslog.SetGlobalLogLevel(capnslog.DEBUG)
 	os.Setenv("ROOK_LOG_LEVEL", "DEBUG")
@@ -769,3 +795,37 @@ func TestCheckIfArbiterReady(t *testing.T) {
 	assert.False(t, ready)
 	assert.Error(t, err)
 }
+
+func TestExcludeNodesForPod(t *testing.T) {
+	excluded := v1.NodeSelectorRequirement{Key: "metadata.name", Operator: v1.NodeSelectorOpNotIn, Values: []string{"node-a"}}
+
+	t.Run("no excluded nodes", func(t *testing.T) {
+		pod := &v1.PodSpec{}
+		excludeNodesForPod(pod, nil)
+		assert.Nil(t, pod.Affinity)
+	})
+
+	t.Run("no node affinity", func(t *testing.T) {
+		pod := &v1.PodSpec{}
+		excludeNodesForPod(pod, []string{"node-a"})
+		terms := pod.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+		assert.Equal(t, []v1.NodeSelectorTerm{{MatchFields: []v1.NodeSelectorRequirement{excluded}}}, terms)
+	})
+
+	t.Run("excluded from each required term", func(t *testing.T) {
+		role := v1.NodeSelectorRequirement{Key: "role", Operator: v1.NodeSelectorOpIn, Values: []string{"mon"}}
+		zone := v1.NodeSelectorRequirement{Key: "zone", Operator: v1.NodeSelectorOpIn, Values: []string{"a"}}
+		pod := &v1.PodSpec{Affinity: &v1.Affinity{NodeAffinity: &v1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{NodeSelectorTerms: []v1.NodeSelectorTerm{
+				{MatchExpressions: []v1.NodeSelectorRequirement{role}},
+				{MatchExpressions: []v1.NodeSelectorRequirement{zone}},
+			}},
+		}}}
+		excludeNodesForPod(pod, []string{"node-a"})
+		terms := pod.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+		assert.Equal(t, []v1.NodeSelectorTerm{
+			{MatchExpressions: []v1.NodeSelectorRequirement{role}, MatchFields: []v1.NodeSelectorRequirement{excluded}},
+			{MatchExpressions: []v1.NodeSelectorRequirement{zone}, MatchFields: []v1.NodeSelectorRequirement{excluded}},
+		}, terms)
+	})
+}
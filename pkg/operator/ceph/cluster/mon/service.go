@@ -20,6 +20,7 @@ import (
 	"fmt"
 
 	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/operator/ceph/controller"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	v1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -37,6 +38,7 @@ func (c *Cluster) createService(mon *monConfig) (*v1.Service, error) {
 			Selector: c.getLabels(mon, false, false),
 		},
 	}
+	controller.ApplyServiceIPFamily(&c.spec, svcDef)
 	err := c.ownerInfo.SetOwnerReference(svcDef)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to set owner reference to mon service %q", svcDef.Name)
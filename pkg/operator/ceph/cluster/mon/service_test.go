@@ -26,6 +26,8 @@ import (
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	"github.com/rook/rook/pkg/operator/test"
 	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -54,4 +56,51 @@ func TestCreateService(t *testing.T) {
 	assert.NoError(t, err)
 	// the clusterIP will now be set to the expected value
 	assert.Equal(t, m.PublicIP, service.Spec.ClusterIP)
+	assert.Empty(t, service.Spec.IPFamilies)
+
+	// the service of an IPv6 cluster is IPv6
+	c.spec.Network.IPFamily = cephv1.IPv6
+	m = &monConfig{ResourceName: "rook-ceph-mon-c", DaemonName: "c"}
+	service, err = c.createService(m)
+	assert.NoError(t, err)
+	assert.Equal(t, []v1.IPFamily{v1.IPv6Protocol}, service.Spec.IPFamilies)
+}
+
+func TestReconcileDNSDiscoveryService(t *testing.T) {
+	ctx := context.TODO()
+	clientset := test.New(t, 1)
+	c := New(ctx, &clusterd.Context{Clientset: clientset}, "ns", cephv1.ClusterSpec{}, client.NewMinimumOwnerInfoWithOwnerRef())
+	c.ClusterInfo = client.AdminTestClusterInfo("ns")
+	c.ClusterInfo.Monitors = map[string]*client.MonInfo{
+		"b": {Name: "b", Endpoint: "1.2.3.5:6789"},
+		"a": {Name: "a", Endpoint: "1.2.3.4:3300"},
+		"c": {Name: "c", Endpoint: "1.2.3.6:6789", OutOfQuorum: true},
+	}
+
+	// disabled
+	err := c.reconcileDNSDiscoveryService()
+	assert.NoError(t, err)
+	_, err = clientset.CoreV1().Services(c.Namespace).Get(ctx, client.MonDNSDiscoveryServiceName, metav1.GetOptions{})
+	assert.True(t, kerrors.IsNotFound(err))
+
+	// enabled
+	c.spec.Mon.DNSDiscovery = true
+	err = c.reconcileDNSDiscoveryService()
+	assert.NoError(t, err)
+	svc, err := clientset.CoreV1().Services(c.Namespace).Get(ctx, client.MonDNSDiscoveryServiceName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "None", svc.Spec.ClusterIP)
+	assert.Equal(t, "ceph-mon", svc.Spec.Ports[0].Name)
+	assert.Equal(t, int32(3300), svc.Spec.Ports[0].Port)
+	endpoints, err := clientset.CoreV1().Endpoints(c.Namespace).Get(ctx, client.MonDNSDiscoveryServiceName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, endpoints.Subsets, 1)
+	assert.Equal(t, []v1.EndpointAddress{{IP: "1.2.3.4"}, {IP: "1.2.3.5"}}, endpoints.Subsets[0].Addresses)
+
+	// disabled again
+	c.spec.Mon.DNSDiscovery = false
+	err = c.reconcileDNSDiscoveryService()
+	assert.NoError(t, err)
+	_, err = clientset.CoreV1().Services(c.Namespace).Get(ctx, client.MonDNSDiscoveryServiceName, metav1.GetOptions{})
+	assert.True(t, kerrors.IsNotFound(err))
 }
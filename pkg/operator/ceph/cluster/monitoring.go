@@ -22,13 +22,14 @@ import (
 
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/daemon/ceph/preflight"
 	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
 	"github.com/rook/rook/pkg/operator/ceph/cluster/osd"
 	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
 )
 
 var (
-	monitorDaemonList = []string{"mon", "osd", "status"}
+	monitorDaemonList = []string{"mon", "osd", "status", "scrub"}
 )
 
 func (c *ClusterController) configureCephMonitoring(cluster *cluster, clusterInfo *cephclient.ClusterInfo) {
@@ -72,6 +73,9 @@ func isMonitoringEnabled(daemon string, clusterSpec *cephv1.ClusterSpec) bool {
 
 	case "status":
 		return !clusterSpec.HealthCheck.DaemonHealth.Status.Disabled
+
+	case "scrub":
+		return clusterSpec.HealthCheck.ScrubReport.Enabled
 	}
 
 	return false
@@ -86,14 +90,24 @@ func (c *ClusterController) startMonitoringCheck(cluster *cluster, clusterInfo *
 
 	case "osd":
 		if !cluster.Spec.External.Enable {
-			c.osdChecker = osd.NewOSDHealthMonitor(c.context, clusterInfo, cluster.Spec.RemoveOSDsIfOutAndSafeToRemove, cluster.Spec.HealthCheck)
+			c.osdChecker = osd.NewOSDHealthMonitor(c.context, clusterInfo, cluster.Spec.RemoveOSDsIfOutAndSafeToRemove, cluster.Spec.Storage.EphemeralDevices, cluster.Spec.HealthCheck)
 			logger.Infof("enabling ceph %s monitoring goroutine for cluster %q", daemon, cluster.Namespace)
 			go c.osdChecker.Start(cluster.monitoringRoutines, daemon)
 		}
 
 	case "status":
 		cephChecker := newCephStatusChecker(c.context, clusterInfo, cluster.Spec)
+		cephChecker.recorder = c.recorder
+		cephChecker.checkNodeClock = func(node string) (preflight.Result, error) {
+			args := []string{"ceph", "preflight", "--checks", string(cephv1.PreflightCheckTimeSync)}
+			return runPreflightJob(cluster, c.rookImage, node, args, nodeClockCheckTimeout)
+		}
 		logger.Infof("enabling ceph %s monitoring goroutine for cluster %q", daemon, cluster.Namespace)
 		go cephChecker.checkCephStatus(cluster.monitoringRoutines, daemon)
+
+	case "scrub":
+		scrubReporter := newScrubReporter(c.context, clusterInfo, cluster.Spec.HealthCheck.ScrubReport)
+		logger.Infof("enabling ceph %s monitoring goroutine for cluster %q", daemon, cluster.Namespace)
+		go scrubReporter.reportScrubs(cluster.monitoringRoutines, daemon)
 	}
 }
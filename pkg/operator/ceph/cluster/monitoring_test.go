@@ -35,6 +35,8 @@ func TestIsMonitoringEnabled(t *testing.T) {
 	}{
 		{"isEnabled", args{"mon", &cephv1.ClusterSpec{}}, true},
 		{"isDisabled", args{"mon", &cephv1.ClusterSpec{HealthCheck: cephv1.CephClusterHealthCheckSpec{DaemonHealth: cephv1.DaemonHealthSpec{Monitor: cephv1.HealthCheckSpec{Disabled: true}}}}}, false},
+		{"scrubReportDisabledByDefault", args{"scrub", &cephv1.ClusterSpec{}}, false},
+		{"scrubReportEnabled", args{"scrub", &cephv1.ClusterSpec{HealthCheck: cephv1.CephClusterHealthCheckSpec{ScrubReport: cephv1.ScrubReportSpec{Enabled: true}}}}, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -127,6 +127,7 @@ func (r *ReconcileNode) createOrUpdateCephExporter(node corev1.Node, tolerations
 				PriorityClassName: cephv1.GetCephExporterPriorityClassName(cephCluster.Spec.PriorityClassNames),
 			},
 		}
+		controller.DecryptKeyringVolumes(&cephCluster.Spec, r.opConfig.Image, &deploy.Spec.Template.Spec, keyring.Volume().Admin().Secret.SecretName)
 		cephv1.GetCephExporterAnnotations(cephCluster.Spec.Annotations).ApplyToObjectMeta(&deploy.Spec.Template.ObjectMeta)
 		applyPrometheusAnnotations(cephCluster, &deploy.Spec.Template.ObjectMeta)
 
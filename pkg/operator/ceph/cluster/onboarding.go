@@ -0,0 +1,167 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// onboardedNodesAnnotation keeps the storage nodes added by the operator, so that the nodes added by the admin are
+// never removed
+const onboardedNodesAnnotation = "ceph.rook.io/onboarded-nodes"
+
+// nodeOnboardingEnabled returns whether the nodes are onboarded. The onboarding is ignored when all the nodes are
+// used, and when no node selector is set, since it would add all the nodes.
+func nodeOnboardingEnabled(storage cephv1.StorageScopeSpec) bool {
+	return storage.NodeOnboarding.Enabled && !storage.UseAllNodes && len(storage.NodeOnboarding.NodeSelector) > 0
+}
+
+// nodeHostname returns the name of the node in the storage nodes, which is its hostname label
+func nodeHostname(node *corev1.Node) string {
+	if hostname := node.Labels[corev1.LabelHostname]; hostname != "" {
+		return hostname
+	}
+	return node.Name
+}
+
+func onboardedNodes(cluster *cephv1.CephCluster) sets.Set[string] {
+	nodes := sets.New[string]()
+	for _, name := range strings.Split(cluster.GetAnnotations()[onboardedNodesAnnotation], ",") {
+		if name != "" {
+			nodes.Insert(name)
+		}
+	}
+	return nodes
+}
+
+func storageHasNode(storage cephv1.StorageScopeSpec, name string) bool {
+	for _, node := range storage.Nodes {
+		if node.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// onboardingAction returns whether the node must be added to or removed from the storage nodes. The nodes that are
+// cordoned, e.g. while they are drained, are neither added nor removed.
+func onboardingAction(storage cephv1.StorageScopeSpec, onboarded sets.Set[string], node *corev1.Node) (add, remove bool) {
+	onboarding := storage.NodeOnboarding
+	name := nodeHostname(node)
+	if !k8sutil.GetNodeSchedulable(*node) {
+		return false, false
+	}
+	if !labels.SelectorFromSet(onboarding.NodeSelector).Matches(labels.Set(node.Labels)) {
+		return false, onboarding.RemoveNodes && onboarded.Has(name) && storageHasNode(storage, name)
+	}
+	return !storageHasNode(storage, name) && k8sutil.NodeIsReady(*node), false
+}
+
+// onboardNodes adds the nodes matching the node selector of the onboarding to the storage nodes of the cluster, and
+// removes the onboarded nodes that are deleted or no longer match when the nodes are removed. The nodes are added
+// with the device selection of the cluster. It returns whether the storage nodes were changed.
+func onboardNodes(cluster *cephv1.CephCluster, nodes []corev1.Node) bool {
+	storage := &cluster.Spec.Storage
+	if !nodeOnboardingEnabled(*storage) {
+		return false
+	}
+	onboarded := onboardedNodes(cluster)
+
+	existing := sets.New[string]()
+	added := []string{}
+	removed := sets.New[string]()
+	for i := range nodes {
+		name := nodeHostname(&nodes[i])
+		existing.Insert(name)
+		add, remove := onboardingAction(*storage, onboarded, &nodes[i])
+		if add {
+			added = append(added, name)
+		}
+		if remove {
+			removed.Insert(name)
+		}
+	}
+	for name := range onboarded {
+		if !existing.Has(name) && storage.NodeOnboarding.RemoveNodes && storageHasNode(*storage, name) {
+			removed.Insert(name)
+		}
+	}
+	if len(added) == 0 && removed.Len() == 0 {
+		return false
+	}
+
+	sort.Strings(added)
+	for _, name := range added {
+		logger.Infof("adding node %q matching the node selector of the onboarding to the storage nodes of cluster %q", name, cluster.Namespace)
+		storage.Nodes = append(storage.Nodes, cephv1.Node{Name: name})
+		onboarded.Insert(name)
+	}
+	remaining := []cephv1.Node{}
+	for _, node := range storage.Nodes {
+		if removed.Has(node.Name) {
+			logger.Infof("removing onboarded node %q from the storage nodes of cluster %q", node.Name, cluster.Namespace)
+			continue
+		}
+		remaining = append(remaining, node)
+	}
+	storage.Nodes = remaining
+
+	// the onboarded nodes that are no longer in the storage nodes, e.g. removed by the admin, are forgotten
+	for name := range onboarded {
+		if !storageHasNode(*storage, name) {
+			onboarded.Delete(name)
+		}
+	}
+	annotations := cluster.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	if onboarded.Len() == 0 {
+		delete(annotations, onboardedNodesAnnotation)
+	} else {
+		annotations[onboardedNodesAnnotation] = strings.Join(sets.List(onboarded), ",")
+	}
+	cluster.SetAnnotations(annotations)
+	return true
+}
+
+// reconcileNodeOnboarding updates the storage nodes of the cluster with the onboarded nodes. It returns whether the
+// storage nodes were updated, in which case the update of the spec triggers the orchestration with the new nodes.
+func (r *ReconcileCephCluster) reconcileNodeOnboarding(cephCluster *cephv1.CephCluster) (bool, error) {
+	if !nodeOnboardingEnabled(cephCluster.Spec.Storage) {
+		return false, nil
+	}
+	nodes := &corev1.NodeList{}
+	if err := r.client.List(r.opManagerContext, nodes); err != nil {
+		return false, errors.Wrap(err, "failed to list the nodes to onboard")
+	}
+	if !onboardNodes(cephCluster, nodes.Items) {
+		return false, nil
+	}
+	if err := r.client.Update(r.opManagerContext, cephCluster); err != nil {
+		return false, errors.Wrap(err, "failed to update the storage nodes with the onboarded nodes")
+	}
+	return true, nil
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newOnboardingTestNode(name string, labels map[string]string) corev1.Node {
+	nodeLabels := map[string]string{corev1.LabelHostname: name}
+	for key, value := range labels {
+		nodeLabels[key] = value
+	}
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name + ".example.com", Labels: nodeLabels},
+		Status:     corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}}},
+	}
+}
+
+func storageNodeNames(cluster *cephv1.CephCluster) []string {
+	names := []string{}
+	for _, node := range cluster.Spec.Storage.Nodes {
+		names = append(names, node.Name)
+	}
+	return names
+}
+
+func TestOnboardNodes(t *testing.T) {
+	storageRole := map[string]string{"storage-role": "ceph"}
+	cluster := fakeCluster("rook-ceph")
+	cluster.Spec.Storage.Nodes = []cephv1.Node{{Name: "manual"}}
+	cluster.Spec.Storage.NodeOnboarding = cephv1.NodeOnboardingSpec{Enabled: true, NodeSelector: storageRole}
+
+	notReady := newOnboardingTestNode("not-ready", storageRole)
+	notReady.Status.Conditions = nil
+	cordoned := newOnboardingTestNode("cordoned", storageRole)
+	cordoned.Spec.Unschedulable = true
+	nodes := []corev1.Node{
+		newOnboardingTestNode("manual", nil),
+		newOnboardingTestNode("b", storageRole),
+		newOnboardingTestNode("a", storageRole),
+		newOnboardingTestNode("other", map[string]string{"storage-role": "none"}),
+		notReady,
+		cordoned,
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		c := cluster.DeepCopy()
+		c.Spec.Storage.NodeOnboarding.Enabled = false
+		assert.False(t, onboardNodes(c, nodes))
+		c.Spec.Storage.NodeOnboarding.Enabled = true
+		c.Spec.Storage.UseAllNodes = true
+		assert.False(t, onboardNodes(c, nodes))
+		c.Spec.Storage.UseAllNodes = false
+		c.Spec.Storage.NodeOnboarding.NodeSelector = nil
+		assert.False(t, onboardNodes(c, nodes))
+	})
+
+	t.Run("add the matching nodes", func(t *testing.T) {
+		assert.True(t, onboardNodes(cluster, nodes))
+		assert.Equal(t, []string{"manual", "a", "b"}, storageNodeNames(cluster))
+		assert.Equal(t, "a,b", cluster.Annotations[onboardedNodesAnnotation])
+		assert.False(t, onboardNodes(cluster, nodes))
+	})
+
+	t.Run("nodes are kept without removal", func(t *testing.T) {
+		assert.False(t, onboardNodes(cluster, nodes[:1]))
+		assert.Equal(t, []string{"manual", "a", "b"}, storageNodeNames(cluster))
+	})
+
+	t.Run("remove the deleted and unlabeled nodes", func(t *testing.T) {
+		cluster.Spec.Storage.NodeOnboarding.RemoveNodes = true
+		unlabeled := newOnboardingTestNode("a", nil)
+		// the manual node does not match either, but was not onboarded
+		assert.True(t, onboardNodes(cluster, []corev1.Node{nodes[0], unlabeled}))
+		assert.Equal(t, []string{"manual"}, storageNodeNames(cluster))
+		assert.NotContains(t, cluster.Annotations, onboardedNodesAnnotation)
+	})
+
+	t.Run("cordoned nodes are kept", func(t *testing.T) {
+		assert.True(t, onboardNodes(cluster, nodes))
+		draining := newOnboardingTestNode("a", nil)
+		draining.Spec.Unschedulable = true
+		assert.False(t, onboardNodes(cluster, []corev1.Node{nodes[0], draining, nodes[1]}))
+		assert.Equal(t, []string{"manual", "a", "b"}, storageNodeNames(cluster))
+	})
+}
+
+func TestReconcileNodeOnboarding(t *testing.T) {
+	ctx := context.TODO()
+	cluster := fakeCluster("rook-ceph")
+	cluster.Spec.Storage.NodeOnboarding = cephv1.NodeOnboardingSpec{Enabled: true, NodeSelector: map[string]string{"storage-role": "ceph"}}
+	node := newOnboardingTestNode("a", map[string]string{"storage-role": "ceph"})
+	s := runtime.NewScheme()
+	assert.NoError(t, cephv1.AddToScheme(s))
+	assert.NoError(t, corev1.AddToScheme(s))
+	cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(cluster, &node).Build()
+	r := &ReconcileCephCluster{client: cl, opManagerContext: ctx}
+
+	updated, err := r.reconcileNodeOnboarding(cluster)
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	current := &cephv1.CephCluster{}
+	assert.NoError(t, cl.Get(ctx, types.NamespacedName{Name: "rook-ceph", Namespace: "rook-ceph"}, current))
+	assert.Equal(t, []string{"a"}, storageNodeNames(current))
+
+	updated, err = r.reconcileNodeOnboarding(current)
+	assert.NoError(t, err)
+	assert.False(t, updated)
+
+	// the predicate of the node watcher reconciles when the node is deleted or unlabeled
+	current.Spec.Storage.NodeOnboarding.RemoveNodes = true
+	assert.NoError(t, cl.Update(ctx, current))
+	clientCluster := newClientCluster(cl, "rook-ceph", &clusterd.Context{})
+	assert.False(t, clientCluster.onNodeOnboarding(&node, false))
+	assert.True(t, clientCluster.onNodeOnboarding(&node, true))
+	unlabeled := newOnboardingTestNode("a", nil)
+	assert.True(t, clientCluster.onNodeOnboarding(&unlabeled, false))
+	added := newOnboardingTestNode("b", map[string]string{"storage-role": "ceph"})
+	assert.True(t, clientCluster.onNodeOnboarding(&added, false))
+}
@@ -18,6 +18,7 @@ limitations under the License.
 package config
 
 import (
+	"encoding/json"
 	"strconv"
 )
 
@@ -106,4 +107,12 @@ func convertToIntIgnoreErr(raw string) int {
 type ConfiguredDevice struct {
 	ID          string      `json:"id"`
 	StoreConfig StoreConfig `json:"storeConfig"`
+	// IsFilter is true when the ID is a regular expression matching the device names
+	IsFilter bool `json:"isFilter,omitempty"`
+}
+
+// DriveGroup is a ceph-volume drive group spec passed to the OSD prepare job.
+type DriveGroup struct {
+	Name string          `json:"name"`
+	Spec json.RawMessage `json:"spec"`
 }
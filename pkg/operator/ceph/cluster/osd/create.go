@@ -108,6 +108,12 @@ func (c *createConfig) createNewOSDsFromStatus(
 		}
 	}
 
+	if !status.PvcBackedOSD && c.cluster.spec.Storage.SwappedDevices.Enabled {
+		if err := c.cluster.replaceSwappedOSDs(nodeOrPVCName, status.OSDs); err != nil {
+			logger.Errorf("failed to replace the osds whose device was swapped on node %q. %v", nodeOrPVCName, err)
+		}
+	}
+
 	c.doneWithStatus(nodeOrPVCName)
 }
 
@@ -319,6 +325,15 @@ func (c *Cluster) startProvisioningOverNodes(config *provisionConfig, errs *prov
 			metadataDevice: metadataDevice,
 		}
 
+		if len(c.ValidStorage.DriveGroups) > 0 {
+			driveGroups, err := c.getDriveGroupsForNode(n.Name)
+			if err != nil {
+				errs.addError("failed to provision OSDs on node %q. failed to get its drive groups. %v", n.Name, err)
+				continue
+			}
+			osdProps.driveGroups = driveGroups
+		}
+
 		// update the orchestration status of this node to the starting state
 		status := OrchestrationStatus{Status: OrchestrationStatusStarting}
 		cmName := c.updateOSDStatus(n.Name, status)
@@ -417,3 +432,16 @@ func createDaemonOnNode(c *Cluster, osd OSDInfo, nodeName string, config *provis
 
 	return nil
 }
+
+// getDriveGroupsForNode returns the drive groups selecting the node by its labels
+func (c *Cluster) getDriveGroupsForNode(nodeName string) ([]cephv1.DriveGroup, error) {
+	node, err := getNode(c.clusterInfo.Context, c.context.Clientset, nodeName)
+	if err != nil {
+		return nil, err
+	}
+	driveGroups := c.ValidStorage.DriveGroupsForNode(node.Labels)
+	if len(driveGroups) > 0 {
+		logger.Infof("provisioning the OSDs of node %q from %d drive group(s)", nodeName, len(driveGroups))
+	}
+	return driveGroups, nil
+}
@@ -639,6 +639,26 @@ func Test_startProvisioningOverNodes(t *testing.T) {
 	})
 }
 
+func TestGetDriveGroupsForNode(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "node1",
+			Labels: map[string]string{corev1.LabelHostname: "node1", "disk": "nvme"},
+		},
+	})
+	clusterInfo := cephclient.AdminTestClusterInfo("ns")
+	c := New(&clusterd.Context{Clientset: clientset}, clusterInfo, cephv1.ClusterSpec{}, "rook/rook:myversion")
+	c.ValidStorage.DriveGroups = []cephv1.DriveGroup{
+		{Name: "nvme", NodeSelector: map[string]string{"disk": "nvme"}},
+		{Name: "hdd", NodeSelector: map[string]string{"disk": "hdd"}},
+	}
+
+	driveGroups, err := c.getDriveGroupsForNode("node1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(driveGroups))
+	assert.Equal(t, "nvme", driveGroups[0].Name)
+}
+
 func newDummyPVC(name, namespace string, capacity string, storageClassName string) corev1.PersistentVolumeClaim {
 	volMode := corev1.PersistentVolumeBlock
 	return corev1.PersistentVolumeClaim{
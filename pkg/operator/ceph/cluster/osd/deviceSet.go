@@ -153,26 +153,32 @@ func (c *Cluster) createDeviceSetPVCsForIndex(newDeviceSet cephv1.StorageClassDe
 		}
 		typesFound.Insert(pvcTemplate.Name)
 
-		pvc, err := c.createDeviceSetPVC(existingPVCs, newDeviceSet.Name, pvcTemplate, setIndex)
-		if err != nil {
-			errs.addError("failed to provision PVC for device set %q index %d. %v", newDeviceSet.Name, setIndex, err)
-			continue
-		}
-
 		// The PVC type must be from a predefined set such as "data", "metadata", and "wal". These names must be enforced if the wal/db are specified
 		// with a separate device, but if there is a single volume template we can assume it is always the data template.
 		pvcType := pvcTemplate.Name
 		if len(newDeviceSet.VolumeClaimTemplates) == 1 {
 			pvcType = bluestorePVCData
 		}
+		if !isDeviceSetPVCType(pvcType) {
+			// the PVC would be provisioned but never consumed by the OSD
+			errs.addError("invalid volume claim template %q for device set %q. the templates must be named %q, %q or %q", pvcTemplate.Name, newDeviceSet.Name, bluestorePVCData, bluestorePVCMetadata, bluestorePVCWal)
+			continue
+		}
+
+		pvc, err := c.createDeviceSetPVC(existingPVCs, newDeviceSet.Name, pvcTemplate, setIndex, newDeviceSet.Provider)
+		if err != nil {
+			errs.addError("failed to provision PVC for device set %q index %d. %v", newDeviceSet.Name, setIndex, err)
+			continue
+		}
 
+		// the crush settings of the OSD are taken from the data template only
 		if pvcType == bluestorePVCData {
 			pvcSize := pvc.Spec.Resources.Requests[v1.ResourceStorage]
 			dataSize = pvcSize.String()
 			crushDeviceClass = pvcTemplate.Annotations["crushDeviceClass"]
+			crushInitialWeight = pvcTemplate.Annotations["crushInitialWeight"]
+			crushPrimaryAffinity = pvcTemplate.Annotations["crushPrimaryAffinity"]
 		}
-		crushInitialWeight = pvcTemplate.Annotations["crushInitialWeight"]
-		crushPrimaryAffinity = pvcTemplate.Annotations["crushPrimaryAffinity"]
 
 		pvcSources[pvcType] = v1.PersistentVolumeClaimVolumeSource{
 			ClaimName: pvc.GetName(),
@@ -199,7 +205,12 @@ func (c *Cluster) createDeviceSetPVCsForIndex(newDeviceSet cephv1.StorageClassDe
 	}
 }
 
-func (c *Cluster) createDeviceSetPVC(existingPVCs map[string]*v1.PersistentVolumeClaim, deviceSetName string, pvcTemplate v1.PersistentVolumeClaim, setIndex int) (*v1.PersistentVolumeClaim, error) {
+// isDeviceSetPVCType returns whether the PVC type is one of the devices an OSD can consume
+func isDeviceSetPVCType(pvcType string) bool {
+	return pvcType == bluestorePVCData || pvcType == bluestorePVCMetadata || pvcType == bluestorePVCWal
+}
+
+func (c *Cluster) createDeviceSetPVC(existingPVCs map[string]*v1.PersistentVolumeClaim, deviceSetName string, pvcTemplate v1.PersistentVolumeClaim, setIndex int, provider *cephv1.DeviceSetProviderSpec) (*v1.PersistentVolumeClaim, error) {
 	// old labels and PVC ID for backward compatibility
 	pvcID := legacyDeviceSetPVCID(deviceSetName, setIndex)
 
@@ -210,7 +221,7 @@ func (c *Cluster) createDeviceSetPVC(existingPVCs map[string]*v1.PersistentVolum
 		pvcID = deviceSetPVCID(deviceSetName, pvcTemplate.GetName(), setIndex)
 		existingPVC = existingPVCs[pvcID]
 	}
-	pvc := makeDeviceSetPVC(deviceSetName, pvcID, setIndex, pvcTemplate, c.clusterInfo.Namespace)
+	pvc := makeDeviceSetPVC(deviceSetName, pvcID, setIndex, pvcTemplate, c.clusterInfo.Namespace, provider)
 	err := c.clusterInfo.OwnerInfo.SetControllerReference(pvc)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to set owner reference to osd pvc %q", pvc.Name)
@@ -221,6 +232,11 @@ func (c *Cluster) createDeviceSetPVC(existingPVCs map[string]*v1.PersistentVolum
 
 		// Update the PVC in case the size changed
 		k8sutil.ExpandPVCIfRequired(c.clusterInfo.Context, c.context.Client, pvc, existingPVC)
+
+		// Update the PVC in case the provider parameters of its disk changed
+		if err := c.updateDeviceSetPVCProvider(existingPVC, provider); err != nil {
+			return nil, err
+		}
 		return existingPVC, nil
 	}
 
@@ -234,7 +250,7 @@ func (c *Cluster) createDeviceSetPVC(existingPVCs map[string]*v1.PersistentVolum
 	return deployedPVC, nil
 }
 
-func makeDeviceSetPVC(deviceSetName, pvcID string, setIndex int, pvcTemplate v1.PersistentVolumeClaim, namespace string) *v1.PersistentVolumeClaim {
+func makeDeviceSetPVC(deviceSetName, pvcID string, setIndex int, pvcTemplate v1.PersistentVolumeClaim, namespace string, provider *cephv1.DeviceSetProviderSpec) *v1.PersistentVolumeClaim {
 	pvcLabels := makeStorageClassDeviceSetPVCLabel(deviceSetName, pvcID, setIndex)
 
 	// Add user provided labels to pvcTemplates
@@ -242,6 +258,23 @@ func makeDeviceSetPVC(deviceSetName, pvcID string, setIndex int, pvcTemplate v1.
 		pvcLabels[k] = v
 	}
 
+	// Add the provider labels and annotations without modifying the annotations of the template
+	pvcAnnotations := pvcTemplate.Annotations
+	if provider != nil {
+		for k, v := range provider.Labels {
+			pvcLabels[k] = v
+		}
+		if len(provider.Annotations) > 0 {
+			pvcAnnotations = map[string]string{}
+			for k, v := range pvcTemplate.Annotations {
+				pvcAnnotations[k] = v
+			}
+			for k, v := range provider.Annotations {
+				pvcAnnotations[k] = v
+			}
+		}
+	}
+
 	// pvc naming format rook-ceph-osd-<deviceSetName>-<SetNumber>-<PVCIndex>-<generatedSuffix>
 	return &v1.PersistentVolumeClaim{
 		ObjectMeta: metav1.ObjectMeta{
@@ -250,12 +283,50 @@ func makeDeviceSetPVC(deviceSetName, pvcID string, setIndex int, pvcTemplate v1.
 			GenerateName: pvcID,
 			Namespace:    namespace,
 			Labels:       pvcLabels,
-			Annotations:  pvcTemplate.Annotations,
+			Annotations:  pvcAnnotations,
 		},
 		Spec: pvcTemplate.Spec,
 	}
 }
 
+// updateDeviceSetPVCProvider updates the provider labels and annotations of an existing PVC. The CSI drivers
+// supporting the modification of the disks (e.g. their IOPS or throughput) apply the updated annotations.
+func (c *Cluster) updateDeviceSetPVCProvider(pvc *v1.PersistentVolumeClaim, provider *cephv1.DeviceSetProviderSpec) error {
+	if provider == nil {
+		return nil
+	}
+	updated := false
+	for k, v := range provider.Labels {
+		if pvc.Labels[k] != v {
+			if pvc.Labels == nil {
+				pvc.Labels = map[string]string{}
+			}
+			pvc.Labels[k] = v
+			updated = true
+		}
+	}
+	for k, v := range provider.Annotations {
+		if pvc.Annotations[k] != v {
+			if pvc.Annotations == nil {
+				pvc.Annotations = map[string]string{}
+			}
+			pvc.Annotations[k] = v
+			updated = true
+		}
+	}
+	if !updated {
+		return nil
+	}
+
+	logger.Infof("updating the provider labels and annotations of OSD PVC %q", pvc.Name)
+	updatedPVC, err := c.context.Clientset.CoreV1().PersistentVolumeClaims(pvc.Namespace).Update(c.clusterInfo.Context, pvc, metav1.UpdateOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to update the provider labels and annotations of PVC %q", pvc.Name)
+	}
+	*pvc = *updatedPVC
+	return nil
+}
+
 // GetExistingPVCs fetches the list of OSD PVCs
 func GetExistingPVCs(ctx context.Context, clusterdContext *clusterd.Context, namespace string) (map[string]*v1.PersistentVolumeClaim, map[string]sets.Set[string], error) {
 	selector := metav1.ListOptions{LabelSelector: CephDeviceSetPVCIDLabelKey}
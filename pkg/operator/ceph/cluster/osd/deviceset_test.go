@@ -18,6 +18,7 @@ package osd
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
@@ -271,6 +272,62 @@ func TestPrepareDeviceSetsWithCrushParams(t *testing.T) {
 	assert.Equal(t, 1, len(pvcs.Items))
 }
 
+func TestPrepareDeviceSetsWithMetadataAndWal(t *testing.T) {
+	ctx := context.TODO()
+	clientset := testexec.NewComplexClientset(t)
+	context := &clusterd.Context{
+		Clientset: clientset,
+	}
+	data := testVolumeClaim("data")
+	data.Annotations = map[string]string{
+		"crushDeviceClass":   "hdd",
+		"crushInitialWeight": "0.5",
+	}
+	deviceSet := cephv1.StorageClassDeviceSet{
+		Name:                 "tiered",
+		Count:                1,
+		VolumeClaimTemplates: []corev1.PersistentVolumeClaim{data, testVolumeClaim("metadata"), testVolumeClaim("wal")},
+	}
+	cluster := &Cluster{
+		context:     context,
+		clusterInfo: client.AdminTestClusterInfo("testns"),
+		spec: cephv1.ClusterSpec{
+			Storage: cephv1.StorageScopeSpec{StorageClassDeviceSets: []cephv1.StorageClassDeviceSet{deviceSet}},
+		},
+	}
+
+	errs := newProvisionErrors()
+	cluster.prepareStorageClassDeviceSets(errs)
+	assert.Equal(t, 0, errs.len())
+	assert.Equal(t, 1, len(cluster.deviceSets))
+	assert.Equal(t, 3, len(cluster.deviceSets[0].PVCSources))
+	assert.True(t, strings.HasPrefix(cluster.deviceSets[0].PVCSources[bluestorePVCData].ClaimName, "tiered-data-0"))
+	assert.True(t, strings.HasPrefix(cluster.deviceSets[0].PVCSources[bluestorePVCMetadata].ClaimName, "tiered-metadata-0"))
+	assert.True(t, strings.HasPrefix(cluster.deviceSets[0].PVCSources[bluestorePVCWal].ClaimName, "tiered-wal-0"))
+	// the crush settings of the data template are not reset by the other templates
+	assert.Equal(t, "hdd", cluster.deviceSets[0].CrushDeviceClass)
+	assert.Equal(t, "0.5", cluster.deviceSets[0].CrushInitialWeight)
+
+	pvcs, err := clientset.CoreV1().PersistentVolumeClaims(cluster.clusterInfo.Namespace).List(ctx, metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(pvcs.Items))
+
+	// a template with an unknown name is not provisioned
+	deviceSet.Name = "unknown"
+	deviceSet.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{testVolumeClaim("data"), testVolumeClaim("db")}
+	cluster.spec.Storage.StorageClassDeviceSets = []cephv1.StorageClassDeviceSet{deviceSet}
+	cluster.deviceSets = nil
+	errs = newProvisionErrors()
+	cluster.prepareStorageClassDeviceSets(errs)
+	assert.Equal(t, 1, errs.len())
+	assert.Equal(t, 1, len(cluster.deviceSets))
+	assert.Equal(t, 1, len(cluster.deviceSets[0].PVCSources))
+
+	pvcs, err = clientset.CoreV1().PersistentVolumeClaims(cluster.clusterInfo.Namespace).List(ctx, metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, len(pvcs.Items))
+}
+
 func TestPVCName(t *testing.T) {
 	id := deviceSetPVCID("mydeviceset", "a", 0)
 	assert.Equal(t, "mydeviceset-a-0", id)
@@ -284,3 +341,51 @@ func TestPVCName(t *testing.T) {
 	id = deviceSetPVCID("device.set.with.dots", "b", 10)
 	assert.Equal(t, "device-set-with-dots-b-10", id)
 }
+
+func TestDeviceSetPVCProvider(t *testing.T) {
+	ctx := context.TODO()
+	claim := testVolumeClaim("data")
+	claim.Annotations = map[string]string{"crushDeviceClass": "ssd"}
+	provider := &cephv1.DeviceSetProviderSpec{
+		Annotations: map[string]string{"ebs.csi.aws.com/iops": "6000"},
+		Labels:      map[string]string{"cost-center": "storage"},
+	}
+
+	t.Run("new pvc", func(t *testing.T) {
+		pvc := makeDeviceSetPVC("mydata", "mydata-data-0", 0, claim, "testns", provider)
+		assert.Equal(t, map[string]string{"crushDeviceClass": "ssd", "ebs.csi.aws.com/iops": "6000"}, pvc.Annotations)
+		assert.Equal(t, "storage", pvc.Labels["cost-center"])
+		assert.Equal(t, "mydata", pvc.Labels[CephDeviceSetLabelKey])
+		// the annotations of the template are not modified
+		assert.Equal(t, map[string]string{"crushDeviceClass": "ssd"}, claim.Annotations)
+
+		pvc = makeDeviceSetPVC("mydata", "mydata-data-0", 0, claim, "testns", nil)
+		assert.Equal(t, claim.Annotations, pvc.Annotations)
+	})
+
+	t.Run("existing pvc", func(t *testing.T) {
+		clientset := testexec.New(t, 1)
+		cluster := &Cluster{
+			context:     &clusterd.Context{Clientset: clientset},
+			clusterInfo: client.AdminTestClusterInfo("testns"),
+		}
+		pvc := makeDeviceSetPVC("mydata", "mydata-data-0", 0, claim, "testns", nil)
+		pvc.Name = "mydata-data-0-abcde"
+		pvc, err := clientset.CoreV1().PersistentVolumeClaims("testns").Create(ctx, pvc, metav1.CreateOptions{})
+		assert.NoError(t, err)
+
+		assert.NoError(t, cluster.updateDeviceSetPVCProvider(pvc, provider))
+		pvc, err = clientset.CoreV1().PersistentVolumeClaims("testns").Get(ctx, pvc.Name, metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, "6000", pvc.Annotations["ebs.csi.aws.com/iops"])
+		assert.Equal(t, "ssd", pvc.Annotations["crushDeviceClass"])
+		assert.Equal(t, "storage", pvc.Labels["cost-center"])
+
+		// the iops are modified on the existing pvc
+		provider.Annotations["ebs.csi.aws.com/iops"] = "8000"
+		assert.NoError(t, cluster.updateDeviceSetPVCProvider(pvc, provider))
+		pvc, err = clientset.CoreV1().PersistentVolumeClaims("testns").Get(ctx, pvc.Name, metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, "8000", pvc.Annotations["ebs.csi.aws.com/iops"])
+	})
+}
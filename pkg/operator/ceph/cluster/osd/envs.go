@@ -120,6 +120,10 @@ func dataDevicesEnvVar(dataDevices string) v1.EnvVar {
 	return v1.EnvVar{Name: "ROOK_DATA_DEVICES", Value: dataDevices}
 }
 
+func driveGroupsEnvVar(driveGroups string) v1.EnvVar {
+	return v1.EnvVar{Name: "ROOK_DRIVE_GROUPS", Value: driveGroups}
+}
+
 func deviceFilterEnvVar(filter string) v1.EnvVar {
 	return v1.EnvVar{Name: "ROOK_DATA_DEVICE_FILTER", Value: filter}
 }
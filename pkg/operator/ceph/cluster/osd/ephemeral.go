@@ -0,0 +1,155 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const defaultMaxConcurrentReplacements = 1
+
+// isOSDLostWithNode returns true if the node where the OSD was provisioned was removed from the cluster, or
+// was recreated after the OSD was provisioned. In both cases the ephemeral device backing the OSD is gone.
+func (m *OSDHealthMonitor) isOSDLostWithNode(osdID int) (bool, error) {
+	label := fmt.Sprintf("%s=%d", OsdIdLabelKey, osdID)
+	deployments, err := k8sutil.GetDeployments(m.clusterInfo.Context, m.context.Clientset, m.clusterInfo.Namespace, label)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get deployment for osd.%d", osdID)
+	}
+	if len(deployments.Items) == 0 {
+		return false, nil
+	}
+	d := deployments.Items[0]
+
+	// OSDs on PVCs are not tied to the lifecycle of a node
+	if osdIsOnPVC(&d) {
+		return false, nil
+	}
+
+	hostname, err := getNodeOrPVCName(&d)
+	if err != nil {
+		return false, err
+	}
+	listOpts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", corev1.LabelHostname, hostname)}
+	nodes, err := m.context.Clientset.CoreV1().Nodes().List(m.clusterInfo.Context, listOpts)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to list nodes with hostname %q", hostname)
+	}
+	if len(nodes.Items) == 0 {
+		logger.Infof("node %q of osd.%d no longer exists", hostname, osdID)
+		return true, nil
+	}
+
+	nodeCreationTime := nodes.Items[0].GetCreationTimestamp()
+	osdCreationTime := d.GetCreationTimestamp()
+	if osdCreationTime.Before(&nodeCreationTime) {
+		logger.Infof("node %q was replaced after osd.%d was provisioned", hostname, osdID)
+		return true, nil
+	}
+	return false, nil
+}
+
+// replaceLostOSDs purges the OSDs whose ephemeral devices were lost with their node. The replacement OSDs are
+// provisioned on the new node by the next orchestration of the cluster. To avoid piling data movement on top
+// of an ongoing recovery, OSDs are only purged while no PGs are recovering or backfilling. Since a recreated node
+// object does not prove that its devices were wiped, e.g. when the node only registered again, an OSD is only purged
+// once it is safe to destroy, i.e. once its data was recovered on the other OSDs.
+func (m *OSDHealthMonitor) replaceLostOSDs(osdIDs []int) error {
+	status, err := client.Status(m.context, m.clusterInfo)
+	if err != nil {
+		return errors.Wrap(err, "failed to get ceph status")
+	}
+	if client.IsRecoveryInProgress(status) {
+		logger.Infof("waiting for recovery to complete before purging osds %v lost with their node", osdIDs)
+		return nil
+	}
+
+	maxReplacements := m.ephemeralDevices.MaxConcurrentReplacements
+	if maxReplacements < 1 {
+		maxReplacements = defaultMaxConcurrentReplacements
+	}
+
+	purged := 0
+	for _, osdID := range osdIDs {
+		if purged >= maxReplacements {
+			logger.Infof("purged %d of the %d osds lost with their node", purged, len(osdIDs))
+			break
+		}
+		safe, err := client.OsdSafeToDestroy(m.context, m.clusterInfo, osdID)
+		if err != nil {
+			logger.Warningf("failed to check if osd.%d lost with its node is safe to destroy. %v", osdID, err)
+			continue
+		}
+		if !safe {
+			logger.Infof("waiting for osd.%d lost with its node to be safe to destroy before purging it", osdID)
+			continue
+		}
+		if err := m.purgeLostOSD(osdID); err != nil {
+			return err
+		}
+		purged++
+	}
+	return nil
+}
+
+func (m *OSDHealthMonitor) purgeLostOSD(osdID int) error {
+	logger.Infof("purging osd.%d whose device was lost with its node", osdID)
+
+	// The pod of the lost OSD cannot be terminated gracefully if its node is gone, so don't wait for it
+	deploymentName := fmt.Sprintf(osdAppNameFmt, osdID)
+	err := m.context.Clientset.AppsV1().Deployments(m.clusterInfo.Namespace).Delete(m.clusterInfo.Context, deploymentName, metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete deployment %q", deploymentName)
+	}
+
+	if err := purgeOSD(m.context, m.clusterInfo, osdID); err != nil {
+		return err
+	}
+
+	logger.Infof("purged osd.%d. a replacement osd will be provisioned on the next orchestration", osdID)
+	return nil
+}
+
+// purgeOSD removes the OSD from the OSD map, the CRUSH map and the auth keys, and removes its CRUSH host if no other
+// OSDs remain on it
+func purgeOSD(context *clusterd.Context, clusterInfo *client.ClusterInfo, osdID int) error {
+	hostName, err := client.GetCrushHostName(context, clusterInfo, osdID)
+	if err != nil {
+		logger.Warningf("failed to get the crush host of osd.%d. %v", osdID, err)
+	}
+
+	if err := client.PurgeOSD(context, clusterInfo, osdID); err != nil {
+		return err
+	}
+
+	// The host is removed from the CRUSH map only if no other OSDs remain on it
+	if hostName != "" {
+		args := []string{"osd", "crush", "rm", hostName}
+		if _, err := client.NewCephCommand(context, clusterInfo, args).Run(); err != nil {
+			logger.Infof("did not remove crush host %q. %v", hostName, err)
+		}
+	}
+	return nil
+}
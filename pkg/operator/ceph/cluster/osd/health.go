@@ -46,15 +46,17 @@ type OSDHealthMonitor struct {
 	context                        *clusterd.Context
 	clusterInfo                    *client.ClusterInfo
 	removeOSDsIfOUTAndSafeToRemove bool
+	ephemeralDevices               cephv1.EphemeralDevicesSpec
 	interval                       *time.Duration
 }
 
 // NewOSDHealthMonitor instantiates OSD monitoring
-func NewOSDHealthMonitor(context *clusterd.Context, clusterInfo *client.ClusterInfo, removeOSDsIfOUTAndSafeToRemove bool, healthCheck cephv1.CephClusterHealthCheckSpec) *OSDHealthMonitor {
+func NewOSDHealthMonitor(context *clusterd.Context, clusterInfo *client.ClusterInfo, removeOSDsIfOUTAndSafeToRemove bool, ephemeralDevices cephv1.EphemeralDevicesSpec, healthCheck cephv1.CephClusterHealthCheckSpec) *OSDHealthMonitor {
 	h := &OSDHealthMonitor{
 		context:                        context,
 		clusterInfo:                    clusterInfo,
 		removeOSDsIfOUTAndSafeToRemove: removeOSDsIfOUTAndSafeToRemove,
+		ephemeralDevices:               ephemeralDevices,
 		interval:                       &defaultHealthCheckInterval,
 	}
 
@@ -127,6 +129,7 @@ func (m *OSDHealthMonitor) checkOSDDump() error {
 		return errors.Wrap(err, "failed to get osd dump")
 	}
 
+	lostOSDs := []int{}
 	for _, osdStatus := range osdDump.OSDs {
 		id64, err := osdStatus.OSD.Int64()
 		if err != nil {
@@ -148,6 +151,16 @@ func (m *OSDHealthMonitor) checkOSDDump() error {
 
 		logger.Debugf("osd.%d is marked 'DOWN'", id)
 
+		if m.ephemeralDevices.Enabled {
+			lost, err := m.isOSDLostWithNode(id)
+			if err != nil {
+				logger.Errorf("failed to check if osd.%d was lost with its node. %v", id, err)
+			} else if lost {
+				lostOSDs = append(lostOSDs, id)
+				continue
+			}
+		}
+
 		if in != inStatus {
 			logger.Debugf("osd.%d is marked 'OUT'", id)
 			if m.removeOSDsIfOUTAndSafeToRemove {
@@ -158,6 +171,12 @@ func (m *OSDHealthMonitor) checkOSDDump() error {
 		}
 	}
 
+	if len(lostOSDs) > 0 {
+		if err := m.replaceLostOSDs(lostOSDs); err != nil {
+			logger.Errorf("failed to replace osds lost with their node. %v", err)
+		}
+	}
+
 	return nil
 }
 
@@ -185,6 +204,11 @@ func (m *OSDHealthMonitor) removeOSDDeploymentIfSafeToDestroy(outOSDid int) erro
 				if err := k8sutil.DeleteDeployment(m.clusterInfo.Context, m.context.Clientset, dp.Items[0].Namespace, dp.Items[0].Name); err != nil {
 					return errors.Wrapf(err, "failed to delete osd deployment %s", dp.Items[0].Name)
 				}
+				// the osd is purged once its deployment is gone, so that it is not left in the osd and crush maps
+				if err := purgeOSD(m.context, m.clusterInfo, outOSDid); err != nil {
+					return errors.Wrapf(err, "failed to purge osd.%d", outOSDid)
+				}
+				logger.Infof("purged osd.%d", outOSDid)
 			}
 		}
 	}
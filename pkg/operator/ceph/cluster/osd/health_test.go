@@ -32,6 +32,8 @@ import (
 	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/stretchr/testify/assert"
 	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -43,10 +45,15 @@ func TestOSDHealthCheck(t *testing.T) {
 	clusterInfo := client.AdminTestClusterInfo("fake")
 
 	var execCount = 0
+	purged := false
 	executor := &exectest.MockExecutor{
 		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
 			logger.Infof("ExecuteCommandWithOutputFile: %s %v", command, args)
 			execCount++
+			if args[0] == "osd" && args[1] == "purge" {
+				assert.Equal(t, "0", args[2])
+				purged = true
+			}
 			if args[1] == "dump" {
 				// Mock executor for OSD Dump command, returning an osd in Down state
 				return `{"OSDs": [{"OSD": 0, "Up": 0, "In": 0}]}`, nil
@@ -88,13 +95,15 @@ func TestOSDHealthCheck(t *testing.T) {
 	assert.Equal(t, 1, len(dp.Items))
 
 	// Initializing an OSD monitoring
-	osdMon := NewOSDHealthMonitor(context, clusterInfo, true, cephv1.CephClusterHealthCheckSpec{})
+	osdMon := NewOSDHealthMonitor(context, clusterInfo, true, cephv1.EphemeralDevicesSpec{}, cephv1.CephClusterHealthCheckSpec{})
 
 	// Run OSD monitoring routine
 	err := osdMon.checkOSDDump()
 	assert.Nil(t, err)
-	// After creating an OSD, the dump has 1 mocked cmd and safe to destroy has 1 mocked cmd
-	assert.Equal(t, 2, execCount)
+	// After creating an OSD, the dump and safe to destroy have 1 mocked cmd each, then the crush host of the osd
+	// is looked up and the osd is purged
+	assert.Equal(t, 4, execCount)
+	assert.True(t, purged)
 
 	// Check if the osd deployment was deleted
 	dp, _ = context.Clientset.AppsV1().Deployments(clusterInfo.Namespace).List(ctx, metav1.ListOptions{LabelSelector: fmt.Sprintf("%v=%d", OsdIdLabelKey, 0)})
@@ -109,7 +118,7 @@ func TestMonitorStart(t *testing.T) {
 		InternalCancel: cancel,
 	}
 
-	osdMon := NewOSDHealthMonitor(&clusterd.Context{}, client.AdminTestClusterInfo("ns"), true, cephv1.CephClusterHealthCheckSpec{})
+	osdMon := NewOSDHealthMonitor(&clusterd.Context{}, client.AdminTestClusterInfo("ns"), true, cephv1.EphemeralDevicesSpec{}, cephv1.CephClusterHealthCheckSpec{})
 	logger.Infof("starting osd monitor")
 	go osdMon.Start(monitoringRoutines, "osd")
 	cancel()
@@ -129,12 +138,12 @@ func TestNewOSDHealthMonitor(t *testing.T) {
 		args args
 		want *OSDHealthMonitor
 	}{
-		{"default-interval", args{c, false, cephv1.CephClusterHealthCheckSpec{}}, &OSDHealthMonitor{c, clusterInfo, false, &defaultHealthCheckInterval}},
-		{"10s-interval", args{c, false, cephv1.CephClusterHealthCheckSpec{DaemonHealth: cephv1.DaemonHealthSpec{ObjectStorageDaemon: cephv1.HealthCheckSpec{Interval: &metav1.Duration{Duration: time10s}}}}}, &OSDHealthMonitor{c, clusterInfo, false, &time10s}},
+		{"default-interval", args{c, false, cephv1.CephClusterHealthCheckSpec{}}, &OSDHealthMonitor{c, clusterInfo, false, cephv1.EphemeralDevicesSpec{}, &defaultHealthCheckInterval}},
+		{"10s-interval", args{c, false, cephv1.CephClusterHealthCheckSpec{DaemonHealth: cephv1.DaemonHealthSpec{ObjectStorageDaemon: cephv1.HealthCheckSpec{Interval: &metav1.Duration{Duration: time10s}}}}}, &OSDHealthMonitor{c, clusterInfo, false, cephv1.EphemeralDevicesSpec{}, &time10s}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := NewOSDHealthMonitor(tt.args.context, clusterInfo, tt.args.removeOSDsIfOUTAndSafeToRemove, tt.args.healthCheck); !reflect.DeepEqual(got, tt.want) {
+			if got := NewOSDHealthMonitor(tt.args.context, clusterInfo, tt.args.removeOSDsIfOUTAndSafeToRemove, cephv1.EphemeralDevicesSpec{}, tt.args.healthCheck); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("NewOSDHealthMonitor() = %v, want %v", got, tt.want)
 			}
 		})
@@ -176,7 +185,7 @@ func TestDeviceClasses(t *testing.T) {
 	}
 
 	// Initializing an OSD monitoring
-	osdMon := NewOSDHealthMonitor(context, clusterInfo, true, cephv1.CephClusterHealthCheckSpec{})
+	osdMon := NewOSDHealthMonitor(context, clusterInfo, true, cephv1.EphemeralDevicesSpec{}, cephv1.CephClusterHealthCheckSpec{})
 
 	// Run OSD monitoring routine
 	err := osdMon.checkDeviceClasses()
@@ -184,3 +193,105 @@ func TestDeviceClasses(t *testing.T) {
 	// checkDeviceClasses has 1 mocked cmd for fetching the device classes
 	assert.Equal(t, 1, execCount)
 }
+
+func TestReplaceLostOSDs(t *testing.T) {
+	ctx := context.TODO()
+	clientset := testexec.New(t, 1)
+	clusterInfo := client.AdminTestClusterInfo("fake")
+
+	recovering := false
+	safeToDestroy := `{"safe_to_destroy":[0]}`
+	purged := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			logger.Infof("ExecuteCommandWithOutput: %s %v", command, args)
+			if args[0] == "osd" && args[1] == "dump" {
+				return `{"OSDs": [{"OSD": 0, "Up": 0, "In": 1}, {"OSD": 1, "Up": 0, "In": 1}, {"OSD": 2, "Up": 1, "In": 1}]}`, nil
+			}
+			if args[0] == "status" {
+				if recovering {
+					return `{"pgmap":{"num_pgs":2,"pgs_by_state":[{"state_name":"active+recovering+degraded","count":2}]}}`, nil
+				}
+				return `{"pgmap":{"num_pgs":2,"pgs_by_state":[{"state_name":"active+undersized+degraded","count":2}]}}`, nil
+			}
+			if args[0] == "osd" && args[1] == "safe-to-destroy" {
+				if args[2] == "0" {
+					return safeToDestroy, nil
+				}
+				return `{"safe_to_destroy":[]}`, nil
+			}
+			if args[0] == "osd" && args[1] == "find" {
+				return `{"osd":0,"crush_location":{"host":"lostnode","root":"default"}}`, nil
+			}
+			if args[0] == "osd" && args[1] == "purge" {
+				purged = append(purged, args[2])
+			}
+			return "", nil
+		},
+	}
+	context := &clusterd.Context{
+		Executor:  executor,
+		Clientset: clientset,
+	}
+
+	// osd.0 was provisioned on a node that no longer exists, osd.1 is on a node that still exists
+	for i, nodeName := range []string{"lostnode", "node0"} {
+		deployment := &apps.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf(osdAppNameFmt, i),
+				Namespace: clusterInfo.Namespace,
+				Labels: map[string]string{
+					k8sutil.AppAttr: AppName,
+					OsdIdLabelKey:   fmt.Sprintf("%d", i),
+				},
+			},
+		}
+		deployment.Spec.Template.Spec.NodeSelector = map[string]string{corev1.LabelHostname: nodeName}
+		_, err := clientset.AppsV1().Deployments(clusterInfo.Namespace).Create(ctx, deployment, metav1.CreateOptions{})
+		assert.NoError(t, err)
+	}
+
+	t.Run("ephemeral devices disabled", func(t *testing.T) {
+		osdMon := NewOSDHealthMonitor(context, clusterInfo, false, cephv1.EphemeralDevicesSpec{}, cephv1.CephClusterHealthCheckSpec{})
+		assert.NoError(t, osdMon.checkOSDDump())
+		assert.Empty(t, purged)
+	})
+
+	t.Run("lost osd is not purged during recovery", func(t *testing.T) {
+		recovering = true
+		osdMon := NewOSDHealthMonitor(context, clusterInfo, false, cephv1.EphemeralDevicesSpec{Enabled: true}, cephv1.CephClusterHealthCheckSpec{})
+		assert.NoError(t, osdMon.checkOSDDump())
+		assert.Empty(t, purged)
+	})
+
+	t.Run("lost osd is purged", func(t *testing.T) {
+		recovering = false
+		osdMon := NewOSDHealthMonitor(context, clusterInfo, false, cephv1.EphemeralDevicesSpec{Enabled: true}, cephv1.CephClusterHealthCheckSpec{})
+		assert.NoError(t, osdMon.checkOSDDump())
+		assert.Equal(t, []string{"0"}, purged)
+
+		_, err := clientset.AppsV1().Deployments(clusterInfo.Namespace).Get(ctx, fmt.Sprintf(osdAppNameFmt, 0), metav1.GetOptions{})
+		assert.True(t, kerrors.IsNotFound(err))
+		_, err = clientset.AppsV1().Deployments(clusterInfo.Namespace).Get(ctx, fmt.Sprintf(osdAppNameFmt, 1), metav1.GetOptions{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("osd on a recreated node is not purged while its pgs are degraded", func(t *testing.T) {
+		purged = []string{}
+		// the node of osd.1 is recreated after the osd was provisioned, e.g. it registered again with its disk intact
+		node, err := clientset.CoreV1().Nodes().Get(ctx, "node0", metav1.GetOptions{})
+		assert.NoError(t, err)
+		node.CreationTimestamp = metav1.Now()
+		_, err = clientset.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{})
+		assert.NoError(t, err)
+
+		osdMon := NewOSDHealthMonitor(context, clusterInfo, false, cephv1.EphemeralDevicesSpec{Enabled: true}, cephv1.CephClusterHealthCheckSpec{})
+		lost, err := osdMon.isOSDLostWithNode(1)
+		assert.NoError(t, err)
+		assert.True(t, lost)
+		assert.NoError(t, osdMon.checkOSDDump())
+		assert.Empty(t, purged)
+		_, err = clientset.AppsV1().Deployments(clusterInfo.Namespace).Get(ctx, fmt.Sprintf(osdAppNameFmt, 1), metav1.GetOptions{})
+		assert.NoError(t, err)
+	})
+}
@@ -171,6 +171,7 @@ func (c *Cluster) provisionPodTemplateSpec(osdProps osdProperties, restart v1.Re
 	}
 
 	k8sutil.RemoveDuplicateEnvVars(&podSpec)
+	controller.DecryptKeyringVolumes(&c.spec, c.rookVersion, &podSpec, mon.AppName)
 
 	podMeta := metav1.ObjectMeta{
 		Name: AppName,
@@ -201,8 +202,19 @@ func (c *Cluster) provisionOSDContainer(osdProps osdProperties, copyBinariesMoun
 	// enable debug logging in the prepare job
 	envVars = append(envVars, setDebugLogLevelEnvVar(true))
 
-	// only 1 of device list, device filter, device path filter and use all devices can be specified.  We prioritize in that order.
-	if len(osdProps.devices) > 0 {
+	// the drive groups replace the device selection of the node.
+	// otherwise only 1 of device list, device filters, device filter, device path filter and use all devices can be specified.  We prioritize in that order.
+	if len(osdProps.driveGroups) > 0 {
+		driveGroups := []config.DriveGroup{}
+		for _, driveGroup := range osdProps.driveGroups {
+			driveGroups = append(driveGroups, config.DriveGroup{Name: driveGroup.Name, Spec: driveGroup.Spec.Raw})
+		}
+		marshalledDriveGroups, err := json.Marshal(driveGroups)
+		if err != nil {
+			return v1.Container{}, errors.Wrapf(err, "failed to JSON marshal drive groups for node %q", osdProps.crushHostname)
+		}
+		envVars = append(envVars, driveGroupsEnvVar(string(marshalledDriveGroups)))
+	} else if len(osdProps.devices) > 0 {
 		configuredDevices := []config.ConfiguredDevice{}
 		for _, device := range osdProps.devices {
 			id := device.Name
@@ -220,6 +232,21 @@ func (c *Cluster) provisionOSDContainer(osdProps osdProperties, copyBinariesMoun
 			return v1.Container{}, errors.Wrapf(err, "failed to JSON marshal configured devices for node %q", osdProps.crushHostname)
 		}
 		envVars = append(envVars, dataDevicesEnvVar(string(marshalledDevices)))
+	} else if len(osdProps.selection.DeviceFilters) > 0 {
+		configuredDevices := []config.ConfiguredDevice{}
+		for _, filter := range osdProps.selection.DeviceFilters {
+			cd := config.ConfiguredDevice{
+				ID:          filter.Filter,
+				StoreConfig: config.ToStoreConfig(filter.Config),
+				IsFilter:    true,
+			}
+			configuredDevices = append(configuredDevices, cd)
+		}
+		marshalledDevices, err := json.Marshal(configuredDevices)
+		if err != nil {
+			return v1.Container{}, errors.Wrapf(err, "failed to JSON marshal device filters for node %q", osdProps.crushHostname)
+		}
+		envVars = append(envVars, dataDevicesEnvVar(string(marshalledDevices)))
 	} else if osdProps.selection.DeviceFilter != "" {
 		envVars = append(envVars, deviceFilterEnvVar(osdProps.selection.DeviceFilter))
 	} else if osdProps.selection.DevicePathFilter != "" {
@@ -437,6 +437,11 @@ func (c *Cluster) makeDeployment(osdProps osdProperties, osd OSDInfo, provisionC
 		volumeMounts = append(volumeMounts, dmVolMount)
 	}
 
+	// Mount the hugetlbfs volumes if the OSD requests hugepages
+	hugePagesVolumes, hugePagesVolumeMounts := getHugePagesVolumes(osdProps.resources)
+	volumes = append(volumes, hugePagesVolumes...)
+	volumeMounts = append(volumeMounts, hugePagesVolumeMounts...)
+
 	// Add the volume to the spec and the mount to the daemon container
 	copyBinariesVolume, copyBinariesContainer := c.getCopyBinariesContainer()
 	if doBinaryCopyInit {
@@ -617,6 +622,8 @@ func (c *Cluster) makeDeployment(osdProps osdProperties, osd OSDInfo, provisionC
 		},
 	}
 
+	controller.DecryptKeyringVolumes(&c.spec, c.rookVersion, &podTemplateSpec.Spec, cephkey.Volume().Admin().Secret.SecretName)
+
 	// add OSD container port only if service export is enabled
 	if osd.ExportService {
 		podTemplateSpec.Spec.Containers[0].Ports = c.getOSDContainerPorts()
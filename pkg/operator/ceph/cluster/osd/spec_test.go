@@ -18,6 +18,7 @@ limitations under the License.
 package osd
 
 import (
+	"encoding/json"
 	"testing"
 
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
@@ -33,6 +34,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
 )
 
@@ -661,6 +663,99 @@ func TestOsdPrepareResources(t *testing.T) {
 	assert.Equal(t, "250", r.Requests.Memory().String())
 }
 
+func TestProvisionOSDContainerDeviceFilters(t *testing.T) {
+	clusterInfo := &cephclient.ClusterInfo{Namespace: "ns", CephVersion: cephver.Reef}
+	clusterInfo.SetName("test")
+	clusterInfo.OwnerInfo = cephclient.NewMinimumOwnerInfo(t)
+	c := New(&clusterd.Context{ConfigDir: "/var/lib/rook"}, clusterInfo, cephv1.ClusterSpec{}, "rook/rook:myversion")
+	dataPathMap := &provisionConfig{
+		DataPathMap: opconfig.NewDatalessDaemonDataPathMap(c.clusterInfo.Namespace, "/var/lib/rook"),
+	}
+	osdProps := osdProperties{
+		crushHostname: "node1",
+		selection: cephv1.Selection{
+			DeviceFilter: "^sd.",
+			DeviceFilters: []cephv1.DeviceFilter{
+				{Filter: "^nvme", Config: map[string]string{"deviceClass": "nvme", "osdsPerDevice": "2"}},
+				{Filter: "^sd[b-d]", Config: map[string]string{"metadataDevice": "nvme0n1"}},
+			},
+		},
+	}
+
+	getEnv := func(container corev1.Container, name string) (string, bool) {
+		for _, env := range container.Env {
+			if env.Name == name {
+				return env.Value, true
+			}
+		}
+		return "", false
+	}
+
+	// the device filters take precedence over the single device filter
+	container, err := c.provisionOSDContainer(osdProps, corev1.VolumeMount{}, dataPathMap)
+	assert.NoError(t, err)
+	_, ok := getEnv(container, "ROOK_DATA_DEVICE_FILTER")
+	assert.False(t, ok)
+	val, ok := getEnv(container, "ROOK_DATA_DEVICES")
+	assert.True(t, ok)
+	configuredDevices := []config.ConfiguredDevice{}
+	assert.NoError(t, json.Unmarshal([]byte(val), &configuredDevices))
+	assert.Equal(t, 2, len(configuredDevices))
+	assert.Equal(t, "^nvme", configuredDevices[0].ID)
+	assert.True(t, configuredDevices[0].IsFilter)
+	assert.Equal(t, "nvme", configuredDevices[0].StoreConfig.DeviceClass)
+	assert.Equal(t, 2, configuredDevices[0].StoreConfig.OSDsPerDevice)
+	assert.Equal(t, "^sd[b-d]", configuredDevices[1].ID)
+	assert.True(t, configuredDevices[1].IsFilter)
+	assert.Equal(t, "nvme0n1", configuredDevices[1].StoreConfig.MetadataDevice)
+	assert.Equal(t, 1, configuredDevices[1].StoreConfig.OSDsPerDevice)
+
+	// explicit devices take precedence over the device filters
+	osdProps.devices = []cephv1.Device{{Name: "sda", Config: map[string]string{"deviceClass": "hdd"}}}
+	container, err = c.provisionOSDContainer(osdProps, corev1.VolumeMount{}, dataPathMap)
+	assert.NoError(t, err)
+	val, ok = getEnv(container, "ROOK_DATA_DEVICES")
+	assert.True(t, ok)
+	configuredDevices = []config.ConfiguredDevice{}
+	assert.NoError(t, json.Unmarshal([]byte(val), &configuredDevices))
+	assert.Equal(t, 1, len(configuredDevices))
+	assert.Equal(t, "sda", configuredDevices[0].ID)
+	assert.False(t, configuredDevices[0].IsFilter)
+	assert.Equal(t, "hdd", configuredDevices[0].StoreConfig.DeviceClass)
+}
+
+func TestProvisionOSDContainerDriveGroups(t *testing.T) {
+	clusterInfo := &cephclient.ClusterInfo{Namespace: "ns", CephVersion: cephver.Reef}
+	clusterInfo.SetName("test")
+	clusterInfo.OwnerInfo = cephclient.NewMinimumOwnerInfo(t)
+	c := New(&clusterd.Context{ConfigDir: "/var/lib/rook"}, clusterInfo, cephv1.ClusterSpec{}, "rook/rook:myversion")
+	dataPathMap := &provisionConfig{
+		DataPathMap: opconfig.NewDatalessDaemonDataPathMap(c.clusterInfo.Namespace, "/var/lib/rook"),
+	}
+	useAllDevices := true
+	osdProps := osdProperties{
+		crushHostname: "node1",
+		devices:       []cephv1.Device{{Name: "sda"}},
+		selection:     cephv1.Selection{UseAllDevices: &useAllDevices},
+		driveGroups: []cephv1.DriveGroup{
+			{Name: "hdd", Spec: runtime.RawExtension{Raw: []byte(`{"data_devices":{"rotational":1},"db_devices":{"rotational":0}}`)}},
+		},
+	}
+
+	// the drive groups replace the device selection of the node
+	container, err := c.provisionOSDContainer(osdProps, corev1.VolumeMount{}, dataPathMap)
+	assert.NoError(t, err)
+	driveGroups := ""
+	for _, env := range container.Env {
+		assert.NotEqual(t, "ROOK_DATA_DEVICES", env.Name)
+		assert.NotEqual(t, "ROOK_DATA_DEVICE_FILTER", env.Name)
+		if env.Name == "ROOK_DRIVE_GROUPS" {
+			driveGroups = env.Value
+		}
+	}
+	assert.JSONEq(t, `[{"name":"hdd","spec":{"data_devices":{"rotational":1},"db_devices":{"rotational":0}}}]`, driveGroups)
+}
+
 func TestClusterGetPVCEncryptionOpenInitContainerActivate(t *testing.T) {
 	c := New(&clusterd.Context{}, &cephclient.ClusterInfo{OwnerInfo: &k8sutil.OwnerInfo{}}, cephv1.ClusterSpec{}, "rook/rook:myversion")
 	osdProperties := osdProperties{
@@ -0,0 +1,164 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	appsv1 "k8s.io/api/apps/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// replaceSwappedOSDs purges the OSDs of a node whose failed device was swapped for a new device. Such an OSD is down,
+// was not found on the node by the prepare job, and a reported OSD of the node now uses a device in the same slot,
+// i.e. with the same persistent path. The OSDs whose device is only missing, or whose data is not yet recovered
+// elsewhere, are kept.
+func (c *Cluster) replaceSwappedOSDs(nodeName string, reportedOSDs []OSDInfo) error {
+	missingOSDs, err := c.getMissingOSDsOnNode(nodeName, reportedOSDs)
+	if err != nil {
+		return err
+	}
+	if len(missingOSDs) == 0 {
+		return nil
+	}
+
+	osdDump, err := client.GetOSDDump(c.context, c.clusterInfo)
+	if err != nil {
+		return errors.Wrap(err, "failed to get osd dump")
+	}
+
+	// the slots of the devices of the reported OSDs, which are only known once the OSDs have started
+	reportedSlots := map[string]int{}
+	for _, osd := range reportedOSDs {
+		paths, err := client.OSDDevicePaths(c.context, c.clusterInfo, osd.ID)
+		if err != nil {
+			logger.Debugf("failed to get the device paths of osd.%d. %v", osd.ID, err)
+			continue
+		}
+		for _, path := range paths {
+			reportedSlots[path] = osd.ID
+		}
+	}
+
+	for _, osdID := range missingOSDs {
+		status, _, err := osdDump.StatusByID(int64(osdID))
+		if err != nil {
+			logger.Warningf("osd.%d is not in the osd map. %v", osdID, err)
+			continue
+		}
+		if status == upStatus {
+			continue
+		}
+
+		replacement, slot, err := c.swappedDeviceReplacement(osdID, reportedSlots)
+		if err != nil {
+			logger.Warningf("failed to find the replacement of osd.%d on node %q. %v", osdID, nodeName, err)
+			continue
+		}
+		if replacement < 0 {
+			logger.Infof("keeping osd.%d on node %q since no new osd uses a device in the slot of its device", osdID, nodeName)
+			continue
+		}
+		safe, err := client.OsdSafeToDestroy(c.context, c.clusterInfo, osdID)
+		if err != nil {
+			logger.Warningf("failed to check if osd.%d is safe to destroy. %v", osdID, err)
+			continue
+		}
+		if !safe {
+			logger.Infof("not purging osd.%d replaced by osd.%d on node %q until its data is recovered on the other osds", osdID, replacement, nodeName)
+			continue
+		}
+
+		logger.Infof("purging osd.%d whose device in slot %q was swapped on node %q", osdID, slot, nodeName)
+		deploymentName := fmt.Sprintf(osdAppNameFmt, osdID)
+		err = c.context.Clientset.AppsV1().Deployments(c.clusterInfo.Namespace).Delete(c.clusterInfo.Context, deploymentName, metav1.DeleteOptions{})
+		if err != nil && !kerrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete deployment %q", deploymentName)
+		}
+		if err := purgeOSD(c.context, c.clusterInfo, osdID); err != nil {
+			return errors.Wrapf(err, "failed to purge osd.%d", osdID)
+		}
+		logger.Infof("purged osd.%d, replaced by osd.%d on node %q", osdID, replacement, nodeName)
+		// a new OSD replaces a single OSD
+		for path, id := range reportedSlots {
+			if id == replacement {
+				delete(reportedSlots, path)
+			}
+		}
+	}
+	return nil
+}
+
+// swappedDeviceReplacement returns the ID of the reported OSD using a device in the same slot as the device of the
+// missing OSD, and the slot, or -1 if there is no such OSD
+func (c *Cluster) swappedDeviceReplacement(osdID int, reportedSlots map[string]int) (int, string, error) {
+	paths, err := client.OSDDevicePaths(c.context, c.clusterInfo, osdID)
+	if err != nil {
+		return -1, "", err
+	}
+	for _, path := range paths {
+		if replacement, ok := reportedSlots[path]; ok && replacement != osdID {
+			return replacement, path, nil
+		}
+	}
+	return -1, "", nil
+}
+
+// getMissingOSDsOnNode returns the IDs of the OSDs deployed on a node that were not reported by its prepare job
+func (c *Cluster) getMissingOSDsOnNode(nodeName string, reportedOSDs []OSDInfo) ([]int, error) {
+	selector := fmt.Sprintf("%s=%s", k8sutil.AppAttr, AppName)
+	deployments, err := k8sutil.GetDeployments(c.clusterInfo.Context, c.context.Clientset, c.clusterInfo.Namespace, selector)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list osd deployments")
+	}
+
+	reported := map[int]bool{}
+	for _, osd := range reportedOSDs {
+		reported[osd.ID] = true
+	}
+
+	missingOSDs := []int{}
+	for i := range deployments.Items {
+		d := &deployments.Items[i]
+		if !isOSDDeploymentOnNode(d, nodeName) {
+			continue
+		}
+		osdID, err := getOSDID(d)
+		if err != nil {
+			logger.Warningf("failed to get the osd id of deployment %q. %v", d.Name, err)
+			continue
+		}
+		if !reported[osdID] {
+			missingOSDs = append(missingOSDs, osdID)
+		}
+	}
+	sort.Ints(missingOSDs)
+	return missingOSDs, nil
+}
+
+func isOSDDeploymentOnNode(d *appsv1.Deployment, nodeName string) bool {
+	if osdIsOnPVC(d) {
+		return false
+	}
+	name, err := getNodeOrPVCName(d)
+	return err == nil && name == nodeName
+}
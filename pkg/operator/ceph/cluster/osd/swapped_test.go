@@ -0,0 +1,128 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	testexec "github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReplaceSwappedOSDs(t *testing.T) {
+	ctx := context.TODO()
+	clientset := testexec.New(t, 2)
+	clusterInfo := client.AdminTestClusterInfo("fake")
+
+	purged := []string{}
+	safeToDestroy := map[string]bool{"0": true, "1": true}
+	// osd.0 and osd.4 are in slot 1, osd.1 and osd.6 in slot 2, osd.5 in slot 3 and the device of osd.3 has no slot
+	devicePaths := map[string]string{
+		"0": "sdb=/dev/disk/by-path/slot-1",
+		"1": "sdc=/dev/disk/by-path/slot-2",
+		"2": "sdd=/dev/disk/by-path/slot-4",
+		"4": "sde=/dev/disk/by-path/slot-1",
+		"5": "sdf=/dev/disk/by-path/slot-3",
+		"6": "sdg=/dev/disk/by-path/slot-2",
+	}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			logger.Infof("ExecuteCommandWithOutput: %s %v", command, args)
+			if args[0] == "osd" && args[1] == "dump" {
+				return `{"OSDs": [{"OSD": 0, "Up": 0, "In": 0}, {"OSD": 1, "Up": 0, "In": 0}, {"OSD": 2, "Up": 1, "In": 1}, {"OSD": 3, "Up": 0, "In": 0}, {"OSD": 5, "Up": 1, "In": 1}, {"OSD": 7, "Up": 0, "In": 0}]}`, nil
+			}
+			if args[0] == "osd" && args[1] == "metadata" {
+				return fmt.Sprintf(`{"device_paths": %q}`, devicePaths[args[2]]), nil
+			}
+			if args[0] == "osd" && args[1] == "safe-to-destroy" {
+				if safeToDestroy[args[2]] {
+					return fmt.Sprintf(`{"safe_to_destroy": [%s]}`, args[2]), nil
+				}
+				return `{"safe_to_destroy": []}`, nil
+			}
+			if args[0] == "osd" && args[1] == "purge" {
+				purged = append(purged, args[2])
+			}
+			return "", nil
+		},
+	}
+	c := New(&clusterd.Context{Executor: executor, Clientset: clientset}, clusterInfo, cephv1.ClusterSpec{}, "rook/rook:myversion")
+
+	// osd.0, osd.1 and osd.7 are down and osd.5 is up on node0 but not found by its prepare job, osd.3 is down on node1
+	for id, nodeName := range map[int]string{0: "node0", 1: "node0", 2: "node0", 3: "node1", 5: "node0", 7: "node0"} {
+		deployment := &apps.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf(osdAppNameFmt, id),
+				Namespace: clusterInfo.Namespace,
+				Labels: map[string]string{
+					k8sutil.AppAttr: AppName,
+					OsdIdLabelKey:   fmt.Sprintf("%d", id),
+				},
+			},
+		}
+		deployment.Spec.Template.Spec.NodeSelector = map[string]string{corev1.LabelHostname: nodeName}
+		_, err := clientset.AppsV1().Deployments(clusterInfo.Namespace).Create(ctx, deployment, metav1.CreateOptions{})
+		assert.NoError(t, err)
+	}
+	deploymentExists := func(id int) bool {
+		_, err := clientset.AppsV1().Deployments(clusterInfo.Namespace).Get(ctx, fmt.Sprintf(osdAppNameFmt, id), metav1.GetOptions{})
+		return !kerrors.IsNotFound(err)
+	}
+
+	missingOSDs, err := c.getMissingOSDsOnNode("node0", []OSDInfo{{ID: 2}, {ID: 4}})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 5, 7}, missingOSDs)
+
+	t.Run("no new osd in the slot of a missing osd", func(t *testing.T) {
+		assert.NoError(t, c.replaceSwappedOSDs("node0", []OSDInfo{{ID: 2}}))
+		assert.Empty(t, purged)
+	})
+
+	t.Run("the osd is not purged until it is safe to destroy", func(t *testing.T) {
+		safeToDestroy["0"] = false
+		assert.NoError(t, c.replaceSwappedOSDs("node0", []OSDInfo{{ID: 2}, {ID: 4}}))
+		assert.Empty(t, purged)
+		assert.True(t, deploymentExists(0))
+		safeToDestroy["0"] = true
+	})
+
+	t.Run("the osd in the slot of a new osd is replaced", func(t *testing.T) {
+		assert.NoError(t, c.replaceSwappedOSDs("node0", []OSDInfo{{ID: 2}, {ID: 4}}))
+		assert.Equal(t, []string{"0"}, purged)
+		assert.False(t, deploymentExists(0))
+	})
+
+	t.Run("up osds and osds without a slot are not replaced", func(t *testing.T) {
+		purged = []string{}
+		assert.NoError(t, c.replaceSwappedOSDs("node0", []OSDInfo{{ID: 2}, {ID: 6}}))
+		assert.Equal(t, []string{"1"}, purged)
+		for _, id := range []int{2, 3, 5, 7} {
+			assert.True(t, deploymentExists(id))
+		}
+	})
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
@@ -92,7 +93,8 @@ func (c *updateConfig) updateExistingOSDs(errs *provisionErrors) {
 		osdIDs, err = cephclient.OSDOkToStop(c.cluster.context, c.cluster.clusterInfo, osdIDQuery, maxUpdatesInParallel)
 		if err != nil {
 			if c.cluster.spec.ContinueUpgradeAfterChecksEvenIfNotHealthy {
-				logger.Infof("OSD %d is not ok-to-stop but 'continueUpgradeAfterChecksEvenIfNotHealthy' is true, so continuing to update it", osdIDQuery)
+				reporting.ReportCephClusterWarning(logger, c.cluster.context, c.cluster.clusterInfo, cephv1.UpgradeChecksBypassedReason,
+					fmt.Sprintf("OSD %d is not ok-to-stop but 'continueUpgradeAfterChecksEvenIfNotHealthy' is true, so continuing to update it. %v", osdIDQuery, err))
 				osdIDs = []int{osdIDQuery} // make sure to update the queried OSD
 			} else {
 				logger.Infof("OSD %d is not ok-to-stop. will try updating it again later", osdIDQuery)
@@ -20,6 +20,8 @@ import (
 	"fmt"
 	"path"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	kms "github.com/rook/rook/pkg/daemon/ceph/osd/kms"
 	"github.com/rook/rook/pkg/operator/ceph/config"
@@ -32,6 +34,7 @@ const (
 	osdEncryptionVolName = "osd-encryption-key"
 	dmPath               = "/dev/mapper"
 	dmVolName            = "dev-mapper"
+	hugePagesVolName     = "hugepages"
 )
 
 func getPvcOSDBridgeMount(claimName string) v1.VolumeMount {
@@ -190,6 +193,44 @@ func getUdevVolume() (v1.Volume, v1.VolumeMount) {
 	return volume, volumeMounts
 }
 
+// getHugePagesVolumes returns a hugetlbfs volume and its mount for each size of hugepages in the resources of the
+// OSD, so that bluestore can allocate its buffers from the hugepages. Kubernetes requires a volume per size when
+// several sizes are requested.
+func getHugePagesVolumes(resources v1.ResourceRequirements) ([]v1.Volume, []v1.VolumeMount) {
+	sizes := map[string]bool{}
+	for _, list := range []v1.ResourceList{resources.Limits, resources.Requests} {
+		for name := range list {
+			if strings.HasPrefix(string(name), v1.ResourceHugePagesPrefix) {
+				sizes[strings.TrimPrefix(string(name), v1.ResourceHugePagesPrefix)] = true
+			}
+		}
+	}
+
+	sortedSizes := []string{}
+	for size := range sizes {
+		sortedSizes = append(sortedSizes, size)
+	}
+	sort.Strings(sortedSizes)
+
+	volumes := []v1.Volume{}
+	volumeMounts := []v1.VolumeMount{}
+	for _, size := range sortedSizes {
+		name := hugePagesVolName + "-" + strings.ToLower(size)
+		volumes = append(volumes, v1.Volume{
+			Name: name,
+			VolumeSource: v1.VolumeSource{
+				EmptyDir: &v1.EmptyDirVolumeSource{Medium: v1.StorageMediumHugePagesPrefix + v1.StorageMedium(size)},
+			},
+		})
+		volumeMounts = append(volumeMounts, v1.VolumeMount{
+			Name:      name,
+			MountPath: "/" + hugePagesVolName + "-" + size,
+		})
+	}
+
+	return volumes, volumeMounts
+}
+
 func (c *Cluster) getEncryptionVolume(osdProps osdProperties) (v1.Volume, v1.VolumeMount) {
 	// Generate volume
 	var m int32 = 0400
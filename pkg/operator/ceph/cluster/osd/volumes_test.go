@@ -23,6 +23,7 @@ import (
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 func TestGetEncryptionVolume(t *testing.T) {
@@ -57,3 +58,30 @@ func TestGetDataBridgeVolumeSource(t *testing.T) {
 	source = getDataBridgeVolumeSource(claimName, configDir, namespace, false)
 	assert.Equal(t, v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: filepath.Join(configDir, namespace, claimName), Type: &hostPathType}}, source)
 }
+
+func TestGetHugePagesVolumes(t *testing.T) {
+	// no hugepages
+	volumes, volumeMounts := getHugePagesVolumes(v1.ResourceRequirements{
+		Limits: v1.ResourceList{v1.ResourceMemory: resource.MustParse("4Gi")},
+	})
+	assert.Empty(t, volumes)
+	assert.Empty(t, volumeMounts)
+
+	// a volume per size of hugepages
+	volumes, volumeMounts = getHugePagesVolumes(v1.ResourceRequirements{
+		Limits: v1.ResourceList{
+			v1.ResourceMemory: resource.MustParse("4Gi"),
+			"hugepages-2Mi":   resource.MustParse("1Gi"),
+			"hugepages-1Gi":   resource.MustParse("2Gi"),
+		},
+		Requests: v1.ResourceList{"hugepages-2Mi": resource.MustParse("1Gi")},
+	})
+	assert.Equal(t, []v1.Volume{
+		{Name: "hugepages-1gi", VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{Medium: "HugePages-1Gi"}}},
+		{Name: "hugepages-2mi", VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{Medium: "HugePages-2Mi"}}},
+	}, volumes)
+	assert.Equal(t, []v1.VolumeMount{
+		{Name: "hugepages-1gi", MountPath: "/hugepages-1Gi"},
+		{Name: "hugepages-2mi", MountPath: "/hugepages-2Mi"},
+	}, volumeMounts)
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/osd"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// checkOSDVolumes applies the provider settings of the device sets to the PVs of their PVCs once the PVCs are
+// bound, and forwards the warning events of the PVCs to the cluster, such as the throttling of their disks.
+func (c *cephStatusChecker) checkOSDVolumes(cephCluster *cephv1.CephCluster) {
+	providers := map[string]*cephv1.DeviceSetProviderSpec{}
+	for _, deviceSet := range cephCluster.Spec.Storage.StorageClassDeviceSets {
+		if deviceSet.Provider != nil {
+			providers[deviceSet.Name] = deviceSet.Provider
+		}
+	}
+	if len(providers) == 0 {
+		return
+	}
+
+	pvcs, err := c.context.Clientset.CoreV1().PersistentVolumeClaims(c.clusterInfo.Namespace).List(c.clusterInfo.Context, metav1.ListOptions{LabelSelector: osd.CephDeviceSetLabelKey})
+	if err != nil {
+		logger.Errorf("failed to list the OSD PVCs. %v", err)
+		return
+	}
+
+	reportedPVCs := map[string]bool{}
+	for _, pvc := range pvcs.Items {
+		provider, ok := providers[pvc.Labels[osd.CephDeviceSetLabelKey]]
+		if !ok {
+			continue
+		}
+		if provider.ReportEvents {
+			reportedPVCs[pvc.Name] = true
+		}
+		if pvc.Spec.VolumeName == "" {
+			// the PV is not provisioned yet
+			continue
+		}
+		if err := c.updateOSDVolume(pvc.Spec.VolumeName, provider); err != nil {
+			logger.Errorf("failed to apply the provider settings to the PV of OSD PVC %q. %v", pvc.Name, err)
+		}
+	}
+
+	if len(reportedPVCs) > 0 {
+		c.forwardOSDVolumeEvents(cephCluster, reportedPVCs)
+	}
+}
+
+// updateOSDVolume updates the reclaim policy and the labels of the PV of an OSD PVC
+func (c *cephStatusChecker) updateOSDVolume(pvName string, provider *cephv1.DeviceSetProviderSpec) error {
+	if provider.ReclaimPolicy == "" && len(provider.Labels) == 0 {
+		return nil
+	}
+	pv, err := c.context.Clientset.CoreV1().PersistentVolumes().Get(c.clusterInfo.Context, pvName, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get PV %q", pvName)
+	}
+
+	updated := false
+	if provider.ReclaimPolicy != "" && pv.Spec.PersistentVolumeReclaimPolicy != provider.ReclaimPolicy {
+		logger.Infof("setting the reclaim policy of OSD PV %q to %q", pv.Name, provider.ReclaimPolicy)
+		pv.Spec.PersistentVolumeReclaimPolicy = provider.ReclaimPolicy
+		updated = true
+	}
+	for k, v := range provider.Labels {
+		if pv.Labels[k] != v {
+			if pv.Labels == nil {
+				pv.Labels = map[string]string{}
+			}
+			pv.Labels[k] = v
+			updated = true
+		}
+	}
+	if !updated {
+		return nil
+	}
+
+	if _, err := c.context.Clientset.CoreV1().PersistentVolumes().Update(c.clusterInfo.Context, pv, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrapf(err, "failed to update PV %q", pv.Name)
+	}
+	return nil
+}
+
+// forwardOSDVolumeEvents records the warning events of the OSD PVCs since the last check on the cluster, so the
+// issues of the provider disks are visible with the events of the cluster
+func (c *cephStatusChecker) forwardOSDVolumeEvents(cephCluster *cephv1.CephCluster, pvcs map[string]bool) {
+	since := c.osdVolumeEventsSince
+	events, err := c.context.Clientset.CoreV1().Events(c.clusterInfo.Namespace).List(c.clusterInfo.Context, metav1.ListOptions{
+		FieldSelector: "involvedObject.kind=PersistentVolumeClaim,type=Warning",
+	})
+	if err != nil {
+		logger.Errorf("failed to list the events of the OSD PVCs. %v", err)
+		return
+	}
+	// track the time of the latest event rather than the time of the check since the events are
+	// only timestamped to the second
+	latest := since
+	if latest.IsZero() {
+		latest = time.Now()
+	}
+	for _, event := range events.Items {
+		if event.Type != v1.EventTypeWarning || event.InvolvedObject.Kind != "PersistentVolumeClaim" || !pvcs[event.InvolvedObject.Name] {
+			continue
+		}
+		timestamp := eventTime(event)
+		if timestamp.After(latest) {
+			latest = timestamp
+		}
+		// the first check only starts the tracking, the older events were reported by the previous operator
+		if since.IsZero() || !timestamp.After(since) {
+			continue
+		}
+		logger.Warningf("OSD PVC %q: %s: %s", event.InvolvedObject.Name, event.Reason, event.Message)
+		c.recordEvent(cephCluster, v1.EventTypeWarning, cephv1.OSDVolumeWarningReason,
+			fmt.Sprintf("OSD PVC %q: %s: %s", event.InvolvedObject.Name, event.Reason, event.Message))
+	}
+	c.osdVolumeEventsSince = latest
+}
+
+func eventTime(event v1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	return event.EventTime.Time
+}
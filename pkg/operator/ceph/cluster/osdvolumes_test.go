@@ -0,0 +1,109 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/osd"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestCheckOSDVolumes(t *testing.T) {
+	ctx := context.TODO()
+	clientset := fake.NewSimpleClientset(
+		&v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "set1-data-0-abcde", Namespace: "rook-ceph", Labels: map[string]string{osd.CephDeviceSetLabelKey: "set1"}},
+			Spec:       v1.PersistentVolumeClaimSpec{VolumeName: "pv-1"},
+		},
+		&v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "set1-data-1-fghij", Namespace: "rook-ceph", Labels: map[string]string{osd.CephDeviceSetLabelKey: "set1"}},
+		},
+		&v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "set2-data-0-klmno", Namespace: "rook-ceph", Labels: map[string]string{osd.CephDeviceSetLabelKey: "set2"}},
+			Spec:       v1.PersistentVolumeClaimSpec{VolumeName: "pv-2"},
+		},
+		&v1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pv-1"},
+			Spec:       v1.PersistentVolumeSpec{PersistentVolumeReclaimPolicy: v1.PersistentVolumeReclaimDelete},
+		},
+		&v1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pv-2"},
+			Spec:       v1.PersistentVolumeSpec{PersistentVolumeReclaimPolicy: v1.PersistentVolumeReclaimDelete},
+		},
+	)
+	recorder := record.NewFakeRecorder(10)
+	c := &cephStatusChecker{
+		context:     &clusterd.Context{Clientset: clientset},
+		clusterInfo: cephclient.AdminTestClusterInfo("rook-ceph"),
+		recorder:    recorder,
+	}
+	cephCluster := &cephv1.CephCluster{Spec: cephv1.ClusterSpec{Storage: cephv1.StorageScopeSpec{
+		StorageClassDeviceSets: []cephv1.StorageClassDeviceSet{
+			{Name: "set1", Provider: &cephv1.DeviceSetProviderSpec{
+				Labels:        map[string]string{"cost-center": "storage"},
+				ReclaimPolicy: v1.PersistentVolumeReclaimRetain,
+				ReportEvents:  true,
+			}},
+			{Name: "set2"},
+		},
+	}}}
+	addEvent := func(name, pvc, eventType string, timestamp time.Time) {
+		_, err := clientset.CoreV1().Events("rook-ceph").Create(ctx, &v1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "rook-ceph"},
+			InvolvedObject: v1.ObjectReference{Kind: "PersistentVolumeClaim", Name: pvc},
+			Type:           eventType,
+			Reason:         "VolumeThrottled",
+			Message:        "the burst balance of the volume is exhausted",
+			LastTimestamp:  metav1.NewTime(timestamp),
+		}, metav1.CreateOptions{})
+		assert.NoError(t, err)
+	}
+	addEvent("old", "set1-data-0-abcde", v1.EventTypeWarning, time.Now().Add(-time.Hour))
+
+	// the first check applies the settings to the bound PVs and only starts tracking the events
+	c.checkOSDVolumes(cephCluster)
+	pv, err := clientset.CoreV1().PersistentVolumes().Get(ctx, "pv-1", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, v1.PersistentVolumeReclaimRetain, pv.Spec.PersistentVolumeReclaimPolicy)
+	assert.Equal(t, "storage", pv.Labels["cost-center"])
+	pv, err = clientset.CoreV1().PersistentVolumes().Get(ctx, "pv-2", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, v1.PersistentVolumeReclaimDelete, pv.Spec.PersistentVolumeReclaimPolicy)
+	assert.Empty(t, recorder.Events)
+
+	// only the new warnings of the PVCs of the device sets reporting the events are forwarded
+	addEvent("throttled", "set1-data-0-abcde", v1.EventTypeWarning, time.Now().Add(time.Second))
+	addEvent("normal", "set1-data-0-abcde", v1.EventTypeNormal, time.Now().Add(time.Second))
+	addEvent("other-set", "set2-data-0-klmno", v1.EventTypeWarning, time.Now().Add(time.Second))
+	c.checkOSDVolumes(cephCluster)
+	assert.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, `OSDVolumeWarning OSD PVC "set1-data-0-abcde": VolumeThrottled`)
+
+	// the events are not forwarded twice
+	c.checkOSDVolumes(cephCluster)
+	assert.Empty(t, recorder.Events)
+}
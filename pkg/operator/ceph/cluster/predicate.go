@@ -39,16 +39,17 @@ func predicateForNodeWatcher(ctx context.Context, client client.Client, context
 	return predicate.Funcs{
 		CreateFunc: func(e event.CreateEvent) bool {
 			clientCluster := newClientCluster(client, e.Object.GetNamespace(), context)
-			return clientCluster.onK8sNode(ctx, e.Object)
+			return clientCluster.onNodeOnboarding(e.Object, false) || clientCluster.onK8sNode(ctx, e.Object)
 		},
 
 		UpdateFunc: func(e event.UpdateEvent) bool {
 			clientCluster := newClientCluster(client, e.ObjectNew.GetNamespace(), context)
-			return clientCluster.onK8sNode(ctx, e.ObjectNew)
+			return clientCluster.onNodeOnboarding(e.ObjectNew, false) || clientCluster.onK8sNode(ctx, e.ObjectNew)
 		},
 
 		DeleteFunc: func(e event.DeleteEvent) bool {
-			return false
+			clientCluster := newClientCluster(client, e.Object.GetNamespace(), context)
+			return clientCluster.onNodeOnboarding(e.Object, true)
 		},
 
 		GenericFunc: func(e event.GenericEvent) bool {
@@ -140,6 +141,10 @@ func watchControllerPredicate(ctx context.Context, c client.Client) predicate.Fu
 					logger.Debugf("object %q matched on update but %q label is set, doing nothing", controller.DoNotReconcileLabelName, objNew.Name)
 					return false
 				}
+				if controller.IsDoNotReconcile(objOld.GetLabels()) {
+					logger.Infof("%q label removed from CR %q, resuming the reconcile", controller.DoNotReconcileLabelName, objNew.Name)
+					return true
+				}
 				diff := cmp.Diff(objOld.Spec, objNew.Spec, resourceQtyComparer)
 				if diff != "" {
 					logger.Infof("CR has changed for %q. diff=%s", objNew.Name, diff)
@@ -0,0 +1,334 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/daemon/ceph/preflight"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/operator/k8sutil/cmdreporter"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	preflightAppName        = "rook-ceph-preflight"
+	preflightModulesVolume  = "kernel-modules"
+	preflightModulesPath    = "/lib/modules"
+	defaultPreflightTimeout = 5 * time.Minute
+)
+
+var (
+	defaultPreflightKernelModules = []string{"rbd", "nbd"}
+	// the prefixes of the sysctls of the ipc namespace, which are read from the host ipc namespace
+	ipcSysctlPrefixes  = []string{"kernel.shm", "kernel.msg", "kernel.sem", "fs.mqueue."}
+	allPreflightChecks = []cephv1.PreflightCheck{
+		cephv1.PreflightCheckKernelModules,
+		cephv1.PreflightCheckTimeSync,
+		cephv1.PreflightCheckPorts,
+		cephv1.PreflightCheckSysctls,
+		cephv1.PreflightCheckDiskWriteCache,
+	}
+)
+
+// runPreflightJob runs the preflight checks in a job on the node with the hostname and returns the result.
+// Overridden for unit testing.
+var runPreflightJob = func(c *cluster, rookImage, hostname string, args []string, timeout time.Duration) (preflight.Result, error) {
+	result := preflight.Result{}
+	jobName := k8sutil.TruncateNodeNameForJob("rook-ceph-preflight-%s", hostname)
+	reporter, err := cmdreporter.New(
+		c.context.Clientset,
+		c.ownerInfo,
+		preflightAppName,
+		jobName,
+		c.Namespace,
+		[]string{"rook"},
+		args,
+		rookImage,
+		rookImage,
+		c.Spec.CephVersion.ImagePullPolicy,
+	)
+	if err != nil {
+		return result, errors.Wrap(err, "failed to set up preflight job")
+	}
+
+	job := reporter.Job()
+	podSpec := &job.Spec.Template.Spec
+	podSpec.ServiceAccountName = "rook-ceph-cmd-reporter"
+	podSpec.NodeSelector = map[string]string{v1.LabelHostname: hostname}
+	podSpec.Tolerations = getCleanupPlacement(*c.Spec).Tolerations
+	// the ports are checked on the host network used by the daemons, and the sysctls of the network and ipc
+	// namespaces are read from the namespaces of the host rather than of the pod
+	hostNetwork, hostIPC := preflightHostNamespaces(c.Spec)
+	podSpec.HostNetwork = c.Spec.Network.IsHost() || hostNetwork
+	podSpec.HostIPC = hostIPC
+	podSpec.Volumes = append(podSpec.Volumes, v1.Volume{
+		Name:         preflightModulesVolume,
+		VolumeSource: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: preflightModulesPath}},
+	})
+	for i := range podSpec.Containers {
+		podSpec.Containers[i].VolumeMounts = append(podSpec.Containers[i].VolumeMounts,
+			v1.VolumeMount{Name: preflightModulesVolume, MountPath: preflightModulesPath, ReadOnly: true})
+	}
+
+	stdout, stderr, retcode, err := reporter.Run(c.ClusterInfo.Context, timeout)
+	if err != nil {
+		return result, errors.Wrap(err, "failed to complete preflight job")
+	}
+	if retcode != 0 {
+		return result, errors.Errorf("preflight job returned failure with retcode %d. stdout: %s. stderr: %s", retcode, stdout, stderr)
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		return result, errors.Wrapf(err, "failed to unmarshal preflight result %q", stdout)
+	}
+	return result, nil
+}
+
+// preflightHostNamespaces returns whether the sysctls to check are in the network or the ipc namespaces, which
+// are not the namespaces of the host in the pod
+func preflightHostNamespaces(spec *cephv1.ClusterSpec) (bool, bool) {
+	hostNetwork, hostIPC := false, false
+	for _, check := range spec.Preflight.SkipChecks {
+		if check == cephv1.PreflightCheckSysctls {
+			return false, false
+		}
+	}
+	for name := range spec.Preflight.Sysctls {
+		if strings.HasPrefix(name, "net.") {
+			hostNetwork = true
+		}
+		for _, prefix := range ipcSysctlPrefixes {
+			if strings.HasPrefix(name, prefix) {
+				hostIPC = true
+			}
+		}
+	}
+	return hostNetwork, hostIPC
+}
+
+// runPreflightChecks validates the storage nodes before the mons of a new cluster are created. A job runs the
+// checks on each node, and the orchestration is blocked with the failures of each node in the status until
+// all the checks pass.
+func (c *cluster) runPreflightChecks(rookImage string) error {
+	if !c.Spec.Preflight.Enabled {
+		return nil
+	}
+
+	opts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", k8sutil.AppAttr, mon.AppName)}
+	monDeployments, err := c.context.Clientset.AppsV1().Deployments(c.Namespace).List(c.ClusterInfo.Context, opts)
+	if err != nil {
+		return errors.Wrap(err, "failed to list mon deployments before the preflight checks")
+	}
+	if len(monDeployments.Items) > 0 {
+		logger.Debug("skipping preflight checks of the existing cluster")
+		return nil
+	}
+
+	nodes, err := c.preflightNodes()
+	if err != nil {
+		return err
+	}
+	if len(nodes) == 0 {
+		logger.Warning("no valid nodes to run the preflight checks")
+		return nil
+	}
+
+	timeout := defaultPreflightTimeout
+	if c.Spec.Preflight.Timeout != nil {
+		timeout = c.Spec.Preflight.Timeout.Duration
+	}
+	hostnames := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		hostnames = append(hostnames, node.Name)
+	}
+	logger.Infof("running preflight checks on nodes %v", hostnames)
+
+	status := &cephv1.PreflightStatus{LastChecked: time.Now().UTC().Format(time.RFC3339)}
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for i := range nodes {
+		wg.Add(1)
+		go func(node *cephv1.Node) {
+			defer wg.Done()
+			result, err := runPreflightJob(c, rookImage, node.Name, preflightArgs(c.Spec, node), timeout)
+			if err != nil {
+				result.Failures = []string{err.Error()}
+			}
+			for _, warning := range result.Warnings {
+				logger.Warningf("preflight check warning on node %q: %s", node.Name, warning)
+			}
+			if len(result.Failures) == 0 {
+				return
+			}
+			mutex.Lock()
+			defer mutex.Unlock()
+			status.FailedNodes = append(status.FailedNodes, cephv1.PreflightNodeStatus{Name: node.Name, Failures: result.Failures})
+		}(&nodes[i])
+	}
+	wg.Wait()
+
+	sort.Slice(status.FailedNodes, func(i, j int) bool { return status.FailedNodes[i].Name < status.FailedNodes[j].Name })
+	status.Passed = len(status.FailedNodes) == 0
+	if err := c.updatePreflightStatus(status); err != nil {
+		return err
+	}
+	if !status.Passed {
+		return errors.Errorf("preflight checks failed. %s", preflightReport(status.FailedNodes))
+	}
+	logger.Infof("preflight checks passed on %d node(s)", len(nodes))
+	return nil
+}
+
+// preflightNodes returns the valid storage nodes with their resolved device selection, or all the valid nodes
+// if all the nodes are used or no node is listed
+func (c *cluster) preflightNodes() ([]cephv1.Node, error) {
+	storage := *c.Spec.Storage.DeepCopy()
+	if storage.UseAllNodes || len(storage.Nodes) == 0 {
+		hostnameMap, err := k8sutil.GetNodeHostNames(c.ClusterInfo.Context, c.context.Clientset)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get node hostnames for the preflight checks")
+		}
+		storage.Nodes = nil
+		for _, hostname := range hostnameMap {
+			storage.Nodes = append(storage.Nodes, cephv1.Node{Name: hostname})
+		}
+	}
+
+	nodes := []cephv1.Node{}
+	for _, node := range k8sutil.GetValidNodes(c.ClusterInfo.Context, storage, c.context.Clientset, cephv1.GetOSDPlacement(c.Spec.Placement)) {
+		resolved := storage.ResolveNode(node.Name)
+		if resolved == nil {
+			resolved = &cephv1.Node{Name: node.Name, Selection: storage.Selection}
+		}
+		nodes = append(nodes, *resolved)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+	return nodes, nil
+}
+
+// preflightArgs returns the args of the preflight command for the checks of the spec on the node
+func preflightArgs(spec *cephv1.ClusterSpec, node *cephv1.Node) []string {
+	skipped := map[cephv1.PreflightCheck]bool{}
+	for _, check := range spec.Preflight.SkipChecks {
+		skipped[check] = true
+	}
+	// the ports of the daemons are only bound on the host with host networking
+	if !spec.Network.IsHost() {
+		skipped[cephv1.PreflightCheckPorts] = true
+	}
+	if len(spec.Preflight.Sysctls) == 0 {
+		skipped[cephv1.PreflightCheckSysctls] = true
+	}
+
+	checks := []string{}
+	for _, check := range allPreflightChecks {
+		if !skipped[check] {
+			checks = append(checks, string(check))
+		}
+	}
+	args := []string{"ceph", "preflight", "--checks", strings.Join(checks, ",")}
+
+	if !skipped[cephv1.PreflightCheckKernelModules] {
+		modules := spec.Preflight.KernelModules
+		if len(modules) == 0 {
+			modules = defaultPreflightKernelModules
+		}
+		args = append(args, "--kernel-modules", strings.Join(modules, ","))
+	}
+	if !skipped[cephv1.PreflightCheckSysctls] {
+		names := make([]string, 0, len(spec.Preflight.Sysctls))
+		for name := range spec.Preflight.Sysctls {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			args = append(args, "--sysctls", fmt.Sprintf("%s=%s", name, spec.Preflight.Sysctls[name]))
+		}
+	}
+	if !skipped[cephv1.PreflightCheckPorts] {
+		ports := []string{strconv.Itoa(int(mon.DefaultMsgr2Port))}
+		if !spec.RequireMsgr2() {
+			ports = append(ports, strconv.Itoa(int(mon.DefaultMsgr1Port)))
+		}
+		args = append(args, "--ports", strings.Join(ports, ","))
+	}
+	if !skipped[cephv1.PreflightCheckDiskWriteCache] {
+		args = append(args, preflightDeviceArgs(node)...)
+	}
+	return args
+}
+
+// preflightDeviceArgs returns the args selecting the OSD devices of the node, whose write cache is checked. No
+// device is checked on the nodes without a device selection, e.g. with the OSDs on PVCs.
+func preflightDeviceArgs(node *cephv1.Node) []string {
+	selection := node.Selection
+	if selection.UseAllDevices != nil && *selection.UseAllDevices {
+		return []string{"--all-devices"}
+	}
+	args := []string{}
+	names := []string{}
+	for _, device := range selection.Devices {
+		if device.Name != "" {
+			names = append(names, device.Name)
+		}
+	}
+	if len(names) > 0 {
+		args = append(args, "--devices", strings.Join(names, ","))
+	}
+	if selection.DeviceFilter != "" {
+		args = append(args, "--device-filter", selection.DeviceFilter)
+	}
+	for _, filter := range selection.DeviceFilters {
+		args = append(args, "--device-filter", filter.Filter)
+	}
+	if selection.DevicePathFilter != "" {
+		args = append(args, "--device-path-filter", selection.DevicePathFilter)
+	}
+	return args
+}
+
+// preflightReport returns the failures of each node in a single message
+func preflightReport(failedNodes []cephv1.PreflightNodeStatus) string {
+	reports := []string{}
+	for _, node := range failedNodes {
+		reports = append(reports, fmt.Sprintf("node %q: %s", node.Name, strings.Join(node.Failures, "; ")))
+	}
+	return strings.Join(reports, ". ")
+}
+
+func (c *cluster) updatePreflightStatus(status *cephv1.PreflightStatus) error {
+	cephCluster := &cephv1.CephCluster{}
+	if err := c.context.Client.Get(c.ClusterInfo.Context, c.namespacedName, cephCluster); err != nil {
+		return errors.Wrap(err, "failed to get cluster to update the preflight status")
+	}
+	cephCluster.Status.Preflight = status
+	if err := reporting.UpdateStatus(c.context.Client, cephCluster); err != nil {
+		return errors.Wrap(err, "failed to update the preflight status")
+	}
+	return nil
+}
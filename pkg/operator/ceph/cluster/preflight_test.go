@@ -0,0 +1,204 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/daemon/ceph/preflight"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	testop "github.com/rook/rook/pkg/operator/test"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPreflightArgs(t *testing.T) {
+	spec := &cephv1.ClusterSpec{}
+	node := &cephv1.Node{Name: "node0"}
+	assert.Equal(t, []string{"ceph", "preflight", "--checks", "kernel-modules,time-sync,disk-write-cache", "--kernel-modules", "rbd,nbd"}, preflightArgs(spec, node))
+
+	spec.Network.Provider = "host"
+	spec.Preflight = cephv1.PreflightSpec{
+		SkipChecks:    []cephv1.PreflightCheck{cephv1.PreflightCheckDiskWriteCache},
+		KernelModules: []string{"rbd"},
+		Sysctls:       map[string]string{"vm.nr_hugepages": "1024", "kernel.pid_max": "4194304"},
+	}
+	node.Selection.Devices = []cephv1.Device{{Name: "sdb"}}
+	assert.Equal(t, []string{
+		"ceph", "preflight", "--checks", "kernel-modules,time-sync,ports,sysctls",
+		"--kernel-modules", "rbd",
+		"--sysctls", "kernel.pid_max=4194304", "--sysctls", "vm.nr_hugepages=1024",
+		"--ports", "3300,6789",
+	}, preflightArgs(spec, node))
+
+	spec.Network.Connections = &cephv1.ConnectionsSpec{RequireMsgr2: true}
+	spec.Preflight.SkipChecks = []cephv1.PreflightCheck{cephv1.PreflightCheckKernelModules, cephv1.PreflightCheckSysctls}
+	assert.Equal(t, []string{"ceph", "preflight", "--checks", "time-sync,ports,disk-write-cache", "--ports", "3300", "--devices", "sdb"}, preflightArgs(spec, node))
+}
+
+func TestPreflightDeviceArgs(t *testing.T) {
+	assert.Empty(t, preflightDeviceArgs(&cephv1.Node{}))
+
+	useAll := true
+	assert.Equal(t, []string{"--all-devices"}, preflightDeviceArgs(&cephv1.Node{Selection: cephv1.Selection{UseAllDevices: &useAll, DeviceFilter: "^sd"}}))
+
+	assert.Equal(t, []string{
+		"--devices", "sdb,/dev/nvme0n1",
+		"--device-filter", "^sd[c-d]",
+		"--device-filter", "^vd{1,2}",
+		"--device-path-filter", "^/dev/xvd",
+	}, preflightDeviceArgs(&cephv1.Node{Selection: cephv1.Selection{
+		Devices:          []cephv1.Device{{Name: "sdb"}, {Name: "/dev/nvme0n1"}, {FullPath: "/dev/disk/by-id/wwn-1"}},
+		DeviceFilter:     "^sd[c-d]",
+		DeviceFilters:    []cephv1.DeviceFilter{{Filter: "^vd{1,2}"}},
+		DevicePathFilter: "^/dev/xvd",
+	}}))
+}
+
+func TestPreflightHostNamespaces(t *testing.T) {
+	spec := &cephv1.ClusterSpec{}
+	hostNetwork, hostIPC := preflightHostNamespaces(spec)
+	assert.False(t, hostNetwork)
+	assert.False(t, hostIPC)
+
+	spec.Preflight.Sysctls = map[string]string{"vm.nr_hugepages": "1024", "net.core.somaxconn": "4096"}
+	hostNetwork, hostIPC = preflightHostNamespaces(spec)
+	assert.True(t, hostNetwork)
+	assert.False(t, hostIPC)
+
+	spec.Preflight.Sysctls["kernel.shmmax"] = "68719476736"
+	hostNetwork, hostIPC = preflightHostNamespaces(spec)
+	assert.True(t, hostNetwork)
+	assert.True(t, hostIPC)
+
+	spec.Preflight.SkipChecks = []cephv1.PreflightCheck{cephv1.PreflightCheckSysctls}
+	hostNetwork, hostIPC = preflightHostNamespaces(spec)
+	assert.False(t, hostNetwork)
+	assert.False(t, hostIPC)
+}
+
+func TestRunPreflightChecks(t *testing.T) {
+	nsName := types.NamespacedName{Namespace: "rook-ceph", Name: "my-cluster"}
+	scheme := runtime.NewScheme()
+	assert.NoError(t, cephv1.AddToScheme(scheme))
+
+	oldRunJob := runPreflightJob
+	defer func() { runPreflightJob = oldRunJob }()
+	var mutex sync.Mutex
+	ranOnNodes := []string{}
+	runPreflightJob = func(c *cluster, rookImage, hostname string, args []string, timeout time.Duration) (preflight.Result, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		ranOnNodes = append(ranOnNodes, hostname)
+		assert.Equal(t, "rook/ceph:master", rookImage)
+		assert.Equal(t, time.Minute, timeout)
+		switch hostname {
+		case "node0":
+			// the warnings do not fail the checks
+			return preflight.Result{Warnings: []string{`device "sdb" has a volatile write cache (write back)`}}, nil
+		case "node1":
+			return preflight.Result{Failures: []string{"the clock is not synchronized, chrony or another NTP service must be running"}}, nil
+		case "node2":
+			return preflight.Result{}, errors.New("failed to complete preflight job")
+		}
+		return preflight.Result{}, nil
+	}
+
+	newTestCluster := func(t *testing.T, storage cephv1.StorageScopeSpec) *cluster {
+		cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: nsName.Name, Namespace: nsName.Namespace}}
+		clusterInfo := cephclient.AdminTestClusterInfo(nsName.Namespace)
+		clusterInfo.Context = context.TODO()
+		ranOnNodes = []string{}
+		return &cluster{
+			ClusterInfo: clusterInfo,
+			context: &clusterd.Context{
+				Clientset: testop.New(t, 3),
+				Client:    clientfake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(cephCluster).Build(),
+			},
+			Namespace: nsName.Namespace,
+			Spec: &cephv1.ClusterSpec{
+				Storage:   storage,
+				Preflight: cephv1.PreflightSpec{Enabled: true, Timeout: &metav1.Duration{Duration: time.Minute}},
+			},
+			namespacedName: nsName,
+		}
+	}
+	getStatus := func(t *testing.T, c *cluster) *cephv1.PreflightStatus {
+		cephCluster := &cephv1.CephCluster{}
+		assert.NoError(t, c.context.Client.Get(context.TODO(), nsName, cephCluster))
+		return cephCluster.Status.Preflight
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		c := newTestCluster(t, cephv1.StorageScopeSpec{UseAllNodes: true})
+		c.Spec.Preflight.Enabled = false
+		assert.NoError(t, c.runPreflightChecks("rook/ceph:master"))
+		assert.Empty(t, ranOnNodes)
+		assert.Nil(t, getStatus(t, c))
+	})
+
+	t.Run("checks pass on the storage nodes", func(t *testing.T) {
+		c := newTestCluster(t, cephv1.StorageScopeSpec{Nodes: []cephv1.Node{{Name: "node0"}}})
+		assert.NoError(t, c.runPreflightChecks("rook/ceph:master"))
+		assert.Equal(t, []string{"node0"}, ranOnNodes)
+		status := getStatus(t, c)
+		assert.True(t, status.Passed)
+		assert.Empty(t, status.FailedNodes)
+		assert.NotEmpty(t, status.LastChecked)
+	})
+
+	t.Run("checks fail on all the nodes", func(t *testing.T) {
+		c := newTestCluster(t, cephv1.StorageScopeSpec{UseAllNodes: true})
+		err := c.runPreflightChecks("rook/ceph:master")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), `node "node1": the clock is not synchronized`)
+		assert.Contains(t, err.Error(), `node "node2": failed to complete preflight job`)
+		assert.ElementsMatch(t, []string{"node0", "node1", "node2"}, ranOnNodes)
+
+		status := getStatus(t, c)
+		assert.False(t, status.Passed)
+		assert.Equal(t, []cephv1.PreflightNodeStatus{
+			{Name: "node1", Failures: []string{"the clock is not synchronized, chrony or another NTP service must be running"}},
+			{Name: "node2", Failures: []string{"failed to complete preflight job"}},
+		}, status.FailedNodes)
+	})
+
+	t.Run("existing cluster is not checked", func(t *testing.T) {
+		c := newTestCluster(t, cephv1.StorageScopeSpec{UseAllNodes: true})
+		monDeployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name:      "rook-ceph-mon-a",
+			Namespace: nsName.Namespace,
+			Labels:    map[string]string{k8sutil.AppAttr: mon.AppName},
+		}}
+		_, err := c.context.Clientset.AppsV1().Deployments(nsName.Namespace).Create(context.TODO(), monDeployment, metav1.CreateOptions{})
+		assert.NoError(t, err)
+		assert.NoError(t, c.runPreflightChecks("rook/ceph:master"))
+		assert.Empty(t, ranOnNodes)
+	})
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/util/retry"
+)
+
+var defaultScrubReportInterval = time.Hour
+
+// scrubReporter periodically reports the last scrubs and the inconsistencies found by the scrubs of the pools in the
+// status of the cluster
+type scrubReporter struct {
+	context     *clusterd.Context
+	clusterInfo *cephclient.ClusterInfo
+	interval    time.Duration
+}
+
+func newScrubReporter(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, spec cephv1.ScrubReportSpec) *scrubReporter {
+	r := &scrubReporter{
+		context:     context,
+		clusterInfo: clusterInfo,
+		interval:    defaultScrubReportInterval,
+	}
+	if spec.Interval != nil {
+		r.interval = spec.Interval.Duration
+	}
+	return r
+}
+
+// reportScrubs refreshes the scrub report until the monitoring routine is canceled
+func (r *scrubReporter) reportScrubs(monitoringRoutines map[string]*opcontroller.ClusterHealth, daemon string) {
+	logger.Infof("reporting the scrubs of cluster %q every %s", r.clusterInfo.Namespace, r.interval.String())
+	r.refreshScrubReport()
+
+	for {
+		// We must perform this check otherwise the case will check an index that does not exist anymore and
+		// we will get an invalid pointer error and the go routine will panic
+		if _, ok := monitoringRoutines[daemon]; !ok {
+			logger.Infof("ceph cluster %q has been deleted. stopping the scrub report", r.clusterInfo.Namespace)
+			return
+		}
+		select {
+		case <-monitoringRoutines[daemon].InternalCtx.Done():
+			logger.Infof("stopping the scrub report of cluster %q", r.clusterInfo.Namespace)
+			delete(monitoringRoutines, daemon)
+			return
+
+		case <-time.After(r.interval):
+			r.refreshScrubReport()
+		}
+	}
+}
+
+func (r *scrubReporter) refreshScrubReport() {
+	report, err := r.scrubReport()
+	if err != nil {
+		logger.Errorf("failed to get the scrub report of cluster %q. %v", r.clusterInfo.Namespace, err)
+		report = &cephv1.ScrubReport{Details: err.Error()}
+	}
+	report.LastChecked = time.Now().UTC().Format(time.RFC3339)
+	if report.InconsistentPGs > 0 {
+		logger.Warningf("scrubs found inconsistencies in %d pgs of cluster %q", report.InconsistentPGs, r.clusterInfo.Namespace)
+	}
+	r.updateScrubReport(report)
+}
+
+func (r *scrubReporter) scrubReport() (*cephv1.ScrubReport, error) {
+	pgs, err := cephclient.GetPGScrubStats(r.context, r.clusterInfo)
+	if err != nil {
+		return nil, err
+	}
+	poolNames, err := cephclient.GetPoolNamesByID(r.context, r.clusterInfo)
+	if err != nil {
+		return nil, err
+	}
+	return buildScrubReport(pgs, poolNames)
+}
+
+// buildScrubReport aggregates the scrub state of the placement groups by pool. The last scrubs of a pool are the
+// oldest last scrubs of its placement groups, since the whole pool was only verified since then.
+func buildScrubReport(pgs []cephclient.PGScrubStats, poolNames map[int]string) (*cephv1.ScrubReport, error) {
+	type poolScrubs struct {
+		report        cephv1.PoolScrubReport
+		lastScrub     time.Time
+		lastDeepScrub time.Time
+	}
+	pools := map[int]*poolScrubs{}
+	report := &cephv1.ScrubReport{}
+
+	oldest := func(current time.Time, stamp string) (time.Time, error) {
+		t, err := cephclient.ParsePGStamp(stamp)
+		if err != nil {
+			return current, err
+		}
+		if current.IsZero() || t.Before(current) {
+			return t, nil
+		}
+		return current, nil
+	}
+
+	for i := range pgs {
+		pg := &pgs[i]
+		poolID, err := pg.PoolID()
+		if err != nil {
+			return nil, err
+		}
+		pool, ok := pools[poolID]
+		if !ok {
+			name, ok := poolNames[poolID]
+			if !ok {
+				// the pool was created since the pools were listed
+				name = strconv.Itoa(poolID)
+			}
+			pool = &poolScrubs{report: cephv1.PoolScrubReport{Name: name}}
+			pools[poolID] = pool
+		}
+
+		pool.report.PGs++
+		if pool.lastScrub, err = oldest(pool.lastScrub, pg.LastScrubStamp); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse the last scrub of pg %q", pg.PGID)
+		}
+		if pool.lastDeepScrub, err = oldest(pool.lastDeepScrub, pg.LastDeepScrubStamp); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse the last deep scrub of pg %q", pg.PGID)
+		}
+		if pg.IsInconsistent() {
+			pool.report.InconsistentPGs++
+		}
+		pool.report.ScrubErrors += pg.StatSum.NumScrubErrors
+		pool.report.ObjectsRepaired += pg.StatSum.NumObjectsRepaired
+	}
+
+	for _, pool := range pools {
+		// the pgs that were never scrubbed have the zero stamp of ceph, i.e. the epoch
+		if pool.lastScrub.Unix() > 0 {
+			pool.report.LastScrub = pool.lastScrub.Format(time.RFC3339)
+		}
+		if pool.lastDeepScrub.Unix() > 0 {
+			pool.report.LastDeepScrub = pool.lastDeepScrub.Format(time.RFC3339)
+		}
+		report.InconsistentPGs += pool.report.InconsistentPGs
+		report.ScrubErrors += pool.report.ScrubErrors
+		report.ObjectsRepaired += pool.report.ObjectsRepaired
+		report.Pools = append(report.Pools, pool.report)
+	}
+	sort.Slice(report.Pools, func(i, j int) bool { return report.Pools[i].Name < report.Pools[j].Name })
+	return report, nil
+}
+
+func (r *scrubReporter) updateScrubReport(report *cephv1.ScrubReport) {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cephCluster := &cephv1.CephCluster{}
+		if err := r.context.Client.Get(r.clusterInfo.Context, r.clusterInfo.NamespacedName(), cephCluster); err != nil {
+			if kerrors.IsNotFound(err) {
+				logger.Debug("CephCluster resource not found. Ignoring since object must be deleted.")
+				return nil
+			}
+			return errors.Wrapf(err, "failed to retrieve cluster %q to update the scrub report", r.clusterInfo.Namespace)
+		}
+		cephCluster.Status.ScrubReport = report
+		return reporting.UpdateStatus(r.context.Client, cephCluster)
+	})
+	if err != nil {
+		logger.Errorf("failed to update the scrub report of cluster %q. %v", r.clusterInfo.Namespace, err)
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testScrubPGs = `{"pg_ready":true,"pg_stats":[
+	{"pgid":"1.0","state":"active+clean","last_scrub_stamp":"2023-06-02T10:00:00.000000+0000","last_deep_scrub_stamp":"2023-05-30T10:00:00.000000+0000","stat_sum":{"num_scrub_errors":0,"num_objects_repaired":2}},
+	{"pgid":"1.1","state":"active+clean+inconsistent","last_scrub_stamp":"2023-06-01T10:00:00.000000+0000","last_deep_scrub_stamp":"2023-05-31T10:00:00.000000+0000","stat_sum":{"num_scrub_errors":3,"num_objects_repaired":0}},
+	{"pgid":"2.0","state":"active+clean","last_scrub_stamp":"2023-06-01T12:00:00.000000+0000","last_deep_scrub_stamp":"0.000000","stat_sum":{}}
+]}`
+
+func TestBuildScrubReport(t *testing.T) {
+	var pgs struct {
+		PGStats []cephclient.PGScrubStats `json:"pg_stats"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(testScrubPGs), &pgs))
+
+	report, err := buildScrubReport(pgs.PGStats, map[int]string{1: "replicapool"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.InconsistentPGs)
+	assert.Equal(t, 3, report.ScrubErrors)
+	assert.Equal(t, 2, report.ObjectsRepaired)
+	require.Len(t, report.Pools, 2)
+
+	// the pool created since the pools were listed is reported with its id
+	assert.Equal(t, cephv1.PoolScrubReport{Name: "2", PGs: 1, LastScrub: "2023-06-01T12:00:00Z"}, report.Pools[0])
+	assert.Equal(t, cephv1.PoolScrubReport{
+		Name:            "replicapool",
+		PGs:             2,
+		LastScrub:       "2023-06-01T10:00:00Z",
+		LastDeepScrub:   "2023-05-30T10:00:00Z",
+		InconsistentPGs: 1,
+		ScrubErrors:     3,
+		ObjectsRepaired: 2,
+	}, report.Pools[1])
+
+	pgs.PGStats[0].LastScrubStamp = "invalid"
+	_, err = buildScrubReport(pgs.PGStats, map[int]string{})
+	assert.Error(t, err)
+}
+
+func TestRefreshScrubReport(t *testing.T) {
+	nsName := types.NamespacedName{Namespace: "rook-ceph", Name: "my-cluster"}
+	scheme := runtime.NewScheme()
+	assert.NoError(t, cephv1.AddToScheme(scheme))
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: nsName.Name, Namespace: nsName.Namespace}}
+
+	failPGs := false
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			switch {
+			case args[0] == "pg" && args[1] == "ls":
+				if failPGs {
+					return "", errors.New("timed out")
+				}
+				return testScrubPGs, nil
+			case args[0] == "osd" && args[1] == "lspools":
+				return `[{"poolnum":1,"poolname":"replicapool"},{"poolnum":2,"poolname":".mgr"}]`, nil
+			}
+			return "", errors.Errorf("unexpected ceph command %q", args)
+		},
+	}
+	clusterInfo := cephclient.AdminTestClusterInfo(nsName.Namespace)
+	clusterInfo.SetName(nsName.Name)
+	clusterInfo.Context = context.TODO()
+	clusterdContext := &clusterd.Context{
+		Executor: executor,
+		Client:   clientfake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(cephCluster).Build(),
+	}
+	getReport := func() *cephv1.ScrubReport {
+		cephCluster := &cephv1.CephCluster{}
+		require.NoError(t, clusterdContext.Client.Get(context.TODO(), nsName, cephCluster))
+		return cephCluster.Status.ScrubReport
+	}
+
+	r := newScrubReporter(clusterdContext, clusterInfo, cephv1.ScrubReportSpec{Enabled: true})
+	assert.Equal(t, time.Hour, r.interval)
+	r = newScrubReporter(clusterdContext, clusterInfo, cephv1.ScrubReportSpec{Enabled: true, Interval: &metav1.Duration{Duration: 10 * time.Minute}})
+	assert.Equal(t, 10*time.Minute, r.interval)
+
+	r.refreshScrubReport()
+	report := getReport()
+	require.NotNil(t, report)
+	assert.NotEmpty(t, report.LastChecked)
+	assert.Empty(t, report.Details)
+	require.Len(t, report.Pools, 2)
+	assert.Equal(t, ".mgr", report.Pools[0].Name)
+	assert.Equal(t, 1, report.InconsistentPGs)
+
+	// the error of the refresh is reported
+	failPGs = true
+	r.refreshScrubReport()
+	report = getReport()
+	assert.Contains(t, report.Details, "timed out")
+	assert.Empty(t, report.Pools)
+}
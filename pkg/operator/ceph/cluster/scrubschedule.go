@@ -0,0 +1,74 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"strconv"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+)
+
+// scrubCephConfig returns the options of the "osd" section of the mon database for the scrub scheduling of the
+// cluster spec
+func scrubCephConfig(scrub cephv1.ScrubSpec) map[string]string {
+	options := map[string]string{}
+	ints := []struct {
+		option string
+		value  *int
+	}{
+		{"osd_scrub_begin_hour", scrub.BeginHour},
+		{"osd_scrub_end_hour", scrub.EndHour},
+		{"osd_scrub_begin_week_day", scrub.BeginWeekDay},
+		{"osd_scrub_end_week_day", scrub.EndWeekDay},
+		{"osd_max_scrubs", scrub.MaxScrubs},
+	}
+	for _, i := range ints {
+		if i.value != nil {
+			options[i.option] = strconv.Itoa(*i.value)
+		}
+	}
+	if scrub.DeepScrubInterval != nil {
+		options["osd_deep_scrub_interval"] = strconv.FormatFloat(scrub.DeepScrubInterval.Seconds(), 'f', -1, 64)
+	}
+	return options
+}
+
+// desiredCephConfig returns the ceph config of the cluster spec merged with the options of the scrub scheduling.
+// The options of the ceph config take precedence.
+func (c *cluster) desiredCephConfig() map[string]map[string]string {
+	scrubOptions := scrubCephConfig(c.Spec.Scrub)
+	if len(scrubOptions) == 0 {
+		return c.Spec.CephConfig
+	}
+
+	desired := map[string]map[string]string{}
+	for section, options := range c.Spec.CephConfig {
+		desired[section] = map[string]string{}
+		for option, value := range options {
+			desired[section][option] = value
+		}
+	}
+	if desired["osd"] == nil {
+		desired["osd"] = map[string]string{}
+	}
+	for option, value := range scrubOptions {
+		if _, ok := desired["osd"][option]; !ok {
+			desired["osd"][option] = value
+		}
+	}
+	return desired
+}
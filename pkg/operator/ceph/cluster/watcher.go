@@ -59,6 +59,28 @@ func checkStorageForNode(cluster *cephv1.CephCluster) bool {
 	return true
 }
 
+// onNodeOnboarding is triggered when a node is added, updated or deleted in the Kubernetes cluster, and returns
+// whether the node must be added to or removed from the storage nodes by the onboarding
+func (c *clientCluster) onNodeOnboarding(object runtime.Object, deleted bool) bool {
+	node, ok := object.(*v1.Node)
+	if !ok {
+		return false
+	}
+	cluster := c.getCephCluster()
+	if !nodeOnboardingEnabled(cluster.Spec.Storage) {
+		return false
+	}
+	onboarded := onboardedNodes(cluster)
+	if deleted {
+		return cluster.Spec.Storage.NodeOnboarding.RemoveNodes && onboarded.Has(nodeHostname(node))
+	}
+	add, remove := onboardingAction(cluster.Spec.Storage, onboarded, node)
+	if add || remove {
+		logger.Debugf("node watcher: onboarding of node %q changed in cluster %q", node.Name, cluster.Namespace)
+	}
+	return add || remove
+}
+
 // onK8sNodeAdd is triggered when a node is added in the Kubernetes cluster
 func (c *clientCluster) onK8sNode(ctx context.Context, object runtime.Object) bool {
 	node, ok := object.(*v1.Node)
@@ -41,6 +41,8 @@ type SecretStore struct {
 	context     *clusterd.Context
 	clusterInfo *client.ClusterInfo
 	ownerInfo   *k8sutil.OwnerInfo
+	encrypt     func(keyring string) (string, error)
+	labels      map[string]string
 }
 
 // GetSecretStore returns a new SecretStore struct.
@@ -52,6 +54,20 @@ func GetSecretStore(context *clusterd.Context, clusterInfo *client.ClusterInfo,
 	}
 }
 
+// WithEncryption encrypts the keyrings stored by the SecretStore with the given function. The keyrings are stored
+// unencrypted if the function is nil.
+func (k *SecretStore) WithEncryption(encrypt func(keyring string) (string, error)) *SecretStore {
+	k.encrypt = encrypt
+	return k
+}
+
+// WithLabels sets the labels of the keyring secrets stored by the SecretStore, e.g. to select the secrets of the
+// daemons of a CR
+func (k *SecretStore) WithLabels(labels map[string]string) *SecretStore {
+	k.labels = labels
+	return k
+}
+
 func keyringSecretName(resourceName string) string {
 	return resourceName + "-keyring" // all keyrings named by suffixing keyring to the resource name
 }
@@ -80,10 +96,17 @@ func (k *SecretStore) GenerateKey(user string, access []string) (string, error)
 // CreateOrUpdate creates or updates the keyring secret for the resource with the keyring specified.
 // WARNING: Do not use "rook-ceph-admin" as the resource name; conflicts with the AdminStore.
 func (k *SecretStore) CreateOrUpdate(resourceName string, keyring string) error {
+	if k.encrypt != nil {
+		var err error
+		if keyring, err = k.encrypt(keyring); err != nil {
+			return errors.Wrapf(err, "failed to encrypt keyring of %q", resourceName)
+		}
+	}
 	secret := &v1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      keyringSecretName(resourceName),
 			Namespace: k.clusterInfo.Namespace,
+			Labels:    k.labels,
 		},
 		StringData: map[string]string{
 			keyringFileName: keyring,
@@ -116,6 +116,16 @@ func TestKeyringStore(t *testing.T) {
 	assert.NoError(t, err)
 	assertDoesNotExist("test-resource-keyring")
 	assertKeyringData("second-resource-keyring", "lkjhgfdsa")
+
+	// encrypt the keys
+	k.WithEncryption(func(keyring string) (string, error) { return "encrypted-" + keyring, nil })
+	err = k.CreateOrUpdate("second-resource", "lkjhgfdsa")
+	assert.NoError(t, err)
+	assertKeyringData("second-resource-keyring", "encrypted-lkjhgfdsa")
+	k.WithEncryption(func(keyring string) (string, error) { return "", errors.New("kms unavailable") })
+	err = k.CreateOrUpdate("second-resource", "lkjhgfdsa")
+	assert.Error(t, err)
+	assertKeyringData("second-resource-keyring", "encrypted-lkjhgfdsa")
 }
 
 func TestResourceVolumeAndMount(t *testing.T) {
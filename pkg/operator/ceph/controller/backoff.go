@@ -0,0 +1,152 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/util/exec"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	minTransientErrorRequeueDelay = 5 * time.Second
+	maxTransientErrorRequeueDelay = 5 * time.Minute
+)
+
+// transientExitCodes are the exit codes of the ceph commands that fail while the cluster is briefly
+// unhealthy, e.g. while the mons are out of quorum. The ceph tools exit with the errno of the failure,
+// and the timeout command exits with 124.
+var transientExitCodes = map[int]bool{
+	int(syscall.EINTR):        true,
+	int(syscall.EAGAIN):       true,
+	int(syscall.EBUSY):        true,
+	int(syscall.ENOTCONN):     true,
+	int(syscall.ETIMEDOUT):    true,
+	int(syscall.ECONNREFUSED): true,
+	int(syscall.ECONNRESET):   true,
+	124:                       true,
+}
+
+// transientError is an error that is expected to resolve on its own, but that cannot be recognized
+// from its cause
+type transientError struct {
+	err error
+}
+
+func (e *transientError) Error() string {
+	return e.err.Error()
+}
+
+func (e *transientError) Unwrap() error {
+	return e.err
+}
+
+// NewTransientError marks an error as transient, so that the reconcile is requeued with a backoff
+// instead of being retried right away
+func NewTransientError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &transientError{err: err}
+}
+
+// IsTransientError returns whether the error, or an error it wraps, is expected to resolve on its own:
+// a timeout, a ceph command that failed with a transient exit code, an unavailable API server, or an
+// error that was marked as transient
+func IsTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var transient *transientError
+	if errors.As(err, &transient) {
+		return true
+	}
+
+	if exec.IsTimeout(err) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	if kerrors.IsTimeout(err) || kerrors.IsServerTimeout(err) || kerrors.IsTooManyRequests(err) || kerrors.IsServiceUnavailable(err) {
+		return true
+	}
+
+	code, ok := commandExitCode(err)
+	return ok && transientExitCodes[code]
+}
+
+// commandExitCode returns the exit code of the failed command wrapped in the error, if any
+func commandExitCode(err error) (int, bool) {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if _, isStatus := err.(*kerrors.StatusError); isStatus {
+			// the exit code of a status error is its http code
+			return 0, false
+		}
+		if code, codeErr := exec.ExtractExitCode(err); codeErr == nil {
+			return code, true
+		}
+	}
+	return 0, false
+}
+
+// RequeueBackoff counts the consecutive transient failures of the reconciles of each object to requeue
+// them with an exponential backoff. The zero value is ready to use.
+type RequeueBackoff struct {
+	mutex    sync.Mutex
+	failures map[types.NamespacedName]int
+}
+
+// Result returns the result of a reconcile that failed with the given error. A transient error is
+// requeued after a delay that doubles with each consecutive failure, while the result of the other
+// errors is returned unchanged. A successful reconcile resets the delay.
+func (b *RequeueBackoff) Result(name types.NamespacedName, result reconcile.Result, err error) reconcile.Result {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if err == nil {
+		delete(b.failures, name)
+		return result
+	}
+	if !result.IsZero() || !IsTransientError(err) {
+		return result
+	}
+
+	if b.failures == nil {
+		b.failures = map[types.NamespacedName]int{}
+	}
+	delay := minTransientErrorRequeueDelay
+	for i := 0; i < b.failures[name] && delay < maxTransientErrorRequeueDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxTransientErrorRequeueDelay {
+		delay = maxTransientErrorRequeueDelay
+	}
+	b.failures[name]++
+
+	return reconcile.Result{Requeue: true, RequeueAfter: delay}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/util/exec"
+	"github.com/stretchr/testify/assert"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	kexec "k8s.io/utils/exec"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestIsTransientError(t *testing.T) {
+	assert.Nil(t, NewTransientError(nil))
+	assert.False(t, IsTransientError(errors.New("induced error")))
+
+	err := errors.Wrap(NewTransientError(errors.New("induced error")), "failed to create pools")
+	assert.True(t, IsTransientError(err))
+	assert.Equal(t, "failed to create pools: induced error", err.Error())
+
+	t.Run("ceph exit codes", func(t *testing.T) {
+		exitErr := func(code syscall.Errno) error {
+			return errors.Wrap(&kexec.CodeExitError{Err: errors.New("induced error"), Code: int(code)}, "failed to create pools")
+		}
+		assert.True(t, IsTransientError(exitErr(syscall.ETIMEDOUT)))
+		assert.True(t, IsTransientError(exitErr(syscall.EAGAIN)))
+		assert.True(t, IsTransientError(exitErr(syscall.ECONNREFUSED)))
+		assert.False(t, IsTransientError(exitErr(syscall.EINVAL)))
+		assert.False(t, IsTransientError(exitErr(syscall.ENOENT)))
+		assert.True(t, IsTransientError(errors.New("command terminated with exit code 110")))
+	})
+
+	t.Run("timeouts", func(t *testing.T) {
+		assert.True(t, IsTransientError(errors.Errorf("%s the command ceph to return", exec.TimeoutWaitingForMessage)))
+		assert.True(t, IsTransientError(fmt.Errorf("failed to get status: %w", context.DeadlineExceeded)))
+		assert.True(t, IsTransientError(kerrors.NewServerTimeout(schema.GroupResource{Resource: "pods"}, "get", 1)))
+		assert.False(t, IsTransientError(kerrors.NewNotFound(schema.GroupResource{Resource: "pods"}, "rgw")))
+	})
+}
+
+func TestRequeueBackoff(t *testing.T) {
+	var backoff RequeueBackoff
+	store := types.NamespacedName{Namespace: "rook-ceph", Name: "my-store"}
+	other := types.NamespacedName{Namespace: "rook-ceph", Name: "other-store"}
+	transientErr := NewTransientError(errors.New("induced error"))
+
+	t.Run("the delay doubles with each transient failure", func(t *testing.T) {
+		assert.Equal(t, 5*time.Second, backoff.Result(store, reconcile.Result{}, transientErr).RequeueAfter)
+		assert.Equal(t, 10*time.Second, backoff.Result(store, reconcile.Result{}, transientErr).RequeueAfter)
+		assert.Equal(t, 20*time.Second, backoff.Result(store, reconcile.Result{}, transientErr).RequeueAfter)
+		// the failures of each object are counted separately
+		assert.Equal(t, 5*time.Second, backoff.Result(other, reconcile.Result{}, transientErr).RequeueAfter)
+	})
+
+	t.Run("the delay is capped", func(t *testing.T) {
+		for i := 0; i < 20; i++ {
+			backoff.Result(store, reconcile.Result{}, transientErr)
+		}
+		assert.Equal(t, 5*time.Minute, backoff.Result(store, reconcile.Result{}, transientErr).RequeueAfter)
+	})
+
+	t.Run("other errors and explicit requeues are unchanged", func(t *testing.T) {
+		assert.Equal(t, reconcile.Result{}, backoff.Result(store, reconcile.Result{}, errors.New("invalid spec")))
+		assert.Equal(t, ImmediateRetryResult, backoff.Result(store, ImmediateRetryResult, transientErr))
+	})
+
+	t.Run("a successful reconcile resets the delay", func(t *testing.T) {
+		assert.Equal(t, reconcile.Result{}, backoff.Result(store, reconcile.Result{}, nil))
+		assert.Equal(t, reconcile.Result{Requeue: true, RequeueAfter: 5 * time.Second}, backoff.Result(store, reconcile.Result{}, transientErr))
+	})
+}
@@ -42,6 +42,11 @@ import (
 )
 
 const (
+	// the names of the values cached in the context of the controllers
+	clusterInfoCacheName   = "clusterinfo"
+	daemonVersionCacheName = "daemon-version-"
+	imageVersionCacheName  = "image-version-"
+
 	// OperatorCreds is the name of the secret
 	//nolint:gosec // since this is not leaking any hardcoded credentials, it's just the secret name
 	OperatorCreds     = "rook-ceph-operator-creds"
@@ -91,6 +96,25 @@ func LoadClusterInfo(ctx *clusterd.Context, context context.Context, namespace s
 	return CreateOrLoadClusterInfo(ctx, context, namespace, nil, cephClusterSpec)
 }
 
+// LoadCachedClusterInfo returns the cluster info of the namespace from the cache of the context, and loads
+// it only if it is not cached yet, so that the controllers do not read the mon secrets at every reconcile.
+// Each caller gets its own copy of the cluster info.
+func LoadCachedClusterInfo(ctx *clusterd.Context, context context.Context, namespace string, cephClusterSpec *cephv1.ClusterSpec) (*cephclient.ClusterInfo, error) {
+	if cached, ok := ctx.Cache.Get(namespace, clusterInfoCacheName); ok {
+		clusterInfo := cached.(*cephclient.ClusterInfo).Copy()
+		clusterInfo.Context = context
+		clusterInfo.NetworkSpec = cephClusterSpec.Network
+		return clusterInfo, nil
+	}
+
+	clusterInfo, _, _, err := LoadClusterInfo(ctx, context, namespace, cephClusterSpec)
+	if err != nil {
+		return nil, err
+	}
+	ctx.Cache.Set(namespace, clusterInfoCacheName, clusterInfo.Copy())
+	return clusterInfo, nil
+}
+
 // CreateOrLoadClusterInfo constructs or loads a clusterinfo and returns it along with the maxMonID
 func CreateOrLoadClusterInfo(clusterdContext *clusterd.Context, context context.Context, namespace string, ownerInfo *k8sutil.OwnerInfo, cephClusterSpec *cephv1.ClusterSpec) (*cephclient.ClusterInfo, int, *Mapping, error) {
 	var clusterInfo *cephclient.ClusterInfo
@@ -114,7 +138,7 @@ func CreateOrLoadClusterInfo(clusterdContext *clusterd.Context, context context.
 		}
 		clusterInfo.Context = context
 
-		err = createClusterAccessSecret(clusterdContext.Clientset, namespace, clusterInfo, ownerInfo)
+		err = createClusterAccessSecret(clusterdContext, namespace, clusterInfo, ownerInfo, cephClusterSpec)
 		if err != nil {
 			return nil, maxMonID, monMapping, err
 		}
@@ -140,6 +164,9 @@ func CreateOrLoadClusterInfo(clusterdContext *clusterd.Context, context context.
 		} else {
 			return nil, maxMonID, monMapping, errors.New("failed to find either the cluster admin key or the username")
 		}
+		if err = reconcileKeyringEncryption(clusterdContext, secrets, clusterInfo, ownerInfo, cephClusterSpec); err != nil {
+			return nil, maxMonID, monMapping, err
+		}
 		logger.Debugf("found existing monitor secrets for cluster %s", clusterInfo.Namespace)
 	}
 
@@ -340,7 +367,7 @@ func fullNameToIndex(name string) (int, error) {
 	return k8sutil.NameToIndex(name)
 }
 
-func createClusterAccessSecret(clientset kubernetes.Interface, namespace string, clusterInfo *cephclient.ClusterInfo, ownerInfo *k8sutil.OwnerInfo) error {
+func createClusterAccessSecret(clusterdContext *clusterd.Context, namespace string, clusterInfo *cephclient.ClusterInfo, ownerInfo *k8sutil.OwnerInfo, cephClusterSpec *cephv1.ClusterSpec) error {
 	logger.Infof("creating mon secrets for a new cluster")
 	var err error
 
@@ -351,6 +378,12 @@ func createClusterAccessSecret(clientset kubernetes.Interface, namespace string,
 		CephUsernameKey:   []byte(clusterInfo.CephCred.Username),
 		CephUserSecretKey: []byte(clusterInfo.CephCred.Secret),
 	}
+	if cephClusterSpec.Security.KeyringEncryption.IsEnabled() {
+		clusterInfo.OwnerInfo = ownerInfo
+		if err = encryptClusterKeys(clusterdContext, secrets, clusterInfo, cephClusterSpec); err != nil {
+			return err
+		}
+	}
 	secret := &v1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:       AppName,
@@ -364,7 +397,7 @@ func createClusterAccessSecret(clientset kubernetes.Interface, namespace string,
 	if err != nil {
 		return errors.Wrapf(err, "failed to set owner reference to mon secret %q", secret.Name)
 	}
-	if _, err = clientset.CoreV1().Secrets(namespace).Create(clusterInfo.Context, secret, metav1.CreateOptions{}); err != nil {
+	if _, err = clusterdContext.Clientset.CoreV1().Secrets(namespace).Create(clusterInfo.Context, secret, metav1.CreateOptions{}); err != nil {
 		return errors.Wrap(err, "failed to save mon secrets")
 	}
 
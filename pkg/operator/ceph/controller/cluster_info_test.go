@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/clusterd"
@@ -29,6 +30,7 @@ import (
 	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -109,3 +111,42 @@ func TestCreateClusterSecrets(t *testing.T) {
 	assert.Equal(t, "testid", info.CephCred.Username)
 	assert.Equal(t, "testkey", info.CephCred.Secret)
 }
+
+func TestLoadCachedClusterInfo(t *testing.T) {
+	ctx := context.TODO()
+	clientset := test.New(t, 1)
+	context := &clusterd.Context{
+		Clientset: clientset,
+		Cache:     clusterd.NewCache(time.Minute),
+	}
+	namespace := "ns"
+	cephClusterSpec := &cephv1.ClusterSpec{}
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-mon", Namespace: namespace},
+		Data: map[string][]byte{
+			fsidSecretNameKey: []byte("fsid"),
+			MonSecretNameKey:  []byte("monsecret"),
+			CephUsernameKey:   []byte("client.admin"),
+			CephUserSecretKey: []byte("adminsecret"),
+		},
+	}
+	_, err := clientset.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	info, err := LoadCachedClusterInfo(context, ctx, namespace, cephClusterSpec)
+	assert.NoError(t, err)
+	assert.Equal(t, "adminsecret", info.CephCred.Secret)
+	info.Monitors["a"] = &cephclient.MonInfo{Name: "a"}
+
+	// the cluster info is loaded from the cache, which is not modified by the callers
+	assert.NoError(t, clientset.CoreV1().Secrets(namespace).Delete(ctx, secret.Name, metav1.DeleteOptions{}))
+	info, err = LoadCachedClusterInfo(context, ctx, namespace, cephClusterSpec)
+	assert.NoError(t, err)
+	assert.Equal(t, "adminsecret", info.CephCred.Secret)
+	assert.Empty(t, info.Monitors)
+
+	// the cluster info is loaded again once invalidated
+	context.Cache.Invalidate(namespace)
+	_, err = LoadCachedClusterInfo(context, ctx, namespace, cephClusterSpec)
+	assert.Error(t, err)
+}
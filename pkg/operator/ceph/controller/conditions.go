@@ -59,7 +59,8 @@ func UpdateClusterCondition(c *clusterd.Context, cluster *cephv1.CephCluster, na
 			condition.Reason == cephv1.ClusterCreatedReason ||
 			condition.Reason == cephv1.ClusterConnectedReason ||
 			condition.Type == cephv1.ConditionDeleting ||
-			condition.Type == cephv1.ConditionDeletionIsBlocked {
+			condition.Type == cephv1.ConditionDeletionIsBlocked ||
+			condition.Type == cephv1.ConditionDegraded {
 			if conditionType != condition.Type {
 				conditions = append(conditions, condition)
 				continue
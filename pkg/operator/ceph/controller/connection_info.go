@@ -0,0 +1,136 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+// The connection info configmap is the documented contract for the third-party consumers of a cluster. The keys of a
+// version are never renamed nor change their format, a new version is published for breaking changes.
+const (
+	// ConnectionInfoConfigMapName is the name of the connection info configmap in the namespace of the cluster
+	ConnectionInfoConfigMapName = "rook-ceph-connection-info"
+	// ConnectionInfoVersion is the version of the format of the connection info
+	ConnectionInfoVersion = "v1"
+
+	// ConnectionInfoVersionKey is the key of the version of the format of the connection info
+	ConnectionInfoVersionKey = "version"
+	// ConnectionInfoFSIDKey is the key of the fsid of the cluster
+	ConnectionInfoFSIDKey = "fsid"
+	// ConnectionInfoMonEndpointsKey is the key of the comma-separated mon endpoints, e.g. "10.0.0.1:6789,10.0.0.2:6789"
+	ConnectionInfoMonEndpointsKey = "monEndpoints"
+	// ConnectionInfoCephVersionKey is the key of the ceph version of the mons, e.g. "17.2.6-0"
+	ConnectionInfoCephVersionKey = "cephVersion"
+	// ConnectionInfoDashboardURLKey is the key of the in-cluster URL of the dashboard, absent if the dashboard is disabled
+	ConnectionInfoDashboardURLKey = "dashboardURL"
+	// ConnectionInfoRGWEndpointsKey is the key of the JSON map of the object store names to their in-cluster endpoint
+	ConnectionInfoRGWEndpointsKey = "rgwEndpoints"
+)
+
+// UpdateConnectionInfo applies the update to the data of the connection info configmap of the cluster, which is created
+// if needed. The update is retried on conflicts, so that the concurrent updates of the controllers are never lost and
+// the consumers always read a consistent configmap.
+func UpdateConnectionInfo(ctx context.Context, clientset kubernetes.Interface, namespace string, ownerInfo *k8sutil.OwnerInfo, update func(data map[string]string) error) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMap, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, ConnectionInfoConfigMapName, metav1.GetOptions{})
+		if err != nil {
+			if !kerrors.IsNotFound(err) {
+				return errors.Wrapf(err, "failed to get configmap %q", ConnectionInfoConfigMapName)
+			}
+			configMap = &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      ConnectionInfoConfigMapName,
+					Namespace: namespace,
+				},
+				Data: map[string]string{},
+			}
+			if err := ownerInfo.SetControllerReference(configMap); err != nil {
+				return errors.Wrapf(err, "failed to set owner reference to configmap %q", ConnectionInfoConfigMapName)
+			}
+			configMap.Data[ConnectionInfoVersionKey] = ConnectionInfoVersion
+			if err := update(configMap.Data); err != nil {
+				return err
+			}
+			_, err = clientset.CoreV1().ConfigMaps(namespace).Create(ctx, configMap, metav1.CreateOptions{})
+			if kerrors.IsAlreadyExists(err) {
+				// retry the update of the configmap created concurrently
+				return kerrors.NewConflict(v1.Resource("configmaps"), ConnectionInfoConfigMapName, err)
+			}
+			return err
+		}
+
+		if configMap.Data == nil {
+			configMap.Data = map[string]string{}
+		}
+		data := make(map[string]string, len(configMap.Data))
+		for key, value := range configMap.Data {
+			data[key] = value
+		}
+		data[ConnectionInfoVersionKey] = ConnectionInfoVersion
+		if err := update(data); err != nil {
+			return err
+		}
+		if reflect.DeepEqual(data, configMap.Data) {
+			return nil
+		}
+		configMap.Data = data
+		_, err = clientset.CoreV1().ConfigMaps(namespace).Update(ctx, configMap, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to update configmap %q", ConnectionInfoConfigMapName)
+	}
+	return nil
+}
+
+// SetConnectionInfoRGWEndpoint sets the endpoint of an object store in the connection info data. The object store is
+// removed if the endpoint is empty.
+func SetConnectionInfoRGWEndpoint(data map[string]string, store, endpoint string) error {
+	endpoints := map[string]string{}
+	if raw, ok := data[ConnectionInfoRGWEndpointsKey]; ok && raw != "" {
+		if err := json.Unmarshal([]byte(raw), &endpoints); err != nil {
+			return errors.Wrapf(err, "failed to parse %q", ConnectionInfoRGWEndpointsKey)
+		}
+	}
+	if endpoint == "" {
+		delete(endpoints, store)
+	} else {
+		endpoints[store] = endpoint
+	}
+	if len(endpoints) == 0 {
+		delete(data, ConnectionInfoRGWEndpointsKey)
+		return nil
+	}
+	// the keys of the maps are sorted by json.Marshal, the value is stable
+	raw, err := json.Marshal(endpoints)
+	if err != nil {
+		return errors.Wrapf(err, "failed to serialize %q", ConnectionInfoRGWEndpointsKey)
+	}
+	data[ConnectionInfoRGWEndpointsKey] = string(raw)
+	return nil
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestUpdateConnectionInfo(t *testing.T) {
+	ctx := context.TODO()
+	clientset := test.New(t, 1)
+	namespace := "ns"
+	ownerInfo := cephclient.NewMinimumOwnerInfoWithOwnerRef()
+
+	getData := func() map[string]string {
+		configMap, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, ConnectionInfoConfigMapName, metav1.GetOptions{})
+		require.NoError(t, err)
+		return configMap.Data
+	}
+
+	t.Run("created", func(t *testing.T) {
+		err := UpdateConnectionInfo(ctx, clientset, namespace, ownerInfo, func(data map[string]string) error {
+			data[ConnectionInfoFSIDKey] = "fsid"
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{ConnectionInfoVersionKey: ConnectionInfoVersion, ConnectionInfoFSIDKey: "fsid"}, getData())
+
+		configMap, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, ConnectionInfoConfigMapName, metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Len(t, configMap.OwnerReferences, 1)
+	})
+
+	t.Run("updated", func(t *testing.T) {
+		err := UpdateConnectionInfo(ctx, clientset, namespace, ownerInfo, func(data map[string]string) error {
+			data[ConnectionInfoMonEndpointsKey] = "10.0.0.1:6789,10.0.0.2:6789"
+			return nil
+		})
+		assert.NoError(t, err)
+		data := getData()
+		// the keys set by the other controllers are kept
+		assert.Equal(t, "fsid", data[ConnectionInfoFSIDKey])
+		assert.Equal(t, "10.0.0.1:6789,10.0.0.2:6789", data[ConnectionInfoMonEndpointsKey])
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		before, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, ConnectionInfoConfigMapName, metav1.GetOptions{})
+		require.NoError(t, err)
+		before.ResourceVersion = "1"
+		_, err = clientset.CoreV1().ConfigMaps(namespace).Update(ctx, before, metav1.UpdateOptions{})
+		require.NoError(t, err)
+
+		err = UpdateConnectionInfo(ctx, clientset, namespace, ownerInfo, func(data map[string]string) error {
+			data[ConnectionInfoFSIDKey] = "fsid"
+			return nil
+		})
+		assert.NoError(t, err)
+		after, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, ConnectionInfoConfigMapName, metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, "1", after.ResourceVersion)
+	})
+
+	t.Run("update error", func(t *testing.T) {
+		err := UpdateConnectionInfo(ctx, clientset, namespace, ownerInfo, func(data map[string]string) error {
+			return assert.AnError
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestSetConnectionInfoRGWEndpoint(t *testing.T) {
+	data := map[string]string{}
+
+	assert.NoError(t, SetConnectionInfoRGWEndpoint(data, "store-b", "http://rook-ceph-rgw-store-b.ns.svc:80"))
+	assert.NoError(t, SetConnectionInfoRGWEndpoint(data, "store-a", "http://rook-ceph-rgw-store-a.ns.svc:80"))
+	assert.Equal(t, `{"store-a":"http://rook-ceph-rgw-store-a.ns.svc:80","store-b":"http://rook-ceph-rgw-store-b.ns.svc:80"}`, data[ConnectionInfoRGWEndpointsKey])
+
+	assert.NoError(t, SetConnectionInfoRGWEndpoint(data, "store-b", ""))
+	assert.Equal(t, `{"store-a":"http://rook-ceph-rgw-store-a.ns.svc:80"}`, data[ConnectionInfoRGWEndpointsKey])
+
+	assert.NoError(t, SetConnectionInfoRGWEndpoint(data, "store-a", ""))
+	assert.NotContains(t, data, ConnectionInfoRGWEndpointsKey)
+
+	data[ConnectionInfoRGWEndpointsKey] = "invalid"
+	assert.Error(t, SetConnectionInfoRGWEndpoint(data, "store-a", ""))
+}
@@ -27,6 +27,7 @@ import (
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	"github.com/rook/rook/pkg/util/exec"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -40,6 +41,9 @@ type OperatorConfig struct {
 	ServiceAccount    string
 	NamespaceToWatch  string
 	Parameters        map[string]string
+	// CanWatchSecrets is false when the operator is not allowed to watch the secrets of the watched namespace, in
+	// which case the controllers do not watch the secrets they own
+	CanWatchSecrets bool
 }
 
 // ClusterHealth is passed to the various monitoring go routines to stop them when the context is cancelled
@@ -160,9 +164,22 @@ func IsReadyToReconcile(ctx context.Context, c client.Client, namespacedName typ
 		return cephCluster, false, cephClusterExists, WaitForRequeueIfCephClusterNotReady
 	}
 
+	// The daemons of the cluster are deleted with its namespace, so the ceph commands to clean up the resources of the
+	// custom resources would never succeed. Treat the cluster as if it does not exist so that they are deleted.
+	if !cephCluster.DeletionTimestamp.IsZero() && IsNamespaceTerminating(ctx, c, namespacedName.Namespace) {
+		logger.Infof("%q: namespace %q of the CephCluster is terminating, allowing %q to be deleted", controllerName, namespacedName.Namespace, namespacedName)
+		return cephCluster, false, cephClusterExists, WaitForRequeueIfCephClusterNotReady
+	}
+
 	cephClusterExists = true
 	logger.Debugf("%q: CephCluster resource %q found in namespace %q", controllerName, cephCluster.Name, namespacedName.Namespace)
 
+	// The reconcile of the resources of the cluster is paused with the reconcile of the cluster
+	if IsDoNotReconcile(cephCluster.GetLabels()) {
+		logger.Infof("%q: skipping reconcile of %q since CephCluster %q is labeled with %q", controllerName, namespacedName, cephCluster.Name, DoNotReconcileLabelName)
+		return cephCluster, false, cephClusterExists, WaitForRequeueIfCephClusterNotReady
+	}
+
 	// read the CR status of the cluster
 	if cephCluster.Status.CephStatus != nil {
 		var operatorDeploymentOk = cephCluster.Status.CephStatus.Health == "HEALTH_OK" || cephCluster.Status.CephStatus.Health == "HEALTH_WARN"
@@ -185,6 +202,17 @@ func IsReadyToReconcile(ctx context.Context, c client.Client, namespacedName typ
 	return cephCluster, false, cephClusterExists, WaitForRequeueIfCephClusterNotReady
 }
 
+// IsNamespaceTerminating returns whether the namespace is being deleted. The namespace is assumed not to be
+// terminating if it cannot be read.
+func IsNamespaceTerminating(ctx context.Context, c client.Client, namespace string) bool {
+	ns := &corev1.Namespace{}
+	if err := c.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		logger.Debugf("failed to get namespace %q to check if it is terminating. %v", namespace, err)
+		return false
+	}
+	return !ns.DeletionTimestamp.IsZero() || ns.Status.Phase == corev1.NamespaceTerminating
+}
+
 // ClusterOwnerRef represents the owner reference of the CephCluster CR
 func ClusterOwnerRef(clusterName, clusterID string) metav1.OwnerReference {
 	blockOwner := true
@@ -25,6 +25,7 @@ import (
 	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
 	"github.com/rook/rook/pkg/util/exec"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -189,4 +190,64 @@ func TestIsReadyToReconcile(t *testing.T) {
 		assert.False(t, ready)
 		assert.False(t, clusterExists)
 	})
+
+	t.Run("deleted cephcluster in a terminating namespace", func(t *testing.T) {
+		assert.NoError(t, corev1.AddToScheme(scheme))
+		cephCluster := &cephv1.CephCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              clusterName.Name,
+				Namespace:         clusterName.Namespace,
+				DeletionTimestamp: &metav1.Time{Time: time.Now()},
+			},
+		}
+		namespace := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: clusterName.Namespace},
+			Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceTerminating},
+		}
+		objects := []runtime.Object{cephCluster, namespace}
+		client := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objects...).Build()
+		c, ready, clusterExists, _ := IsReadyToReconcile(ctx.TODO(), client, clusterName, controllerName)
+		assert.NotNil(t, c)
+		assert.False(t, ready)
+		assert.False(t, clusterExists)
+	})
+
+	t.Run("healthy cephcluster with paused reconcile", func(t *testing.T) {
+		cephCluster := &cephv1.CephCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      clusterName.Name,
+				Namespace: clusterName.Namespace,
+				Labels:    map[string]string{DoNotReconcileLabelName: "true"},
+			},
+			Status: cephv1.ClusterStatus{
+				CephStatus: &cephv1.CephStatus{Health: "HEALTH_OK"},
+			},
+		}
+		objects := []runtime.Object{cephCluster}
+		client := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objects...).Build()
+		c, ready, clusterExists, reconcileResult := IsReadyToReconcile(ctx.TODO(), client, clusterName, controllerName)
+		assert.NotNil(t, c)
+		assert.False(t, ready)
+		assert.True(t, clusterExists)
+		assert.Equal(t, WaitForRequeueIfCephClusterNotReady, reconcileResult)
+
+		// resumed
+		cephCluster.Labels = nil
+		client = fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objects...).Build()
+		_, ready, _, _ = IsReadyToReconcile(ctx.TODO(), client, clusterName, controllerName)
+		assert.True(t, ready)
+	})
+}
+
+func TestIsNamespaceTerminating(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+	active := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "active"}, Status: corev1.NamespaceStatus{Phase: corev1.NamespaceActive}}
+	terminating := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "terminating"}, Status: corev1.NamespaceStatus{Phase: corev1.NamespaceTerminating}}
+	client := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(active, terminating).Build()
+
+	assert.False(t, IsNamespaceTerminating(ctx.TODO(), client, "active"))
+	assert.True(t, IsNamespaceTerminating(ctx.TODO(), client, "terminating"))
+	// a namespace that cannot be read is assumed not to be terminating
+	assert.False(t, IsNamespaceTerminating(ctx.TODO(), client, "missing"))
 }
@@ -23,6 +23,7 @@ import (
 
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -96,6 +97,7 @@ func RemoveFinalizerWithName(ctx context.Context, client client.Client, obj clie
 		if err := client.Update(ctx, obj); err != nil {
 			return errors.Wrapf(err, "failed to remove finalizer %q on %q", finalizerName, accessor.GetName())
 		}
+		reporting.DeleteResourcePhase(obj)
 	}
 
 	return nil
@@ -34,6 +34,16 @@ import (
 // So we reconcile Kind A instead of Kind B
 // For instance, we watch for CephCluster CR changes but want to reconcile CephFilesystem based on a Spec change
 func ObjectToCRMapper(ctx context.Context, c client.Client, ro runtime.Object, scheme *runtime.Scheme) (handler.MapFunc, error) {
+	return objectToCRMapper(ctx, c, ro, scheme, false)
+}
+
+// ObjectToCRInNamespaceMapper is like ObjectToCRMapper but only returns the objects in the namespace of the watched
+// object. For instance, we watch for CephCluster CR changes but want to reconcile the CephObjectStores of that cluster
+func ObjectToCRInNamespaceMapper(ctx context.Context, c client.Client, ro runtime.Object, scheme *runtime.Scheme) (handler.MapFunc, error) {
+	return objectToCRMapper(ctx, c, ro, scheme, true)
+}
+
+func objectToCRMapper(ctx context.Context, c client.Client, ro runtime.Object, scheme *runtime.Scheme, inNamespace bool) (handler.MapFunc, error) {
 	if _, ok := ro.(metav1.ListInterface); !ok {
 		return nil, errors.Errorf("expected a metav1.ListInterface, got %T instead", ro)
 	}
@@ -47,7 +57,11 @@ func ObjectToCRMapper(ctx context.Context, c client.Client, ro runtime.Object, s
 	return handler.MapFunc(func(o client.Object) []ctrl.Request {
 		list := &unstructured.UnstructuredList{}
 		list.SetGroupVersionKind(gvk)
-		err := c.List(ctx, list)
+		opts := []client.ListOption{}
+		if inNamespace {
+			opts = append(opts, client.InNamespace(o.GetNamespace()))
+		}
+		err := c.List(ctx, list, opts...)
 		if err != nil {
 			return nil
 		}
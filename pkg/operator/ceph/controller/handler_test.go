@@ -66,3 +66,29 @@ func TestObjectToCRMapper(t *testing.T) {
 	assert.NoError(t, err)
 	assert.ElementsMatch(t, fakeRequest, handlerFunc(fs))
 }
+
+func TestObjectToCRInNamespaceMapper(t *testing.T) {
+	newFilesystem := func(name, namespace string) *cephv1.CephFilesystem {
+		return &cephv1.CephFilesystem{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	}
+	objects := []runtime.Object{
+		newFilesystem("fs-a", "rook-ceph"),
+		newFilesystem("fs-b", "rook-ceph"),
+		newFilesystem("fs-c", "other"),
+	}
+
+	s := runtime.NewScheme()
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephFilesystem{}, &cephv1.CephFilesystemList{}, &cephv1.CephCluster{})
+	cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(objects...).Build()
+
+	handlerFunc, err := ObjectToCRInNamespaceMapper(context.TODO(), cl, &cephv1.CephFilesystemList{}, s)
+	assert.NoError(t, err)
+	cluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "rook-ceph"}}
+	assert.ElementsMatch(t, []ctrl.Request{
+		{NamespacedName: client.ObjectKey{Name: "fs-a", Namespace: "rook-ceph"}},
+		{NamespacedName: client.ObjectKey{Name: "fs-b", Namespace: "rook-ceph"}},
+	}, handlerFunc(cluster))
+
+	_, err = ObjectToCRInNamespaceMapper(context.TODO(), cl, &cephv1.CephFilesystem{}, s)
+	assert.Error(t, err)
+}
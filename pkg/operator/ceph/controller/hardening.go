@@ -0,0 +1,141 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strings"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/config"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+)
+
+const (
+	hardenedTmpVolumeName = "ceph-daemon-tmp"
+	hardenedTmpDir        = "/tmp"
+)
+
+var (
+	// the capabilities of the hardened daemons started as root, to switch to the ceph user and to bind the ports
+	// below 1024
+	rootDaemonCapabilities = []v1.Capability{"CHOWN", "DAC_OVERRIDE", "FOWNER", "SETGID", "SETUID", "NET_BIND_SERVICE"}
+	// the capabilities of the init containers changing the owner of the daemon dirs
+	chownCapabilities = []v1.Capability{"CHOWN", "DAC_OVERRIDE", "FOWNER"}
+)
+
+// IsDaemonHardeningEnabled returns whether the stateless daemons (rgw, mgr and mds) run with a hardened security
+// context. The hardening is skipped when the host paths require privileged containers.
+func IsDaemonHardeningEnabled(spec *cephv1.ClusterSpec) bool {
+	return !spec.Security.DaemonHardening.Disabled && !HostPathRequiresPrivileged()
+}
+
+// DaemonSecurityContext returns the security context of a stateless daemon container. The hardened daemons drop all
+// capabilities and run as the ceph user with a read-only root filesystem, unless overridden in the cluster spec.
+// The daemons needing root to start, e.g. to bind a port below 1024 with host networking, keep the minimal
+// capabilities to switch to the ceph user.
+func DaemonSecurityContext(spec *cephv1.ClusterSpec, runAsRoot bool) *v1.SecurityContext {
+	context := PodSecurityContext()
+	if !IsDaemonHardeningEnabled(spec) {
+		return context
+	}
+	hardening := spec.Security.DaemonHardening
+
+	context.AllowPrivilegeEscalation = pointer.Bool(false)
+	context.ReadOnlyRootFilesystem = pointer.Bool(!hardening.AllowWritableRootFilesystem)
+	capabilities := []v1.Capability{}
+	if runAsRoot || hardening.RunAsRoot {
+		capabilities = append(capabilities, rootDaemonCapabilities...)
+	} else {
+		context.RunAsUser = pointer.Int64(CephUserID)
+		context.RunAsGroup = pointer.Int64(CephUserID)
+		context.RunAsNonRoot = pointer.Bool(true)
+	}
+	capabilities = append(capabilities, hardening.AddCapabilities...)
+	context.Capabilities = &v1.Capabilities{Drop: []v1.Capability{"ALL"}, Add: capabilities}
+	return context
+}
+
+// ChownSecurityContext returns the security context of the init container changing the owner of the dirs of a
+// stateless daemon. The init container runs as root, with only the capabilities to change the owner of the dirs
+// when the daemons are hardened.
+func ChownSecurityContext(spec *cephv1.ClusterSpec) *v1.SecurityContext {
+	context := PodSecurityContext()
+	if !IsDaemonHardeningEnabled(spec) {
+		return context
+	}
+	context.AllowPrivilegeEscalation = pointer.Bool(false)
+	context.ReadOnlyRootFilesystem = pointer.Bool(!spec.Security.DaemonHardening.AllowWritableRootFilesystem)
+	context.Capabilities = &v1.Capabilities{Drop: []v1.Capability{"ALL"}, Add: chownCapabilities}
+	return context
+}
+
+// HardenedDaemonFlags removes the flags switching to the ceph user from the daemon flags when the daemon already
+// runs as the ceph user, since the daemon would fail to switch user without the capabilities.
+func HardenedDaemonFlags(flags []string, context *v1.SecurityContext) []string {
+	if context.RunAsUser == nil || *context.RunAsUser == 0 {
+		return flags
+	}
+	filtered := make([]string, 0, len(flags))
+	for _, flag := range flags {
+		if strings.HasPrefix(flag, config.NewFlag("setuser", "")) || strings.HasPrefix(flag, config.NewFlag("setgroup", "")) {
+			continue
+		}
+		filtered = append(filtered, flag)
+	}
+	return filtered
+}
+
+// HardenedDaemonVolumes returns the volumes of the dirs written by the hardened daemons that are not already provided
+// by the DaemonVolumes, since the root filesystem of the hardened daemons is read-only.
+func HardenedDaemonVolumes(spec *cephv1.ClusterSpec, dataPaths *config.DataPathMap) []v1.Volume {
+	if !hardenedRootFilesystem(spec) {
+		return []v1.Volume{}
+	}
+	emptyDir := v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}
+	vols := []v1.Volume{{Name: hardenedTmpVolumeName, VolumeSource: emptyDir}}
+	if spec.DataDirHostPath == "" {
+		vols = append(vols, v1.Volume{Name: "ceph-daemons-sock-dir", VolumeSource: emptyDir})
+	}
+	if dataPaths.HostLogAndCrashDir == "" {
+		vols = append(vols,
+			v1.Volume{Name: logVolumeName, VolumeSource: emptyDir},
+			v1.Volume{Name: crashVolumeName, VolumeSource: emptyDir},
+		)
+	}
+	return vols
+}
+
+// HardenedDaemonVolumeMounts returns the volume mounts which correspond to the HardenedDaemonVolumes. They are shared
+// by the daemon and chown init containers.
+func HardenedDaemonVolumeMounts(spec *cephv1.ClusterSpec, dataPaths *config.DataPathMap) []v1.VolumeMount {
+	if !hardenedRootFilesystem(spec) {
+		return []v1.VolumeMount{}
+	}
+	mounts := []v1.VolumeMount{{Name: hardenedTmpVolumeName, MountPath: hardenedTmpDir}}
+	if spec.DataDirHostPath == "" {
+		mounts = append(mounts, v1.VolumeMount{Name: "ceph-daemons-sock-dir", MountPath: daemonSocketDir})
+	}
+	if dataPaths.HostLogAndCrashDir == "" {
+		mounts = append(mounts, StoredLogAndCrashVolumeMount(config.VarLogCephDir, config.VarLibCephCrashDir)...)
+	}
+	return mounts
+}
+
+func hardenedRootFilesystem(spec *cephv1.ClusterSpec) bool {
+	return IsDaemonHardeningEnabled(spec) && !spec.Security.DaemonHardening.AllowWritableRootFilesystem
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/config"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestDaemonSecurityContext(t *testing.T) {
+	spec := &cephv1.ClusterSpec{}
+
+	t.Run("hardened", func(t *testing.T) {
+		context := DaemonSecurityContext(spec, false)
+		assert.Equal(t, int64(CephUserID), *context.RunAsUser)
+		assert.Equal(t, int64(CephUserID), *context.RunAsGroup)
+		assert.True(t, *context.RunAsNonRoot)
+		assert.True(t, *context.ReadOnlyRootFilesystem)
+		assert.False(t, *context.AllowPrivilegeEscalation)
+		assert.Equal(t, []v1.Capability{"ALL"}, context.Capabilities.Drop)
+		assert.Empty(t, context.Capabilities.Add)
+	})
+
+	t.Run("started as root", func(t *testing.T) {
+		context := DaemonSecurityContext(spec, true)
+		assert.Nil(t, context.RunAsUser)
+		assert.Nil(t, context.RunAsNonRoot)
+		assert.True(t, *context.ReadOnlyRootFilesystem)
+		assert.Contains(t, context.Capabilities.Add, v1.Capability("SETUID"))
+		assert.Contains(t, context.Capabilities.Add, v1.Capability("NET_BIND_SERVICE"))
+	})
+
+	t.Run("escape hatches", func(t *testing.T) {
+		spec := &cephv1.ClusterSpec{Security: cephv1.SecuritySpec{DaemonHardening: cephv1.DaemonHardeningSpec{
+			AllowWritableRootFilesystem: true,
+			RunAsRoot:                   true,
+			AddCapabilities:             []v1.Capability{"SYS_PTRACE"},
+		}}}
+		context := DaemonSecurityContext(spec, false)
+		assert.Nil(t, context.RunAsUser)
+		assert.False(t, *context.ReadOnlyRootFilesystem)
+		assert.Equal(t, append(rootDaemonCapabilities, "SYS_PTRACE"), context.Capabilities.Add)
+		// the capabilities of the daemons started as root are not modified
+		assert.Len(t, rootDaemonCapabilities, 6)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		spec := &cephv1.ClusterSpec{Security: cephv1.SecuritySpec{DaemonHardening: cephv1.DaemonHardeningSpec{Disabled: true}}}
+		assert.Equal(t, PodSecurityContext(), DaemonSecurityContext(spec, false))
+		assert.Equal(t, PodSecurityContext(), ChownSecurityContext(spec))
+	})
+
+	t.Run("privileged host paths", func(t *testing.T) {
+		t.Setenv("ROOK_HOSTPATH_REQUIRES_PRIVILEGED", "true")
+		assert.Equal(t, PodSecurityContext(), DaemonSecurityContext(spec, false))
+		assert.True(t, *DaemonSecurityContext(spec, false).Privileged)
+	})
+}
+
+func TestChownSecurityContext(t *testing.T) {
+	context := ChownSecurityContext(&cephv1.ClusterSpec{})
+	assert.Nil(t, context.RunAsUser)
+	assert.True(t, *context.ReadOnlyRootFilesystem)
+	assert.Equal(t, []v1.Capability{"ALL"}, context.Capabilities.Drop)
+	assert.Equal(t, chownCapabilities, context.Capabilities.Add)
+}
+
+func TestHardenedDaemonFlags(t *testing.T) {
+	flags := []string{"--fsid=myfsid", "--setuser=ceph", "--setgroup=ceph", "--foreground"}
+	assert.Equal(t, []string{"--fsid=myfsid", "--foreground"}, HardenedDaemonFlags(flags, DaemonSecurityContext(&cephv1.ClusterSpec{}, false)))
+	assert.Equal(t, flags, HardenedDaemonFlags(flags, DaemonSecurityContext(&cephv1.ClusterSpec{}, true)))
+	assert.Equal(t, flags, HardenedDaemonFlags(flags, PodSecurityContext()))
+}
+
+func TestHardenedDaemonVolumes(t *testing.T) {
+	t.Run("host paths", func(t *testing.T) {
+		spec := &cephv1.ClusterSpec{DataDirHostPath: "/var/lib/rook"}
+		dataPaths := config.NewStatelessDaemonDataPathMap(config.RgwType, "my-store", "rook-ceph", spec.DataDirHostPath)
+		vols := HardenedDaemonVolumes(spec, dataPaths)
+		mounts := HardenedDaemonVolumeMounts(spec, dataPaths)
+		assert.Len(t, vols, 1)
+		assert.Equal(t, []v1.VolumeMount{{Name: hardenedTmpVolumeName, MountPath: "/tmp"}}, mounts)
+
+		// the hardened volumes do not conflict with the daemon volumes
+		all := append(DaemonVolumes(dataPaths, "my-keyring", spec.DataDirHostPath), vols...)
+		names := map[string]bool{}
+		for _, vol := range all {
+			assert.False(t, names[vol.Name], vol.Name)
+			names[vol.Name] = true
+		}
+	})
+
+	t.Run("no host paths", func(t *testing.T) {
+		spec := &cephv1.ClusterSpec{}
+		dataPaths := &config.DataPathMap{ContainerDataDir: "/var/lib/ceph/rgw/ceph-my-store"}
+		vols := HardenedDaemonVolumes(spec, dataPaths)
+		mounts := HardenedDaemonVolumeMounts(spec, dataPaths)
+		assert.Len(t, vols, 4)
+		assert.Len(t, mounts, 4)
+		for _, vol := range vols {
+			assert.NotNil(t, vol.EmptyDir, vol.Name)
+		}
+		paths := []string{}
+		for _, mount := range mounts {
+			paths = append(paths, mount.MountPath)
+		}
+		assert.ElementsMatch(t, []string{"/tmp", daemonSocketDir, config.VarLogCephDir, config.VarLibCephCrashDir}, paths)
+	})
+
+	t.Run("writable root filesystem", func(t *testing.T) {
+		spec := &cephv1.ClusterSpec{Security: cephv1.SecuritySpec{DaemonHardening: cephv1.DaemonHardeningSpec{AllowWritableRootFilesystem: true}}}
+		dataPaths := config.NewStatelessDaemonDataPathMap(config.RgwType, "my-store", "rook-ceph", "")
+		assert.Empty(t, HardenedDaemonVolumes(spec, dataPaths))
+		assert.Empty(t, HardenedDaemonVolumeMounts(spec, dataPaths))
+	})
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"path"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/daemon/ceph/osd/kms"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	keyringDecryptionContainerName = "decrypt-keyrings"
+	encryptedKeyringVolumePrefix   = "encrypted-"
+	encryptedKeyringDir            = "/etc/rook/encrypted-keyrings"
+	decryptedKeyringDir            = "/etc/rook/decrypted-keyrings"
+)
+
+// the keys of the mon secret encrypted with the KMS when the keyring encryption is enabled
+var encryptedClusterKeys = []string{MonSecretNameKey, CephUserSecretKey}
+
+// newKeyringEncryptionConfig is overridden in the unit tests
+var newKeyringEncryptionConfig = func(clusterdContext *clusterd.Context, clusterInfo *cephclient.ClusterInfo, cephClusterSpec *cephv1.ClusterSpec) (keyringEncryptionKMS, error) {
+	// the connection details are validated on a copy since the validation populates the token details in the spec
+	kmsSpec := cephClusterSpec.Security.KeyManagementService.DeepCopy()
+	if err := kms.ValidateConnectionDetails(clusterInfo.Context, clusterdContext, kmsSpec, clusterInfo.Namespace); err != nil {
+		return nil, errors.Wrap(err, "failed to validate kms connection details of the keyring encryption")
+	}
+	return kms.NewConfig(clusterdContext, &cephv1.ClusterSpec{Security: cephv1.SecuritySpec{KeyManagementService: *kmsSpec}}, clusterInfo), nil
+}
+
+type keyringEncryptionKMS interface {
+	GetKeyringEncryptionKey() (string, error)
+	GetOrCreateKeyringEncryptionKey() (string, error)
+}
+
+// ValidateKeyringEncryption validates that the KMS storing the keyring encryption key is configured. The keys cannot
+// be encrypted with a key stored in the Kubernetes secrets.
+func ValidateKeyringEncryption(spec *cephv1.ClusterSpec) error {
+	if !spec.Security.KeyringEncryption.IsEnabled() {
+		return nil
+	}
+	if spec.External.Enable {
+		return errors.New("keyring encryption is not supported for external clusters")
+	}
+	// the daemons fetch the key from the kms without accessing the kubernetes api, which the kmip kms requires
+	kmsSpec := spec.Security.KeyManagementService
+	if !kmsSpec.IsVaultKMS() && !kmsSpec.IsIBMKeyProtectKMS() {
+		return errors.New("keyring encryption requires the vault or ibm key protect kms in the security settings")
+	}
+	return nil
+}
+
+// NewKeyringEncrypter returns the function encrypting the keyrings stored in the keyring secrets with the keyring
+// encryption key. The function is nil when the keyring encryption is disabled.
+func NewKeyringEncrypter(clusterdContext *clusterd.Context, clusterInfo *cephclient.ClusterInfo, cephClusterSpec *cephv1.ClusterSpec) (func(string) (string, error), error) {
+	if !cephClusterSpec.Security.KeyringEncryption.IsEnabled() {
+		return nil, nil
+	}
+	kmsConfig, err := newKeyringEncryptionConfig(clusterdContext, clusterInfo, cephClusterSpec)
+	if err != nil {
+		return nil, err
+	}
+	kek, err := kmsConfig.GetOrCreateKeyringEncryptionKey()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the keyring encryption key")
+	}
+	return func(keyring string) (string, error) {
+		return kms.EncryptKeyringSecret(kek, keyring)
+	}, nil
+}
+
+// DecryptKeyringVolumes replaces the volumes of the encrypted keyring secrets of a pod with memory backed volumes, in
+// which an init container decrypts the keyrings. The containers of the pod keep mounting the volumes unchanged.
+func DecryptKeyringVolumes(spec *cephv1.ClusterSpec, rookImage string, podSpec *v1.PodSpec, secretNames ...string) {
+	if !spec.Security.KeyringEncryption.IsEnabled() {
+		return
+	}
+
+	args := []string{"key-management", "decrypt-keyring"}
+	mounts := []v1.VolumeMount{}
+	encryptedSecrets := sets.NewString(secretNames...)
+	for i, vol := range podSpec.Volumes {
+		if vol.Secret == nil || !encryptedSecrets.Has(vol.Secret.SecretName) {
+			continue
+		}
+		encryptedVol := vol.DeepCopy()
+		encryptedVol.Name = encryptedKeyringVolumePrefix + vol.Name
+		podSpec.Volumes[i] = v1.Volume{Name: vol.Name, VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{Medium: v1.StorageMediumMemory}}}
+		podSpec.Volumes = append(podSpec.Volumes, *encryptedVol)
+
+		encryptedPath := path.Join(encryptedKeyringDir, vol.Name)
+		decryptedPath := path.Join(decryptedKeyringDir, vol.Name)
+		mounts = append(mounts,
+			v1.VolumeMount{Name: encryptedVol.Name, MountPath: encryptedPath, ReadOnly: true},
+			v1.VolumeMount{Name: vol.Name, MountPath: decryptedPath},
+		)
+		args = append(args, encryptedPath, decryptedPath)
+	}
+	if len(mounts) == 0 {
+		return
+	}
+
+	// the kms env variables are generated from a copy since they modify the connection details
+	kmsSpec := spec.Security.KeyManagementService.DeepCopy()
+	env := append([]v1.EnvVar{k8sutil.NamespaceEnvVar()}, kms.ConfigToEnvVar(cephv1.ClusterSpec{Security: cephv1.SecuritySpec{KeyManagementService: *kmsSpec}})...)
+	if kmsSpec.IsVaultKMS() && kmsSpec.IsTLSEnabled() {
+		vaultVol, vaultMount := kms.VaultVolumeAndMount(kmsSpec.ConnectionDetails, "")
+		if !hasVolume(podSpec, vaultVol.Name) {
+			podSpec.Volumes = append(podSpec.Volumes, vaultVol)
+		}
+		mounts = append(mounts, vaultMount)
+	}
+
+	container := v1.Container{
+		Name:            keyringDecryptionContainerName,
+		Image:           rookImage,
+		ImagePullPolicy: GetContainerImagePullPolicy(spec.CephVersion.ImagePullPolicy),
+		Command:         []string{"rook"},
+		Args:            args,
+		Env:             env,
+		VolumeMounts:    mounts,
+	}
+	podSpec.InitContainers = append([]v1.Container{container}, podSpec.InitContainers...)
+}
+
+func hasVolume(podSpec *v1.PodSpec, name string) bool {
+	for _, vol := range podSpec.Volumes {
+		if vol.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// reconcileKeyringEncryption decrypts the cluster keys loaded from the mon secret in memory. When the cluster is
+// reconciled, the keys are also encrypted or decrypted in the mon secret to follow the keyring encryption setting.
+func reconcileKeyringEncryption(clusterdContext *clusterd.Context, secret *v1.Secret, clusterInfo *cephclient.ClusterInfo, ownerInfo *k8sutil.OwnerInfo, cephClusterSpec *cephv1.ClusterSpec) error {
+	encrypted := false
+	for _, key := range encryptedClusterKeys {
+		if kms.IsEncryptedKeyringSecret(string(secret.Data[key])) {
+			encrypted = true
+		}
+	}
+	enabled := cephClusterSpec.Security.KeyringEncryption.IsEnabled()
+	if !encrypted && !enabled {
+		return nil
+	}
+
+	kmsConfig, err := newKeyringEncryptionConfig(clusterdContext, clusterInfo, cephClusterSpec)
+	if err != nil {
+		return err
+	}
+	if encrypted {
+		kek, err := kmsConfig.GetKeyringEncryptionKey()
+		if err != nil {
+			return errors.Wrap(err, "failed to decrypt the cluster keys")
+		}
+		if clusterInfo.MonitorSecret, err = kms.DecryptKeyringSecret(kek, clusterInfo.MonitorSecret); err != nil {
+			return errors.Wrap(err, "failed to decrypt the mon key")
+		}
+		if clusterInfo.CephCred.Secret, err = kms.DecryptKeyringSecret(kek, clusterInfo.CephCred.Secret); err != nil {
+			return errors.Wrapf(err, "failed to decrypt the %q key", clusterInfo.CephCred.Username)
+		}
+	}
+
+	// only the cluster reconcile updates the mon secret
+	if ownerInfo == nil || encrypted == enabled {
+		return nil
+	}
+	secret.Data[MonSecretNameKey] = []byte(clusterInfo.MonitorSecret)
+	secret.Data[CephUserSecretKey] = []byte(clusterInfo.CephCred.Secret)
+	if enabled {
+		logger.Infof("encrypting the cluster keys of cluster %q with the kms", clusterInfo.Namespace)
+		if err := encryptSecretData(kmsConfig, secret.Data); err != nil {
+			return err
+		}
+	} else {
+		logger.Infof("keyring encryption disabled, storing the cluster keys of cluster %q unencrypted", clusterInfo.Namespace)
+	}
+	if _, err := clusterdContext.Clientset.CoreV1().Secrets(secret.Namespace).Update(clusterInfo.Context, secret, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrap(err, "failed to update the keyring encryption of the mon secrets")
+	}
+	return nil
+}
+
+// encryptClusterKeys encrypts the cluster keys of a new mon secret
+func encryptClusterKeys(clusterdContext *clusterd.Context, data map[string][]byte, clusterInfo *cephclient.ClusterInfo, cephClusterSpec *cephv1.ClusterSpec) error {
+	kmsConfig, err := newKeyringEncryptionConfig(clusterdContext, clusterInfo, cephClusterSpec)
+	if err != nil {
+		return err
+	}
+	return encryptSecretData(kmsConfig, data)
+}
+
+func encryptSecretData(kmsConfig keyringEncryptionKMS, data map[string][]byte) error {
+	kek, err := kmsConfig.GetOrCreateKeyringEncryptionKey()
+	if err != nil {
+		return errors.Wrap(err, "failed to encrypt the cluster keys")
+	}
+	for _, key := range encryptedClusterKeys {
+		encrypted, err := kms.EncryptKeyringSecret(kek, string(data[key]))
+		if err != nil {
+			return errors.Wrapf(err, "failed to encrypt %q", key)
+		}
+		data[key] = []byte(encrypted)
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/daemon/ceph/osd/kms"
+	"github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fakeKeyringEncryptionKMS struct {
+	kek string
+}
+
+func (f *fakeKeyringEncryptionKMS) GetKeyringEncryptionKey() (string, error) {
+	if f.kek == "" {
+		return "", fmt.Errorf("key not found")
+	}
+	return f.kek, nil
+}
+
+func (f *fakeKeyringEncryptionKMS) GetOrCreateKeyringEncryptionKey() (string, error) {
+	if f.kek == "" {
+		f.kek = base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32)))
+	}
+	return f.kek, nil
+}
+
+func TestValidateKeyringEncryption(t *testing.T) {
+	spec := &cephv1.ClusterSpec{}
+	assert.NoError(t, ValidateKeyringEncryption(spec))
+
+	spec.Security.KeyringEncryption.Enabled = true
+	assert.Error(t, ValidateKeyringEncryption(spec))
+
+	spec.Security.KeyManagementService.ConnectionDetails = map[string]string{"KMS_PROVIDER": "kmip"}
+	assert.Error(t, ValidateKeyringEncryption(spec))
+
+	spec.Security.KeyManagementService.ConnectionDetails = map[string]string{"KMS_PROVIDER": "vault"}
+	assert.NoError(t, ValidateKeyringEncryption(spec))
+
+	spec.External.Enable = true
+	assert.Error(t, ValidateKeyringEncryption(spec))
+}
+
+func TestClusterKeysEncryption(t *testing.T) {
+	fakeKMS := &fakeKeyringEncryptionKMS{}
+	newConfig := newKeyringEncryptionConfig
+	defer func() { newKeyringEncryptionConfig = newConfig }()
+	newKeyringEncryptionConfig = func(*clusterd.Context, *cephclient.ClusterInfo, *cephv1.ClusterSpec) (keyringEncryptionKMS, error) {
+		return fakeKMS, nil
+	}
+
+	ctx := context.TODO()
+	clientset := test.New(t, 1)
+	namespace := "ns"
+	assert.NoError(t, os.MkdirAll(namespace, 0755))
+	defer os.RemoveAll(namespace)
+	adminSecret := "AQDkLIBd9vLGJxAAnXsIKPrwvUXAmY+D1g0X1Q==" //nolint:gosec // This is just a var name, not a real secret
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if command == "ceph-authtool" && args[0] == "--create-keyring" {
+				assert.NoError(t, os.WriteFile(args[1], []byte(fmt.Sprintf("key = %s", adminSecret)), 0600))
+			}
+			return "", nil
+		},
+	}
+	clusterdContext := &clusterd.Context{Clientset: clientset, Executor: executor}
+	ownerInfo := cephclient.NewMinimumOwnerInfoWithOwnerRef()
+	spec := &cephv1.ClusterSpec{Security: cephv1.SecuritySpec{KeyringEncryption: cephv1.KeyringEncryptionSpec{Enabled: true}}}
+
+	monSecret := func() *v1.Secret {
+		secret, err := clientset.CoreV1().Secrets(namespace).Get(ctx, AppName, metav1.GetOptions{})
+		require.NoError(t, err)
+		return secret
+	}
+
+	t.Run("new cluster", func(t *testing.T) {
+		info, _, _, err := CreateOrLoadClusterInfo(clusterdContext, ctx, namespace, ownerInfo, spec)
+		assert.NoError(t, err)
+		assert.Equal(t, adminSecret, info.CephCred.Secret)
+		secret := monSecret()
+		assert.True(t, kms.IsEncryptedKeyringSecret(string(secret.Data[CephUserSecretKey])))
+		assert.True(t, kms.IsEncryptedKeyringSecret(string(secret.Data[MonSecretNameKey])))
+	})
+
+	t.Run("decrypted when loaded", func(t *testing.T) {
+		info, _, _, err := LoadClusterInfo(clusterdContext, ctx, namespace, spec)
+		assert.NoError(t, err)
+		assert.Equal(t, adminSecret, info.CephCred.Secret)
+		assert.Equal(t, adminSecret, info.MonitorSecret)
+
+		// the other controllers decrypt the keys even before they see the encryption enabled
+		info, _, _, err = LoadClusterInfo(clusterdContext, ctx, namespace, &cephv1.ClusterSpec{})
+		assert.NoError(t, err)
+		assert.Equal(t, adminSecret, info.CephCred.Secret)
+		assert.True(t, kms.IsEncryptedKeyringSecret(string(monSecret().Data[CephUserSecretKey])))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		info, _, _, err := CreateOrLoadClusterInfo(clusterdContext, ctx, namespace, ownerInfo, &cephv1.ClusterSpec{})
+		assert.NoError(t, err)
+		assert.Equal(t, adminSecret, info.CephCred.Secret)
+		assert.Equal(t, adminSecret, string(monSecret().Data[CephUserSecretKey]))
+		assert.Equal(t, adminSecret, string(monSecret().Data[MonSecretNameKey]))
+	})
+
+	t.Run("enabled on an existing cluster", func(t *testing.T) {
+		info, _, _, err := CreateOrLoadClusterInfo(clusterdContext, ctx, namespace, ownerInfo, spec)
+		assert.NoError(t, err)
+		assert.Equal(t, adminSecret, info.CephCred.Secret)
+		assert.True(t, kms.IsEncryptedKeyringSecret(string(monSecret().Data[CephUserSecretKey])))
+	})
+
+	t.Run("key not found", func(t *testing.T) {
+		fakeKMS.kek = ""
+		_, _, _, err := LoadClusterInfo(clusterdContext, ctx, namespace, spec)
+		assert.Error(t, err)
+	})
+}
+
+func TestDecryptKeyringVolumes(t *testing.T) {
+	newPodSpec := func() *v1.PodSpec {
+		return &v1.PodSpec{
+			InitContainers: []v1.Container{{Name: "chown"}},
+			Volumes: []v1.Volume{
+				{Name: "rook-ceph-admin-keyring", VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{SecretName: "rook-ceph-admin-keyring"}}},
+				{Name: "rook-ceph-mgr-a-keyring", VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{SecretName: "rook-ceph-mgr-a-keyring"}}},
+			},
+		}
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		podSpec := newPodSpec()
+		DecryptKeyringVolumes(&cephv1.ClusterSpec{}, "rook/ceph:master", podSpec, "rook-ceph-admin-keyring")
+		assert.Equal(t, newPodSpec(), podSpec)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		spec := &cephv1.ClusterSpec{Security: cephv1.SecuritySpec{
+			KeyringEncryption: cephv1.KeyringEncryptionSpec{Enabled: true},
+			KeyManagementService: cephv1.KeyManagementServiceSpec{
+				TokenSecretName:   "vault-token",
+				ConnectionDetails: map[string]string{"KMS_PROVIDER": "vault", "VAULT_ADDR": "https://vault:8200", "VAULT_CACERT": "vault-ca"},
+			},
+		}}
+		podSpec := newPodSpec()
+		DecryptKeyringVolumes(spec, "rook/ceph:master", podSpec, "rook-ceph-admin-keyring")
+
+		// the keyring volume keeps its name so the containers mount the decrypted keyring
+		assert.Equal(t, "rook-ceph-admin-keyring", podSpec.Volumes[0].Name)
+		assert.Equal(t, v1.StorageMediumMemory, podSpec.Volumes[0].EmptyDir.Medium)
+		// the other keyrings are not modified
+		assert.Equal(t, newPodSpec().Volumes[1], podSpec.Volumes[1])
+		assert.Equal(t, "encrypted-rook-ceph-admin-keyring", podSpec.Volumes[2].Name)
+		assert.Equal(t, "rook-ceph-admin-keyring", podSpec.Volumes[2].Secret.SecretName)
+		assert.Equal(t, "vault", podSpec.Volumes[3].Name)
+
+		require.Len(t, podSpec.InitContainers, 2)
+		container := podSpec.InitContainers[0]
+		assert.Equal(t, keyringDecryptionContainerName, container.Name)
+		assert.Equal(t, "rook/ceph:master", container.Image)
+		assert.Equal(t, []string{"key-management", "decrypt-keyring",
+			"/etc/rook/encrypted-keyrings/rook-ceph-admin-keyring", "/etc/rook/decrypted-keyrings/rook-ceph-admin-keyring"}, container.Args)
+		assert.Len(t, container.VolumeMounts, 3)
+		envs := map[string]bool{}
+		for _, env := range container.Env {
+			envs[env.Name] = true
+		}
+		assert.True(t, envs["POD_NAMESPACE"])
+		assert.True(t, envs["VAULT_ADDR"])
+		assert.True(t, envs["VAULT_TOKEN"])
+		// the connection details of the cluster are not modified
+		assert.Len(t, spec.Security.KeyManagementService.ConnectionDetails, 3)
+	})
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// AdoptedOrphanedResourceReason is the event reason when a resource left by a previous CR with the same name is
+	// adopted by the CR
+	AdoptedOrphanedResourceReason = "AdoptedOrphanedResource"
+	// DeletedOrphanedResourceReason is the event reason when a resource controlled by a CR that no longer exists is
+	// deleted
+	DeletedOrphanedResourceReason = "DeletedOrphanedResource"
+)
+
+// OwnerExistsFunc returns whether the CR with the given name exists
+type OwnerExistsFunc func(name string) (bool, error)
+
+// childResource abstracts the update and deletion of the kinds of resources checked for orphans
+type childResource struct {
+	kind   string
+	object metav1.Object
+	update func() error
+	delete func() error
+}
+
+// ReconcileOrphanedChildren looks for the Deployments, Services and Secrets in the namespace of the owner matching the
+// label selector of the children of the owner's kind, whose controller is a CR of the owner's kind that no longer
+// exists. This happens when a CR is deleted and recreated
+// before the garbage collector catches up, or when the children are orphaned on deletion. The children of a
+// previous CR with the same name as the owner are adopted by the owner, since the owner reconciles them anyway. The
+// children of CRs that no longer exist are deleted, so they don't keep running with a stale configuration. Both
+// actions are reported as events on the owner.
+func ReconcileOrphanedChildren(ctx context.Context, clientset kubernetes.Interface, recorder record.EventRecorder, owner client.Object, ownerKind, selector string, ownerExists OwnerExistsFunc) error {
+	children, err := listChildResources(ctx, clientset, owner.GetNamespace(), selector)
+	if err != nil {
+		return err
+	}
+
+	for _, child := range children {
+		ref := metav1.GetControllerOf(child.object)
+		if ref == nil || ref.Kind != ownerKind || ref.UID == owner.GetUID() {
+			continue
+		}
+		childName := fmt.Sprintf("%s %q", child.kind, child.object.GetName())
+
+		if ref.Name == owner.GetName() {
+			// the child was created by a previous CR with the same name
+			refs := child.object.GetOwnerReferences()
+			for i := range refs {
+				if refs[i].UID == ref.UID {
+					refs[i].UID = owner.GetUID()
+				}
+			}
+			child.object.SetOwnerReferences(refs)
+			if err := child.update(); err != nil {
+				return errors.Wrapf(err, "failed to adopt orphaned %s", childName)
+			}
+			msg := fmt.Sprintf("adopted %s left by a previous %s %q", childName, ownerKind, ref.Name)
+			logger.Info(msg)
+			recorder.Event(owner, corev1.EventTypeNormal, AdoptedOrphanedResourceReason, msg)
+			continue
+		}
+
+		exists, err := ownerExists(ref.Name)
+		if err != nil {
+			return errors.Wrapf(err, "failed to check if %s %q exists", ownerKind, ref.Name)
+		}
+		if exists {
+			// the child belongs to another CR, which reconciles it
+			continue
+		}
+		if err := child.delete(); err != nil && !kerrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete orphaned %s", childName)
+		}
+		msg := fmt.Sprintf("deleted %s orphaned by %s %q that no longer exists", childName, ownerKind, ref.Name)
+		logger.Info(msg)
+		recorder.Event(owner, corev1.EventTypeNormal, DeletedOrphanedResourceReason, msg)
+	}
+
+	return nil
+}
+
+func listChildResources(ctx context.Context, clientset kubernetes.Interface, namespace, selector string) ([]childResource, error) {
+	children := []childResource{}
+	opts := metav1.ListOptions{LabelSelector: selector}
+
+	deployments, err := clientset.AppsV1().Deployments(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list deployments in namespace %q", namespace)
+	}
+	for i := range deployments.Items {
+		d := &deployments.Items[i]
+		children = append(children, childResource{
+			kind:   "deployment",
+			object: d,
+			update: func() error {
+				_, err := clientset.AppsV1().Deployments(namespace).Update(ctx, d, metav1.UpdateOptions{})
+				return err
+			},
+			delete: func() error {
+				return clientset.AppsV1().Deployments(namespace).Delete(ctx, d.Name, metav1.DeleteOptions{})
+			},
+		})
+	}
+
+	services, err := clientset.CoreV1().Services(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list services in namespace %q", namespace)
+	}
+	for i := range services.Items {
+		s := &services.Items[i]
+		children = append(children, childResource{
+			kind:   "service",
+			object: s,
+			update: func() error {
+				_, err := clientset.CoreV1().Services(namespace).Update(ctx, s, metav1.UpdateOptions{})
+				return err
+			},
+			delete: func() error {
+				return clientset.CoreV1().Services(namespace).Delete(ctx, s.Name, metav1.DeleteOptions{})
+			},
+		})
+	}
+
+	secrets, err := clientset.CoreV1().Secrets(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list secrets in namespace %q", namespace)
+	}
+	for i := range secrets.Items {
+		s := &secrets.Items[i]
+		children = append(children, childResource{
+			kind:   "secret",
+			object: s,
+			update: func() error {
+				_, err := clientset.CoreV1().Secrets(namespace).Update(ctx, s, metav1.UpdateOptions{})
+				return err
+			},
+			delete: func() error {
+				return clientset.CoreV1().Secrets(namespace).Delete(ctx, s.Name, metav1.DeleteOptions{})
+			},
+		})
+	}
+
+	return children, nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestReconcileOrphanedChildren(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	controlledBy := func(kind, name string, uid types.UID) []metav1.OwnerReference {
+		isController := true
+		return []metav1.OwnerReference{{APIVersion: "ceph.rook.io/v1", Kind: kind, Name: name, UID: uid, Controller: &isController}}
+	}
+	meta := func(name string, refs []metav1.OwnerReference) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: namespace, OwnerReferences: refs, Labels: map[string]string{"app": "rook-ceph-rgw"}}
+	}
+	unlabeled := func(name string, refs []metav1.OwnerReference) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: namespace, OwnerReferences: refs}
+	}
+
+	clientset := fake.NewSimpleClientset(
+		// children of the current store
+		&appsv1.Deployment{ObjectMeta: meta("rgw-store-a", controlledBy("CephObjectStore", "store", "new-uid"))},
+		// children of a previous store with the same name
+		&appsv1.Deployment{ObjectMeta: meta("rgw-store-b", controlledBy("CephObjectStore", "store", "old-uid"))},
+		&corev1.Secret{ObjectMeta: meta("rgw-store-keyring", controlledBy("CephObjectStore", "store", "old-uid"))},
+		// children of a store that was deleted
+		&appsv1.Deployment{ObjectMeta: meta("rgw-renamed-a", controlledBy("CephObjectStore", "renamed", "renamed-uid"))},
+		&corev1.Service{ObjectMeta: meta("rgw-renamed", controlledBy("CephObjectStore", "renamed", "renamed-uid"))},
+		// children of another store that exists
+		&corev1.Service{ObjectMeta: meta("rgw-other", controlledBy("CephObjectStore", "other", "other-uid"))},
+		// children of another kind
+		&appsv1.Deployment{ObjectMeta: meta("mds-fs-a", controlledBy("CephFilesystem", "fs", "fs-uid"))},
+		// resources not matching the selector of the children
+		&corev1.Secret{ObjectMeta: unlabeled("unlabeled", controlledBy("CephObjectStore", "renamed", "renamed-uid"))},
+	)
+	recorder := record.NewFakeRecorder(10)
+	owner := &cephv1.CephObjectStore{ObjectMeta: metav1.ObjectMeta{Name: "store", Namespace: namespace, UID: "new-uid"}}
+	ownerExists := func(name string) (bool, error) {
+		return name == "store" || name == "other", nil
+	}
+
+	err := ReconcileOrphanedChildren(ctx, clientset, recorder, owner, "CephObjectStore", "app=rook-ceph-rgw", ownerExists)
+	assert.NoError(t, err)
+
+	// the children of the previous store with the same name are adopted
+	d, err := clientset.AppsV1().Deployments(namespace).Get(ctx, "rgw-store-b", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, types.UID("new-uid"), metav1.GetControllerOf(d).UID)
+	s, err := clientset.CoreV1().Secrets(namespace).Get(ctx, "rgw-store-keyring", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, types.UID("new-uid"), metav1.GetControllerOf(s).UID)
+
+	// the children of the deleted store are deleted
+	_, err = clientset.AppsV1().Deployments(namespace).Get(ctx, "rgw-renamed-a", metav1.GetOptions{})
+	assert.True(t, kerrors.IsNotFound(err))
+	_, err = clientset.CoreV1().Services(namespace).Get(ctx, "rgw-renamed", metav1.GetOptions{})
+	assert.True(t, kerrors.IsNotFound(err))
+
+	// the other children are left alone
+	_, err = clientset.CoreV1().Services(namespace).Get(ctx, "rgw-other", metav1.GetOptions{})
+	assert.NoError(t, err)
+	_, err = clientset.AppsV1().Deployments(namespace).Get(ctx, "mds-fs-a", metav1.GetOptions{})
+	assert.NoError(t, err)
+	_, err = clientset.AppsV1().Deployments(namespace).Get(ctx, "rgw-store-a", metav1.GetOptions{})
+	assert.NoError(t, err)
+	_, err = clientset.CoreV1().Secrets(namespace).Get(ctx, "unlabeled", metav1.GetOptions{})
+	assert.NoError(t, err)
+
+	assert.Len(t, recorder.Events, 4)
+}
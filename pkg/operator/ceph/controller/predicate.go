@@ -445,6 +445,40 @@ func WatchControllerPredicate() predicate.Funcs {
 	}
 }
 
+// PredicateClusterDefaultsChanged only lets through the updates of a CephCluster that change the default placement,
+// resources or annotations of the daemons of the key, which the CRs of these daemons inherit
+func PredicateClusterDefaultsChanged(key cephv1.KeyType) predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return false
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldCluster, ok := e.ObjectOld.(*cephv1.CephCluster)
+			if !ok {
+				return false
+			}
+			newCluster, ok := e.ObjectNew.(*cephv1.CephCluster)
+			if !ok {
+				return false
+			}
+			resourceQtyComparer := cmp.Comparer(func(x, y resource.Quantity) bool { return x.Cmp(y) == 0 })
+			changed := !cmp.Equal(oldCluster.Spec.Placement[key], newCluster.Spec.Placement[key], resourceQtyComparer) ||
+				!cmp.Equal(oldCluster.Spec.Resources[string(key)], newCluster.Spec.Resources[string(key)], resourceQtyComparer) ||
+				!reflect.DeepEqual(oldCluster.Spec.Annotations[key], newCluster.Spec.Annotations[key])
+			if changed {
+				logger.Debugf("default %q settings of CephCluster %q changed", key, newCluster.Name)
+			}
+			return changed
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
 func objectToBeDeleted(oldObj, newObj client.Object) bool {
 	return !oldObj.GetDeletionTimestamp().Equal(newObj.GetDeletionTimestamp())
 }
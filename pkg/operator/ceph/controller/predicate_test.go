@@ -27,9 +27,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 )
 
 var (
@@ -277,3 +279,45 @@ func TestDuplicateCephClusters(t *testing.T) {
 		assert.True(t, DuplicateCephClusters(ctx, cl, dup, true))
 	})
 }
+
+func TestPredicateClusterDefaultsChanged(t *testing.T) {
+	p := PredicateClusterDefaultsChanged(cephv1.KeyRgw)
+	oldCluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: namespace},
+		Spec: cephv1.ClusterSpec{
+			Placement: cephv1.PlacementSpec{
+				cephv1.KeyRgw: {Tolerations: []corev1.Toleration{{Key: "rgw"}}},
+			},
+			Resources: cephv1.ResourceSpec{
+				cephv1.ResourcesKeyRgw: {Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}},
+			},
+			Annotations: cephv1.AnnotationsSpec{
+				cephv1.KeyRgw: {"a": "b"},
+			},
+		},
+	}
+	update := func(change func(c *cephv1.CephCluster)) bool {
+		newCluster := oldCluster.DeepCopy()
+		change(newCluster)
+		return p.Update(event.UpdateEvent{ObjectOld: oldCluster, ObjectNew: newCluster})
+	}
+
+	assert.False(t, p.Create(event.CreateEvent{Object: oldCluster}))
+	assert.False(t, p.Delete(event.DeleteEvent{Object: oldCluster}))
+	assert.False(t, update(func(c *cephv1.CephCluster) {}))
+	assert.False(t, update(func(c *cephv1.CephCluster) {
+		c.Spec.Resources[cephv1.ResourcesKeyRgw] = corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1000m")}}
+	}))
+	assert.False(t, update(func(c *cephv1.CephCluster) {
+		c.Spec.Placement[cephv1.KeyMds] = cephv1.Placement{Tolerations: []corev1.Toleration{{Key: "mds"}}}
+	}))
+	assert.True(t, update(func(c *cephv1.CephCluster) {
+		c.Spec.Placement[cephv1.KeyRgw] = cephv1.Placement{}
+	}))
+	assert.True(t, update(func(c *cephv1.CephCluster) {
+		c.Spec.Resources[cephv1.ResourcesKeyRgw] = corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")}}
+	}))
+	assert.True(t, update(func(c *cephv1.CephCluster) {
+		c.Spec.Annotations[cephv1.KeyRgw] = cephv1.Annotations{"a": "c"}
+	}))
+}
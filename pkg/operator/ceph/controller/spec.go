@@ -389,6 +389,33 @@ func NetworkBindingFlags(cluster *client.ClusterInfo, spec *cephv1.ClusterSpec)
 	return args
 }
 
+// ApplyServiceIPFamily sets the ip families of a service of the cluster from the network settings of the cluster, since
+// the default ip family of a dual-stack Kubernetes cluster may not be the one the ceph daemons bind to. The primary ip
+// family is the one of the network settings, IPv4 if only dual-stack is enabled. The ip families of the existing
+// services are immutable and not updated.
+func ApplyServiceIPFamily(spec *cephv1.ClusterSpec, service *v1.Service) {
+	if spec.Network.IPFamily == "" && !spec.Network.DualStack {
+		// the default ip family of the Kubernetes cluster
+		return
+	}
+
+	primary := v1.IPv4Protocol
+	secondary := v1.IPv6Protocol
+	if spec.Network.IPFamily == cephv1.IPv6 {
+		primary, secondary = secondary, primary
+	}
+
+	if spec.Network.DualStack {
+		policy := v1.IPFamilyPolicyPreferDualStack
+		service.Spec.IPFamilyPolicy = &policy
+		service.Spec.IPFamilies = []v1.IPFamily{primary, secondary}
+		return
+	}
+	policy := v1.IPFamilyPolicySingleStack
+	service.Spec.IPFamilyPolicy = &policy
+	service.Spec.IPFamilies = []v1.IPFamily{primary}
+}
+
 // ContainerEnvVarReference returns a reference to a Kubernetes container env var of the given name
 // which can be used in command or argument fields.
 func ContainerEnvVarReference(envVarName string) string {
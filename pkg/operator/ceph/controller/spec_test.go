@@ -243,6 +243,35 @@ func TestNetworkBindingFlags(t *testing.T) {
 	}
 }
 
+func TestApplyServiceIPFamily(t *testing.T) {
+	apply := func(network cephv1.NetworkSpec) *v1.Service {
+		svc := &v1.Service{}
+		ApplyServiceIPFamily(&cephv1.ClusterSpec{Network: network}, svc)
+		return svc
+	}
+
+	// the default ip family of the Kubernetes cluster
+	svc := apply(cephv1.NetworkSpec{})
+	assert.Nil(t, svc.Spec.IPFamilyPolicy)
+	assert.Empty(t, svc.Spec.IPFamilies)
+
+	svc = apply(cephv1.NetworkSpec{IPFamily: cephv1.IPv6})
+	assert.Equal(t, v1.IPFamilyPolicySingleStack, *svc.Spec.IPFamilyPolicy)
+	assert.Equal(t, []v1.IPFamily{v1.IPv6Protocol}, svc.Spec.IPFamilies)
+
+	svc = apply(cephv1.NetworkSpec{IPFamily: cephv1.IPv4})
+	assert.Equal(t, v1.IPFamilyPolicySingleStack, *svc.Spec.IPFamilyPolicy)
+	assert.Equal(t, []v1.IPFamily{v1.IPv4Protocol}, svc.Spec.IPFamilies)
+
+	svc = apply(cephv1.NetworkSpec{DualStack: true})
+	assert.Equal(t, v1.IPFamilyPolicyPreferDualStack, *svc.Spec.IPFamilyPolicy)
+	assert.Equal(t, []v1.IPFamily{v1.IPv4Protocol, v1.IPv6Protocol}, svc.Spec.IPFamilies)
+
+	svc = apply(cephv1.NetworkSpec{DualStack: true, IPFamily: cephv1.IPv6})
+	assert.Equal(t, v1.IPFamilyPolicyPreferDualStack, *svc.Spec.IPFamilyPolicy)
+	assert.Equal(t, []v1.IPFamily{v1.IPv6Protocol, v1.IPv4Protocol}, svc.Spec.IPFamilies)
+}
+
 func TestExtractMgrIP(t *testing.T) {
 	activeMgrRaw := "172.17.0.12:6801/2535462469"
 	ip := extractMgrIP(activeMgrRaw)
@@ -108,15 +108,39 @@ func DetectCephVersion(ctx context.Context, rookImage, namespace, jobName string
 	return version, nil
 }
 
-func CurrentAndDesiredCephVersion(ctx context.Context, rookImage, namespace, jobName string, ownerInfo *k8sutil.OwnerInfo, context *clusterd.Context, cephClusterSpec *cephv1.ClusterSpec, clusterInfo *cephclient.ClusterInfo) (*cephver.CephVersion, *cephver.CephVersion, error) {
-	// Detect desired CephCluster version
-	desiredCephVersion, err := DetectCephVersion(ctx, rookImage, namespace, fmt.Sprintf("%s-detect-version", jobName), ownerInfo, context.Clientset, cephClusterSpec)
+// CachedLeastUptodateDaemonVersion returns the least up to date version of the running daemons of the given
+// type from the cache of the context, and queries the mons only if the version is not cached yet
+func CachedLeastUptodateDaemonVersion(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, daemonType string) (cephver.CephVersion, error) {
+	if cached, ok := context.Cache.Get(clusterInfo.Namespace, daemonVersionCacheName+daemonType); ok {
+		return cached.(cephver.CephVersion), nil
+	}
+
+	version, err := cephclient.LeastUptodateDaemonVersion(context, clusterInfo, daemonType)
 	if err != nil {
-		return nil, nil, errors.Wrap(err, "failed to detect ceph image version")
+		return version, err
+	}
+	context.Cache.Set(clusterInfo.Namespace, daemonVersionCacheName+daemonType, version)
+	return version, nil
+}
+
+func CurrentAndDesiredCephVersion(ctx context.Context, rookImage, namespace, jobName string, ownerInfo *k8sutil.OwnerInfo, context *clusterd.Context, cephClusterSpec *cephv1.ClusterSpec, clusterInfo *cephclient.ClusterInfo) (*cephver.CephVersion, *cephver.CephVersion, error) {
+	// Detect desired CephCluster version, the version of an image is only detected again when the cache expires
+	imageVersionKey := imageVersionCacheName + cephClusterSpec.CephVersion.Image
+	var desiredCephVersion *cephver.CephVersion
+	if cached, ok := context.Cache.Get(namespace, imageVersionKey); ok {
+		version := cached.(cephver.CephVersion)
+		desiredCephVersion = &version
+	} else {
+		var err error
+		desiredCephVersion, err = DetectCephVersion(ctx, rookImage, namespace, fmt.Sprintf("%s-detect-version", jobName), ownerInfo, context.Clientset, cephClusterSpec)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to detect ceph image version")
+		}
+		context.Cache.Set(namespace, imageVersionKey, *desiredCephVersion)
 	}
 
 	// Check the ceph version of the running monitors
-	runningMonDaemonVersion, err := cephclient.LeastUptodateDaemonVersion(context, clusterInfo, config.MonType)
+	runningMonDaemonVersion, err := CachedLeastUptodateDaemonVersion(context, clusterInfo, config.MonType)
 	if err != nil {
 		return nil, nil, errors.Wrapf(err, "failed to retrieve current ceph %q version", config.MonType)
 	}
@@ -19,6 +19,7 @@ package operator
 import (
 	"context"
 
+	routev1 "github.com/openshift/api/route/v1"
 	"github.com/pkg/errors"
 	"github.com/rook/rook/pkg/clusterd"
 	"github.com/rook/rook/pkg/operator/ceph/client"
@@ -35,31 +36,40 @@ import (
 	"github.com/rook/rook/pkg/operator/ceph/nfs"
 	"github.com/rook/rook/pkg/operator/ceph/object"
 	"github.com/rook/rook/pkg/operator/ceph/object/bucket"
+	"github.com/rook/rook/pkg/operator/ceph/object/cosi"
 	"github.com/rook/rook/pkg/operator/ceph/object/notification"
 	"github.com/rook/rook/pkg/operator/ceph/object/realm"
 	"github.com/rook/rook/pkg/operator/ceph/object/topic"
 	objectuser "github.com/rook/rook/pkg/operator/ceph/object/user"
 	"github.com/rook/rook/pkg/operator/ceph/object/zone"
 	"github.com/rook/rook/pkg/operator/ceph/object/zonegroup"
+	"github.com/rook/rook/pkg/operator/ceph/operation"
 	"github.com/rook/rook/pkg/operator/ceph/pool"
 	"github.com/rook/rook/pkg/operator/ceph/pool/radosnamespace"
+	"github.com/rook/rook/pkg/operator/k8sutil"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	corev1 "k8s.io/api/core/v1"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
 const (
 	certDir = "/etc/webhook"
+	// the port must match the "http-metrics" container port of the operator deployment
+	defaultMetricsBindAddress = ":8080"
 )
 
 var (
 	resourcesSchemeFuncs = []func(*runtime.Scheme) error{
 		clientgoscheme.AddToScheme,
 		cephv1.AddToScheme,
+		// the routes of the object stores on OpenShift
+		routev1.AddToScheme,
 	}
 )
 
@@ -70,6 +80,7 @@ var (
 		&cephv1.CephObjectStore{},
 		&cephv1.CephBlockPoolRadosNamespace{},
 		&cephv1.CephFilesystemSubVolumeGroup{},
+		&cephv1.CephFilesystem{},
 	}
 )
 
@@ -104,6 +115,8 @@ var AddToManagerFuncs = []func(manager.Manager, *clusterd.Context, context.Conte
 	notification.Add,
 	subvolumegroup.Add,
 	radosnamespace.Add,
+	cosi.Add,
+	operation.Add,
 }
 
 // AddToManagerOpFunc is a list of functions to add all Controllers to the Manager (entrypoint for
@@ -152,12 +165,25 @@ func (o *Operator) startCRDManager(context context.Context, mgrErrorCh chan erro
 		}
 	}
 
+	// The metrics of the controllers, the ceph commands and the phases of the CRs are served on this
+	// address. Setting it to "0" disables the metrics endpoint.
+	metricsBindAddress, err := k8sutil.GetOperatorSetting(context, o.context.Clientset, opcontroller.OperatorSettingConfigMapName, "ROOK_OPERATOR_METRICS_BIND_ADDRESS", defaultMetricsBindAddress)
+	if err != nil {
+		mgrErrorCh <- errors.Wrap(err, "failed to get the metrics bind address of the operator")
+		return
+	}
+
 	// Set up a manager
 	mgrOpts := manager.Options{
-		LeaderElection: false,
-		Namespace:      o.config.NamespaceToWatch,
-		Scheme:         scheme,
-		CertDir:        certDir,
+		LeaderElection:     false,
+		Namespace:          o.config.NamespaceToWatch,
+		Scheme:             scheme,
+		CertDir:            certDir,
+		MetricsBindAddress: metricsBindAddress,
+		// Read the secrets directly from the namespace they are in, so that reading a secret does not start an
+		// informer on the secrets of all the watched namespaces, which requires permission to list and watch them.
+		// The namespaces are only read to check if they are terminating, which does not need an informer either.
+		ClientDisableCacheFor: []crclient.Object{&corev1.Secret{}, &corev1.Namespace{}},
 	}
 
 	logger.Info("setting up the controller-runtime manager")
@@ -166,6 +192,7 @@ func (o *Operator) startCRDManager(context context.Context, mgrErrorCh chan erro
 		mgrErrorCh <- errors.Wrap(err, "failed to set up overall controller-runtime manager")
 		return
 	}
+	o.context.Recorder = mgr.GetEventRecorderFor("rook-ceph-operator")
 
 	// Add webhook if needed
 	isPresent, err := createWebhook(context, o.context)
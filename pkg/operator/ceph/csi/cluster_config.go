@@ -26,6 +26,7 @@ import (
 
 	"github.com/coreos/pkg/capnslog"
 	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/daemon/ceph/client"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
 	"github.com/rook/rook/pkg/operator/k8sutil"
@@ -102,6 +103,20 @@ func formatCsiClusterConfig(cc csiClusterConfig) (string, error) {
 	return string(ccJson), nil
 }
 
+// ClusterMonEndpoints returns the mon endpoints of the cluster for the csi config. If the mons are published
+// with DNS discovery, the endpoint is the name of the headless service of the mons, so the csi config doesn't
+// change when the mons fail over.
+func ClusterMonEndpoints(namespace string, mons map[string]*cephclient.MonInfo, spec *cephv1.ClusterSpec) []string {
+	if !spec.Mon.DNSDiscovery || spec.External.Enable {
+		return MonEndpoints(mons, spec.RequireMsgr2())
+	}
+	port := int32(client.Msgr1port)
+	if spec.RequireMsgr2() {
+		port = client.Msgr2port
+	}
+	return []string{cephclient.MonDNSDiscoveryEndpoint(namespace, port)}
+}
+
 func MonEndpoints(mons map[string]*cephclient.MonInfo, requireMsgr2 bool) []string {
 	endpoints := make([]string, 0)
 	for _, m := range mons {
@@ -20,6 +20,7 @@ import (
 	"strings"
 	"testing"
 
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
 	"github.com/stretchr/testify/assert"
 )
@@ -381,6 +382,31 @@ func TestMonEndpoints(t *testing.T) {
 	})
 }
 
+func TestClusterMonEndpoints(t *testing.T) {
+	monInfo := map[string]*cephclient.MonInfo{
+		"a": {Name: "a", Endpoint: "1.2.3.4:6789"},
+		"b": {Name: "b", Endpoint: "1.2.3.5:6789"},
+	}
+	spec := &cephv1.ClusterSpec{}
+
+	t.Run("mon endpoints without dns discovery", func(t *testing.T) {
+		endpoints := ClusterMonEndpoints("rook-ceph", monInfo, spec)
+		assert.Equal(t, 2, len(endpoints))
+	})
+
+	t.Run("service name with dns discovery", func(t *testing.T) {
+		spec.Mon.DNSDiscovery = true
+		endpoints := ClusterMonEndpoints("rook-ceph", monInfo, spec)
+		assert.Equal(t, []string{"rook-ceph-mon-headless.rook-ceph.svc:6789"}, endpoints)
+	})
+
+	t.Run("msgr2 service port with dns discovery", func(t *testing.T) {
+		spec.Network.Connections = &cephv1.ConnectionsSpec{RequireMsgr2: true}
+		endpoints := ClusterMonEndpoints("rook-ceph", monInfo, spec)
+		assert.Equal(t, []string{"rook-ceph-mon-headless.rook-ceph.svc:3300"}, endpoints)
+	})
+}
+
 func verifyEndpointPort(t *testing.T, endpoints []string, expectedPort string) {
 	for _, endpoint := range endpoints {
 		assert.True(t, strings.HasSuffix(endpoint, expectedPort))
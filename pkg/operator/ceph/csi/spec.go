@@ -877,7 +877,7 @@ func (r *ReconcileCSI) configureHolder(driver driverDetails, c ClusterDetail, tp
 	}
 
 	clusterConfigEntry := &CsiClusterConfigEntry{
-		Monitors: MonEndpoints(c.clusterInfo.Monitors, c.cluster.Spec.RequireMsgr2()),
+		Monitors: ClusterMonEndpoints(c.clusterInfo.Namespace, c.clusterInfo.Monitors, &c.cluster.Spec),
 		RBD:      &CsiRBDSpec{},
 		CephFS:   &CsiCephFSSpec{},
 		NFS:      &CsiNFSSpec{},
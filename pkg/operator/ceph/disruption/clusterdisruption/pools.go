@@ -117,6 +117,10 @@ func (r *ReconcileClusterDisruption) reconcileCephObjectStore(cephObjectStoreLis
 			MatchLabels: map[string]string{"rgw": storeName},
 		}
 
+		// the gateways of a daemonset are not evicted by the drains
+		if objectStore.Spec.Gateway.AllNodes {
+			continue
+		}
 		rgwCount := objectStore.Spec.Gateway.Instances
 		minAvailable := &intstr.IntOrString{IntVal: rgwCount - 1}
 		if minAvailable.IntVal < 1 {
@@ -34,6 +34,7 @@ import (
 	"github.com/rook/rook/pkg/operator/ceph/file/mirror"
 	"github.com/rook/rook/pkg/operator/ceph/reporting"
 	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/util/dependents"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -147,6 +148,16 @@ func add(opManagerContext context.Context, mgr manager.Manager, r reconcile.Reco
 		return err
 	}
 
+	// Watch for changes on the default mds settings of the CephCluster, which the filesystems of the cluster inherit
+	clusterHandlerFunc, err := opcontroller.ObjectToCRInNamespaceMapper(opManagerContext, mgr.GetClient(), &cephv1.CephFilesystemList{}, mgr.GetScheme())
+	if err != nil {
+		return err
+	}
+	err = c.Watch(&source.Kind{Type: &cephv1.CephCluster{}}, handler.EnqueueRequestsFromMapFunc(clusterHandlerFunc), opcontroller.PredicateClusterDefaultsChanged(cephv1.KeyMds))
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -213,8 +224,21 @@ func (r *ReconcileCephFilesystem) reconcile(request reconcile.Request) (reconcil
 		// If not, we should wait for it to be ready
 		// This handles the case where the operator is not ready to accept Ceph command but the cluster exists
 		if !cephFilesystem.GetDeletionTimestamp().IsZero() && !cephClusterExists {
+			// The subvolume groups of the filesystem are deleted before the filesystem when the namespace is terminating
+			if opcontroller.IsNamespaceTerminating(r.opManagerContext, r.client, cephFilesystem.Namespace) {
+				deps := dependents.NewDependentList()
+				if err := getSubVolumeGroupCRDependents(deps, r.context, r.opManagerContext, cephFilesystem); err != nil {
+					return reconcile.Result{}, *cephFilesystem, err
+				}
+				if !deps.Empty() {
+					err := reporting.ReportDeletionBlockedDueToDependents(r.opManagerContext, logger, r.client, cephFilesystem, deps)
+					return opcontroller.WaitForRequeueIfFinalizerBlocked, *cephFilesystem, err
+				}
+			}
+
 			// don't leak the health checker routine if we are force deleting
 			r.cancelMirrorMonitoring(cephFilesystem)
+			deletePoolCapacityMetrics(*cephFilesystem)
 
 			// Remove finalizer
 			err := opcontroller.RemoveFinalizer(r.opManagerContext, r.client, cephFilesystem)
@@ -323,6 +347,7 @@ func (r *ReconcileCephFilesystem) reconcile(request reconcile.Request) (reconcil
 			logger.Info(opcontroller.OperatorNotInitializedMessage)
 			return opcontroller.WaitForRequeueIfOperatorNotInitialized, *cephFilesystem, nil
 		}
+		reporting.ReportValidationFailed(r.recorder, cephFilesystem, err)
 		return reconcile.Result{}, *cephFilesystem,
 			errors.Wrapf(err, "invalid object filesystem %q arguments", cephFilesystem.Name)
 	}
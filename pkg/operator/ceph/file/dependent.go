@@ -17,6 +17,7 @@ limitations under the License.
 package file
 
 import (
+	"context"
 	"fmt"
 	"syscall"
 
@@ -85,10 +86,21 @@ func cephFilesystemDependents(clusterdCtx *clusterd.Context, clusterInfo *client
 		}
 	}
 
-	// CephFilesystemSubVolumeGroups
-	subVolumeGroups, err := clusterdCtx.RookClientset.CephV1().CephFilesystemSubVolumeGroups(filesystem.Namespace).List(clusterInfo.Context, metav1.ListOptions{})
+	err = getSubVolumeGroupCRDependents(deps, clusterdCtx, clusterInfo.Context, filesystem)
 	if err != nil {
-		return deps, errors.Wrapf(err, "%s. failed to list CephFilesystemSubVolumeGroups for CephFilesystem %q", baseErrMsg, nsName)
+		return deps, errors.Wrapf(err, baseErrMsg)
+	}
+
+	return deps, nil
+}
+
+// adds the CephFilesystemSubVolumeGroups of the filesystem to the given dependents list
+func getSubVolumeGroupCRDependents(deps *dependents.DependentList, clusterdCtx *clusterd.Context, ctx context.Context, filesystem *v1.CephFilesystem) error {
+	nsName := fmt.Sprintf("%s/%s", filesystem.Namespace, filesystem.Name)
+
+	subVolumeGroups, err := clusterdCtx.RookClientset.CephV1().CephFilesystemSubVolumeGroups(filesystem.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to list CephFilesystemSubVolumeGroups for CephFilesystem %q", nsName)
 	}
 	for _, subVolumeGroup := range subVolumeGroups.Items {
 		if subVolumeGroup.Spec.FilesystemName == filesystem.Name {
@@ -96,8 +108,7 @@ func cephFilesystemDependents(clusterdCtx *clusterd.Context, clusterInfo *client
 		}
 		logger.Debugf("found CephFilesystemSubVolumeGroups %q that does not depend on CephFilesystem %q", subVolumeGroup.Name, nsName)
 	}
-
-	return deps, nil
+	return nil
 }
 
 // return subvolume groups that have 1 or more subvolumes present in them
@@ -122,9 +122,16 @@ func deleteFilesystem(
 			logger.Warningf("continuing to remove filesystem CR even though removal failed. %v", err)
 		}
 	}
+	deletePoolCapacityMetrics(fs)
 	return nil
 }
 
+// deletePoolCapacityMetrics deletes the capacity metrics of the pools of the filesystem
+func deletePoolCapacityMetrics(fs cephv1.CephFilesystem) {
+	f := newFS(fs.Name, fs.Namespace)
+	cephpool.DeletePoolCapacityMetrics(fs.Namespace, append(generateDataPoolNames(f, fs.Spec), generateMetaDataPoolName(f))...)
+}
+
 func validateFilesystem(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, clusterSpec *cephv1.ClusterSpec, f *cephv1.CephFilesystem) error {
 	if f.Name == "" {
 		return errors.New("missing name")
@@ -167,6 +174,9 @@ func createOrUpdatePools(f *Filesystem, context *clusterd.Context, clusterInfo *
 		Name:     generateMetaDataPoolName(f),
 		PoolSpec: spec.MetadataPool,
 	}
+	if err := cephpool.ValidateNewPoolCapacity(context, clusterInfo, metadataPool); err != nil {
+		return err
+	}
 	err := cephclient.CreatePool(context, clusterInfo, clusterSpec, metadataPool, "")
 	if err != nil {
 		return errors.Wrapf(err, "failed to update metadata pool %q", metadataPool.Name)
@@ -175,6 +185,9 @@ func createOrUpdatePools(f *Filesystem, context *clusterd.Context, clusterInfo *
 	dataPoolNames := generateDataPoolNames(f, spec)
 	for i, dataPool := range spec.DataPools {
 		dataPool.Name = dataPoolNames[i]
+		if err := cephpool.ValidateNewPoolCapacity(context, clusterInfo, dataPool); err != nil {
+			return err
+		}
 		err := cephclient.CreatePool(context, clusterInfo, clusterSpec, dataPool, "")
 		if err != nil {
 			return errors.Wrapf(err, "failed to update datapool  %q", dataPool.Name)
@@ -238,6 +251,9 @@ func (f *Filesystem) doFilesystemCreate(context *clusterd.Context, clusterInfo *
 		PoolSpec: spec.MetadataPool,
 	}
 	if _, poolFound := reversedPoolMap[metadataPool.Name]; !poolFound {
+		if err := cephpool.ValidateNewPoolCapacity(context, clusterInfo, metadataPool); err != nil {
+			return err
+		}
 		err = cephclient.CreatePool(context, clusterInfo, clusterSpec, metadataPool, "")
 		if err != nil {
 			return errors.Wrapf(err, "failed to create metadata pool %q", metadataPool.Name)
@@ -248,6 +264,9 @@ func (f *Filesystem) doFilesystemCreate(context *clusterd.Context, clusterInfo *
 	for i, dataPool := range spec.DataPools {
 		dataPool.Name = dataPoolNames[i]
 		if _, poolFound := reversedPoolMap[dataPool.Name]; !poolFound {
+			if err := cephpool.ValidateNewPoolCapacity(context, clusterInfo, dataPool); err != nil {
+				return err
+			}
 			err = cephclient.CreatePool(context, clusterInfo, clusterSpec, dataPool, "")
 			if err != nil {
 				return errors.Wrapf(err, "failed to create data pool %q", dataPool.Name)
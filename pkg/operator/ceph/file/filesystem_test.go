@@ -46,7 +46,7 @@ func TestValidateSpec(t *testing.T) {
 	context := &clusterd.Context{Executor: &exectest.MockExecutor{}}
 	clusterInfo := &cephclient.ClusterInfo{Namespace: "ns"}
 	fs := &cephv1.CephFilesystem{}
-	clusterSpec := &cephv1.ClusterSpec{}
+	clusterSpec := &cephv1.ClusterSpec{AllowUnsafePools: true}
 
 	// missing name
 	assert.NotNil(t, validateFilesystem(context, clusterInfo, clusterSpec, fs))
@@ -338,7 +338,7 @@ func TestCreateFilesystem(t *testing.T) {
 
 	t.Run("start basic filesystem", func(t *testing.T) {
 		// start a basic cluster
-		err := createFilesystem(context, clusterInfo, fs, &cephv1.ClusterSpec{}, ownerInfo, "/var/lib/rook/")
+		err := createFilesystem(context, clusterInfo, fs, &cephv1.ClusterSpec{AllowUnsafePools: true}, ownerInfo, "/var/lib/rook/")
 		assert.Nil(t, err)
 		validateStart(ctx, t, context, fs)
 		assert.ElementsMatch(t, []string{}, testopk8s.DeploymentNamesUpdated(deploymentsUpdated))
@@ -346,7 +346,7 @@ func TestCreateFilesystem(t *testing.T) {
 	})
 
 	t.Run("start again should no-op", func(t *testing.T) {
-		err := createFilesystem(context, clusterInfo, fs, &cephv1.ClusterSpec{}, ownerInfo, "/var/lib/rook/")
+		err := createFilesystem(context, clusterInfo, fs, &cephv1.ClusterSpec{AllowUnsafePools: true}, ownerInfo, "/var/lib/rook/")
 		assert.Nil(t, err)
 		validateStart(ctx, t, context, fs)
 		assert.ElementsMatch(t, []string{fmt.Sprintf("rook-ceph-mds-%s-a", fsName), fmt.Sprintf("rook-ceph-mds-%s-b", fsName)}, testopk8s.DeploymentNamesUpdated(deploymentsUpdated))
@@ -367,7 +367,7 @@ func TestCreateFilesystem(t *testing.T) {
 			Name:     "named-pool",
 			PoolSpec: cephv1.PoolSpec{Replicated: cephv1.ReplicatedSpec{Size: 1, RequireSafeReplicaSize: false}},
 		})
-		err := createFilesystem(context, clusterInfo, fs, &cephv1.ClusterSpec{}, ownerInfo, "/var/lib/rook/")
+		err := createFilesystem(context, clusterInfo, fs, &cephv1.ClusterSpec{AllowUnsafePools: true}, ownerInfo, "/var/lib/rook/")
 		assert.Nil(t, err)
 		validateStart(ctx, t, context, fs)
 		assert.ElementsMatch(t, []string{fmt.Sprintf("rook-ceph-mds-%s-a", fsName), fmt.Sprintf("rook-ceph-mds-%s-b", fsName)}, testopk8s.DeploymentNamesUpdated(deploymentsUpdated))
@@ -378,7 +378,7 @@ func TestCreateFilesystem(t *testing.T) {
 
 	t.Run("multi filesystem creation should succeed", func(t *testing.T) {
 		clusterInfo.CephVersion = version.Pacific
-		err := createFilesystem(context, clusterInfo, fs, &cephv1.ClusterSpec{}, ownerInfo, "/var/lib/rook/")
+		err := createFilesystem(context, clusterInfo, fs, &cephv1.ClusterSpec{AllowUnsafePools: true}, ownerInfo, "/var/lib/rook/")
 		assert.NoError(t, err)
 	})
 }
@@ -403,7 +403,7 @@ func TestUpgradeFilesystem(t *testing.T) {
 
 	// start a basic cluster for upgrade
 	ownerInfo := cephclient.NewMinimumOwnerInfoWithOwnerRef()
-	err := createFilesystem(context, clusterInfo, fs, &cephv1.ClusterSpec{}, ownerInfo, "/var/lib/rook/")
+	err := createFilesystem(context, clusterInfo, fs, &cephv1.ClusterSpec{AllowUnsafePools: true}, ownerInfo, "/var/lib/rook/")
 	assert.NoError(t, err)
 	validateStart(ctx, t, context, fs)
 	assert.ElementsMatch(t, []string{}, testopk8s.DeploymentNamesUpdated(deploymentsUpdated))
@@ -416,7 +416,7 @@ func TestUpgradeFilesystem(t *testing.T) {
 		ConfigDir: configDir,
 		Clientset: clientset,
 	}
-	err = createFilesystem(context, clusterInfo, fs, &cephv1.ClusterSpec{}, ownerInfo, "/var/lib/rook/")
+	err = createFilesystem(context, clusterInfo, fs, &cephv1.ClusterSpec{AllowUnsafePools: true}, ownerInfo, "/var/lib/rook/")
 	assert.NoError(t, err)
 
 	// test fail standby daemon failed
@@ -500,7 +500,7 @@ func TestUpgradeFilesystem(t *testing.T) {
 		ConfigDir: configDir,
 		Clientset: clientset,
 	}
-	err = createFilesystem(context, clusterInfo, fs, &cephv1.ClusterSpec{}, ownerInfo, "/var/lib/rook/")
+	err = createFilesystem(context, clusterInfo, fs, &cephv1.ClusterSpec{AllowUnsafePools: true}, ownerInfo, "/var/lib/rook/")
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "fail mds failed")
 }
@@ -536,12 +536,12 @@ func TestCreateNopoolFilesystem(t *testing.T) {
 
 	// start a basic cluster
 	ownerInfo := cephclient.NewMinimumOwnerInfoWithOwnerRef()
-	err := createFilesystem(context, clusterInfo, fs, &cephv1.ClusterSpec{}, ownerInfo, "/var/lib/rook/")
+	err := createFilesystem(context, clusterInfo, fs, &cephv1.ClusterSpec{AllowUnsafePools: true}, ownerInfo, "/var/lib/rook/")
 	assert.Nil(t, err)
 	validateStart(ctx, t, context, fs)
 
 	// starting again should be a no-op
-	err = createFilesystem(context, clusterInfo, fs, &cephv1.ClusterSpec{}, ownerInfo, "/var/lib/rook/")
+	err = createFilesystem(context, clusterInfo, fs, &cephv1.ClusterSpec{AllowUnsafePools: true}, ownerInfo, "/var/lib/rook/")
 	assert.Nil(t, err)
 	validateStart(ctx, t, context, fs)
 }
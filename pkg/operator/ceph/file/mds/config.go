@@ -69,11 +69,12 @@ func (c *Cluster) setDefaultFlagsMonConfigStore(mdsID string) error {
 	configOptions := make(map[string]string)
 
 	// Set mds cache memory limit to the best appropriate value
-	if !c.fs.Spec.MetadataServer.Resources.Limits.Memory().IsZero() {
-		mdsCacheMemoryLimit := float64(c.fs.Spec.MetadataServer.Resources.Limits.Memory().Value()) * mdsCacheMemoryLimitFactor
+	resources := c.mdsResources()
+	if !resources.Limits.Memory().IsZero() {
+		mdsCacheMemoryLimit := float64(resources.Limits.Memory().Value()) * mdsCacheMemoryLimitFactor
 		configOptions["mds_cache_memory_limit"] = strconv.Itoa(int(mdsCacheMemoryLimit))
-	} else if !c.fs.Spec.MetadataServer.Resources.Requests.Memory().IsZero() {
-		mdsCacheMemoryRequest := float64(c.fs.Spec.MetadataServer.Resources.Requests.Memory().Value()) * mdsCacheMemoryResourceFactor
+	} else if !resources.Requests.Memory().IsZero() {
+		mdsCacheMemoryRequest := float64(resources.Requests.Memory().Value()) * mdsCacheMemoryResourceFactor
 		configOptions["mds_cache_memory_limit"] = strconv.Itoa(int(mdsCacheMemoryRequest))
 	}
 
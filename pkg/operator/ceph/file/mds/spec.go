@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/clusterd"
 	cephconfig "github.com/rook/rook/pkg/operator/ceph/config"
 	"github.com/rook/rook/pkg/operator/ceph/controller"
@@ -59,8 +60,9 @@ func (c *Cluster) makeDeployment(mdsConfig *mdsConfig, namespace string) (*apps.
 			Containers: []v1.Container{
 				mdsContainer,
 			},
-			RestartPolicy:     v1.RestartPolicyAlways,
-			Volumes:           controller.DaemonVolumes(mdsConfig.DataPathMap, mdsConfig.ResourceName, c.clusterSpec.DataDirHostPath),
+			RestartPolicy: v1.RestartPolicyAlways,
+			Volumes: append(controller.DaemonVolumes(mdsConfig.DataPathMap, mdsConfig.ResourceName, c.clusterSpec.DataDirHostPath),
+				controller.HardenedDaemonVolumes(c.clusterSpec, mdsConfig.DataPathMap)...),
 			HostNetwork:       c.clusterSpec.Network.IsHost(),
 			PriorityClassName: c.fs.Spec.MetadataServer.PriorityClassName,
 		},
@@ -76,9 +78,9 @@ func (c *Cluster) makeDeployment(mdsConfig *mdsConfig, namespace string) (*apps.
 		podSpec.Spec.Containers = append(podSpec.Spec.Containers, *controller.LogCollectorContainer(fmt.Sprintf("ceph-mds.%s", mdsConfig.DaemonID), c.clusterInfo.Namespace, *c.clusterSpec))
 	}
 
-	c.fs.Spec.MetadataServer.Annotations.ApplyToObjectMeta(&podSpec.ObjectMeta)
+	c.applyMdsAnnotations(&podSpec.ObjectMeta)
 	c.fs.Spec.MetadataServer.Labels.ApplyToObjectMeta(&podSpec.ObjectMeta)
-	c.fs.Spec.MetadataServer.Placement.ApplyToPodSpec(&podSpec.Spec)
+	c.mdsPlacement().ApplyToPodSpec(&podSpec.Spec)
 
 	replicas := int32(1)
 	d := &apps.Deployment{
@@ -108,7 +110,7 @@ func (c *Cluster) makeDeployment(mdsConfig *mdsConfig, namespace string) (*apps.
 	}
 
 	k8sutil.AddRookVersionLabelToDeployment(d)
-	c.fs.Spec.MetadataServer.Annotations.ApplyToObjectMeta(&d.ObjectMeta)
+	c.applyMdsAnnotations(&d.ObjectMeta)
 	c.fs.Spec.MetadataServer.Labels.ApplyToObjectMeta(&d.ObjectMeta)
 	controller.AddCephVersionLabelToDeployment(c.clusterInfo.CephVersion, d)
 
@@ -120,16 +122,18 @@ func (c *Cluster) makeChownInitContainer(mdsConfig *mdsConfig) v1.Container {
 		*mdsConfig.DataPathMap,
 		c.clusterSpec.CephVersion.Image,
 		controller.GetContainerImagePullPolicy(c.clusterSpec.CephVersion.ImagePullPolicy),
-		controller.DaemonVolumeMounts(mdsConfig.DataPathMap, mdsConfig.ResourceName, c.clusterSpec.DataDirHostPath),
-		c.fs.Spec.MetadataServer.Resources,
-		controller.PodSecurityContext(),
+		append(controller.DaemonVolumeMounts(mdsConfig.DataPathMap, mdsConfig.ResourceName, c.clusterSpec.DataDirHostPath),
+			controller.HardenedDaemonVolumeMounts(c.clusterSpec, mdsConfig.DataPathMap)...),
+		c.mdsResources(),
+		controller.ChownSecurityContext(c.clusterSpec),
 		"",
 	)
 }
 
 func (c *Cluster) makeMdsDaemonContainer(mdsConfig *mdsConfig) v1.Container {
+	securityContext := controller.DaemonSecurityContext(c.clusterSpec, false)
 	args := append(
-		controller.DaemonFlags(c.clusterInfo, c.clusterSpec, mdsConfig.DaemonID),
+		controller.HardenedDaemonFlags(controller.DaemonFlags(c.clusterInfo, c.clusterSpec, mdsConfig.DaemonID), securityContext),
 		"--foreground",
 	)
 
@@ -146,10 +150,11 @@ func (c *Cluster) makeMdsDaemonContainer(mdsConfig *mdsConfig) v1.Container {
 		Args:            args,
 		Image:           c.clusterSpec.CephVersion.Image,
 		ImagePullPolicy: controller.GetContainerImagePullPolicy(c.clusterSpec.CephVersion.ImagePullPolicy),
-		VolumeMounts:    controller.DaemonVolumeMounts(mdsConfig.DataPathMap, mdsConfig.ResourceName, c.clusterSpec.DataDirHostPath),
+		VolumeMounts: append(controller.DaemonVolumeMounts(mdsConfig.DataPathMap, mdsConfig.ResourceName, c.clusterSpec.DataDirHostPath),
+			controller.HardenedDaemonVolumeMounts(c.clusterSpec, mdsConfig.DataPathMap)...),
 		Env:             append(controller.DaemonEnvVars(c.clusterSpec.CephVersion.Image), k8sutil.PodIPEnvVar(podIPEnvVar)),
-		Resources:       c.fs.Spec.MetadataServer.Resources,
-		SecurityContext: controller.PodSecurityContext(),
+		Resources:       c.mdsResources(),
+		SecurityContext: securityContext,
 		StartupProbe:    controller.GenerateStartupProbeExecDaemon(cephconfig.MdsType, mdsConfig.DaemonID),
 		LivenessProbe:   controller.GenerateLivenessProbeExecDaemon(cephconfig.MdsType, mdsConfig.DaemonID),
 		WorkingDir:      cephconfig.VarLogCephDir,
@@ -204,3 +209,20 @@ func scaleMdsDeployment(ctx context.Context, clusterdContext *clusterd.Context,
 	}
 	return nil
 }
+
+// mdsPlacement returns the placement of the mds daemons, merged over the default mds placement of the cluster
+func (c *Cluster) mdsPlacement() cephv1.Placement {
+	return cephv1.GetMdsPlacement(c.clusterSpec.Placement).Merge(c.fs.Spec.MetadataServer.Placement)
+}
+
+// mdsResources returns the resources of the mds daemons, or the default mds resources of the cluster
+func (c *Cluster) mdsResources() v1.ResourceRequirements {
+	return cephv1.InheritResources(c.fs.Spec.MetadataServer.Resources, cephv1.GetMdsResources(c.clusterSpec.Resources))
+}
+
+// applyMdsAnnotations adds the annotations of the mds daemons, and the default mds annotations of the cluster
+// that the filesystem does not override
+func (c *Cluster) applyMdsAnnotations(meta *metav1.ObjectMeta) {
+	c.fs.Spec.MetadataServer.Annotations.ApplyToObjectMeta(meta)
+	cephv1.GetMdsAnnotations(c.clusterSpec.Annotations).ApplyToObjectMeta(meta)
+}
@@ -89,7 +89,7 @@ type ReconcileCephNFS struct {
 // Add creates a new cephNFS Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
-	return add(mgr, newReconciler(mgr, context, opManagerContext, opConfig))
+	return add(opManagerContext, mgr, newReconciler(mgr, context, opManagerContext, opConfig))
 }
 
 // newReconciler returns a new reconcile.Reconciler
@@ -104,7 +104,7 @@ func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerCont
 	}
 }
 
-func add(mgr manager.Manager, r reconcile.Reconciler) error {
+func add(opManagerContext context.Context, mgr manager.Manager, r reconcile.Reconciler) error {
 	// Create a new controller
 	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
 	if err != nil {
@@ -129,6 +129,16 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		}
 	}
 
+	// Watch for changes on the default nfs settings of the CephCluster, which the CephNFSes of the cluster inherit
+	handlerFunc, err := opcontroller.ObjectToCRInNamespaceMapper(opManagerContext, mgr.GetClient(), &cephv1.CephNFSList{}, mgr.GetScheme())
+	if err != nil {
+		return err
+	}
+	err = c.Watch(&source.Kind{Type: &cephv1.CephCluster{}}, handler.EnqueueRequestsFromMapFunc(handlerFunc), opcontroller.PredicateClusterDefaultsChanged(cephv1.KeyNFS))
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -277,6 +287,7 @@ func (r *ReconcileCephNFS) reconcile(request reconcile.Request) (reconcile.Resul
 
 	// validate the store settings
 	if err := validateGanesha(r.context, r.clusterInfo, cephNFS); err != nil {
+		reporting.ReportValidationFailed(r.recorder, cephNFS, err)
 		return reconcile.Result{}, *cephNFS, errors.Wrapf(err, "invalid ceph nfs %q arguments", cephNFS.Name)
 	}
 
@@ -222,7 +222,7 @@ cat /tmp/etc/krb5.conf`,
 			{Name: generatedKrbConfVolName, MountPath: "/tmp/etc"},
 		},
 		Image:     r.cephClusterSpec.CephVersion.Image,
-		Resources: nfs.Spec.Server.Resources,
+		Resources: r.serverResources(nfs),
 	}
 
 	return init, volume, ganeshaMount
@@ -302,7 +302,7 @@ cat /tmp/etc/nsswitch.conf`,
 		// use CephCluster image and NFS server resources here because this container should be used
 		// to configure /etc/nsswitch.conf even if the SSSD sidecar isn't configured
 		Image:     r.cephClusterSpec.CephVersion.Image,
-		Resources: nfs.Spec.Server.Resources,
+		Resources: r.serverResources(nfs),
 	}
 
 	return init, podVol, nfsGaneshaContainerMount
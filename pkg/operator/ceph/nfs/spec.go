@@ -109,7 +109,7 @@ func (r *ReconcileCephNFS) makeDeployment(nfs *cephv1.CephNFS, cfg daemonConfig)
 
 	k8sutil.AddRookVersionLabelToDeployment(deployment)
 	controller.AddCephVersionLabelToDeployment(r.clusterInfo.CephVersion, deployment)
-	nfs.Spec.Server.Annotations.ApplyToObjectMeta(&deployment.ObjectMeta)
+	r.applyServerAnnotations(nfs, &deployment.ObjectMeta)
 	nfs.Spec.Server.Labels.ApplyToObjectMeta(&deployment.ObjectMeta)
 
 	cephConfigVol, _ := cephConfigVolumeAndMount()
@@ -152,7 +152,7 @@ func (r *ReconcileCephNFS) makeDeployment(nfs *cephv1.CephNFS, cfg daemonConfig)
 	if hostNetwork {
 		podSpec.DNSPolicy = v1.DNSClusterFirstWithHostNet
 	}
-	nfs.Spec.Server.Placement.ApplyToPodSpec(&podSpec)
+	r.serverPlacement(nfs).ApplyToPodSpec(&podSpec)
 
 	if err := r.addSecurityConfigsToPod(nfs, &podSpec); err != nil {
 		return nil, err
@@ -180,7 +180,7 @@ func (r *ReconcileCephNFS) makeDeployment(nfs *cephv1.CephNFS, cfg daemonConfig)
 		}
 	}
 
-	nfs.Spec.Server.Annotations.ApplyToObjectMeta(&podTemplateSpec.ObjectMeta)
+	r.applyServerAnnotations(nfs, &podTemplateSpec.ObjectMeta)
 	nfs.Spec.Server.Labels.ApplyToObjectMeta(&podTemplateSpec.ObjectMeta)
 
 	// Multiple replicas of the nfs service would be handled by creating a service and a new deployment for each one, rather than increasing the pod count here
@@ -208,7 +208,7 @@ func (r *ReconcileCephNFS) connectionConfigInitContainer(nfs *cephv1.CephNFS, na
 			cephConfigMount,
 			keyring.VolumeMount().Resource(instanceName(nfs, name)),
 		},
-		nfs.Spec.Server.Resources,
+		r.serverResources(nfs),
 		controller.PodSecurityContext(),
 	)
 }
@@ -242,7 +242,7 @@ func (r *ReconcileCephNFS) daemonContainer(nfs *cephv1.CephNFS, cfg daemonConfig
 			dbusMount,
 		},
 		Env:             controller.DaemonEnvVars(r.cephClusterSpec.CephVersion.Image),
-		Resources:       nfs.Spec.Server.Resources,
+		Resources:       r.serverResources(nfs),
 		SecurityContext: controller.PodSecurityContext(),
 	}
 }
@@ -267,10 +267,27 @@ func (r *ReconcileCephNFS) dbusContainer(nfs *cephv1.CephNFS) v1.Container {
 			dbusMount,
 		},
 		Env:       k8sutil.ClusterDaemonEnvVars(r.cephClusterSpec.CephVersion.Image), // do not need access to Ceph env vars b/c not a Ceph daemon
-		Resources: nfs.Spec.Server.Resources,
+		Resources: r.serverResources(nfs),
 	}
 }
 
+// serverPlacement returns the placement of the NFS servers, merged over the default NFS placement of the cluster
+func (r *ReconcileCephNFS) serverPlacement(nfs *cephv1.CephNFS) cephv1.Placement {
+	return cephv1.GetNFSPlacement(r.cephClusterSpec.Placement).Merge(nfs.Spec.Server.Placement)
+}
+
+// serverResources returns the resources of the NFS servers, or the default NFS resources of the cluster
+func (r *ReconcileCephNFS) serverResources(nfs *cephv1.CephNFS) v1.ResourceRequirements {
+	return cephv1.InheritResources(nfs.Spec.Server.Resources, cephv1.GetNFSResources(r.cephClusterSpec.Resources))
+}
+
+// applyServerAnnotations adds the annotations of the NFS servers, and the default NFS annotations of the cluster
+// that the CephNFS does not override
+func (r *ReconcileCephNFS) applyServerAnnotations(nfs *cephv1.CephNFS, meta *metav1.ObjectMeta) {
+	nfs.Spec.Server.Annotations.ApplyToObjectMeta(meta)
+	cephv1.GetNFSAnnotations(r.cephClusterSpec.Annotations).ApplyToObjectMeta(meta)
+}
+
 func getLabels(n *cephv1.CephNFS, name string, includeNewLabels bool) map[string]string {
 	labels := controller.CephDaemonAppLabels(AppName, n.Namespace, "nfs", n.Name+"-"+name, n.Name, "cephnfses.ceph.rook.io", includeNewLabels)
 	labels[CephNFSNameLabelKey] = n.Name
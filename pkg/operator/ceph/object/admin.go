@@ -23,6 +23,7 @@ import (
 	"net/http/httputil"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/ceph/go-ceph/rgw/admin"
 	"github.com/coreos/pkg/capnslog"
@@ -235,6 +236,38 @@ func RunAdminCommandNoMultisite(c *Context, expectJSON bool, args ...string) (st
 	return output, nil
 }
 
+// RunAdminCommandWithStdin runs a radosgw-admin command in the zone of the store that reads its input from stdin,
+// such as "zone set"
+func RunAdminCommandWithStdin(c *Context, stdin string, args ...string) error {
+	// the proxy container of the multus network does not forward stdin
+	if c.CephClusterSpec.Network.IsMultus() {
+		return errors.Errorf("radosgw-admin %q cannot be run with a multus network", strings.Join(args, " "))
+	}
+	args = append(args,
+		fmt.Sprintf("--rgw-realm=%s", c.Realm),
+		fmt.Sprintf("--rgw-zonegroup=%s", c.ZoneGroup),
+		fmt.Sprintf("--rgw-zone=%s", c.Zone),
+	)
+	command, args := cephclient.FinalizeCephCommandArgs("radosgw-admin", c.clusterInfo, args, c.Context.ConfigDir)
+	return c.Context.Executor.ExecuteCommandWithStdin(exec.CephCommandsTimeout, command, &stdin, args...)
+}
+
+// RunAdminCommandWithTimeout runs a radosgw-admin command in the zone of the store with the timeout, for the commands
+// that take longer than the other commands, such as the scan of the orphan objects of a pool
+func RunAdminCommandWithTimeout(c *Context, timeout time.Duration, args ...string) (string, error) {
+	// the commands run in the proxy container of the multus network are bound to its own timeout
+	if c.CephClusterSpec.Network.IsMultus() {
+		return "", errors.Errorf("radosgw-admin %q cannot be run with a multus network", strings.Join(args, " "))
+	}
+	args = append(args,
+		fmt.Sprintf("--rgw-realm=%s", c.Realm),
+		fmt.Sprintf("--rgw-zonegroup=%s", c.ZoneGroup),
+		fmt.Sprintf("--rgw-zone=%s", c.Zone),
+	)
+	command, args := cephclient.FinalizeCephCommandArgs("radosgw-admin", c.clusterInfo, args, c.Context.ConfigDir)
+	return c.Context.Executor.ExecuteCommandWithTimeout(timeout, command, args...)
+}
+
 // This function is for running radosgw-admin commands in scenarios where an object-store has been created and the Context has been updated with the appropriate realm, zone group, and zone.
 func runAdminCommand(c *Context, expectJSON bool, args ...string) (string, error) {
 	// If the objectStoreName is not passed in the storage class
@@ -0,0 +1,128 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"syscall"
+
+	"github.com/pkg/errors"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/util/exec"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// checkAdoptedZone returns an error if the realm, zone group or zone adopted by the store do not exist, since the
+// store must not create them. The pools of the zone are not created either: the missing ones are only reported,
+// since the gateways create the pools they need on demand.
+func checkAdoptedZone(objContext *Context) error {
+	realmArg := fmt.Sprintf("--rgw-realm=%s", objContext.Realm)
+	zoneGroupArg := fmt.Sprintf("--rgw-zonegroup=%s", objContext.ZoneGroup)
+
+	checks := []struct {
+		kind, name string
+		args       []string
+	}{
+		{"realm", objContext.Realm, []string{"realm", "get", realmArg}},
+		{"zone group", objContext.ZoneGroup, []string{"zonegroup", "get", realmArg, zoneGroupArg}},
+		{"zone", objContext.Zone, []string{"zone", "get", realmArg, zoneGroupArg, fmt.Sprintf("--rgw-zone=%s", objContext.Zone)}},
+	}
+	output := ""
+	for _, check := range checks {
+		var err error
+		output, err = RunAdminCommandNoMultisite(objContext, true, check.args...)
+		if err != nil {
+			// ENOENT means "No such file or directory"
+			if code, err := exec.ExtractExitCode(err); err == nil && code == int(syscall.ENOENT) {
+				return errors.Errorf("cannot adopt %s %q since it does not exist", check.kind, check.name)
+			}
+			return errorOrIsNotFound(err, "failed to get %s %q to adopt, for reason %q", check.kind, check.name, output)
+		}
+	}
+
+	pools, err := zonePools(output)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the pools of zone %q", objContext.Zone)
+	}
+	summaries, err := cephclient.ListPoolSummaries(objContext.Context, objContext.clusterInfo)
+	if err != nil {
+		return errors.Wrap(err, "failed to list pools")
+	}
+	existing := sets.New[string]()
+	for _, summary := range summaries {
+		existing.Insert(summary.Name)
+	}
+	missing := []string{}
+	for _, pool := range pools {
+		if !existing.Has(pool) {
+			missing = append(missing, pool)
+		}
+	}
+	if len(missing) > 0 {
+		logger.Infof("pools %v of adopted zone %q do not exist yet and will be created by the gateways on demand", missing, objContext.Zone)
+	}
+
+	logger.Infof("adopted realm %q, zone group %q and zone %q", objContext.Realm, objContext.ZoneGroup, objContext.Zone)
+	return nil
+}
+
+// zonePools returns the sorted names of the pools used by the zone config, as returned by "radosgw-admin zone get".
+// The rados namespaces of the pools, e.g. "default.rgw.log:gc", are dropped.
+func zonePools(zoneGetOutput string) ([]string, error) {
+	zoneConfig := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(zoneGetOutput), &zoneConfig); err != nil {
+		return nil, errors.Wrap(err, "failed to parse `radosgw-admin zone get` output")
+	}
+
+	pools := sets.New[string]()
+	add := func(value interface{}) {
+		if pool, ok := value.(string); ok && pool != "" {
+			pools.Insert(strings.SplitN(pool, ":", 2)[0])
+		}
+	}
+
+	// the metadata pools of the zone, e.g. "domain_root", "control_pool" and "log_pool"
+	for key, value := range zoneConfig {
+		if key == "domain_root" || strings.HasSuffix(key, "_pool") {
+			add(value)
+		}
+	}
+
+	placements, _ := zoneConfig["placement_pools"].([]interface{})
+	for _, p := range placements {
+		placement, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		val, ok := placement["val"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		add(val["index_pool"])
+		add(val["data_extra_pool"])
+		classes, _ := val["storage_classes"].(map[string]interface{})
+		for _, c := range classes {
+			if class, ok := c.(map[string]interface{}); ok {
+				add(class["data_pool"])
+			}
+		}
+	}
+
+	return sets.List(pools), nil
+}
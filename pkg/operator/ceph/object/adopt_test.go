@@ -0,0 +1,83 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZonePools(t *testing.T) {
+	pools, err := zonePools(zoneGetOutput)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"my-store.rgw.buckets.data",
+		"my-store.rgw.buckets.index",
+		"my-store.rgw.buckets.non-ec",
+		"my-store.rgw.control",
+		"my-store.rgw.log",
+		"my-store.rgw.meta",
+		"my-store.rgw.otp",
+	}, pools)
+
+	_, err = zonePools("not json")
+	assert.Error(t, err)
+}
+
+func TestCheckAdoptedZone(t *testing.T) {
+	missing := ""
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			if args[0] == missing {
+				return "", exectest.MockExecCommandReturns(t, "", "", int(syscall.ENOENT))
+			}
+			if args[0] == "zone" && args[1] == "get" {
+				return zoneGetOutput, nil
+			}
+			return "{}", nil
+		},
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "osd" && args[1] == "lspools" {
+				return `[{"poolnum":1,"poolname":"my-store.rgw.meta"},{"poolnum":2,"poolname":"my-store.rgw.log"}]`, nil
+			}
+			return "", nil
+		},
+	}
+	clusterInfo := client.AdminTestClusterInfo("rook-ceph")
+	clusterInfo.Context = context.TODO()
+	objContext := NewContext(&clusterd.Context{Executor: executor}, clusterInfo, "my-store")
+	objContext.Realm = "default"
+	objContext.ZoneGroup = "default"
+	objContext.Zone = "my-store"
+
+	// the missing pools of the zone are left to the gateways
+	assert.NoError(t, checkAdoptedZone(objContext))
+
+	for _, kind := range []string{"realm", "zonegroup", "zone"} {
+		missing = kind
+		err := checkAdoptedZone(objContext)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "does not exist")
+	}
+}
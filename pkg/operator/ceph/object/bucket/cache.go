@@ -0,0 +1,159 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bucket
+
+import (
+	"sync"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// adminOpsCredentialsTTL is how long the credentials of the admin ops user of an object store are reused before
+// they are read again, which also picks up the rotated credentials
+var adminOpsCredentialsTTL = 5 * time.Minute
+
+type adminOpsCredentials struct {
+	storeUID  types.UID
+	accessKey string
+	secretKey string
+	expiry    time.Time
+}
+
+// adminOpsCredentialCache caches the credentials of the admin ops users of the object stores. Without the cache, every
+// reconcile of every OBC reads the credentials with radosgw-admin, which does not scale to thousands of OBCs.
+type adminOpsCredentialCache struct {
+	mutex       sync.Mutex
+	credentials map[types.NamespacedName]adminOpsCredentials
+}
+
+func newAdminOpsCredentialCache() *adminOpsCredentialCache {
+	return &adminOpsCredentialCache{credentials: map[types.NamespacedName]adminOpsCredentials{}}
+}
+
+// get returns the cached credentials of the store, unless they expired or the store was recreated since
+func (c *adminOpsCredentialCache) get(store *cephv1.CephObjectStore) (string, string, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	creds, ok := c.credentials[types.NamespacedName{Namespace: store.Namespace, Name: store.Name}]
+	if !ok || creds.storeUID != store.UID || time.Now().After(creds.expiry) {
+		return "", "", false
+	}
+	return creds.accessKey, creds.secretKey, true
+}
+
+func (c *adminOpsCredentialCache) set(store *cephv1.CephObjectStore, accessKey, secretKey string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.credentials[types.NamespacedName{Namespace: store.Namespace, Name: store.Name}] = adminOpsCredentials{
+		storeUID:  store.UID,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		expiry:    time.Now().Add(adminOpsCredentialsTTL),
+	}
+}
+
+// invalidate drops the cached credentials of the store, e.g. after the admin ops api rejected them
+func (c *adminOpsCredentialCache) invalidate(namespace, name string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.credentials, types.NamespacedName{Namespace: namespace, Name: name})
+}
+
+// bucketListTTL is how long the bulk listing of the buckets of an object store is reused before the buckets are listed
+// again. The listing is resynced periodically like an informer, since the admin ops api cannot watch the buckets.
+var bucketListTTL = 1 * time.Minute
+
+type storeBuckets struct {
+	storeUID types.UID
+	// the owners of the buckets by name, or "" when the owner was not read yet
+	owners map[string]string
+	expiry time.Time
+}
+
+// bucketCache caches the buckets of the object stores. Without the cache, every reconcile of every OBC reads its bucket
+// with the admin ops api, while a single listing of the buckets of the store answers most of the reconciles.
+type bucketCache struct {
+	mutex  sync.Mutex
+	stores map[types.NamespacedName]*storeBuckets
+}
+
+func newBucketCache() *bucketCache {
+	return &bucketCache{stores: map[types.NamespacedName]*storeBuckets{}}
+}
+
+// listed returns the buckets of the store, unless they were not listed recently or the store was recreated since
+func (c *bucketCache) listed(store *cephv1.CephObjectStore) (*storeBuckets, bool) {
+	buckets, ok := c.stores[types.NamespacedName{Namespace: store.Namespace, Name: store.Name}]
+	if !ok || buckets.storeUID != store.UID || time.Now().After(buckets.expiry) {
+		return nil, false
+	}
+	return buckets, true
+}
+
+// get returns the owner of the bucket if the bucket is in the listing of the store, and whether the listing is valid
+func (c *bucketCache) get(store *cephv1.CephObjectStore, bucket string) (owner string, exists bool, valid bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	buckets, ok := c.listed(store)
+	if !ok {
+		return "", false, false
+	}
+	owner, exists = buckets.owners[bucket]
+	return owner, exists, true
+}
+
+// setListing replaces the buckets of the store with a new listing
+func (c *bucketCache) setListing(store *cephv1.CephObjectStore, names []string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	owners := make(map[string]string, len(names))
+	for _, name := range names {
+		owners[name] = ""
+	}
+	c.stores[types.NamespacedName{Namespace: store.Namespace, Name: store.Name}] = &storeBuckets{
+		storeUID: store.UID,
+		owners:   owners,
+		expiry:   time.Now().Add(bucketListTTL),
+	}
+}
+
+// add records a bucket created or read since the listing of the store
+func (c *bucketCache) add(store *cephv1.CephObjectStore, bucket, owner string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if buckets, ok := c.listed(store); ok {
+		buckets.owners[bucket] = owner
+	}
+}
+
+// remove drops a deleted bucket from the listing of the store
+func (c *bucketCache) remove(namespace, name, bucket string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if buckets, ok := c.stores[types.NamespacedName{Namespace: namespace, Name: name}]; ok {
+		delete(buckets.owners, bucket)
+	}
+}
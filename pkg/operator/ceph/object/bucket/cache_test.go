@@ -0,0 +1,132 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bucket
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	rookclient "github.com/rook/rook/pkg/client/clientset/versioned/fake"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAdminOpsCredentialCache(t *testing.T) {
+	ttl := adminOpsCredentialsTTL
+	defer func() { adminOpsCredentialsTTL = ttl }()
+
+	store := &cephv1.CephObjectStore{ObjectMeta: metav1.ObjectMeta{Name: "my-store", Namespace: "rook-ceph", UID: "uid-1"}}
+	c := newAdminOpsCredentialCache()
+
+	_, _, ok := c.get(store)
+	assert.False(t, ok)
+
+	c.set(store, "access", "secret")
+	accessKey, secretKey, ok := c.get(store)
+	assert.True(t, ok)
+	assert.Equal(t, "access", accessKey)
+	assert.Equal(t, "secret", secretKey)
+
+	// the credentials of a recreated store are read again
+	recreated := store.DeepCopy()
+	recreated.UID = "uid-2"
+	_, _, ok = c.get(recreated)
+	assert.False(t, ok)
+
+	c.invalidate(store.Namespace, store.Name)
+	_, _, ok = c.get(store)
+	assert.False(t, ok)
+
+	// the expired credentials are read again
+	adminOpsCredentialsTTL = -time.Second
+	c.set(store, "access", "secret")
+	_, _, ok = c.get(store)
+	assert.False(t, ok)
+}
+
+func TestGetAdminOpsCredentialsFromCache(t *testing.T) {
+	store := &cephv1.CephObjectStore{ObjectMeta: metav1.ObjectMeta{Name: "my-store", Namespace: "rook-ceph", UID: "uid-1"}}
+	cephCluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "rook-ceph"},
+		Spec:       cephv1.ClusterSpec{Network: cephv1.NetworkSpec{Provider: "multus"}},
+	}
+	clusterInfo := client.AdminTestClusterInfo("rook-ceph")
+	clusterInfo.Context = context.TODO()
+	ctx := &clusterd.Context{RookClientset: rookclient.NewSimpleClientset(cephCluster)}
+	p := Provisioner{
+		context:             ctx,
+		clusterInfo:         clusterInfo,
+		objectContext:       object.NewContext(ctx, clusterInfo, store.Name),
+		adminOpsCredentials: newAdminOpsCredentialCache(),
+	}
+	p.adminOpsCredentials.set(store, "access", "secret")
+
+	accessKey, secretKey, err := p.getAdminOpsCredentials(store)
+	assert.NoError(t, err)
+	assert.Equal(t, "access", accessKey)
+	assert.Equal(t, "secret", secretKey)
+	// the radosgw-admin commands still go through the multus proxy when the credentials are cached
+	assert.True(t, p.objectContext.CephClusterSpec.Network.IsMultus())
+}
+
+func TestBucketCache(t *testing.T) {
+	ttl := bucketListTTL
+	defer func() { bucketListTTL = ttl }()
+
+	store := &cephv1.CephObjectStore{ObjectMeta: metav1.ObjectMeta{Name: "my-store", Namespace: "rook-ceph", UID: "uid-1"}}
+	c := newBucketCache()
+
+	// nothing is cached before the buckets are listed
+	c.add(store, "bucket-a", "user-a")
+	_, _, valid := c.get(store, "bucket-a")
+	assert.False(t, valid)
+
+	c.setListing(store, []string{"bucket-a", "bucket-b"})
+	owner, exists, valid := c.get(store, "bucket-a")
+	assert.True(t, valid)
+	assert.True(t, exists)
+	assert.Equal(t, "", owner)
+	_, exists, _ = c.get(store, "bucket-c")
+	assert.False(t, exists)
+
+	// the buckets created or read since the listing
+	c.add(store, "bucket-c", "user-c")
+	owner, exists, _ = c.get(store, "bucket-c")
+	assert.True(t, exists)
+	assert.Equal(t, "user-c", owner)
+
+	c.remove(store.Namespace, store.Name, "bucket-a")
+	_, exists, _ = c.get(store, "bucket-a")
+	assert.False(t, exists)
+
+	// the buckets of a recreated store are listed again
+	recreated := store.DeepCopy()
+	recreated.UID = "uid-2"
+	_, _, valid = c.get(recreated, "bucket-b")
+	assert.False(t, valid)
+
+	// the expired listing is listed again
+	bucketListTTL = -time.Second
+	c.setListing(store, []string{"bucket-a"})
+	_, _, valid = c.get(store, "bucket-a")
+	assert.False(t, valid)
+}
@@ -17,20 +17,26 @@ limitations under the License.
 package bucket
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/ceph/go-ceph/rgw/admin"
 	"github.com/coreos/pkg/capnslog"
 	"github.com/kube-object-storage/lib-bucket-provisioner/pkg/apis/objectbucket.io/v1alpha1"
 	bktv1alpha1 "github.com/kube-object-storage/lib-bucket-provisioner/pkg/apis/objectbucket.io/v1alpha1"
 	apibkt "github.com/kube-object-storage/lib-bucket-provisioner/pkg/provisioner/api"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
 	"github.com/rook/rook/pkg/operator/ceph/object"
 	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/pkg/errors"
 	"github.com/rook/rook/pkg/clusterd"
@@ -39,12 +45,14 @@ import (
 )
 
 type Provisioner struct {
-	context         *clusterd.Context
-	objectContext   *object.Context
-	clusterInfo     *client.ClusterInfo
-	bucketName      string
-	storeDomainName string
-	storePort       int32
+	context       *clusterd.Context
+	objectContext *object.Context
+	clusterInfo   *client.ClusterInfo
+	bucketName    string
+	// the location constraint of the bucket, selecting its zonegroup and placement target
+	locationConstraint string
+	storeDomainName    string
+	storePort          int32
 	// access keys for acct for the bucket *owner*
 	cephUserName         string
 	accessKeyID          string
@@ -55,12 +63,18 @@ type Provisioner struct {
 	tlsCert              []byte
 	insecureTLS          bool
 	adminOpsClient       *admin.API
+	// the object store and ceph user read by the current reconcile, to read them only once per reconcile
+	objectStore *cephv1.CephObjectStore
+	cephUser    *admin.User
+	// shared by the reconciles of all the OBCs
+	adminOpsCredentials *adminOpsCredentialCache
+	buckets             *bucketCache
 }
 
 var _ apibkt.Provisioner = &Provisioner{}
 
 func NewProvisioner(context *clusterd.Context, clusterInfo *client.ClusterInfo) *Provisioner {
-	return &Provisioner{context: context, clusterInfo: clusterInfo}
+	return &Provisioner{context: context, clusterInfo: clusterInfo, adminOpsCredentials: newAdminOpsCredentialCache(), buckets: newBucketCache()}
 }
 
 func (p Provisioner) GenerateUserID(obc *v1alpha1.ObjectBucketClaim, ob *v1alpha1.ObjectBucket) (string, error) {
@@ -112,26 +126,31 @@ func (p Provisioner) Provision(options *apibkt.BucketOptions) (*bktv1alpha1.Obje
 		// if bucket already exists, this returns error: TooManyBuckets because we set the quota
 		// below. If it already exists, assume we are good to go
 		logger.Debugf("creating bucket %q", p.bucketName)
-		err = s3svc.CreateBucket(p.bucketName)
+		err = s3svc.CreateBucketInLocation(p.bucketName, p.locationConstraint)
 		if err != nil {
 			return nil, errors.Wrapf(err, "error creating bucket %q", p.bucketName)
 		}
+		p.cacheBucket(p.bucketName, p.cephUserName)
 	} else {
 		logger.Debugf("bucket %q already exists", p.bucketName)
 	}
 
 	singleBucketQuota := 1
-	_, err = p.adminOpsClient.ModifyUser(p.clusterInfo.Context, admin.User{ID: p.cephUserName, MaxBuckets: &singleBucketQuota})
+	err = p.setUserMaxBuckets(singleBucketQuota)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to set user %q bucket quota to %d", p.cephUserName, singleBucketQuota)
 	}
-	logger.Infof("set user %q bucket max to %d", p.cephUserName, singleBucketQuota)
 
 	err = p.setAdditionalSettings(options)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to set additional settings for OBC %q", options.ObjectBucketClaim.Name)
 	}
 
+	err = p.applyBucketSettings(s3svc, options, nil)
+	if err != nil {
+		return nil, err
+	}
+
 	return p.composeObjectBucket(), nil
 }
 
@@ -161,20 +180,20 @@ func (p Provisioner) Grant(options *apibkt.BucketOptions) (*bktv1alpha1.ObjectBu
 
 	// restrict creation of new buckets in rgw
 	restrictBucketCreation := 0
-	_, err = p.adminOpsClient.ModifyUser(p.clusterInfo.Context, admin.User{ID: p.cephUserName, MaxBuckets: &restrictBucketCreation})
+	err = p.setUserMaxBuckets(restrictBucketCreation)
 	if err != nil {
 		return nil, err
 	}
 
 	// get the bucket's owner via the bucket metadata
-	stats, err := p.adminOpsClient.GetBucketInfo(p.clusterInfo.Context, admin.Bucket{Bucket: p.bucketName})
+	owner, err := p.bucketOwner(p.bucketName)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to get bucket %q stats", p.bucketName)
+		return nil, err
 	}
 
-	objectUser, err := p.adminOpsClient.GetUser(p.clusterInfo.Context, admin.User{ID: stats.Owner})
+	objectUser, err := p.adminOpsClient.GetUser(p.clusterInfo.Context, admin.User{ID: owner})
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to get user %q", stats.Owner)
+		return nil, errors.Wrapf(err, "failed to get user %q", owner)
 	}
 
 	var s3svc *object.S3Agent
@@ -187,16 +206,7 @@ func (p Provisioner) Grant(options *apibkt.BucketOptions) (*bktv1alpha1.ObjectBu
 		return nil, err
 	}
 
-	// if the policy does not exist, we'll create a new and append the statement to it
-	policy, err := s3svc.GetBucketPolicy(p.bucketName)
-	if err != nil {
-		if aerr, ok := err.(awserr.Error); ok {
-			if aerr.Code() != "NoSuchBucketPolicy" {
-				return nil, err
-			}
-		}
-	}
-
+	// the statement granting the user of the OBC access to the bucket is added to the policy of the bucket
 	statement := object.NewPolicyStatement().
 		WithSID(p.cephUserName).
 		ForPrincipals(p.cephUserName).
@@ -204,20 +214,14 @@ func (p Provisioner) Grant(options *apibkt.BucketOptions) (*bktv1alpha1.ObjectBu
 		ForSubResources(p.bucketName).
 		Allows().
 		Actions(object.AllowedActions...)
-	if policy == nil {
-		policy = object.NewBucketPolicy(*statement)
-	} else {
-		policy = policy.ModifyBucketPolicy(*statement)
-	}
-	out, err := s3svc.PutBucketPolicy(p.bucketName, *policy)
 
-	logger.Infof("PutBucketPolicy output: %v", out)
+	// setting quota limit if it is enabled
+	err = p.setAdditionalSettings(options)
 	if err != nil {
 		return nil, err
 	}
 
-	// setting quota limit if it is enabled
-	err = p.setAdditionalSettings(options)
+	err = p.applyBucketSettings(s3svc, options, statement)
 	if err != nil {
 		return nil, err
 	}
@@ -354,6 +358,14 @@ func (p *Provisioner) initializeCreateOrGrant(options *apibkt.BucketOptions) err
 		logger.Errorf("failed to get storage class for OBC %q in namespace %q. %v", obc.Name, obc.Namespace, err)
 		return err
 	}
+	allowed, err := isNamespaceAllowed(sc, obc.Namespace)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return errors.Errorf("OBC %q in namespace %q is not allowed to use storage class %q, which is restricted to namespaces %q",
+			obc.Name, obc.Namespace, sc.Name, sc.Parameters[allowedNamespacesParam])
+	}
 
 	// In most cases we assume the bucket is to be generated dynamically.  When a storage class
 	// defines the bucket in the parameters, it's assumed to be a request to connect to a statically
@@ -362,6 +374,12 @@ func (p *Provisioner) initializeCreateOrGrant(options *apibkt.BucketOptions) err
 	p.setBucketName(options.BucketName)
 	if bucketName, isStatic := isStaticBucket(sc); isStatic {
 		p.setBucketName(bucketName)
+	} else {
+		err = p.setBucketNamePrefix(sc)
+		if err != nil {
+			return err
+		}
+		p.locationConstraint = getLocationConstraint(sc)
 	}
 
 	p.setObjectStoreName(sc)
@@ -532,6 +550,21 @@ func (p *Provisioner) setBucketName(name string) {
 	p.bucketName = name
 }
 
+// setBucketNamePrefix prepends the prefix of the StorageClass to the name of a dynamically provisioned bucket,
+// unless the name requested by the OBC already starts with it
+func (p *Provisioner) setBucketNamePrefix(sc *storagev1.StorageClass) error {
+	prefix := getBucketNamePrefix(sc)
+	if prefix == "" || strings.HasPrefix(p.bucketName, prefix) {
+		return nil
+	}
+	name := prefix + p.bucketName
+	if len(name) > maxBucketNameLength {
+		return errors.Errorf("bucket name %q with prefix %q of storage class %q exceeds the maximum length of %d characters", p.bucketName, prefix, sc.Name, maxBucketNameLength)
+	}
+	p.setBucketName(name)
+	return nil
+}
+
 func (p *Provisioner) setAdditionalConfigData(additionalConfigData map[string]string) {
 	if len(additionalConfigData) == 0 {
 		additionalConfigData = make(map[string]string)
@@ -599,9 +632,14 @@ func (p *Provisioner) setAdditionalSettings(options *apibkt.BucketOptions) error
 		}
 	}
 
-	objectUser, err := p.adminOpsClient.GetUser(p.clusterInfo.Context, admin.User{ID: p.cephUserName})
-	if err != nil {
-		return errors.Wrapf(err, "failed to fetch user %q", p.cephUserName)
+	// the user was usually read already by this reconcile
+	objectUser := p.cephUser
+	if objectUser == nil || objectUser.ID != p.cephUserName || objectUser.UserQuota.Enabled == nil || objectUser.UserQuota.MaxObjects == nil {
+		user, err := p.adminOpsClient.GetUser(p.clusterInfo.Context, admin.User{ID: p.cephUserName})
+		if err != nil {
+			return errors.Wrapf(err, "failed to fetch user %q", p.cephUserName)
+		}
+		objectUser = &user
 	}
 
 	// enable or disable quota for user
@@ -624,7 +662,7 @@ func (p *Provisioner) setAdditionalSettings(options *apibkt.BucketOptions) error
 		}
 	}
 
-	if objectUser.UserQuota.MaxSize != &maxSizeInt64 {
+	if objectUser.UserQuota.MaxSize == nil || *objectUser.UserQuota.MaxSize != maxSizeInt64 {
 		err = p.adminOpsClient.SetUserQuota(p.clusterInfo.Context, admin.QuotaSpec{UID: p.cephUserName, MaxSize: &maxSizeInt64})
 		if err != nil {
 			return errors.Wrapf(err, "failed to set MaxSize=%v to user %q", maxSizeInt64, p.cephUserName)
@@ -634,6 +672,457 @@ func (p *Provisioner) setAdditionalSettings(options *apibkt.BucketOptions) error
 	return nil
 }
 
+// applyBucketSettings applies the settings of the bucket requested in the OBC, both to the buckets provisioned for
+// the OBCs and to the existing buckets the OBCs are granted access to. The grant statement is the statement of the
+// bucket policy granting the user of the OBC access to an existing bucket, or nil for a provisioned bucket.
+func (p *Provisioner) applyBucketSettings(s3svc *object.S3Agent, options *apibkt.BucketOptions, grant *object.PolicyStatement) error {
+	err := p.setBucketVersioning(s3svc, options)
+	if err != nil {
+		return errors.Wrapf(err, "failed to set bucket versioning for OBC %q", options.ObjectBucketClaim.Name)
+	}
+
+	err = p.setBucketEncryption(s3svc, options)
+	if err != nil {
+		return errors.Wrapf(err, "failed to set bucket encryption for OBC %q", options.ObjectBucketClaim.Name)
+	}
+
+	err = p.setBucketPolicy(s3svc, options, grant)
+	if err != nil {
+		return errors.Wrapf(err, "failed to set bucket policy for OBC %q", options.ObjectBucketClaim.Name)
+	}
+
+	err = p.setBucketRateLimit(options)
+	if err != nil {
+		return errors.Wrapf(err, "failed to set bucket rate limit for OBC %q", options.ObjectBucketClaim.Name)
+	}
+	return nil
+}
+
+// setBucketVersioning enables or suspends the versioning of the bucket if requested in the OBC. Since the OBC is
+// provisioned again on every reconcile, the requested versioning state is enforced if it was changed out of band.
+func (p *Provisioner) setBucketVersioning(s3svc *object.S3Agent, options *apibkt.BucketOptions) error {
+	versioning := BucketVersioning(options.ObjectBucketClaim.Spec.AdditionalConfig)
+	if versioning == "" {
+		return nil
+	}
+	enabled, err := strconv.ParseBool(versioning)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse bucketVersioning %q", versioning)
+	}
+
+	status, err := s3svc.GetBucketVersioning(p.bucketName)
+	if err != nil {
+		return err
+	}
+	if enabled && status == s3.BucketVersioningStatusEnabled {
+		return nil
+	}
+	// a bucket that never had versioning enabled cannot be suspended
+	if !enabled && status != s3.BucketVersioningStatusEnabled {
+		return nil
+	}
+
+	err = s3svc.PutBucketVersioning(p.bucketName, enabled)
+	if err != nil {
+		return err
+	}
+	logger.Infof("set bucket %q versioning enabled=%t", p.bucketName, enabled)
+	return nil
+}
+
+// bucketEncryption returns whether the default encryption of the bucket is requested by the OBC, or by the object
+// store if the OBC does not override it. The second value is false if neither requests a state of the encryption.
+func (p *Provisioner) bucketEncryption(additionalConfig map[string]string) (bool, bool, error) {
+	if encryption := BucketEncryption(additionalConfig); encryption != "" {
+		enabled, err := strconv.ParseBool(encryption)
+		if err != nil {
+			return false, false, errors.Wrapf(err, "failed to parse bucketEncryption %q", encryption)
+		}
+		return enabled, true, nil
+	}
+	if p.objectStore != nil && p.objectStore.Spec.Security != nil && p.objectStore.Spec.Security.DefaultBucketEncryption {
+		return true, true, nil
+	}
+	return false, false, nil
+}
+
+// setBucketEncryption enables the default AWS-SSE:S3 encryption of the bucket if requested by the object store or
+// the OBC, so the objects are encrypted even if the applications do not send the encryption headers. The encryption
+// is disabled if the OBC opts out of it, and it is enforced on every reconcile like the versioning.
+func (p *Provisioner) setBucketEncryption(s3svc *object.S3Agent, options *apibkt.BucketOptions) error {
+	enabled, requested, err := p.bucketEncryption(options.ObjectBucketClaim.Spec.AdditionalConfig)
+	if err != nil || !requested {
+		return err
+	}
+
+	encrypted, err := s3svc.GetBucketEncryption(p.bucketName)
+	if err != nil {
+		return err
+	}
+	if encrypted == enabled {
+		return nil
+	}
+
+	if enabled {
+		err = s3svc.PutBucketEncryption(p.bucketName)
+	} else {
+		err = s3svc.DeleteBucketEncryption(p.bucketName)
+	}
+	if err != nil {
+		return err
+	}
+	logger.Infof("set bucket %q default encryption enabled=%t", p.bucketName, enabled)
+	return nil
+}
+
+// bucketRateLimit returns the rate limit of the bucket requested in the OBC, or nil if none is requested
+func bucketRateLimit(additionalConfig map[string]string) (*object.RateLimit, error) {
+	rateLimit := &object.RateLimit{}
+	limits := []struct {
+		name  string
+		value string
+		limit *int64
+	}{
+		{"maxReadOps", MaxReadOps(additionalConfig), &rateLimit.MaxReadOps},
+		{"maxWriteOps", MaxWriteOps(additionalConfig), &rateLimit.MaxWriteOps},
+		{"maxReadBytes", MaxReadBytes(additionalConfig), &rateLimit.MaxReadBytes},
+		{"maxWriteBytes", MaxWriteBytes(additionalConfig), &rateLimit.MaxWriteBytes},
+	}
+
+	requested := false
+	for _, l := range limits {
+		if l.value == "" {
+			continue
+		}
+		value, err := toInt64(l.value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %s", l.name)
+		}
+		if value < 0 {
+			return nil, errors.Errorf("%s must not be negative", l.name)
+		}
+		*l.limit = value
+		requested = true
+	}
+	if !requested {
+		return nil, nil
+	}
+	return rateLimit, nil
+}
+
+// setBucketRateLimit sets the rate limit of the bucket requested in the OBC, and disables the rate limit of the
+// bucket when it is no longer requested. The rate limits are set with radosgw-admin, which cannot reach the
+// external object stores.
+func (p *Provisioner) setBucketRateLimit(options *apibkt.BucketOptions) error {
+	rateLimit, err := bucketRateLimit(options.ObjectBucketClaim.Spec.AdditionalConfig)
+	if err != nil {
+		return err
+	}
+	if p.endpoint != "" {
+		if rateLimit != nil {
+			return errors.New("bucket rate limits are not supported with an external object store endpoint")
+		}
+		return nil
+	}
+	return object.SetRateLimit(p.objectContext, object.RateLimitScopeBucket, p.bucketName, rateLimit)
+}
+
+// setBucketPolicy sets the bucket policy requested in the OBC, either inline or from a ConfigMap in the namespace of
+// the OBC, with the statements granting the bucket users of the OBC access to the bucket, and the grant statement of
+// an OBC attached to an existing bucket. The grant statements of the other OBCs attached to the bucket are kept. The
+// policy is only written if it differs from the current policy of the bucket.
+func (p *Provisioner) setBucketPolicy(s3svc *object.S3Agent, options *apibkt.BucketOptions, grant *object.PolicyStatement) error {
+	policy, err := p.getRequestedBucketPolicy(options.ObjectBucketClaim)
+	if err != nil {
+		return err
+	}
+	userStatements, err := p.getBucketUserStatements(options.ObjectBucketClaim)
+	if err != nil {
+		return err
+	}
+
+	current, err := s3svc.GetBucketPolicyDocument(p.bucketName)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != "NoSuchBucketPolicy" {
+			return errors.Wrapf(err, "failed to get policy of bucket %q", p.bucketName)
+		}
+	}
+
+	if policy != "" {
+		policy, err = bucketPolicyWithGrants(policy, current)
+		if err != nil {
+			return err
+		}
+	}
+
+	managedStatements := userStatements
+	if grant != nil {
+		managedStatements = append(managedStatements, *grant)
+	}
+	if len(managedStatements) > 0 || hasBucketUserStatements(current) {
+		// the managed statements are kept in the requested policy, or else in the current policy
+		base := policy
+		if base == "" {
+			base = current
+		}
+		policy, err = bucketPolicyWithStatements(base, managedStatements)
+		if err != nil {
+			return err
+		}
+		if policy == "" {
+			err = s3svc.DeleteBucketPolicy(p.bucketName)
+			if err != nil {
+				return errors.Wrapf(err, "failed to delete policy of bucket %q", p.bucketName)
+			}
+			logger.Infof("deleted policy of bucket %q since it no longer grants access to any bucket user", p.bucketName)
+			return nil
+		}
+	}
+	if policy == "" {
+		return nil
+	}
+	var desired interface{}
+	if err := json.Unmarshal([]byte(policy), &desired); err != nil {
+		return errors.Wrap(err, "failed to parse bucket policy")
+	}
+
+	if current != "" {
+		var existing interface{}
+		if err := json.Unmarshal([]byte(current), &existing); err == nil && reflect.DeepEqual(existing, desired) {
+			return nil
+		}
+	}
+
+	err = s3svc.PutBucketPolicyDocument(p.bucketName, policy)
+	if err != nil {
+		return errors.Wrapf(err, "failed to put policy of bucket %q", p.bucketName)
+	}
+	logger.Infof("set policy of bucket %q", p.bucketName)
+	return nil
+}
+
+// getBucketUserStatements returns the bucket policy statements granting the bucket users of the OBC access to the
+// bucket. The bucket users are CephObjectStoreUsers of the object store of the OBC in the namespace of the OBC.
+func (p *Provisioner) getBucketUserStatements(obc *bktv1alpha1.ObjectBucketClaim) ([]object.PolicyStatement, error) {
+	users, err := parseBucketUsers(BucketUsers(obc.Spec.AdditionalConfig))
+	if err != nil {
+		return nil, err
+	}
+
+	statements := []object.PolicyStatement{}
+	for _, bucketUser := range users {
+		user, err := p.context.RookClientset.CephV1().CephObjectStoreUsers(obc.Namespace).Get(p.clusterInfo.Context, bucketUser.name, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get bucket user \"%s/%s\"", obc.Namespace, bucketUser.name)
+		}
+		store := user.StoreNamespacedName()
+		if store.Name != p.objectStoreName || store.Namespace != p.clusterInfo.Namespace {
+			return nil, errors.Errorf("bucket user \"%s/%s\" is not a user of object store \"%s/%s\"", obc.Namespace, bucketUser.name, p.clusterInfo.Namespace, p.objectStoreName)
+		}
+
+		statement := object.NewPolicyStatement().
+			WithSID(bucketUserSidPrefix + bucketUser.name).
+			ForPrincipals(user.CephUserID()).
+			ForResources(p.bucketName).
+			ForSubResources(p.bucketName).
+			Allows()
+		switch bucketUser.access {
+		case bucketUserAccessRead:
+			statement.Actions(object.ReadActions...)
+		case bucketUserAccessWrite:
+			statement.Actions(object.WriteActions...)
+		case bucketUserAccessFull:
+			statement.Actions(object.All)
+		}
+		statements = append(statements, *statement)
+	}
+	return statements, nil
+}
+
+const (
+	bucketUserAccessRead  = "read"
+	bucketUserAccessWrite = "write"
+	bucketUserAccessFull  = "full"
+)
+
+type bucketUser struct {
+	name   string
+	access string
+}
+
+// parseBucketUsers parses the bucket users of an OBC, given as a comma-separated list of "<user>:<access>", where the
+// access is one of read, write or full
+func parseBucketUsers(value string) ([]bucketUser, error) {
+	users := []bucketUser{}
+	if strings.TrimSpace(value) == "" {
+		return users, nil
+	}
+	seen := map[string]bool{}
+	for _, entry := range strings.Split(value, ",") {
+		name, access, found := strings.Cut(strings.TrimSpace(entry), ":")
+		if !found || name == "" {
+			return nil, errors.Errorf("invalid bucket user %q, expected \"<user>:<access>\"", entry)
+		}
+		if access != bucketUserAccessRead && access != bucketUserAccessWrite && access != bucketUserAccessFull {
+			return nil, errors.Errorf("invalid access %q of bucket user %q, expected one of %q, %q or %q", access, name, bucketUserAccessRead, bucketUserAccessWrite, bucketUserAccessFull)
+		}
+		if seen[name] {
+			return nil, errors.Errorf("bucket user %q is listed more than once", name)
+		}
+		seen[name] = true
+		users = append(users, bucketUser{name: name, access: access})
+	}
+	return users, nil
+}
+
+// hasBucketUserStatements returns whether the policy document grants access to bucket users
+func hasBucketUserStatements(policy string) bool {
+	if policy == "" {
+		return false
+	}
+	document := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(policy), &document); err != nil {
+		return false
+	}
+	for _, statement := range policyStatements(document) {
+		if isBucketUserStatement(statement) {
+			return true
+		}
+	}
+	return false
+}
+
+// bucketPolicyWithStatements returns the policy document with the given statements, replacing the statements of the
+// bucket users it had and the statements with the same IDs. An empty document is returned if the policy is left
+// without statements.
+func bucketPolicyWithStatements(policy string, managedStatements []object.PolicyStatement) (string, error) {
+	document := map[string]interface{}{}
+	if policy != "" {
+		if err := json.Unmarshal([]byte(policy), &document); err != nil {
+			return "", errors.Wrap(err, "failed to parse bucket policy")
+		}
+	}
+	replaced := map[string]bool{}
+	for _, statement := range managedStatements {
+		replaced[statement.Sid] = true
+	}
+
+	statements := []interface{}{}
+	for _, statement := range policyStatements(document) {
+		if !isBucketUserStatement(statement) && !replaced[statementSid(statement)] {
+			statements = append(statements, statement)
+		}
+	}
+	for _, statement := range managedStatements {
+		statements = append(statements, statement)
+	}
+	if len(statements) == 0 {
+		return "", nil
+	}
+	document["Statement"] = statements
+	if _, ok := document["Version"]; !ok {
+		document["Version"] = object.NewBucketPolicy().Version
+	}
+
+	out, err := json.Marshal(document)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to serialize bucket policy")
+	}
+	return string(out), nil
+}
+
+// policyStatements returns the statements of the policy document, which can be a single statement or a list
+func policyStatements(document map[string]interface{}) []interface{} {
+	switch statements := document["Statement"].(type) {
+	case []interface{}:
+		return statements
+	case map[string]interface{}:
+		return []interface{}{statements}
+	}
+	return []interface{}{}
+}
+
+func statementSid(statement interface{}) string {
+	s, ok := statement.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	sid, _ := s["Sid"].(string)
+	return sid
+}
+
+func isBucketUserStatement(statement interface{}) bool {
+	return strings.HasPrefix(statementSid(statement), bucketUserSidPrefix)
+}
+
+// isGrantStatement returns whether the statement grants the user of an OBC access to an existing bucket, since the
+// IDs of the grant statements are the names of the users generated for the OBCs
+func isGrantStatement(statement interface{}) bool {
+	return strings.HasPrefix(statementSid(statement), obcUserPrefix)
+}
+
+// bucketPolicyWithGrants returns the requested policy document with the grant statements of the current policy that
+// the requested policy does not have, so the OBCs attached to the bucket keep their access
+func bucketPolicyWithGrants(policy, current string) (string, error) {
+	if current == "" {
+		return policy, nil
+	}
+	currentDocument := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(current), &currentDocument); err != nil {
+		// the current policy is replaced
+		return policy, nil
+	}
+	document := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(policy), &document); err != nil {
+		return "", errors.Wrap(err, "failed to parse bucket policy")
+	}
+
+	statements := policyStatements(document)
+	sids := map[string]bool{}
+	for _, statement := range statements {
+		sids[statementSid(statement)] = true
+	}
+	kept := false
+	for _, statement := range policyStatements(currentDocument) {
+		if isGrantStatement(statement) && !sids[statementSid(statement)] {
+			statements = append(statements, statement)
+			kept = true
+		}
+	}
+	if !kept {
+		return policy, nil
+	}
+	document["Statement"] = statements
+
+	out, err := json.Marshal(document)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to serialize bucket policy")
+	}
+	return string(out), nil
+}
+
+// getRequestedBucketPolicy returns the bucket policy document requested in the OBC, or an empty string if none is
+func (p *Provisioner) getRequestedBucketPolicy(obc *bktv1alpha1.ObjectBucketClaim) (string, error) {
+	policy := BucketPolicy(obc.Spec.AdditionalConfig)
+	configMapName := BucketPolicyConfigMap(obc.Spec.AdditionalConfig)
+	if configMapName == "" {
+		return policy, nil
+	}
+	if policy != "" {
+		return "", errors.New("only one of bucketPolicy and bucketPolicyConfigMap can be set")
+	}
+
+	cm, err := p.context.Clientset.CoreV1().ConfigMaps(obc.Namespace).Get(p.clusterInfo.Context, configMapName, metav1.GetOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get bucket policy configmap \"%s/%s\"", obc.Namespace, configMapName)
+	}
+	policy, ok := cm.Data[bucketPolicyConfigMapKey]
+	if !ok {
+		return "", errors.Errorf("bucket policy configmap \"%s/%s\" has no %q key", obc.Namespace, configMapName, bucketPolicyConfigMapKey)
+	}
+	return policy, nil
+}
+
 func toInt64(maxSize string) (int64, error) {
 	maxSizeInt, err := resource.ParseQuantity(maxSize)
 	if err != nil {
@@ -674,20 +1163,9 @@ func (p *Provisioner) setAdminOpsAPIClient() error {
 		return errors.Wrapf(err, "failed to get ceph object store %q", p.objectStoreName)
 	}
 
-	cephCluster, err := p.getCephCluster()
+	accessKey, secretKey, err := p.getAdminOpsCredentials(cephObjectStore)
 	if err != nil {
-		return errors.Wrapf(err, "failed to get ceph cluster in namespace %q", p.clusterInfo.Namespace)
-	}
-	if cephCluster == nil {
-		return errors.Errorf("failed to read ceph cluster in namespace %q, it's nil", p.clusterInfo.Namespace)
-	}
-	// Set the Ceph Cluster Spec so that we can fetch the admin ops key properly when multus is enabled
-	p.objectContext.CephClusterSpec = cephCluster.Spec
-
-	// Fetch the object store admin ops user
-	accessKey, secretKey, err := object.GetAdminOPSUserCredentials(p.objectContext, &cephObjectStore.Spec)
-	if err != nil {
-		return errors.Wrap(err, "failed to retrieve rgw admin ops user")
+		return err
 	}
 
 	// Build endpoint
@@ -708,3 +1186,35 @@ func (p *Provisioner) setAdminOpsAPIClient() error {
 
 	return nil
 }
+
+// getAdminOpsCredentials returns the credentials of the admin ops user of the object store, from the cache if the
+// credentials were read recently
+func (p *Provisioner) getAdminOpsCredentials(cephObjectStore *cephv1.CephObjectStore) (string, string, error) {
+	cephCluster, err := p.getCephCluster()
+	if err != nil {
+		return "", "", errors.Wrapf(err, "failed to get ceph cluster in namespace %q", p.clusterInfo.Namespace)
+	}
+	if cephCluster == nil {
+		return "", "", errors.Errorf("failed to read ceph cluster in namespace %q, it's nil", p.clusterInfo.Namespace)
+	}
+	// Set the Ceph Cluster Spec so that we can fetch the admin ops key properly when multus is enabled, and so that
+	// the next radosgw-admin commands go through the multus proxy even when the credentials are cached
+	p.objectContext.CephClusterSpec = cephCluster.Spec
+
+	if p.adminOpsCredentials != nil {
+		if accessKey, secretKey, ok := p.adminOpsCredentials.get(cephObjectStore); ok {
+			return accessKey, secretKey, nil
+		}
+	}
+
+	// Fetch the object store admin ops user
+	accessKey, secretKey, err := object.GetAdminOPSUserCredentials(p.objectContext, &cephObjectStore.Spec)
+	if err != nil {
+		return "", "", errors.Wrap(err, "failed to retrieve rgw admin ops user")
+	}
+
+	if p.adminOpsCredentials != nil {
+		p.adminOpsCredentials.set(cephObjectStore, accessKey, secretKey)
+	}
+	return accessKey, secretKey, nil
+}
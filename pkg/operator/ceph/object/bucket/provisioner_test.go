@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
@@ -140,6 +141,31 @@ func TestMaxSizeToInt64(t *testing.T) {
 	}
 }
 
+func TestBucketRateLimit(t *testing.T) {
+	tests := []struct {
+		name             string
+		additionalConfig map[string]string
+		want             *object.RateLimit
+		wantErr          bool
+	}{
+		{"no rate limit", map[string]string{"maxSize": "2G"}, nil, false},
+		{"ops and bytes", map[string]string{"maxReadOps": "100", "maxWriteBytes": "1Mi"}, &object.RateLimit{MaxReadOps: 100, MaxWriteBytes: 1048576}, false},
+		{"invalid ops", map[string]string{"maxWriteOps": "many"}, nil, true},
+		{"negative bytes", map[string]string{"maxReadBytes": "-1"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bucketRateLimit(tt.additionalConfig)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestProvisioner_setAdditionalSettings(t *testing.T) {
 	newProvisioner := func(t *testing.T, getUserResult string, putValsSeen *[]string) *Provisioner {
 		mockClient := &object.MockClient{
@@ -344,6 +370,27 @@ func TestProvisioner_setAdditionalSettings(t *testing.T) {
 		assert.NotEmpty(t, putWithValue(`max-objects=3`, putValsSeen))
 		assert.Equal(t, 1, numberOfPutsWithValue(`max-objects`, putValsSeen))
 	})
+
+	t.Run("quotas are already set", func(t *testing.T) {
+		putValsSeen := []string{}
+		p := newProvisioner(t,
+			`{"user_quota":{"enabled":true,"max_size":2,"max_objects":3}}`,
+			&putValsSeen,
+		)
+
+		err := p.setAdditionalSettings(&apibkt.BucketOptions{
+			ObjectBucketClaim: &v1alpha1.ObjectBucketClaim{
+				Spec: v1alpha1.ObjectBucketClaimSpec{
+					AdditionalConfig: map[string]string{
+						"maxSize":    "2",
+						"maxObjects": "3",
+					},
+				},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, putValsSeen, 0)
+	})
 }
 
 func numberOfPutsWithValue(substr string, strs []string) int {
@@ -364,3 +411,485 @@ func putWithValue(substr string, strs []string) string {
 	}
 	return ""
 }
+
+func TestProvisioner_setBucketVersioning(t *testing.T) {
+	newS3Agent := func(t *testing.T, status string, putBodies *[]string) *object.S3Agent {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPut {
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				*putBodies = append(*putBodies, string(body))
+				return
+			}
+			fmt.Fprintf(w, `<VersioningConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">%s</VersioningConfiguration>`, status)
+		}))
+		t.Cleanup(srv.Close)
+
+		s3svc, err := object.NewS3Agent("access", "secret", srv.URL, false, nil)
+		assert.NoError(t, err)
+		return s3svc
+	}
+	optionsWith := func(additionalConfig map[string]string) *apibkt.BucketOptions {
+		return &apibkt.BucketOptions{
+			ObjectBucketClaim: &v1alpha1.ObjectBucketClaim{
+				Spec: v1alpha1.ObjectBucketClaimSpec{AdditionalConfig: additionalConfig},
+			},
+		}
+	}
+	p := &Provisioner{bucketName: "bkt"}
+
+	t.Run("versioning not requested", func(t *testing.T) {
+		putBodies := []string{}
+		s3svc := newS3Agent(t, "", &putBodies)
+		err := p.setBucketVersioning(s3svc, optionsWith(map[string]string{}))
+		assert.NoError(t, err)
+		assert.Empty(t, putBodies)
+	})
+
+	t.Run("versioning is enabled", func(t *testing.T) {
+		putBodies := []string{}
+		s3svc := newS3Agent(t, "", &putBodies)
+		err := p.setBucketVersioning(s3svc, optionsWith(map[string]string{"bucketVersioning": "true"}))
+		assert.NoError(t, err)
+		assert.Len(t, putBodies, 1)
+		assert.Contains(t, putBodies[0], "<Status>Enabled</Status>")
+	})
+
+	t.Run("versioning is already enabled", func(t *testing.T) {
+		putBodies := []string{}
+		s3svc := newS3Agent(t, "<Status>Enabled</Status>", &putBodies)
+		err := p.setBucketVersioning(s3svc, optionsWith(map[string]string{"bucketVersioning": "true"}))
+		assert.NoError(t, err)
+		assert.Empty(t, putBodies)
+	})
+
+	t.Run("versioning is suspended", func(t *testing.T) {
+		putBodies := []string{}
+		s3svc := newS3Agent(t, "<Status>Enabled</Status>", &putBodies)
+		err := p.setBucketVersioning(s3svc, optionsWith(map[string]string{"bucketVersioning": "false"}))
+		assert.NoError(t, err)
+		assert.Len(t, putBodies, 1)
+		assert.Contains(t, putBodies[0], "<Status>Suspended</Status>")
+	})
+
+	t.Run("versioning was never enabled", func(t *testing.T) {
+		putBodies := []string{}
+		s3svc := newS3Agent(t, "", &putBodies)
+		err := p.setBucketVersioning(s3svc, optionsWith(map[string]string{"bucketVersioning": "false"}))
+		assert.NoError(t, err)
+		assert.Empty(t, putBodies)
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		putBodies := []string{}
+		s3svc := newS3Agent(t, "", &putBodies)
+		err := p.setBucketVersioning(s3svc, optionsWith(map[string]string{"bucketVersioning": "maybe"}))
+		assert.Error(t, err)
+	})
+}
+
+func TestProvisioner_setBucketEncryption(t *testing.T) {
+	newS3Agent := func(t *testing.T, encrypted bool, requests *[]string) *object.S3Agent {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet {
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				*requests = append(*requests, r.Method+" "+string(body))
+				return
+			}
+			if !encrypted {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `<Error><Code>ServerSideEncryptionConfigurationNotFoundError</Code></Error>`)
+				return
+			}
+			fmt.Fprint(w, `<ServerSideEncryptionConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><Rule>`+
+				`<ApplyServerSideEncryptionByDefault><SSEAlgorithm>AES256</SSEAlgorithm></ApplyServerSideEncryptionByDefault>`+
+				`</Rule></ServerSideEncryptionConfiguration>`)
+		}))
+		t.Cleanup(srv.Close)
+
+		s3svc, err := object.NewS3Agent("access", "secret", srv.URL, false, nil)
+		assert.NoError(t, err)
+		return s3svc
+	}
+	optionsWith := func(additionalConfig map[string]string) *apibkt.BucketOptions {
+		return &apibkt.BucketOptions{
+			ObjectBucketClaim: &v1alpha1.ObjectBucketClaim{
+				Spec: v1alpha1.ObjectBucketClaimSpec{AdditionalConfig: additionalConfig},
+			},
+		}
+	}
+	storeWithDefaultEncryption := &cephv1.CephObjectStore{Spec: cephv1.ObjectStoreSpec{
+		Security: &cephv1.ObjectStoreSecuritySpec{DefaultBucketEncryption: true},
+	}}
+
+	t.Run("encryption not requested", func(t *testing.T) {
+		requests := []string{}
+		p := &Provisioner{bucketName: "bkt"}
+		err := p.setBucketEncryption(newS3Agent(t, false, &requests), optionsWith(map[string]string{}))
+		assert.NoError(t, err)
+		assert.Empty(t, requests)
+	})
+
+	t.Run("default encryption of the store", func(t *testing.T) {
+		requests := []string{}
+		p := &Provisioner{bucketName: "bkt", objectStore: storeWithDefaultEncryption}
+		err := p.setBucketEncryption(newS3Agent(t, false, &requests), optionsWith(map[string]string{}))
+		assert.NoError(t, err)
+		assert.Len(t, requests, 1)
+		assert.Contains(t, requests[0], "PUT")
+		assert.Contains(t, requests[0], "<SSEAlgorithm>AES256</SSEAlgorithm>")
+	})
+
+	t.Run("bucket already encrypted", func(t *testing.T) {
+		requests := []string{}
+		p := &Provisioner{bucketName: "bkt", objectStore: storeWithDefaultEncryption}
+		err := p.setBucketEncryption(newS3Agent(t, true, &requests), optionsWith(map[string]string{}))
+		assert.NoError(t, err)
+		assert.Empty(t, requests)
+	})
+
+	t.Run("obc opts out of the default encryption", func(t *testing.T) {
+		requests := []string{}
+		p := &Provisioner{bucketName: "bkt", objectStore: storeWithDefaultEncryption}
+		err := p.setBucketEncryption(newS3Agent(t, true, &requests), optionsWith(map[string]string{"bucketEncryption": "false"}))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"DELETE "}, requests)
+	})
+
+	t.Run("obc requests the encryption", func(t *testing.T) {
+		requests := []string{}
+		p := &Provisioner{bucketName: "bkt"}
+		err := p.setBucketEncryption(newS3Agent(t, false, &requests), optionsWith(map[string]string{"bucketEncryption": "true"}))
+		assert.NoError(t, err)
+		assert.Len(t, requests, 1)
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		requests := []string{}
+		p := &Provisioner{bucketName: "bkt"}
+		err := p.setBucketEncryption(newS3Agent(t, false, &requests), optionsWith(map[string]string{"bucketEncryption": "maybe"}))
+		assert.Error(t, err)
+	})
+}
+
+func TestProvisioner_setBucketPolicy(t *testing.T) {
+	const policy = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":["arn:aws:iam:::user/reader"]},"Action":["s3:GetObject"],"Resource":["arn:aws:s3:::bkt/*"]}]}`
+
+	newS3Agent := func(t *testing.T, currentPolicy string, putBodies *[]string) *object.S3Agent {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPut {
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				*putBodies = append(*putBodies, string(body))
+				return
+			}
+			if currentPolicy == "" {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `<Error><Code>NoSuchBucketPolicy</Code></Error>`)
+				return
+			}
+			fmt.Fprint(w, currentPolicy)
+		}))
+		t.Cleanup(srv.Close)
+
+		s3svc, err := object.NewS3Agent("access", "secret", srv.URL, false, nil)
+		assert.NoError(t, err)
+		return s3svc
+	}
+	optionsWith := func(additionalConfig map[string]string) *apibkt.BucketOptions {
+		return &apibkt.BucketOptions{
+			ObjectBucketClaim: &v1alpha1.ObjectBucketClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "obc", Namespace: "app"},
+				Spec:       v1alpha1.ObjectBucketClaimSpec{AdditionalConfig: additionalConfig},
+			},
+		}
+	}
+	clientset := test.New(t, 1)
+	_, err := clientset.CoreV1().ConfigMaps("app").Create(context.TODO(), &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "bkt-policy", Namespace: "app"},
+		Data:       map[string]string{"policy": policy},
+	}, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	p := &Provisioner{
+		bucketName:  "bkt",
+		context:     &clusterd.Context{Clientset: clientset},
+		clusterInfo: &client.ClusterInfo{Context: context.TODO()},
+	}
+
+	t.Run("policy not requested", func(t *testing.T) {
+		putBodies := []string{}
+		s3svc := newS3Agent(t, "", &putBodies)
+		err := p.setBucketPolicy(s3svc, optionsWith(map[string]string{}), nil)
+		assert.NoError(t, err)
+		assert.Empty(t, putBodies)
+	})
+
+	t.Run("inline policy is set", func(t *testing.T) {
+		putBodies := []string{}
+		s3svc := newS3Agent(t, "", &putBodies)
+		err := p.setBucketPolicy(s3svc, optionsWith(map[string]string{"bucketPolicy": policy}), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{policy}, putBodies)
+	})
+
+	t.Run("policy from configmap is set", func(t *testing.T) {
+		putBodies := []string{}
+		s3svc := newS3Agent(t, `{"Version":"2012-10-17","Statement":[]}`, &putBodies)
+		err := p.setBucketPolicy(s3svc, optionsWith(map[string]string{"bucketPolicyConfigMap": "bkt-policy"}), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{policy}, putBodies)
+	})
+
+	t.Run("policy is already set", func(t *testing.T) {
+		putBodies := []string{}
+		s3svc := newS3Agent(t, policy, &putBodies)
+		err := p.setBucketPolicy(s3svc, optionsWith(map[string]string{"bucketPolicy": policy}), nil)
+		assert.NoError(t, err)
+		assert.Empty(t, putBodies)
+	})
+
+	t.Run("invalid policies", func(t *testing.T) {
+		putBodies := []string{}
+		s3svc := newS3Agent(t, "", &putBodies)
+		err := p.setBucketPolicy(s3svc, optionsWith(map[string]string{"bucketPolicy": "{not json"}), nil)
+		assert.Error(t, err)
+
+		err = p.setBucketPolicy(s3svc, optionsWith(map[string]string{"bucketPolicy": policy, "bucketPolicyConfigMap": "bkt-policy"}), nil)
+		assert.Error(t, err)
+
+		err = p.setBucketPolicy(s3svc, optionsWith(map[string]string{"bucketPolicyConfigMap": "missing"}), nil)
+		assert.Error(t, err)
+		assert.Empty(t, putBodies)
+	})
+}
+
+func TestParseBucketUsers(t *testing.T) {
+	users, err := parseBucketUsers("")
+	assert.NoError(t, err)
+	assert.Empty(t, users)
+
+	users, err = parseBucketUsers("reader:read, writer:write,admin:full")
+	assert.NoError(t, err)
+	assert.Equal(t, []bucketUser{{"reader", "read"}, {"writer", "write"}, {"admin", "full"}}, users)
+
+	for _, invalid := range []string{"reader", ":read", "reader:admin", "reader:read,reader:write", "reader:read,"} {
+		_, err = parseBucketUsers(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestProvisioner_setBucketUsers(t *testing.T) {
+	const policy = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":["arn:aws:iam:::user/reader"]},"Action":["s3:GetObject"],"Resource":["arn:aws:s3:::bkt/*"]}]}`
+
+	newS3Agent := func(t *testing.T, currentPolicy string, putBodies *[]string, deletes *int) *object.S3Agent {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodPut:
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				*putBodies = append(*putBodies, string(body))
+				return
+			case http.MethodDelete:
+				*deletes++
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			if currentPolicy == "" {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `<Error><Code>NoSuchBucketPolicy</Code></Error>`)
+				return
+			}
+			fmt.Fprint(w, currentPolicy)
+		}))
+		t.Cleanup(srv.Close)
+
+		s3svc, err := object.NewS3Agent("access", "secret", srv.URL, false, nil)
+		assert.NoError(t, err)
+		return s3svc
+	}
+	optionsWith := func(additionalConfig map[string]string) *apibkt.BucketOptions {
+		return &apibkt.BucketOptions{
+			ObjectBucketClaim: &v1alpha1.ObjectBucketClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "obc", Namespace: "app"},
+				Spec:       v1alpha1.ObjectBucketClaimSpec{AdditionalConfig: additionalConfig},
+			},
+		}
+	}
+	rookClientset := rookclient.NewSimpleClientset(
+		&cephv1.CephObjectStoreUser{ObjectMeta: metav1.ObjectMeta{Name: "writer", Namespace: "app"}, Spec: cephv1.ObjectStoreUserSpec{Store: "rook-ceph/my-store"}},
+		&cephv1.CephObjectStoreUser{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "app"}, Spec: cephv1.ObjectStoreUserSpec{Store: "other-store"}},
+	)
+	p := &Provisioner{
+		bucketName:      "bkt",
+		objectStoreName: "my-store",
+		context:         &clusterd.Context{Clientset: test.New(t, 1), RookClientset: rookClientset},
+		clusterInfo:     &client.ClusterInfo{Namespace: "rook-ceph", Context: context.TODO()},
+	}
+
+	t.Run("bucket users are granted access", func(t *testing.T) {
+		putBodies, deletes := []string{}, 0
+		s3svc := newS3Agent(t, "", &putBodies, &deletes)
+		err := p.setBucketPolicy(s3svc, optionsWith(map[string]string{"bucketUsers": "writer:write"}), nil)
+		assert.NoError(t, err)
+		assert.Len(t, putBodies, 1)
+		assert.Contains(t, putBodies[0], `"Sid":"rook-bucket-user-writer"`)
+		// the user in another namespace than its store is in the tenant of its namespace
+		assert.Contains(t, putBodies[0], `"arn:aws:iam::app:user/writer"`)
+		assert.Contains(t, putBodies[0], `"s3:PutObject"`)
+		assert.Contains(t, putBodies[0], `"arn:aws:s3:::bkt/*"`)
+
+		// the policy is not written again
+		current := putBodies[0]
+		putBodies = []string{}
+		s3svc = newS3Agent(t, current, &putBodies, &deletes)
+		err = p.setBucketPolicy(s3svc, optionsWith(map[string]string{"bucketUsers": "writer:write"}), nil)
+		assert.NoError(t, err)
+		assert.Empty(t, putBodies)
+	})
+
+	t.Run("bucket users are added to the requested policy", func(t *testing.T) {
+		putBodies, deletes := []string{}, 0
+		s3svc := newS3Agent(t, "", &putBodies, &deletes)
+		err := p.setBucketPolicy(s3svc, optionsWith(map[string]string{"bucketPolicy": policy, "bucketUsers": "writer:full"}), nil)
+		assert.NoError(t, err)
+		assert.Len(t, putBodies, 1)
+		assert.Contains(t, putBodies[0], `"arn:aws:iam:::user/reader"`)
+		assert.Contains(t, putBodies[0], `"rook-bucket-user-writer"`)
+		assert.Contains(t, putBodies[0], `"s3:*"`)
+	})
+
+	t.Run("bucket users are revoked", func(t *testing.T) {
+		current, err := bucketPolicyWithStatements(policy, []object.PolicyStatement{*object.NewPolicyStatement().WithSID("rook-bucket-user-writer").ForPrincipals("writer")})
+		assert.NoError(t, err)
+
+		// the other statements of the current policy are kept
+		putBodies, deletes := []string{}, 0
+		s3svc := newS3Agent(t, current, &putBodies, &deletes)
+		err = p.setBucketPolicy(s3svc, optionsWith(map[string]string{}), nil)
+		assert.NoError(t, err)
+		assert.Len(t, putBodies, 1)
+		assert.Contains(t, putBodies[0], `"arn:aws:iam:::user/reader"`)
+		assert.NotContains(t, putBodies[0], "rook-bucket-user-writer")
+		assert.Zero(t, deletes)
+
+		// the policy is deleted when it has no statements left
+		current, err = bucketPolicyWithStatements("", []object.PolicyStatement{*object.NewPolicyStatement().WithSID("rook-bucket-user-writer").ForPrincipals("writer")})
+		assert.NoError(t, err)
+		putBodies = []string{}
+		s3svc = newS3Agent(t, current, &putBodies, &deletes)
+		err = p.setBucketPolicy(s3svc, optionsWith(map[string]string{}), nil)
+		assert.NoError(t, err)
+		assert.Empty(t, putBodies)
+		assert.Equal(t, 1, deletes)
+	})
+
+	t.Run("the grant of an OBC is kept with the requested policy", func(t *testing.T) {
+		grant := object.NewPolicyStatement().WithSID("obc-app-obc-fsid").ForPrincipals("obc-app-obc-fsid").ForResources("bkt").Allows().Actions(object.AllowedActions...)
+		putBodies, deletes := []string{}, 0
+		s3svc := newS3Agent(t, "", &putBodies, &deletes)
+		err := p.setBucketPolicy(s3svc, optionsWith(map[string]string{"bucketPolicy": policy}), grant)
+		assert.NoError(t, err)
+		assert.Len(t, putBodies, 1)
+		assert.Contains(t, putBodies[0], `"arn:aws:iam:::user/reader"`)
+		assert.Contains(t, putBodies[0], `"Sid":"obc-app-obc-fsid"`)
+
+		// the policy is not written again
+		current := putBodies[0]
+		putBodies = []string{}
+		s3svc = newS3Agent(t, current, &putBodies, &deletes)
+		err = p.setBucketPolicy(s3svc, optionsWith(map[string]string{"bucketPolicy": policy}), grant)
+		assert.NoError(t, err)
+		assert.Empty(t, putBodies)
+
+		// the grants of the other OBCs attached to the bucket are kept in the requested policy
+		putBodies = []string{}
+		s3svc = newS3Agent(t, current, &putBodies, &deletes)
+		err = p.setBucketPolicy(s3svc, optionsWith(map[string]string{"bucketPolicy": policy, "bucketUsers": "writer:read"}), nil)
+		assert.NoError(t, err)
+		assert.Len(t, putBodies, 1)
+		assert.Contains(t, putBodies[0], `"Sid":"obc-app-obc-fsid"`)
+		assert.Contains(t, putBodies[0], `"Sid":"rook-bucket-user-writer"`)
+	})
+
+	t.Run("invalid bucket users", func(t *testing.T) {
+		putBodies, deletes := []string{}, 0
+		s3svc := newS3Agent(t, "", &putBodies, &deletes)
+		err := p.setBucketPolicy(s3svc, optionsWith(map[string]string{"bucketUsers": "other:read"}), nil)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "is not a user of object store")
+
+		err = p.setBucketPolicy(s3svc, optionsWith(map[string]string{"bucketUsers": "missing:read"}), nil)
+		assert.Error(t, err)
+
+		err = p.setBucketPolicy(s3svc, optionsWith(map[string]string{"bucketUsers": "writer"}), nil)
+		assert.Error(t, err)
+		assert.Empty(t, putBodies)
+	})
+}
+
+func TestProvisioner_setBucketNamePrefix(t *testing.T) {
+	sc := &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "bucket-class"}}
+
+	t.Run("no prefix", func(t *testing.T) {
+		p := &Provisioner{bucketName: "bkt"}
+		assert.NoError(t, p.setBucketNamePrefix(sc))
+		assert.Equal(t, "bkt", p.bucketName)
+	})
+
+	sc.Parameters = map[string]string{"bucketNamePrefix": "team-a-"}
+
+	t.Run("prefix is prepended", func(t *testing.T) {
+		p := &Provisioner{bucketName: "bkt"}
+		assert.NoError(t, p.setBucketNamePrefix(sc))
+		assert.Equal(t, "team-a-bkt", p.bucketName)
+	})
+
+	t.Run("name already has the prefix", func(t *testing.T) {
+		p := &Provisioner{bucketName: "team-a-bkt"}
+		assert.NoError(t, p.setBucketNamePrefix(sc))
+		assert.Equal(t, "team-a-bkt", p.bucketName)
+	})
+
+	t.Run("name is too long", func(t *testing.T) {
+		p := &Provisioner{bucketName: strings.Repeat("b", 60)}
+		assert.Error(t, p.setBucketNamePrefix(sc))
+	})
+}
+
+func TestGetLocationConstraint(t *testing.T) {
+	sc := &storagev1.StorageClass{}
+	assert.Equal(t, "", getLocationConstraint(sc))
+
+	sc.Parameters = map[string]string{"region": "eu"}
+	assert.Equal(t, "eu", getLocationConstraint(sc))
+
+	sc.Parameters = map[string]string{"placementTarget": "fast"}
+	assert.Equal(t, ":fast", getLocationConstraint(sc))
+
+	sc.Parameters = map[string]string{"region": "eu", "placementTarget": "fast"}
+	assert.Equal(t, "eu:fast", getLocationConstraint(sc))
+}
+
+func TestIsNamespaceAllowed(t *testing.T) {
+	sc := &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "bucket-class"}}
+	allowed, err := isNamespaceAllowed(sc, "any")
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+
+	sc.Parameters = map[string]string{"allowedNamespaces": "apps, team-a-*"}
+	for namespace, expected := range map[string]bool{"apps": true, "team-a-dev": true, "team-b-dev": false, "apps2": false} {
+		allowed, err := isNamespaceAllowed(sc, namespace)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, allowed, namespace)
+	}
+
+	sc.Parameters = map[string]string{"allowedNamespaces": ""}
+	allowed, err = isNamespaceAllowed(sc, "apps")
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+
+	sc.Parameters = map[string]string{"allowedNamespaces": "team-[a"}
+	_, err = isNamespaceAllowed(sc, "team-a")
+	assert.Error(t, err)
+}
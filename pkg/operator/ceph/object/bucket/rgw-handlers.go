@@ -6,16 +6,62 @@ import (
 )
 
 func (p *Provisioner) bucketExists(name string) (bool, error) {
-	_, err := p.adminOpsClient.GetBucketInfo(p.clusterInfo.Context, admin.Bucket{Bucket: name})
+	if _, ok := p.cachedBucket(name); ok {
+		return true, nil
+	}
+	bucket, err := p.adminOpsClient.GetBucketInfo(p.clusterInfo.Context, admin.Bucket{Bucket: name})
 	if err != nil {
 		if errors.Is(err, admin.ErrNoSuchBucket) {
 			return false, nil
 		}
 		return false, errors.Wrapf(err, "failed to get ceph bucket %q", name)
 	}
+	p.cacheBucket(name, bucket.Owner)
 	return true, nil
 }
 
+// bucketOwner returns the owner of the bucket, from the cache if the owner was read already
+func (p *Provisioner) bucketOwner(name string) (string, error) {
+	if owner, ok := p.cachedBucket(name); ok && owner != "" {
+		return owner, nil
+	}
+	bucket, err := p.adminOpsClient.GetBucketInfo(p.clusterInfo.Context, admin.Bucket{Bucket: name})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get bucket %q stats", name)
+	}
+	p.cacheBucket(name, bucket.Owner)
+	return bucket.Owner, nil
+}
+
+// cachedBucket returns the owner of the bucket and whether the bucket is in the listing of the buckets of the store.
+// The buckets of the store are listed with a single call when the listing expired.
+func (p *Provisioner) cachedBucket(name string) (string, bool) {
+	if p.buckets == nil || p.objectStore == nil {
+		return "", false
+	}
+	owner, exists, valid := p.buckets.get(p.objectStore, name)
+	if valid {
+		return owner, exists
+	}
+
+	names, err := p.adminOpsClient.ListBuckets(p.clusterInfo.Context)
+	if err != nil {
+		logger.Warningf("failed to list the buckets of object store %q, reading bucket %q alone. %v", p.objectStoreName, name, err)
+		return "", false
+	}
+	logger.Debugf("listed %d buckets of object store %q", len(names), p.objectStoreName)
+	p.buckets.setListing(p.objectStore, names)
+	owner, exists, _ = p.buckets.get(p.objectStore, name)
+	return owner, exists
+}
+
+// cacheBucket records a bucket created or read since the buckets of the store were listed
+func (p *Provisioner) cacheBucket(name, owner string) {
+	if p.buckets != nil && p.objectStore != nil {
+		p.buckets.add(p.objectStore, name, owner)
+	}
+}
+
 // Create a Ceph user based on the passed-in name or a generated name. Return the
 // accessKeys and set user name and keys in receiver.
 func (p *Provisioner) createCephUser(username string) (accKey string, secKey string, err error) {
@@ -39,14 +85,33 @@ func (p *Provisioner) createCephUser(username string) (accKey string, secKey str
 				return "", "", errors.Wrapf(err, "failed to create ceph object user %v", userConfig.ID)
 			}
 		} else {
+			// the cached admin ops credentials may have been rotated, read them again on the next reconcile
+			if p.adminOpsCredentials != nil {
+				p.adminOpsCredentials.invalidate(p.clusterInfo.Namespace, p.objectStoreName)
+			}
 			return "", "", errors.Wrapf(err, "failed to get ceph user %q", username)
 		}
 	}
+	p.cephUser = &u
 
 	logger.Infof("successfully created Ceph user %q with access keys", username)
 	return u.Keys[0].AccessKey, u.Keys[0].SecretKey, nil
 }
 
+// setUserMaxBuckets sets the maximum number of buckets of the ceph user, unless the user read by the reconcile already
+// has it, since most of the reconciles of the OBCs do not change anything
+func (p *Provisioner) setUserMaxBuckets(maxBuckets int) error {
+	if p.cephUser != nil && p.cephUser.MaxBuckets != nil && *p.cephUser.MaxBuckets == maxBuckets {
+		return nil
+	}
+	_, err := p.adminOpsClient.ModifyUser(p.clusterInfo.Context, admin.User{ID: p.cephUserName, MaxBuckets: &maxBuckets})
+	if err != nil {
+		return err
+	}
+	logger.Infof("set user %q bucket max to %d", p.cephUserName, maxBuckets)
+	return nil
+}
+
 func (p *Provisioner) genUserName(obcName, obcNamespace string) (string, error) {
 	// user name can be deterministically generated by obc name and namespace. since there can't be
 	// 2 obcs in the same namespace w/ the same name, this won't collide w/in the same k8s cluster.
@@ -55,7 +120,7 @@ func (p *Provisioner) genUserName(obcName, obcNamespace string) (string, error)
 	if p.clusterInfo.FSID == "" {
 		return "", errors.Errorf("failed to find ceph cluster FSID")
 	}
-	return "obc-" + obcNamespace + "-" + obcName + "-" + fsid, nil
+	return obcUserPrefix + obcNamespace + "-" + obcName + "-" + fsid, nil
 }
 
 // Delete the user and bucket created by OBC with help of radosgw-admin commands
@@ -69,6 +134,9 @@ func (p *Provisioner) deleteOBCResource(bucketName string) error {
 		// delete bucket with purge option to remove all objects
 		thePurge := true
 		err := p.adminOpsClient.RemoveBucket(p.clusterInfo.Context, admin.Bucket{Bucket: bucketName, PurgeObject: &thePurge})
+		if p.buckets != nil {
+			p.buckets.remove(p.clusterInfo.Namespace, p.objectStoreName, bucketName)
+		}
 		if err == nil {
 			logger.Infof("bucket %q successfully deleted", bucketName)
 		} else if errors.Is(err, admin.ErrNoSuchBucket) {
@@ -25,12 +25,14 @@ import (
 	"testing"
 
 	"github.com/ceph/go-ceph/rgw/admin"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	rookclient "github.com/rook/rook/pkg/client/clientset/versioned/fake"
 	"github.com/rook/rook/pkg/clusterd"
 	"github.com/rook/rook/pkg/daemon/ceph/client"
 	cephobject "github.com/rook/rook/pkg/operator/ceph/object"
 	"github.com/rook/rook/pkg/operator/test"
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type statusError struct {
@@ -90,3 +92,96 @@ func TestDeleteOBCResource(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestSetUserMaxBuckets(t *testing.T) {
+	putValsSeen := []string{}
+	mockClient := &cephobject.MockClient{
+		MockDo: func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path == "my.endpoint.net/admin/user" && req.Method == http.MethodPost {
+				putValsSeen = append(putValsSeen, req.URL.RawQuery)
+				return &http.Response{
+					StatusCode: 200,
+					Body:       io.NopCloser(bytes.NewReader([]byte(`{"user_id":"bob","max_buckets":1}`))),
+				}, nil
+			}
+			return nil, fmt.Errorf("unexpected request: %q. method %q. path %q", req.URL.RawQuery, req.Method, req.URL.Path)
+		},
+	}
+	adminClient, err := admin.New("my.endpoint.net", "accesskey", "secretkey", mockClient)
+	assert.NoError(t, err)
+	p := &Provisioner{
+		clusterInfo:    client.AdminTestClusterInfo("ns"),
+		cephUserName:   "bob",
+		adminOpsClient: adminClient,
+	}
+
+	// the user was not read by the reconcile
+	assert.NoError(t, p.setUserMaxBuckets(1))
+	assert.Len(t, putValsSeen, 1)
+	assert.Contains(t, putValsSeen[0], "max-buckets=1")
+
+	// the user already has the max buckets
+	maxBuckets := 1
+	p.cephUser = &admin.User{ID: "bob", MaxBuckets: &maxBuckets}
+	assert.NoError(t, p.setUserMaxBuckets(1))
+	assert.Len(t, putValsSeen, 1)
+
+	// the max buckets of the user changed
+	assert.NoError(t, p.setUserMaxBuckets(0))
+	assert.Len(t, putValsSeen, 2)
+	assert.Contains(t, putValsSeen[1], "max-buckets=0")
+}
+
+func TestBucketExists(t *testing.T) {
+	clusterInfo := client.AdminTestClusterInfo("ns")
+	p := NewProvisioner(&clusterd.Context{RookClientset: rookclient.NewSimpleClientset(), Clientset: test.New(t, 1)}, clusterInfo)
+	p.objectStoreName = "my-store"
+	p.objectStore = &cephv1.CephObjectStore{ObjectMeta: metav1.ObjectMeta{Name: "my-store", Namespace: "ns", UID: "uid"}}
+
+	listings, infos := 0, 0
+	adminClient, err := admin.New("rook-ceph-rgw-my-store.mycluster.svc", "53S6B9S809NUP19IJ2K3", "1bXPegzsGClvoGAiJdHQD1uOW2sQBLAZM9j9VtXR", &cephobject.MockClient{
+		MockDo: func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path == "rook-ceph-rgw-my-store.mycluster.svc/admin/bucket" && req.Method == http.MethodGet {
+				bucket := req.URL.Query().Get("bucket")
+				if bucket == "" {
+					listings++
+					return &http.Response{StatusCode: 200, Body: io.NopCloser(bytes.NewReader([]byte(`["bucket-a","bucket-b"]`)))}, nil
+				}
+				infos++
+				if bucket == "bucket-c" {
+					return &http.Response{StatusCode: 200, Body: io.NopCloser(bytes.NewReader([]byte(`{"bucket":"bucket-c","owner":"user-c"}`)))}, nil
+				}
+				status, _ := json.Marshal(statusError{"NoSuchBucket", "requestid", "hostid"})
+				return &http.Response{StatusCode: 404, Body: io.NopCloser(bytes.NewReader(status))}, nil
+			}
+			return nil, fmt.Errorf("unexpected request: %q. method %q. path %q", req.URL.RawQuery, req.Method, req.URL.Path)
+		},
+	})
+	assert.NoError(t, err)
+	p.adminOpsClient = adminClient
+
+	// the buckets of the store are listed once
+	exists, err := p.bucketExists("bucket-a")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = p.bucketExists("bucket-b")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, 1, listings)
+	assert.Equal(t, 0, infos)
+
+	// a bucket created since the listing is read alone, and then cached with its owner
+	exists, err = p.bucketExists("bucket-c")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	owner, err := p.bucketOwner("bucket-c")
+	assert.NoError(t, err)
+	assert.Equal(t, "user-c", owner)
+	assert.Equal(t, 1, infos)
+
+	exists, err = p.bucketExists("bucket-d")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+	assert.Equal(t, 1, listings)
+	assert.Equal(t, 2, infos)
+}
@@ -18,6 +18,8 @@ package bucket
 
 import (
 	"fmt"
+	"path"
+	"strings"
 
 	"github.com/coreos/pkg/capnslog"
 	"github.com/kube-object-storage/lib-bucket-provisioner/pkg/apis/objectbucket.io/v1alpha1"
@@ -40,6 +42,27 @@ const (
 	ObjectStoreName      = "objectStoreName"
 	ObjectStoreNamespace = "objectStoreNamespace"
 	objectStoreEndpoint  = "endpoint"
+
+	// StorageClass parameters applied to the buckets provisioned dynamically
+	bucketNamePrefixParam = "bucketNamePrefix"
+	regionParam           = "region"
+	placementTargetParam  = "placementTarget"
+
+	// allowedNamespacesParam is the StorageClass parameter restricting the namespaces of the OBCs using it
+	allowedNamespacesParam = "allowedNamespaces"
+
+	// maxBucketNameLength is the maximum length of an S3 bucket name
+	maxBucketNameLength = 63
+
+	// bucketPolicyConfigMapKey is the key of the policy document in the ConfigMap referenced by an OBC
+	bucketPolicyConfigMapKey = "policy"
+
+	// bucketUserSidPrefix is the prefix of the sids of the bucket policy statements granting the bucket users access
+	bucketUserSidPrefix = "rook-bucket-user-"
+
+	// obcUserPrefix is the prefix of the names of the users generated for the OBCs, which are also the sids of the
+	// bucket policy statements granting them access to existing buckets
+	obcUserPrefix = "obc-"
 )
 
 func NewBucketController(cfg *rest.Config, p *Provisioner, data map[string]string) (*provisioner.Provisioner, error) {
@@ -68,11 +91,56 @@ func isStaticBucket(sc *storagev1.StorageClass) (string, bool) {
 	return val, ok
 }
 
+// getBucketNamePrefix returns the prefix the StorageClass adds to the name of the dynamically provisioned buckets
+func getBucketNamePrefix(sc *storagev1.StorageClass) string {
+	return sc.Parameters[bucketNamePrefixParam]
+}
+
+// getLocationConstraint returns the S3 location constraint of the buckets created with the StorageClass, in the
+// "<zonegroup api name>:<placement target>" form that RGW expects. It is empty if the StorageClass sets neither
+// the region nor the placement target, in which case RGW uses its defaults.
+func getLocationConstraint(sc *storagev1.StorageClass) string {
+	region := sc.Parameters[regionParam]
+	placement := sc.Parameters[placementTargetParam]
+	if placement == "" {
+		return region
+	}
+	return fmt.Sprintf("%s:%s", region, placement)
+}
+
+// isNamespaceAllowed returns whether OBCs of the namespace may use the StorageClass. The "allowedNamespaces"
+// parameter is a comma-separated list of namespaces, which may be glob patterns like "team-a-*". All the
+// namespaces are allowed if the parameter is not set.
+func isNamespaceAllowed(sc *storagev1.StorageClass, namespace string) (bool, error) {
+	allowed, ok := sc.Parameters[allowedNamespacesParam]
+	if !ok {
+		return true, nil
+	}
+	for _, pattern := range strings.Split(allowed, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		match, err := path.Match(pattern, namespace)
+		if err != nil {
+			return false, errors.Wrapf(err, "invalid pattern %q in the %q parameter of storage class %q", pattern, allowedNamespacesParam, sc.Name)
+		}
+		if match {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func getCephUser(ob *bktv1alpha1.ObjectBucket) string {
 	return ob.Spec.AdditionalState[CephUser]
 }
 
 func (p *Provisioner) getObjectStore() (*cephv1.CephObjectStore, error) {
+	// the store is needed several times by a reconcile, only get it once
+	if p.objectStore != nil && p.objectStore.Name == p.objectStoreName {
+		return p.objectStore, nil
+	}
 	ctx := p.clusterInfo.Context
 	// Verify the object store API object actually exists
 	store, err := p.context.RookClientset.CephV1().CephObjectStores(p.clusterInfo.Namespace).Get(ctx, p.objectStoreName, metav1.GetOptions{})
@@ -82,7 +150,8 @@ func (p *Provisioner) getObjectStore() (*cephv1.CephObjectStore, error) {
 		}
 		return nil, errors.Wrapf(err, "failed to get ceph object store %q", p.objectStoreName)
 	}
-	return store, err
+	p.objectStore = store
+	return store, nil
 }
 
 func (p *Provisioner) getCephCluster() (*cephv1.CephCluster, error) {
@@ -106,6 +175,42 @@ func MaxSizeQuota(AdditionalConfig map[string]string) string {
 	return AdditionalConfig["maxSize"]
 }
 
+func BucketVersioning(AdditionalConfig map[string]string) string {
+	return AdditionalConfig["bucketVersioning"]
+}
+
+func BucketEncryption(AdditionalConfig map[string]string) string {
+	return AdditionalConfig["bucketEncryption"]
+}
+
+func BucketPolicy(AdditionalConfig map[string]string) string {
+	return AdditionalConfig["bucketPolicy"]
+}
+
+func BucketPolicyConfigMap(AdditionalConfig map[string]string) string {
+	return AdditionalConfig["bucketPolicyConfigMap"]
+}
+
+func BucketUsers(AdditionalConfig map[string]string) string {
+	return AdditionalConfig["bucketUsers"]
+}
+
+func MaxReadOps(AdditionalConfig map[string]string) string {
+	return AdditionalConfig["maxReadOps"]
+}
+
+func MaxWriteOps(AdditionalConfig map[string]string) string {
+	return AdditionalConfig["maxWriteOps"]
+}
+
+func MaxReadBytes(AdditionalConfig map[string]string) string {
+	return AdditionalConfig["maxReadBytes"]
+}
+
+func MaxWriteBytes(AdditionalConfig map[string]string) string {
+	return AdditionalConfig["maxWriteBytes"]
+}
+
 func GetObjectStoreNameFromBucket(ob *v1alpha1.ObjectBucket) (types.NamespacedName, error) {
 	// Rook v1.11 OBCs have additional state labels that tell the object store namespace and name.
 	// This is critical for CephObjectStores in external mode that connect to RGW endpoints directly
@@ -0,0 +1,178 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	defaultPlacementTarget = "default-placement"
+	cloudTierType          = "cloud-s3"
+)
+
+// configureCloudTiers adds the cloud tiers of the object store as storage classes of the placement targets of its
+// zonegroup and commits the period if the zonegroup changed. Cloud tiers removed from the spec are left in the
+// zonegroup since objects may have been transitioned to them.
+func configureCloudTiers(objContext *Context, store *cephv1.CephObjectStore) error {
+	if len(store.Spec.CloudTiers) == 0 {
+		return nil
+	}
+	if !objContext.clusterInfo.CephVersion.IsAtLeastReef() {
+		return errors.Errorf("cloud tiers require ceph reef or newer, current version is %q", objContext.clusterInfo.CephVersion.String())
+	}
+
+	credentials := map[string]cloudTierCredentials{}
+	for _, tier := range store.Spec.CloudTiers {
+		tierCredentials, err := getCloudTierCredentials(objContext, store.Namespace, tier)
+		if err != nil {
+			return err
+		}
+
+		placementTarget := cloudTierPlacementTarget(tier)
+		args := []string{
+			fmt.Sprintf("--placement-id=%s", placementTarget),
+			fmt.Sprintf("--storage-class=%s", tier.Name),
+		}
+
+		// adding an existing storage class only resets its tier type
+		addArgs := append([]string{"zonegroup", "placement", "add", fmt.Sprintf("--tier-type=%s", cloudTierType)}, args...)
+		if output, err := runAdminCommand(objContext, false, addArgs...); err != nil {
+			return errorOrIsNotFound(err, "failed to add cloud tier %q to placement target %q. %s", tier.Name, placementTarget, output)
+		}
+		modifyArgs := append([]string{"zonegroup", "placement", "modify", fmt.Sprintf("--tier-config=%s", getCloudTierConfig(tier))}, args...)
+		if output, err := runAdminCommand(objContext, false, modifyArgs...); err != nil {
+			return errorOrIsNotFound(err, "failed to configure cloud tier %q of placement target %q. %s", tier.Name, placementTarget, output)
+		}
+		credentials[placementTarget+"/"+tier.Name] = tierCredentials
+		logger.Debugf("configured cloud tier %q of placement target %q", tier.Name, placementTarget)
+	}
+
+	// the credentials are passed on stdin since the arguments of the commands are logged
+	if err := setCloudTierCredentials(objContext, credentials); err != nil {
+		return err
+	}
+
+	if err := commitConfigChanges(objContext); err != nil {
+		nsName := fmt.Sprintf("%s/%s", objContext.clusterInfo.Namespace, objContext.Name)
+		return errors.Wrapf(err, "failed to commit config changes after configuring cloud tiers for CephObjectStore %q", nsName)
+	}
+
+	logger.Infof("configured cloud tiers for object store %q", store.Name)
+	return nil
+}
+
+type cloudTierCredentials struct {
+	AccessKey string `json:"access_key"`
+	Secret    string `json:"secret"`
+}
+
+func cloudTierPlacementTarget(tier cephv1.CloudTierSpec) string {
+	if tier.PlacementTarget == "" {
+		return defaultPlacementTarget
+	}
+	return tier.PlacementTarget
+}
+
+// getCloudTierCredentials returns the credentials of the remote S3 endpoint of a cloud tier
+func getCloudTierCredentials(objContext *Context, namespace string, tier cephv1.CloudTierSpec) (cloudTierCredentials, error) {
+	secret, err := objContext.Context.Clientset.CoreV1().Secrets(namespace).Get(objContext.clusterInfo.Context, tier.CredentialsSecretName, metav1.GetOptions{})
+	if err != nil {
+		return cloudTierCredentials{}, errors.Wrapf(err, "failed to get credentials secret %q of cloud tier %q", tier.CredentialsSecretName, tier.Name)
+	}
+	accessKey, err := DecodeSecret(secret, AccessKeyName)
+	if err != nil {
+		return cloudTierCredentials{}, errors.Wrapf(err, "failed to get the access key of cloud tier %q", tier.Name)
+	}
+	secretKey, err := DecodeSecret(secret, SecretKeyName)
+	if err != nil {
+		return cloudTierCredentials{}, errors.Wrapf(err, "failed to get the secret key of cloud tier %q", tier.Name)
+	}
+	return cloudTierCredentials{AccessKey: accessKey, Secret: secretKey}, nil
+}
+
+// getCloudTierConfig returns the tier config of a cloud tier, without its credentials, in the comma separated form
+// expected by radosgw-admin
+func getCloudTierConfig(tier cephv1.CloudTierSpec) string {
+	config := []string{
+		fmt.Sprintf("endpoint=%s", tier.Endpoint),
+		fmt.Sprintf("retain_head_object=%t", tier.RetainHeadObject),
+	}
+	if tier.Region != "" {
+		config = append(config, fmt.Sprintf("region=%s", tier.Region))
+	}
+	if tier.TargetPath != "" {
+		config = append(config, fmt.Sprintf("target_path=%s", tier.TargetPath))
+	}
+	return strings.Join(config, ",")
+}
+
+// setCloudTierCredentials sets the credentials of the cloud tiers in the tier targets of the placement targets of the
+// zonegroup, keyed by "<placement target>/<storage class>". The zonegroup is only updated when the credentials change.
+func setCloudTierCredentials(objContext *Context, credentials map[string]cloudTierCredentials) error {
+	output, err := runAdminCommand(objContext, true, "zonegroup", "get")
+	if err != nil {
+		return errorOrIsNotFound(err, "failed to get zonegroup %q", objContext.ZoneGroup)
+	}
+	zoneGroupConfig := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(output), &zoneGroupConfig); err != nil {
+		return errors.Wrapf(err, "failed to parse config of zonegroup %q", objContext.ZoneGroup)
+	}
+
+	changed := false
+	placementTargets, _ := zoneGroupConfig["placement_targets"].([]interface{})
+	for _, p := range placementTargets {
+		placementTarget, _ := p.(map[string]interface{})
+		tierTargets, _ := placementTarget["tier_targets"].([]interface{})
+		for _, t := range tierTargets {
+			tierTarget, _ := t.(map[string]interface{})
+			tierCredentials, ok := credentials[fmt.Sprintf("%v/%v", placementTarget["name"], tierTarget["key"])]
+			if !ok {
+				continue
+			}
+			tierConfig, _ := tierTarget["val"].(map[string]interface{})
+			s3Config, _ := tierConfig["s3"].(map[string]interface{})
+			if s3Config == nil {
+				return errors.Errorf("failed to find the s3 config of cloud tier %q of zonegroup %q", tierTarget["key"], objContext.ZoneGroup)
+			}
+			current, _ := s3Config["credentials"].(map[string]interface{})
+			if current["access_key"] == tierCredentials.AccessKey && current["secret"] == tierCredentials.Secret {
+				continue
+			}
+			s3Config["credentials"] = map[string]interface{}{"access_key": tierCredentials.AccessKey, "secret": tierCredentials.Secret}
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	zoneGroupJSON, err := json.Marshal(zoneGroupConfig)
+	if err != nil {
+		return errors.Wrapf(err, "failed to serialize config of zonegroup %q", objContext.ZoneGroup)
+	}
+	if err := RunAdminCommandWithStdin(objContext, string(zoneGroupJSON), "zonegroup", "set"); err != nil {
+		return errors.Wrapf(err, "failed to set the credentials of the cloud tiers of zonegroup %q", objContext.ZoneGroup)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestConfigureCloudTiers(t *testing.T) {
+	commitConfigChangesOrig := commitConfigChanges
+	defer func() { commitConfigChanges = commitConfigChangesOrig }()
+	committed := false
+	commitConfigChanges = func(c *Context) error {
+		committed = true
+		return nil
+	}
+
+	commands := []string{}
+	zoneGroupSet := ""
+	zoneGroup := `{"name":"my-store","placement_targets":[{"name":"default-placement","storage_classes":["CLOUDTIER","STANDARD"],
+		"tier_targets":[{"key":"CLOUDTIER","val":{"tier_type":"cloud-s3","storage_class":"CLOUDTIER",
+		"s3":{"endpoint":"https://s3.amazonaws.com","credentials":{"access_key":"","secret":""}}}}]}]}`
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			commands = append(commands, strings.Join(args, " "))
+			if args[0] == "zonegroup" && args[1] == "get" {
+				return zoneGroup, nil
+			}
+			return "", nil
+		},
+		MockExecuteCommandWithStdin: func(timeout time.Duration, command string, stdin *string, args ...string) error {
+			commands = append(commands, strings.Join(args, " "))
+			zoneGroupSet = *stdin
+			return nil
+		},
+	}
+	clientset := fake.NewSimpleClientset(&v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "aws-creds", Namespace: "rook-ceph"},
+		Data:       map[string][]byte{AccessKeyName: []byte("access"), SecretKeyName: []byte("secret")},
+	})
+	clusterInfo := client.AdminTestClusterInfo("rook-ceph")
+	clusterInfo.CephVersion = cephver.Reef
+	clusterInfo.Context = context.TODO()
+	objContext := NewContext(&clusterd.Context{Executor: executor, Clientset: clientset}, clusterInfo, "my-store")
+	objContext.Realm = "my-store"
+	objContext.ZoneGroup = "my-store"
+	objContext.Zone = "my-store"
+	store := &cephv1.CephObjectStore{ObjectMeta: metav1.ObjectMeta{Name: "my-store", Namespace: "rook-ceph"}}
+
+	t.Run("no cloud tiers", func(t *testing.T) {
+		err := configureCloudTiers(objContext, store)
+		assert.NoError(t, err)
+		assert.Empty(t, commands)
+		assert.False(t, committed)
+	})
+
+	store.Spec.CloudTiers = []cephv1.CloudTierSpec{
+		{Name: "CLOUDTIER", Endpoint: "https://s3.amazonaws.com", Region: "us-east-1", CredentialsSecretName: "aws-creds", TargetPath: "archive"},
+	}
+
+	t.Run("cloud tier is configured", func(t *testing.T) {
+		err := configureCloudTiers(objContext, store)
+		assert.NoError(t, err)
+		assert.Len(t, commands, 4)
+		assert.Contains(t, commands[0], "zonegroup placement add --tier-type=cloud-s3 --placement-id=default-placement --storage-class=CLOUDTIER")
+		assert.Contains(t, commands[1], "zonegroup placement modify --tier-config=endpoint=https://s3.amazonaws.com,retain_head_object=false,region=us-east-1,target_path=archive")
+		assert.Contains(t, commands[1], "--rgw-zonegroup=my-store")
+		assert.Contains(t, commands[2], "zonegroup get")
+		assert.Contains(t, commands[3], "zonegroup set")
+		// the credentials are only passed on stdin
+		for _, command := range commands {
+			assert.NotContains(t, command, "secret")
+		}
+		assert.Contains(t, zoneGroupSet, `"credentials":{"access_key":"access","secret":"secret"}`)
+		assert.True(t, committed)
+	})
+
+	t.Run("credentials are unchanged", func(t *testing.T) {
+		commands = []string{}
+		zoneGroup = zoneGroupSet
+		err := configureCloudTiers(objContext, store)
+		assert.NoError(t, err)
+		assert.Len(t, commands, 3)
+		assert.NotContains(t, strings.Join(commands, ";"), "zonegroup set")
+	})
+
+	t.Run("missing credentials", func(t *testing.T) {
+		store.Spec.CloudTiers[0].CredentialsSecretName = "missing"
+		err := configureCloudTiers(objContext, store)
+		assert.Error(t, err)
+	})
+
+	t.Run("ceph version too old", func(t *testing.T) {
+		store.Spec.CloudTiers[0].CredentialsSecretName = "aws-creds"
+		clusterInfo.CephVersion = cephver.Quincy
+		err := configureCloudTiers(objContext, store)
+		assert.Error(t, err)
+	})
+}
@@ -17,16 +17,22 @@ limitations under the License.
 package object
 
 import (
+	"encoding/json"
 	"fmt"
 	"path"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	cephconfig "github.com/rook/rook/pkg/operator/ceph/config"
 	"github.com/rook/rook/pkg/operator/ceph/config/keyring"
+	"github.com/rook/rook/pkg/operator/k8sutil"
 	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
 const (
@@ -54,6 +60,19 @@ caps osd = "allow rwx"
 	HttpTimeOut                     = time.Second * 15
 	rgwVaultVolumeName              = "rgw-vault-volume"
 	rgwVaultDirName                 = "/etc/vault/rgw/"
+
+	rgwDynamicReshardingOption            = "rgw_dynamic_resharding"
+	rgwOverrideBucketIndexMaxShardsOption = "rgw_override_bucket_index_max_shards"
+	rgwGCMaxObjsOption                    = "rgw_gc_max_objs"
+	rgwGCProcessorPeriodOption            = "rgw_gc_processor_period"
+	rgwGCMaxConcurrentIOOption            = "rgw_gc_max_concurrent_io"
+	rgwUserMaxBucketsOption               = "rgw_user_max_buckets"
+	rgwUserDefaultQuotaMaxSizeOption      = "rgw_user_default_quota_max_size"
+	rgwUserDefaultQuotaMaxObjectsOption   = "rgw_user_default_quota_max_objects"
+	rgwBucketDefaultQuotaMaxSizeOption    = "rgw_bucket_default_quota_max_size"
+	rgwBucketDefaultQuotaMaxObjectsOption = "rgw_bucket_default_quota_max_objects"
+
+	appliedGatewayConfigKey = "config"
 )
 
 var (
@@ -90,6 +109,36 @@ func (c *clusterConfig) portString() string {
 	return portString
 }
 
+// frontendString returns the rgw frontends option: the beast frontend, its ports and its tuning from the spec
+func (c *clusterConfig) frontendString() string {
+	options := []string{rgwFrontendName}
+	if portString := c.portString(); portString != "" {
+		options = append(options, portString)
+	}
+
+	frontend := c.store.Spec.Gateway.Frontend
+	if frontend == nil {
+		return strings.Join(options, " ")
+	}
+	if frontend.RequestTimeout != nil {
+		options = append(options, fmt.Sprintf("request_timeout_ms=%d", frontend.RequestTimeout.Milliseconds()))
+	}
+	if frontend.MaxHeaderSize > 0 {
+		options = append(options, fmt.Sprintf("max_header_size=%d", frontend.MaxHeaderSize))
+	}
+	if frontend.MaxConnectionBacklog > 0 {
+		options = append(options, fmt.Sprintf("max_connection_backlog=%d", frontend.MaxConnectionBacklog))
+	}
+	if frontend.TCPNoDelay != nil {
+		tcpNoDelay := 0
+		if *frontend.TCPNoDelay {
+			tcpNoDelay = 1
+		}
+		options = append(options, fmt.Sprintf("tcp_nodelay=%d", tcpNoDelay))
+	}
+	return strings.Join(options, " ")
+}
+
 func generateCephXUser(name string) string {
 	user := strings.TrimPrefix(name, AppName)
 	return "client.rgw" + strings.Replace(user, "-", ".", -1)
@@ -99,7 +148,8 @@ func (c *clusterConfig) generateKeyring(rgwConfig *rgwConfig) (string, error) {
 	user := generateCephXUser(rgwConfig.ResourceName)
 	/* TODO: this says `osd allow rwx` while template says `osd allow *`; which is correct? */
 	access := []string{"osd", "allow rwx", "mon", "allow rw"}
-	s := keyring.GetSecretStore(c.context, c.clusterInfo, c.ownerInfo)
+	// the keyring is labeled like the other resources of the rgw daemons, to select them
+	s := keyring.GetSecretStore(c.context, c.clusterInfo, c.ownerInfo).WithLabels(getLabels(c.store.Name, c.store.Namespace, false))
 
 	key, err := s.GenerateKey(user, access)
 	if err != nil {
@@ -110,9 +160,7 @@ func (c *clusterConfig) generateKeyring(rgwConfig *rgwConfig) (string, error) {
 	return keyring, s.CreateOrUpdate(rgwConfig.ResourceName, keyring)
 }
 
-func (c *clusterConfig) setDefaultFlagsMonConfigStore(rgwConfig *rgwConfig) error {
-	monStore := cephconfig.GetMonStore(c.context, c.clusterInfo)
-	who := generateCephXUser(rgwConfig.ResourceName)
+func defaultFlagsMonConfigStore(rgwConfig *rgwConfig) map[string]string {
 	configOptions := make(map[string]string)
 
 	configOptions["rgw_log_nonexistent_bucket"] = "true"
@@ -121,7 +169,14 @@ func (c *clusterConfig) setDefaultFlagsMonConfigStore(rgwConfig *rgwConfig) erro
 	configOptions["rgw_zone"] = rgwConfig.Zone
 	configOptions["rgw_zonegroup"] = rgwConfig.ZoneGroup
 
-	for flag, val := range configOptions {
+	return configOptions
+}
+
+func (c *clusterConfig) setDefaultFlagsMonConfigStore(rgwConfig *rgwConfig) error {
+	monStore := cephconfig.GetMonStore(c.context, c.clusterInfo)
+	who := generateCephXUser(rgwConfig.ResourceName)
+
+	for flag, val := range defaultFlagsMonConfigStore(rgwConfig) {
 		err := monStore.Set(who, flag, val)
 		if err != nil {
 			return errors.Wrapf(err, "failed to set %q to %q on %q", flag, val, who)
@@ -131,6 +186,202 @@ func (c *clusterConfig) setDefaultFlagsMonConfigStore(rgwConfig *rgwConfig) erro
 	return nil
 }
 
+// bucketIndexConfigOptions returns the config options of the bucket index settings of the spec
+func bucketIndexConfigOptions(bucketIndex *cephv1.BucketIndexSpec) map[string]string {
+	options := map[string]string{}
+	if bucketIndex == nil {
+		return options
+	}
+	if bucketIndex.DynamicResharding != nil {
+		options[rgwDynamicReshardingOption] = strconv.FormatBool(*bucketIndex.DynamicResharding)
+	}
+	if bucketIndex.OverrideMaxShards != nil {
+		options[rgwOverrideBucketIndexMaxShardsOption] = strconv.Itoa(int(*bucketIndex.OverrideMaxShards))
+	}
+	return options
+}
+
+// garbageCollectionConfigOptions returns the config options of the garbage collection settings of the spec
+func garbageCollectionConfigOptions(gc *cephv1.GarbageCollectionSpec) map[string]string {
+	options := map[string]string{}
+	if gc == nil {
+		return options
+	}
+	if gc.MaxObjects != nil {
+		options[rgwGCMaxObjsOption] = strconv.Itoa(int(*gc.MaxObjects))
+	}
+	if gc.ProcessorPeriod != nil {
+		options[rgwGCProcessorPeriodOption] = strconv.Itoa(int(gc.ProcessorPeriod.Round(time.Second).Seconds()))
+	}
+	if gc.MaxConcurrentIO != nil {
+		options[rgwGCMaxConcurrentIOOption] = strconv.Itoa(int(*gc.MaxConcurrentIO))
+	}
+	return options
+}
+
+// zoneGroupDefaultsConfigOptions returns the config options of the user and bucket defaults of the zone group
+func zoneGroupDefaultsConfigOptions(defaults *cephv1.ZoneGroupDefaultsSpec) map[string]string {
+	options := map[string]string{}
+	if defaults == nil {
+		return options
+	}
+	if defaults.MaxBuckets != nil {
+		options[rgwUserMaxBucketsOption] = strconv.Itoa(*defaults.MaxBuckets)
+	}
+	if quota := defaults.UserQuota; quota != nil {
+		if quota.MaxSize != nil {
+			options[rgwUserDefaultQuotaMaxSizeOption] = strconv.FormatInt(quota.MaxSize.Value(), 10)
+		}
+		if quota.MaxObjects != nil {
+			options[rgwUserDefaultQuotaMaxObjectsOption] = strconv.FormatInt(*quota.MaxObjects, 10)
+		}
+	}
+	if quota := defaults.BucketQuota; quota != nil {
+		if quota.MaxSize != nil {
+			options[rgwBucketDefaultQuotaMaxSizeOption] = strconv.FormatInt(quota.MaxSize.Value(), 10)
+		}
+		if quota.MaxObjects != nil {
+			options[rgwBucketDefaultQuotaMaxObjectsOption] = strconv.FormatInt(*quota.MaxObjects, 10)
+		}
+	}
+	return options
+}
+
+// zoneGroupDefaultsOptions returns the config options of the user and bucket defaults of the zone group of the store
+func (c *clusterConfig) zoneGroupDefaultsOptions() (map[string]string, error) {
+	zoneGroup, err := getZoneGroupForObjectStore(c.clusterInfo.Context, c.context, &c.store.Spec, c.store.Namespace)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the zone group of object store %q", c.store.Name)
+	}
+	if zoneGroup == nil {
+		return map[string]string{}, nil
+	}
+	return zoneGroupDefaultsConfigOptions(zoneGroup.Spec.Defaults), nil
+}
+
+// gatewaySpecConfigOptions returns the config options of the gateway spec, named the way the mon database reports
+// them, with underscores
+func gatewaySpecConfigOptions(config map[string]string) map[string]string {
+	normalize := strings.NewReplacer(" ", "_", "-", "_")
+	options := map[string]string{}
+	for option, val := range config {
+		options[normalize.Replace(option)] = val
+	}
+	return options
+}
+
+// gatewayConfigOptions returns the config options of all the settings of the store. The gateway config of the spec
+// overrides the defaults of the zone group, and the dedicated settings of the spec override both.
+func (c *clusterConfig) gatewayConfigOptions() (map[string]string, error) {
+	zoneGroupDefaults, err := c.zoneGroupDefaultsOptions()
+	if err != nil {
+		return nil, err
+	}
+	sts, err := c.stsConfigOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	options := map[string]string{}
+	for _, featureOptions := range []map[string]string{
+		zoneGroupDefaults,
+		gatewaySpecConfigOptions(c.store.Spec.Gateway.Config),
+		hostingConfigOptions(c.store.Spec.Hosting),
+		swiftConfigOptions(c.store.Spec.Protocols.Swift),
+		bucketIndexConfigOptions(c.store.Spec.BucketIndex),
+		garbageCollectionConfigOptions(c.store.Spec.GarbageCollection),
+		proxyConfigOptions(c.store.Spec.Gateway.Proxy),
+		sts,
+	} {
+		for option, val := range featureOptions {
+			options[option] = val
+		}
+	}
+	return options, nil
+}
+
+// appliedGatewayConfigName is the configmap recording the names of the options rook set on a rgw from the spec, so
+// that the options removed from the spec can be removed from the mon database
+func appliedGatewayConfigName(rgwName string) string {
+	return fmt.Sprintf("%s-applied-config", rgwName)
+}
+
+// getAppliedGatewayConfig returns the names of the options rook set on the rgw
+func (c *clusterConfig) getAppliedGatewayConfig(rgwName string) ([]string, error) {
+	k := k8sutil.NewConfigMapKVStore(c.store.Namespace, c.context.Clientset, c.ownerInfo)
+	data, err := k.GetValue(c.clusterInfo.Context, appliedGatewayConfigName(rgwName), appliedGatewayConfigKey)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return []string{}, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get the applied config of %q", rgwName)
+	}
+	applied := []string{}
+	if err := json.Unmarshal([]byte(data), &applied); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the applied config of %q", rgwName)
+	}
+	return applied, nil
+}
+
+// saveAppliedGatewayConfig records the names of the options rook set on the rgw. Only the names are recorded since
+// some values are secret, e.g. the sts key.
+func (c *clusterConfig) saveAppliedGatewayConfig(rgwName string, applied []string) error {
+	data, err := json.Marshal(applied)
+	if err != nil {
+		return errors.Wrapf(err, "failed to serialize the applied config of %q", rgwName)
+	}
+	k := k8sutil.NewConfigMapKVStore(c.store.Namespace, c.context.Clientset, c.ownerInfo)
+	if err := k.SetValue(c.clusterInfo.Context, appliedGatewayConfigName(rgwName), appliedGatewayConfigKey, string(data)); err != nil {
+		return errors.Wrapf(err, "failed to save the applied config of %q", rgwName)
+	}
+	return nil
+}
+
+// setGatewayConfigMonConfigStore sets the config options of the settings of the store on the rgw in the mon database,
+// and removes the options rook set before that are not desired anymore. The options set by other means, e.g. with
+// the CLI or the dashboard, are kept.
+func (c *clusterConfig) setGatewayConfigMonConfigStore(rgwConfig *rgwConfig) error {
+	monStore := cephconfig.GetMonStore(c.context, c.clusterInfo)
+	who := generateCephXUser(rgwConfig.ResourceName)
+
+	desired, err := c.gatewayConfigOptions()
+	if err != nil {
+		return err
+	}
+	applied, err := c.getAppliedGatewayConfig(rgwConfig.ResourceName)
+	if err != nil {
+		return err
+	}
+
+	desiredNames := []string{}
+	for option, val := range desired {
+		if _, err := monStore.SetIfChanged(who, option, val); err != nil {
+			return errors.Wrapf(err, "failed to set %q to %q on %q", option, val, who)
+		}
+		desiredNames = append(desiredNames, option)
+	}
+	sort.Strings(desiredNames)
+
+	defaultFlags := defaultFlagsMonConfigStore(rgwConfig)
+	for _, option := range applied {
+		if _, ok := desired[option]; ok {
+			continue
+		}
+		if _, ok := defaultFlags[option]; ok {
+			continue
+		}
+		logger.Infof("removing config option %q of %q that is no longer in the object store spec", option, who)
+		if err := monStore.Delete(who, option); err != nil {
+			return errors.Wrapf(err, "failed to remove %q on %q", option, who)
+		}
+	}
+
+	if reflect.DeepEqual(applied, desiredNames) {
+		return nil
+	}
+	return c.saveAppliedGatewayConfig(rgwConfig.ResourceName, desiredNames)
+}
+
 func (c *clusterConfig) deleteFlagsMonConfigStore(rgwName string) error {
 	monStore := cephconfig.GetMonStore(c.context, c.clusterInfo)
 	who := generateCephXUser(rgwName)
@@ -17,14 +17,21 @@ limitations under the License.
 package object
 
 import (
+	"context"
+	"strings"
 	"testing"
+	"time"
 
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	rookclient "github.com/rook/rook/pkg/client/clientset/versioned/fake"
 	"github.com/rook/rook/pkg/clusterd"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
 	cephver "github.com/rook/rook/pkg/operator/ceph/version"
 	"github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func newConfig(t *testing.T) *clusterConfig {
@@ -44,6 +51,7 @@ func newConfig(t *testing.T) *clusterConfig {
 		clusterInfo: clusterInfo,
 		clusterSpec: clusterSpec,
 		context:     &clusterd.Context{Clientset: test.New(t, 3)},
+		ownerInfo:   cephclient.NewMinimumOwnerInfoWithOwnerRef(),
 	}
 }
 
@@ -91,7 +99,269 @@ func TestPortString(t *testing.T) {
 	assert.Equal(t, "port=8080", result)
 }
 
+func TestFrontendString(t *testing.T) {
+	cfg := newConfig(t)
+	cfg.store.Spec.Gateway.Port = 80
+	assert.Equal(t, "beast port=8080", cfg.frontendString())
+
+	// the frontend is tuned
+	tcpNoDelay := false
+	cfg.store.Spec.Gateway.Frontend = &cephv1.GatewayFrontendSpec{
+		RequestTimeout:       &metav1.Duration{Duration: 5 * time.Minute},
+		MaxHeaderSize:        65536,
+		MaxConnectionBacklog: 1024,
+		TCPNoDelay:           &tcpNoDelay,
+	}
+	assert.Equal(t, "beast port=8080 request_timeout_ms=300000 max_header_size=65536 max_connection_backlog=1024 tcp_nodelay=0", cfg.frontendString())
+
+	// the timeout can be disabled
+	cfg.store.Spec.Gateway.Port = 0
+	cfg.store.Spec.Gateway.Frontend = &cephv1.GatewayFrontendSpec{RequestTimeout: &metav1.Duration{}}
+	assert.Equal(t, "beast request_timeout_ms=0", cfg.frontendString())
+}
+
 func TestGenerateCephXUser(t *testing.T) {
 	fakeUser := generateCephXUser("rook-ceph-rgw-fake-store-fake-user")
 	assert.Equal(t, "client.rgw.fake.store.fake.user", fakeUser)
 }
+
+func TestSetGatewayConfigMonConfigStore(t *testing.T) {
+	commands := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			commands = append(commands, strings.Join(args, " "))
+			if args[0] == "config" && args[1] == "get" && len(args) > 3 && args[3] == "rgw_thread_pool_size" {
+				return "512", nil
+			}
+			return "", nil
+		},
+	}
+	c := newConfig(t)
+	c.context.Executor = executor
+	c.clusterInfo = cephclient.AdminTestClusterInfo("rook-ceph")
+	c.store.Spec.Gateway.Config = map[string]string{"rgw-thread-pool-size": "512", "rgw_max_chunk_size": "4194304"}
+
+	err := c.setGatewayConfigMonConfigStore(&rgwConfig{ResourceName: "rook-ceph-rgw-my-store-a"})
+	assert.NoError(t, err)
+	all := strings.Join(commands, ";")
+	// the unchanged option is not set again
+	assert.NotContains(t, all, "config set client.rgw.my.store.a rgw_thread_pool_size")
+	assert.Contains(t, all, "config set client.rgw.my.store.a rgw_max_chunk_size 4194304")
+	assert.NotContains(t, all, "config rm")
+	applied, err := c.getAppliedGatewayConfig("rook-ceph-rgw-my-store-a")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"rgw_max_chunk_size", "rgw_thread_pool_size"}, applied)
+
+	// the option removed from the spec is removed, the options set by other means are not touched
+	commands = []string{}
+	c.store.Spec.Gateway.Config = map[string]string{"rgw-thread-pool-size": "512"}
+	assert.NoError(t, c.setGatewayConfigMonConfigStore(&rgwConfig{ResourceName: "rook-ceph-rgw-my-store-a"}))
+	all = strings.Join(commands, ";")
+	assert.Contains(t, all, "config rm client.rgw.my.store.a rgw_max_chunk_size")
+	assert.NotContains(t, all, "config rm client.rgw.my.store.a rgw_thread_pool_size")
+	applied, err = c.getAppliedGatewayConfig("rook-ceph-rgw-my-store-a")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"rgw_thread_pool_size"}, applied)
+
+	// the values of the options are not recorded
+	c.store.Spec.Gateway.Config = map[string]string{"rgw_sts_key": "secretvalue"}
+	assert.NoError(t, c.setGatewayConfigMonConfigStore(&rgwConfig{ResourceName: "rook-ceph-rgw-my-store-a"}))
+	cm, err := c.context.Clientset.CoreV1().ConfigMaps("").Get(context.TODO(), "rook-ceph-rgw-my-store-a-applied-config", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.NotContains(t, cm.Data["config"], "secretvalue")
+}
+
+func TestSetGatewayConfigDNSName(t *testing.T) {
+	commands := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			commands = append(commands, strings.Join(args, " "))
+			if args[0] == "config" && args[1] == "get" && args[3] == "rgw_dns_name" {
+				return "old.example.com", nil
+			}
+			if args[0] == "config" && args[1] == "get" {
+				return `{"rgw_dns_name":{"value":"old.example.com","section":"client.rgw.my.store.a","mask":{},"can_update_at_runtime":false}}`, nil
+			}
+			return "", nil
+		},
+	}
+	c := newConfig(t)
+	c.context.Executor = executor
+	c.clusterInfo = cephclient.AdminTestClusterInfo("rook-ceph")
+	c.store.Spec.Hosting = &cephv1.ObjectStoreHostingSpec{DNSName: "s3.example.com"}
+
+	assert.NoError(t, c.setGatewayConfigMonConfigStore(&rgwConfig{ResourceName: "rook-ceph-rgw-my-store-a"}))
+	all := strings.Join(commands, ";")
+	assert.Contains(t, all, "config set client.rgw.my.store.a rgw_dns_name s3.example.com")
+	assert.NotContains(t, all, "config rm client.rgw.my.store.a rgw_dns_name")
+
+	// the dns name is removed with the hosting
+	commands = []string{}
+	c.store.Spec.Hosting = nil
+	assert.NoError(t, c.setGatewayConfigMonConfigStore(&rgwConfig{ResourceName: "rook-ceph-rgw-my-store-a"}))
+	assert.Contains(t, strings.Join(commands, ";"), "config rm client.rgw.my.store.a rgw_dns_name")
+}
+
+func TestSetGatewayConfigSTS(t *testing.T) {
+	commands := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			commands = append(commands, strings.Join(args, " "))
+			if args[0] == "config" && args[1] == "get" && strings.HasPrefix(args[3], "rgw_") {
+				return "", nil
+			}
+			if args[0] == "config" && args[1] == "get" {
+				return `{"rgw_s3_auth_use_sts":{"value":"true","section":"client.rgw.my.store.a","mask":{},"can_update_at_runtime":false},
+					"rgw_sts_key":{"value":"abcdefghijklmnop","section":"client.rgw.my.store.a","mask":{},"can_update_at_runtime":false}}`, nil
+			}
+			return "", nil
+		},
+	}
+	c := newConfig(t)
+	c.context.Executor = executor
+	c.clusterInfo = cephclient.AdminTestClusterInfo("rook-ceph")
+	c.ownerInfo = cephclient.NewMinimumOwnerInfoWithOwnerRef()
+	c.store.Name = "my-store"
+	c.store.Spec.STS = &cephv1.ObjectStoreSTSSpec{Enabled: true}
+
+	assert.NoError(t, c.setGatewayConfigMonConfigStore(&rgwConfig{ResourceName: "rook-ceph-rgw-my-store-a"}))
+	all := strings.Join(commands, ";")
+	assert.Contains(t, all, "config set client.rgw.my.store.a rgw_s3_auth_use_sts true")
+	assert.Contains(t, all, "config set client.rgw.my.store.a rgw_sts_key ")
+	assert.NotContains(t, all, "config rm")
+
+	// the sts options are removed when the sts is disabled
+	commands = []string{}
+	c.store.Spec.STS.Enabled = false
+	assert.NoError(t, c.setGatewayConfigMonConfigStore(&rgwConfig{ResourceName: "rook-ceph-rgw-my-store-a"}))
+	all = strings.Join(commands, ";")
+	assert.Contains(t, all, "config rm client.rgw.my.store.a rgw_s3_auth_use_sts")
+	assert.Contains(t, all, "config rm client.rgw.my.store.a rgw_sts_key")
+}
+
+func TestSetGatewayConfigBucketIndex(t *testing.T) {
+	commands := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			commands = append(commands, strings.Join(args, " "))
+			if args[0] == "config" && args[1] == "get" && strings.HasPrefix(args[3], "rgw_") {
+				return "", nil
+			}
+			if args[0] == "config" && args[1] == "get" {
+				return `{"rgw_dynamic_resharding":{"value":"false","section":"client.rgw.my.store.a","mask":{},"can_update_at_runtime":true},
+					"rgw_override_bucket_index_max_shards":{"value":"11","section":"client.rgw.my.store.a","mask":{},"can_update_at_runtime":true}}`, nil
+			}
+			return "", nil
+		},
+	}
+	c := newConfig(t)
+	c.context.Executor = executor
+	c.clusterInfo = cephclient.AdminTestClusterInfo("rook-ceph")
+	disabled := false
+	shards := int32(11)
+	c.store.Spec.BucketIndex = &cephv1.BucketIndexSpec{DynamicResharding: &disabled, OverrideMaxShards: &shards}
+
+	assert.NoError(t, c.setGatewayConfigMonConfigStore(&rgwConfig{ResourceName: "rook-ceph-rgw-my-store-a"}))
+	all := strings.Join(commands, ";")
+	assert.Contains(t, all, "config set client.rgw.my.store.a rgw_dynamic_resharding false")
+	assert.Contains(t, all, "config set client.rgw.my.store.a rgw_override_bucket_index_max_shards 11")
+	assert.NotContains(t, all, "config rm")
+
+	// the options are removed when the settings are removed
+	commands = []string{}
+	c.store.Spec.BucketIndex = nil
+	assert.NoError(t, c.setGatewayConfigMonConfigStore(&rgwConfig{ResourceName: "rook-ceph-rgw-my-store-a"}))
+	all = strings.Join(commands, ";")
+	assert.Contains(t, all, "config rm client.rgw.my.store.a rgw_dynamic_resharding")
+	assert.Contains(t, all, "config rm client.rgw.my.store.a rgw_override_bucket_index_max_shards")
+}
+
+func TestGarbageCollectionConfigOptions(t *testing.T) {
+	assert.Empty(t, garbageCollectionConfigOptions(nil))
+
+	maxObjects := int32(64)
+	concurrentIO := int32(20)
+	gc := &cephv1.GarbageCollectionSpec{
+		MaxObjects:      &maxObjects,
+		ProcessorPeriod: &metav1.Duration{Duration: 90 * time.Minute},
+		MaxConcurrentIO: &concurrentIO,
+	}
+	assert.Equal(t, map[string]string{
+		"rgw_gc_max_objs":          "64",
+		"rgw_gc_processor_period":  "5400",
+		"rgw_gc_max_concurrent_io": "20",
+	}, garbageCollectionConfigOptions(gc))
+
+	gc.MaxObjects = nil
+	gc.MaxConcurrentIO = nil
+	gc.ProcessorPeriod.Duration = 1500 * time.Millisecond
+	assert.Equal(t, map[string]string{"rgw_gc_processor_period": "2"}, garbageCollectionConfigOptions(gc))
+}
+
+func TestZoneGroupDefaultsConfigOptions(t *testing.T) {
+	assert.Empty(t, zoneGroupDefaultsConfigOptions(nil))
+
+	maxBuckets := 10
+	maxObjects := int64(1000)
+	maxSize := resource.MustParse("1Gi")
+	defaults := &cephv1.ZoneGroupDefaultsSpec{
+		MaxBuckets:  &maxBuckets,
+		UserQuota:   &cephv1.ObjectQuotaDefaultsSpec{MaxSize: &maxSize},
+		BucketQuota: &cephv1.ObjectQuotaDefaultsSpec{MaxObjects: &maxObjects},
+	}
+	assert.Equal(t, map[string]string{
+		"rgw_user_max_buckets":                 "10",
+		"rgw_user_default_quota_max_size":      "1073741824",
+		"rgw_bucket_default_quota_max_objects": "1000",
+	}, zoneGroupDefaultsConfigOptions(defaults))
+}
+
+func TestSetGatewayConfigZoneGroupDefaults(t *testing.T) {
+	commands := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			commands = append(commands, strings.Join(args, " "))
+			if args[0] == "config" && args[1] == "get" && len(args) > 3 {
+				return "", nil
+			}
+			if args[0] == "config" && args[1] == "get" {
+				return `{}`, nil
+			}
+			return "", nil
+		},
+	}
+	maxBuckets := 10
+	maxObjects := int64(1000)
+	zone := &cephv1.CephObjectZone{
+		ObjectMeta: metav1.ObjectMeta{Name: "zone-a", Namespace: "rook-ceph"},
+		Spec:       cephv1.ObjectZoneSpec{ZoneGroup: "zonegroup-a"},
+	}
+	zoneGroup := &cephv1.CephObjectZoneGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "zonegroup-a", Namespace: "rook-ceph"},
+		Spec: cephv1.ObjectZoneGroupSpec{
+			Realm: "realm-a",
+			Defaults: &cephv1.ZoneGroupDefaultsSpec{
+				MaxBuckets:  &maxBuckets,
+				BucketQuota: &cephv1.ObjectQuotaDefaultsSpec{MaxObjects: &maxObjects},
+			},
+		},
+	}
+	c := newConfig(t)
+	c.context.Executor = executor
+	c.context.RookClientset = rookclient.NewSimpleClientset(zone, zoneGroup)
+	c.clusterInfo = cephclient.AdminTestClusterInfo("rook-ceph")
+	c.store.Namespace = "rook-ceph"
+	c.store.Spec.Zone.Name = "zone-a"
+	// the gateway config of the store overrides the defaults of the zone group
+	c.store.Spec.Gateway.Config = map[string]string{"rgw user max buckets": "20"}
+
+	assert.NoError(t, c.setGatewayConfigMonConfigStore(&rgwConfig{ResourceName: "rook-ceph-rgw-my-store-a"}))
+	all := strings.Join(commands, ";")
+	assert.Contains(t, all, "config set client.rgw.my.store.a rgw_bucket_default_quota_max_objects 1000")
+	assert.Contains(t, all, "config set client.rgw.my.store.a rgw_user_max_buckets 20")
+	assert.NotContains(t, all, "rgw_user_max_buckets 10")
+
+	// the zone of the store must exist
+	c.store.Spec.Zone.Name = "zone-b"
+	assert.Error(t, c.setGatewayConfigMonConfigStore(&rgwConfig{ResourceName: "rook-ceph-rgw-my-store-a"}))
+}
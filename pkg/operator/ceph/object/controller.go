@@ -33,8 +33,10 @@ import (
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
 	"github.com/rook/rook/pkg/operator/ceph/config"
 	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/pool"
 	"github.com/rook/rook/pkg/operator/ceph/reporting"
 	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/util/dependents"
 	"github.com/rook/rook/pkg/util/exec"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -47,6 +49,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
@@ -64,6 +67,7 @@ var objectsToWatch = []client.Object{
 	&corev1.Secret{TypeMeta: metav1.TypeMeta{Kind: "Secret", APIVersion: corev1.SchemeGroupVersion.String()}},
 	&corev1.Service{TypeMeta: metav1.TypeMeta{Kind: "Service", APIVersion: corev1.SchemeGroupVersion.String()}},
 	&appsv1.Deployment{TypeMeta: metav1.TypeMeta{Kind: "Deployment", APIVersion: appsv1.SchemeGroupVersion.String()}},
+	&appsv1.DaemonSet{TypeMeta: metav1.TypeMeta{Kind: "DaemonSet", APIVersion: appsv1.SchemeGroupVersion.String()}},
 }
 
 var cephObjectStoreKind = reflect.TypeOf(cephv1.CephObjectStore{}).Name()
@@ -90,29 +94,34 @@ type ReconcileCephObjectStore struct {
 	recorder         record.EventRecorder
 	opManagerContext context.Context
 	opConfig         opcontroller.OperatorConfig
+	// the routines reporting the usage summary of the stores
+	objectStoreUsages map[string]*objectStoreUsage
+	// the backoff of the reconciles that failed on transient ceph errors
+	backoff opcontroller.RequeueBackoff
 }
 
 // Add creates a new cephObjectStore Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
-	return add(mgr, newReconciler(mgr, context, opManagerContext, opConfig))
+	return add(opManagerContext, mgr, newReconciler(mgr, context, opManagerContext, opConfig))
 }
 
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) reconcile.Reconciler {
 	context.Client = mgr.GetClient()
 	return &ReconcileCephObjectStore{
-		client:           mgr.GetClient(),
-		scheme:           mgr.GetScheme(),
-		context:          context,
-		bktclient:        bktclient.NewForConfigOrDie(context.KubeConfig),
-		recorder:         mgr.GetEventRecorderFor("rook-" + controllerName),
-		opManagerContext: opManagerContext,
-		opConfig:         opConfig,
+		client:            mgr.GetClient(),
+		scheme:            mgr.GetScheme(),
+		context:           context,
+		bktclient:         bktclient.NewForConfigOrDie(context.KubeConfig),
+		recorder:          mgr.GetEventRecorderFor("rook-" + controllerName),
+		opManagerContext:  opManagerContext,
+		opConfig:          opConfig,
+		objectStoreUsages: make(map[string]*objectStoreUsage),
 	}
 }
 
-func add(mgr manager.Manager, r reconcile.Reconciler) error {
+func add(opManagerContext context.Context, mgr manager.Manager, r reconcile.Reconciler) error {
 	// Create a new controller
 	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
 	if err != nil {
@@ -137,9 +146,68 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		}
 	}
 
+	// Watch for changes on the zone groups, since the gateways of the stores inherit the defaults of their zone group
+	err = c.Watch(
+		&source.Kind{Type: &cephv1.CephObjectZoneGroup{}},
+		handler.EnqueueRequestsFromMapFunc(handler.MapFunc(func(obj client.Object) []reconcile.Request {
+			return storesOfZoneGroup(opManagerContext, mgr.GetClient(), obj)
+		})),
+		predicate.GenerationChangedPredicate{},
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to watch for changes on the zone groups")
+	}
+
+	// Watch for changes on the default rgw settings of the CephCluster, which the stores of the cluster inherit
+	handlerFunc, err := opcontroller.ObjectToCRInNamespaceMapper(opManagerContext, mgr.GetClient(), &cephv1.CephObjectStoreList{}, mgr.GetScheme())
+	if err != nil {
+		return err
+	}
+	err = c.Watch(&source.Kind{Type: &cephv1.CephCluster{}}, handler.EnqueueRequestsFromMapFunc(handlerFunc), opcontroller.PredicateClusterDefaultsChanged(cephv1.KeyRgw))
+	if err != nil {
+		return errors.Wrap(err, "failed to watch for changes on the cluster")
+	}
+
 	return nil
 }
 
+// storesOfZoneGroup returns the requests of the object stores of the zones in the zone group
+func storesOfZoneGroup(ctx context.Context, c client.Client, obj client.Object) []reconcile.Request {
+	zoneGroup, ok := obj.(*cephv1.CephObjectZoneGroup)
+	if !ok {
+		return []reconcile.Request{}
+	}
+
+	zones := &cephv1.CephObjectZoneList{}
+	if err := c.List(ctx, zones, client.InNamespace(zoneGroup.Namespace)); err != nil {
+		logger.Errorf("failed to list the zones of zone group %q. %v", zoneGroup.Name, err)
+		return []reconcile.Request{}
+	}
+	zonesInGroup := map[string]bool{}
+	for _, zone := range zones.Items {
+		if zone.Spec.ZoneGroup == zoneGroup.Name {
+			zonesInGroup[zone.Name] = true
+		}
+	}
+	if len(zonesInGroup) == 0 {
+		return []reconcile.Request{}
+	}
+
+	stores := &cephv1.CephObjectStoreList{}
+	if err := c.List(ctx, stores, client.InNamespace(zoneGroup.Namespace)); err != nil {
+		logger.Errorf("failed to list the object stores of zone group %q. %v", zoneGroup.Name, err)
+		return []reconcile.Request{}
+	}
+	requests := []reconcile.Request{}
+	for _, store := range stores.Items {
+		if zonesInGroup[store.Spec.Zone.Name] {
+			logger.Debugf("zone group %q of object store %q changed", zoneGroup.Name, store.Name)
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: store.Namespace, Name: store.Name}})
+		}
+	}
+	return requests
+}
+
 // Reconcile reads that state of the cluster for a cephObjectStore object and makes changes based on the state read
 // and what is in the cephObjectStore.Spec
 // The Controller will requeue the Request to be processed again if the returned error is non-nil or
@@ -148,6 +216,13 @@ func (r *ReconcileCephObjectStore) Reconcile(context context.Context, request re
 	// workaround because the rook logging mechanism is not compatible with the controller-runtime logging interface
 	reconcileResponse, objectStore, err := r.reconcile(request)
 
+	// Requeue with a backoff on transient ceph errors instead of retrying right away while the cluster is unhealthy
+	reconcileResponse = r.backoff.Result(request.NamespacedName, reconcileResponse, err)
+	if opcontroller.IsTransientError(err) {
+		// the cached cluster info or ceph version may be outdated
+		r.context.Cache.Invalidate(request.Namespace)
+	}
+
 	return reporting.ReportReconcileResult(logger, r.recorder, request,
 		&objectStore, reconcileResponse, err)
 }
@@ -159,6 +234,8 @@ func (r *ReconcileCephObjectStore) reconcile(request reconcile.Request) (reconci
 	if err != nil {
 		if kerrors.IsNotFound(err) {
 			logger.Debug("cephObjectStore resource not found. Ignoring since object must be deleted.")
+			// make sure the usage summary routine does not leak if the finalizer was removed by the user
+			r.cancelUsageSummary(request.NamespacedName)
 			return reconcile.Result{}, *cephObjectStore, nil
 		}
 		// Error reading the object - requeue the request.
@@ -193,6 +270,21 @@ func (r *ReconcileCephObjectStore) reconcile(request reconcile.Request) (reconci
 		// If not, we should wait for it to be ready
 		// This handles the case where the operator is not ready to accept Ceph command but the cluster exists
 		if !cephObjectStore.GetDeletionTimestamp().IsZero() && !cephClusterExists {
+			// The users of the store are deleted before the store when the namespace is terminating
+			if opcontroller.IsNamespaceTerminating(r.opManagerContext, r.client, cephObjectStore.Namespace) {
+				deps := dependents.NewDependentList()
+				if err := getUserDependents(deps, r.context, r.opManagerContext, cephObjectStore); err != nil {
+					return reconcile.Result{}, *cephObjectStore, err
+				}
+				if !deps.Empty() {
+					err := reporting.ReportDeletionBlockedDueToDependents(r.opManagerContext, logger, r.client, cephObjectStore, deps)
+					return opcontroller.WaitForRequeueIfFinalizerBlocked, *cephObjectStore, err
+				}
+			}
+
+			r.cancelUsageSummary(request.NamespacedName)
+			pool.DeletePoolCapacityMetrics(cephObjectStore.Namespace, allObjectPools(cephObjectStore.Name)...)
+
 			// Remove finalizer
 			err := opcontroller.RemoveFinalizer(r.opManagerContext, r.client, cephObjectStore)
 			if err != nil {
@@ -207,8 +299,8 @@ func (r *ReconcileCephObjectStore) reconcile(request reconcile.Request) (reconci
 	}
 	r.clusterSpec = &cephCluster.Spec
 
-	// Populate clusterInfo during each reconcile
-	r.clusterInfo, _, _, err = opcontroller.LoadClusterInfo(r.context, r.opManagerContext, request.NamespacedName.Namespace, r.clusterSpec)
+	// Populate clusterInfo during each reconcile, from the cache shared with the other controllers
+	r.clusterInfo, err = opcontroller.LoadCachedClusterInfo(r.context, r.opManagerContext, request.NamespacedName.Namespace, r.clusterSpec)
 	if err != nil {
 		return reconcile.Result{}, *cephObjectStore, errors.Wrap(err, "failed to populate cluster info")
 	}
@@ -216,9 +308,10 @@ func (r *ReconcileCephObjectStore) reconcile(request reconcile.Request) (reconci
 	// DELETE: the CR was deleted
 	if !cephObjectStore.GetDeletionTimestamp().IsZero() {
 		updateStatus(r.opManagerContext, k8sutil.ObservedGenerationNotAvailable, r.client, request.NamespacedName, cephv1.ConditionDeleting, buildStatusInfo(cephObjectStore))
+		r.cancelUsageSummary(request.NamespacedName)
 
 		// Detect running Ceph version
-		runningCephVersion, err := cephclient.LeastUptodateDaemonVersion(r.context, r.clusterInfo, config.MonType)
+		runningCephVersion, err := opcontroller.CachedLeastUptodateDaemonVersion(r.context, r.clusterInfo, config.MonType)
 		if err != nil {
 			return reconcile.Result{}, *cephObjectStore, errors.Wrapf(err, "failed to retrieve current ceph %q version", config.MonType)
 		}
@@ -261,6 +354,11 @@ func (r *ReconcileCephObjectStore) reconcile(request reconcile.Request) (reconci
 			clusterInfo: r.clusterInfo,
 		}
 		cfg.deleteStore()
+		pool.DeletePoolCapacityMetrics(cephObjectStore.Namespace, allObjectPools(cephObjectStore.Name)...)
+
+		if err := r.updateConnectionInfo(cephCluster, cephObjectStore, ""); err != nil {
+			return reconcile.Result{}, *cephObjectStore, err
+		}
 
 		// Remove finalizer
 		err = opcontroller.RemoveFinalizer(r.opManagerContext, r.client, cephObjectStore)
@@ -274,7 +372,7 @@ func (r *ReconcileCephObjectStore) reconcile(request reconcile.Request) (reconci
 
 	if cephObjectStore.Spec.IsExternal() {
 		// Check the ceph version of the running monitors
-		desiredCephVersion, err := cephclient.LeastUptodateDaemonVersion(r.context, r.clusterInfo, config.MonType)
+		desiredCephVersion, err := opcontroller.CachedLeastUptodateDaemonVersion(r.context, r.clusterInfo, config.MonType)
 		if err != nil {
 			return reconcile.Result{}, *cephObjectStore, errors.Wrapf(err, "failed to retrieve current ceph %q version", config.MonType)
 		}
@@ -314,6 +412,7 @@ func (r *ReconcileCephObjectStore) reconcile(request reconcile.Request) (reconci
 
 	// validate the store settings
 	if err := r.validateStore(cephObjectStore); err != nil {
+		reporting.ReportValidationFailed(r.recorder, cephObjectStore, err)
 		return reconcile.Result{}, *cephObjectStore, errors.Wrapf(err, "invalid object store %q arguments", cephObjectStore.Name)
 	}
 
@@ -340,6 +439,13 @@ func (r *ReconcileCephObjectStore) reconcile(request reconcile.Request) (reconci
 		return reconcile.Result{}, *cephObjectStore, errors.Wrap(err, "updated object store but failed to remove deprecated health check bucket")
 	}
 
+	// Report the usage summary of the users and buckets if enabled
+	r.reconcileUsageSummary(cephObjectStore, opsCtx)
+
+	if err := r.updateConnectionInfo(cephCluster, cephObjectStore, buildStatusInfo(cephObjectStore)["endpoint"]); err != nil {
+		return reconcile.Result{}, *cephObjectStore, err
+	}
+
 	// update ObservedGeneration in status at the end of reconcile
 	// Set Progressing status, we are done reconciling, the health check go routine will update the status
 	updateStatus(r.opManagerContext, observedGeneration, r.client, request.NamespacedName, cephv1.ConditionReady, buildStatusInfo(cephObjectStore))
@@ -356,10 +462,12 @@ func (r *ReconcileCephObjectStore) reconcileCreateObjectStore(cephObjectStore *c
 		clusterInfo: r.clusterInfo,
 		store:       cephObjectStore,
 		rookVersion: r.clusterSpec.CephVersion.Image,
+		rookImage:   r.opConfig.Image,
 		clusterSpec: r.clusterSpec,
 		DataPathMap: config.NewStatelessDaemonDataPathMap(config.RgwType, cephObjectStore.Name, cephObjectStore.Namespace, r.clusterSpec.DataDirHostPath),
 		client:      r.client,
 		ownerInfo:   ownerInfo,
+		recorder:    r.recorder,
 	}
 	objContext, err := NewMultisiteContext(r.context, r.clusterInfo, cephObjectStore)
 	if err != nil {
@@ -423,6 +531,14 @@ func (r *ReconcileCephObjectStore) reconcileCreateObjectStore(cephObjectStore *c
 			return reconcileResponse, err
 		}
 
+		// Stores in different realms must not share zone groups, zones or pools, otherwise they corrupt the
+		// periods of each other
+		topology, err := r.checkRealmIsolation(cephObjectStore, realmName, zoneGroupName, zoneName)
+		if err != nil {
+			return r.setFailedStatus(k8sutil.ObservedGenerationNotAvailable, namespacedName, "failed to check the realm isolation of the object store", err)
+		}
+		r.updateRealmTopology(namespacedName, topology)
+
 		// Reconcile Ceph Zone if Multisite
 		if cephObjectStore.Spec.IsMultisite() {
 			reconcileResponse, err := r.reconcileCephZone(cephObjectStore, zoneGroupName, realmName)
@@ -436,6 +552,13 @@ func (r *ReconcileCephObjectStore) reconcileCreateObjectStore(cephObjectStore *c
 		objContext.Zone = zoneName
 		logger.Debugf("realm for object-store is %q, zone group for object-store is %q, zone for object-store is %q", objContext.Realm, objContext.ZoneGroup, objContext.Zone)
 
+		// Clean up the resources left by object stores that were deleted or recreated, otherwise their rgw
+		// daemons keep serving a stale configuration
+		err = opcontroller.ReconcileOrphanedChildren(r.opManagerContext, r.context.Clientset, r.recorder, cephObjectStore, "CephObjectStore", fmt.Sprintf("%s=%s", k8sutil.AppAttr, AppName), r.objectStoreExists)
+		if err != nil {
+			logger.Warningf("failed to reconcile resources orphaned by previous object stores. %v", err)
+		}
+
 		// RECONCILE SERVICE
 		logger.Debug("reconciling object store service")
 		err = cfg.reconcileService(cephObjectStore)
@@ -448,33 +571,101 @@ func (r *ReconcileCephObjectStore) reconcileCreateObjectStore(cephObjectStore *c
 		}
 
 		// Reconcile Pool Creation
-		if !cephObjectStore.Spec.IsMultisite() {
+		if cephObjectStore.Spec.UsesSharedPools() {
+			logger.Info("checking the shared pools of the object store")
+			err = checkSharedPools(objContext, cephObjectStore.Spec.SharedPools)
+			if err != nil {
+				return r.setFailedStatus(k8sutil.ObservedGenerationNotAvailable, namespacedName, "failed to find shared pools", err)
+			}
+		} else if cephObjectStore.Spec.IsAdopted() {
+			logger.Info("checking the adopted zone of the object store")
+			err = checkAdoptedZone(objContext)
+			if err != nil {
+				return r.setFailedStatus(k8sutil.ObservedGenerationNotAvailable, namespacedName, "failed to adopt zone", err)
+			}
+		} else if !cephObjectStore.Spec.IsMultisite() {
 			logger.Info("reconciling object store pools")
+			createdPools, err := missingPools(objContext)
+			if err != nil {
+				return r.setFailedStatus(k8sutil.ObservedGenerationNotAvailable, namespacedName, "failed to check object pools", err)
+			}
 			err = CreatePools(objContext, r.clusterSpec, cephObjectStore.Spec.MetadataPool, cephObjectStore.Spec.DataPool)
 			if err != nil {
 				return r.setFailedStatus(k8sutil.ObservedGenerationNotAvailable, namespacedName, "failed to create object pools", err)
 			}
+			if len(createdPools) > 0 {
+				r.recorder.Eventf(cephObjectStore, corev1.EventTypeNormal, string(cephv1.PoolsCreatedReason), "created the pools %v of object store %q", createdPools, namespacedName.String())
+			}
 		}
 
 		// Reconcile Multisite Creation
 		logger.Infof("setting multisite settings for object store %q", cephObjectStore.Name)
-		err = setMultisite(objContext, cephObjectStore, zone)
+		realmCreated, err := setMultisite(objContext, cephObjectStore, zone)
 		if err != nil && kerrors.IsNotFound(err) {
 			return reconcile.Result{}, err
 		} else if err != nil {
 			return r.setFailedStatus(k8sutil.ObservedGenerationNotAvailable, namespacedName, "failed to configure multisite for object store", err)
 		}
+		if realmCreated {
+			r.recorder.Eventf(cephObjectStore, corev1.EventTypeNormal, string(cephv1.RealmCreatedReason), "created realm %q, zone group %q and zone %q of object store %q", realmName, zoneGroupName, zoneName, namespacedName.String())
+		}
+
+		// Reconcile the shared pools of the zone
+		if cephObjectStore.Spec.UsesSharedPools() {
+			err = configureSharedPools(objContext, cephObjectStore.Spec.SharedPools)
+			if err != nil {
+				return r.setFailedStatus(k8sutil.ObservedGenerationNotAvailable, namespacedName, "failed to configure shared pools for object store", err)
+			}
+		}
+
+		// Reconcile the dns names of the zonegroup, which is shared with other stores in a zone
+		if !cephObjectStore.Spec.IsMultisite() {
+			err = configureZoneGroupHostnames(objContext, cephObjectStore.Spec.Hosting)
+			if err != nil {
+				return r.setFailedStatus(k8sutil.ObservedGenerationNotAvailable, namespacedName, "failed to configure dns names for object store", err)
+			}
+		}
+
+		// Reconcile the placement targets
+		err = configurePlacementTargets(objContext, r.clusterSpec, cephObjectStore)
+		if err != nil {
+			return r.setFailedStatus(k8sutil.ObservedGenerationNotAvailable, namespacedName, "failed to configure placement targets for object store", err)
+		}
+
+		// Reconcile the cloud tiers
+		err = configureCloudTiers(objContext, cephObjectStore)
+		if err != nil {
+			return r.setFailedStatus(k8sutil.ObservedGenerationNotAvailable, namespacedName, "failed to configure cloud tiers for object store", err)
+		}
 
 		// Create or Update Store
 		err = cfg.createOrUpdateStore(realmName, zoneGroupName, zoneName)
 		if err != nil {
 			return reconcile.Result{}, errors.Wrapf(err, "failed to create object store %q", cephObjectStore.Name)
 		}
+
+		// Reconcile the service monitor of the rgw metrics
+		err = cfg.reconcileServiceMonitor()
+		if err != nil {
+			return r.setFailedStatus(k8sutil.ObservedGenerationNotAvailable, namespacedName, "failed to enable rgw service monitor", err)
+		}
 	}
 
 	return reconcile.Result{}, nil
 }
 
+// objectStoreExists returns whether the object store with the given name exists in the namespace of the cluster
+func (r *ReconcileCephObjectStore) objectStoreExists(name string) (bool, error) {
+	err := r.client.Get(r.opManagerContext, types.NamespacedName{Name: name, Namespace: r.clusterInfo.Namespace}, &cephv1.CephObjectStore{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
 func (r *ReconcileCephObjectStore) reconcileCephZone(store *cephv1.CephObjectStore, zoneGroupName string, realmName string) (reconcile.Result, error) {
 	realmArg := fmt.Sprintf("--rgw-realm=%s", realmName)
 	zoneGroupArg := fmt.Sprintf("--rgw-zonegroup=%s", zoneGroupName)
@@ -531,6 +722,11 @@ func (r *ReconcileCephObjectStore) reconcileMultisiteCRs(cephObjectStore *cephv1
 		return realm.Name, zonegroup.Name, zone.Name, zone, reconcile.Result{}, nil
 	}
 
+	if cephObjectStore.Spec.IsAdopted() {
+		adopt := cephObjectStore.Spec.Adopt
+		return adopt.Realm, adopt.ZoneGroup, adopt.Zone, nil, reconcile.Result{}, nil
+	}
+
 	return cephObjectStore.Name, cephObjectStore.Name, cephObjectStore.Name, nil, reconcile.Result{}, nil
 }
 
@@ -571,3 +767,12 @@ var removeDeprecatedHealthCheckBucket = func(ctx context.Context, opsCtx *AdminO
 
 	return errors.Wrapf(remErr, "failed to delete deprecated health checker bucket %q", healthCheckBucket)
 }
+
+// updateConnectionInfo publishes the endpoint of the object store in the connection info of the cluster, the store is
+// removed from the connection info if the endpoint is empty
+func (r *ReconcileCephObjectStore) updateConnectionInfo(cephCluster cephv1.CephCluster, store *cephv1.CephObjectStore, endpoint string) error {
+	err := opcontroller.UpdateConnectionInfo(r.opManagerContext, r.context.Clientset, store.Namespace, k8sutil.NewOwnerInfo(&cephCluster, r.scheme), func(data map[string]string) error {
+		return opcontroller.SetConnectionInfoRGWEndpoint(data, store.Name, endpoint)
+	})
+	return errors.Wrapf(err, "failed to update the endpoint of object store %q in the connection info", store.Name)
+}
@@ -442,6 +442,9 @@ func TestCephObjectStoreController(t *testing.T) {
 				Name:      namespace,
 				Namespace: namespace,
 			},
+			Spec: cephv1.ClusterSpec{
+				AllowUnsafePools: true,
+			},
 			Status: cephv1.ClusterStatus{
 				Phase: k8sutil.ReadyStatus,
 				CephStatus: &cephv1.CephStatus{
@@ -1037,3 +1040,42 @@ func TestDiffVersions(t *testing.T) {
 	// Compares the actual value of the pointer by dereferencing the pointer
 	assert.True(t, reflect.DeepEqual(runningCephVersion, *desiredCephVersion))
 }
+
+func TestStoresOfZoneGroup(t *testing.T) {
+	ctx := context.TODO()
+	zoneGroup := &cephv1.CephObjectZoneGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "zonegroup-a", Namespace: "ns"},
+	}
+	objects := []runtime.Object{
+		&cephv1.CephObjectZone{
+			ObjectMeta: metav1.ObjectMeta{Name: "zone-a", Namespace: "ns"},
+			Spec:       cephv1.ObjectZoneSpec{ZoneGroup: "zonegroup-a"},
+		},
+		&cephv1.CephObjectZone{
+			ObjectMeta: metav1.ObjectMeta{Name: "zone-b", Namespace: "ns"},
+			Spec:       cephv1.ObjectZoneSpec{ZoneGroup: "zonegroup-b"},
+		},
+		&cephv1.CephObjectStore{
+			ObjectMeta: metav1.ObjectMeta{Name: "store-a", Namespace: "ns"},
+			Spec:       cephv1.ObjectStoreSpec{Zone: cephv1.ZoneSpec{Name: "zone-a"}},
+		},
+		&cephv1.CephObjectStore{
+			ObjectMeta: metav1.ObjectMeta{Name: "store-b", Namespace: "ns"},
+			Spec:       cephv1.ObjectStoreSpec{Zone: cephv1.ZoneSpec{Name: "zone-b"}},
+		},
+		&cephv1.CephObjectStore{
+			ObjectMeta: metav1.ObjectMeta{Name: "store-c", Namespace: "ns"},
+		},
+	}
+	s := runtime.NewScheme()
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephObjectZone{}, &cephv1.CephObjectZoneList{}, &cephv1.CephObjectStore{}, &cephv1.CephObjectStoreList{})
+	cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(objects...).Build()
+
+	requests := storesOfZoneGroup(ctx, cl, zoneGroup)
+	assert.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "store-a"}}}, requests)
+
+	zoneGroup.Name = "zonegroup-c"
+	assert.Empty(t, storesOfZoneGroup(ctx, cl, zoneGroup))
+
+	assert.Empty(t, storesOfZoneGroup(ctx, cl, &cephv1.CephObjectStore{}))
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cosi manages the Ceph COSI driver, which serves the buckets and bucket accesses of the Kubernetes
+// Container Object Storage Interface from the object stores.
+package cosi
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	appsv1 "k8s.io/api/apps/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName = "ceph-cosi-controller"
+)
+
+var logger = capnslog.NewPackageLogger("github.com/rook/rook", controllerName)
+
+var cephCOSIDriverKind = reflect.TypeOf(cephv1.CephCOSIDriver{}).Name()
+
+// Sets the type meta for the controller main object
+var controllerTypeMeta = metav1.TypeMeta{
+	Kind:       cephCOSIDriverKind,
+	APIVersion: fmt.Sprintf("%s/%s", cephv1.CustomResourceGroup, cephv1.Version),
+}
+
+// ReconcileCephCOSIDriver reconciles the CephCOSIDriver object
+type ReconcileCephCOSIDriver struct {
+	context          *clusterd.Context
+	client           client.Client
+	scheme           *runtime.Scheme
+	opManagerContext context.Context
+	opConfig         opcontroller.OperatorConfig
+	recorder         record.EventRecorder
+}
+
+// Add creates a new CephCOSIDriver Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
+	return add(mgr, newReconciler(mgr, context, opManagerContext, opConfig), opConfig)
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) reconcile.Reconciler {
+	return &ReconcileCephCOSIDriver{
+		client:           mgr.GetClient(),
+		scheme:           mgr.GetScheme(),
+		context:          context,
+		opManagerContext: opManagerContext,
+		opConfig:         opConfig,
+		recorder:         mgr.GetEventRecorderFor("rook-" + controllerName),
+	}
+}
+
+func add(mgr manager.Manager, r reconcile.Reconciler, opConfig opcontroller.OperatorConfig) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+	logger.Info("successfully started")
+
+	// Watch for changes on the CephCOSIDriver CRD object
+	err = c.Watch(&source.Kind{Type: &cephv1.CephCOSIDriver{TypeMeta: controllerTypeMeta}}, &handler.EnqueueRequestForObject{}, predicate.GenerationChangedPredicate{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes on the deployment of the driver
+	err = c.Watch(&source.Kind{Type: &appsv1.Deployment{TypeMeta: metav1.TypeMeta{Kind: "Deployment", APIVersion: appsv1.SchemeGroupVersion.String()}}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &cephv1.CephCOSIDriver{},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch for the object stores created and deleted, which start and stop the driver with the Auto strategy
+	err = c.Watch(&source.Kind{Type: &cephv1.CephObjectStore{TypeMeta: metav1.TypeMeta{Kind: "CephObjectStore", APIVersion: fmt.Sprintf("%s/%s", cephv1.CustomResourceGroup, cephv1.Version)}}},
+		handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []reconcile.Request {
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: CephCOSIDriverName, Namespace: opConfig.OperatorNamespace}}}
+		}),
+		predicate.Funcs{
+			UpdateFunc: func(e event.UpdateEvent) bool { return false },
+		})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Reconcile reads that state of the cluster for the CephCOSIDriver object and makes changes based on the state read
+// and what is in the CephCOSIDriver.Spec
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileCephCOSIDriver) Reconcile(context context.Context, request reconcile.Request) (reconcile.Result, error) {
+	// workaround because the rook logging mechanism is not compatible with the controller-runtime logging interface
+	reconcileResponse, cephCOSIDriver, err := r.reconcile(request)
+	if err != nil {
+		logger.Errorf("failed to reconcile %v", err)
+	}
+
+	return reporting.ReportReconcileResult(logger, r.recorder, request, &cephCOSIDriver, reconcileResponse, err)
+}
+
+func (r *ReconcileCephCOSIDriver) reconcile(request reconcile.Request) (reconcile.Result, cephv1.CephCOSIDriver, error) {
+	// Fetch the CephCOSIDriver instance
+	cephCOSIDriver := &cephv1.CephCOSIDriver{}
+	err := r.client.Get(r.opManagerContext, request.NamespacedName, cephCOSIDriver)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			// the deployment of the driver is removed with its owner
+			logger.Debug("CephCOSIDriver resource not found. Ignoring since object must be deleted.")
+			return reconcile.Result{}, *cephCOSIDriver, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, *cephCOSIDriver, errors.Wrap(err, "failed to get CephCOSIDriver")
+	}
+
+	// the object stores of all the clusters are served by the single driver of the operator
+	if cephCOSIDriver.Name != CephCOSIDriverName || cephCOSIDriver.Namespace != r.opConfig.OperatorNamespace {
+		logger.Warningf("ignoring CephCOSIDriver %q in namespace %q, the driver must be named %q in the operator namespace %q",
+			cephCOSIDriver.Name, cephCOSIDriver.Namespace, CephCOSIDriverName, r.opConfig.OperatorNamespace)
+		return reconcile.Result{}, *cephCOSIDriver, nil
+	}
+
+	deploy, err := r.shouldDeployCOSIDriver(cephCOSIDriver)
+	if err != nil {
+		return reconcile.Result{}, *cephCOSIDriver, err
+	}
+	if !deploy {
+		logger.Debugf("removing the COSI driver with the %q deployment strategy", cephCOSIDriver.Spec.DeploymentStrategy)
+		if err := k8sutil.DeleteDeployment(r.opManagerContext, r.context.Clientset, cephCOSIDriver.Namespace, CephCOSIDriverName); err != nil {
+			return reconcile.Result{}, *cephCOSIDriver, errors.Wrap(err, "failed to delete the COSI driver deployment")
+		}
+		return reconcile.Result{}, *cephCOSIDriver, nil
+	}
+
+	deployment := createCOSIDriverDeployment(cephCOSIDriver)
+	if err := k8sutil.NewOwnerInfo(cephCOSIDriver, r.scheme).SetControllerReference(deployment); err != nil {
+		return reconcile.Result{}, *cephCOSIDriver, errors.Wrapf(err, "failed to set owner reference to the COSI driver deployment %q", deployment.Name)
+	}
+	if _, err := k8sutil.CreateOrUpdateDeployment(r.opManagerContext, r.context.Clientset, deployment); err != nil {
+		return reconcile.Result{}, *cephCOSIDriver, errors.Wrap(err, "failed to start the COSI driver")
+	}
+	logger.Infof("COSI driver %q started in namespace %q", CephCOSIDriverName, cephCOSIDriver.Namespace)
+
+	return reconcile.Result{}, *cephCOSIDriver, nil
+}
+
+// shouldDeployCOSIDriver returns whether the COSI driver runs with its deployment strategy. With the Auto strategy,
+// which is the default, the driver runs as long as an object store exists.
+func (r *ReconcileCephCOSIDriver) shouldDeployCOSIDriver(cephCOSIDriver *cephv1.CephCOSIDriver) (bool, error) {
+	switch cephCOSIDriver.Spec.DeploymentStrategy {
+	case cephv1.COSIDeploymentStrategyNever:
+		return false, nil
+	case cephv1.COSIDeploymentStrategyAlways:
+		return true, nil
+	}
+
+	objectStores := &cephv1.CephObjectStoreList{}
+	if err := r.client.List(r.opManagerContext, objectStores); err != nil {
+		return false, errors.Wrap(err, "failed to list the object stores served by the COSI driver")
+	}
+	return len(objectStores.Items) > 0, nil
+}
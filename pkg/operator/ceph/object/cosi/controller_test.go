@@ -0,0 +1,114 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cosi
+
+import (
+	"context"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/test"
+	"github.com/stretchr/testify/assert"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestCephCOSIDriverController(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	s := runtime.NewScheme()
+	assert.NoError(t, cephv1.AddToScheme(s))
+
+	cephCOSIDriver := &cephv1.CephCOSIDriver{
+		ObjectMeta: metav1.ObjectMeta{Name: CephCOSIDriverName, Namespace: namespace},
+	}
+	objectStore := &cephv1.CephObjectStore{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-store", Namespace: "my-cluster"},
+	}
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: CephCOSIDriverName, Namespace: namespace}}
+
+	newReconciler := func(objects ...runtime.Object) *ReconcileCephCOSIDriver {
+		return &ReconcileCephCOSIDriver{
+			client:           fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(objects...).Build(),
+			scheme:           s,
+			context:          &clusterd.Context{Clientset: test.New(t, 1)},
+			opManagerContext: ctx,
+			opConfig:         opcontroller.OperatorConfig{OperatorNamespace: namespace},
+			recorder:         record.NewFakeRecorder(5),
+		}
+	}
+	deploymentExists := func(r *ReconcileCephCOSIDriver) bool {
+		_, err := r.context.Clientset.AppsV1().Deployments(namespace).Get(ctx, CephCOSIDriverName, metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			return false
+		}
+		assert.NoError(t, err)
+		return true
+	}
+
+	t.Run("no driver without the CR", func(t *testing.T) {
+		r := newReconciler(objectStore)
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.False(t, deploymentExists(r))
+	})
+
+	t.Run("auto strategy", func(t *testing.T) {
+		r := newReconciler(cephCOSIDriver)
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.False(t, deploymentExists(r))
+
+		r = newReconciler(cephCOSIDriver, objectStore)
+		_, err = r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.True(t, deploymentExists(r))
+	})
+
+	t.Run("always and never strategies", func(t *testing.T) {
+		driver := cephCOSIDriver.DeepCopy()
+		driver.Spec.DeploymentStrategy = cephv1.COSIDeploymentStrategyAlways
+		r := newReconciler(driver)
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.True(t, deploymentExists(r))
+
+		driver.Spec.DeploymentStrategy = cephv1.COSIDeploymentStrategyNever
+		assert.NoError(t, r.client.Update(ctx, driver))
+		_, err = r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.False(t, deploymentExists(r))
+	})
+
+	t.Run("driver outside of the operator namespace is ignored", func(t *testing.T) {
+		driver := cephCOSIDriver.DeepCopy()
+		driver.Namespace = "my-cluster"
+		driver.Spec.DeploymentStrategy = cephv1.COSIDeploymentStrategyAlways
+		r := newReconciler(driver)
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: CephCOSIDriverName, Namespace: "my-cluster"}})
+		assert.NoError(t, err)
+		_, err = r.context.Clientset.AppsV1().Deployments("my-cluster").Get(ctx, CephCOSIDriverName, metav1.GetOptions{})
+		assert.True(t, kerrors.IsNotFound(err))
+	})
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cosi
+
+import (
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// CephCOSIDriverName is the name of the CephCOSIDriver and of the deployment of the driver
+	CephCOSIDriverName = "ceph-cosi-driver"
+	// DefaultCOSIDriverImage is the default image of the Ceph COSI driver
+	DefaultCOSIDriverImage = "quay.io/ceph/cosi:v0.1.1"
+	// DefaultCOSISidecarImage is the default image of the COSI provisioner sidecar
+	DefaultCOSISidecarImage = "gcr.io/k8s-staging-sig-storage/objectstorage-sidecar/objectstorage-sidecar:v20230130-v0.1.0-24-gc0cf995"
+
+	cosiServiceAccountName  = "objectstorage-provisioner"
+	cosiSidecarName         = "objectstorage-provisioner-sidecar"
+	cosiSocketVolumeName    = "socket"
+	cosiSocketMountPath     = "/var/lib/cosi"
+	cosiDriverPrefixArg     = "--driver-prefix=cosi"
+	cosiSidecarVerbosityArg = "--v=5"
+)
+
+func getCOSILabels() map[string]string {
+	return map[string]string{
+		k8sutil.AppAttr:               CephCOSIDriverName,
+		"app.kubernetes.io/part-of":   "container-object-storage-interface",
+		"app.kubernetes.io/component": "driver-ceph",
+		"app.kubernetes.io/name":      "cosi-driver-ceph",
+	}
+}
+
+// createCOSIDriverDeployment returns the deployment of the COSI driver, which runs the Ceph COSI driver and the COSI
+// provisioner sidecar sharing the socket of the driver
+func createCOSIDriverDeployment(cephCOSIDriver *cephv1.CephCOSIDriver) *appsv1.Deployment {
+	replicas := int32(1)
+	labels := getCOSILabels()
+
+	podSpec := v1.PodSpec{
+		Containers: []v1.Container{
+			createCOSIDriverContainer(cephCOSIDriver),
+			createCOSISidecarContainer(cephCOSIDriver),
+		},
+		ServiceAccountName: cosiServiceAccountName,
+		Volumes: []v1.Volume{{
+			Name:         cosiSocketVolumeName,
+			VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}},
+		}},
+	}
+	cephCOSIDriver.Spec.Placement.ApplyToPodSpec(&podSpec)
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      CephCOSIDriverName,
+			Namespace: cephCOSIDriver.Namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec:       podSpec,
+			},
+		},
+	}
+	k8sutil.AddRookVersionLabelToDeployment(deployment)
+	return deployment
+}
+
+func createCOSIDriverContainer(cephCOSIDriver *cephv1.CephCOSIDriver) v1.Container {
+	image := DefaultCOSIDriverImage
+	if cephCOSIDriver.Spec.Image != "" {
+		image = cephCOSIDriver.Spec.Image
+	}
+	return v1.Container{
+		Name:         CephCOSIDriverName,
+		Image:        image,
+		Args:         []string{cosiDriverPrefixArg},
+		Env:          []v1.EnvVar{{Name: "POD_NAMESPACE", ValueFrom: &v1.EnvVarSource{FieldRef: &v1.ObjectFieldSelector{FieldPath: "metadata.namespace"}}}},
+		VolumeMounts: []v1.VolumeMount{{Name: cosiSocketVolumeName, MountPath: cosiSocketMountPath}},
+		Resources:    cephCOSIDriver.Spec.Resources,
+	}
+}
+
+func createCOSISidecarContainer(cephCOSIDriver *cephv1.CephCOSIDriver) v1.Container {
+	image := DefaultCOSISidecarImage
+	if cephCOSIDriver.Spec.ObjectProvisionerImage != "" {
+		image = cephCOSIDriver.Spec.ObjectProvisionerImage
+	}
+	return v1.Container{
+		Name:         cosiSidecarName,
+		Image:        image,
+		Args:         []string{cosiSidecarVerbosityArg},
+		Env:          []v1.EnvVar{{Name: "POD_NAMESPACE", ValueFrom: &v1.EnvVarSource{FieldRef: &v1.ObjectFieldSelector{FieldPath: "metadata.namespace"}}}},
+		VolumeMounts: []v1.VolumeMount{{Name: cosiSocketVolumeName, MountPath: cosiSocketMountPath}},
+	}
+}
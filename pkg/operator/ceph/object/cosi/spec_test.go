@@ -0,0 +1,61 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cosi
+
+import (
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCreateCOSIDriverDeployment(t *testing.T) {
+	cephCOSIDriver := &cephv1.CephCOSIDriver{
+		ObjectMeta: metav1.ObjectMeta{Name: CephCOSIDriverName, Namespace: "rook-ceph"},
+	}
+
+	deployment := createCOSIDriverDeployment(cephCOSIDriver)
+	assert.Equal(t, CephCOSIDriverName, deployment.Name)
+	assert.Equal(t, "rook-ceph", deployment.Namespace)
+	assert.Equal(t, int32(1), *deployment.Spec.Replicas)
+	podSpec := deployment.Spec.Template.Spec
+	assert.Equal(t, cosiServiceAccountName, podSpec.ServiceAccountName)
+	assert.Equal(t, 2, len(podSpec.Containers))
+	assert.Equal(t, DefaultCOSIDriverImage, podSpec.Containers[0].Image)
+	assert.Equal(t, []string{"--driver-prefix=cosi"}, podSpec.Containers[0].Args)
+	assert.Equal(t, DefaultCOSISidecarImage, podSpec.Containers[1].Image)
+	for _, container := range podSpec.Containers {
+		assert.Equal(t, []v1.VolumeMount{{Name: cosiSocketVolumeName, MountPath: cosiSocketMountPath}}, container.VolumeMounts)
+	}
+	assert.NotNil(t, podSpec.Volumes[0].EmptyDir)
+
+	cephCOSIDriver.Spec = cephv1.CephCOSIDriverSpec{
+		Image:                  "quay.io/ceph/cosi:custom",
+		ObjectProvisionerImage: "registry.example.com/objectstorage-sidecar:custom",
+		Placement:              cephv1.Placement{Tolerations: []v1.Toleration{{Key: "storage-node", Operator: v1.TolerationOpExists}}},
+		Resources:              v1.ResourceRequirements{Limits: v1.ResourceList{v1.ResourceMemory: resource.MustParse("128Mi")}},
+	}
+	deployment = createCOSIDriverDeployment(cephCOSIDriver)
+	podSpec = deployment.Spec.Template.Spec
+	assert.Equal(t, "quay.io/ceph/cosi:custom", podSpec.Containers[0].Image)
+	assert.Equal(t, "registry.example.com/objectstorage-sidecar:custom", podSpec.Containers[1].Image)
+	assert.Equal(t, "storage-node", podSpec.Tolerations[0].Key)
+	assert.Equal(t, "128Mi", podSpec.Containers[0].Resources.Limits.Memory().String())
+}
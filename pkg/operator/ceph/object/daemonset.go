@@ -0,0 +1,108 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	apps "k8s.io/api/apps/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func (c *clusterConfig) createDaemonSet(rgwConfig *rgwConfig) (*apps.DaemonSet, error) {
+	pod, err := c.makeRGWPodSpec(rgwConfig)
+	if err != nil {
+		return nil, err
+	}
+	ds := &apps.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      rgwConfig.ResourceName,
+			Namespace: c.store.Namespace,
+			Labels:    getLabels(c.store.Name, c.store.Namespace, true),
+		},
+		Spec: apps.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: getLabels(c.store.Name, c.store.Namespace, false),
+			},
+			Template: pod,
+			// the gateways are updated one node at a time, and the update stops at the first updated gateway that
+			// does not answer its S3 readiness probe
+			UpdateStrategy: apps.DaemonSetUpdateStrategy{
+				Type: apps.RollingUpdateDaemonSetStrategyType,
+				RollingUpdate: &apps.RollingUpdateDaemonSet{
+					MaxUnavailable: &intstr.IntOrString{IntVal: int32(1)},
+				},
+			},
+		},
+	}
+	k8sutil.AddRookVersionLabelToDaemonSet(ds)
+	c.applyGatewayAnnotations(&ds.ObjectMeta)
+	c.store.Spec.Gateway.Labels.ApplyToObjectMeta(&ds.ObjectMeta)
+	controller.AddCephVersionLabelToDaemonSet(c.clusterInfo.CephVersion, ds)
+
+	return ds, nil
+}
+
+// startRGWDaemonSet runs a gateway on every node matching the placement of the gateways with a daemonset. The
+// deployment of the gateways is removed once the gateways of the daemonset are ready, so the store is served during
+// the switch. The daemonset shares the keyring of the deployment.
+func (c *clusterConfig) startRGWDaemonSet(rgwConfig *rgwConfig) error {
+	ds, err := c.createDaemonSet(rgwConfig)
+	if err != nil {
+		return errors.Wrap(err, "failed to create rgw daemonset")
+	}
+	if err := c.ownerInfo.SetControllerReference(ds); err != nil {
+		return errors.Wrapf(err, "failed to set owner reference for rgw daemonset %q", ds.Name)
+	}
+	if err := k8sutil.CreateDaemonSet(c.clusterInfo.Context, c.store.Namespace, c.context.Clientset, ds); err != nil {
+		return errors.Wrapf(err, "failed to create or update object store %q daemonset %q", c.store.Name, ds.Name)
+	}
+	logger.Infof("object store %q daemonset %q started on all the nodes matching the placement", c.store.Name, ds.Name)
+
+	_, err = c.context.Clientset.AppsV1().Deployments(c.store.Namespace).Get(c.clusterInfo.Context, rgwConfig.ResourceName, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get object store %q deployment %q", c.store.Name, rgwConfig.ResourceName)
+	}
+	if err := waitForDaemonSetReady(c.clusterInfo.Context, c.context.Clientset, c.store.Namespace, ds.Name); err != nil {
+		return errors.Wrapf(err, "failed to wait for object store %q daemonset %q before deleting the deployment it replaces", c.store.Name, ds.Name)
+	}
+	if err := k8sutil.DeleteDeployment(c.clusterInfo.Context, c.context.Clientset, c.store.Namespace, rgwConfig.ResourceName); err != nil {
+		return errors.Wrapf(err, "failed to delete object store %q deployment %q replaced by the daemonset", c.store.Name, rgwConfig.ResourceName)
+	}
+	return nil
+}
+
+// deleteRGWDaemonSet removes the daemonset of the gateways when the gateways no longer run on all the nodes
+func (c *clusterConfig) deleteRGWDaemonSet(name string) error {
+	_, err := c.context.Clientset.AppsV1().DaemonSets(c.store.Namespace).Get(c.clusterInfo.Context, name, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get object store %q daemonset %q", c.store.Name, name)
+	}
+	if err := k8sutil.DeleteDaemonset(c.clusterInfo.Context, c.context.Clientset, c.store.Namespace, name); err != nil {
+		return errors.Wrapf(err, "failed to delete object store %q daemonset %q", c.store.Name, name)
+	}
+	return nil
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package object
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/pkg/errors"
@@ -25,6 +26,7 @@ import (
 	"github.com/rook/rook/pkg/daemon/ceph/client"
 	"github.com/rook/rook/pkg/util/dependents"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 const (
@@ -83,19 +85,36 @@ func CephObjectStoreDependents(
 		return deps, errors.Wrapf(err, baseErrMsg)
 	}
 
-	// CephObjectStoreUsers
-	users, err := clusterdCtx.RookClientset.CephV1().CephObjectStoreUsers(store.Namespace).List(clusterInfo.Context, metav1.ListOptions{})
+	err = getUserDependents(deps, clusterdCtx, clusterInfo.Context, store)
 	if err != nil {
-		return deps, errors.Wrapf(err, "%s. failed to list CephObjectStoreUsers for CephObjectStore %q", baseErrMsg, nsName)
+		return deps, errors.Wrapf(err, baseErrMsg)
+	}
+
+	return deps, nil
+}
+
+// adds the CephObjectStoreUsers of the store to the given dependents list
+func getUserDependents(deps *dependents.DependentList, clusterdCtx *clusterd.Context, ctx context.Context, store *v1.CephObjectStore) error {
+	nsName := fmt.Sprintf("%s/%s", store.Namespace, store.Name)
+
+	// the users of the store may be in other namespaces than the store, even after they are no longer allowed
+	// by the store, so the users of all the namespaces are checked
+	users, err := clusterdCtx.RookClientset.CephV1().CephObjectStoreUsers(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to list CephObjectStoreUsers for CephObjectStore %q", nsName)
 	}
 	for _, user := range users.Items {
-		if user.Spec.Store == store.Name {
-			deps.Add("CephObjectStoreUsers", user.Name)
+		if user.StoreNamespacedName() == (types.NamespacedName{Namespace: store.Namespace, Name: store.Name}) {
+			if user.Namespace == store.Namespace {
+				deps.Add("CephObjectStoreUsers", user.Name)
+			} else {
+				deps.Add("CephObjectStoreUsers", fmt.Sprintf("%s/%s", user.Namespace, user.Name))
+			}
+			continue
 		}
 		logger.Debugf("found CephObjectStoreUser %q that does not depend on CephObjectStore %q", user.Name, nsName)
 	}
-
-	return deps, nil
+	return nil
 }
 
 // adds bucket dependents to the given dependents list
@@ -435,6 +435,40 @@ func TestCephObjectStoreDependents(t *testing.T) {
 		assert.ElementsMatch(t, []string{"u1"}, deps.OfKind("CephObjectStoreUsers"))
 	})
 
+	t.Run("objectstore users in other namespaces and no buckets", func(t *testing.T) {
+		executor = &exectest.MockExecutor{
+			MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+				if args[0] == "osd" && args[1] == "lspools" {
+					output, err := json.Marshal(pools)
+					assert.Nil(t, err)
+					return string(output), nil
+				}
+				return "", errors.Errorf("unexpected ceph command %q", args)
+			},
+		}
+		c = newClusterdCtx(executor)
+		for _, u := range []*cephv1.CephObjectStoreUser{
+			{ObjectMeta: v1.ObjectMeta{Name: "u1", Namespace: "tenant-a"}, Spec: cephv1.ObjectStoreUserSpec{Store: clusterInfo.Namespace + "/my-store"}},
+			{ObjectMeta: v1.ObjectMeta{Name: "u2", Namespace: "tenant-a"}, Spec: cephv1.ObjectStoreUserSpec{Store: "my-store"}},
+		} {
+			_, err := c.RookClientset.CephV1().CephObjectStoreUsers(u.Namespace).Create(context.TODO(), u, v1.CreateOptions{})
+			assert.NoError(t, err)
+		}
+		client, err := admin.New("rook-ceph-rgw-my-store.mycluster.svc", "53S6B9S809NUP19IJ2K3", "1bXPegzsGClvoGAiJdHQD1uOW2sQBLAZM9j9VtXR", mockClient(`[]`))
+		assert.NoError(t, err)
+
+		allowingStore := store.DeepCopy()
+		allowingStore.Spec.AllowUsersInNamespaces = []string{"tenant-a"}
+		deps, err := CephObjectStoreDependents(c, clusterInfo, allowingStore, NewContext(c, clusterInfo, store.Name), &AdminOpsContext{AdminOpsClient: client})
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"tenant-a/u1"}, deps.OfKind("CephObjectStoreUsers"))
+
+		// the users in other namespaces are still found after the store no longer allows them
+		deps, err = CephObjectStoreDependents(c, clusterInfo, store, NewContext(c, clusterInfo, store.Name), &AdminOpsContext{AdminOpsClient: client})
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"tenant-a/u1"}, deps.OfKind("CephObjectStoreUsers"))
+	})
+
 	t.Run("store belong to secondary zone with no objectstore users and no buckets", func(t *testing.T) {
 		executor = &exectest.MockExecutor{
 			MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
@@ -0,0 +1,182 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/banzaicloud/k8s-objectmatcher/patch"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// the maximum length of the message of an event accepted by the api server
+const maxEventMessageLength = 1024
+
+// deploymentChanges returns the changes of the annotations of the deployment and of its pods, of the images, commands,
+// args and env of the containers and of the volumes between the current and the desired deployment. The other fields
+// are not compared since the api server sets their defaults on the current deployment.
+func deploymentChanges(current, desired *appsv1.Deployment) []string {
+	changes := annotationsChanges("deployment", current.Annotations, desired.Annotations)
+	changes = append(changes, annotationsChanges("pod", current.Spec.Template.Annotations, desired.Spec.Template.Annotations)...)
+	changes = append(changes, containersChanges("init container", current.Spec.Template.Spec.InitContainers, desired.Spec.Template.Spec.InitContainers)...)
+	changes = append(changes, containersChanges("container", current.Spec.Template.Spec.Containers, desired.Spec.Template.Spec.Containers)...)
+
+	currentVolumes := map[string]bool{}
+	for _, volume := range current.Spec.Template.Spec.Volumes {
+		currentVolumes[volume.Name] = true
+	}
+	desiredVolumes := map[string]bool{}
+	for _, volume := range desired.Spec.Template.Spec.Volumes {
+		desiredVolumes[volume.Name] = true
+		if !currentVolumes[volume.Name] {
+			changes = append(changes, fmt.Sprintf("volume %q added", volume.Name))
+		}
+	}
+	for _, volume := range current.Spec.Template.Spec.Volumes {
+		if !desiredVolumes[volume.Name] {
+			changes = append(changes, fmt.Sprintf("volume %q removed", volume.Name))
+		}
+	}
+	return changes
+}
+
+// annotationsChanges compares the annotations set by the operator. The annotations managed by kubernetes and the
+// last applied configuration, which changes with any other field, are ignored.
+func annotationsChanges(kind string, current, desired map[string]string) []string {
+	changes := []string{}
+	for _, key := range sortedKeys(desired) {
+		if ignoredAnnotation(key) {
+			continue
+		}
+		previous, ok := current[key]
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("%s annotation %s added", kind, key))
+		case previous != desired[key]:
+			changes = append(changes, fmt.Sprintf("%s annotation %s changed from %q to %q", kind, key, previous, desired[key]))
+		}
+	}
+	for _, key := range sortedKeys(current) {
+		if _, ok := desired[key]; !ok && !ignoredAnnotation(key) {
+			changes = append(changes, fmt.Sprintf("%s annotation %s removed", kind, key))
+		}
+	}
+	return changes
+}
+
+func ignoredAnnotation(key string) bool {
+	return key == patch.LastAppliedConfig || strings.HasPrefix(key, "deployment.kubernetes.io/")
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func containersChanges(kind string, current, desired []v1.Container) []string {
+	changes := []string{}
+	currentContainers := map[string]v1.Container{}
+	for _, container := range current {
+		currentContainers[container.Name] = container
+	}
+	desiredContainers := map[string]bool{}
+	for _, container := range desired {
+		desiredContainers[container.Name] = true
+		previous, ok := currentContainers[container.Name]
+		if !ok {
+			changes = append(changes, fmt.Sprintf("%s %q added", kind, container.Name))
+			continue
+		}
+		if previous.Image != container.Image {
+			changes = append(changes, fmt.Sprintf("%s %q image changed from %q to %q", kind, container.Name, previous.Image, container.Image))
+		}
+		if !reflect.DeepEqual(previous.Command, container.Command) {
+			changes = append(changes, fmt.Sprintf("%s %q command changed from %v to %v", kind, container.Name, previous.Command, container.Command))
+		}
+		if !reflect.DeepEqual(previous.Args, container.Args) {
+			changes = append(changes, fmt.Sprintf("%s %q args changed from %v to %v", kind, container.Name, previous.Args, container.Args))
+		}
+		changes = append(changes, envChanges(kind, container.Name, previous.Env, container.Env)...)
+	}
+	for _, container := range current {
+		if !desiredContainers[container.Name] {
+			changes = append(changes, fmt.Sprintf("%s %q removed", kind, container.Name))
+		}
+	}
+	return changes
+}
+
+// envChanges compares the values of the env variables. The variables set from a source are only reported when they
+// are added or removed.
+func envChanges(kind, containerName string, current, desired []v1.EnvVar) []string {
+	changes := []string{}
+	currentEnv := map[string]v1.EnvVar{}
+	for _, env := range current {
+		currentEnv[env.Name] = env
+	}
+	desiredEnv := map[string]bool{}
+	for _, env := range desired {
+		desiredEnv[env.Name] = true
+		previous, ok := currentEnv[env.Name]
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("%s %q env %s added", kind, containerName, env.Name))
+		case previous.Value != env.Value:
+			changes = append(changes, fmt.Sprintf("%s %q env %s changed from %q to %q", kind, containerName, env.Name, previous.Value, env.Value))
+		}
+	}
+	for _, env := range current {
+		if !desiredEnv[env.Name] {
+			changes = append(changes, fmt.Sprintf("%s %q env %s removed", kind, containerName, env.Name))
+		}
+	}
+	return changes
+}
+
+// reportDeploymentChanges logs the changes the operator is about to make to an rgw deployment, and records them in an
+// event on the object store, so that a rollout of the gateways can be traced back to the operator
+func (c *clusterConfig) reportDeploymentChanges(desired *appsv1.Deployment) {
+	current, err := c.context.Clientset.AppsV1().Deployments(desired.Namespace).Get(c.clusterInfo.Context, desired.Name, metav1.GetOptions{})
+	if err != nil {
+		logger.Debugf("failed to get object store %q deployment %q to report its changes. %v", c.store.Name, desired.Name, err)
+		return
+	}
+	changes := deploymentChanges(current, desired)
+	if len(changes) == 0 {
+		return
+	}
+
+	message := fmt.Sprintf("updating deployment %q: %s", desired.Name, strings.Join(changes, "; "))
+	logger.Infof("object store %q %s", c.store.Name, message)
+	if c.recorder == nil {
+		return
+	}
+	if len(message) > maxEventMessageLength {
+		message = message[:maxEventMessageLength-3] + "..."
+	}
+	c.recorder.Event(c.store, v1.EventTypeNormal, string(cephv1.DeploymentUpdatedReason), message)
+}
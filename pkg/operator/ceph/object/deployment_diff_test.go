@@ -0,0 +1,105 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"context"
+	"testing"
+
+	"github.com/banzaicloud/k8s-objectmatcher/patch"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+)
+
+func newDiffTestDeployment(image string, args []string, env []v1.EnvVar, volumes ...string) *appsv1.Deployment {
+	d := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-rgw-my-store-a", Namespace: "rook-ceph"}}
+	d.Spec.Template.Spec.Containers = []v1.Container{{Name: "rgw", Image: image, Args: args, Env: env}}
+	for _, volume := range volumes {
+		d.Spec.Template.Spec.Volumes = append(d.Spec.Template.Spec.Volumes, v1.Volume{Name: volume})
+	}
+	return d
+}
+
+func TestDeploymentChanges(t *testing.T) {
+	env := []v1.EnvVar{{Name: "POD_NAME", ValueFrom: &v1.EnvVarSource{FieldRef: &v1.ObjectFieldSelector{FieldPath: "metadata.name"}}}}
+	current := newDiffTestDeployment("quay.io/ceph/ceph:v17", []string{"--foreground"}, env, "rook-ceph-rgw-config")
+	current.Annotations = map[string]string{"deployment.kubernetes.io/revision": "3", patch.LastAppliedConfig: "{}"}
+	current.Spec.Template.Annotations = map[string]string{"prometheus.io/port": "9283", "kubectl.kubernetes.io/restartedAt": "2023-05-01T10:00:00Z"}
+
+	t.Run("no changes", func(t *testing.T) {
+		desired := current.DeepCopy()
+		// the defaults of the api server are ignored
+		desired.Spec.Template.Spec.Containers[0].Env[0].ValueFrom.FieldRef.APIVersion = "v1"
+		// the annotations managed by kubernetes and the last applied configuration are ignored
+		desired.Annotations = map[string]string{patch.LastAppliedConfig: `{"spec":{}}`}
+		assert.Empty(t, deploymentChanges(current, desired))
+	})
+
+	t.Run("changes", func(t *testing.T) {
+		desiredEnv := []v1.EnvVar{{Name: "ROOK_DEBUG", Value: "true"}}
+		desired := newDiffTestDeployment("quay.io/ceph/ceph:v18", []string{"--foreground", "--debug-rgw=20"}, desiredEnv, "rook-ceph-rgw-config", "rgw-cert")
+		desired.Spec.Template.Spec.InitContainers = []v1.Container{{Name: "chown-container-data-dir"}}
+		desired.Annotations = map[string]string{"example.com/owner": "storage"}
+		desired.Spec.Template.Annotations = map[string]string{"prometheus.io/port": "9284"}
+		assert.Equal(t, []string{
+			`deployment annotation example.com/owner added`,
+			`pod annotation prometheus.io/port changed from "9283" to "9284"`,
+			`pod annotation kubectl.kubernetes.io/restartedAt removed`,
+			`init container "chown-container-data-dir" added`,
+			`container "rgw" image changed from "quay.io/ceph/ceph:v17" to "quay.io/ceph/ceph:v18"`,
+			`container "rgw" args changed from [--foreground] to [--foreground --debug-rgw=20]`,
+			`container "rgw" env ROOK_DEBUG added`,
+			`container "rgw" env POD_NAME removed`,
+			`volume "rgw-cert" added`,
+		}, deploymentChanges(current, desired))
+	})
+}
+
+func TestReportDeploymentChanges(t *testing.T) {
+	current := newDiffTestDeployment("quay.io/ceph/ceph:v17", nil, nil)
+	clientset := fake.NewSimpleClientset()
+	_, err := clientset.AppsV1().Deployments("rook-ceph").Create(context.TODO(), current, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	recorder := record.NewFakeRecorder(5)
+	c := &clusterConfig{
+		context:     &clusterd.Context{Clientset: clientset},
+		clusterInfo: cephclient.AdminTestClusterInfo("rook-ceph"),
+		store:       &cephv1.CephObjectStore{ObjectMeta: metav1.ObjectMeta{Name: "my-store", Namespace: "rook-ceph"}},
+		recorder:    recorder,
+	}
+
+	c.reportDeploymentChanges(current.DeepCopy())
+	assert.Len(t, recorder.Events, 0)
+
+	c.reportDeploymentChanges(newDiffTestDeployment("quay.io/ceph/ceph:v18", nil, nil))
+	assert.Len(t, recorder.Events, 1)
+	assert.Equal(t, `Normal DeploymentUpdated updating deployment "rook-ceph-rgw-my-store-a": container "rgw" image changed from "quay.io/ceph/ceph:v17" to "quay.io/ceph/ceph:v18"`, <-recorder.Events)
+
+	// the changes of a new deployment are not reported
+	c.store.Name = "other-store"
+	other := newDiffTestDeployment("quay.io/ceph/ceph:v18", nil, nil)
+	other.Name = "rook-ceph-rgw-other-store-a"
+	c.reportDeploymentChanges(other)
+	assert.Len(t, recorder.Events, 0)
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+)
+
+const (
+	rgwDNSNameOption = "rgw_dns_name"
+	// dnsNamesHashAnnotation restarts the rgw daemons when the dns names change, since rgw only reads the
+	// rgw_dns_name and the hostnames of the zonegroup at startup
+	dnsNamesHashAnnotation = "dns-names-hash"
+)
+
+// hostingConfigOptions returns the config options of the hosting settings of the store
+func hostingConfigOptions(hosting *cephv1.ObjectStoreHostingSpec) map[string]string {
+	options := map[string]string{}
+	if hosting != nil && hosting.DNSName != "" {
+		options[rgwDNSNameOption] = hosting.DNSName
+	}
+	return options
+}
+
+// configureZoneGroupHostnames sets the hostnames of the zonegroup of the store to the additional dns names of its
+// hosting, which rgw accepts in addition to the rgw_dns_name. The hostnames are cleared when the store has no hosting.
+// The zonegroup is only updated and committed when its hostnames change.
+func configureZoneGroupHostnames(objContext *Context, hosting *cephv1.ObjectStoreHostingSpec) error {
+	dnsNames := []string{}
+	if hosting != nil {
+		dnsNames = hosting.AdditionalDNSNames
+	}
+
+	output, err := runAdminCommand(objContext, true, "zonegroup", "get")
+	if err != nil {
+		return errorOrIsNotFound(err, "failed to get zonegroup %q", objContext.ZoneGroup)
+	}
+	zoneGroupConfig := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(output), &zoneGroupConfig); err != nil {
+		return errors.Wrapf(err, "failed to parse config of zonegroup %q", objContext.ZoneGroup)
+	}
+
+	hostnames := []interface{}{}
+	for _, name := range dnsNames {
+		hostnames = append(hostnames, name)
+	}
+	current, _ := zoneGroupConfig["hostnames"].([]interface{})
+	if reflect.DeepEqual(current, hostnames) || (len(current) == 0 && len(hostnames) == 0) {
+		logger.Debugf("zonegroup %q already has the hostnames %v", objContext.ZoneGroup, dnsNames)
+		return nil
+	}
+	zoneGroupConfig["hostnames"] = hostnames
+
+	zoneGroupJSON, err := json.Marshal(zoneGroupConfig)
+	if err != nil {
+		return errors.Wrapf(err, "failed to serialize config of zonegroup %q", objContext.ZoneGroup)
+	}
+	if err := RunAdminCommandWithStdin(objContext, string(zoneGroupJSON), "zonegroup", "set"); err != nil {
+		return errors.Wrapf(err, "failed to set the hostnames of zonegroup %q", objContext.ZoneGroup)
+	}
+
+	if err := commitConfigChanges(objContext); err != nil {
+		nsName := fmt.Sprintf("%s/%s", objContext.clusterInfo.Namespace, objContext.Name)
+		return errors.Wrapf(err, "failed to commit config changes after setting the hostnames of CephObjectStore %q", nsName)
+	}
+	logger.Infof("zonegroup %q has the hostnames %v", objContext.ZoneGroup, dnsNames)
+	return nil
+}
+
+// dnsNamesHash returns the hash of the dns names of the hosting, or an empty string without dns names
+func dnsNamesHash(hosting *cephv1.ObjectStoreHostingSpec) string {
+	if hosting == nil || (hosting.DNSName == "" && len(hosting.AdditionalDNSNames) == 0) {
+		return ""
+	}
+	return k8sutil.Hash(fmt.Sprintf("%s %v", hosting.DNSName, hosting.AdditionalDNSNames))
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigureZoneGroupHostnames(t *testing.T) {
+	commitConfigChangesOrig := commitConfigChanges
+	defer func() { commitConfigChanges = commitConfigChangesOrig }()
+	committed := false
+	commitConfigChanges = func(c *Context) error {
+		committed = true
+		return nil
+	}
+
+	zoneGroup := `{"id":"1234","name":"my-store","api_name":"my-store","is_master":"true","endpoints":[],"hostnames":[],"master_zone":"5678"}`
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			if args[0] == "zonegroup" && args[1] == "get" {
+				return zoneGroup, nil
+			}
+			return "", nil
+		},
+		MockExecuteCommandWithStdin: func(timeout time.Duration, command string, stdin *string, args ...string) error {
+			assert.Equal(t, "zonegroup set", strings.Join(args[0:2], " "))
+			assert.Contains(t, args, "--rgw-zonegroup=my-store")
+			zoneGroup = *stdin
+			return nil
+		},
+	}
+	clusterInfo := client.AdminTestClusterInfo("rook-ceph")
+	clusterInfo.Context = context.TODO()
+	objContext := NewContext(&clusterd.Context{Executor: executor}, clusterInfo, "my-store")
+	objContext.Realm = "my-store"
+	objContext.ZoneGroup = "my-store"
+	objContext.Zone = "my-store"
+
+	// no additional names
+	hosting := &cephv1.ObjectStoreHostingSpec{DNSName: "s3.example.com"}
+	assert.NoError(t, configureZoneGroupHostnames(objContext, hosting))
+	assert.False(t, committed)
+
+	hosting.AdditionalDNSNames = []string{"s3.internal.example.com", "s3.apps.example.com"}
+	assert.NoError(t, configureZoneGroupHostnames(objContext, hosting))
+	assert.True(t, committed)
+	assert.Contains(t, zoneGroup, `"hostnames":["s3.internal.example.com","s3.apps.example.com"]`)
+	assert.Contains(t, zoneGroup, `"master_zone":"5678"`)
+
+	// the zonegroup is not updated again
+	committed = false
+	assert.NoError(t, configureZoneGroupHostnames(objContext, hosting))
+	assert.False(t, committed)
+
+	// the names are removed
+	hosting.AdditionalDNSNames = nil
+	assert.NoError(t, configureZoneGroupHostnames(objContext, hosting))
+	assert.True(t, committed)
+	assert.Contains(t, zoneGroup, `"hostnames":[]`)
+
+	// the names are removed with the hosting
+	hosting.AdditionalDNSNames = []string{"s3.internal.example.com"}
+	assert.NoError(t, configureZoneGroupHostnames(objContext, hosting))
+	committed = false
+	assert.NoError(t, configureZoneGroupHostnames(objContext, nil))
+	assert.True(t, committed)
+	assert.Contains(t, zoneGroup, `"hostnames":[]`)
+}
+
+func TestDNSNamesHash(t *testing.T) {
+	assert.Equal(t, "", dnsNamesHash(nil))
+	assert.Equal(t, "", dnsNamesHash(&cephv1.ObjectStoreHostingSpec{}))
+
+	hash := dnsNamesHash(&cephv1.ObjectStoreHostingSpec{DNSName: "s3.example.com"})
+	assert.NotEqual(t, "", hash)
+	assert.NotEqual(t, hash, dnsNamesHash(&cephv1.ObjectStoreHostingSpec{DNSName: "s3.example.com", AdditionalDNSNames: []string{"s3.internal.example.com"}}))
+}
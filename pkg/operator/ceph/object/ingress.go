@@ -0,0 +1,207 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	routev1 "github.com/openshift/api/route/v1"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// reconcileIngress exposes the rgw service of the store with the ingress of the gateway spec, and removes the
+// ingress when it is no longer in the spec. On OpenShift, where the route API is served, the service is exposed
+// with a route instead unless an ingress class is set. The ingress routes to the http port of the service, or to
+// its https port when the gateways only listen on the secure port.
+func (c *clusterConfig) reconcileIngress() error {
+	routesServed, err := c.routesServed()
+	if err != nil {
+		return err
+	}
+	spec := c.store.Spec.Gateway.Ingress
+	useRoute := spec != nil && spec.IngressClassName == nil && routesServed
+
+	if spec == nil || useRoute {
+		if err := c.deleteIngress(); err != nil {
+			return err
+		}
+	}
+	if routesServed && !useRoute {
+		if err := c.deleteRoute(); err != nil {
+			return err
+		}
+	}
+	if spec == nil {
+		return nil
+	}
+	if useRoute {
+		return c.reconcileRoute()
+	}
+
+	name := instanceName(c.store.Name)
+	ingresses := c.context.Clientset.NetworkingV1().Ingresses(c.store.Namespace)
+	pathType := networkingv1.PathTypePrefix
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: c.store.Namespace,
+			Labels:    getLabels(c.store.Name, c.store.Namespace, true),
+		},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: spec.IngressClassName,
+			Rules: []networkingv1.IngressRule{{
+				Host: spec.Host,
+				IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{{
+						Path:     "/",
+						PathType: &pathType,
+						Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+							Name: name,
+							Port: networkingv1.ServiceBackendPort{Name: c.ingressBackendPort()},
+						}},
+					}},
+				}},
+			}},
+		},
+	}
+	if spec.TLSSecretName != "" {
+		ingress.Spec.TLS = []networkingv1.IngressTLS{{Hosts: []string{spec.Host}, SecretName: spec.TLSSecretName}}
+	}
+	spec.Annotations.ApplyToObjectMeta(&ingress.ObjectMeta)
+	if err := c.ownerInfo.SetControllerReference(ingress); err != nil {
+		return errors.Wrapf(err, "failed to set owner reference to rgw ingress %q", name)
+	}
+
+	current, err := ingresses.Get(c.clusterInfo.Context, name, metav1.GetOptions{})
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get rgw ingress %q", name)
+		}
+		if _, err := ingresses.Create(c.clusterInfo.Context, ingress, metav1.CreateOptions{}); err != nil {
+			return errors.Wrapf(err, "failed to create rgw ingress %q", name)
+		}
+		logger.Infof("object store %q is exposed at host %q by ingress %q", c.store.Name, spec.Host, name)
+		return nil
+	}
+	ingress.ResourceVersion = current.ResourceVersion
+	if _, err := ingresses.Update(c.clusterInfo.Context, ingress, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrapf(err, "failed to update rgw ingress %q", name)
+	}
+	return nil
+}
+
+// reconcileRoute exposes the rgw service of the store with an OpenShift route. Since a route cannot refer to a
+// secret, the certificate of the TLS secret is copied in the route, which terminates the TLS at the router and
+// re-encrypts it to the secure port. Without a TLS secret, the TLS of the secure port is passed through.
+func (c *clusterConfig) reconcileRoute() error {
+	name := instanceName(c.store.Name)
+	spec := c.store.Spec.Gateway.Ingress
+	backendPort := c.ingressBackendPort()
+
+	route := &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: c.store.Namespace,
+			Labels:    getLabels(c.store.Name, c.store.Namespace, true),
+		},
+		Spec: routev1.RouteSpec{
+			Host: spec.Host,
+			To:   routev1.RouteTargetReference{Kind: "Service", Name: name},
+			Port: &routev1.RoutePort{TargetPort: intstr.FromString(backendPort)},
+		},
+	}
+	if spec.TLSSecretName != "" {
+		secret, err := c.context.Clientset.CoreV1().Secrets(c.store.Namespace).Get(c.clusterInfo.Context, spec.TLSSecretName, metav1.GetOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to get the tls secret %q of rgw route %q", spec.TLSSecretName, name)
+		}
+		route.Spec.TLS = &routev1.TLSConfig{
+			Termination: routev1.TLSTerminationEdge,
+			Certificate: string(secret.Data[v1.TLSCertKey]),
+			Key:         string(secret.Data[v1.TLSPrivateKeyKey]),
+		}
+		if backendPort == "https" {
+			route.Spec.TLS.Termination = routev1.TLSTerminationReencrypt
+		}
+	} else if backendPort == "https" {
+		route.Spec.TLS = &routev1.TLSConfig{Termination: routev1.TLSTerminationPassthrough}
+	}
+	spec.Annotations.ApplyToObjectMeta(&route.ObjectMeta)
+	if err := c.ownerInfo.SetControllerReference(route); err != nil {
+		return errors.Wrapf(err, "failed to set owner reference to rgw route %q", name)
+	}
+
+	current := &routev1.Route{}
+	err := c.client.Get(c.clusterInfo.Context, types.NamespacedName{Namespace: c.store.Namespace, Name: name}, current)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get rgw route %q", name)
+		}
+		if err := c.client.Create(c.clusterInfo.Context, route); err != nil {
+			return errors.Wrapf(err, "failed to create rgw route %q", name)
+		}
+		logger.Infof("object store %q is exposed at host %q by route %q", c.store.Name, spec.Host, name)
+		return nil
+	}
+	route.ResourceVersion = current.ResourceVersion
+	if err := c.client.Update(c.clusterInfo.Context, route); err != nil {
+		return errors.Wrapf(err, "failed to update rgw route %q", name)
+	}
+	return nil
+}
+
+// ingressBackendPort returns the port of the rgw service the ingress or the route sends the requests to
+func (c *clusterConfig) ingressBackendPort() string {
+	if c.store.Spec.Gateway.Port == 0 {
+		return "https"
+	}
+	return "http"
+}
+
+// routesServed returns whether the cluster serves the OpenShift route API
+func (c *clusterConfig) routesServed() (bool, error) {
+	_, err := c.context.Clientset.Discovery().ServerResourcesForGroupVersion(routev1.GroupVersion.String())
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, errors.Wrap(err, "failed to check whether the cluster serves the route api")
+	}
+	return true, nil
+}
+
+func (c *clusterConfig) deleteIngress() error {
+	name := instanceName(c.store.Name)
+	err := c.context.Clientset.NetworkingV1().Ingresses(c.store.Namespace).Delete(c.clusterInfo.Context, name, metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete rgw ingress %q", name)
+	}
+	return nil
+}
+
+func (c *clusterConfig) deleteRoute() error {
+	route := &routev1.Route{ObjectMeta: metav1.ObjectMeta{Name: instanceName(c.store.Name), Namespace: c.store.Namespace}}
+	err := c.client.Delete(c.clusterInfo.Context, route)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete rgw route %q", route.Name)
+	}
+	return nil
+}
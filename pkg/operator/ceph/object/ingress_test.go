@@ -0,0 +1,164 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"context"
+	"testing"
+
+	routev1 "github.com/openshift/api/route/v1"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileIngress(t *testing.T) {
+	c := newConfig(t)
+	c.clusterInfo = cephclient.AdminTestClusterInfo("rook-ceph")
+	c.ownerInfo = cephclient.NewMinimumOwnerInfoWithOwnerRef()
+	c.store.Name = "my-store"
+	c.store.Namespace = "rook-ceph"
+	c.store.Spec.Gateway.Port = 80
+	ingresses := c.context.Clientset.NetworkingV1().Ingresses("rook-ceph")
+
+	// no ingress without the spec
+	assert.NoError(t, c.reconcileIngress())
+	_, err := ingresses.Get(context.TODO(), "rook-ceph-rgw-my-store", metav1.GetOptions{})
+	assert.True(t, kerrors.IsNotFound(err))
+
+	className := "nginx"
+	c.store.Spec.Gateway.Ingress = &cephv1.RGWIngressSpec{
+		Host:             "s3.example.com",
+		TLSSecretName:    "s3-tls",
+		IngressClassName: &className,
+		Annotations:      cephv1.Annotations{"nginx.ingress.kubernetes.io/proxy-body-size": "0"},
+	}
+	assert.NoError(t, c.reconcileIngress())
+	ingress, err := ingresses.Get(context.TODO(), "rook-ceph-rgw-my-store", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "nginx", *ingress.Spec.IngressClassName)
+	assert.Equal(t, "0", ingress.Annotations["nginx.ingress.kubernetes.io/proxy-body-size"])
+	assert.Equal(t, "s3.example.com", ingress.Spec.Rules[0].Host)
+	backend := ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service
+	assert.Equal(t, "rook-ceph-rgw-my-store", backend.Name)
+	assert.Equal(t, "http", backend.Port.Name)
+	assert.Equal(t, []string{"s3.example.com"}, ingress.Spec.TLS[0].Hosts)
+	assert.Equal(t, "s3-tls", ingress.Spec.TLS[0].SecretName)
+
+	// the ingress is updated, and routes to the secure port when the gateways only listen on it
+	c.store.Spec.Gateway.Port = 0
+	c.store.Spec.Gateway.SecurePort = 443
+	c.store.Spec.Gateway.Ingress.TLSSecretName = ""
+	assert.NoError(t, c.reconcileIngress())
+	ingress, err = ingresses.Get(context.TODO(), "rook-ceph-rgw-my-store", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "https", ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Port.Name)
+	assert.Empty(t, ingress.Spec.TLS)
+
+	// the ingress is removed with the spec
+	c.store.Spec.Gateway.Ingress = nil
+	assert.NoError(t, c.reconcileIngress())
+	_, err = ingresses.Get(context.TODO(), "rook-ceph-rgw-my-store", metav1.GetOptions{})
+	assert.True(t, kerrors.IsNotFound(err))
+}
+
+func TestReconcileRoute(t *testing.T) {
+	c := newConfig(t)
+	c.clusterInfo = cephclient.AdminTestClusterInfo("rook-ceph")
+	c.store.Name = "my-store"
+	c.store.Namespace = "rook-ceph"
+	c.store.Spec.Gateway.Port = 80
+	s := runtime.NewScheme()
+	assert.NoError(t, routev1.AddToScheme(s))
+	c.client = fake.NewClientBuilder().WithScheme(s).Build()
+	// the cluster serves the route api
+	c.context.Clientset.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{{
+		GroupVersion: routev1.GroupVersion.String(),
+		APIResources: []metav1.APIResource{{Name: "routes", Kind: "Route"}},
+	}}
+	_, err := c.context.Clientset.CoreV1().Secrets("rook-ceph").Create(context.TODO(), &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "s3-tls", Namespace: "rook-ceph"},
+		Data:       map[string][]byte{v1.TLSCertKey: []byte("cert"), v1.TLSPrivateKeyKey: []byte("key")},
+	}, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	getRoute := func() (*routev1.Route, error) {
+		route := &routev1.Route{}
+		err := c.client.Get(context.TODO(), types.NamespacedName{Namespace: "rook-ceph", Name: "rook-ceph-rgw-my-store"}, route)
+		return route, err
+	}
+	ingresses := c.context.Clientset.NetworkingV1().Ingresses("rook-ceph")
+
+	// a route instead of an ingress
+	c.store.Spec.Gateway.Ingress = &cephv1.RGWIngressSpec{
+		Host:          "s3.example.com",
+		TLSSecretName: "s3-tls",
+		Annotations:   cephv1.Annotations{"haproxy.router.openshift.io/timeout": "5m"},
+	}
+	assert.NoError(t, c.reconcileIngress())
+	route, err := getRoute()
+	assert.NoError(t, err)
+	assert.Equal(t, "s3.example.com", route.Spec.Host)
+	assert.Equal(t, "rook-ceph-rgw-my-store", route.Spec.To.Name)
+	assert.Equal(t, "http", route.Spec.Port.TargetPort.String())
+	assert.Equal(t, routev1.TLSTerminationEdge, route.Spec.TLS.Termination)
+	assert.Equal(t, "cert", route.Spec.TLS.Certificate)
+	assert.Equal(t, "key", route.Spec.TLS.Key)
+	assert.Equal(t, "5m", route.Annotations["haproxy.router.openshift.io/timeout"])
+	_, err = ingresses.Get(context.TODO(), "rook-ceph-rgw-my-store", metav1.GetOptions{})
+	assert.True(t, kerrors.IsNotFound(err))
+
+	// the tls is re-encrypted to the secure port, or passed through without a secret
+	c.store.Spec.Gateway.Port = 0
+	c.store.Spec.Gateway.SecurePort = 443
+	assert.NoError(t, c.reconcileIngress())
+	route, err = getRoute()
+	assert.NoError(t, err)
+	assert.Equal(t, "https", route.Spec.Port.TargetPort.String())
+	assert.Equal(t, routev1.TLSTerminationReencrypt, route.Spec.TLS.Termination)
+	c.store.Spec.Gateway.Ingress.TLSSecretName = ""
+	assert.NoError(t, c.reconcileIngress())
+	route, err = getRoute()
+	assert.NoError(t, err)
+	assert.Equal(t, routev1.TLSTerminationPassthrough, route.Spec.TLS.Termination)
+	assert.Empty(t, route.Spec.TLS.Certificate)
+
+	// an ingress replaces the route when an ingress class is set
+	className := "nginx"
+	c.store.Spec.Gateway.Ingress.IngressClassName = &className
+	assert.NoError(t, c.reconcileIngress())
+	_, err = getRoute()
+	assert.True(t, kerrors.IsNotFound(err))
+	_, err = ingresses.Get(context.TODO(), "rook-ceph-rgw-my-store", metav1.GetOptions{})
+	assert.NoError(t, err)
+
+	// the route is removed with the spec
+	c.store.Spec.Gateway.Ingress.IngressClassName = nil
+	assert.NoError(t, c.reconcileIngress())
+	_, err = getRoute()
+	assert.NoError(t, err)
+	c.store.Spec.Gateway.Ingress = nil
+	assert.NoError(t, c.reconcileIngress())
+	_, err = getRoute()
+	assert.True(t, kerrors.IsNotFound(err))
+}
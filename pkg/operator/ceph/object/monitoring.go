@@ -0,0 +1,101 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+)
+
+const (
+	monitoringPath            = "/etc/ceph-monitoring/"
+	serviceMonitorFile        = "rgw-service-monitor.yaml"
+	cephExporterAppName       = "rook-ceph-exporter"
+	exporterServiceMetricName = "ceph-exporter-http-metrics"
+)
+
+// allow the service monitor calls to be overridden for unit testing
+var (
+	createOrUpdateServiceMonitor = k8sutil.CreateOrUpdateServiceMonitor
+	deleteServiceMonitor         = k8sutil.DeleteServiceMonitor
+)
+
+// reconcileServiceMonitor adds a servicemonitor for the rgw metrics of the store when monitoring is enabled in
+// the cluster, and removes it otherwise. The rgw perf counters are collected by the ceph-exporter of the node
+// from the admin socket of the rgw daemons, so the servicemonitor scrapes the exporter service and only keeps
+// the metrics of the rgw daemons of this store.
+func (c *clusterConfig) reconcileServiceMonitor() error {
+	name := instanceName(c.store.Name)
+	if !c.clusterSpec.Monitoring.Enabled {
+		if err := deleteServiceMonitor(c.clusterInfo.Context, c.store.Namespace, name); err != nil {
+			logger.Debugf("failed to delete rgw service monitor %q. %v", name, err)
+		}
+		return nil
+	}
+
+	serviceMonitor, err := k8sutil.GetServiceMonitor(path.Join(monitoringPath, serviceMonitorFile))
+	if err != nil {
+		return errors.Wrap(err, "rgw service monitor could not be enabled")
+	}
+	c.applyServiceMonitorSpec(serviceMonitor)
+	if err := c.ownerInfo.SetControllerReference(serviceMonitor); err != nil {
+		return errors.Wrapf(err, "failed to set owner reference to service monitor %q", serviceMonitor.Name)
+	}
+
+	if _, err := createOrUpdateServiceMonitor(c.clusterInfo.Context, serviceMonitor); err != nil {
+		return errors.Wrap(err, "rgw service monitor could not be enabled")
+	}
+	logger.Debugf("service monitor for the rgw metrics of object store %q was enabled successfully", c.store.Name)
+	return nil
+}
+
+func (c *clusterConfig) applyServiceMonitorSpec(serviceMonitor *monitoringv1.ServiceMonitor) {
+	serviceMonitor.SetName(instanceName(c.store.Name))
+	serviceMonitor.SetNamespace(c.store.Namespace)
+	serviceMonitor.SetLabels(controller.AppLabels(AppName, c.store.Namespace))
+	serviceMonitor.Labels["rook_object_store"] = c.store.Name
+	serviceMonitor.Spec.NamespaceSelector.MatchNames = []string{c.store.Namespace}
+	serviceMonitor.Spec.Selector.MatchLabels = controller.AppLabels(cephExporterAppName, c.store.Namespace)
+
+	// the exporter reports the rgw metrics with the name of the daemon, e.g. "rgw.my.store.a"
+	daemonName := strings.TrimPrefix(generateCephXUser(fmt.Sprintf("%s-%s-%s", AppName, c.store.Name, k8sutil.IndexToName(0))), "client.")
+	endpoint := &serviceMonitor.Spec.Endpoints[0]
+	endpoint.Port = exporterServiceMetricName
+	endpoint.MetricRelabelConfigs = []*monitoringv1.RelabelConfig{
+		{
+			SourceLabels: []monitoringv1.LabelName{"__name__"},
+			Regex:        "ceph_rgw_.*",
+			Action:       "keep",
+		},
+		{
+			SourceLabels: []monitoringv1.LabelName{"ceph_daemon"},
+			Regex:        fmt.Sprintf(`(client\.)?%s(\..*)?`, regexp.QuoteMeta(daemonName)),
+			Action:       "keep",
+		},
+		{
+			TargetLabel: "rook_object_store",
+			Replacement: c.store.Name,
+		},
+	}
+}
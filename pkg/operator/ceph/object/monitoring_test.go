@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"context"
+	"path"
+	"regexp"
+	"testing"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/util"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestApplyServiceMonitorSpec(t *testing.T) {
+	filePath := path.Join(util.PathToProjectRoot(), "/deploy/examples/monitoring", serviceMonitorFile)
+	serviceMonitor, err := k8sutil.GetServiceMonitor(filePath)
+	assert.NoError(t, err)
+
+	c := &clusterConfig{
+		store: &cephv1.CephObjectStore{ObjectMeta: metav1.ObjectMeta{Name: "my-store", Namespace: "ns"}},
+	}
+	c.applyServiceMonitorSpec(serviceMonitor)
+
+	assert.Equal(t, "rook-ceph-rgw-my-store", serviceMonitor.Name)
+	assert.Equal(t, "ns", serviceMonitor.Namespace)
+	assert.Equal(t, "my-store", serviceMonitor.Labels["rook_object_store"])
+	assert.Equal(t, []string{"ns"}, serviceMonitor.Spec.NamespaceSelector.MatchNames)
+	assert.Equal(t, map[string]string{"app": "rook-ceph-exporter", "rook_cluster": "ns"}, serviceMonitor.Spec.Selector.MatchLabels)
+
+	relabelings := serviceMonitor.Spec.Endpoints[0].MetricRelabelConfigs
+	assert.Len(t, relabelings, 3)
+	daemonRegex := regexp.MustCompile("^(?:" + relabelings[1].Regex + ")$")
+	assert.True(t, daemonRegex.MatchString("rgw.my.store.a"))
+	assert.True(t, daemonRegex.MatchString("client.rgw.my.store.a.12.94398234"))
+	assert.False(t, daemonRegex.MatchString("rgw.my.store.b"))
+	assert.False(t, daemonRegex.MatchString("rgw.my.storea"))
+	assert.Equal(t, "my-store", relabelings[2].Replacement)
+}
+
+func TestReconcileServiceMonitor(t *testing.T) {
+	createOrUpdateOrig, deleteOrig := createOrUpdateServiceMonitor, deleteServiceMonitor
+	defer func() { createOrUpdateServiceMonitor, deleteServiceMonitor = createOrUpdateOrig, deleteOrig }()
+	created, deleted := false, false
+	createOrUpdateServiceMonitor = func(ctx context.Context, sm *monitoringv1.ServiceMonitor) (*monitoringv1.ServiceMonitor, error) {
+		created = true
+		return sm, nil
+	}
+	deleteServiceMonitor = func(ctx context.Context, namespace, name string) error {
+		assert.Equal(t, "rook-ceph-rgw-my-store", name)
+		deleted = true
+		return nil
+	}
+
+	clusterInfo := client.AdminTestClusterInfo("ns")
+	clusterInfo.Context = context.TODO()
+	c := &clusterConfig{
+		clusterInfo: clusterInfo,
+		clusterSpec: &cephv1.ClusterSpec{},
+		store:       &cephv1.CephObjectStore{ObjectMeta: metav1.ObjectMeta{Name: "my-store", Namespace: "ns"}},
+	}
+
+	assert.NoError(t, c.reconcileServiceMonitor())
+	assert.True(t, deleted)
+	assert.False(t, created)
+}
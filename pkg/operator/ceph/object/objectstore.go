@@ -31,6 +31,7 @@ import (
 	"github.com/rook/rook/pkg/clusterd"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
 	"github.com/rook/rook/pkg/operator/ceph/config"
+	"github.com/rook/rook/pkg/operator/ceph/pool"
 	cephver "github.com/rook/rook/pkg/operator/ceph/version"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	"github.com/rook/rook/pkg/util"
@@ -85,8 +86,10 @@ type zoneGroupType struct {
 }
 
 type zoneType struct {
+	ID        string   `json:"id"`
 	Name      string   `json:"name"`
 	Endpoints []string `json:"endpoints"`
+	TierType  string   `json:"tier_type"`
 }
 
 type realmType struct {
@@ -106,6 +109,11 @@ func deleteRealmAndPools(objContext *Context, spec cephv1.ObjectStoreSpec) error
 
 		return nil
 	}
+	if spec.IsAdopted() {
+		// the realm and pools were not created by the store, so they are left for the gateways they were adopted from
+		logger.Infof("object store %q adopted zone %q. realm and pools not deleted", objContext.Name, objContext.Zone)
+		return nil
+	}
 
 	return deleteSingleSiteRealmAndPools(objContext, spec)
 }
@@ -196,6 +204,7 @@ func deleteSingleSiteRealmAndPools(objContext *Context, spec cephv1.ObjectStoreS
 		if err != nil {
 			return errors.Wrap(err, "failed to delete object store pools")
 		}
+		deletePlacementPools(objContext, spec.PlacementTargets)
 	} else {
 		logger.Infof("PreservePoolsOnDelete is set in object store %s. Pools not deleted", objContext.Name)
 	}
@@ -231,10 +240,29 @@ func getMultisiteForObjectStore(ctx context.Context, clusterdContext *clusterd.C
 
 		return realm.Name, zonegroup.Name, zone.Name, nil
 	}
+	if spec.IsAdopted() {
+		return spec.Adopt.Realm, spec.Adopt.ZoneGroup, spec.Adopt.Zone, nil
+	}
 
 	return name, name, name, nil
 }
 
+// getZoneGroupForObjectStore returns the CephObjectZoneGroup of a multisite object store, nil for the other stores
+func getZoneGroupForObjectStore(ctx context.Context, clusterdContext *clusterd.Context, spec *cephv1.ObjectStoreSpec, namespace string) (*cephv1.CephObjectZoneGroup, error) {
+	if !spec.IsMultisite() || spec.IsExternal() {
+		return nil, nil
+	}
+	zone, err := clusterdContext.RookClientset.CephV1().CephObjectZones(namespace).Get(ctx, spec.Zone.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find zone %q", spec.Zone.Name)
+	}
+	zoneGroup, err := clusterdContext.RookClientset.CephV1().CephObjectZoneGroups(namespace).Get(ctx, zone.Spec.ZoneGroup, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find zone group %q", zone.Spec.ZoneGroup)
+	}
+	return zoneGroup, nil
+}
+
 func CheckZoneIsMaster(objContext *Context) (bool, error) {
 	logger.Debugf("checking if zone %v is the master zone", objContext.Zone)
 	realmArg := fmt.Sprintf("--rgw-realm=%s", objContext.Realm)
@@ -392,11 +420,14 @@ func getZoneEndpoints(objContext *Context, serviceEndpoint string) ([]string, bo
 	return zoneEndpointsList, isEndpointAlreadyExists, nil
 }
 
-func createMultisite(objContext *Context, endpointArg string) error {
+// createMultisite creates the realm, zone group and zone of the object store if they do not exist yet, and
+// returns whether any of them was created
+func createMultisite(objContext *Context, endpointArg string) (bool, error) {
 	logger.Debugf("creating realm, zone group, zone for object-store %v", objContext.Name)
 
 	realmArg := fmt.Sprintf("--rgw-realm=%s", objContext.Realm)
 	zoneGroupArg := fmt.Sprintf("--rgw-zonegroup=%s", objContext.ZoneGroup)
+	created := false
 
 	// create the realm if it doesn't exist yet
 	output, err := RunAdminCommandNoMultisite(objContext, true, "realm", "get", realmArg)
@@ -405,11 +436,12 @@ func createMultisite(objContext *Context, endpointArg string) error {
 		if code, err := exec.ExtractExitCode(err); err == nil && code == int(syscall.ENOENT) {
 			output, err = RunAdminCommandNoMultisite(objContext, false, "realm", "create", realmArg)
 			if err != nil {
-				return errorOrIsNotFound(err, "failed to create ceph realm %q, for reason %q", objContext.ZoneGroup, output)
+				return false, errorOrIsNotFound(err, "failed to create ceph realm %q, for reason %q", objContext.ZoneGroup, output)
 			}
 			logger.Debugf("created realm %q", objContext.Realm)
+			created = true
 		} else {
-			return errorOrIsNotFound(err, "'radosgw-admin realm get' failed with code %d, for reason %q. %v", strconv.Itoa(code), output, string(kerrors.ReasonForError(err)))
+			return false, errorOrIsNotFound(err, "'radosgw-admin realm get' failed with code %d, for reason %q. %v", strconv.Itoa(code), output, string(kerrors.ReasonForError(err)))
 		}
 	}
 
@@ -420,11 +452,12 @@ func createMultisite(objContext *Context, endpointArg string) error {
 		if code, err := exec.ExtractExitCode(err); err == nil && code == int(syscall.ENOENT) {
 			output, err = RunAdminCommandNoMultisite(objContext, false, "zonegroup", "create", "--master", realmArg, zoneGroupArg, endpointArg)
 			if err != nil {
-				return errorOrIsNotFound(err, "failed to create ceph zone group %q, for reason %q", objContext.ZoneGroup, output)
+				return false, errorOrIsNotFound(err, "failed to create ceph zone group %q, for reason %q", objContext.ZoneGroup, output)
 			}
 			logger.Debugf("created zone group %q", objContext.ZoneGroup)
+			created = true
 		} else {
-			return errorOrIsNotFound(err, "'radosgw-admin zonegroup get' failed with code %d, for reason %q", strconv.Itoa(code), output)
+			return false, errorOrIsNotFound(err, "'radosgw-admin zonegroup get' failed with code %d, for reason %q", strconv.Itoa(code), output)
 		}
 	}
 
@@ -435,22 +468,23 @@ func createMultisite(objContext *Context, endpointArg string) error {
 		if code, err := exec.ExtractExitCode(err); err == nil && code == int(syscall.ENOENT) {
 			output, err = runAdminCommand(objContext, false, "zone", "create", "--master", endpointArg)
 			if err != nil {
-				return errorOrIsNotFound(err, "failed to create ceph zone %q, for reason %q", objContext.Zone, output)
+				return false, errorOrIsNotFound(err, "failed to create ceph zone %q, for reason %q", objContext.Zone, output)
 			}
 			logger.Debugf("created zone %q", objContext.Zone)
+			created = true
 		} else {
-			return errorOrIsNotFound(err, "'radosgw-admin zone get' failed with code %d, for reason %q", strconv.Itoa(code), output)
+			return false, errorOrIsNotFound(err, "'radosgw-admin zone get' failed with code %d, for reason %q", strconv.Itoa(code), output)
 		}
 	}
 
 	if err := commitConfigChanges(objContext); err != nil {
 		nsName := fmt.Sprintf("%s/%s", objContext.clusterInfo.Namespace, objContext.Name)
-		return errors.Wrapf(err, "failed to commit config changes after creating multisite config for CephObjectStore %q", nsName)
+		return false, errors.Wrapf(err, "failed to commit config changes after creating multisite config for CephObjectStore %q", nsName)
 	}
 
 	logger.Infof("Multisite for object-store: realm=%s, zonegroup=%s, zone=%s", objContext.Realm, objContext.ZoneGroup, objContext.Zone)
 
-	return nil
+	return created, nil
 }
 
 func JoinMultisite(objContext *Context, endpointArg, zoneEndpoints, namespace string) error {
@@ -537,14 +571,18 @@ func createSystemUser(objContext *Context, namespace string) error {
 	return nil
 }
 
-func setMultisite(objContext *Context, store *cephv1.CephObjectStore, zone *cephv1.CephObjectZone) error {
+// setMultisite configures the multisite of the object store, and returns whether the realm, zone group or
+// zone of a store that is not part of a multisite were created
+func setMultisite(objContext *Context, store *cephv1.CephObjectStore, zone *cephv1.CephObjectZone) (bool, error) {
 	logger.Debugf("setting multisite configuration for object-store %v", store.Name)
+	created := false
 
 	if store.Spec.IsMultisite() {
-		if zone != nil && len(zone.Spec.CustomEndpoints) == 0 {
+		// the zone controller manages the custom endpoints and the endpoints of the gateway pods
+		if zone != nil && len(zone.Spec.CustomEndpoints) == 0 && !zone.Spec.GatewayPodEndpoints {
 			zoneEndpointsList, isEndpointAlreadyExists, err := getZoneEndpoints(objContext, objContext.Endpoint)
 			if err != nil {
-				return err
+				return false, err
 			}
 
 			// If endpoint already exists in zone, no need to update
@@ -557,20 +595,21 @@ func setMultisite(objContext *Context, store *cephv1.CephObjectStore, zone *ceph
 
 				err = JoinMultisite(objContext, endpointArg, zoneEndpoints, store.Namespace)
 				if err != nil {
-					return errors.Wrapf(err, "failed join ceph multisite in zone %q", objContext.Zone)
+					return false, errors.Wrapf(err, "failed join ceph multisite in zone %q", objContext.Zone)
 				}
 			}
 		}
 	} else {
 		endpointArg := fmt.Sprintf("--endpoints=%s", objContext.Endpoint)
-		err := createMultisite(objContext, endpointArg)
+		var err error
+		created, err = createMultisite(objContext, endpointArg)
 		if err != nil {
-			return errorOrIsNotFound(err, "failed create ceph multisite for object-store %q", objContext.Name)
+			return false, errorOrIsNotFound(err, "failed create ceph multisite for object-store %q", objContext.Name)
 		}
 	}
 
 	logger.Infof("multisite configuration for object-store %v is complete", store.Name)
-	return nil
+	return created, nil
 }
 
 func deleteRealm(context *Context) error {
@@ -810,23 +849,31 @@ func createSimilarPools(ctx *Context, pools []string, clusterSpec *cephv1.Cluste
 
 func createRGWPool(ctx *Context, clusterSpec *cephv1.ClusterSpec, poolSpec cephv1.PoolSpec, pgCount, requestedName string) error {
 	// create the pool if it doesn't exist yet
-	pool := cephv1.NamedPoolSpec{
+	rgwPool := cephv1.NamedPoolSpec{
 		Name:     poolName(ctx.Name, requestedName),
 		PoolSpec: poolSpec,
 	}
-	if err := cephclient.CreatePoolWithPGs(ctx.Context, ctx.clusterInfo, clusterSpec, pool, AppName, pgCount); err != nil {
-		return errors.Wrapf(err, "failed to create pool %q", pool.Name)
+	if err := pool.ValidateNewPoolCapacity(ctx.Context, ctx.clusterInfo, rgwPool); err != nil {
+		return err
+	}
+	if err := cephclient.CreatePoolWithPGs(ctx.Context, ctx.clusterInfo, clusterSpec, rgwPool, AppName, pgCount); err != nil {
+		return errors.Wrapf(err, "failed to create pool %q", rgwPool.Name)
 	}
 	// Set the pg_num_min if not the default so the autoscaler won't immediately increase the pg count
 	if pgCount != cephclient.DefaultPGCount {
-		if err := cephclient.SetPoolProperty(ctx.Context, ctx.clusterInfo, pool.Name, "pg_num_min", pgCount); err != nil {
-			return errors.Wrapf(err, "failed to set pg_num_min on pool %q to %q", pool.Name, pgCount)
+		if err := cephclient.SetPoolProperty(ctx.Context, ctx.clusterInfo, rgwPool.Name, "pg_num_min", pgCount); err != nil {
+			return errors.Wrapf(err, "failed to set pg_num_min on pool %q to %q", rgwPool.Name, pgCount)
 		}
 	}
 
 	return nil
 }
 
+// DataPoolName returns the name of the data pool of the object store, which is not shared with other stores
+func DataPoolName(storeName string) string {
+	return poolName(storeName, dataPoolName)
+}
+
 func poolName(poolPrefix, poolName string) string {
 	if strings.HasPrefix(poolName, ".") {
 		return poolName
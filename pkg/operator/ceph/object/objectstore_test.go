@@ -95,11 +95,11 @@ func TestReconcileRealm(t *testing.T) {
 	objContext := NewContext(context, &client.ClusterInfo{Namespace: "mycluster"}, storeName)
 	// create the first realm, marked as default
 	store := cephv1.CephObjectStore{}
-	err := setMultisite(objContext, &store, nil)
+	_, err := setMultisite(objContext, &store, nil)
 	assert.Nil(t, err)
 
 	// create the second realm, not marked as default
-	err = setMultisite(objContext, &store, nil)
+	_, err = setMultisite(objContext, &store, nil)
 	assert.Nil(t, err)
 }
 
@@ -218,6 +218,15 @@ func deleteStore(t *testing.T, name string, existingStores string, expectedDelet
 	assert.Equal(t, expectedPoolsDeleted, poolsDeleted)
 	assert.Equal(t, expectedDeleteRootPool, deletedRootPool)
 	assert.Equal(t, true, deletedErasureCodeProfile)
+
+	// The realm and pools adopted by an object store are not deleted
+	realmDeleted = false
+	poolsDeleted = 0
+	spec = cephv1.ObjectStoreSpec{Adopt: &cephv1.ObjectStoreAdoptSpec{Realm: "default", ZoneGroup: "default", Zone: "default"}}
+	err = deleteRealmAndPools(context, spec)
+	assert.Nil(t, err)
+	assert.False(t, realmDeleted)
+	assert.Equal(t, 0, poolsDeleted)
 }
 
 func TestGetObjectBucketProvisioner(t *testing.T) {
@@ -652,7 +661,7 @@ func Test_createMultisite(t *testing.T) {
 			objContext := NewContext(ctx, &client.ClusterInfo{Namespace: "my-cluster"}, "my-store")
 
 			// assumption: endpointArg is sufficiently tested by integration tests
-			err := createMultisite(objContext, "")
+			created, err := createMultisite(objContext, "")
 			assert.Equal(t, tt.expectCommands.getRealm, calledGetRealm)
 			assert.Equal(t, tt.expectCommands.createRealm, calledCreateRealm)
 			assert.Equal(t, tt.expectCommands.getZoneGroup, calledGetZoneGroup)
@@ -664,6 +673,7 @@ func Test_createMultisite(t *testing.T) {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, tt.expectCommands.createRealm || tt.expectCommands.createZoneGroup || tt.expectCommands.createZone, created)
 			}
 		})
 	}
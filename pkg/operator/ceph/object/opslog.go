@@ -0,0 +1,77 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"path"
+
+	cephconfig "github.com/rook/rook/pkg/operator/ceph/config"
+	"github.com/rook/rook/pkg/operator/ceph/controller"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+)
+
+const (
+	opsLogContainerName = "ops-log"
+	opsLogVolumeName    = "rgw-ops-log"
+	opsLogDir           = "/var/run/rgw-ops-log"
+)
+
+var opsLogSocketPath = path.Join(opsLogDir, "ops-log.asok")
+
+// addOpsLogSidecar configures the rgw daemon to write the ops log to a socket, instead of the log pool of the zone,
+// and adds the sidecar printing the ops log of the socket to its stdout
+func (c *clusterConfig) addOpsLogSidecar(podSpec *v1.PodSpec) {
+	if !c.store.Spec.IsOpsLogEnabled() {
+		return
+	}
+	opsLog := c.store.Spec.Gateway.OpsLog
+
+	podSpec.Volumes = append(podSpec.Volumes, v1.Volume{
+		Name:         opsLogVolumeName,
+		VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{Medium: v1.StorageMediumMemory}},
+	})
+	mount := v1.VolumeMount{Name: opsLogVolumeName, MountPath: opsLogDir}
+
+	for i := range podSpec.Containers {
+		if podSpec.Containers[i].Name != "rgw" {
+			continue
+		}
+		podSpec.Containers[i].Args = append(podSpec.Containers[i].Args,
+			cephconfig.NewFlag("rgw enable ops log", "true"),
+			cephconfig.NewFlag("rgw ops log rados", "false"),
+			cephconfig.NewFlag("rgw ops log socket path", opsLogSocketPath),
+		)
+		podSpec.Containers[i].VolumeMounts = append(podSpec.Containers[i].VolumeMounts, mount)
+	}
+
+	// the sidecar runs as the user of the rgw daemon to connect to the socket, the rook image runs as another user
+	securityContext := controller.DaemonSecurityContext(c.clusterSpec, false)
+	if securityContext.RunAsUser == nil {
+		securityContext.RunAsUser = pointer.Int64(0)
+	}
+	podSpec.Containers = append(podSpec.Containers, v1.Container{
+		Name:            opsLogContainerName,
+		Image:           c.rookImage,
+		ImagePullPolicy: controller.GetContainerImagePullPolicy(c.clusterSpec.CephVersion.ImagePullPolicy),
+		Command:         []string{"rook"},
+		Args:            []string{"ceph", "rgw", "ops-log", "--socket", opsLogSocketPath, "--format", string(opsLog.GetFormat())},
+		VolumeMounts:    []v1.VolumeMount{mount},
+		Resources:       opsLog.Resources,
+		SecurityContext: securityContext,
+	})
+}
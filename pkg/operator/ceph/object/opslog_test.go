@@ -0,0 +1,95 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	clienttest "github.com/rook/rook/pkg/daemon/ceph/client/test"
+	cephconfig "github.com/rook/rook/pkg/operator/ceph/config"
+	"github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestOpsLogSidecar(t *testing.T) {
+	c := &clusterConfig{
+		store:       simpleStore(),
+		rookImage:   "rook/ceph:myversion",
+		clusterSpec: &cephv1.ClusterSpec{CephVersion: cephv1.CephVersionSpec{Image: "quay.io/ceph/ceph:v17"}},
+		clusterInfo: clienttest.CreateTestClusterInfo(1),
+		DataPathMap: cephconfig.NewStatelessDaemonDataPathMap(cephconfig.RgwType, "default", "rook-ceph", "/var/lib/rook/"),
+	}
+	rgwConfig := &rgwConfig{ResourceName: "rook-ceph-rgw-default", DaemonID: "default"}
+
+	t.Run("disabled", func(t *testing.T) {
+		podTemplate, err := c.makeRGWPodSpec(rgwConfig)
+		assert.NoError(t, err)
+		require.Len(t, podTemplate.Spec.Containers, 1)
+		assert.NotContains(t, podTemplate.Spec.Containers[0].Args, "--rgw-enable-ops-log=true")
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		c.store.Spec.Gateway.OpsLog = &cephv1.GatewayOpsLogSpec{
+			Enabled: true,
+			Format:  cephv1.OpsLogFormatCombined,
+			Resources: v1.ResourceRequirements{
+				Limits: v1.ResourceList{v1.ResourceMemory: resource.MustParse("64Mi")},
+			},
+		}
+		podTemplate, err := c.makeRGWPodSpec(rgwConfig)
+		assert.NoError(t, err)
+		require.Len(t, podTemplate.Spec.Containers, 2)
+
+		rgw := podTemplate.Spec.Containers[0]
+		assert.Contains(t, rgw.Args, "--rgw-enable-ops-log=true")
+		assert.Contains(t, rgw.Args, "--rgw-ops-log-rados=false")
+		assert.Contains(t, rgw.Args, "--rgw-ops-log-socket-path=/var/run/rgw-ops-log/ops-log.asok")
+		assert.Contains(t, rgw.VolumeMounts, v1.VolumeMount{Name: opsLogVolumeName, MountPath: opsLogDir})
+
+		sidecar := podTemplate.Spec.Containers[1]
+		assert.Equal(t, opsLogContainerName, sidecar.Name)
+		assert.Equal(t, "rook/ceph:myversion", sidecar.Image)
+		assert.Equal(t, []string{"ceph", "rgw", "ops-log", "--socket", "/var/run/rgw-ops-log/ops-log.asok", "--format", "combined"}, sidecar.Args)
+		assert.Equal(t, []v1.VolumeMount{{Name: opsLogVolumeName, MountPath: opsLogDir}}, sidecar.VolumeMounts)
+		assert.Equal(t, "64Mi", sidecar.Resources.Limits.Memory().String())
+		// the sidecar runs as the user of the rgw daemon
+		assert.Equal(t, int64(controller.CephUserID), *sidecar.SecurityContext.RunAsUser)
+		assert.Equal(t, rgw.SecurityContext.RunAsUser, sidecar.SecurityContext.RunAsUser)
+
+		found := false
+		for _, vol := range podTemplate.Spec.Volumes {
+			if vol.Name == opsLogVolumeName {
+				found = true
+				assert.Equal(t, v1.StorageMediumMemory, vol.EmptyDir.Medium)
+			}
+		}
+		assert.True(t, found)
+	})
+
+	t.Run("hardening disabled", func(t *testing.T) {
+		c.clusterSpec.Security.DaemonHardening.Disabled = true
+		podTemplate, err := c.makeRGWPodSpec(rgwConfig)
+		assert.NoError(t, err)
+		require.Len(t, podTemplate.Spec.Containers, 2)
+		// the sidecar does not run as the user of the rook image
+		assert.Equal(t, int64(0), *podTemplate.Spec.Containers[1].SecurityContext.RunAsUser)
+	})
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+)
+
+const (
+	standardStorageClass = "STANDARD"
+	indexPoolName        = "rgw.buckets.index"
+	dataExtraPoolName    = "rgw.buckets.non-ec"
+)
+
+// placementPool is the data pool of a storage class of a placement target
+type placementPool struct {
+	placementTarget string
+	storageClass    string
+	// the name of the pool without the prefix of the store
+	name string
+	spec cephv1.PoolSpec
+}
+
+// placementPools returns the data pools of the storage classes of the placement targets. The STANDARD storage class
+// of the default placement target is backed by the data pool of the store and is not returned.
+func placementPools(targets []cephv1.PlacementTargetSpec) []placementPool {
+	pools := []placementPool{}
+	for _, target := range targets {
+		if target.Name != defaultPlacementTarget {
+			pools = append(pools, placementPool{
+				placementTarget: target.Name,
+				storageClass:    standardStorageClass,
+				name:            fmt.Sprintf("rgw.%s.data", target.Name),
+				spec:            target.DataPool,
+			})
+		}
+		for _, class := range target.StorageClasses {
+			pools = append(pools, placementPool{
+				placementTarget: target.Name,
+				storageClass:    class.Name,
+				name:            fmt.Sprintf("rgw.%s.%s.data", target.Name, class.Name),
+				spec:            class.DataPool,
+			})
+		}
+	}
+	return pools
+}
+
+// configurePlacementTargets creates the data pools of the placement targets of the object store, adds the
+// placement targets and their storage classes to the zonegroup and the zone, and commits the period. The bucket
+// indexes and the incomplete multipart uploads of all the placement targets are kept in the metadata pools of the
+// store. Placement targets and storage classes removed from the spec are left in the zone since buckets may still
+// be placed in them.
+func configurePlacementTargets(objContext *Context, clusterSpec *cephv1.ClusterSpec, store *cephv1.CephObjectStore) error {
+	if len(store.Spec.PlacementTargets) == 0 {
+		return nil
+	}
+
+	pools := placementPools(store.Spec.PlacementTargets)
+	for _, pool := range pools {
+		if err := createRGWPool(objContext, clusterSpec, pool.spec, cephclient.DefaultPGCount, pool.name); err != nil {
+			return errors.Wrapf(err, "failed to create the data pool of storage class %q of placement target %q", pool.storageClass, pool.placementTarget)
+		}
+	}
+
+	for _, target := range store.Spec.PlacementTargets {
+		if target.Name == defaultPlacementTarget {
+			continue
+		}
+		args := []string{"zonegroup", "placement", "add", fmt.Sprintf("--placement-id=%s", target.Name)}
+		if output, err := runAdminCommand(objContext, false, args...); err != nil {
+			return errorOrIsNotFound(err, "failed to add placement target %q to the zonegroup. %s", target.Name, output)
+		}
+	}
+
+	for _, pool := range pools {
+		if pool.storageClass != standardStorageClass {
+			args := []string{"zonegroup", "placement", "add",
+				fmt.Sprintf("--placement-id=%s", pool.placementTarget),
+				fmt.Sprintf("--storage-class=%s", pool.storageClass),
+			}
+			if output, err := runAdminCommand(objContext, false, args...); err != nil {
+				return errorOrIsNotFound(err, "failed to add storage class %q of placement target %q to the zonegroup. %s", pool.storageClass, pool.placementTarget, output)
+			}
+		}
+
+		// adding an existing storage class to the zone only updates its pools
+		args := []string{"zone", "placement", "add",
+			fmt.Sprintf("--placement-id=%s", pool.placementTarget),
+			fmt.Sprintf("--storage-class=%s", pool.storageClass),
+			fmt.Sprintf("--data-pool=%s", poolName(objContext.Name, pool.name)),
+			fmt.Sprintf("--index-pool=%s", poolName(objContext.Name, indexPoolName)),
+			fmt.Sprintf("--data-extra-pool=%s", poolName(objContext.Name, dataExtraPoolName)),
+		}
+		if output, err := runAdminCommand(objContext, false, args...); err != nil {
+			return errorOrIsNotFound(err, "failed to add storage class %q of placement target %q to the zone. %s", pool.storageClass, pool.placementTarget, output)
+		}
+		logger.Debugf("configured storage class %q of placement target %q", pool.storageClass, pool.placementTarget)
+	}
+
+	if err := commitConfigChanges(objContext); err != nil {
+		nsName := fmt.Sprintf("%s/%s", objContext.clusterInfo.Namespace, objContext.Name)
+		return errors.Wrapf(err, "failed to commit config changes after configuring placement targets for CephObjectStore %q", nsName)
+	}
+
+	logger.Infof("configured placement targets for object store %q", store.Name)
+	return nil
+}
+
+// deletePlacementPools deletes the data pools of the placement targets of the store and their erasure code profiles
+func deletePlacementPools(objContext *Context, targets []cephv1.PlacementTargetSpec) {
+	for _, pool := range placementPools(targets) {
+		name := poolName(objContext.Name, pool.name)
+		if err := cephclient.DeletePool(objContext.Context, objContext.clusterInfo, name); err != nil {
+			logger.Warningf("failed to delete pool %q. %v", name, err)
+			continue
+		}
+		if pool.spec.IsErasureCoded() {
+			if err := cephclient.DeleteErasureCodeProfile(objContext.Context, objContext.clusterInfo, cephclient.GetErasureCodeProfileForPool(name)); err != nil {
+				logger.Warningf("failed to delete erasure code profile of pool %q. %v", name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPlacementPools(t *testing.T) {
+	pool := cephv1.PoolSpec{Replicated: cephv1.ReplicatedSpec{Size: 3}}
+	pools := placementPools([]cephv1.PlacementTargetSpec{
+		{Name: "default-placement", StorageClasses: []cephv1.PlacementStorageClassSpec{{Name: "STANDARD_IA", DataPool: pool}}},
+		{Name: "archive", DataPool: pool, StorageClasses: []cephv1.PlacementStorageClassSpec{{Name: "COLD", DataPool: pool}}},
+	})
+	assert.Len(t, pools, 3)
+	assert.Equal(t, "rgw.default-placement.STANDARD_IA.data", pools[0].name)
+	assert.Equal(t, "rgw.archive.data", pools[1].name)
+	assert.Equal(t, "STANDARD", pools[1].storageClass)
+	assert.Equal(t, "rgw.archive.COLD.data", pools[2].name)
+}
+
+func TestConfigurePlacementTargets(t *testing.T) {
+	commitConfigChangesOrig := commitConfigChanges
+	defer func() { commitConfigChanges = commitConfigChangesOrig }()
+	committed := false
+	commitConfigChanges = func(c *Context) error {
+		committed = true
+		return nil
+	}
+
+	adminCommands := []string{}
+	reconciledPools := map[string]bool{}
+	mockCommand := func(command string, args ...string) (string, error) {
+		if command == "radosgw-admin" {
+			adminCommands = append(adminCommands, strings.Join(args, " "))
+		}
+		if len(args) > 3 && args[0] == "osd" && args[1] == "pool" && args[2] == "get" {
+			reconciledPools[args[3]] = true
+		}
+		return "", nil
+	}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			return mockCommand(command, args...)
+		},
+		MockExecuteCommandWithOutput: mockCommand,
+	}
+	clusterInfo := client.AdminTestClusterInfo("rook-ceph")
+	clusterInfo.Context = context.TODO()
+	objContext := NewContext(&clusterd.Context{Executor: executor}, clusterInfo, "my-store")
+	objContext.Realm = "my-store"
+	objContext.ZoneGroup = "my-store"
+	objContext.Zone = "my-store"
+	store := &cephv1.CephObjectStore{ObjectMeta: metav1.ObjectMeta{Name: "my-store", Namespace: "rook-ceph"}}
+
+	t.Run("no placement targets", func(t *testing.T) {
+		err := configurePlacementTargets(objContext, &cephv1.ClusterSpec{}, store)
+		assert.NoError(t, err)
+		assert.Empty(t, adminCommands)
+		assert.False(t, committed)
+	})
+
+	t.Run("placement targets are configured", func(t *testing.T) {
+		pool := cephv1.PoolSpec{Replicated: cephv1.ReplicatedSpec{Size: 1}}
+		store.Spec.PlacementTargets = []cephv1.PlacementTargetSpec{
+			{Name: "default-placement", StorageClasses: []cephv1.PlacementStorageClassSpec{{Name: "STANDARD_IA", DataPool: pool}}},
+			{Name: "archive", DataPool: pool},
+		}
+		err := configurePlacementTargets(objContext, &cephv1.ClusterSpec{AllowUnsafePools: true}, store)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]bool{"my-store.rgw.default-placement.STANDARD_IA.data": true, "my-store.rgw.archive.data": true}, reconciledPools)
+		assert.Len(t, adminCommands, 4)
+		assert.Contains(t, adminCommands[0], "zonegroup placement add --placement-id=archive --rgw-realm=my-store")
+		assert.Contains(t, adminCommands[1], "zonegroup placement add --placement-id=default-placement --storage-class=STANDARD_IA")
+		assert.Contains(t, adminCommands[2], "zone placement add --placement-id=default-placement --storage-class=STANDARD_IA --data-pool=my-store.rgw.default-placement.STANDARD_IA.data --index-pool=my-store.rgw.buckets.index --data-extra-pool=my-store.rgw.buckets.non-ec")
+		assert.Contains(t, adminCommands[3], "zone placement add --placement-id=archive --storage-class=STANDARD --data-pool=my-store.rgw.archive.data")
+		assert.Contains(t, adminCommands[3], "--rgw-zone=my-store")
+		assert.True(t, committed)
+	})
+}
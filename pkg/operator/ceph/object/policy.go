@@ -18,6 +18,7 @@ package object
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/service/s3"
 	"k8s.io/apimachinery/pkg/util/json"
@@ -112,6 +113,28 @@ var AllowedActions = []action{
 	RestoreObject,
 }
 
+// ReadActions are the actions to list and read the objects of a bucket
+var ReadActions = []action{
+	GetBucketLocation,
+	GetBucketVersioning,
+	GetObject,
+	GetObjectAcl,
+	GetObjectVersion,
+	GetObjectVersionAcl,
+	ListBucket,
+	ListBucketMultiPartUploads,
+	ListBucketVersions,
+}
+
+// WriteActions are the actions to list, read, write and delete the objects of a bucket
+var WriteActions = append(append([]action{}, ReadActions...),
+	AbortMultipartUpload,
+	DeleteObject,
+	DeleteObjectVersion,
+	ListMultipartUploadParts,
+	PutObject,
+)
+
 type effect string
 
 // effectAllow and effectDeny values are expected by the S3 API to be 'Allow' or 'Deny' explicitly
@@ -195,6 +218,39 @@ func (s *S3Agent) GetBucketPolicy(bucket string) (*BucketPolicy, error) {
 	return policy, nil
 }
 
+// PutBucketPolicyDocument sets the bucket policy to the given JSON policy document as is
+func (s *S3Agent) PutBucketPolicyDocument(bucket, policy string) error {
+	confirmRemoveSelfBucketAccess := false
+	_, err := s.Client.PutBucketPolicy(&s3.PutBucketPolicyInput{
+		Bucket:                        &bucket,
+		ConfirmRemoveSelfBucketAccess: &confirmRemoveSelfBucketAccess,
+		Policy:                        &policy,
+	})
+	return err
+}
+
+// GetBucketPolicyDocument returns the JSON policy document of the bucket
+func (s *S3Agent) GetBucketPolicyDocument(bucket string) (string, error) {
+	out, err := s.Client.GetBucketPolicy(&s3.GetBucketPolicyInput{
+		Bucket: &bucket,
+	})
+	if err != nil {
+		return "", err
+	}
+	if out.Policy == nil {
+		return "", nil
+	}
+	return *out.Policy, nil
+}
+
+// DeleteBucketPolicy removes the policy of the bucket
+func (s *S3Agent) DeleteBucketPolicy(bucket string) error {
+	_, err := s.Client.DeleteBucketPolicy(&s3.DeleteBucketPolicyInput{
+		Bucket: &bucket,
+	})
+	return err
+}
+
 // ModifyBucketPolicy new and old statement SIDs and overwrites on a match.
 // This allows users to Get, modify, and Replace existing statements as well as
 // add new ones.
@@ -204,6 +260,7 @@ func (bp *BucketPolicy) ModifyBucketPolicy(ps ...PolicyStatement) *BucketPolicy
 		for j, oldP := range bp.Statement {
 			if newP.Sid == oldP.Sid {
 				bp.Statement[j] = newP
+				match = true
 			}
 		}
 		if !match {
@@ -254,12 +311,18 @@ func (ps *PolicyStatement) WithSID(sid string) *PolicyStatement {
 
 const awsPrinciple = "AWS"
 const arnPrefixPrinciple = "arn:aws:iam:::user/%s"
+const arnPrefixTenantPrinciple = "arn:aws:iam::%s:user/%s"
 const arnPrefixResource = "arn:aws:s3:::%s"
 
 // ForPrincipals adds users to the PolicyStatement
 func (ps *PolicyStatement) ForPrincipals(users ...string) *PolicyStatement {
 	principals := ps.Principal[awsPrinciple]
 	for _, u := range users {
+		// the users of a tenant are identified as "<tenant>$<user>"
+		if tenant, user, found := strings.Cut(u, "$"); found {
+			principals = append(principals, fmt.Sprintf(arnPrefixTenantPrinciple, tenant, user))
+			continue
+		}
 		principals = append(principals, fmt.Sprintf(arnPrefixPrinciple, u))
 	}
 	ps.Principal[awsPrinciple] = principals
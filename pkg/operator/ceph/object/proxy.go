@@ -0,0 +1,112 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	rgwRemoteAddrParamOption = "rgw_remote_addr_param"
+)
+
+// remoteAddrParam returns the rgw_remote_addr_param of the HTTP header, which rgw reads as a CGI variable,
+// e.g. HTTP_X_FORWARDED_FOR for the X-Forwarded-For header
+func remoteAddrParam(header string) string {
+	return "HTTP_" + strings.ToUpper(strings.ReplaceAll(header, "-", "_"))
+}
+
+// proxyConfigOptions returns the config options of the proxy settings of the gateway
+func proxyConfigOptions(proxy *cephv1.GatewayProxySpec) map[string]string {
+	options := map[string]string{}
+	if proxy != nil && proxy.RemoteAddrHeader != "" {
+		options[rgwRemoteAddrParamOption] = remoteAddrParam(proxy.RemoteAddrHeader)
+	}
+	return options
+}
+
+func trustedProxiesPolicyName(storeName string) string {
+	return fmt.Sprintf("%s-%s-trusted-proxies", AppName, storeName)
+}
+
+// reconcileTrustedProxies restricts the ingress to the rgw pods from outside of the pod network to the trusted proxies
+// of the gateway spec with a network policy, since rgw trusts the remote addr header of any client. The pods of all
+// the namespaces keep their access to the gateways, so that the in-cluster clients are not cut off. The policy is
+// removed when there are no trusted proxies.
+func (c *clusterConfig) reconcileTrustedProxies() error {
+	name := trustedProxiesPolicyName(c.store.Name)
+	policies := c.context.Clientset.NetworkingV1().NetworkPolicies(c.store.Namespace)
+
+	proxy := c.store.Spec.Gateway.Proxy
+	if proxy == nil || len(proxy.TrustedProxies) == 0 {
+		err := policies.Delete(c.clusterInfo.Context, name, metav1.DeleteOptions{})
+		if err != nil && !kerrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete trusted proxies network policy %q", name)
+		}
+		return nil
+	}
+	if c.store.Spec.IsHostNetwork(c.clusterSpec) {
+		return errors.New("trusted proxies cannot be enforced on gateways with host networking, which are not subject to network policies")
+	}
+
+	peers := []networkingv1.NetworkPolicyPeer{}
+	for _, cidr := range proxy.TrustedProxies {
+		peers = append(peers, networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: cidr}})
+	}
+	// the empty namespace selector selects the pods of all the namespaces
+	peers = append(peers, networkingv1.NetworkPolicyPeer{NamespaceSelector: &metav1.LabelSelector{}})
+
+	policy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: c.store.Namespace,
+			Labels:    getLabels(c.store.Name, c.store.Namespace, false),
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: getLabels(c.store.Name, c.store.Namespace, false)},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress:     []networkingv1.NetworkPolicyIngressRule{{From: peers}},
+		},
+	}
+	if err := c.ownerInfo.SetControllerReference(policy); err != nil {
+		return errors.Wrapf(err, "failed to set owner reference to trusted proxies network policy %q", name)
+	}
+
+	current, err := policies.Get(c.clusterInfo.Context, name, metav1.GetOptions{})
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get trusted proxies network policy %q", name)
+		}
+		if _, err := policies.Create(c.clusterInfo.Context, policy, metav1.CreateOptions{}); err != nil {
+			return errors.Wrapf(err, "failed to create trusted proxies network policy %q", name)
+		}
+		logger.Infof("restricted the ingress of the gateways of object store %q to the trusted proxies %v", c.store.Name, proxy.TrustedProxies)
+		return nil
+	}
+	policy.ResourceVersion = current.ResourceVersion
+	if _, err := policies.Update(c.clusterInfo.Context, policy, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrapf(err, "failed to update trusted proxies network policy %q", name)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRemoteAddrParam(t *testing.T) {
+	assert.Equal(t, "HTTP_X_FORWARDED_FOR", remoteAddrParam("X-Forwarded-For"))
+	assert.Equal(t, "HTTP_X_REAL_IP", remoteAddrParam("x-real-ip"))
+}
+
+func TestSetGatewayConfigRemoteAddrHeader(t *testing.T) {
+	commands := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			commands = append(commands, strings.Join(args, " "))
+			if args[0] == "config" && args[1] == "get" && strings.HasPrefix(args[3], "rgw_") {
+				return "", nil
+			}
+			if args[0] == "config" && args[1] == "get" {
+				return `{"rgw_remote_addr_param":{"value":"HTTP_X_FORWARDED_FOR","section":"client.rgw.my.store.a","mask":{},"can_update_at_runtime":false}}`, nil
+			}
+			return "", nil
+		},
+	}
+	c := newConfig(t)
+	c.context.Executor = executor
+	c.clusterInfo = cephclient.AdminTestClusterInfo("rook-ceph")
+	c.store.Spec.Gateway.Proxy = &cephv1.GatewayProxySpec{RemoteAddrHeader: "X-Forwarded-For"}
+
+	assert.NoError(t, c.setGatewayConfigMonConfigStore(&rgwConfig{ResourceName: "rook-ceph-rgw-my-store-a"}))
+	all := strings.Join(commands, ";")
+	assert.Contains(t, all, "config set client.rgw.my.store.a rgw_remote_addr_param HTTP_X_FORWARDED_FOR")
+	assert.NotContains(t, all, "config rm")
+
+	// the option is removed with the header
+	commands = []string{}
+	c.store.Spec.Gateway.Proxy = nil
+	assert.NoError(t, c.setGatewayConfigMonConfigStore(&rgwConfig{ResourceName: "rook-ceph-rgw-my-store-a"}))
+	assert.Contains(t, strings.Join(commands, ";"), "config rm client.rgw.my.store.a rgw_remote_addr_param")
+}
+
+func TestReconcileTrustedProxies(t *testing.T) {
+	c := newConfig(t)
+	c.clusterInfo = cephclient.AdminTestClusterInfo("rook-ceph")
+	c.ownerInfo = cephclient.NewMinimumOwnerInfoWithOwnerRef()
+	c.store.Name = "my-store"
+	c.store.Namespace = "rook-ceph"
+	policies := c.context.Clientset.NetworkingV1().NetworkPolicies("rook-ceph")
+
+	// no policy without trusted proxies
+	assert.NoError(t, c.reconcileTrustedProxies())
+	_, err := policies.Get(context.TODO(), "rook-ceph-rgw-my-store-trusted-proxies", metav1.GetOptions{})
+	assert.True(t, kerrors.IsNotFound(err))
+
+	c.store.Spec.Gateway.Proxy = &cephv1.GatewayProxySpec{TrustedProxies: []string{"10.0.0.0/8"}}
+	assert.NoError(t, c.reconcileTrustedProxies())
+	policy, err := policies.Get(context.TODO(), "rook-ceph-rgw-my-store-trusted-proxies", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "my-store", policy.Spec.PodSelector.MatchLabels["rook_object_store"])
+	peers := policy.Spec.Ingress[0].From
+	assert.Len(t, peers, 2)
+	assert.Equal(t, "10.0.0.0/8", peers[0].IPBlock.CIDR)
+	// the pods of all the namespaces keep their access
+	assert.NotNil(t, peers[1].NamespaceSelector)
+	assert.Empty(t, peers[1].NamespaceSelector.MatchLabels)
+
+	// the policy is updated with the proxies
+	c.store.Spec.Gateway.Proxy.TrustedProxies = []string{"10.0.0.0/8", "192.168.0.0/16"}
+	assert.NoError(t, c.reconcileTrustedProxies())
+	policy, err = policies.Get(context.TODO(), "rook-ceph-rgw-my-store-trusted-proxies", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, policy.Spec.Ingress[0].From, 3)
+
+	// host networking is not subject to network policies
+	c.clusterSpec.Network.HostNetwork = true
+	assert.Error(t, c.reconcileTrustedProxies())
+	c.clusterSpec.Network.HostNetwork = false
+
+	// the policy is removed without trusted proxies
+	c.store.Spec.Gateway.Proxy.TrustedProxies = nil
+	assert.NoError(t, c.reconcileTrustedProxies())
+	_, err = policies.Get(context.TODO(), "rook-ceph-rgw-my-store-trusted-proxies", metav1.GetOptions{})
+	assert.True(t, kerrors.IsNotFound(err))
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+)
+
+const (
+	// RateLimitScopeUser is the scope of the rate limits of the users
+	RateLimitScopeUser = "user"
+	// RateLimitScopeBucket is the scope of the rate limits of the buckets
+	RateLimitScopeBucket = "bucket"
+)
+
+// RateLimit is the rate limit of the requests of a user or a bucket on each gateway. The limits are per minute, and
+// a limit of 0 is unlimited.
+type RateLimit struct {
+	MaxReadOps    int64 `json:"max_read_ops"`
+	MaxWriteOps   int64 `json:"max_write_ops"`
+	MaxReadBytes  int64 `json:"max_read_bytes"`
+	MaxWriteBytes int64 `json:"max_write_bytes"`
+	Enabled       bool  `json:"enabled"`
+}
+
+// RateLimitFromSpec returns the rate limit of the spec, or nil if the spec has no rate limit
+func RateLimitFromSpec(spec *cephv1.ObjectRateLimitSpec) *RateLimit {
+	if spec == nil {
+		return nil
+	}
+	limit := &RateLimit{}
+	if spec.MaxReadOps != nil {
+		limit.MaxReadOps = *spec.MaxReadOps
+	}
+	if spec.MaxWriteOps != nil {
+		limit.MaxWriteOps = *spec.MaxWriteOps
+	}
+	if spec.MaxReadBytes != nil {
+		limit.MaxReadBytes = spec.MaxReadBytes.Value()
+	}
+	if spec.MaxWriteBytes != nil {
+		limit.MaxWriteBytes = spec.MaxWriteBytes.Value()
+	}
+	return limit
+}
+
+func rateLimitArgs(scope, name string) []string {
+	target := fmt.Sprintf("--uid=%s", name)
+	if scope == RateLimitScopeBucket {
+		target = fmt.Sprintf("--bucket=%s", name)
+	}
+	return []string{fmt.Sprintf("--ratelimit-scope=%s", scope), target}
+}
+
+func getRateLimit(c *Context, scope, name string) (*RateLimit, error) {
+	output, err := runAdminCommand(c, true, append([]string{"ratelimit", "get"}, rateLimitArgs(scope, name)...)...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the rate limit of %s %q", scope, name)
+	}
+	return parseRateLimit(output, scope, fmt.Sprintf("%s %q", scope, name))
+}
+
+// parseRateLimit parses the rate limit of the scope in the output of "ratelimit get" or "global ratelimit get"
+func parseRateLimit(output, scope, target string) (*RateLimit, error) {
+	var reply map[string]RateLimit
+	if err := json.Unmarshal([]byte(output), &reply); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the rate limit of %s. %s", target, output)
+	}
+	limit, ok := reply[scope+"_ratelimit"]
+	if !ok {
+		return nil, errors.Errorf("no rate limit of %s in %s", target, output)
+	}
+	return &limit, nil
+}
+
+func rateLimitChanged(current, limit *RateLimit) bool {
+	return current.MaxReadOps != limit.MaxReadOps || current.MaxWriteOps != limit.MaxWriteOps ||
+		current.MaxReadBytes != limit.MaxReadBytes || current.MaxWriteBytes != limit.MaxWriteBytes
+}
+
+func rateLimitValueArgs(limit *RateLimit) []string {
+	return []string{
+		fmt.Sprintf("--max-read-ops=%d", limit.MaxReadOps),
+		fmt.Sprintf("--max-write-ops=%d", limit.MaxWriteOps),
+		fmt.Sprintf("--max-read-bytes=%d", limit.MaxReadBytes),
+		fmt.Sprintf("--max-write-bytes=%d", limit.MaxWriteBytes),
+	}
+}
+
+// SetRateLimit sets the rate limit of a user or a bucket with radosgw-admin, since the admin ops api does not manage
+// the rate limits. The rate limit is disabled when the limit is nil.
+func SetRateLimit(c *Context, scope, name string, limit *RateLimit) error {
+	current, err := getRateLimit(c, scope, name)
+	if err != nil {
+		return err
+	}
+
+	if limit == nil {
+		if !current.Enabled {
+			return nil
+		}
+		if _, err := runAdminCommand(c, false, append([]string{"ratelimit", "disable"}, rateLimitArgs(scope, name)...)...); err != nil {
+			return errors.Wrapf(err, "failed to disable the rate limit of %s %q", scope, name)
+		}
+		logger.Infof("disabled the rate limit of %s %q", scope, name)
+		return nil
+	}
+
+	if rateLimitChanged(current, limit) {
+		args := append([]string{"ratelimit", "set"}, rateLimitArgs(scope, name)...)
+		args = append(args, rateLimitValueArgs(limit)...)
+		if _, err := runAdminCommand(c, false, args...); err != nil {
+			return errors.Wrapf(err, "failed to set the rate limit of %s %q", scope, name)
+		}
+		logger.Infof("set the rate limit of %s %q to %d read ops, %d write ops, %d read bytes and %d write bytes per minute",
+			scope, name, limit.MaxReadOps, limit.MaxWriteOps, limit.MaxReadBytes, limit.MaxWriteBytes)
+	}
+	if !current.Enabled {
+		if _, err := runAdminCommand(c, false, append([]string{"ratelimit", "enable"}, rateLimitArgs(scope, name)...)...); err != nil {
+			return errors.Wrapf(err, "failed to enable the rate limit of %s %q", scope, name)
+		}
+		logger.Infof("enabled the rate limit of %s %q", scope, name)
+	}
+	return nil
+}
+
+// SetGlobalRateLimit sets the default rate limit of the users or the buckets of the realm. The global rate limits are
+// stored in the period, so the caller must commit the period when they changed. A nil limit is left unchanged.
+func SetGlobalRateLimit(c *Context, realm, scope string, limit *RateLimit) (bool, error) {
+	if limit == nil {
+		return false, nil
+	}
+	realmArg := fmt.Sprintf("--rgw-realm=%s", realm)
+	scopeArg := fmt.Sprintf("--ratelimit-scope=%s", scope)
+	target := fmt.Sprintf("the %ss of realm %q", scope, realm)
+
+	output, err := RunAdminCommandNoMultisite(c, true, "global", "ratelimit", "get", realmArg)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get the global rate limits of realm %q", realm)
+	}
+	current, err := parseRateLimit(output, scope, target)
+	if err != nil {
+		return false, err
+	}
+
+	changed := false
+	if rateLimitChanged(current, limit) {
+		args := append([]string{"global", "ratelimit", "set", scopeArg}, rateLimitValueArgs(limit)...)
+		args = append(args, realmArg)
+		if _, err := RunAdminCommandNoMultisite(c, false, args...); err != nil {
+			return false, errors.Wrapf(err, "failed to set the rate limit of %s", target)
+		}
+		logger.Infof("set the rate limit of %s to %d read ops, %d write ops, %d read bytes and %d write bytes per minute",
+			target, limit.MaxReadOps, limit.MaxWriteOps, limit.MaxReadBytes, limit.MaxWriteBytes)
+		changed = true
+	}
+	if !current.Enabled {
+		if _, err := RunAdminCommandNoMultisite(c, false, "global", "ratelimit", "enable", scopeArg, realmArg); err != nil {
+			return false, errors.Wrapf(err, "failed to enable the rate limit of %s", target)
+		}
+		logger.Infof("enabled the rate limit of %s", target)
+		changed = true
+	}
+	return changed, nil
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestRateLimitFromSpec(t *testing.T) {
+	assert.Nil(t, RateLimitFromSpec(nil))
+
+	ops := int64(100)
+	bytes := resource.MustParse("1Mi")
+	limit := RateLimitFromSpec(&cephv1.ObjectRateLimitSpec{MaxWriteOps: &ops, MaxReadBytes: &bytes})
+	assert.Equal(t, &RateLimit{MaxWriteOps: 100, MaxReadBytes: 1048576}, limit)
+}
+
+func TestSetRateLimit(t *testing.T) {
+	var current string
+	commands := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			// the command without the zone and connection flags
+			for i, arg := range args {
+				if strings.HasPrefix(arg, "--rgw-realm") {
+					commands = append(commands, strings.Join(args[:i], " "))
+					break
+				}
+			}
+			if args[0] == "ratelimit" && args[1] == "get" {
+				return current, nil
+			}
+			return "", nil
+		},
+	}
+	c := NewContext(&clusterd.Context{Executor: executor}, cephclient.AdminTestClusterInfo("rook-ceph"), "my-store")
+	disabled := `{"user_ratelimit":{"max_read_ops":0,"max_write_ops":0,"max_read_bytes":0,"max_write_bytes":0,"enabled":false}}`
+	enabled := `{"bucket_ratelimit":{"max_read_ops":100,"max_write_ops":0,"max_read_bytes":0,"max_write_bytes":1024,"enabled":true}}`
+
+	t.Run("no rate limit", func(t *testing.T) {
+		current, commands = disabled, []string{}
+		assert.NoError(t, SetRateLimit(c, RateLimitScopeUser, "my-user", nil))
+		assert.Equal(t, []string{"ratelimit get --ratelimit-scope=user --uid=my-user"}, commands)
+	})
+
+	t.Run("new rate limit", func(t *testing.T) {
+		current, commands = disabled, []string{}
+		assert.NoError(t, SetRateLimit(c, RateLimitScopeUser, "my-user", &RateLimit{MaxReadOps: 100}))
+		assert.Equal(t, []string{
+			"ratelimit get --ratelimit-scope=user --uid=my-user",
+			"ratelimit set --ratelimit-scope=user --uid=my-user --max-read-ops=100 --max-write-ops=0 --max-read-bytes=0 --max-write-bytes=0",
+			"ratelimit enable --ratelimit-scope=user --uid=my-user",
+		}, commands)
+	})
+
+	t.Run("unchanged rate limit", func(t *testing.T) {
+		current, commands = enabled, []string{}
+		assert.NoError(t, SetRateLimit(c, RateLimitScopeBucket, "my-bucket", &RateLimit{MaxReadOps: 100, MaxWriteBytes: 1024}))
+		assert.Equal(t, []string{"ratelimit get --ratelimit-scope=bucket --bucket=my-bucket"}, commands)
+	})
+
+	t.Run("removed rate limit", func(t *testing.T) {
+		current, commands = enabled, []string{}
+		assert.NoError(t, SetRateLimit(c, RateLimitScopeBucket, "my-bucket", nil))
+		assert.Equal(t, []string{
+			"ratelimit get --ratelimit-scope=bucket --bucket=my-bucket",
+			"ratelimit disable --ratelimit-scope=bucket --bucket=my-bucket",
+		}, commands)
+	})
+
+	t.Run("rate limit of another scope", func(t *testing.T) {
+		current, commands = enabled, []string{}
+		assert.Error(t, SetRateLimit(c, RateLimitScopeUser, "my-user", nil))
+	})
+}
+
+func TestSetGlobalRateLimit(t *testing.T) {
+	var current string
+	commands := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			// the command without the realm and connection flags
+			for i, arg := range args {
+				if strings.HasPrefix(arg, "--rgw-realm") {
+					commands = append(commands, strings.Join(args[:i], " "))
+					break
+				}
+			}
+			if args[0] == "global" && args[2] == "get" {
+				return current, nil
+			}
+			return "", nil
+		},
+	}
+	c := NewContext(&clusterd.Context{Executor: executor}, cephclient.AdminTestClusterInfo("rook-ceph"), "my-zonegroup")
+	disabled := `{"bucket_ratelimit":{"max_read_ops":0,"max_write_ops":0,"max_read_bytes":0,"max_write_bytes":0,"enabled":false},
+		"user_ratelimit":{"max_read_ops":0,"max_write_ops":0,"max_read_bytes":0,"max_write_bytes":0,"enabled":false}}`
+	enabled := `{"bucket_ratelimit":{"max_read_ops":0,"max_write_ops":0,"max_read_bytes":0,"max_write_bytes":0,"enabled":false},
+		"user_ratelimit":{"max_read_ops":100,"max_write_ops":0,"max_read_bytes":0,"max_write_bytes":0,"enabled":true}}`
+
+	t.Run("no rate limit", func(t *testing.T) {
+		current, commands = enabled, []string{}
+		changed, err := SetGlobalRateLimit(c, "my-realm", RateLimitScopeUser, nil)
+		assert.NoError(t, err)
+		assert.False(t, changed)
+		assert.Empty(t, commands)
+	})
+
+	t.Run("new rate limit", func(t *testing.T) {
+		current, commands = disabled, []string{}
+		changed, err := SetGlobalRateLimit(c, "my-realm", RateLimitScopeBucket, &RateLimit{MaxWriteOps: 10})
+		assert.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, []string{
+			"global ratelimit get",
+			"global ratelimit set --ratelimit-scope=bucket --max-read-ops=0 --max-write-ops=10 --max-read-bytes=0 --max-write-bytes=0",
+			"global ratelimit enable --ratelimit-scope=bucket",
+		}, commands)
+	})
+
+	t.Run("unchanged rate limit", func(t *testing.T) {
+		current, commands = enabled, []string{}
+		changed, err := SetGlobalRateLimit(c, "my-realm", RateLimitScopeUser, &RateLimit{MaxReadOps: 100})
+		assert.NoError(t, err)
+		assert.False(t, changed)
+		assert.Equal(t, []string{"global ratelimit get"}, commands)
+	})
+
+	t.Run("bad output", func(t *testing.T) {
+		current, commands = "{", []string{}
+		_, err := SetGlobalRateLimit(c, "my-realm", RateLimitScopeUser, &RateLimit{MaxReadOps: 100})
+		assert.Error(t, err)
+	})
+}
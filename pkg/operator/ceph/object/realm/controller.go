@@ -176,6 +176,7 @@ func (r *ReconcileObjectRealm) reconcile(request reconcile.Request) (reconcile.R
 	// validate the realm settings
 	err = validateRealmCR(cephObjectRealm)
 	if err != nil {
+		reporting.ReportValidationFailed(r.recorder, cephObjectRealm, err)
 		r.updateStatus(k8sutil.ObservedGenerationNotAvailable, request.NamespacedName, k8sutil.ReconcileFailedStatus)
 		return reconcile.Result{}, *cephObjectRealm, errors.Wrapf(err, "invalid CephObjectRealm CR %q", cephObjectRealm.Name)
 	}
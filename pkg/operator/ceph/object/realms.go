@@ -0,0 +1,161 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// storeRealm is the realm, zone group, zone and pools of an object store
+type storeRealm struct {
+	store     string
+	realm     string
+	zoneGroup string
+	zone      string
+	// singleSite is whether the realm is created for the store alone
+	singleSite bool
+	pools      []string
+}
+
+func newStoreRealm(store *cephv1.CephObjectStore, realm, zoneGroup, zone string) storeRealm {
+	return storeRealm{
+		store:      store.Name,
+		realm:      realm,
+		zoneGroup:  zoneGroup,
+		zone:       zone,
+		singleSite: !store.Spec.IsMultisite() && !store.Spec.IsAdopted(),
+		pools:      storePools(&store.Spec, zone),
+	}
+}
+
+// storePools returns the pools of the store, which are the pools named after the zone, or the shared pools with the
+// namespaces of the zone as "<pool>:<zone>" since the stores sharing pools are kept apart by the namespaces of their
+// zones. The ".rgw.root" pool spans all the realms and is not returned.
+func storePools(spec *cephv1.ObjectStoreSpec, zone string) []string {
+	if spec.UsesSharedPools() {
+		return []string{
+			fmt.Sprintf("%s:%s", spec.SharedPools.MetadataPoolName, zone),
+			fmt.Sprintf("%s:%s", spec.SharedPools.DataPoolName, zone),
+		}
+	}
+	pools := []string{}
+	for _, pool := range allObjectPools(zone) {
+		if !strings.HasPrefix(pool, ".") {
+			pools = append(pools, pool)
+		}
+	}
+	return pools
+}
+
+// checkRealmIsolation returns the realm topology of the store, or an error if the store collides with the other
+// object stores of the namespace that are in a different realm
+func (r *ReconcileCephObjectStore) checkRealmIsolation(store *cephv1.CephObjectStore, realm, zoneGroup, zone string) (*cephv1.ObjectRealmTopology, error) {
+	stores := &cephv1.CephObjectStoreList{}
+	if err := r.client.List(r.opManagerContext, stores, client.InNamespace(store.Namespace)); err != nil {
+		return nil, errors.Wrapf(err, "failed to list the object stores in namespace %q", store.Namespace)
+	}
+
+	others := []storeRealm{}
+	for i := range stores.Items {
+		other := &stores.Items[i]
+		if other.Name == store.Name || other.Spec.IsExternal() || !other.DeletionTimestamp.IsZero() {
+			continue
+		}
+		otherRealm, otherZoneGroup, otherZone, _, _, err := r.reconcileMultisiteCRs(other)
+		if err != nil {
+			logger.Debugf("skipping the realm isolation check against object store %q. %v", other.Name, err)
+			continue
+		}
+		others = append(others, newStoreRealm(other, otherRealm, otherZoneGroup, otherZone))
+	}
+
+	return validateRealmIsolation(newStoreRealm(store, realm, zoneGroup, zone), others)
+}
+
+// validateRealmIsolation returns the realm topology of the store, or an error if the realm of a single-site store is
+// used by another store, or if stores in different realms share a zone group, a zone or a pool. Each of those would
+// mix the periods of the realms.
+func validateRealmIsolation(current storeRealm, others []storeRealm) (*cephv1.ObjectRealmTopology, error) {
+	topology := &cephv1.ObjectRealmTopology{
+		Name:      current.realm,
+		ZoneGroup: current.zoneGroup,
+		Zone:      current.zone,
+		Stores:    []string{current.store},
+	}
+
+	for _, other := range others {
+		if other.realm == current.realm {
+			if current.singleSite || other.singleSite {
+				return nil, errors.Errorf("realm %q of object store %q is also used by object store %q", current.realm, current.store, other.store)
+			}
+			topology.Stores = append(topology.Stores, other.store)
+			continue
+		}
+
+		if other.zoneGroup == current.zoneGroup {
+			return nil, errors.Errorf("zone group %q of object store %q in realm %q is also in realm %q of object store %q",
+				current.zoneGroup, current.store, current.realm, other.realm, other.store)
+		}
+		if other.zone == current.zone {
+			return nil, errors.Errorf("zone %q of object store %q in realm %q is also in realm %q of object store %q",
+				current.zone, current.store, current.realm, other.realm, other.store)
+		}
+		for _, pool := range current.pools {
+			for _, otherPool := range other.pools {
+				if pool == otherPool {
+					return nil, errors.Errorf("pool %q of object store %q in realm %q is also used by object store %q in realm %q",
+						pool, current.store, current.realm, other.store, other.realm)
+				}
+			}
+		}
+	}
+
+	sort.Strings(topology.Stores)
+	return topology, nil
+}
+
+// updateRealmTopology sets the realm topology in the status of the store
+func (r *ReconcileCephObjectStore) updateRealmTopology(namespacedName types.NamespacedName, topology *cephv1.ObjectRealmTopology) {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		objectStore := &cephv1.CephObjectStore{}
+		if err := r.client.Get(r.opManagerContext, namespacedName, objectStore); err != nil {
+			if kerrors.IsNotFound(err) {
+				logger.Debug("CephObjectStore resource not found. Ignoring since object must be deleted.")
+				return nil
+			}
+			return errors.Wrapf(err, "failed to retrieve object store %q to update realm topology", namespacedName.String())
+		}
+		if objectStore.Status == nil {
+			return nil
+		}
+		objectStore.Status.Realm = topology
+		return reporting.UpdateStatus(r.client, objectStore)
+	})
+	if err != nil {
+		logger.Errorf("failed to update realm topology of object store %q. %v", namespacedName.String(), err)
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateRealmIsolation(t *testing.T) {
+	singleSite := func(name string) storeRealm {
+		store := &cephv1.CephObjectStore{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		return newStoreRealm(store, name, name, name)
+	}
+	multisite := func(name, realm, zoneGroup, zone string) storeRealm {
+		store := &cephv1.CephObjectStore{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		store.Spec.Zone.Name = zone
+		return newStoreRealm(store, realm, zoneGroup, zone)
+	}
+
+	t.Run("pools of the stores", func(t *testing.T) {
+		s := singleSite("a")
+		assert.Contains(t, s.pools, "a.rgw.buckets.data")
+		assert.NotContains(t, s.pools, ".rgw.root")
+		assert.True(t, s.singleSite)
+		assert.False(t, multisite("b", "r", "zg", "z").singleSite)
+	})
+
+	t.Run("independent single-site stores", func(t *testing.T) {
+		topology, err := validateRealmIsolation(singleSite("a"), []storeRealm{singleSite("b")})
+		assert.NoError(t, err)
+		assert.Equal(t, &cephv1.ObjectRealmTopology{Name: "a", ZoneGroup: "a", Zone: "a", Stores: []string{"a"}}, topology)
+	})
+
+	t.Run("stores in the same realm", func(t *testing.T) {
+		topology, err := validateRealmIsolation(multisite("b", "r", "zg", "z"), []storeRealm{multisite("a", "r", "zg", "z"), singleSite("c")})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, topology.Stores)
+	})
+
+	t.Run("realm of a single-site store used by another store", func(t *testing.T) {
+		_, err := validateRealmIsolation(multisite("b", "a", "zg", "z"), []storeRealm{singleSite("a")})
+		assert.ErrorContains(t, err, `realm "a" of object store "b" is also used by object store "a"`)
+	})
+
+	t.Run("zone group in two realms", func(t *testing.T) {
+		_, err := validateRealmIsolation(multisite("b", "r2", "zg", "z2"), []storeRealm{multisite("a", "r1", "zg", "z1")})
+		assert.ErrorContains(t, err, `zone group "zg"`)
+	})
+
+	t.Run("zone in two realms", func(t *testing.T) {
+		_, err := validateRealmIsolation(multisite("b", "r2", "zg2", "a"), []storeRealm{singleSite("a")})
+		assert.ErrorContains(t, err, `zone "a"`)
+	})
+
+	t.Run("shared pools in two realms", func(t *testing.T) {
+		sharedPools := func(name, metadataPool, dataPool string) storeRealm {
+			store := &cephv1.CephObjectStore{ObjectMeta: metav1.ObjectMeta{Name: name}}
+			store.Spec.SharedPools = cephv1.ObjectSharedPoolsSpec{MetadataPoolName: metadataPool, DataPoolName: dataPool}
+			return newStoreRealm(store, name, name, name)
+		}
+		a := sharedPools("a", "meta", "data")
+		assert.Equal(t, []string{"meta:a", "data:a"}, a.pools)
+		// the stores are kept apart by the namespaces of their zones in the shared pools
+		_, err := validateRealmIsolation(sharedPools("b", "meta", "data"), []storeRealm{a})
+		assert.NoError(t, err)
+	})
+
+	t.Run("pools in two realms", func(t *testing.T) {
+		a := singleSite("a")
+		a.pools = []string{"meta", "data"}
+		b := singleSite("b")
+		b.pools = []string{"meta2", "data"}
+		_, err := validateRealmIsolation(b, []storeRealm{a})
+		assert.ErrorContains(t, err, `pool "data" of object store "b" in realm "b" is also used by object store "a" in realm "a"`)
+	})
+}
@@ -20,6 +20,7 @@ package object
 import (
 	"fmt"
 	"math/rand"
+	"net"
 	"net/http"
 	"os"
 	"reflect"
@@ -41,6 +42,7 @@ import (
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -53,6 +55,8 @@ type clusterConfig struct {
 	ownerInfo   *k8sutil.OwnerInfo
 	DataPathMap *config.DataPathMap
 	client      client.Client
+	recorder    record.EventRecorder
+	rookImage   string
 }
 
 type rgwConfig struct {
@@ -65,6 +69,12 @@ type rgwConfig struct {
 
 var updateDeploymentAndWait = mon.UpdateCephDeploymentAndWait
 
+// waitForDeploymentRollout is overridden for unit testing
+var waitForDeploymentRollout = k8sutil.WaitForDeploymentRollout
+
+// waitForDaemonSetReady is overridden for unit testing
+var waitForDaemonSetReady = k8sutil.WaitForDaemonSetReady
+
 var (
 	insecureSkipVerify = "insecureSkipVerify"
 )
@@ -72,10 +82,19 @@ var (
 func (c *clusterConfig) createOrUpdateStore(realmName, zoneGroupName, zoneName string) error {
 	logger.Infof("creating object store %q in namespace %q", c.store.Name, c.store.Namespace)
 
+	// the gateways only trust the remote addr header once the ingress is restricted to the trusted proxies
+	if err := c.reconcileTrustedProxies(); err != nil {
+		return errors.Wrap(err, "failed to reconcile the trusted proxies")
+	}
+
 	if err := c.startRGWPods(realmName, zoneGroupName, zoneName); err != nil {
 		return errors.Wrap(err, "failed to start rgw pods")
 	}
 
+	if err := c.reconcileIngress(); err != nil {
+		return errors.Wrap(err, "failed to reconcile the rgw ingress")
+	}
+
 	objContext, err := NewMultisiteContext(c.context, c.clusterInfo, c.store)
 	if err != nil {
 		logger.Warningf("failed to get object context for rgw %q. %v", c.store.Name, err)
@@ -145,6 +164,22 @@ func (c *clusterConfig) startRGWPods(realmName, zoneGroupName, zoneName string)
 			}
 		}
 
+		err = c.setGatewayConfigMonConfigStore(rgwConfig)
+		if err != nil {
+			return errors.Wrap(err, "failed to set gateway config options")
+		}
+
+		// the gateways of all the nodes run in a daemonset instead of the deployment
+		if c.store.Spec.Gateway.AllNodes {
+			if err := c.startRGWDaemonSet(rgwConfig); err != nil {
+				return err
+			}
+			if err := c.generateMimeTypes(); err != nil {
+				return errors.Wrap(err, "failed to generate the rgw mime.types config")
+			}
+			return nil
+		}
+
 		// Create deployment
 		deployment, err := c.createDeployment(rgwConfig)
 		if err != nil {
@@ -170,9 +205,18 @@ func (c *clusterConfig) startRGWPods(realmName, zoneGroupName, zoneName string)
 				return errors.Wrap(createErr, "failed to create rgw deployment")
 			}
 			logger.Infof("object store %q deployment %q already exists. updating if needed", c.store.Name, deployment.Name)
+			c.reportDeploymentChanges(deployment)
 			if err := updateDeploymentAndWait(c.context, c.clusterInfo, deployment, config.RgwType, daemonLetterID, c.clusterSpec.SkipUpgradeChecks, c.clusterSpec.ContinueUpgradeAfterChecksEvenIfNotHealthy); err != nil {
 				return errors.Wrapf(err, "failed to update object store %q deployment %q", c.store.Name, deployment.Name)
 			}
+			// the gateways are updated one at a time, and the rollout stops at the first updated gateway that
+			// does not answer its S3 readiness probe, so that a bad image or config cannot stop all the gateways
+			if err := waitForDeploymentRollout(c.clusterInfo.Context, c.context.Clientset, c.store.Namespace, deployment.Name); err != nil {
+				return errors.Wrapf(err, "failed to roll out the updated gateways of object store %q", c.store.Name)
+			}
+		}
+		if err := c.deleteRGWDaemonSet(deployment.Name); err != nil {
+			return err
 		}
 
 		// Generate the mime.types file after the rep. controller as well for the same reason as keyring
@@ -392,6 +436,31 @@ func BuildDNSEndpoint(domainName string, port int32, secure bool) string {
 	return fmt.Sprintf("%s://%s:%d", httpPrefix, domainName, port)
 }
 
+// GatewayPodEndpoint builds the endpoint to reach a gateway pod of the object store directly, through the host
+// IP of the pods on the host network. It returns an empty endpoint while the pod has no address.
+func GatewayPodEndpoint(store *cephv1.CephObjectStore, pod *v1.Pod) string {
+	address := pod.Status.PodIP
+	if pod.Spec.HostNetwork {
+		address = pod.Status.HostIP
+	}
+	if address == "" {
+		return ""
+	}
+
+	secure := store.Spec.IsTLSEnabled()
+	port := rgwPortInternalPort
+	if secure {
+		port = store.Spec.Gateway.SecurePort
+	} else if pod.Spec.HostNetwork {
+		port = store.Spec.Gateway.Port
+	}
+	httpPrefix := "http"
+	if secure {
+		httpPrefix = "https"
+	}
+	return fmt.Sprintf("%s://%s", httpPrefix, net.JoinHostPort(address, strconv.Itoa(int(port))))
+}
+
 // GetTLSCACert fetch cacert for internal RGW requests
 func GetTlsCaCert(objContext *Context, objectStoreSpec *cephv1.ObjectStoreSpec) ([]byte, bool, error) {
 	var insecureTLS, ok bool
@@ -20,19 +20,25 @@ import (
 	"context"
 	"testing"
 
+	"github.com/pkg/errors"
+
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
 	"github.com/rook/rook/pkg/clusterd"
 	"github.com/rook/rook/pkg/daemon/ceph/client"
 	clienttest "github.com/rook/rook/pkg/daemon/ceph/client/test"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
 	"github.com/rook/rook/pkg/operator/ceph/config"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	"github.com/rook/rook/pkg/operator/test"
 	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/stretchr/testify/assert"
+	apps "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
 	fclient "k8s.io/client-go/kubernetes/fake"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -45,6 +51,9 @@ func TestStartRGW(t *testing.T) {
 			if args[0] == "auth" && args[1] == "get-or-create-key" {
 				return `{"key":"mysecurekey"}`, nil
 			}
+			if args[0] == "config" && args[1] == "get" {
+				return `{}`, nil
+			}
 			return `{"id":"test-id"}`, nil
 		},
 	}
@@ -64,13 +73,133 @@ func TestStartRGW(t *testing.T) {
 
 	// start a basic cluster
 	ownerInfo := client.NewMinimumOwnerInfoWithOwnerRef()
-	c := &clusterConfig{context, info, store, version, &cephv1.ClusterSpec{}, ownerInfo, data, r.client}
+	c := &clusterConfig{context, info, store, version, &cephv1.ClusterSpec{}, ownerInfo, data, r.client, nil, ""}
 	err := c.startRGWPods(store.Name, store.Name, store.Name)
 	assert.Nil(t, err)
 
 	validateStart(ctx, t, c, clientset)
 }
 
+func TestUpdateRGWWaitsForRollout(t *testing.T) {
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "auth" && args[1] == "get-or-create-key" {
+				return `{"key":"mysecurekey"}`, nil
+			}
+			if args[0] == "config" && args[1] == "get" {
+				return `{}`, nil
+			}
+			return `{"id":"test-id"}`, nil
+		},
+	}
+	clusterdContext := &clusterd.Context{Clientset: test.New(t, 3), Executor: executor, ConfigDir: t.TempDir()}
+	store := simpleStore()
+	store.Spec.Gateway.Instances = 3
+	data := config.NewStatelessDaemonDataPathMap(config.RgwType, "my-fs", "rook-ceph", "/var/lib/rook/")
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(&cephv1.CephObjectStore{}).Build()
+	c := &clusterConfig{clusterdContext, clienttest.CreateTestClusterInfo(1), store, "v1.1.0", &cephv1.ClusterSpec{}, client.NewMinimumOwnerInfoWithOwnerRef(), data, cl, nil, ""}
+
+	updateDeploymentAndWait = func(clusterdContext *clusterd.Context, clusterInfo *client.ClusterInfo, deployment *apps.Deployment, daemonType, daemonName string, skipUpgradeChecks, continueUpgradeAfterChecksEvenIfNotHealthy bool) error {
+		return nil
+	}
+	rolloutWaited := []string{}
+	var rolloutErr error
+	waitForDeploymentRollout = func(ctx context.Context, clientset kubernetes.Interface, namespace, name string) error {
+		rolloutWaited = append(rolloutWaited, name)
+		return rolloutErr
+	}
+	defer func() {
+		updateDeploymentAndWait = mon.UpdateCephDeploymentAndWait
+		waitForDeploymentRollout = k8sutil.WaitForDeploymentRollout
+	}()
+
+	// the rollout is not awaited when the deployment is created
+	assert.NoError(t, c.startRGWPods(store.Name, store.Name, store.Name))
+	assert.Empty(t, rolloutWaited)
+
+	// the update waits for the rollout of the gateways
+	assert.NoError(t, c.startRGWPods(store.Name, store.Name, store.Name))
+	assert.Equal(t, []string{instanceName(store.Name) + "-a"}, rolloutWaited)
+
+	// the reconcile fails when an updated gateway does not become ready
+	rolloutErr = errors.New("progress deadline exceeded")
+	err := c.startRGWPods(store.Name, store.Name, store.Name)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to roll out the updated gateways")
+}
+
+func TestStartRGWAllNodes(t *testing.T) {
+	ctx := context.TODO()
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "auth" && args[1] == "get-or-create-key" {
+				return `{"key":"mysecurekey"}`, nil
+			}
+			if args[0] == "config" && args[1] == "get" {
+				return `{}`, nil
+			}
+			return `{"id":"test-id"}`, nil
+		},
+	}
+	clientset := test.New(t, 3)
+	clusterdContext := &clusterd.Context{Clientset: clientset, Executor: executor, ConfigDir: t.TempDir()}
+	store := simpleStore()
+	store.Spec.Gateway.Instances = 2
+	data := config.NewStatelessDaemonDataPathMap(config.RgwType, "my-fs", "rook-ceph", "/var/lib/rook/")
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(&cephv1.CephObjectStore{}).Build()
+	c := &clusterConfig{clusterdContext, clienttest.CreateTestClusterInfo(1), store, "v1.1.0", &cephv1.ClusterSpec{}, client.NewMinimumOwnerInfoWithOwnerRef(), data, cl, nil, ""}
+	rgwName := instanceName(store.Name) + "-a"
+
+	updateDeploymentAndWait = func(clusterdContext *clusterd.Context, clusterInfo *client.ClusterInfo, deployment *apps.Deployment, daemonType, daemonName string, skipUpgradeChecks, continueUpgradeAfterChecksEvenIfNotHealthy bool) error {
+		return nil
+	}
+	waitForDeploymentRollout = func(ctx context.Context, clientset kubernetes.Interface, namespace, name string) error {
+		return nil
+	}
+	daemonSetReady := errors.New("daemonset not ready")
+	waitForDaemonSetReady = func(ctx context.Context, clientset kubernetes.Interface, namespace, name string) error {
+		return daemonSetReady
+	}
+	defer func() {
+		updateDeploymentAndWait = mon.UpdateCephDeploymentAndWait
+		waitForDeploymentRollout = k8sutil.WaitForDeploymentRollout
+		waitForDaemonSetReady = k8sutil.WaitForDaemonSetReady
+	}()
+
+	assert.NoError(t, c.startRGWPods(store.Name, store.Name, store.Name))
+	validateStart(ctx, t, c, clientset)
+
+	// the deployment is kept until the gateways of the daemonset are ready
+	store.Spec.Gateway.AllNodes = true
+	assert.Error(t, c.startRGWPods(store.Name, store.Name, store.Name))
+	_, err := clientset.AppsV1().DaemonSets(store.Namespace).Get(ctx, rgwName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	validateStart(ctx, t, c, clientset)
+
+	// the daemonset replaces the deployment
+	daemonSetReady = nil
+	assert.NoError(t, c.startRGWPods(store.Name, store.Name, store.Name))
+	ds, err := clientset.AppsV1().DaemonSets(store.Namespace).Get(ctx, rgwName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "rgw", ds.Spec.Template.Spec.Containers[0].Name)
+	assert.Equal(t, apps.RollingUpdateDaemonSetStrategyType, ds.Spec.UpdateStrategy.Type)
+	_, err = clientset.AppsV1().Deployments(store.Namespace).Get(ctx, rgwName, metav1.GetOptions{})
+	assert.True(t, kerrors.IsNotFound(err))
+	// the keyring of the gateways is kept
+	_, err = clientset.CoreV1().Secrets(c.clusterInfo.Namespace).Get(ctx, c.generateSecretName("a"), metav1.GetOptions{})
+	assert.NoError(t, err)
+
+	// the daemonset is updated
+	assert.NoError(t, c.startRGWPods(store.Name, store.Name, store.Name))
+
+	// the deployment replaces the daemonset
+	store.Spec.Gateway.AllNodes = false
+	assert.NoError(t, c.startRGWPods(store.Name, store.Name, store.Name))
+	validateStart(ctx, t, c, clientset)
+	_, err = clientset.AppsV1().DaemonSets(store.Namespace).Get(ctx, rgwName, metav1.GetOptions{})
+	assert.True(t, kerrors.IsNotFound(err))
+}
+
 func validateStart(ctx context.Context, t *testing.T, c *clusterConfig, clientset *fclient.Clientset) {
 	rgwName := instanceName(c.store.Name) + "-a"
 	r, err := clientset.AppsV1().Deployments(c.store.Namespace).Get(ctx, rgwName, metav1.GetOptions{})
@@ -110,7 +239,7 @@ func TestCreateObjectStore(t *testing.T) {
 	cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(object...).Build()
 	r := &ReconcileCephObjectStore{client: cl, scheme: s}
 	ownerInfo := client.NewMinimumOwnerInfoWithOwnerRef()
-	c := &clusterConfig{context, info, store, "1.2.3.4", &cephv1.ClusterSpec{}, ownerInfo, data, r.client}
+	c := &clusterConfig{context, info, store, "1.2.3.4", &cephv1.ClusterSpec{}, ownerInfo, data, r.client, nil, ""}
 	err := c.createOrUpdateStore(store.Name, store.Name, store.Name)
 	assert.Nil(t, err)
 }
@@ -137,7 +266,9 @@ func TestGenerateSecretName(t *testing.T) {
 		&cephv1.ClusterSpec{},
 		&k8sutil.OwnerInfo{},
 		&config.DataPathMap{},
-		cl}
+		cl,
+		nil,
+		""}
 	secret := c.generateSecretName("a")
 	assert.Equal(t, "rook-ceph-rgw-default-a-keyring", secret)
 }
@@ -176,6 +307,33 @@ func TestBuildDomainNameAndEndpoint(t *testing.T) {
 	assert.Equal(t, "https://rook-ceph-rgw-my-store.rook-ceph.svc:443", ep)
 }
 
+func TestGatewayPodEndpoint(t *testing.T) {
+	s := &cephv1.CephObjectStore{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-store", Namespace: "rook-ceph"},
+		Spec:       cephv1.ObjectStoreSpec{Gateway: cephv1.GatewaySpec{Port: 80}},
+	}
+	pod := &v1.Pod{Status: v1.PodStatus{PodIP: "10.1.2.3", HostIP: "192.168.0.1"}}
+
+	// the pod network listens on the internal port
+	assert.Equal(t, "http://10.1.2.3:8080", GatewayPodEndpoint(s, pod))
+
+	// the host network listens on the port of the spec
+	pod.Spec.HostNetwork = true
+	assert.Equal(t, "http://192.168.0.1:80", GatewayPodEndpoint(s, pod))
+
+	pod.Status.HostIP = "fd00::1"
+	assert.Equal(t, "http://[fd00::1]:80", GatewayPodEndpoint(s, pod))
+
+	// secure endpoint
+	s.Spec.Gateway.SecurePort = 443
+	s.Spec.Gateway.SSLCertificateRef = "my-cert"
+	assert.Equal(t, "https://[fd00::1]:443", GatewayPodEndpoint(s, pod))
+
+	// no address yet
+	pod.Status.HostIP = ""
+	assert.Equal(t, "", GatewayPodEndpoint(s, pod))
+}
+
 func TestGetTlsCaCert(t *testing.T) {
 	objContext := &Context{
 		Context: &clusterd.Context{
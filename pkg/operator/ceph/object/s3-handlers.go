@@ -82,15 +82,21 @@ func newS3Agent(accessKey, secretKey, endpoint string, debug bool, tlsCert []byt
 
 // CreateBucket creates a bucket with the given name
 func (s *S3Agent) CreateBucketNoInfoLogging(name string) error {
-	return s.createBucket(name, false)
+	return s.createBucket(name, "", false)
 }
 
 // CreateBucket creates a bucket with the given name
 func (s *S3Agent) CreateBucket(name string) error {
-	return s.createBucket(name, true)
+	return s.createBucket(name, "", true)
 }
 
-func (s *S3Agent) createBucket(name string, infoLogging bool) error {
+// CreateBucketInLocation creates a bucket with the given name and location constraint. RGW interprets the
+// location constraint as "<zonegroup api name>:<placement target>", where either part may be empty.
+func (s *S3Agent) CreateBucketInLocation(name, locationConstraint string) error {
+	return s.createBucket(name, locationConstraint, true)
+}
+
+func (s *S3Agent) createBucket(name, locationConstraint string, infoLogging bool) error {
 	if infoLogging {
 		logger.Infof("creating bucket %q", name)
 	} else {
@@ -99,6 +105,11 @@ func (s *S3Agent) createBucket(name string, infoLogging bool) error {
 	bucketInput := &s3.CreateBucketInput{
 		Bucket: &name,
 	}
+	if locationConstraint != "" {
+		bucketInput.CreateBucketConfiguration = &s3.CreateBucketConfiguration{
+			LocationConstraint: aws.String(locationConstraint),
+		}
+	}
 
 	_, err := s.Client.CreateBucket(bucketInput)
 	if err != nil {
@@ -137,6 +148,90 @@ func (s *S3Agent) DeleteBucket(name string) (bool, error) {
 	return true, nil
 }
 
+// GetBucketVersioning returns the versioning status of the bucket, which is empty if versioning was never enabled
+func (s *S3Agent) GetBucketVersioning(name string) (string, error) {
+	output, err := s.Client.GetBucketVersioning(&s3.GetBucketVersioningInput{
+		Bucket: aws.String(name),
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get versioning of bucket %q", name)
+	}
+	return aws.StringValue(output.Status), nil
+}
+
+// PutBucketVersioning enables or suspends the versioning of the bucket
+func (s *S3Agent) PutBucketVersioning(name string, enabled bool) error {
+	status := s3.BucketVersioningStatusSuspended
+	if enabled {
+		status = s3.BucketVersioningStatusEnabled
+	}
+	_, err := s.Client.PutBucketVersioning(&s3.PutBucketVersioningInput{
+		Bucket: aws.String(name),
+		VersioningConfiguration: &s3.VersioningConfiguration{
+			Status: aws.String(status),
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to set versioning of bucket %q to %q", name, status)
+	}
+	return nil
+}
+
+// bucketEncryptionNotFound is the error code of a bucket without a default encryption
+const bucketEncryptionNotFound = "ServerSideEncryptionConfigurationNotFoundError"
+
+// GetBucketEncryption returns whether the bucket is encrypted by default with AWS-SSE:S3
+func (s *S3Agent) GetBucketEncryption(name string) (bool, error) {
+	output, err := s.Client.GetBucketEncryption(&s3.GetBucketEncryptionInput{
+		Bucket: aws.String(name),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == bucketEncryptionNotFound {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to get encryption of bucket %q", name)
+	}
+	if output.ServerSideEncryptionConfiguration == nil {
+		return false, nil
+	}
+	for _, rule := range output.ServerSideEncryptionConfiguration.Rules {
+		if rule.ApplyServerSideEncryptionByDefault != nil &&
+			aws.StringValue(rule.ApplyServerSideEncryptionByDefault.SSEAlgorithm) == s3.ServerSideEncryptionAes256 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// PutBucketEncryption enables the default encryption of the bucket with AWS-SSE:S3
+func (s *S3Agent) PutBucketEncryption(name string) error {
+	_, err := s.Client.PutBucketEncryption(&s3.PutBucketEncryptionInput{
+		Bucket: aws.String(name),
+		ServerSideEncryptionConfiguration: &s3.ServerSideEncryptionConfiguration{
+			Rules: []*s3.ServerSideEncryptionRule{{
+				ApplyServerSideEncryptionByDefault: &s3.ServerSideEncryptionByDefault{
+					SSEAlgorithm: aws.String(s3.ServerSideEncryptionAes256),
+				},
+			}},
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to set encryption of bucket %q", name)
+	}
+	return nil
+}
+
+// DeleteBucketEncryption disables the default encryption of the bucket
+func (s *S3Agent) DeleteBucketEncryption(name string) error {
+	_, err := s.Client.DeleteBucketEncryption(&s3.DeleteBucketEncryptionInput{
+		Bucket: aws.String(name),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete encryption of bucket %q", name)
+	}
+	return nil
+}
+
 // PutObjectInBucket function puts an object in a bucket using s3 client
 func (s *S3Agent) PutObjectInBucket(bucketname string, body string, key string,
 	contentType string) (bool, error) {
@@ -0,0 +1,132 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+)
+
+// checkSharedPools returns an error if the shared pools of the store do not exist. The pools are created and
+// deleted outside of the store, for example by CephBlockPool CRs.
+func checkSharedPools(objContext *Context, pools cephv1.ObjectSharedPoolsSpec) error {
+	summaries, err := cephclient.ListPoolSummaries(objContext.Context, objContext.clusterInfo)
+	if err != nil {
+		return errors.Wrap(err, "failed to list pools")
+	}
+	existing := map[string]bool{}
+	for _, summary := range summaries {
+		existing[summary.Name] = true
+	}
+	for _, pool := range []string{pools.MetadataPoolName, pools.DataPoolName} {
+		if !existing[pool] {
+			return errors.Errorf("shared pool %q does not exist", pool)
+		}
+	}
+	return nil
+}
+
+// configureSharedPools sets the pools of the zone of the store to rados namespaces of the shared pools that are
+// named after the zone, so that several stores can keep their objects in the same pools. The zone is only updated
+// and committed when its pools change.
+func configureSharedPools(objContext *Context, pools cephv1.ObjectSharedPoolsSpec) error {
+	output, err := runAdminCommand(objContext, true, "zone", "get")
+	if err != nil {
+		return errorOrIsNotFound(err, "failed to get zone %q", objContext.Zone)
+	}
+	zoneConfig := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(output), &zoneConfig); err != nil {
+		return errors.Wrapf(err, "failed to parse config of zone %q", objContext.Zone)
+	}
+
+	if !applySharedPools(zoneConfig, objContext.Zone, pools) {
+		logger.Debugf("zone %q already uses the shared pools", objContext.Zone)
+		return nil
+	}
+
+	zoneJSON, err := json.Marshal(zoneConfig)
+	if err != nil {
+		return errors.Wrapf(err, "failed to serialize config of zone %q", objContext.Zone)
+	}
+	if err := RunAdminCommandWithStdin(objContext, string(zoneJSON), "zone", "set"); err != nil {
+		return errors.Wrapf(err, "failed to set the shared pools of zone %q", objContext.Zone)
+	}
+
+	if err := commitConfigChanges(objContext); err != nil {
+		nsName := fmt.Sprintf("%s/%s", objContext.clusterInfo.Namespace, objContext.Name)
+		return errors.Wrapf(err, "failed to commit config changes after setting the shared pools of CephObjectStore %q", nsName)
+	}
+	logger.Infof("zone %q uses the shared metadata pool %q and data pool %q", objContext.Zone, pools.MetadataPoolName, pools.DataPoolName)
+	return nil
+}
+
+// applySharedPools sets the pools of the zone config, as returned by "radosgw-admin zone get", to the namespaces of
+// the shared pools. It returns whether the config changed.
+func applySharedPools(zoneConfig map[string]interface{}, zoneName string, pools cephv1.ObjectSharedPoolsSpec) bool {
+	changed := false
+	set := func(config map[string]interface{}, key, value string) {
+		if current, ok := config[key].(string); !ok || current != value {
+			config[key] = value
+			changed = true
+		}
+	}
+	namespaced := func(pool, namespace string) string {
+		return fmt.Sprintf("%s:%s.%s", pool, zoneName, namespace)
+	}
+
+	// the metadata pools of the zone, e.g. "domain_root", "control_pool" and "log_pool"
+	for key, value := range zoneConfig {
+		if _, ok := value.(string); !ok {
+			continue
+		}
+		if key == "domain_root" || strings.HasSuffix(key, "_pool") {
+			set(zoneConfig, key, namespaced(pools.MetadataPoolName, strings.TrimSuffix(key, "_pool")))
+		}
+	}
+
+	placements, _ := zoneConfig["placement_pools"].([]interface{})
+	for _, p := range placements {
+		placement, ok := p.(map[string]interface{})
+		if !ok || placement["key"] != defaultPlacementTarget {
+			continue
+		}
+		val, ok := placement["val"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		set(val, "index_pool", namespaced(pools.MetadataPoolName, "index"))
+		set(val, "data_extra_pool", namespaced(pools.MetadataPoolName, "non-ec"))
+		classes, ok := val["storage_classes"].(map[string]interface{})
+		if !ok {
+			classes = map[string]interface{}{}
+			val["storage_classes"] = classes
+		}
+		standard, ok := classes[standardStorageClass].(map[string]interface{})
+		if !ok {
+			standard = map[string]interface{}{}
+			classes[standardStorageClass] = standard
+		}
+		set(standard, "data_pool", namespaced(pools.DataPoolName, "data"))
+	}
+
+	return changed
+}
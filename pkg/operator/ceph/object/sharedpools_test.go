@@ -0,0 +1,132 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const zoneGetOutput = `{
+	"id": "c4b8a5a6-9c3d-4a4e-8d1e-8a8c0f7b3f10",
+	"name": "my-store",
+	"domain_root": "my-store.rgw.meta:root",
+	"control_pool": "my-store.rgw.control",
+	"gc_pool": "my-store.rgw.log:gc",
+	"log_pool": "my-store.rgw.log",
+	"otp_pool": "my-store.rgw.otp",
+	"system_key": {"access_key": "", "secret_key": ""},
+	"placement_pools": [
+		{
+			"key": "default-placement",
+			"val": {
+				"index_pool": "my-store.rgw.buckets.index",
+				"storage_classes": {"STANDARD": {"data_pool": "my-store.rgw.buckets.data"}},
+				"data_extra_pool": "my-store.rgw.buckets.non-ec",
+				"index_type": 0
+			}
+		}
+	],
+	"realm_id": ""
+}`
+
+func TestApplySharedPools(t *testing.T) {
+	pools := cephv1.ObjectSharedPoolsSpec{MetadataPoolName: "rgw-meta", DataPoolName: "rgw-data"}
+	zoneConfig := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal([]byte(zoneGetOutput), &zoneConfig))
+
+	assert.True(t, applySharedPools(zoneConfig, "my-store", pools))
+	assert.Equal(t, "rgw-meta:my-store.domain_root", zoneConfig["domain_root"])
+	assert.Equal(t, "rgw-meta:my-store.control", zoneConfig["control_pool"])
+	assert.Equal(t, "rgw-meta:my-store.gc", zoneConfig["gc_pool"])
+	assert.Equal(t, "rgw-meta:my-store.otp", zoneConfig["otp_pool"])
+	assert.Equal(t, "my-store", zoneConfig["name"])
+	placement := zoneConfig["placement_pools"].([]interface{})[0].(map[string]interface{})["val"].(map[string]interface{})
+	assert.Equal(t, "rgw-meta:my-store.index", placement["index_pool"])
+	assert.Equal(t, "rgw-meta:my-store.non-ec", placement["data_extra_pool"])
+	standard := placement["storage_classes"].(map[string]interface{})["STANDARD"].(map[string]interface{})
+	assert.Equal(t, "rgw-data:my-store.data", standard["data_pool"])
+
+	// the zone already uses the shared pools
+	assert.False(t, applySharedPools(zoneConfig, "my-store", pools))
+}
+
+func TestConfigureSharedPools(t *testing.T) {
+	commitConfigChangesOrig := commitConfigChanges
+	defer func() { commitConfigChanges = commitConfigChangesOrig }()
+	committed := false
+	commitConfigChanges = func(c *Context) error {
+		committed = true
+		return nil
+	}
+
+	zone := zoneGetOutput
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			if args[0] == "zone" && args[1] == "get" {
+				return zone, nil
+			}
+			return "", nil
+		},
+		MockExecuteCommandWithStdin: func(timeout time.Duration, command string, stdin *string, args ...string) error {
+			assert.Equal(t, "zone set", strings.Join(args[0:2], " "))
+			assert.Contains(t, args, "--rgw-zone=my-store")
+			zone = *stdin
+			return nil
+		},
+	}
+	clusterInfo := client.AdminTestClusterInfo("rook-ceph")
+	clusterInfo.Context = context.TODO()
+	objContext := NewContext(&clusterd.Context{Executor: executor}, clusterInfo, "my-store")
+	objContext.Realm = "my-store"
+	objContext.ZoneGroup = "my-store"
+	objContext.Zone = "my-store"
+	pools := cephv1.ObjectSharedPoolsSpec{MetadataPoolName: "rgw-meta", DataPoolName: "rgw-data"}
+
+	assert.NoError(t, configureSharedPools(objContext, pools))
+	assert.True(t, committed)
+	assert.Contains(t, zone, `"index_pool":"rgw-meta:my-store.index"`)
+
+	// the zone is not updated again
+	committed = false
+	assert.NoError(t, configureSharedPools(objContext, pools))
+	assert.False(t, committed)
+}
+
+func TestCheckSharedPools(t *testing.T) {
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "osd" && args[1] == "lspools" {
+				return `[{"poolnum":1,"poolname":"rgw-meta"},{"poolnum":2,"poolname":"rgw-data"}]`, nil
+			}
+			return "", nil
+		},
+	}
+	objContext := NewContext(&clusterd.Context{Executor: executor}, client.AdminTestClusterInfo("rook-ceph"), "my-store")
+
+	assert.NoError(t, checkSharedPools(objContext, cephv1.ObjectSharedPoolsSpec{MetadataPoolName: "rgw-meta", DataPoolName: "rgw-data"}))
+	assert.Error(t, checkSharedPools(objContext, cephv1.ObjectSharedPoolsSpec{MetadataPoolName: "rgw-meta", DataPoolName: "missing"}))
+}
@@ -44,6 +44,8 @@ const (
 	sseKMS             = "ssekms"
 	sseS3              = "sses3"
 	vaultPrefix        = "/v1/"
+	// rackTopologyLabel is the node label of the rack in the CRUSH topology of the cluster
+	rackTopologyLabel = "topology.rook.io/rack"
 	//nolint:gosec // since this is not leaking any hardcoded details
 	setupVaultTokenFile = `
 set -e
@@ -93,6 +95,34 @@ type rgwProbeConfig struct {
 	Port     string
 }
 
+// setTopologySpreadConstraints spreads the rgw pods across the zones and racks of the nodes by default. The
+// constraints of the placement are applied instead if set, with the labels of the rgw pods when they have no selector.
+// An empty list of constraints in the placement disables the spreading.
+func setTopologySpreadConstraints(podSpec *v1.PodSpec, constraints []v1.TopologySpreadConstraint, labels map[string]string) {
+	if constraints == nil {
+		podSpec.TopologySpreadConstraints = []v1.TopologySpreadConstraint{}
+		for _, topologyKey := range []string{v1.LabelTopologyZone, rackTopologyLabel} {
+			// the nodes without the label are only scored lower, so the pods are still scheduled if the label is not set
+			podSpec.TopologySpreadConstraints = append(podSpec.TopologySpreadConstraints, v1.TopologySpreadConstraint{
+				MaxSkew:           1,
+				TopologyKey:       topologyKey,
+				WhenUnsatisfiable: v1.ScheduleAnyway,
+				LabelSelector:     &metav1.LabelSelector{MatchLabels: labels},
+			})
+		}
+		return
+	}
+
+	// copy the constraints to not modify the spec of the store
+	podSpec.TopologySpreadConstraints = make([]v1.TopologySpreadConstraint, len(constraints))
+	for i := range constraints {
+		constraints[i].DeepCopyInto(&podSpec.TopologySpreadConstraints[i])
+		if podSpec.TopologySpreadConstraints[i].LabelSelector == nil {
+			podSpec.TopologySpreadConstraints[i].LabelSelector = &metav1.LabelSelector{MatchLabels: labels}
+		}
+	}
+}
+
 func (c *clusterConfig) createDeployment(rgwConfig *rgwConfig) (*apps.Deployment, error) {
 	pod, err := c.makeRGWPodSpec(rgwConfig)
 	if err != nil {
@@ -109,6 +139,15 @@ func (c *clusterConfig) createDeployment(rgwConfig *rgwConfig) (*apps.Deployment
 		MaxUnavailable: &intstr.IntOrString{IntVal: int32(1)},
 		MaxSurge:       &intstr.IntOrString{IntVal: int32(0)},
 	}
+	if !c.store.Spec.IsHostNetwork(c.clusterSpec) {
+		// Start each updated gateway before stopping an old one, so that the gateways keep serving until the
+		// updated gateway answers its S3 readiness probe. With host networking, the gateways cannot share the
+		// port of a node, so one is stopped first.
+		strategy.RollingUpdate = &apps.RollingUpdateDeployment{
+			MaxUnavailable: &intstr.IntOrString{IntVal: int32(0)},
+			MaxSurge:       &intstr.IntOrString{IntVal: int32(1)},
+		}
+	}
 	d := &apps.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      rgwConfig.ResourceName,
@@ -125,7 +164,7 @@ func (c *clusterConfig) createDeployment(rgwConfig *rgwConfig) (*apps.Deployment
 		},
 	}
 	k8sutil.AddRookVersionLabelToDeployment(d)
-	c.store.Spec.Gateway.Annotations.ApplyToObjectMeta(&d.ObjectMeta)
+	c.applyGatewayAnnotations(&d.ObjectMeta)
 	c.store.Spec.Gateway.Labels.ApplyToObjectMeta(&d.ObjectMeta)
 	controller.AddCephVersionLabelToDeployment(c.clusterInfo.CephVersion, d)
 
@@ -146,7 +185,8 @@ func (c *clusterConfig) makeRGWPodSpec(rgwConfig *rgwConfig) (v1.PodTemplateSpec
 		Containers:    []v1.Container{rgwDaemonContainer},
 		RestartPolicy: v1.RestartPolicyAlways,
 		Volumes: append(
-			controller.DaemonVolumes(c.DataPathMap, rgwConfig.ResourceName, c.clusterSpec.DataDirHostPath),
+			append(controller.DaemonVolumes(c.DataPathMap, rgwConfig.ResourceName, c.clusterSpec.DataDirHostPath),
+				controller.HardenedDaemonVolumes(c.clusterSpec, c.DataPathMap)...),
 			c.mimeTypesVolume(),
 		),
 		HostNetwork:        hostNetwork,
@@ -161,6 +201,8 @@ func (c *clusterConfig) makeRGWPodSpec(rgwConfig *rgwConfig) (v1.PodTemplateSpec
 		podSpec.Containers = append(podSpec.Containers, *controller.LogCollectorContainer(getDaemonName(rgwConfig), c.clusterInfo.Namespace, *c.clusterSpec))
 	}
 
+	c.addOpsLogSidecar(&podSpec)
+
 	// Replace default unreachable node toleration
 	k8sutil.AddUnreachableNodeToleration(&podSpec)
 
@@ -229,10 +271,13 @@ func (c *clusterConfig) makeRGWPodSpec(rgwConfig *rgwConfig) (v1.PodTemplateSpec
 		podSpec.InitContainers = append(podSpec.InitContainers,
 			c.vaultTokenInitContainer(rgwConfig, kmsEnabled, s3Enabled))
 	}
-	c.store.Spec.Gateway.Placement.ApplyToPodSpec(&podSpec)
+	placement := c.gatewayPlacement()
+	placement.ApplyToPodSpec(&podSpec)
 
-	// If host networking is not enabled, preferred pod anti-affinity is added to the rgw daemons
 	labels := getLabels(c.store.Name, c.store.Namespace, false)
+	setTopologySpreadConstraints(&podSpec, placement.TopologySpreadConstraints, labels)
+
+	// If host networking is not enabled, preferred pod anti-affinity is added to the rgw daemons
 	k8sutil.SetNodeAntiAffinityForPod(&podSpec, c.store.Spec.IsHostNetwork(c.clusterSpec), v1.LabelHostname, labels, nil)
 
 	podTemplateSpec := v1.PodTemplateSpec{
@@ -242,7 +287,36 @@ func (c *clusterConfig) makeRGWPodSpec(rgwConfig *rgwConfig) (v1.PodTemplateSpec
 		},
 		Spec: podSpec,
 	}
-	c.store.Spec.Gateway.Annotations.ApplyToObjectMeta(&podTemplateSpec.ObjectMeta)
+	if len(c.store.Spec.Gateway.Config) > 0 {
+		// restart the rgw daemons when the config changes, since most rgw options are only read at startup
+		podTemplateSpec.ObjectMeta.Annotations = map[string]string{
+			"config-hash": k8sutil.Hash(fmt.Sprintf("%v", c.store.Spec.Gateway.Config)),
+		}
+	}
+	if hash := dnsNamesHash(c.store.Spec.Hosting); hash != "" {
+		if podTemplateSpec.ObjectMeta.Annotations == nil {
+			podTemplateSpec.ObjectMeta.Annotations = map[string]string{}
+		}
+		podTemplateSpec.ObjectMeta.Annotations[dnsNamesHashAnnotation] = hash
+	}
+	if hash := swiftConfigHash(c.store.Spec.Protocols.Swift); hash != "" {
+		// the swift url prefix and auth entry are only read at startup
+		if podTemplateSpec.ObjectMeta.Annotations == nil {
+			podTemplateSpec.ObjectMeta.Annotations = map[string]string{}
+		}
+		podTemplateSpec.ObjectMeta.Annotations[swiftConfigHashAnnotation] = hash
+	}
+	if c.store.Spec.IsSTSEnabled() {
+		// the sts auth is enabled at the startup of the rgw daemons
+		if podTemplateSpec.ObjectMeta.Annotations == nil {
+			podTemplateSpec.ObjectMeta.Annotations = map[string]string{}
+		}
+		podTemplateSpec.ObjectMeta.Annotations[stsEnabledAnnotation] = "true"
+	}
+	if err := c.addTracingSidecar(&podTemplateSpec); err != nil {
+		return podTemplateSpec, err
+	}
+	c.applyGatewayAnnotations(&podTemplateSpec.ObjectMeta)
 	c.store.Spec.Gateway.Labels.ApplyToObjectMeta(&podTemplateSpec.ObjectMeta)
 
 	if hostNetwork {
@@ -272,7 +346,7 @@ func (c *clusterConfig) createCaBundleUpdateInitContainer(rgwConfig *rgwConfig)
 		Image:           c.clusterSpec.CephVersion.Image,
 		ImagePullPolicy: controller.GetContainerImagePullPolicy(c.clusterSpec.CephVersion.ImagePullPolicy),
 		VolumeMounts:    volumeMounts,
-		Resources:       c.store.Spec.Gateway.Resources,
+		Resources:       c.gatewayResources(),
 		SecurityContext: controller.PodSecurityContext(),
 	}
 }
@@ -309,7 +383,7 @@ func (c *clusterConfig) vaultTokenInitContainer(rgwConfig *rgwConfig, kmsEnabled
 		ImagePullPolicy: controller.GetContainerImagePullPolicy(c.clusterSpec.CephVersion.ImagePullPolicy),
 		VolumeMounts: append(
 			controller.DaemonVolumeMounts(c.DataPathMap, rgwConfig.ResourceName, c.clusterSpec.DataDirHostPath), vaultVolMounts...),
-		Resources:       c.store.Spec.Gateway.Resources,
+		Resources:       c.gatewayResources(),
 		SecurityContext: controller.PodSecurityContext(),
 	}
 }
@@ -319,13 +393,24 @@ func (c *clusterConfig) makeChownInitContainer(rgwConfig *rgwConfig) v1.Containe
 		*c.DataPathMap,
 		c.clusterSpec.CephVersion.Image,
 		controller.GetContainerImagePullPolicy(c.clusterSpec.CephVersion.ImagePullPolicy),
-		controller.DaemonVolumeMounts(c.DataPathMap, rgwConfig.ResourceName, c.clusterSpec.DataDirHostPath),
-		c.store.Spec.Gateway.Resources,
-		controller.PodSecurityContext(),
+		append(controller.DaemonVolumeMounts(c.DataPathMap, rgwConfig.ResourceName, c.clusterSpec.DataDirHostPath),
+			controller.HardenedDaemonVolumeMounts(c.clusterSpec, c.DataPathMap)...),
+		c.gatewayResources(),
+		controller.ChownSecurityContext(c.clusterSpec),
 		"",
 	)
 }
 
+// bindsPrivilegedPort returns whether the gateways bind a port below 1024, which requires to start them as root
+func (c *clusterConfig) bindsPrivilegedPort() bool {
+	privileged := func(port int32) bool { return port > 0 && port < 1024 }
+	if c.store.Spec.IsHostNetwork(c.clusterSpec) && privileged(c.store.Spec.Gateway.Port) {
+		return true
+	}
+	// the secure port is bound as is, even without host networking
+	return c.store.Spec.IsTLSEnabled() && privileged(c.store.Spec.Gateway.SecurePort)
+}
+
 func (c *clusterConfig) makeDaemonContainer(rgwConfig *rgwConfig) (v1.Container, error) {
 	// start the rgw daemon in the foreground
 	startupProbe, err := c.defaultStartupProbe()
@@ -336,6 +421,7 @@ func (c *clusterConfig) makeDaemonContainer(rgwConfig *rgwConfig) (v1.Container,
 	if err != nil {
 		return v1.Container{}, errors.Wrap(err, "failed to generate default readiness probe")
 	}
+	securityContext := controller.DaemonSecurityContext(c.clusterSpec, c.bindsPrivilegedPort())
 
 	container := v1.Container{
 		Name:            "rgw",
@@ -345,10 +431,10 @@ func (c *clusterConfig) makeDaemonContainer(rgwConfig *rgwConfig) (v1.Container,
 			"radosgw",
 		},
 		Args: append(
-			controller.DaemonFlags(c.clusterInfo, c.clusterSpec,
-				strings.TrimPrefix(generateCephXUser(rgwConfig.ResourceName), "client.")),
+			controller.HardenedDaemonFlags(controller.DaemonFlags(c.clusterInfo, c.clusterSpec,
+				strings.TrimPrefix(generateCephXUser(rgwConfig.ResourceName), "client.")), securityContext),
 			"--foreground",
-			cephconfig.NewFlag("rgw frontends", fmt.Sprintf("%s %s", rgwFrontendName, c.portString())),
+			cephconfig.NewFlag("rgw frontends", c.frontendString()),
 			cephconfig.NewFlag("host", controller.ContainerEnvVarReference(k8sutil.PodNameEnvVar)),
 			cephconfig.NewFlag("rgw-mime-types-file", mimeTypesMountPath()),
 			cephconfig.NewFlag("rgw realm", rgwConfig.Realm),
@@ -356,15 +442,16 @@ func (c *clusterConfig) makeDaemonContainer(rgwConfig *rgwConfig) (v1.Container,
 			cephconfig.NewFlag("rgw zone", rgwConfig.Zone),
 		),
 		VolumeMounts: append(
-			controller.DaemonVolumeMounts(c.DataPathMap, rgwConfig.ResourceName, c.clusterSpec.DataDirHostPath),
+			append(controller.DaemonVolumeMounts(c.DataPathMap, rgwConfig.ResourceName, c.clusterSpec.DataDirHostPath),
+				controller.HardenedDaemonVolumeMounts(c.clusterSpec, c.DataPathMap)...),
 			c.mimeTypesVolumeMount(),
 		),
 		Env:             controller.DaemonEnvVars(c.clusterSpec.CephVersion.Image),
-		Resources:       c.store.Spec.Gateway.Resources,
+		Resources:       c.gatewayResources(),
 		StartupProbe:    startupProbe,
 		LivenessProbe:   noLivenessProbe(),
 		ReadinessProbe:  readinessProbe,
-		SecurityContext: controller.PodSecurityContext(),
+		SecurityContext: securityContext,
 		WorkingDir:      cephconfig.VarLogCephDir,
 	}
 
@@ -556,6 +643,8 @@ func (c *clusterConfig) generateService(cephObjectStore *cephv1.CephObjectStore)
 		svc.Spec = v1.ServiceSpec{
 			Selector: getLabels(cephObjectStore.Name, cephObjectStore.Namespace, false),
 		}
+		// the ip families of the external gateways are the ones of their endpoints
+		controller.ApplyServiceIPFamily(c.clusterSpec, svc)
 	}
 
 	addPort(svc, "http", cephObjectStore.Spec.Gateway.Port, destPort.IntVal)
@@ -698,6 +787,23 @@ func (c *clusterConfig) CheckRGWSSES3Enabled() (bool, error) {
 	return false, nil
 }
 
+// gatewayPlacement returns the placement of the rgw daemons, merged over the default rgw placement of the cluster
+func (c *clusterConfig) gatewayPlacement() cephv1.Placement {
+	return cephv1.GetRgwPlacement(c.clusterSpec.Placement).Merge(c.store.Spec.Gateway.Placement)
+}
+
+// gatewayResources returns the resources of the rgw daemons, or the default rgw resources of the cluster
+func (c *clusterConfig) gatewayResources() v1.ResourceRequirements {
+	return cephv1.InheritResources(c.store.Spec.Gateway.Resources, cephv1.GetRgwResources(c.clusterSpec.Resources))
+}
+
+// applyGatewayAnnotations adds the annotations of the rgw daemons, and the default rgw annotations of the cluster
+// that the object store does not override
+func (c *clusterConfig) applyGatewayAnnotations(meta *metav1.ObjectMeta) {
+	c.store.Spec.Gateway.Annotations.ApplyToObjectMeta(meta)
+	cephv1.GetRgwAnnotations(c.clusterSpec.Annotations).ApplyToObjectMeta(meta)
+}
+
 func addPort(service *v1.Service, name string, port, destPort int32) {
 	if port == 0 || destPort == 0 {
 		return
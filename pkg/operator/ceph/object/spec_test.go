@@ -278,6 +278,7 @@ func TestValidateSpec(t *testing.T) {
 			External: cephv1.ExternalSpec{
 				Enable: false,
 			},
+			AllowUnsafePools: true,
 		},
 		clusterInfo: &client.ClusterInfo{
 			CephCred: client.CephCred{
@@ -876,3 +877,107 @@ func TestAWSServerSideEncryption(t *testing.T) {
 		assert.True(t, checkRGWOptions(rgwContainer.Args, c.sseS3VaultTLSOptions(true)))
 	})
 }
+
+func TestRGWTopologySpreadConstraints(t *testing.T) {
+	store := simpleStore()
+	info := clienttest.CreateTestClusterInfo(1)
+	data := cephconfig.NewStatelessDaemonDataPathMap(cephconfig.RgwType, "default", "rook-ceph", "/var/lib/rook/")
+	c := &clusterConfig{
+		store:       store,
+		rookVersion: "rook/rook:myversion",
+		clusterSpec: &cephv1.ClusterSpec{
+			CephVersion: cephv1.CephVersionSpec{Image: "quay.io/ceph/ceph:v15"},
+		},
+		clusterInfo: info,
+		DataPathMap: data,
+	}
+	rgwConfig := &rgwConfig{
+		ResourceName: fmt.Sprintf("%s-%s", AppName, c.store.Name),
+		DaemonID:     "default",
+	}
+	labels := getLabels(c.store.Name, c.store.Namespace, false)
+
+	t.Run("spread across zones and racks by default", func(t *testing.T) {
+		podTemplateSpec, err := c.makeRGWPodSpec(rgwConfig)
+		assert.NoError(t, err)
+		constraints := podTemplateSpec.Spec.TopologySpreadConstraints
+		assert.Len(t, constraints, 2)
+		assert.Equal(t, v1.LabelTopologyZone, constraints[0].TopologyKey)
+		assert.Equal(t, rackTopologyLabel, constraints[1].TopologyKey)
+		for _, constraint := range constraints {
+			assert.Equal(t, int32(1), constraint.MaxSkew)
+			assert.Equal(t, v1.ScheduleAnyway, constraint.WhenUnsatisfiable)
+			assert.Equal(t, labels, constraint.LabelSelector.MatchLabels)
+		}
+	})
+
+	t.Run("constraints of the placement", func(t *testing.T) {
+		c.store.Spec.Gateway.Placement.TopologySpreadConstraints = []v1.TopologySpreadConstraint{
+			{MaxSkew: 2, TopologyKey: v1.LabelHostname, WhenUnsatisfiable: v1.DoNotSchedule},
+		}
+		podTemplateSpec, err := c.makeRGWPodSpec(rgwConfig)
+		assert.NoError(t, err)
+		constraints := podTemplateSpec.Spec.TopologySpreadConstraints
+		assert.Len(t, constraints, 1)
+		assert.Equal(t, v1.LabelHostname, constraints[0].TopologyKey)
+		assert.Equal(t, v1.DoNotSchedule, constraints[0].WhenUnsatisfiable)
+		// the labels of the rgw pods are selected, without modifying the spec of the store
+		assert.Equal(t, labels, constraints[0].LabelSelector.MatchLabels)
+		assert.Nil(t, c.store.Spec.Gateway.Placement.TopologySpreadConstraints[0].LabelSelector)
+	})
+
+	t.Run("spreading disabled", func(t *testing.T) {
+		c.store.Spec.Gateway.Placement.TopologySpreadConstraints = []v1.TopologySpreadConstraint{}
+		podTemplateSpec, err := c.makeRGWPodSpec(rgwConfig)
+		assert.NoError(t, err)
+		assert.Empty(t, podTemplateSpec.Spec.TopologySpreadConstraints)
+	})
+}
+
+func TestRGWDeploymentStrategy(t *testing.T) {
+	store := simpleStore()
+	c := &clusterConfig{
+		store:       store,
+		rookVersion: "rook/rook:myversion",
+		clusterSpec: &cephv1.ClusterSpec{
+			CephVersion: cephv1.CephVersionSpec{Image: "quay.io/ceph/ceph:v15"},
+		},
+		clusterInfo: clienttest.CreateTestClusterInfo(1),
+		DataPathMap: cephconfig.NewStatelessDaemonDataPathMap(cephconfig.RgwType, "default", "rook-ceph", "/var/lib/rook/"),
+	}
+	rgwConfig := &rgwConfig{ResourceName: fmt.Sprintf("%s-%s", AppName, c.store.Name), DaemonID: "default"}
+
+	// an updated gateway is started before an old one is stopped
+	d, err := c.createDeployment(rgwConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(0), d.Spec.Strategy.RollingUpdate.MaxUnavailable.IntVal)
+	assert.Equal(t, int32(1), d.Spec.Strategy.RollingUpdate.MaxSurge.IntVal)
+
+	// with host networking, an old gateway is stopped first to free the port
+	c.clusterSpec.Network.HostNetwork = true
+	d, err = c.createDeployment(rgwConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), d.Spec.Strategy.RollingUpdate.MaxUnavailable.IntVal)
+	assert.Equal(t, int32(0), d.Spec.Strategy.RollingUpdate.MaxSurge.IntVal)
+}
+
+func TestBindsPrivilegedPort(t *testing.T) {
+	// the internal port is bound without host networking
+	cfg := newConfig(t)
+	cfg.store.Spec.Gateway.Port = 80
+	assert.False(t, cfg.bindsPrivilegedPort())
+
+	cfg.clusterSpec.Network.HostNetwork = true
+	assert.True(t, cfg.bindsPrivilegedPort())
+	cfg.store.Spec.Gateway.Port = 8080
+	assert.False(t, cfg.bindsPrivilegedPort())
+
+	// the secure port is bound as is
+	cfg = newConfig(t)
+	cfg.store.Spec.Gateway.SecurePort = 443
+	assert.False(t, cfg.bindsPrivilegedPort())
+	cfg.store.Spec.Gateway.SSLCertificateRef = "some-k8s-key-secret"
+	assert.True(t, cfg.bindsPrivilegedPort())
+	cfg.store.Spec.Gateway.SecurePort = 8443
+	assert.False(t, cfg.bindsPrivilegedPort())
+}
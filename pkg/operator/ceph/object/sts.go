@@ -0,0 +1,108 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mgr"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	rgwS3AuthUseSTSOption = "rgw_s3_auth_use_sts"
+	rgwSTSKeyOption       = "rgw_sts_key"
+	stsEnabledAnnotation  = "sts-enabled"
+	// STSKeySecretKey is the key of the sts key in the data of its secret
+	STSKeySecretKey = "key"
+	// the rgw encrypts the session tokens with AES, which requires a key of 16 characters
+	stsKeyLength = 16
+	stsKeyChars  = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+var validSTSKey = regexp.MustCompile(fmt.Sprintf("^[0-9A-Za-z]{%d}$", stsKeyLength))
+
+func stsKeySecretName(storeName string) string {
+	return fmt.Sprintf("%s-%s-sts-key", AppName, storeName)
+}
+
+// stsConfigOptions returns the config options of the sts settings of the store
+func (c *clusterConfig) stsConfigOptions() (map[string]string, error) {
+	options := map[string]string{}
+	if !c.store.Spec.IsSTSEnabled() {
+		return options, nil
+	}
+	key, err := c.stsKey()
+	if err != nil {
+		return nil, err
+	}
+	options[rgwS3AuthUseSTSOption] = "true"
+	options[rgwSTSKeyOption] = key
+	return options, nil
+}
+
+// stsKey returns the key the gateways encrypt the session tokens of the sts with. The key is read from the secret of
+// the spec if set, otherwise it is generated in a secret owned by the store on the first reconcile.
+func (c *clusterConfig) stsKey() (string, error) {
+	secretName := c.store.Spec.STS.KeySecretName
+	generated := secretName == ""
+	if generated {
+		secretName = stsKeySecretName(c.store.Name)
+	}
+
+	secret, err := c.context.Clientset.CoreV1().Secrets(c.store.Namespace).Get(c.clusterInfo.Context, secretName, metav1.GetOptions{})
+	if err == nil {
+		key := string(secret.Data[STSKeySecretKey])
+		if !validSTSKey.MatchString(key) {
+			return "", errors.Errorf("sts key in secret %q must be %d alphanumeric characters", secretName, stsKeyLength)
+		}
+		return key, nil
+	}
+	if !kerrors.IsNotFound(err) || !generated {
+		return "", errors.Wrapf(err, "failed to get sts key secret %q", secretName)
+	}
+
+	keyBytes, err := mgr.GenerateRandomBytes(stsKeyLength)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to generate sts key")
+	}
+	for i, b := range keyBytes {
+		keyBytes[i] = stsKeyChars[b%byte(len(stsKeyChars))]
+	}
+	secret = &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: c.store.Namespace,
+			Labels:    getLabels(c.store.Name, c.store.Namespace, false),
+		},
+		Data: map[string][]byte{STSKeySecretKey: keyBytes},
+		Type: k8sutil.RookType,
+	}
+	if err := c.ownerInfo.SetControllerReference(secret); err != nil {
+		return "", errors.Wrapf(err, "failed to set owner reference to sts key secret %q", secretName)
+	}
+	if _, err := c.context.Clientset.CoreV1().Secrets(c.store.Namespace).Create(c.clusterInfo.Context, secret, metav1.CreateOptions{}); err != nil {
+		return "", errors.Wrapf(err, "failed to create sts key secret %q", secretName)
+	}
+	logger.Infof("generated sts key secret %q for object store %q", secretName, c.store.Name)
+	return string(keyBytes), nil
+}
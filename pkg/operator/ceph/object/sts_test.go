@@ -0,0 +1,73 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"context"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSTSKey(t *testing.T) {
+	c := newConfig(t)
+	c.clusterInfo = cephclient.AdminTestClusterInfo("rook-ceph")
+	c.ownerInfo = cephclient.NewMinimumOwnerInfoWithOwnerRef()
+	c.store.Name = "my-store"
+	c.store.Namespace = "rook-ceph"
+	c.store.Spec.STS = &cephv1.ObjectStoreSTSSpec{Enabled: true}
+	secrets := c.context.Clientset.CoreV1().Secrets("rook-ceph")
+
+	t.Run("generated key", func(t *testing.T) {
+		key, err := c.stsKey()
+		assert.NoError(t, err)
+		assert.Regexp(t, "^[0-9A-Za-z]{16}$", key)
+		secret, err := secrets.Get(context.TODO(), "rook-ceph-rgw-my-store-sts-key", metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, key, string(secret.Data[STSKeySecretKey]))
+		assert.NotEmpty(t, secret.OwnerReferences)
+
+		// the key is not generated again
+		sameKey, err := c.stsKey()
+		assert.NoError(t, err)
+		assert.Equal(t, key, sameKey)
+	})
+
+	t.Run("key of the secret of the spec", func(t *testing.T) {
+		c.store.Spec.STS.KeySecretName = "my-sts-key"
+		_, err := c.stsKey()
+		assert.Error(t, err)
+
+		secret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "my-sts-key"}, Data: map[string][]byte{STSKeySecretKey: []byte("abcdefghijklmnop")}}
+		_, err = secrets.Create(context.TODO(), secret, metav1.CreateOptions{})
+		assert.NoError(t, err)
+		key, err := c.stsKey()
+		assert.NoError(t, err)
+		assert.Equal(t, "abcdefghijklmnop", key)
+
+		// invalid key
+		secret.Data[STSKeySecretKey] = []byte("too-short")
+		_, err = secrets.Update(context.TODO(), secret, metav1.UpdateOptions{})
+		assert.NoError(t, err)
+		_, err = c.stsKey()
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+)
+
+const (
+	swiftConfigHashAnnotation = "swift-config-hash"
+	// DefaultSwiftAuthEntry is the default entry point of the swift auth of the gateways
+	DefaultSwiftAuthEntry = "auth"
+)
+
+// swiftConfigOptions returns the rgw options of the swift settings of the store
+func swiftConfigOptions(swift *cephv1.SwiftSpec) map[string]string {
+	options := map[string]string{}
+	if swift == nil {
+		return options
+	}
+	if swift.UrlPrefix != nil {
+		options["rgw_swift_url_prefix"] = *swift.UrlPrefix
+	}
+	if swift.AccountInUrl != nil {
+		options["rgw_swift_account_in_url"] = strconv.FormatBool(*swift.AccountInUrl)
+	}
+	if swift.VersioningEnabled != nil {
+		options["rgw_swift_versioning_enabled"] = strconv.FormatBool(*swift.VersioningEnabled)
+	}
+	if swift.AuthEntry != nil {
+		options["rgw_swift_auth_entry"] = *swift.AuthEntry
+	}
+	return options
+}
+
+// swiftConfigHash returns the hash of the rgw options of the swift settings, or an empty string without options
+func swiftConfigHash(swift *cephv1.SwiftSpec) string {
+	options := swiftConfigOptions(swift)
+	if len(options) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	config := ""
+	for _, name := range names {
+		config += fmt.Sprintf("%s=%s;", name, options[name])
+	}
+	return k8sutil.Hash(config)
+}
+
+// SwiftAuthEndpoint returns the url of the swift auth of the gateways at the endpoint
+func SwiftAuthEndpoint(swift *cephv1.SwiftSpec, endpoint string) string {
+	authEntry := DefaultSwiftAuthEntry
+	if swift != nil && swift.AuthEntry != nil {
+		authEntry = *swift.AuthEntry
+	}
+	return fmt.Sprintf("%s/%s/1.0", endpoint, authEntry)
+}
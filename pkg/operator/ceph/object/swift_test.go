@@ -0,0 +1,83 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSwiftConfigOptions(t *testing.T) {
+	assert.Empty(t, swiftConfigOptions(nil))
+	assert.Empty(t, swiftConfigHash(&cephv1.SwiftSpec{}))
+
+	prefix := "object"
+	enabled := true
+	swift := &cephv1.SwiftSpec{UrlPrefix: &prefix, VersioningEnabled: &enabled}
+	assert.Equal(t, map[string]string{"rgw_swift_url_prefix": "object", "rgw_swift_versioning_enabled": "true"}, swiftConfigOptions(swift))
+	hash := swiftConfigHash(swift)
+	assert.NotEmpty(t, hash)
+
+	// the hash changes with the settings
+	swift.AccountInUrl = &enabled
+	assert.NotEqual(t, hash, swiftConfigHash(swift))
+}
+
+func TestSwiftAuthEndpoint(t *testing.T) {
+	assert.Equal(t, "http://rgw:80/auth/1.0", SwiftAuthEndpoint(nil, "http://rgw:80"))
+
+	authEntry := "swiftauth"
+	assert.Equal(t, "http://rgw:80/swiftauth/1.0", SwiftAuthEndpoint(&cephv1.SwiftSpec{AuthEntry: &authEntry}, "http://rgw:80"))
+}
+
+func TestSetGatewayConfigSwift(t *testing.T) {
+	commands := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			commands = append(commands, strings.Join(args, " "))
+			if args[0] == "config" && args[1] == "get" && strings.HasPrefix(args[3], "rgw_") {
+				return "", nil
+			}
+			if args[0] == "config" && args[1] == "get" {
+				return `{"rgw_swift_url_prefix":{"value":"object","section":"client.rgw.my.store.a","mask":{},"can_update_at_runtime":false}}`, nil
+			}
+			return "", nil
+		},
+	}
+	c := newConfig(t)
+	c.context.Executor = executor
+	c.clusterInfo = cephclient.AdminTestClusterInfo("rook-ceph")
+	prefix := "object"
+	c.store.Spec.Protocols.Swift = &cephv1.SwiftSpec{UrlPrefix: &prefix}
+
+	assert.NoError(t, c.setGatewayConfigMonConfigStore(&rgwConfig{ResourceName: "rook-ceph-rgw-my-store-a"}))
+	all := strings.Join(commands, ";")
+	assert.Contains(t, all, "config set client.rgw.my.store.a rgw_swift_url_prefix object")
+	assert.NotContains(t, all, "config rm")
+
+	// the options are removed with the swift settings
+	commands = []string{}
+	c.store.Spec.Protocols.Swift = nil
+	assert.NoError(t, c.setGatewayConfigMonConfigStore(&rgwConfig{ResourceName: "rook-ceph-rgw-my-store-a"}))
+	assert.Contains(t, strings.Join(commands, ";"), "config rm client.rgw.my.store.a rgw_swift_url_prefix")
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+	cephconfig "github.com/rook/rook/pkg/operator/ceph/config"
+	"github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	tracingContainerName = "tracing-agent"
+	// tracingCollectorEndpointKey is the key of the endpoint of the collector in the secret of the collector
+	tracingCollectorEndpointKey = "endpoint"
+	// the rgw daemons only send the traces to an agent on localhost, on the port of the option "jaeger_agent_port"
+	tracingAgentPort                = 6799
+	tracingCollectorHashAnnotation  = "tracing-collector-hash"
+	defaultTracingAgentImage        = "jaegertracing/jaeger-agent:1.47.0"
+	tracingAgentCollectorEnvVarName = "REPORTER_GRPC_HOST_PORT"
+)
+
+// addTracingSidecar enables the tracing of the rgw daemon and adds the agent sidecar forwarding the traces to the
+// collector. The rgw pods are restarted when the endpoint of the collector changes.
+func (c *clusterConfig) addTracingSidecar(podTemplateSpec *v1.PodTemplateSpec) error {
+	if !c.store.Spec.IsTracingEnabled() {
+		return nil
+	}
+	tracing := c.store.Spec.Gateway.Tracing
+
+	secret, err := c.context.Clientset.CoreV1().Secrets(c.store.Namespace).Get(c.clusterInfo.Context, tracing.CollectorSecretName, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the secret %q of the tracing collector", tracing.CollectorSecretName)
+	}
+	endpoint, ok := secret.Data[tracingCollectorEndpointKey]
+	if !ok || len(endpoint) == 0 {
+		return errors.Errorf("the secret %q of the tracing collector has no %q", tracing.CollectorSecretName, tracingCollectorEndpointKey)
+	}
+
+	podSpec := &podTemplateSpec.Spec
+	for i := range podSpec.Containers {
+		if podSpec.Containers[i].Name != "rgw" {
+			continue
+		}
+		podSpec.Containers[i].Args = append(podSpec.Containers[i].Args,
+			cephconfig.NewFlag("jaeger tracing enable", "true"),
+			cephconfig.NewFlag("jaeger agent port", strconv.Itoa(tracingAgentPort)),
+		)
+	}
+
+	image := tracing.AgentImage
+	if image == "" {
+		image = defaultTracingAgentImage
+	}
+	podSpec.Containers = append(podSpec.Containers, v1.Container{
+		Name:            tracingContainerName,
+		Image:           image,
+		ImagePullPolicy: controller.GetContainerImagePullPolicy(c.clusterSpec.CephVersion.ImagePullPolicy),
+		// rgw traces all the requests and the agent has no sampling of its own, so the sampling rate is not
+		// configured here but in the collector, which can also sample the traces by their latency or errors
+		Args: []string{
+			// the agent only receives the traces of the rgw daemon of the pod
+			fmt.Sprintf("--processor.jaeger-compact.server-host-port=127.0.0.1:%d", tracingAgentPort),
+		},
+		Env: []v1.EnvVar{{
+			Name: tracingAgentCollectorEnvVarName,
+			ValueFrom: &v1.EnvVarSource{SecretKeyRef: &v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: tracing.CollectorSecretName},
+				Key:                  tracingCollectorEndpointKey,
+			}},
+		}},
+		Resources: tracing.Resources,
+	})
+
+	// the variables of the secret are only read when the containers start
+	if podTemplateSpec.ObjectMeta.Annotations == nil {
+		podTemplateSpec.ObjectMeta.Annotations = map[string]string{}
+	}
+	podTemplateSpec.ObjectMeta.Annotations[tracingCollectorHashAnnotation] = k8sutil.Hash(string(endpoint))
+	return nil
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"context"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	clienttest "github.com/rook/rook/pkg/daemon/ceph/client/test"
+	cephconfig "github.com/rook/rook/pkg/operator/ceph/config"
+	testop "github.com/rook/rook/pkg/operator/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTracingSidecar(t *testing.T) {
+	clientset := testop.New(t, 1)
+	c := &clusterConfig{
+		store:       simpleStore(),
+		rookImage:   "rook/ceph:myversion",
+		clusterSpec: &cephv1.ClusterSpec{CephVersion: cephv1.CephVersionSpec{Image: "quay.io/ceph/ceph:v17"}},
+		clusterInfo: clienttest.CreateTestClusterInfo(1),
+		context:     &clusterd.Context{Clientset: clientset},
+		DataPathMap: cephconfig.NewStatelessDaemonDataPathMap(cephconfig.RgwType, "default", "rook-ceph", "/var/lib/rook/"),
+	}
+	rgwConfig := &rgwConfig{ResourceName: "rook-ceph-rgw-default", DaemonID: "default"}
+
+	t.Run("disabled", func(t *testing.T) {
+		podTemplate, err := c.makeRGWPodSpec(rgwConfig)
+		assert.NoError(t, err)
+		require.Len(t, podTemplate.Spec.Containers, 1)
+		assert.NotContains(t, podTemplate.Spec.Containers[0].Args, "--jaeger-tracing-enable=true")
+		assert.NotContains(t, podTemplate.Annotations, tracingCollectorHashAnnotation)
+	})
+
+	c.store.Spec.Gateway.Tracing = &cephv1.GatewayTracingSpec{Enabled: true, CollectorSecretName: "jaeger-collector"}
+
+	t.Run("the secret of the collector is required", func(t *testing.T) {
+		_, err := c.makeRGWPodSpec(rgwConfig)
+		assert.Error(t, err)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		secret := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "jaeger-collector", Namespace: c.store.Namespace},
+			Data:       map[string][]byte{"endpoint": []byte("jaeger-collector.tracing:14250")},
+		}
+		_, err := clientset.CoreV1().Secrets(c.store.Namespace).Create(context.TODO(), secret, metav1.CreateOptions{})
+		assert.NoError(t, err)
+
+		podTemplate, err := c.makeRGWPodSpec(rgwConfig)
+		assert.NoError(t, err)
+		require.Len(t, podTemplate.Spec.Containers, 2)
+
+		rgw := podTemplate.Spec.Containers[0]
+		assert.Contains(t, rgw.Args, "--jaeger-tracing-enable=true")
+		assert.Contains(t, rgw.Args, "--jaeger-agent-port=6799")
+
+		sidecar := podTemplate.Spec.Containers[1]
+		assert.Equal(t, tracingContainerName, sidecar.Name)
+		assert.Equal(t, defaultTracingAgentImage, sidecar.Image)
+		assert.Equal(t, []string{"--processor.jaeger-compact.server-host-port=127.0.0.1:6799"}, sidecar.Args)
+		require.Len(t, sidecar.Env, 1)
+		assert.Equal(t, "jaeger-collector", sidecar.Env[0].ValueFrom.SecretKeyRef.Name)
+		assert.Equal(t, "endpoint", sidecar.Env[0].ValueFrom.SecretKeyRef.Key)
+
+		// the pods are restarted when the endpoint changes
+		hash := podTemplate.Annotations[tracingCollectorHashAnnotation]
+		assert.NotEmpty(t, hash)
+		secret.Data["endpoint"] = []byte("otel-collector.tracing:14250")
+		_, err = clientset.CoreV1().Secrets(c.store.Namespace).Update(context.TODO(), secret, metav1.UpdateOptions{})
+		assert.NoError(t, err)
+		podTemplate, err = c.makeRGWPodSpec(rgwConfig)
+		assert.NoError(t, err)
+		assert.NotEqual(t, hash, podTemplate.Annotations[tracingCollectorHashAnnotation])
+	})
+}
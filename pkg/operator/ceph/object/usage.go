@@ -0,0 +1,260 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/ceph/go-ceph/rgw/admin"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	defaultUsageSummaryInterval = 5 * time.Minute
+	// the status is stored in etcd, so only the users and buckets with the largest usage are listed
+	defaultUsageSummaryMaxUsers          = 20
+	defaultUsageSummaryMaxBucketsPerUser = 10
+)
+
+// usageChecker periodically reports the quotas and usage of the users and buckets of an object store in its
+// status, so they can be consumed without access to the admin ops API
+type usageChecker struct {
+	opsCtx         *AdminOpsContext
+	client         client.Client
+	namespacedName types.NamespacedName
+	interval       time.Duration
+	maxUsers       int
+	maxBuckets     int
+}
+
+type objectStoreUsage struct {
+	internalCtx    context.Context
+	internalCancel context.CancelFunc
+	interval       time.Duration
+	maxUsers       int
+	maxBuckets     int
+}
+
+func newUsageChecker(opsCtx *AdminOpsContext, client client.Client, namespacedName types.NamespacedName, spec cephv1.ObjectUsageSummarySpec) *usageChecker {
+	c := &usageChecker{
+		opsCtx:         opsCtx,
+		client:         client,
+		namespacedName: namespacedName,
+		interval:       defaultUsageSummaryInterval,
+		maxUsers:       defaultUsageSummaryMaxUsers,
+		maxBuckets:     defaultUsageSummaryMaxBucketsPerUser,
+	}
+	if spec.Interval != nil {
+		c.interval = spec.Interval.Duration
+	}
+	if spec.MaxUsers > 0 {
+		c.maxUsers = spec.MaxUsers
+	}
+	if spec.MaxBucketsPerUser > 0 {
+		c.maxBuckets = spec.MaxBucketsPerUser
+	}
+	return c
+}
+
+// reconcileUsageSummary starts the routine reporting the usage summary of the store if it is enabled, or stops
+// the routine and removes the summary from the status if it is disabled
+func (r *ReconcileCephObjectStore) reconcileUsageSummary(cephObjectStore *cephv1.CephObjectStore, opsCtx *AdminOpsContext) {
+	namespacedName := types.NamespacedName{Namespace: cephObjectStore.Namespace, Name: cephObjectStore.Name}
+	checker := newUsageChecker(opsCtx, r.client, namespacedName, cephObjectStore.Spec.UsageSummary)
+
+	if !cephObjectStore.Spec.UsageSummary.Enabled {
+		if r.cancelUsageSummary(namespacedName) {
+			checker.updateUsageSummary(nil)
+		}
+		return
+	}
+
+	if r.objectStoreUsages == nil {
+		r.objectStoreUsages = make(map[string]*objectStoreUsage)
+	}
+	if usage, ok := r.objectStoreUsages[namespacedName.String()]; ok {
+		if usage.interval == checker.interval && usage.maxUsers == checker.maxUsers && usage.maxBuckets == checker.maxBuckets {
+			logger.Debugf("usage summary routine of object store %q already running", namespacedName.String())
+			return
+		}
+		r.cancelUsageSummary(namespacedName)
+	}
+
+	internalCtx, internalCancel := context.WithCancel(r.opManagerContext)
+	r.objectStoreUsages[namespacedName.String()] = &objectStoreUsage{
+		internalCtx:    internalCtx,
+		internalCancel: internalCancel,
+		interval:       checker.interval,
+		maxUsers:       checker.maxUsers,
+		maxBuckets:     checker.maxBuckets,
+	}
+	go checker.checkUsage(internalCtx)
+}
+
+// cancelUsageSummary stops the usage summary routine of the store. It returns false if the routine was not running.
+func (r *ReconcileCephObjectStore) cancelUsageSummary(namespacedName types.NamespacedName) bool {
+	usage, ok := r.objectStoreUsages[namespacedName.String()]
+	if !ok {
+		return false
+	}
+	usage.internalCancel()
+	delete(r.objectStoreUsages, namespacedName.String())
+	return true
+}
+
+// checkUsage periodically refreshes the usage summary until the context is canceled
+func (c *usageChecker) checkUsage(ctx context.Context) {
+	logger.Infof("reporting usage summary of object store %q every %s", c.namespacedName.String(), c.interval.String())
+	for {
+		summary, err := c.usageSummary(ctx)
+		if err != nil {
+			logger.Debugf("failed to get usage summary of object store %q. %v", c.namespacedName.String(), err)
+			summary = &cephv1.ObjectUsageSummary{Details: err.Error()}
+		}
+		summary.LastChecked = time.Now().UTC().Format(time.RFC3339)
+		if ctx.Err() == nil {
+			c.updateUsageSummary(summary)
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Infof("stopping usage summary of object store %q", c.namespacedName.String())
+			return
+		case <-time.After(c.interval):
+		}
+	}
+}
+
+// usageSummary sums the usage of all the users and buckets of the store, and lists the users with the largest usage
+// with their quotas, and the buckets they own with the largest usage with their quotas
+func (c *usageChecker) usageSummary(ctx context.Context) (*cephv1.ObjectUsageSummary, error) {
+	client := c.opsCtx.AdminOpsClient
+	userIDs, err := client.GetUsers(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list users")
+	}
+
+	summary := &cephv1.ObjectUsageSummary{}
+	for _, id := range *userIDs {
+		user, err := client.GetUser(ctx, admin.User{ID: id})
+		if err != nil {
+			if errors.Is(err, admin.ErrNoSuchUser) {
+				// the user was removed since the users were listed
+				continue
+			}
+			return nil, errors.Wrapf(err, "failed to get user %q", id)
+		}
+		buckets, err := client.ListUsersBucketsWithStat(ctx, id)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list buckets of user %q", id)
+		}
+
+		userUsage := cephv1.ObjectUserUsage{
+			ID:         id,
+			MaxBuckets: user.MaxBuckets,
+			Quota:      quotaStatus(user.UserQuota),
+		}
+		for _, bucket := range buckets {
+			bucketUsage := cephv1.ObjectBucketUsage{
+				Name:  bucket.Bucket,
+				Quota: quotaStatus(bucket.BucketQuota),
+			}
+			if bucket.Usage.RgwMain.Size != nil {
+				bucketUsage.Size = *bucket.Usage.RgwMain.Size
+			}
+			if bucket.Usage.RgwMain.NumObjects != nil {
+				bucketUsage.Objects = *bucket.Usage.RgwMain.NumObjects
+			}
+			userUsage.Size += bucketUsage.Size
+			userUsage.Objects += bucketUsage.Objects
+			userUsage.Buckets = append(userUsage.Buckets, bucketUsage)
+		}
+		userUsage.BucketCount = len(userUsage.Buckets)
+		sort.Slice(userUsage.Buckets, func(i, j int) bool {
+			return largerUsage(userUsage.Buckets[i].Size, userUsage.Buckets[j].Size, userUsage.Buckets[i].Name, userUsage.Buckets[j].Name)
+		})
+		if len(userUsage.Buckets) > c.maxBuckets {
+			userUsage.Buckets = userUsage.Buckets[:c.maxBuckets]
+		}
+
+		summary.TotalUsers++
+		summary.TotalBuckets += userUsage.BucketCount
+		summary.TotalSize += userUsage.Size
+		summary.TotalObjects += userUsage.Objects
+		summary.Users = append(summary.Users, userUsage)
+	}
+	sort.Slice(summary.Users, func(i, j int) bool {
+		return largerUsage(summary.Users[i].Size, summary.Users[j].Size, summary.Users[i].ID, summary.Users[j].ID)
+	})
+	if len(summary.Users) > c.maxUsers {
+		summary.Users = summary.Users[:c.maxUsers]
+	}
+
+	return summary, nil
+}
+
+// largerUsage orders by decreasing size, then by name so the order is stable between the refreshes
+func largerUsage(size1, size2 uint64, name1, name2 string) bool {
+	if size1 != size2 {
+		return size1 > size2
+	}
+	return name1 < name2
+}
+
+// quotaStatus returns the limits of the quota, or nil if the quota is disabled. Negative limits are unlimited.
+func quotaStatus(quota admin.QuotaSpec) *cephv1.ObjectQuotaStatus {
+	if quota.Enabled == nil || !*quota.Enabled {
+		return nil
+	}
+	status := &cephv1.ObjectQuotaStatus{}
+	if quota.MaxSize != nil && *quota.MaxSize >= 0 {
+		status.MaxSize = quota.MaxSize
+	}
+	if quota.MaxObjects != nil && *quota.MaxObjects >= 0 {
+		status.MaxObjects = quota.MaxObjects
+	}
+	return status
+}
+
+func (c *usageChecker) updateUsageSummary(summary *cephv1.ObjectUsageSummary) {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		objectStore := &cephv1.CephObjectStore{}
+		if err := c.client.Get(c.opsCtx.clusterInfo.Context, c.namespacedName, objectStore); err != nil {
+			if kerrors.IsNotFound(err) {
+				logger.Debug("CephObjectStore resource not found. Ignoring since object must be deleted.")
+				return nil
+			}
+			return errors.Wrapf(err, "failed to retrieve object store %q to update usage summary", c.namespacedName.String())
+		}
+		if objectStore.Status == nil {
+			return nil
+		}
+		objectStore.Status.UsageSummary = summary
+		return reporting.UpdateStatus(c.client, objectStore)
+	})
+	if err != nil {
+		logger.Errorf("failed to update usage summary of object store %q. %v", c.namespacedName.String(), err)
+	}
+}
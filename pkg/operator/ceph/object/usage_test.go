@@ -0,0 +1,108 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/ceph/go-ceph/rgw/admin"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestUsageSummary(t *testing.T) {
+	mockClient := &MockClient{
+		MockDo: func(req *http.Request) (*http.Response, error) {
+			body := ""
+			switch {
+			case req.URL.Path == "rook-ceph-rgw-my-store.mycluster.svc/admin/metadata/user":
+				body = `["bob","tenant$alice"]`
+			case req.URL.Path == "rook-ceph-rgw-my-store.mycluster.svc/admin/user" && req.URL.Query().Get("uid") == "bob":
+				body = `{"user_id":"bob","max_buckets":1000,"user_quota":{"enabled":false,"max_size":-1,"max_objects":-1}}`
+			case req.URL.Path == "rook-ceph-rgw-my-store.mycluster.svc/admin/user" && req.URL.Query().Get("uid") == "tenant$alice":
+				body = `{"user_id":"tenant$alice","max_buckets":10,"user_quota":{"enabled":true,"max_size":1073741824,"max_objects":-1}}`
+			case req.URL.Path == "rook-ceph-rgw-my-store.mycluster.svc/admin/bucket" && req.URL.Query().Get("uid") == "bob":
+				body = `[]`
+			case req.URL.Path == "rook-ceph-rgw-my-store.mycluster.svc/admin/bucket" && req.URL.Query().Get("uid") == "tenant$alice":
+				body = `[
+					{"bucket":"logs","owner":"tenant$alice","usage":{"rgw.main":{"size":100,"num_objects":2}},"bucket_quota":{"enabled":true,"max_size":-1,"max_objects":10}},
+					{"bucket":"data","owner":"tenant$alice","usage":{"rgw.main":{"size":1000,"num_objects":5}},"bucket_quota":{"enabled":false,"max_size":-1,"max_objects":-1}}
+				]`
+			default:
+				return nil, fmt.Errorf("unexpected request: %q. method %q. path %q", req.URL.RawQuery, req.Method, req.URL.Path)
+			}
+			return &http.Response{StatusCode: 200, Body: io.NopCloser(bytes.NewReader([]byte(body)))}, nil
+		},
+	}
+	client, err := admin.New("rook-ceph-rgw-my-store.mycluster.svc", "53S6B9S809NUP19IJ2K3", "1bXPegzsGClvoGAiJdHQD1uOW2sQBLAZM9j9VtXR", mockClient)
+	assert.NoError(t, err)
+
+	c := newUsageChecker(&AdminOpsContext{AdminOpsClient: client}, nil, types.NamespacedName{Namespace: "ns", Name: "my-store"}, cephv1.ObjectUsageSummarySpec{Enabled: true})
+	assert.Equal(t, defaultUsageSummaryInterval, c.interval)
+
+	summary, err := c.usageSummary(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1100), summary.TotalSize)
+	assert.Equal(t, uint64(7), summary.TotalObjects)
+	assert.Equal(t, 2, summary.TotalUsers)
+	assert.Equal(t, 2, summary.TotalBuckets)
+	assert.Len(t, summary.Users, 2)
+
+	// users are sorted by decreasing size
+	alice := summary.Users[0]
+	assert.Equal(t, "tenant$alice", alice.ID)
+	assert.Equal(t, 10, *alice.MaxBuckets)
+	assert.Equal(t, int64(1073741824), *alice.Quota.MaxSize)
+	assert.Nil(t, alice.Quota.MaxObjects)
+	assert.Equal(t, uint64(1100), alice.Size)
+	assert.Equal(t, uint64(7), alice.Objects)
+	assert.Equal(t, 2, alice.BucketCount)
+	assert.Len(t, alice.Buckets, 2)
+	// buckets are sorted by decreasing size
+	assert.Equal(t, "data", alice.Buckets[0].Name)
+	assert.Nil(t, alice.Buckets[0].Quota)
+	assert.Equal(t, "logs", alice.Buckets[1].Name)
+	assert.Nil(t, alice.Buckets[1].Quota.MaxSize)
+	assert.Equal(t, int64(10), *alice.Buckets[1].Quota.MaxObjects)
+
+	bob := summary.Users[1]
+	assert.Equal(t, "bob", bob.ID)
+	assert.Nil(t, bob.Quota)
+	assert.Equal(t, 0, bob.BucketCount)
+	assert.Empty(t, bob.Buckets)
+
+	t.Run("the lists are capped but the totals cover everything", func(t *testing.T) {
+		c := newUsageChecker(&AdminOpsContext{AdminOpsClient: client}, nil, types.NamespacedName{Namespace: "ns", Name: "my-store"},
+			cephv1.ObjectUsageSummarySpec{Enabled: true, MaxUsers: 1, MaxBucketsPerUser: 1})
+		summary, err := c.usageSummary(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1100), summary.TotalSize)
+		assert.Equal(t, 2, summary.TotalUsers)
+		assert.Equal(t, 2, summary.TotalBuckets)
+		assert.Len(t, summary.Users, 1)
+		assert.Equal(t, "tenant$alice", summary.Users[0].ID)
+		assert.Equal(t, 2, summary.Users[0].BucketCount)
+		assert.Len(t, summary.Users[0].Buckets, 1)
+		assert.Equal(t, "data", summary.Users[0].Buckets[0].Name)
+	})
+}
@@ -21,6 +21,8 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
+	"time"
 
 	"github.com/ceph/go-ceph/rgw/admin"
 	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
@@ -78,12 +80,14 @@ type ReconcileObjectStoreUser struct {
 	clusterInfo      *cephclient.ClusterInfo
 	opManagerContext context.Context
 	recorder         record.EventRecorder
+	swiftSpec        *cephv1.SwiftSpec
+	swiftKeys        map[string]string
 }
 
 // Add creates a new CephObjectStoreUser Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
-	return add(mgr, newReconciler(mgr, context, opManagerContext))
+	return add(mgr, newReconciler(mgr, context, opManagerContext), opConfig.CanWatchSecrets)
 }
 
 // newReconciler returns a new reconcile.Reconciler
@@ -97,7 +101,7 @@ func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerCont
 	}
 }
 
-func add(mgr manager.Manager, r reconcile.Reconciler) error {
+func add(mgr manager.Manager, r reconcile.Reconciler, watchSecrets bool) error {
 	// Create a new controller
 	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
 	if err != nil {
@@ -111,7 +115,12 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
-	// Watch secrets
+	// Watch secrets, unless the operator is not allowed to, in which case changes to the secrets are only
+	// reverted on the next reconcile
+	if !watchSecrets {
+		logger.Info("not watching secrets since the operator is not allowed to watch them")
+		return nil
+	}
 	err = c.Watch(&source.Kind{Type: &corev1.Secret{TypeMeta: metav1.TypeMeta{Kind: "Secret", APIVersion: corev1.SchemeGroupVersion.String()}}}, &handler.EnqueueRequestForOwner{
 		IsController: true,
 		OwnerType:    &cephv1.CephObjectStoreUser{},
@@ -163,8 +172,11 @@ func (r *ReconcileObjectStoreUser) reconcile(request reconcile.Request) (reconci
 		r.updateStatus(k8sutil.ObservedGenerationNotAvailable, request.NamespacedName, k8sutil.EmptyStatus)
 	}
 
-	// Make sure a CephCluster is present otherwise do nothing
-	cephCluster, isReadyToReconcile, cephClusterExists, reconcileResponse := opcontroller.IsReadyToReconcile(r.opManagerContext, r.client, request.NamespacedName, controllerName)
+	// Make sure a CephCluster is present otherwise do nothing. The cluster is in the namespace of the store, which may
+	// differ from the namespace of the user.
+	storeName := cephObjectStoreUser.StoreNamespacedName()
+	clusterName := types.NamespacedName{Name: request.Name, Namespace: storeName.Namespace}
+	cephCluster, isReadyToReconcile, cephClusterExists, reconcileResponse := opcontroller.IsReadyToReconcile(r.opManagerContext, r.client, clusterName, controllerName)
 	if !isReadyToReconcile {
 		// This handles the case where the Ceph Cluster is gone and we want to delete that CR
 		// We skip the deleteUser() function since everything is gone already
@@ -187,7 +199,7 @@ func (r *ReconcileObjectStoreUser) reconcile(request reconcile.Request) (reconci
 	r.cephClusterSpec = &cephCluster.Spec
 
 	// Populate clusterInfo during each reconcile
-	r.clusterInfo, _, _, err = opcontroller.LoadClusterInfo(r.context, r.opManagerContext, request.NamespacedName.Namespace, r.cephClusterSpec)
+	r.clusterInfo, _, _, err = opcontroller.LoadClusterInfo(r.context, r.opManagerContext, storeName.Namespace, r.cephClusterSpec)
 	if err != nil {
 		return reconcile.Result{}, *cephObjectStoreUser, errors.Wrap(err, "failed to populate cluster info")
 	}
@@ -207,7 +219,7 @@ func (r *ReconcileObjectStoreUser) reconcile(request reconcile.Request) (reconci
 			return reconcile.Result{}, *cephObjectStoreUser, nil
 		}
 		logger.Debugf("ObjectStore resource not ready in namespace %q, retrying in %q. %v",
-			request.NamespacedName.Namespace, opcontroller.WaitForRequeueIfCephClusterNotReady.RequeueAfter.String(), err)
+			storeName.Namespace, opcontroller.WaitForRequeueIfCephClusterNotReady.RequeueAfter.String(), err)
 		r.updateStatus(k8sutil.ObservedGenerationNotAvailable, request.NamespacedName, k8sutil.ReconcileFailedStatus)
 		return opcontroller.WaitForRequeueIfCephClusterNotReady, *cephObjectStoreUser, nil
 	}
@@ -240,10 +252,32 @@ func (r *ReconcileObjectStoreUser) reconcile(request reconcile.Request) (reconci
 	// validate the user settings
 	err = r.validateUser(cephObjectStoreUser)
 	if err != nil {
+		reporting.ReportValidationFailed(r.recorder, cephObjectStoreUser, err)
 		r.updateStatus(k8sutil.ObservedGenerationNotAvailable, request.NamespacedName, k8sutil.ReconcileFailedStatus)
 		return reconcile.Result{}, *cephObjectStoreUser, errors.Wrapf(err, "invalid pool CR %q spec", cephObjectStoreUser.Name)
 	}
 
+	// check the store allows the user in its namespace
+	err = r.checkStoreAllowsUser(cephObjectStoreUser)
+	if err != nil {
+		r.updateStatus(k8sutil.ObservedGenerationNotAvailable, request.NamespacedName, k8sutil.ReconcileFailedStatus)
+		return reconcile.Result{}, *cephObjectStoreUser, err
+	}
+
+	// EXPIRE: the user outlived its time to live
+	expiration := expirationTime(cephObjectStoreUser)
+	if expiration != nil && !timeNow().Before(*expiration) {
+		err = r.expireUser(cephObjectStoreUser)
+		if err != nil {
+			r.updateStatus(k8sutil.ObservedGenerationNotAvailable, request.NamespacedName, k8sutil.ReconcileFailedStatus)
+			return reconcile.Result{}, *cephObjectStoreUser, err
+		}
+		if cephObjectStoreUser.Spec.Expiration.Action != cephv1.ObjectUserExpirationDelete {
+			r.updateStatus(observedGeneration, request.NamespacedName, expiredStatus)
+		}
+		return reconcile.Result{}, *cephObjectStoreUser, nil
+	}
+
 	// CREATE/UPDATE CEPH USER
 	reconcileResponse, err = r.reconcileCephUser(cephObjectStoreUser)
 	if err != nil {
@@ -262,6 +296,12 @@ func (r *ReconcileObjectStoreUser) reconcile(request reconcile.Request) (reconci
 	// Set Ready status, we are done reconciling
 	r.updateStatus(observedGeneration, request.NamespacedName, k8sutil.ReadyStatus)
 
+	// Requeue when the user expires
+	if expiration != nil {
+		logger.Debugf("done reconciling, user %q expires at %s", cephObjectStoreUser.Name, expiration.Format(time.RFC3339))
+		return reconcile.Result{RequeueAfter: expiration.Sub(timeNow())}, *cephObjectStoreUser, nil
+	}
+
 	// Return and do not requeue
 	logger.Debug("done reconciling")
 	return reconcile.Result{}, *cephObjectStoreUser, nil
@@ -295,6 +335,10 @@ func (r *ReconcileObjectStoreUser) createOrUpdateCephUser(u *cephv1.CephObjectSt
 		}
 	}
 
+	if err := r.resumeUser(u, user); err != nil {
+		return err
+	}
+
 	// Update max bucket if necessary
 	logger.Tracef("user capabilities(id: %s, caps: %#v, user caps: %s, op mask: %s)",
 		user.ID, user.Caps, user.UserCaps, user.OpMask)
@@ -341,7 +385,7 @@ func (r *ReconcileObjectStoreUser) createOrUpdateCephUser(u *cephv1.CephObjectSt
 		}
 	}
 	userQuota := admin.QuotaSpec{
-		UID:        u.Name,
+		UID:        r.userConfig.ID,
 		Enabled:    &quotaEnabled,
 		MaxSize:    &maxSize,
 		MaxObjects: &maxObjects,
@@ -351,6 +395,15 @@ func (r *ReconcileObjectStoreUser) createOrUpdateCephUser(u *cephv1.CephObjectSt
 		return errors.Wrapf(err, "failed to set quotas for user %q", u.Name)
 	}
 
+	err = object.SetRateLimit(&r.objContext.Context, object.RateLimitScopeUser, r.userConfig.ID, object.RateLimitFromSpec(u.Spec.RateLimit))
+	if err != nil {
+		return errors.Wrapf(err, "failed to set rate limit for user %q", u.Name)
+	}
+
+	if err := r.reconcileSubUsers(u, user); err != nil {
+		return err
+	}
+
 	// Set access and secret key
 	if r.userConfig.Keys == nil {
 		r.userConfig.Keys = make([]admin.UserKeySpec, 1)
@@ -365,12 +418,12 @@ func (r *ReconcileObjectStoreUser) createOrUpdateCephUser(u *cephv1.CephObjectSt
 func (r *ReconcileObjectStoreUser) initializeObjectStoreContext(u *cephv1.CephObjectStoreUser) error {
 	err := r.objectStoreInitialized(u)
 	if err != nil {
-		return errors.Wrapf(err, "failed to detect if object store %q is initialized", u.Spec.Store)
+		return errors.Wrapf(err, "failed to detect if object store %q is initialized", u.StoreNamespacedName())
 	}
 
-	store, err := r.getObjectStore(u.Spec.Store)
+	store, err := r.getObjectStore(u.StoreNamespacedName())
 	if err != nil {
-		return errors.Wrapf(err, "failed to get object store %q", u.Spec.Store)
+		return errors.Wrapf(err, "failed to get object store %q", u.StoreNamespacedName())
 	}
 
 	objContext, err := object.NewMultisiteContext(r.context, r.clusterInfo, store)
@@ -387,6 +440,7 @@ func (r *ReconcileObjectStoreUser) initializeObjectStoreContext(u *cephv1.CephOb
 		return errors.Wrap(err, "failed to initialized rgw admin ops client api")
 	}
 	r.objContext = opsContext
+	r.swiftSpec = store.Spec.Protocols.Swift
 
 	return nil
 }
@@ -408,7 +462,7 @@ func generateUserConfig(user *cephv1.CephObjectStoreUser) admin.User {
 
 	// create the user
 	userConfig := admin.User{
-		ID:          user.Name,
+		ID:          user.CephUserID(),
 		DisplayName: displayName,
 	}
 
@@ -419,33 +473,64 @@ func generateUserConfig(user *cephv1.CephObjectStoreUser) admin.User {
 	}
 
 	if user.Spec.Capabilities != nil {
+		caps := profileCapabilities(user.Spec.Capabilities.Profile)
 		if user.Spec.Capabilities.User != "" {
-			userConfig.UserCaps += fmt.Sprintf("users=%s;", user.Spec.Capabilities.User)
+			caps.User = user.Spec.Capabilities.User
 		}
 		if user.Spec.Capabilities.Bucket != "" {
-			userConfig.UserCaps += fmt.Sprintf("buckets=%s;", user.Spec.Capabilities.Bucket)
+			caps.Bucket = user.Spec.Capabilities.Bucket
 		}
 		if user.Spec.Capabilities.MetaData != "" {
-			userConfig.UserCaps += fmt.Sprintf("metadata=%s;", user.Spec.Capabilities.MetaData)
+			caps.MetaData = user.Spec.Capabilities.MetaData
 		}
 		if user.Spec.Capabilities.Usage != "" {
-			userConfig.UserCaps += fmt.Sprintf("usage=%s;", user.Spec.Capabilities.Usage)
+			caps.Usage = user.Spec.Capabilities.Usage
 		}
 		if user.Spec.Capabilities.Zone != "" {
-			userConfig.UserCaps += fmt.Sprintf("zone=%s;", user.Spec.Capabilities.Zone)
+			caps.Zone = user.Spec.Capabilities.Zone
+		}
+
+		if caps.User != "" {
+			userConfig.UserCaps += fmt.Sprintf("users=%s;", caps.User)
+		}
+		if caps.Bucket != "" {
+			userConfig.UserCaps += fmt.Sprintf("buckets=%s;", caps.Bucket)
+		}
+		if caps.MetaData != "" {
+			userConfig.UserCaps += fmt.Sprintf("metadata=%s;", caps.MetaData)
+		}
+		if caps.Usage != "" {
+			userConfig.UserCaps += fmt.Sprintf("usage=%s;", caps.Usage)
+		}
+		if caps.Zone != "" {
+			userConfig.UserCaps += fmt.Sprintf("zone=%s;", caps.Zone)
 		}
 	}
 
 	return userConfig
 }
 
+// profileCapabilities returns the admin capabilities of a named profile
+func profileCapabilities(profile cephv1.CapsProfile) cephv1.ObjectUserCapSpec {
+	switch profile {
+	case cephv1.CapsProfileRGWAdminOps:
+		return cephv1.ObjectUserCapSpec{User: "*", Bucket: "*", MetaData: "*", Usage: "*", Zone: "*"}
+	case cephv1.CapsProfileMonitoringReadOnly:
+		return cephv1.ObjectUserCapSpec{User: "read", Bucket: "read", MetaData: "read", Usage: "read", Zone: "read"}
+	}
+	return cephv1.ObjectUserCapSpec{}
+}
+
 func generateCephUserSecretName(u *cephv1.CephObjectStoreUser) string {
-	return fmt.Sprintf("rook-ceph-object-user-%s-%s", u.Spec.Store, u.Name)
+	return fmt.Sprintf("rook-ceph-object-user-%s-%s", u.StoreNamespacedName().Name, u.Name)
 }
 
 func generateStatusInfo(u *cephv1.CephObjectStoreUser) map[string]string {
 	m := make(map[string]string)
 	m["secretName"] = generateCephUserSecretName(u)
+	if expiration := expirationTime(u); expiration != nil {
+		m["expirationTime"] = expiration.UTC().Format(time.RFC3339)
+	}
 	return m
 }
 
@@ -463,8 +548,8 @@ func (r *ReconcileObjectStoreUser) generateCephUserSecret(u *cephv1.CephObjectSt
 			Labels: map[string]string{
 				"app":               appName,
 				"user":              u.Name,
-				"rook_cluster":      u.Namespace,
-				"rook_object_store": u.Spec.Store,
+				"rook_cluster":      u.StoreNamespacedName().Namespace,
+				"rook_object_store": u.StoreNamespacedName().Name,
 			},
 		},
 		StringData: secrets,
@@ -489,11 +574,15 @@ func (r *ReconcileObjectStoreUser) reconcileCephUserSecret(cephObjectStoreUser *
 		return reconcile.Result{}, errors.Wrapf(err, "failed to create or update ceph object user %q secret", secret.Name)
 	}
 
+	if err := r.reconcileSubUserSecrets(cephObjectStoreUser); err != nil {
+		return reconcile.Result{}, err
+	}
+
 	return reconcile.Result{}, nil
 }
 
 func (r *ReconcileObjectStoreUser) objectStoreInitialized(cephObjectStoreUser *cephv1.CephObjectStoreUser) error {
-	cephObjectStore, err := r.getObjectStore(cephObjectStoreUser.Spec.Store)
+	cephObjectStore, err := r.getObjectStore(cephObjectStoreUser.StoreNamespacedName())
 	if err != nil {
 		return err
 	}
@@ -521,25 +610,36 @@ func (r *ReconcileObjectStoreUser) objectStoreInitialized(cephObjectStoreUser *c
 	return errors.New("no rgw pod found")
 }
 
-func (r *ReconcileObjectStoreUser) getObjectStore(storeName string) (*cephv1.CephObjectStore, error) {
+func (r *ReconcileObjectStoreUser) getObjectStore(storeName types.NamespacedName) (*cephv1.CephObjectStore, error) {
 	// check if CephObjectStore CR is created
-	objectStores := &cephv1.CephObjectStoreList{}
-	err := r.client.List(r.opManagerContext, objectStores)
+	store := &cephv1.CephObjectStore{}
+	err := r.client.Get(r.opManagerContext, storeName, store)
 	if err != nil {
 		if kerrors.IsNotFound(err) {
 			return nil, errors.Wrapf(err, "CephObjectStore %q could not be found", storeName)
 		}
 		return nil, errors.Wrap(err, "failed to get CephObjectStore")
 	}
+	logger.Infof("CephObjectStore %q found", storeName)
 
-	for _, store := range objectStores.Items {
-		if store.Name == storeName {
-			logger.Infof("CephObjectStore %q found", storeName)
-			return &store, nil
-		}
-	}
+	return store, nil
+}
 
-	return nil, errors.Errorf("CephObjectStore %q could not be found", storeName)
+// checkStoreAllowsUser returns an error if the user is in another namespace than its store, and the store does not
+// allow users in the namespace of the user
+func (r *ReconcileObjectStoreUser) checkStoreAllowsUser(u *cephv1.CephObjectStoreUser) error {
+	storeName := u.StoreNamespacedName()
+	if storeName.Namespace == u.Namespace {
+		return nil
+	}
+	store, err := r.getObjectStore(storeName)
+	if err != nil {
+		return err
+	}
+	if !store.Spec.AllowsUsersInNamespace(u.Namespace) {
+		return errors.Errorf("CephObjectStore %q does not allow users in namespace %q", storeName, u.Namespace)
+	}
+	return nil
 }
 
 func (r *ReconcileObjectStoreUser) getRgwPodList(cephObjectStoreUser *cephv1.CephObjectStoreUser) (*corev1.PodList, error) {
@@ -548,8 +648,8 @@ func (r *ReconcileObjectStoreUser) getRgwPodList(cephObjectStoreUser *cephv1.Cep
 	// check if ObjectStore is initialized
 	// rook does this by starting the RGW pod(s)
 	listOpts := []client.ListOption{
-		client.InNamespace(cephObjectStoreUser.Namespace),
-		client.MatchingLabels(labelsForRgw(cephObjectStoreUser.Spec.Store)),
+		client.InNamespace(cephObjectStoreUser.StoreNamespacedName().Namespace),
+		client.MatchingLabels(labelsForRgw(cephObjectStoreUser.StoreNamespacedName().Name)),
 	}
 
 	err := r.client.List(r.opManagerContext, pods, listOpts...)
@@ -565,7 +665,7 @@ func (r *ReconcileObjectStoreUser) getRgwPodList(cephObjectStoreUser *cephv1.Cep
 
 // Delete the user
 func (r *ReconcileObjectStoreUser) deleteUser(u *cephv1.CephObjectStoreUser) error {
-	err := r.objContext.AdminOpsClient.RemoveUser(r.opManagerContext, admin.User{ID: u.Name})
+	err := r.objContext.AdminOpsClient.RemoveUser(r.opManagerContext, admin.User{ID: u.CephUserID()})
 	if err != nil {
 		if errors.Is(err, admin.ErrNoSuchUser) {
 			logger.Warningf("user %q does not exist, nothing to remove", u.Name)
@@ -589,6 +689,22 @@ func (r *ReconcileObjectStoreUser) validateUser(u *cephv1.CephObjectStoreUser) e
 	if u.Spec.Store == "" {
 		return errors.New("missing store")
 	}
+	if storeName := u.StoreNamespacedName(); storeName.Namespace == "" || storeName.Name == "" || strings.Contains(storeName.Name, "/") {
+		return errors.Errorf("invalid store %q, expected \"<name>\" or \"<namespace>/<name>\"", u.Spec.Store)
+	}
+	if u.Spec.Expiration != nil && u.Spec.Expiration.TTL.Duration <= 0 {
+		return errors.New("the ttl of the expiration must be positive")
+	}
+	subUsers := map[string]bool{}
+	for _, subUser := range u.Spec.SubUsers {
+		if subUser.Name == "" {
+			return errors.New("missing subuser name")
+		}
+		if subUsers[subUser.Name] {
+			return errors.Errorf("duplicate subuser %q", subUser.Name)
+		}
+		subUsers[subUser.Name] = true
+	}
 	return nil
 }
 
@@ -34,6 +34,7 @@ import (
 	"github.com/rook/rook/pkg/operator/test"
 
 	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
 	cephobject "github.com/rook/rook/pkg/operator/ceph/object"
 	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/stretchr/testify/assert"
@@ -49,7 +50,8 @@ import (
 )
 
 const (
-	userCreateJSON = `{
+	rateLimitDisabledJSON = `{"user_ratelimit":{"max_read_ops":0,"max_write_ops":0,"max_read_bytes":0,"max_write_bytes":0,"enabled":false}}`
+	userCreateJSON        = `{
 	"user_id": "my-user",
 	"display_name": "my-user",
 	"email": "",
@@ -228,6 +230,9 @@ func TestCephObjectStoreUserController(t *testing.T) {
 				if args[0] == "user" {
 					return userCreateJSON, nil
 				}
+				if args[0] == "ratelimit" {
+					return rateLimitDisabledJSON, nil
+				}
 				return "", nil
 			},
 		}
@@ -346,6 +351,44 @@ func TestBuildUpdateStatusInfo(t *testing.T) {
 	assert.Equal(t, "rook-ceph-object-user-my-store-my-user", statusInfo["secretName"])
 }
 
+func TestCrossNamespaceUser(t *testing.T) {
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephObjectStore{}, &cephv1.CephObjectStoreList{})
+	objectStore := &cephv1.CephObjectStore{ObjectMeta: metav1.ObjectMeta{Name: store, Namespace: namespace}}
+	r := &ReconcileObjectStoreUser{
+		client:           fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(objectStore).Build(),
+		opManagerContext: context.TODO(),
+	}
+	u := &cephv1.CephObjectStoreUser{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "tenant-a"},
+		Spec:       cephv1.ObjectStoreUserSpec{Store: namespace + "/" + store},
+	}
+
+	// the user is created in the tenant of its namespace, with its secret in its namespace
+	assert.NoError(t, r.validateUser(u))
+	assert.Equal(t, "tenant-a$my-user", u.CephUserID())
+	assert.Equal(t, "tenant-a$my-user:swift", subUserID(u, "swift"))
+	assert.Equal(t, "rook-ceph-object-user-my-store-my-user", generateCephUserSecretName(u))
+
+	// the store must allow the namespace of the user
+	assert.Error(t, r.checkStoreAllowsUser(u))
+	objectStore.Spec.AllowUsersInNamespaces = []string{"tenant-a"}
+	r.client = fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(objectStore).Build()
+	assert.NoError(t, r.checkStoreAllowsUser(u))
+
+	// the users in the namespace of the store are left unchanged
+	u.Namespace = namespace
+	assert.Equal(t, "my-user", u.CephUserID())
+	objectStore.Spec.AllowUsersInNamespaces = nil
+	r.client = fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(objectStore).Build()
+	assert.NoError(t, r.checkStoreAllowsUser(u))
+
+	u.Spec.Store = "rook-ceph/my-store/extra"
+	assert.Error(t, r.validateUser(u))
+	u.Spec.Store = "/my-store"
+	assert.Error(t, r.validateUser(u))
+}
+
 func TestCreateOrUpdateCephUser(t *testing.T) {
 	// Set DEBUG logging
 	capnslog.SetGlobalLogLevel(capnslog.DEBUG)
@@ -425,9 +468,19 @@ func TestCreateOrUpdateCephUser(t *testing.T) {
 	}
 	adminClient, err := admin.New("rook-ceph-rgw-my-store.mycluster.svc", "53S6B9S809NUP19IJ2K3", "1bXPegzsGClvoGAiJdHQD1uOW2sQBLAZM9j9VtXR", mockClient)
 	assert.NoError(t, err)
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			if args[0] == "ratelimit" && args[1] == "get" {
+				return rateLimitDisabledJSON, nil
+			}
+			return "", nil
+		},
+	}
+	objContext := cephobject.NewContext(&clusterd.Context{Executor: executor}, cephclient.AdminTestClusterInfo(namespace), store)
 	userConfig := generateUserConfig(objectUser)
 	r := &ReconcileObjectStoreUser{
 		objContext: &cephobject.AdminOpsContext{
+			Context:        *objContext,
 			AdminOpsClient: adminClient,
 		},
 		userConfig:       &userConfig,
@@ -519,3 +572,19 @@ func TestCreateOrUpdateCephUser(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestGenerateUserConfigWithProfile(t *testing.T) {
+	objectUser := &cephv1.CephObjectStoreUser{
+		ObjectMeta: metav1.ObjectMeta{Name: "monitoring", Namespace: "rook-ceph"},
+		Spec: cephv1.ObjectStoreUserSpec{
+			Capabilities: &cephv1.ObjectUserCapSpec{Profile: cephv1.CapsProfileMonitoringReadOnly},
+		},
+	}
+	userConfig := generateUserConfig(objectUser)
+	assert.Equal(t, "users=read;buckets=read;metadata=read;usage=read;zone=read;", userConfig.UserCaps)
+
+	// the capabilities set explicitly override the profile
+	objectUser.Spec.Capabilities = &cephv1.ObjectUserCapSpec{Profile: cephv1.CapsProfileRGWAdminOps, Zone: "read"}
+	userConfig = generateUserConfig(objectUser)
+	assert.Equal(t, "users=*;buckets=*;metadata=*;usage=*;zone=read;", userConfig.UserCaps)
+}
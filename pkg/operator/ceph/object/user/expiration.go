@@ -0,0 +1,131 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package objectuser
+
+import (
+	"time"
+
+	"github.com/ceph/go-ceph/rgw/admin"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// the phase of the users that expired and were suspended
+	expiredStatus = "Expired"
+	// the annotation of the CephObjectStoreUsers whose user was suspended on expiration, so that only the users
+	// suspended by the operator are resumed when their expiration is extended
+	suspendedOnExpirationAnnotation = "ceph.rook.io/suspended-on-expiration"
+)
+
+// allow the current time to be overridden for unit testing
+var timeNow = time.Now
+
+// expirationTime returns the time the user expires at, or nil if the user does not expire
+func expirationTime(u *cephv1.CephObjectStoreUser) *time.Time {
+	if u.Spec.Expiration == nil {
+		return nil
+	}
+	expiration := u.CreationTimestamp.Add(u.Spec.Expiration.TTL.Duration)
+	return &expiration
+}
+
+// expireUser suspends the expired user and deletes its secrets, or deletes the CephObjectStoreUser, depending on the
+// action of the expiration. The user is deleted by the finalizer of the CephObjectStoreUser.
+func (r *ReconcileObjectStoreUser) expireUser(u *cephv1.CephObjectStoreUser) error {
+	if u.Spec.Expiration.Action == cephv1.ObjectUserExpirationDelete {
+		logger.Infof("deleting expired CephObjectStoreUser %q", u.Name)
+		if err := r.client.Delete(r.opManagerContext, u); err != nil && !kerrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete expired CephObjectStoreUser %q", u.Name)
+		}
+		return nil
+	}
+
+	user, err := r.objContext.AdminOpsClient.GetUser(r.opManagerContext, admin.User{ID: u.CephUserID()})
+	if err != nil && !errors.Is(err, admin.ErrNoSuchUser) {
+		return errors.Wrapf(err, "failed to get details from ceph object user %q", u.Name)
+	}
+	if err == nil && (user.Suspended == nil || *user.Suspended == 0) {
+		// the annotation is set first so the user is resumed even if the operator stops right after suspending it
+		if err := r.setSuspendedOnExpiration(u, true); err != nil {
+			return err
+		}
+		suspended := 1
+		if _, err := r.objContext.AdminOpsClient.ModifyUser(r.opManagerContext, admin.User{ID: u.CephUserID(), Suspended: &suspended}); err != nil {
+			return errors.Wrapf(err, "failed to suspend expired ceph object user %q", u.Name)
+		}
+		logger.Infof("suspended expired ceph object user %q", u.Name)
+	}
+
+	// the secrets of the user and of its subusers
+	secrets := &corev1.SecretList{}
+	err = r.client.List(r.opManagerContext, secrets, client.InNamespace(u.Namespace), client.MatchingLabels{
+		"app":               appName,
+		"user":              u.Name,
+		"rook_object_store": u.StoreNamespacedName().Name,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to list the secrets of expired ceph object user %q", u.Name)
+	}
+	for i := range secrets.Items {
+		if err := r.client.Delete(r.opManagerContext, &secrets.Items[i]); err != nil && !kerrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete secret %q of expired ceph object user %q", secrets.Items[i].Name, u.Name)
+		}
+		logger.Infof("deleted secret %q of expired ceph object user %q", secrets.Items[i].Name, u.Name)
+	}
+	return nil
+}
+
+// resumeUser resumes a user the operator suspended on expiration, when its expiration is extended or removed. The
+// users suspended by an admin are left suspended.
+func (r *ReconcileObjectStoreUser) resumeUser(u *cephv1.CephObjectStoreUser, user admin.User) error {
+	if _, ok := u.Annotations[suspendedOnExpirationAnnotation]; !ok {
+		return nil
+	}
+	if user.Suspended != nil && *user.Suspended != 0 {
+		suspended := 0
+		if _, err := r.objContext.AdminOpsClient.ModifyUser(r.opManagerContext, admin.User{ID: u.CephUserID(), Suspended: &suspended}); err != nil {
+			return errors.Wrapf(err, "failed to resume ceph object user %q", u.Name)
+		}
+		logger.Infof("resumed ceph object user %q since its expiration was extended", u.Name)
+	}
+	return r.setSuspendedOnExpiration(u, false)
+}
+
+// setSuspendedOnExpiration adds or removes the annotation of the CephObjectStoreUser recording that the operator
+// suspended its user on expiration
+func (r *ReconcileObjectStoreUser) setSuspendedOnExpiration(u *cephv1.CephObjectStoreUser, suspended bool) error {
+	if _, ok := u.Annotations[suspendedOnExpirationAnnotation]; ok == suspended {
+		return nil
+	}
+	patch := client.MergeFrom(u.DeepCopy())
+	if suspended {
+		if u.Annotations == nil {
+			u.Annotations = map[string]string{}
+		}
+		u.Annotations[suspendedOnExpirationAnnotation] = "true"
+	} else {
+		delete(u.Annotations, suspendedOnExpirationAnnotation)
+	}
+	if err := r.client.Patch(r.opManagerContext, u, patch); err != nil {
+		return errors.Wrapf(err, "failed to update the annotation %q of CephObjectStoreUser %q", suspendedOnExpirationAnnotation, u.Name)
+	}
+	return nil
+}
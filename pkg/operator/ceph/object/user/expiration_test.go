@@ -0,0 +1,161 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package objectuser
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ceph/go-ceph/rgw/admin"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephobject "github.com/rook/rook/pkg/operator/ceph/object"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestExpirationTime(t *testing.T) {
+	created := metav1.NewTime(time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC))
+	u := &cephv1.CephObjectStoreUser{ObjectMeta: metav1.ObjectMeta{Name: "my-user", CreationTimestamp: created}}
+	assert.Nil(t, expirationTime(u))
+
+	u.Spec.Expiration = &cephv1.ObjectUserExpirationSpec{TTL: metav1.Duration{Duration: 24 * time.Hour}}
+	assert.Equal(t, time.Date(2023, 6, 2, 12, 0, 0, 0, time.UTC), *expirationTime(u))
+	assert.Equal(t, "2023-06-02T12:00:00Z", generateStatusInfo(u)["expirationTime"])
+}
+
+func TestExpireUser(t *testing.T) {
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephObjectStoreUser{})
+	newUser := func(action cephv1.ObjectUserExpirationAction) *cephv1.CephObjectStoreUser {
+		return &cephv1.CephObjectStoreUser{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-user", Namespace: "rook-ceph"},
+			Spec: cephv1.ObjectStoreUserSpec{
+				Store:      "my-store",
+				Expiration: &cephv1.ObjectUserExpirationSpec{TTL: metav1.Duration{Duration: time.Hour}, Action: action},
+			},
+		}
+	}
+	secretLabels := map[string]string{"app": appName, "user": "my-user", "rook_object_store": "my-store"}
+	secrets := []runtime.Object{
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-object-user-my-store-my-user", Namespace: "rook-ceph", Labels: secretLabels}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-object-user-my-store-my-user-swift", Namespace: "rook-ceph", Labels: secretLabels}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: "rook-ceph"}},
+	}
+
+	t.Run("suspend", func(t *testing.T) {
+		requests := []*http.Request{}
+		mockClient := &cephobject.MockClient{
+			MockDo: func(req *http.Request) (*http.Response, error) {
+				requests = append(requests, req)
+				return &http.Response{StatusCode: 200, Body: io.NopCloser(bytes.NewReader([]byte(`{"user_id":"my-user","suspended":0}`)))}, nil
+			},
+		}
+		adminClient, err := admin.New("rook-ceph-rgw-my-store.mycluster.svc", "53S6B9S809NUP19IJ2K3", "1bXPegzsGClvoGAiJdHQD1uOW2sQBLAZM9j9VtXR", mockClient)
+		assert.NoError(t, err)
+		u := newUser("")
+		cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(append(secrets, u)...).Build()
+		r := &ReconcileObjectStoreUser{
+			client:           cl,
+			scheme:           s,
+			objContext:       &cephobject.AdminOpsContext{AdminOpsClient: adminClient},
+			opManagerContext: context.TODO(),
+		}
+
+		assert.NoError(t, r.expireUser(u))
+		assert.Len(t, requests, 2)
+		assert.Equal(t, "POST", requests[1].Method)
+		assert.Equal(t, "1", requests[1].URL.Query().Get("suspended"))
+
+		// the user is recorded as suspended by the operator
+		updated := &cephv1.CephObjectStoreUser{}
+		assert.NoError(t, cl.Get(context.TODO(), client.ObjectKeyFromObject(u), updated))
+		assert.Equal(t, "true", updated.Annotations[suspendedOnExpirationAnnotation])
+
+		// the secrets of the user are deleted, the user is kept
+		remaining := &corev1.SecretList{}
+		assert.NoError(t, cl.List(context.TODO(), remaining))
+		assert.Len(t, remaining.Items, 1)
+		assert.Equal(t, "unrelated", remaining.Items[0].Name)
+		assert.NoError(t, cl.Get(context.TODO(), client.ObjectKeyFromObject(u), &cephv1.CephObjectStoreUser{}))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		u := newUser(cephv1.ObjectUserExpirationDelete)
+		cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(u).Build()
+		r := &ReconcileObjectStoreUser{client: cl, scheme: s, opManagerContext: context.TODO()}
+
+		assert.NoError(t, r.expireUser(u))
+		err := cl.Get(context.TODO(), client.ObjectKeyFromObject(u), &cephv1.CephObjectStoreUser{})
+		assert.True(t, kerrors.IsNotFound(err))
+	})
+}
+
+func TestResumeUser(t *testing.T) {
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephObjectStoreUser{})
+	requests := []*http.Request{}
+	mockClient := &cephobject.MockClient{
+		MockDo: func(req *http.Request) (*http.Response, error) {
+			requests = append(requests, req)
+			return &http.Response{StatusCode: 200, Body: io.NopCloser(bytes.NewReader([]byte(`{"user_id":"my-user"}`)))}, nil
+		},
+	}
+	adminClient, err := admin.New("rook-ceph-rgw-my-store.mycluster.svc", "53S6B9S809NUP19IJ2K3", "1bXPegzsGClvoGAiJdHQD1uOW2sQBLAZM9j9VtXR", mockClient)
+	assert.NoError(t, err)
+	u := &cephv1.CephObjectStoreUser{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-user", Namespace: "rook-ceph"},
+		Spec:       cephv1.ObjectStoreUserSpec{Expiration: &cephv1.ObjectUserExpirationSpec{TTL: metav1.Duration{Duration: time.Hour}}},
+	}
+	cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(u).Build()
+	r := &ReconcileObjectStoreUser{
+		client:           cl,
+		scheme:           s,
+		objContext:       &cephobject.AdminOpsContext{AdminOpsClient: adminClient},
+		opManagerContext: context.TODO(),
+	}
+	suspended, active := 1, 0
+
+	// the users suspended by an admin are left suspended
+	assert.NoError(t, r.resumeUser(u, admin.User{ID: "my-user", Suspended: &suspended}))
+	assert.Empty(t, requests)
+
+	// the users suspended on expiration are resumed
+	assert.NoError(t, r.setSuspendedOnExpiration(u, true))
+	assert.NoError(t, r.resumeUser(u, admin.User{ID: "my-user", Suspended: &suspended}))
+	assert.Len(t, requests, 1)
+	assert.Equal(t, "0", requests[0].URL.Query().Get("suspended"))
+	updated := &cephv1.CephObjectStoreUser{}
+	assert.NoError(t, cl.Get(context.TODO(), client.ObjectKeyFromObject(u), updated))
+	assert.NotContains(t, updated.Annotations, suspendedOnExpirationAnnotation)
+
+	// the annotation is removed even if the user was already resumed, and an admin can then suspend it again
+	assert.NoError(t, r.setSuspendedOnExpiration(u, true))
+	assert.NoError(t, r.resumeUser(u, admin.User{ID: "my-user", Suspended: &active}))
+	assert.Len(t, requests, 1)
+	assert.NoError(t, r.resumeUser(u, admin.User{ID: "my-user", Suspended: &suspended}))
+	assert.Len(t, requests, 1)
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package objectuser
+
+import (
+	"fmt"
+
+	"github.com/ceph/go-ceph/rgw/admin"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mgr"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	swiftKeyType   = "swift"
+	swiftKeyLength = 40
+	swiftKeyChars  = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	subUserLabel   = "subuser"
+)
+
+// the access of the subusers in the replies of the admin ops api differs from the access of the requests
+var subUserAccessReplies = map[admin.SubuserAccess]cephv1.SubUserAccess{
+	admin.SubuserAccessReplyRead:      cephv1.SubUserAccessRead,
+	admin.SubuserAccessReplyWrite:     cephv1.SubUserAccessWrite,
+	admin.SubuserAccessReplyReadWrite: cephv1.SubUserAccessReadWrite,
+	admin.SubuserAccessReplyFull:      cephv1.SubUserAccessFull,
+}
+
+func subUserID(u *cephv1.CephObjectStoreUser, subUser string) string {
+	return fmt.Sprintf("%s:%s", u.CephUserID(), subUser)
+}
+
+func generateSwiftKey() (string, error) {
+	key, err := mgr.GenerateRandomBytes(swiftKeyLength)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to generate swift key")
+	}
+	for i, b := range key {
+		key[i] = swiftKeyChars[b%byte(len(swiftKeyChars))]
+	}
+	return string(key), nil
+}
+
+// reconcileSubUsers creates the subusers of the spec with a swift key and updates their access. The subusers that are
+// no longer in the spec are removed with their keys. The swift keys of the subusers are kept by subuser name.
+func (r *ReconcileObjectStoreUser) reconcileSubUsers(u *cephv1.CephObjectStoreUser, user admin.User) error {
+	currentAccess := map[string]admin.SubuserAccess{}
+	for _, subUser := range user.Subusers {
+		currentAccess[subUser.Name] = subUser.Access
+	}
+	currentKeys := map[string]string{}
+	for _, key := range user.SwiftKeys {
+		currentKeys[key.User] = key.SecretKey
+	}
+
+	r.swiftKeys = map[string]string{}
+	desired := map[string]bool{}
+	for _, subUser := range u.Spec.SubUsers {
+		id := subUserID(u, subUser.Name)
+		desired[id] = true
+		access, exists := currentAccess[id]
+		key, hasKey := currentKeys[id]
+
+		if !exists || !hasKey {
+			var err error
+			if key, err = generateSwiftKey(); err != nil {
+				return err
+			}
+		}
+		keyType := swiftKeyType
+		spec := admin.SubuserSpec{Name: id, Access: admin.SubuserAccess(subUser.Access)}
+		switch {
+		case !exists:
+			spec.SecretKey = &key
+			spec.KeyType = &keyType
+			if err := r.objContext.AdminOpsClient.CreateSubuser(r.opManagerContext, admin.User{ID: u.CephUserID()}, spec); err != nil {
+				return errors.Wrapf(err, "failed to create subuser %q", id)
+			}
+			logger.Infof("created subuser %q with a swift key", id)
+		case subUserAccessReplies[access] != subUser.Access:
+			if err := r.objContext.AdminOpsClient.ModifySubuser(r.opManagerContext, admin.User{ID: u.CephUserID()}, spec); err != nil {
+				return errors.Wrapf(err, "failed to update the access of subuser %q", id)
+			}
+			logger.Infof("updated the access of subuser %q to %q", id, subUser.Access)
+		}
+		if exists && !hasKey {
+			if _, err := r.objContext.AdminOpsClient.CreateKey(r.opManagerContext, admin.UserKeySpec{UID: u.CephUserID(), SubUser: id, KeyType: swiftKeyType, SecretKey: key}); err != nil {
+				return errors.Wrapf(err, "failed to create the swift key of subuser %q", id)
+			}
+			logger.Infof("created the swift key of subuser %q", id)
+		}
+		r.swiftKeys[subUser.Name] = key
+	}
+
+	for id := range currentAccess {
+		if desired[id] {
+			continue
+		}
+		purgeKeys := true
+		if err := r.objContext.AdminOpsClient.RemoveSubuser(r.opManagerContext, admin.User{ID: u.CephUserID()}, admin.SubuserSpec{Name: id, PurgeKeys: &purgeKeys}); err != nil {
+			return errors.Wrapf(err, "failed to remove subuser %q", id)
+		}
+		logger.Infof("removed subuser %q that is no longer in the spec", id)
+	}
+	return nil
+}
+
+func generateSubUserSecretName(u *cephv1.CephObjectStoreUser, subUser string) string {
+	return fmt.Sprintf("%s-%s", generateCephUserSecretName(u), subUser)
+}
+
+// reconcileSubUserSecrets stores the swift credentials of each subuser in a secret, and removes the secrets of the
+// subusers that are no longer in the spec
+func (r *ReconcileObjectStoreUser) reconcileSubUserSecrets(u *cephv1.CephObjectStoreUser) error {
+	for _, subUser := range u.Spec.SubUsers {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      generateSubUserSecretName(u, subUser.Name),
+				Namespace: u.Namespace,
+				Labels: map[string]string{
+					"app":               appName,
+					"user":              u.Name,
+					subUserLabel:        subUser.Name,
+					"rook_cluster":      u.StoreNamespacedName().Namespace,
+					"rook_object_store": u.StoreNamespacedName().Name,
+				},
+			},
+			StringData: map[string]string{
+				"User":         subUserID(u, subUser.Name),
+				"SecretKey":    r.swiftKeys[subUser.Name],
+				"AuthEndpoint": object.SwiftAuthEndpoint(r.swiftSpec, r.objContext.Endpoint),
+			},
+			Type: k8sutil.RookType,
+		}
+		if err := controllerutil.SetControllerReference(u, secret, r.scheme); err != nil {
+			return errors.Wrapf(err, "failed to set owner reference of subuser secret %q", secret.Name)
+		}
+		if err := opcontroller.CreateOrUpdateObject(r.opManagerContext, r.client, secret); err != nil {
+			return errors.Wrapf(err, "failed to create or update subuser secret %q", secret.Name)
+		}
+	}
+
+	desired := map[string]bool{}
+	for _, subUser := range u.Spec.SubUsers {
+		desired[subUser.Name] = true
+	}
+	selector := fmt.Sprintf("user=%s,rook_object_store=%s,%s", u.Name, u.StoreNamespacedName().Name, subUserLabel)
+	secrets, err := r.context.Clientset.CoreV1().Secrets(u.Namespace).List(r.opManagerContext, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return errors.Wrap(err, "failed to list subuser secrets")
+	}
+	for _, secret := range secrets.Items {
+		if desired[secret.Labels[subUserLabel]] {
+			continue
+		}
+		err := r.context.Clientset.CoreV1().Secrets(u.Namespace).Delete(r.opManagerContext, secret.Name, metav1.DeleteOptions{})
+		if err != nil && !kerrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete subuser secret %q", secret.Name)
+		}
+		logger.Infof("deleted secret %q of a removed subuser", secret.Name)
+	}
+	return nil
+}
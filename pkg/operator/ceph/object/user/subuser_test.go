@@ -0,0 +1,133 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package objectuser
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/ceph/go-ceph/rgw/admin"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephobject "github.com/rook/rook/pkg/operator/ceph/object"
+	"github.com/rook/rook/pkg/operator/test"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileSubUsers(t *testing.T) {
+	requests := []*http.Request{}
+	mockClient := &cephobject.MockClient{
+		MockDo: func(req *http.Request) (*http.Response, error) {
+			requests = append(requests, req)
+			return &http.Response{StatusCode: 200, Body: io.NopCloser(bytes.NewReader([]byte(`[]`)))}, nil
+		},
+	}
+	adminClient, err := admin.New("rook-ceph-rgw-my-store.mycluster.svc", "53S6B9S809NUP19IJ2K3", "1bXPegzsGClvoGAiJdHQD1uOW2sQBLAZM9j9VtXR", mockClient)
+	assert.NoError(t, err)
+	r := &ReconcileObjectStoreUser{
+		objContext:       &cephobject.AdminOpsContext{AdminOpsClient: adminClient},
+		opManagerContext: context.TODO(),
+	}
+	u := &cephv1.CephObjectStoreUser{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-user", Namespace: "rook-ceph"},
+		Spec: cephv1.ObjectStoreUserSpec{
+			Store: "my-store",
+			SubUsers: []cephv1.ObjectUserSubUserSpec{
+				{Name: "new", Access: cephv1.SubUserAccessRead},
+				{Name: "changed", Access: cephv1.SubUserAccessFull},
+				{Name: "unchanged", Access: cephv1.SubUserAccessReadWrite},
+			},
+		},
+	}
+	user := admin.User{
+		ID: "my-user",
+		Subusers: []admin.SubuserSpec{
+			{Name: "my-user:changed", Access: admin.SubuserAccessReplyRead},
+			{Name: "my-user:unchanged", Access: admin.SubuserAccessReplyReadWrite},
+			{Name: "my-user:removed", Access: admin.SubuserAccessReplyRead},
+		},
+		SwiftKeys: []admin.SwiftKeySpec{
+			{User: "my-user:changed", SecretKey: "changedkey"},
+			{User: "my-user:removed", SecretKey: "removedkey"},
+		},
+	}
+
+	assert.NoError(t, r.reconcileSubUsers(u, user))
+	assert.Len(t, requests, 4)
+	query := func(i int) string { return requests[i].Method + " " + requests[i].URL.Query().Encode() }
+
+	// the new subuser is created with a generated swift key
+	assert.Regexp(t, "^PUT access=read&format=json&key-type=swift&secret-key=[0-9A-Za-z]{40}&subuser=my-user%3Anew&uid=my-user$", query(0))
+	assert.Equal(t, requests[0].URL.Query().Get("secret-key"), r.swiftKeys["new"])
+	// the access of the changed subuser is updated
+	assert.Equal(t, "POST access=full&format=json&subuser=my-user%3Achanged&uid=my-user", query(1))
+	assert.Equal(t, "changedkey", r.swiftKeys["changed"])
+	// the swift key of the subuser without a key is created
+	assert.Regexp(t, "^PUT format=json&key=&key-type=swift&secret-key=[0-9A-Za-z]{40}&subuser=my-user%3Aunchanged&uid=my-user$", query(2))
+	assert.Equal(t, requests[2].URL.Query().Get("secret-key"), r.swiftKeys["unchanged"])
+	// the subuser no longer in the spec is removed with its keys
+	assert.Equal(t, "DELETE format=json&purge-keys=true&subuser=my-user%3Aremoved&uid=my-user", query(3))
+}
+
+func TestReconcileSubUserSecrets(t *testing.T) {
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephObjectStoreUser{})
+	u := &cephv1.CephObjectStoreUser{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-user", Namespace: "rook-ceph", UID: "c1a1"},
+		Spec: cephv1.ObjectStoreUserSpec{
+			Store:    "my-store",
+			SubUsers: []cephv1.ObjectUserSubUserSpec{{Name: "swift", Access: cephv1.SubUserAccessFull}},
+		},
+	}
+	clientset := test.New(t, 1)
+	stale := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Name:      "rook-ceph-object-user-my-store-my-user-removed",
+		Namespace: "rook-ceph",
+		Labels:    map[string]string{"user": "my-user", "rook_object_store": "my-store", subUserLabel: "removed"},
+	}}
+	_, err := clientset.CoreV1().Secrets("rook-ceph").Create(context.TODO(), stale, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects([]runtime.Object{u}...).Build()
+	r := &ReconcileObjectStoreUser{
+		client:           cl,
+		scheme:           s,
+		context:          &clusterd.Context{Clientset: clientset},
+		objContext:       &cephobject.AdminOpsContext{Context: cephobject.Context{Endpoint: "http://rook-ceph-rgw-my-store.rook-ceph.svc:80"}},
+		opManagerContext: context.TODO(),
+		swiftKeys:        map[string]string{"swift": "swiftkey"},
+	}
+
+	assert.NoError(t, r.reconcileSubUserSecrets(u))
+	secret := &corev1.Secret{}
+	assert.NoError(t, cl.Get(context.TODO(), client.ObjectKey{Name: "rook-ceph-object-user-my-store-my-user-swift", Namespace: "rook-ceph"}, secret))
+	assert.Equal(t, map[string]string{
+		"User":         "my-user:swift",
+		"SecretKey":    "swiftkey",
+		"AuthEndpoint": "http://rook-ceph-rgw-my-store.rook-ceph.svc:80/auth/1.0",
+	}, secret.StringData)
+	_, err = clientset.CoreV1().Secrets("rook-ceph").Get(context.TODO(), stale.Name, metav1.GetOptions{})
+	assert.Error(t, err)
+}
@@ -45,6 +45,7 @@ import (
 	"github.com/rook/rook/pkg/operator/ceph/pool"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	"github.com/rook/rook/pkg/util/exec"
+	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -85,7 +86,7 @@ type ReconcileObjectZone struct {
 // Add creates a new CephObjectZone Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
-	return add(mgr, newReconciler(mgr, context, opManagerContext))
+	return add(opManagerContext, mgr, newReconciler(mgr, context, opManagerContext))
 }
 
 // newReconciler returns a new reconcile.Reconciler
@@ -99,7 +100,7 @@ func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerCont
 	}
 }
 
-func add(mgr manager.Manager, r reconcile.Reconciler) error {
+func add(opManagerContext context.Context, mgr manager.Manager, r reconcile.Reconciler) error {
 	// Create a new controller
 	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
 	if err != nil {
@@ -113,6 +114,18 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Watch for the readiness of the gateway pods of the zones that use the endpoints of the gateway pods
+	err = c.Watch(
+		&source.Kind{Type: &corev1.Pod{}},
+		handler.EnqueueRequestsFromMapFunc(handler.MapFunc(func(obj client.Object) []reconcile.Request {
+			return zonesOfGatewayPod(opManagerContext, mgr.GetClient(), obj)
+		})),
+		predicateGatewayPodEndpointChanges(),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to watch for changes on the gateway pods")
+	}
+
 	return nil
 }
 
@@ -181,6 +194,7 @@ func (r *ReconcileObjectZone) reconcile(request reconcile.Request) (reconcile.Re
 	// validate the zone settings
 	err = r.validateZoneCR(cephObjectZone)
 	if err != nil {
+		reporting.ReportValidationFailed(r.recorder, cephObjectZone, err)
 		r.updateStatus(k8sutil.ObservedGenerationNotAvailable, request.NamespacedName, k8sutil.ReconcileFailedStatus)
 		return reconcile.Result{}, *cephObjectZone, errors.Wrapf(err, "invalid CephObjectZone CR %q", cephObjectZone.Name)
 	}
@@ -249,15 +263,27 @@ func (r *ReconcileObjectZone) createorUpdateCephZone(zone *cephv1.CephObjectZone
 	}
 
 	// create/update zone
-	_, err = object.RunAdminCommandNoMultisite(objContext, true, "zone", "get", realmArg, zoneGroupArg, zoneArg)
+	zoneGetOutput, err := object.RunAdminCommandNoMultisite(objContext, true, "zone", "get", realmArg, zoneGroupArg, zoneArg)
 	if err == nil {
 		logger.Debugf("ceph zone %q already exists, new zone and pools will not be created but checking for update", zone.Name)
-		zoneEndpointsModified, err := object.ShouldUpdateZoneEndpointList(zoneGroupJson.Zones, zone.Spec.CustomEndpoints, objContext.Zone)
+		desiredEndpoints := zone.Spec.CustomEndpoints
+		if zone.Spec.GatewayPodEndpoints {
+			desiredEndpoints, err = r.gatewayPodEndpoints(zone)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		zoneEndpointsModified, err := object.ShouldUpdateZoneEndpointList(zoneGroupJson.Zones, desiredEndpoints, objContext.Zone)
 		if err != nil {
 			return reconcile.Result{}, err
 		}
+		if zoneEndpointsModified && zone.Spec.GatewayPodEndpoints && len(desiredEndpoints) == 0 {
+			// keep the last endpoints rather than leaving the zone without any endpoint
+			logger.Warningf("no gateway pod of zone %q is ready, keeping the current endpoints of the zone", zone.Name)
+			zoneEndpointsModified = false
+		}
 		if zoneEndpointsModified {
-			zoneEndpoints := strings.Join(zone.Spec.CustomEndpoints, ",")
+			zoneEndpoints := strings.Join(desiredEndpoints, ",")
 			logger.Debugf("Updating endpoints for zone %q are: %q", objContext.Zone, zoneEndpoints)
 			endpointArg := fmt.Sprintf("--endpoints=%s", zoneEndpoints)
 			err = object.JoinMultisite(objContext, endpointArg, zoneEndpoints, zone.Namespace)
@@ -265,6 +291,20 @@ func (r *ReconcileObjectZone) createorUpdateCephZone(zone *cephv1.CephObjectZone
 				return reconcile.Result{}, err
 			}
 		}
+		isMaster, tierType := false, ""
+		for _, z := range zoneGroupJson.Zones {
+			if z.Name == zone.Name {
+				isMaster, tierType = z.ID == zoneGroupJson.MasterZoneID, z.TierType
+			}
+		}
+		err = reconcileMetadataSearch(objContext, zone, isMaster, tierType, zoneGetOutput)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		err = r.reconcileSyncModule(objContext, zone, isMaster, tierType, zoneGetOutput)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
 		return reconcile.Result{}, nil
 	}
 
@@ -275,6 +315,12 @@ func (r *ReconcileObjectZone) createorUpdateCephZone(zone *cephv1.CephObjectZone
 		if zoneGroupJson.MasterZoneID == "" {
 			zoneIsMaster = true
 		}
+		if zoneIsMaster && zone.Spec.MetadataSearch != nil {
+			return reconcile.Result{}, errors.Errorf("metadata search cannot be configured on zone %q since it would be the master zone of zone group %q", zone.Name, zone.Spec.ZoneGroup)
+		}
+		if zoneIsMaster && zone.Spec.SyncModule != nil {
+			return reconcile.Result{}, errors.Errorf("sync module cannot be configured on zone %q since it would be the master zone of zone group %q", zone.Name, zone.Spec.ZoneGroup)
+		}
 
 		err = r.createPoolsAndZone(objContext, zone, realmName, zoneIsMaster)
 		if err != nil {
@@ -310,6 +356,18 @@ func (r *ReconcileObjectZone) createPoolsAndZone(objContext *object.Context, zon
 		// master zone does not exist yet for zone group
 		args = append(args, "--master")
 	}
+	if zone.Spec.MetadataSearch != nil {
+		// the zone syncs the object metadata to elasticsearch
+		args = append(args, metadataSearchArgs(zone.Spec.MetadataSearch)...)
+	}
+	if zone.Spec.SyncModule != nil {
+		// the zone runs the sync module instead of storing the objects of the other zones
+		tierType, config, err := syncModuleTierConfig(zone)
+		if err != nil {
+			return err
+		}
+		args = append(args, syncModuleArgs(tierType, config)...)
+	}
 	if len(zone.Spec.CustomEndpoints) > 0 {
 		// If custom endpoint list defined set those values
 		zoneEndpoints := strings.Join(zone.Spec.CustomEndpoints, ",")
@@ -321,6 +379,12 @@ func (r *ReconcileObjectZone) createPoolsAndZone(objContext *object.Context, zon
 	}
 	logger.Debugf("created ceph zone %q", zone.Name)
 
+	if zone.Spec.SyncModule != nil && zone.Spec.SyncModule.Cloud != nil {
+		if _, err := r.setCloudSyncModuleCredentials(objContext, zone); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -374,6 +438,18 @@ func (r *ReconcileObjectZone) validateZoneCR(z *cephv1.CephObjectZone) error {
 	if err := pool.ValidatePoolSpec(r.context, r.clusterInfo, r.clusterSpec, &z.Spec.DataPool); err != nil {
 		return errors.Wrap(err, "invalid data pool spec")
 	}
+	if z.Spec.GatewayPodEndpoints && len(z.Spec.CustomEndpoints) > 0 {
+		return errors.New("customEndpoints cannot be set with gatewayPodEndpoints")
+	}
+	if err := validateMetadataSearch(z.Spec.MetadataSearch); err != nil {
+		return errors.Wrap(err, "invalid metadata search spec")
+	}
+	if z.Spec.MetadataSearch != nil && z.Spec.SyncModule != nil {
+		return errors.New("syncModule cannot be set with metadataSearch")
+	}
+	if err := validateSyncModule(z.Spec.SyncModule); err != nil {
+		return errors.Wrap(err, "invalid sync module spec")
+	}
 	return nil
 }
 
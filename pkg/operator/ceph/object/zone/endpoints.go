@@ -0,0 +1,133 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zone
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const objectStoreLabel = "rook_object_store"
+
+// zonesOfGatewayPod returns the zone of the object store of a gateway pod, if the zone uses the endpoints of
+// the gateway pods
+func zonesOfGatewayPod(ctx context.Context, c client.Client, obj client.Object) []reconcile.Request {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return []reconcile.Request{}
+	}
+	storeName, ok := pod.Labels[objectStoreLabel]
+	if !ok || pod.Labels[k8sutil.AppAttr] != object.AppName {
+		return []reconcile.Request{}
+	}
+
+	store := &cephv1.CephObjectStore{}
+	err := c.Get(ctx, types.NamespacedName{Namespace: pod.Namespace, Name: storeName}, store)
+	if err != nil || store.Spec.Zone.Name == "" {
+		return []reconcile.Request{}
+	}
+	zone := &cephv1.CephObjectZone{}
+	zoneName := types.NamespacedName{Namespace: pod.Namespace, Name: store.Spec.Zone.Name}
+	err = c.Get(ctx, zoneName, zone)
+	if err != nil || !zone.Spec.GatewayPodEndpoints {
+		return []reconcile.Request{}
+	}
+
+	logger.Debugf("endpoint of gateway pod %q of zone %q changed", pod.Name, zoneName)
+	return []reconcile.Request{{NamespacedName: zoneName}}
+}
+
+// predicateGatewayPodEndpointChanges only lets through the pod events that may change the endpoints of a zone: the
+// creation and deletion of the pods, and the changes of their readiness or addresses
+func predicateGatewayPodEndpointChanges() predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldPod, ok := e.ObjectOld.(*corev1.Pod)
+			if !ok {
+				return false
+			}
+			newPod, ok := e.ObjectNew.(*corev1.Pod)
+			if !ok {
+				return false
+			}
+			return isPodReady(oldPod) != isPodReady(newPod) ||
+				oldPod.Status.PodIP != newPod.Status.PodIP ||
+				oldPod.Status.HostIP != newPod.Status.HostIP
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	if pod.DeletionTimestamp != nil {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// gatewayPodEndpoints returns the sorted endpoints of the ready gateway pods of the object stores in the zone
+func (r *ReconcileObjectZone) gatewayPodEndpoints(zone *cephv1.CephObjectZone) ([]string, error) {
+	stores := &cephv1.CephObjectStoreList{}
+	err := r.client.List(r.opManagerContext, stores, client.InNamespace(zone.Namespace))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list the object stores of zone %q", zone.Name)
+	}
+
+	endpoints := []string{}
+	for i := range stores.Items {
+		store := &stores.Items[i]
+		if store.Spec.Zone.Name != zone.Name || store.Spec.IsExternal() {
+			continue
+		}
+
+		pods := &corev1.PodList{}
+		err = r.client.List(r.opManagerContext, pods, client.InNamespace(zone.Namespace),
+			client.MatchingLabels{k8sutil.AppAttr: object.AppName, objectStoreLabel: store.Name})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list the gateway pods of object store %q", store.Name)
+		}
+		for j := range pods.Items {
+			if !isPodReady(&pods.Items[j]) {
+				continue
+			}
+			if endpoint := object.GatewayPodEndpoint(store, &pods.Items[j]); endpoint != "" {
+				endpoints = append(endpoints, endpoint)
+			}
+		}
+	}
+	sort.Strings(endpoints)
+
+	return endpoints, nil
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zone
+
+import (
+	"context"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func gatewayPod(name, store, hostIP string, ready bool) *corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "rook-ceph",
+			Labels:    map[string]string{"app": "rook-ceph-rgw", objectStoreLabel: store},
+		},
+		Spec: corev1.PodSpec{HostNetwork: true},
+		Status: corev1.PodStatus{
+			HostIP:     hostIP,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+}
+
+func TestGatewayPodEndpoints(t *testing.T) {
+	ctx := context.TODO()
+	name := "zone-a"
+	zonegroup := "zonegroup-a"
+	namespace := "rook-ceph"
+	zone := &cephv1.CephObjectZone{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       cephv1.ObjectZoneSpec{ZoneGroup: zonegroup, GatewayPodEndpoints: true},
+	}
+	store := &cephv1.CephObjectStore{
+		ObjectMeta: metav1.ObjectMeta{Name: "store-a", Namespace: namespace},
+		Spec: cephv1.ObjectStoreSpec{
+			Gateway: cephv1.GatewaySpec{Port: 80},
+			Zone:    cephv1.ZoneSpec{Name: name},
+		},
+	}
+	otherStore := &cephv1.CephObjectStore{
+		ObjectMeta: metav1.ObjectMeta{Name: "store-b", Namespace: namespace},
+		Spec: cephv1.ObjectStoreSpec{
+			Gateway: cephv1.GatewaySpec{Port: 80},
+			Zone:    cephv1.ZoneSpec{Name: "zone-b"},
+		},
+	}
+	objects := []runtime.Object{
+		zone, store, otherStore,
+		gatewayPod("rgw-a", "store-a", "192.168.0.2", true),
+		gatewayPod("rgw-b", "store-a", "192.168.0.1", true),
+		gatewayPod("rgw-c", "store-a", "192.168.0.3", false),
+		gatewayPod("rgw-d", "store-b", "192.168.0.4", true),
+	}
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephObjectZone{}, &cephv1.CephObjectZoneList{}, &cephv1.CephObjectStore{}, &cephv1.CephObjectStoreList{})
+	cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(objects...).Build()
+	r := &ReconcileObjectZone{client: cl, scheme: s, opManagerContext: ctx}
+
+	t.Run("only the ready pods of the stores of the zone", func(t *testing.T) {
+		endpoints, err := r.gatewayPodEndpoints(zone)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"http://192.168.0.1:80", "http://192.168.0.2:80"}, endpoints)
+	})
+
+	t.Run("the pod events are mapped to the zone", func(t *testing.T) {
+		requests := zonesOfGatewayPod(ctx, cl, gatewayPod("rgw-a", "store-a", "192.168.0.2", true))
+		assert.Len(t, requests, 1)
+		assert.Equal(t, types.NamespacedName{Namespace: namespace, Name: name}, requests[0].NamespacedName)
+
+		// the zone of the other store does not exist
+		assert.Empty(t, zonesOfGatewayPod(ctx, cl, gatewayPod("rgw-d", "store-b", "192.168.0.4", true)))
+
+		// not a gateway pod
+		pod := gatewayPod("rgw-a", "store-a", "192.168.0.2", true)
+		pod.Labels["app"] = "rook-ceph-mon"
+		assert.Empty(t, zonesOfGatewayPod(ctx, cl, pod))
+	})
+
+	t.Run("the zones without the pod endpoints are not reconciled", func(t *testing.T) {
+		zone.Spec.GatewayPodEndpoints = false
+		assert.NoError(t, cl.Update(ctx, zone))
+		assert.Empty(t, zonesOfGatewayPod(ctx, cl, gatewayPod("rgw-a", "store-a", "192.168.0.2", true)))
+	})
+}
+
+func TestPredicateGatewayPodEndpointChanges(t *testing.T) {
+	p := predicateGatewayPodEndpointChanges()
+	oldPod := gatewayPod("rgw-a", "store-a", "192.168.0.1", true)
+
+	newPod := oldPod.DeepCopy()
+	newPod.Labels["foo"] = "bar"
+	assert.False(t, p.Update(event.UpdateEvent{ObjectOld: oldPod, ObjectNew: newPod}))
+
+	newPod = gatewayPod("rgw-a", "store-a", "192.168.0.1", false)
+	assert.True(t, p.Update(event.UpdateEvent{ObjectOld: oldPod, ObjectNew: newPod}))
+
+	newPod = gatewayPod("rgw-a", "store-a", "192.168.0.2", true)
+	assert.True(t, p.Update(event.UpdateEvent{ObjectOld: oldPod, ObjectNew: newPod}))
+
+	newPod = oldPod.DeepCopy()
+	now := metav1.Now()
+	newPod.DeletionTimestamp = &now
+	assert.True(t, p.Update(event.UpdateEvent{ObjectOld: oldPod, ObjectNew: newPod}))
+
+	assert.True(t, p.Create(event.CreateEvent{Object: oldPod}))
+	assert.True(t, p.Delete(event.DeleteEvent{Object: oldPod}))
+}
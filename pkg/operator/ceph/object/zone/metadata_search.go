@@ -0,0 +1,140 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zone
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+)
+
+// elasticsearchTierType is the tier type of the zones running the Elasticsearch sync module
+const elasticsearchTierType = "elasticsearch"
+
+// allow commitConfigChanges to be overridden for unit testing
+var commitConfigChanges = object.CommitConfigChanges
+
+type zoneTierConfig struct {
+	TierConfig map[string]interface{} `json:"tier_config"`
+}
+
+// metadataSearchTierConfig returns the tier config of the Elasticsearch sync module of the zone. The options that
+// are not set keep the defaults of the sync module.
+func metadataSearchTierConfig(spec *cephv1.ZoneMetadataSearchSpec) map[string]string {
+	config := map[string]string{"endpoint": spec.Endpoint}
+	if spec.NumShards != nil {
+		config["num_shards"] = strconv.Itoa(int(*spec.NumShards))
+	}
+	if spec.NumReplicas != nil {
+		config["num_replicas"] = strconv.Itoa(int(*spec.NumReplicas))
+	}
+	if spec.ExplicitCustomMeta != nil {
+		config["explicit_custom_meta"] = strconv.FormatBool(*spec.ExplicitCustomMeta)
+	}
+	return config
+}
+
+// metadataSearchArgs returns the radosgw-admin arguments configuring the Elasticsearch sync module of the zone
+func metadataSearchArgs(spec *cephv1.ZoneMetadataSearchSpec) []string {
+	config := metadataSearchTierConfig(spec)
+	options := []string{}
+	for _, key := range []string{"endpoint", "num_shards", "num_replicas", "explicit_custom_meta"} {
+		if value, ok := config[key]; ok {
+			options = append(options, fmt.Sprintf("%s=%s", key, value))
+		}
+	}
+	return []string{
+		fmt.Sprintf("--tier-type=%s", elasticsearchTierType),
+		fmt.Sprintf("--tier-config=%s", strings.Join(options, ",")),
+	}
+}
+
+// metadataSearchChanged returns whether the tier config in the `radosgw-admin zone get` output differs from the
+// metadata search of the zone. The values are compared as strings since radosgw-admin may report them as numbers.
+func metadataSearchChanged(spec *cephv1.ZoneMetadataSearchSpec, zoneGetOutput string) (bool, error) {
+	var current zoneTierConfig
+	if err := json.Unmarshal([]byte(zoneGetOutput), &current); err != nil {
+		return false, errors.Wrap(err, "failed to parse `radosgw-admin zone get` output")
+	}
+	for key, value := range metadataSearchTierConfig(spec) {
+		currentValue, ok := current.TierConfig[key]
+		if !ok || fmt.Sprint(currentValue) != value {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// validateMetadataSearch validates the Elasticsearch endpoint of the metadata search of the zone
+func validateMetadataSearch(spec *cephv1.ZoneMetadataSearchSpec) error {
+	if spec == nil {
+		return nil
+	}
+	endpoint, err := url.Parse(spec.Endpoint)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse elasticsearch endpoint %q", spec.Endpoint)
+	}
+	if (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return errors.Errorf("elasticsearch endpoint %q must be an http or https url", spec.Endpoint)
+	}
+	return nil
+}
+
+// reconcileMetadataSearch configures the Elasticsearch sync module of an existing zone and commits the period when
+// the tier of the zone changes. The zone is left unchanged when the metadata search is removed from the spec.
+func reconcileMetadataSearch(objContext *object.Context, zone *cephv1.CephObjectZone, isMaster bool, tierType, zoneGetOutput string) error {
+	spec := zone.Spec.MetadataSearch
+	if spec == nil {
+		return nil
+	}
+	if isMaster {
+		return errors.Errorf("metadata search cannot be configured on zone %q since it is the master zone of zone group %q", zone.Name, zone.Spec.ZoneGroup)
+	}
+	changed := tierType != elasticsearchTierType
+	if !changed {
+		var err error
+		changed, err = metadataSearchChanged(spec, zoneGetOutput)
+		if err != nil {
+			return err
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	logger.Infof("configuring the metadata search of zone %q with elasticsearch endpoint %q", zone.Name, spec.Endpoint)
+	args := []string{"zone", "modify",
+		fmt.Sprintf("--rgw-realm=%s", objContext.Realm),
+		fmt.Sprintf("--rgw-zonegroup=%s", objContext.ZoneGroup),
+		fmt.Sprintf("--rgw-zone=%s", objContext.Zone),
+	}
+	args = append(args, metadataSearchArgs(spec)...)
+	output, err := object.RunAdminCommandNoMultisite(objContext, false, args...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to configure the metadata search of zone %q for reason %q", zone.Name, output)
+	}
+	if err := commitConfigChanges(objContext); err != nil {
+		return errors.Wrapf(err, "failed to commit the metadata search of zone %q", zone.Name)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zone
+
+import (
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMetadataSearchArgs(t *testing.T) {
+	spec := &cephv1.ZoneMetadataSearchSpec{Endpoint: "http://es.example.com:9200"}
+	assert.Equal(t, []string{"--tier-type=elasticsearch", "--tier-config=endpoint=http://es.example.com:9200"}, metadataSearchArgs(spec))
+
+	shards, replicas, explicit := int32(16), int32(0), true
+	spec.NumShards, spec.NumReplicas, spec.ExplicitCustomMeta = &shards, &replicas, &explicit
+	assert.Equal(t, []string{
+		"--tier-type=elasticsearch",
+		"--tier-config=endpoint=http://es.example.com:9200,num_shards=16,num_replicas=0,explicit_custom_meta=true",
+	}, metadataSearchArgs(spec))
+}
+
+func TestMetadataSearchChanged(t *testing.T) {
+	shards := int32(16)
+	spec := &cephv1.ZoneMetadataSearchSpec{Endpoint: "http://es.example.com:9200", NumShards: &shards}
+
+	changed, err := metadataSearchChanged(spec, `{"tier_config": {"endpoint": "http://es.example.com:9200", "num_shards": 16, "num_replicas": 1}}`)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	changed, err = metadataSearchChanged(spec, `{"tier_config": {"endpoint": "http://es.example.com:9200", "num_shards": "8"}}`)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+
+	changed, err = metadataSearchChanged(spec, `{"id": "test-id"}`)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+
+	_, err = metadataSearchChanged(spec, `not json`)
+	assert.Error(t, err)
+}
+
+func TestValidateMetadataSearch(t *testing.T) {
+	assert.NoError(t, validateMetadataSearch(nil))
+	assert.NoError(t, validateMetadataSearch(&cephv1.ZoneMetadataSearchSpec{Endpoint: "https://es.example.com"}))
+	assert.Error(t, validateMetadataSearch(&cephv1.ZoneMetadataSearchSpec{Endpoint: "es.example.com:9200"}))
+	assert.Error(t, validateMetadataSearch(&cephv1.ZoneMetadataSearchSpec{Endpoint: "ftp://es.example.com"}))
+	assert.Error(t, validateMetadataSearch(&cephv1.ZoneMetadataSearchSpec{Endpoint: "http://"}))
+}
+
+func TestReconcileMetadataSearch(t *testing.T) {
+	modified := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			if args[0] == "zone" && args[1] == "modify" {
+				modified = append(modified, args[5:7]...)
+			}
+			return "", nil
+		},
+	}
+	commits := 0
+	commitConfigChanges = func(c *object.Context) error {
+		commits++
+		return nil
+	}
+	defer func() { commitConfigChanges = object.CommitConfigChanges }()
+
+	objContext := object.NewContext(&clusterd.Context{Executor: executor}, cephclient.AdminTestClusterInfo("rook-ceph"), "zone-a")
+	objContext.Realm, objContext.ZoneGroup, objContext.Zone = "realm-a", "zonegroup-a", "zone-a"
+	zone := &cephv1.CephObjectZone{
+		ObjectMeta: metav1.ObjectMeta{Name: "zone-a", Namespace: "rook-ceph"},
+		Spec:       cephv1.ObjectZoneSpec{ZoneGroup: "zonegroup-a"},
+	}
+
+	// the zone is left unchanged without the metadata search
+	assert.NoError(t, reconcileMetadataSearch(objContext, zone, false, "elasticsearch", zoneGetOutput))
+	assert.Empty(t, modified)
+
+	zone.Spec.MetadataSearch = &cephv1.ZoneMetadataSearchSpec{Endpoint: "http://es.example.com:9200"}
+	assert.Error(t, reconcileMetadataSearch(objContext, zone, true, "", zoneGetOutput))
+	assert.Empty(t, modified)
+
+	assert.NoError(t, reconcileMetadataSearch(objContext, zone, false, "", zoneGetOutput))
+	assert.Equal(t, []string{"--tier-type=elasticsearch", "--tier-config=endpoint=http://es.example.com:9200"}, modified)
+	assert.Equal(t, 1, commits)
+
+	// the period is not committed again when the tier config is up to date
+	modified = []string{}
+	assert.NoError(t, reconcileMetadataSearch(objContext, zone, false, "elasticsearch", `{"tier_config": {"endpoint": "http://es.example.com:9200"}}`))
+	assert.Empty(t, modified)
+	assert.Equal(t, 1, commits)
+}
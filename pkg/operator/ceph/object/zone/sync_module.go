@@ -0,0 +1,270 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zone
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// the tier types of the zones running the sync modules
+const (
+	cloudTierType  = "cloud"
+	pubsubTierType = "pubsub"
+)
+
+// syncModuleTierConfig returns the tier type and the tier config of the sync module of the zone. The options that
+// are not set keep the defaults of the sync module. The nested options of the tier config, e.g. the connection of
+// the cloud sync module, are keyed by their path. The credentials of the cloud sync module are not part of the tier
+// config, they are set with setCloudSyncModuleCredentials.
+func syncModuleTierConfig(zone *cephv1.CephObjectZone) (string, map[string]string, error) {
+	syncModule := zone.Spec.SyncModule
+	switch {
+	case syncModule.Cloud != nil:
+		spec := syncModule.Cloud
+		config := map[string]string{"connection.endpoint": spec.Endpoint}
+		if spec.HostStyle != "" {
+			config["connection.host_style"] = spec.HostStyle
+		}
+		if spec.TargetPath != "" {
+			config["target_path"] = spec.TargetPath
+		}
+		return cloudTierType, config, nil
+
+	case syncModule.PubSub != nil:
+		spec := syncModule.PubSub
+		config := map[string]string{"uid": spec.UID}
+		if spec.DataBucketPrefix != "" {
+			config["data_bucket_prefix"] = spec.DataBucketPrefix
+		}
+		if spec.DataOIDPrefix != "" {
+			config["data_oid_prefix"] = spec.DataOIDPrefix
+		}
+		if spec.EventsRetentionDays != nil {
+			config["events_retention_days"] = strconv.Itoa(int(*spec.EventsRetentionDays))
+		}
+		return pubsubTierType, config, nil
+	}
+	return "", nil, errors.Errorf("no sync module is set for zone %q", zone.Name)
+}
+
+// syncModuleArgs returns the radosgw-admin arguments configuring the sync module of the zone
+func syncModuleArgs(tierType string, config map[string]string) []string {
+	options := []string{}
+	for key, value := range config {
+		options = append(options, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(options)
+	return []string{
+		fmt.Sprintf("--tier-type=%s", tierType),
+		fmt.Sprintf("--tier-config=%s", strings.Join(options, ",")),
+	}
+}
+
+// cloudSyncModuleCredentials returns the access key and the secret key of the target object store of the cloud sync
+// module of the zone
+func (r *ReconcileObjectZone) cloudSyncModuleCredentials(zone *cephv1.CephObjectZone) (string, string, error) {
+	spec := zone.Spec.SyncModule.Cloud
+	secret, err := r.context.Clientset.CoreV1().Secrets(zone.Namespace).Get(r.opManagerContext, spec.CredentialsSecretName, metav1.GetOptions{})
+	if err != nil {
+		return "", "", errors.Wrapf(err, "failed to get credentials secret %q of the cloud sync module of zone %q", spec.CredentialsSecretName, zone.Name)
+	}
+	accessKey, err := object.DecodeSecret(secret, object.AccessKeyName)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "failed to get the access key of the cloud sync module of zone %q", zone.Name)
+	}
+	secretKey, err := object.DecodeSecret(secret, object.SecretKeyName)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "failed to get the secret key of the cloud sync module of zone %q", zone.Name)
+	}
+	return accessKey, secretKey, nil
+}
+
+// setCloudSyncModuleCredentials sets the credentials of the cloud sync module in the tier config of the zone. The
+// zone is updated with `radosgw-admin zone set`, which reads the zone from stdin so that the credentials are not
+// passed on the command line. It returns whether the credentials changed.
+func (r *ReconcileObjectZone) setCloudSyncModuleCredentials(objContext *object.Context, zone *cephv1.CephObjectZone) (bool, error) {
+	accessKey, secretKey, err := r.cloudSyncModuleCredentials(zone)
+	if err != nil {
+		return false, err
+	}
+	output, err := object.RunAdminCommandNoMultisite(objContext, true, "zone", "get",
+		fmt.Sprintf("--rgw-realm=%s", objContext.Realm),
+		fmt.Sprintf("--rgw-zonegroup=%s", objContext.ZoneGroup),
+		fmt.Sprintf("--rgw-zone=%s", objContext.Zone),
+	)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get zone %q", zone.Name)
+	}
+	zoneConfig := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(output), &zoneConfig); err != nil {
+		return false, errors.Wrapf(err, "failed to parse config of zone %q", zone.Name)
+	}
+
+	tierConfig, ok := zoneConfig["tier_config"].(map[string]interface{})
+	if !ok {
+		tierConfig = map[string]interface{}{}
+		zoneConfig["tier_config"] = tierConfig
+	}
+	connection, ok := tierConfig["connection"].(map[string]interface{})
+	if !ok {
+		connection = map[string]interface{}{}
+		tierConfig["connection"] = connection
+	}
+	if connection["access_key"] == accessKey && connection["secret"] == secretKey {
+		return false, nil
+	}
+	connection["access_key"] = accessKey
+	connection["secret"] = secretKey
+
+	zoneJSON, err := json.Marshal(zoneConfig)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to serialize config of zone %q", zone.Name)
+	}
+	if err := object.RunAdminCommandWithStdin(objContext, string(zoneJSON), "zone", "set"); err != nil {
+		return false, errors.Wrapf(err, "failed to set the credentials of the cloud sync module of zone %q", zone.Name)
+	}
+	logger.Infof("set the credentials of the cloud sync module of zone %q", zone.Name)
+	return true, nil
+}
+
+// flattenTierConfig keys the nested options of the tier config by their path
+func flattenTierConfig(prefix string, config map[string]interface{}, flattened map[string]string) {
+	for key, value := range config {
+		if nested, ok := value.(map[string]interface{}); ok {
+			flattenTierConfig(prefix+key+".", nested, flattened)
+			continue
+		}
+		flattened[prefix+key] = fmt.Sprint(value)
+	}
+}
+
+// tierConfigChanged returns whether the tier config in the `radosgw-admin zone get` output differs from the tier
+// config of the sync module. The values are compared as strings since radosgw-admin may report them as numbers.
+func tierConfigChanged(config map[string]string, zoneGetOutput string) (bool, error) {
+	var current zoneTierConfig
+	if err := json.Unmarshal([]byte(zoneGetOutput), &current); err != nil {
+		return false, errors.Wrap(err, "failed to parse `radosgw-admin zone get` output")
+	}
+	flattened := map[string]string{}
+	flattenTierConfig("", current.TierConfig, flattened)
+	for key, value := range config {
+		if currentValue, ok := flattened[key]; !ok || currentValue != value {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func validateEndpoint(endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse endpoint %q", endpoint)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.Errorf("endpoint %q must be an http or https url", endpoint)
+	}
+	return nil
+}
+
+// validateSyncModule validates that a single sync module is set, and its endpoint
+func validateSyncModule(spec *cephv1.ZoneSyncModuleSpec) error {
+	if spec == nil {
+		return nil
+	}
+	if (spec.Cloud != nil) == (spec.PubSub != nil) {
+		return errors.New("exactly one of the cloud or pubsub sync modules must be set")
+	}
+
+	switch {
+	case spec.Cloud != nil:
+		if spec.Cloud.CredentialsSecretName == "" {
+			return errors.New("invalid cloud sync module: credentials secret name is required")
+		}
+		return errors.Wrap(validateEndpoint(spec.Cloud.Endpoint), "invalid cloud sync module")
+	case spec.PubSub.UID == "":
+		return errors.New("invalid pubsub sync module: uid is required")
+	}
+	return nil
+}
+
+// reconcileSyncModule configures the sync module of an existing zone and commits the period when the tier of the
+// zone changes. The zone is left unchanged when the sync module is removed from the spec.
+func (r *ReconcileObjectZone) reconcileSyncModule(objContext *object.Context, zone *cephv1.CephObjectZone, isMaster bool, currentTierType, zoneGetOutput string) error {
+	if zone.Spec.SyncModule == nil {
+		return nil
+	}
+	if isMaster {
+		return errors.Errorf("sync module cannot be configured on zone %q since it is the master zone of zone group %q", zone.Name, zone.Spec.ZoneGroup)
+	}
+	tierType, config, err := syncModuleTierConfig(zone)
+	if err != nil {
+		return err
+	}
+	changed := currentTierType != tierType
+	if !changed {
+		changed, err = tierConfigChanged(config, zoneGetOutput)
+		if err != nil {
+			return err
+		}
+	}
+	if changed {
+		if err := configureSyncModule(objContext, zone, tierType, config); err != nil {
+			return err
+		}
+	}
+	if zone.Spec.SyncModule.Cloud != nil {
+		credentialsChanged, err := r.setCloudSyncModuleCredentials(objContext, zone)
+		if err != nil {
+			return err
+		}
+		changed = changed || credentialsChanged
+	}
+	if !changed {
+		return nil
+	}
+
+	if err := commitConfigChanges(objContext); err != nil {
+		return errors.Wrapf(err, "failed to commit the sync module of zone %q", zone.Name)
+	}
+	return nil
+}
+
+// configureSyncModule sets the tier type and the tier config of the sync module of an existing zone
+func configureSyncModule(objContext *object.Context, zone *cephv1.CephObjectZone, tierType string, config map[string]string) error {
+	logger.Infof("configuring the %q sync module of zone %q", tierType, zone.Name)
+	args := []string{"zone", "modify",
+		fmt.Sprintf("--rgw-realm=%s", objContext.Realm),
+		fmt.Sprintf("--rgw-zonegroup=%s", objContext.ZoneGroup),
+		fmt.Sprintf("--rgw-zone=%s", objContext.Zone),
+	}
+	args = append(args, syncModuleArgs(tierType, config)...)
+	output, err := object.RunAdminCommandNoMultisite(objContext, false, args...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to configure the sync module of zone %q for reason %q", zone.Name, output)
+	}
+	return nil
+}
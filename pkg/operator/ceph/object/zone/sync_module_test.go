@@ -0,0 +1,194 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zone
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+	"github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newSyncModuleTestReconciler(t *testing.T, executor *exectest.MockExecutor) *ReconcileObjectZone {
+	clientset := test.New(t, 1)
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "aws-credentials", Namespace: "rook-ceph"},
+		Data:       map[string][]byte{object.AccessKeyName: []byte("access"), object.SecretKeyName: []byte("secret")},
+	}
+	_, err := clientset.CoreV1().Secrets("rook-ceph").Create(context.TODO(), secret, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	return &ReconcileObjectZone{
+		context:          &clusterd.Context{Executor: executor, Clientset: clientset},
+		clusterInfo:      cephclient.AdminTestClusterInfo("rook-ceph"),
+		opManagerContext: context.TODO(),
+	}
+}
+
+func TestSyncModuleTierConfig(t *testing.T) {
+	zone := &cephv1.CephObjectZone{ObjectMeta: metav1.ObjectMeta{Name: "zone-a", Namespace: "rook-ceph"}}
+
+	t.Run("cloud", func(t *testing.T) {
+		zone.Spec.SyncModule = &cephv1.ZoneSyncModuleSpec{Cloud: &cephv1.CloudSyncModuleSpec{
+			Endpoint: "https://s3.us-east-1.amazonaws.com", CredentialsSecretName: "aws-credentials", HostStyle: "virtual", TargetPath: "rgw-${zonegroup}",
+		}}
+		tierType, config, err := syncModuleTierConfig(zone)
+		assert.NoError(t, err)
+		// the credentials are not passed on the command line
+		assert.Equal(t, []string{
+			"--tier-type=cloud",
+			"--tier-config=connection.endpoint=https://s3.us-east-1.amazonaws.com,connection.host_style=virtual,target_path=rgw-${zonegroup}",
+		}, syncModuleArgs(tierType, config))
+	})
+
+	t.Run("pubsub", func(t *testing.T) {
+		days := int32(7)
+		zone.Spec.SyncModule = &cephv1.ZoneSyncModuleSpec{PubSub: &cephv1.PubSubSyncModuleSpec{UID: "pubsub", EventsRetentionDays: &days}}
+		tierType, config, err := syncModuleTierConfig(zone)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"--tier-type=pubsub", "--tier-config=events_retention_days=7,uid=pubsub"}, syncModuleArgs(tierType, config))
+	})
+}
+
+func TestTierConfigChanged(t *testing.T) {
+	config := map[string]string{"endpoint": "http://es.example.com:9200", "num_shards": "16"}
+
+	changed, err := tierConfigChanged(config, `{"tier_config": {"endpoint": "http://es.example.com:9200", "num_shards": 16, "num_replicas": 1}}`)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	changed, err = tierConfigChanged(config, `{"tier_config": {"endpoint": "http://es.example.com:9200", "num_shards": "8"}}`)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+
+	changed, err = tierConfigChanged(config, `{"id": "test-id"}`)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+
+	// the nested options are compared by their path
+	changed, err = tierConfigChanged(map[string]string{"connection.endpoint": "https://s3.example.com"}, `{"tier_config": {"connection": {"endpoint": "https://s3.example.com"}}}`)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	_, err = tierConfigChanged(config, `not json`)
+	assert.Error(t, err)
+}
+
+func TestValidateSyncModule(t *testing.T) {
+	assert.NoError(t, validateSyncModule(nil))
+	assert.Error(t, validateSyncModule(&cephv1.ZoneSyncModuleSpec{}))
+	assert.Error(t, validateSyncModule(&cephv1.ZoneSyncModuleSpec{
+		Cloud:  &cephv1.CloudSyncModuleSpec{Endpoint: "https://s3.example.com", CredentialsSecretName: "creds"},
+		PubSub: &cephv1.PubSubSyncModuleSpec{UID: "pubsub"},
+	}))
+
+	assert.NoError(t, validateSyncModule(&cephv1.ZoneSyncModuleSpec{Cloud: &cephv1.CloudSyncModuleSpec{Endpoint: "https://s3.example.com", CredentialsSecretName: "creds"}}))
+	assert.Error(t, validateSyncModule(&cephv1.ZoneSyncModuleSpec{Cloud: &cephv1.CloudSyncModuleSpec{Endpoint: "https://s3.example.com"}}))
+	assert.Error(t, validateSyncModule(&cephv1.ZoneSyncModuleSpec{Cloud: &cephv1.CloudSyncModuleSpec{Endpoint: "s3.example.com", CredentialsSecretName: "creds"}}))
+	assert.Error(t, validateSyncModule(&cephv1.ZoneSyncModuleSpec{Cloud: &cephv1.CloudSyncModuleSpec{Endpoint: "ftp://s3.example.com", CredentialsSecretName: "creds"}}))
+
+	assert.NoError(t, validateSyncModule(&cephv1.ZoneSyncModuleSpec{PubSub: &cephv1.PubSubSyncModuleSpec{UID: "pubsub"}}))
+	assert.Error(t, validateSyncModule(&cephv1.ZoneSyncModuleSpec{PubSub: &cephv1.PubSubSyncModuleSpec{}}))
+}
+
+func TestReconcileSyncModule(t *testing.T) {
+	modified := []string{}
+	currentZone := `{"id": "test-id", "tier_config": {"connection": {"endpoint": "https://s3.example.com"}}}`
+	stdin := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			for _, arg := range args {
+				// the credentials must not be passed on the command line
+				assert.NotContains(t, arg, "secret")
+			}
+			if args[0] == "zone" && args[1] == "modify" {
+				modified = append(modified, args[5:7]...)
+			}
+			if args[0] == "zone" && args[1] == "get" {
+				return currentZone, nil
+			}
+			return "", nil
+		},
+		MockExecuteCommandWithStdin: func(timeout time.Duration, command string, in *string, args ...string) error {
+			assert.Equal(t, []string{"zone", "set"}, args[0:2])
+			stdin = append(stdin, *in)
+			currentZone = *in
+			return nil
+		},
+	}
+	commits := 0
+	commitConfigChanges = func(c *object.Context) error {
+		commits++
+		return nil
+	}
+	defer func() { commitConfigChanges = object.CommitConfigChanges }()
+
+	r := newSyncModuleTestReconciler(t, executor)
+	objContext := object.NewContext(r.context, r.clusterInfo, "zone-a")
+	objContext.Realm, objContext.ZoneGroup, objContext.Zone = "realm-a", "zonegroup-a", "zone-a"
+	zone := &cephv1.CephObjectZone{
+		ObjectMeta: metav1.ObjectMeta{Name: "zone-a", Namespace: "rook-ceph"},
+		Spec:       cephv1.ObjectZoneSpec{ZoneGroup: "zonegroup-a"},
+	}
+
+	// the zone is left unchanged without the sync module
+	assert.NoError(t, r.reconcileSyncModule(objContext, zone, false, "pubsub", zoneGetOutput))
+	assert.Empty(t, modified)
+
+	zone.Spec.SyncModule = &cephv1.ZoneSyncModuleSpec{PubSub: &cephv1.PubSubSyncModuleSpec{UID: "pubsub"}}
+	assert.Error(t, r.reconcileSyncModule(objContext, zone, true, "", zoneGetOutput))
+	assert.Empty(t, modified)
+
+	assert.NoError(t, r.reconcileSyncModule(objContext, zone, false, "", zoneGetOutput))
+	assert.Equal(t, []string{"--tier-type=pubsub", "--tier-config=uid=pubsub"}, modified)
+	assert.Equal(t, 1, commits)
+	assert.Empty(t, stdin)
+
+	// the period is not committed again when the tier config is up to date
+	modified = []string{}
+	assert.NoError(t, r.reconcileSyncModule(objContext, zone, false, "pubsub", `{"tier_config": {"uid": "pubsub"}}`))
+	assert.Empty(t, modified)
+	assert.Equal(t, 1, commits)
+
+	// the tier of the zone is changed to the cloud sync module, with the credentials set on stdin
+	zone.Spec.SyncModule = &cephv1.ZoneSyncModuleSpec{Cloud: &cephv1.CloudSyncModuleSpec{Endpoint: "https://s3.example.com", CredentialsSecretName: "aws-credentials"}}
+	assert.NoError(t, r.reconcileSyncModule(objContext, zone, false, "pubsub", `{"tier_config": {"uid": "pubsub"}}`))
+	assert.Equal(t, []string{"--tier-type=cloud", "--tier-config=connection.endpoint=https://s3.example.com"}, modified)
+	assert.Len(t, stdin, 1)
+	assert.Contains(t, stdin[0], `"access_key":"access"`)
+	assert.Contains(t, stdin[0], `"secret":"secret"`)
+	assert.Equal(t, 2, commits)
+
+	// the zone is not set again when the credentials are unchanged
+	modified = []string{}
+	assert.NoError(t, r.reconcileSyncModule(objContext, zone, false, "cloud", currentZone))
+	assert.Empty(t, modified)
+	assert.Len(t, stdin, 1)
+	assert.Equal(t, 2, commits)
+
+	// the credentials are required
+	zone.Spec.SyncModule.Cloud.CredentialsSecretName = "missing"
+	assert.Error(t, r.reconcileSyncModule(objContext, zone, false, "cloud", currentZone))
+}
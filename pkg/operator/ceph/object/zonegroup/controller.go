@@ -173,6 +173,13 @@ func (r *ReconcileObjectZoneGroup) reconcile(request reconcile.Request) (reconci
 		return reconcile.Result{}, errors.Wrapf(err, "invalid CephObjectZoneGroup CR %q", cephObjectZoneGroup.Name)
 	}
 
+	// the rate limits are global to the realm, so only one zone group of the realm can set them
+	err = r.validateRealmRateLimits(cephObjectZoneGroup)
+	if err != nil {
+		r.updateStatus(k8sutil.ObservedGenerationNotAvailable, request.NamespacedName, k8sutil.ReconcileFailedStatus)
+		return reconcile.Result{}, errors.Wrapf(err, "invalid CephObjectZoneGroup CR %q", cephObjectZoneGroup.Name)
+	}
+
 	// Start object reconciliation, updating status for this
 	r.updateStatus(k8sutil.ObservedGenerationNotAvailable, request.NamespacedName, k8sutil.ReconcilingStatus)
 
@@ -234,7 +241,7 @@ func (r *ReconcileObjectZoneGroup) createCephZoneGroup(zoneGroup *cephv1.CephObj
 	// create zone group
 	output, err = object.RunAdminCommandNoMultisite(objContext, true, "zonegroup", "get", realmArg, zoneGroupArg)
 	if err == nil {
-		return reconcile.Result{}, nil
+		return reconcile.Result{}, r.updateCephZoneGroup(objContext, zoneGroup, output)
 	}
 
 	if code, ok := exec.ExitStatus(err); ok && code == int(syscall.ENOENT) {
@@ -259,7 +266,96 @@ func (r *ReconcileObjectZoneGroup) createCephZoneGroup(zoneGroup *cephv1.CephObj
 		return reconcile.Result{}, errors.Wrapf(err, "radosgw-admin zonegroup get failed with code %d for reason %q", code, output)
 	}
 
-	return reconcile.Result{}, nil
+	// the output of the creation is the new zone group
+	return reconcile.Result{}, r.updateCephZoneGroup(objContext, zoneGroup, output)
+}
+
+// updateCephZoneGroup applies the features and the rate limits of the spec to the ceph zone group
+func (r *ReconcileObjectZoneGroup) updateCephZoneGroup(objContext *object.Context, zoneGroup *cephv1.CephObjectZoneGroup, zoneGroupJSON string) error {
+	if err := r.enableZoneGroupFeatures(objContext, zoneGroup, zoneGroupJSON); err != nil {
+		return err
+	}
+	return r.setZoneGroupRateLimits(objContext, zoneGroup)
+}
+
+// enableZoneGroupFeatures enables the features of the spec that are not enabled in the zone group yet
+func (r *ReconcileObjectZoneGroup) enableZoneGroupFeatures(objContext *object.Context, zoneGroup *cephv1.CephObjectZoneGroup, zoneGroupJSON string) error {
+	if len(zoneGroup.Spec.EnabledFeatures) == 0 {
+		return nil
+	}
+	features, err := missingFeatures(zoneGroupJSON, zoneGroup.Spec.EnabledFeatures)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the features of ceph zone group %q", zoneGroup.Name)
+	}
+	if len(features) == 0 {
+		return nil
+	}
+
+	logger.Infof("enabling features %v in object zone group %q", features, zoneGroup.Name)
+	realmArg := fmt.Sprintf("--rgw-realm=%s", zoneGroup.Spec.Realm)
+	zoneGroupArg := fmt.Sprintf("--rgw-zonegroup=%s", zoneGroup.Name)
+	args := []string{"zonegroup", "modify", realmArg, zoneGroupArg}
+	for _, feature := range features {
+		args = append(args, fmt.Sprintf("--enable-feature=%s", feature))
+	}
+	output, err := object.RunAdminCommandNoMultisite(objContext, false, args...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to enable features %v in ceph zone group %q for reason %q", features, zoneGroup.Name, output)
+	}
+
+	args = []string{"period", "update", "--commit", realmArg, zoneGroupArg}
+	output, err = object.RunAdminCommandNoMultisite(objContext, false, args...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to commit updates in ceph zone group %q for reason %q", zoneGroup.Name, output)
+	}
+	return nil
+}
+
+// setZoneGroupRateLimits sets the default rate limits of the users and the buckets of the spec, and commits the period
+// when they changed
+func (r *ReconcileObjectZoneGroup) setZoneGroupRateLimits(objContext *object.Context, zoneGroup *cephv1.CephObjectZoneGroup) error {
+	defaults := zoneGroup.Spec.Defaults
+	if defaults == nil {
+		return nil
+	}
+	userChanged, err := object.SetGlobalRateLimit(objContext, zoneGroup.Spec.Realm, object.RateLimitScopeUser, object.RateLimitFromSpec(defaults.UserRateLimit))
+	if err != nil {
+		return errors.Wrapf(err, "failed to set the user rate limit of ceph zone group %q", zoneGroup.Name)
+	}
+	bucketChanged, err := object.SetGlobalRateLimit(objContext, zoneGroup.Spec.Realm, object.RateLimitScopeBucket, object.RateLimitFromSpec(defaults.BucketRateLimit))
+	if err != nil {
+		return errors.Wrapf(err, "failed to set the bucket rate limit of ceph zone group %q", zoneGroup.Name)
+	}
+	if !userChanged && !bucketChanged {
+		return nil
+	}
+
+	realmArg := fmt.Sprintf("--rgw-realm=%s", zoneGroup.Spec.Realm)
+	zoneGroupArg := fmt.Sprintf("--rgw-zonegroup=%s", zoneGroup.Name)
+	output, err := object.RunAdminCommandNoMultisite(objContext, false, "period", "update", "--commit", realmArg, zoneGroupArg)
+	if err != nil {
+		return errors.Wrapf(err, "failed to commit the rate limits of ceph zone group %q for reason %q", zoneGroup.Name, output)
+	}
+	return nil
+}
+
+// validateRealmRateLimits fails when another zone group of the realm also sets the rate limits
+func (r *ReconcileObjectZoneGroup) validateRealmRateLimits(zoneGroup *cephv1.CephObjectZoneGroup) error {
+	if !hasRateLimits(zoneGroup) {
+		return nil
+	}
+	zoneGroups := &cephv1.CephObjectZoneGroupList{}
+	err := r.client.List(r.opManagerContext, zoneGroups, client.InNamespace(zoneGroup.Namespace))
+	if err != nil {
+		return errors.Wrap(err, "failed to list the zone groups")
+	}
+	for i := range zoneGroups.Items {
+		other := &zoneGroups.Items[i]
+		if other.Name != zoneGroup.Name && other.Spec.Realm == zoneGroup.Spec.Realm && hasRateLimits(other) {
+			return errors.Errorf("zone group %q of realm %q already sets the rate limits of the realm", other.Name, zoneGroup.Spec.Realm)
+		}
+	}
+	return nil
 }
 
 func (r *ReconcileObjectZoneGroup) reconcileObjectRealm(zoneGroup *cephv1.CephObjectZoneGroup) (reconcile.Result, error) {
@@ -29,6 +29,7 @@ import (
 
 	"github.com/rook/rook/pkg/clusterd"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/object"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/stretchr/testify/assert"
@@ -362,3 +363,125 @@ func TestCephObjectZoneGroupController(t *testing.T) {
 	err = r.client.Get(context.TODO(), req.NamespacedName, objectZoneGroup)
 	assert.NoError(t, err)
 }
+
+func TestMissingFeatures(t *testing.T) {
+	missing, err := missingFeatures(`{"name":"zonegroup-a","enabled_features":["resharding"]}`, []string{"resharding", "compress-encrypted", "compress-encrypted"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"compress-encrypted"}, missing)
+
+	// the zone groups of the ceph versions without features
+	missing, err = missingFeatures(zoneGroupGetJSON, []string{"resharding"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"resharding"}, missing)
+
+	_, err = missingFeatures("", []string{"resharding"})
+	assert.Error(t, err)
+}
+
+func TestEnableZoneGroupFeatures(t *testing.T) {
+	commands := [][]string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			commands = append(commands, args)
+			return "", nil
+		},
+	}
+	clusterInfo := cephclient.AdminTestClusterInfo(namespace)
+	r := &ReconcileObjectZoneGroup{context: &clusterd.Context{Executor: executor}, clusterInfo: clusterInfo}
+	zoneGroup := &cephv1.CephObjectZoneGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       cephv1.ObjectZoneGroupSpec{Realm: realm},
+	}
+	objContext := object.NewContext(r.context, clusterInfo, name)
+
+	// no features requested
+	assert.NoError(t, r.enableZoneGroupFeatures(objContext, zoneGroup, ""))
+	assert.Empty(t, commands)
+
+	// the features are already enabled
+	zoneGroup.Spec.EnabledFeatures = []string{"resharding"}
+	assert.NoError(t, r.enableZoneGroupFeatures(objContext, zoneGroup, `{"enabled_features":["resharding"]}`))
+	assert.Empty(t, commands)
+
+	// the feature is enabled and the period is committed
+	assert.NoError(t, r.enableZoneGroupFeatures(objContext, zoneGroup, zoneGroupGetJSON))
+	assert.Len(t, commands, 2)
+	assert.Equal(t, []string{"zonegroup", "modify", "--rgw-realm=realm-a", "--rgw-zonegroup=zonegroup-a", "--enable-feature=resharding"}, commands[0][:5])
+	assert.Equal(t, []string{"period", "update", "--commit"}, commands[1][:3])
+}
+
+func TestSetZoneGroupRateLimits(t *testing.T) {
+	commands := [][]string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			commands = append(commands, args)
+			if args[0] == "global" && args[2] == "get" {
+				return `{"bucket_ratelimit":{"max_read_ops":0,"max_write_ops":0,"max_read_bytes":0,"max_write_bytes":0,"enabled":false},
+					"user_ratelimit":{"max_read_ops":100,"max_write_ops":0,"max_read_bytes":0,"max_write_bytes":0,"enabled":true}}`, nil
+			}
+			return "", nil
+		},
+	}
+	clusterInfo := cephclient.AdminTestClusterInfo(namespace)
+	r := &ReconcileObjectZoneGroup{context: &clusterd.Context{Executor: executor}, clusterInfo: clusterInfo}
+	zoneGroup := &cephv1.CephObjectZoneGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       cephv1.ObjectZoneGroupSpec{Realm: realm},
+	}
+	objContext := object.NewContext(r.context, clusterInfo, name)
+
+	// no rate limits requested
+	assert.NoError(t, r.setZoneGroupRateLimits(objContext, zoneGroup))
+	assert.Empty(t, commands)
+
+	// the user rate limit is already set
+	ops := int64(100)
+	zoneGroup.Spec.Defaults = &cephv1.ZoneGroupDefaultsSpec{UserRateLimit: &cephv1.ObjectRateLimitSpec{MaxReadOps: &ops}}
+	assert.NoError(t, r.setZoneGroupRateLimits(objContext, zoneGroup))
+	assert.Len(t, commands, 1)
+
+	// the bucket rate limit is set and enabled and the period is committed
+	commands = [][]string{}
+	zoneGroup.Spec.Defaults.BucketRateLimit = &cephv1.ObjectRateLimitSpec{MaxWriteOps: &ops}
+	assert.NoError(t, r.setZoneGroupRateLimits(objContext, zoneGroup))
+	assert.Len(t, commands, 5)
+	assert.Equal(t, []string{"global", "ratelimit", "set", "--ratelimit-scope=bucket"}, commands[2][:4])
+	assert.Equal(t, []string{"global", "ratelimit", "enable", "--ratelimit-scope=bucket"}, commands[3][:4])
+	assert.Equal(t, []string{"period", "update", "--commit"}, commands[4][:3])
+}
+
+func TestValidateRealmRateLimits(t *testing.T) {
+	ops := int64(100)
+	rateLimits := &cephv1.ZoneGroupDefaultsSpec{UserRateLimit: &cephv1.ObjectRateLimitSpec{MaxReadOps: &ops}}
+	zoneGroup := &cephv1.CephObjectZoneGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       cephv1.ObjectZoneGroupSpec{Realm: realm, Defaults: rateLimits},
+	}
+	newReconciler := func(objects ...runtime.Object) *ReconcileObjectZoneGroup {
+		s := runtime.NewScheme()
+		s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephObjectZoneGroup{}, &cephv1.CephObjectZoneGroupList{})
+		cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(objects...).Build()
+		return &ReconcileObjectZoneGroup{client: cl, opManagerContext: context.TODO()}
+	}
+	otherZoneGroup := func(name, realm string, defaults *cephv1.ZoneGroupDefaultsSpec) *cephv1.CephObjectZoneGroup {
+		return &cephv1.CephObjectZoneGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       cephv1.ObjectZoneGroupSpec{Realm: realm, Defaults: defaults},
+		}
+	}
+
+	t.Run("only zone group with rate limits", func(t *testing.T) {
+		r := newReconciler(zoneGroup, otherZoneGroup("zonegroup-b", realm, nil), otherZoneGroup("zonegroup-c", "realm-b", rateLimits))
+		assert.NoError(t, r.validateRealmRateLimits(zoneGroup))
+	})
+
+	t.Run("another zone group of the realm sets rate limits", func(t *testing.T) {
+		r := newReconciler(zoneGroup, otherZoneGroup("zonegroup-b", realm, rateLimits))
+		assert.Error(t, r.validateRealmRateLimits(zoneGroup))
+	})
+
+	t.Run("no rate limits", func(t *testing.T) {
+		r := newReconciler(otherZoneGroup("zonegroup-b", realm, rateLimits))
+		assert.NoError(t, r.validateRealmRateLimits(otherZoneGroup(name, realm, &cephv1.ZoneGroupDefaultsSpec{})))
+	})
+}
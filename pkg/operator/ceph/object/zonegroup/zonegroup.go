@@ -37,6 +37,37 @@ func decodeMasterZoneGroup(data string) (string, error) {
 	return periodGet.MasterZoneGroup, err
 }
 
+type zoneGroupFeaturesType struct {
+	EnabledFeatures []string `json:"enabled_features"`
+}
+
+// missingFeatures returns the features that are not enabled in the zone group yet
+func missingFeatures(zoneGroupJSON string, features []string) ([]string, error) {
+	var zoneGroup zoneGroupFeaturesType
+	if err := json.Unmarshal([]byte(zoneGroupJSON), &zoneGroup); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal json")
+	}
+	enabled := map[string]bool{}
+	for _, feature := range zoneGroup.EnabledFeatures {
+		enabled[feature] = true
+	}
+
+	missing := []string{}
+	for _, feature := range features {
+		if !enabled[feature] {
+			missing = append(missing, feature)
+			enabled[feature] = true
+		}
+	}
+	return missing, nil
+}
+
+// hasRateLimits returns whether the zone group sets the rate limits of its realm
+func hasRateLimits(zoneGroup *cephv1.CephObjectZoneGroup) bool {
+	defaults := zoneGroup.Spec.Defaults
+	return defaults != nil && (defaults.UserRateLimit != nil || defaults.BucketRateLimit != nil)
+}
+
 // validateZoneGroup validates the zonegroup arguments
 func validateZoneGroup(u *cephv1.CephObjectZoneGroup) error {
 	if u.Name == "" {
@@ -0,0 +1,254 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package operation runs the long-running operations on the Ceph cluster requested with the CephOperation CRs.
+package operation
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName = "ceph-operation-controller"
+)
+
+var logger = capnslog.NewPackageLogger("github.com/rook/rook", controllerName)
+
+var cephOperationKind = reflect.TypeOf(cephv1.CephOperation{}).Name()
+
+// Sets the type meta for the controller main object
+var controllerTypeMeta = metav1.TypeMeta{
+	Kind:       cephOperationKind,
+	APIVersion: fmt.Sprintf("%s/%s", cephv1.CustomResourceGroup, cephv1.Version),
+}
+
+// waitForStepResult is the requeue of a step that waits for the cluster, e.g. for an OSD to be safe to destroy
+var waitForStepResult = reconcile.Result{Requeue: true, RequeueAfter: time.Minute}
+
+// ReconcileCephOperation reconciles a CephOperation object
+type ReconcileCephOperation struct {
+	client           client.Client
+	scheme           *runtime.Scheme
+	context          *clusterd.Context
+	clusterInfo      *cephclient.ClusterInfo
+	opManagerContext context.Context
+	recorder         record.EventRecorder
+	tasks            backgroundTasks
+}
+
+// Add creates a new CephOperation Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
+	return add(mgr, newReconciler(mgr, context, opManagerContext))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context) reconcile.Reconciler {
+	return &ReconcileCephOperation{
+		client:           mgr.GetClient(),
+		scheme:           mgr.GetScheme(),
+		context:          context,
+		opManagerContext: opManagerContext,
+		recorder:         mgr.GetEventRecorderFor("rook-" + controllerName),
+	}
+}
+
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+	logger.Info("successfully started")
+
+	// Watch for changes on the CephOperation CRD object
+	err = c.Watch(&source.Kind{Type: &cephv1.CephOperation{TypeMeta: controllerTypeMeta}}, &handler.EnqueueRequestForObject{}, opcontroller.WatchControllerPredicate())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Reconcile runs the next step of a CephOperation and records the progress in its status. The Controller will requeue
+// the Request to be processed again if the returned error is non-nil or Result.Requeue is true, otherwise upon
+// completion it will remove the work from the queue.
+func (r *ReconcileCephOperation) Reconcile(context context.Context, request reconcile.Request) (reconcile.Result, error) {
+	// workaround because the rook logging mechanism is not compatible with the controller-runtime logging interface
+	reconcileResponse, err := r.reconcile(request)
+	if err != nil {
+		logger.Errorf("failed to reconcile %q. %v", request.NamespacedName, err)
+	}
+
+	return reconcileResponse, err
+}
+
+func (r *ReconcileCephOperation) reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// Fetch the CephOperation instance
+	cephOperation := &cephv1.CephOperation{}
+	err := r.client.Get(r.opManagerContext, request.NamespacedName, cephOperation)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debugf("CephOperation %q not found. Ignoring since object must be deleted.", request.NamespacedName)
+			r.tasks.forget(request.NamespacedName)
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, errors.Wrap(err, "failed to get CephOperation")
+	}
+
+	// The operations that are done are never run again
+	if isDone(cephOperation.Status) {
+		logger.Debugf("CephOperation %q is %s", request.NamespacedName, cephOperation.Status.Phase)
+		return reconcile.Result{}, nil
+	}
+
+	if cephOperation.Spec.Cancel {
+		logger.Infof("cancelling CephOperation %q", request.NamespacedName)
+		r.tasks.forget(request.NamespacedName)
+		return reconcile.Result{}, r.complete(cephOperation, cephv1.CephOperationCancelled, "cancelled by the user")
+	}
+
+	// Make sure a CephCluster is present otherwise do nothing
+	cephCluster, isReadyToReconcile, _, reconcileResponse := opcontroller.IsReadyToReconcile(r.opManagerContext, r.client, request.NamespacedName, controllerName)
+	if !isReadyToReconcile {
+		logger.Debugf("CephCluster resource not ready in namespace %q, retrying in %q.", request.NamespacedName.Namespace, reconcileResponse.RequeueAfter.String())
+		return reconcileResponse, nil
+	}
+	if cephCluster.Spec.External.Enable {
+		return reconcile.Result{}, r.complete(cephOperation, cephv1.CephOperationFailed, "the operations are not supported on an external cluster")
+	}
+
+	// Populate clusterInfo during each reconcile
+	r.clusterInfo, _, _, err = opcontroller.LoadClusterInfo(r.context, r.opManagerContext, request.NamespacedName.Namespace, &cephCluster.Spec)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to populate cluster info")
+	}
+	r.clusterInfo.Context = r.opManagerContext
+
+	steps, err := r.operationSteps(cephOperation)
+	if err != nil {
+		return reconcile.Result{}, r.complete(cephOperation, cephv1.CephOperationFailed, fmt.Sprintf("invalid operation. %v", err))
+	}
+
+	// The CR was just created, start the operation
+	if cephOperation.Status == nil {
+		now := metav1.Now()
+		cephOperation.Status = &cephv1.CephOperationStatus{
+			Phase:      cephv1.CephOperationRunning,
+			TotalSteps: len(steps),
+			StartTime:  &now,
+		}
+	}
+	if cephOperation.Status.TotalSteps != len(steps) {
+		return reconcile.Result{}, r.complete(cephOperation, cephv1.CephOperationFailed, "the steps of the operation changed while it was running, create a new operation instead")
+	}
+	return r.runNextStep(cephOperation, steps)
+}
+
+// runNextStep runs the next step of the operation. The progress is saved after each step, so that an operator
+// restart only runs again the step that was in progress.
+func (r *ReconcileCephOperation) runNextStep(cephOperation *cephv1.CephOperation, steps []step) (reconcile.Result, error) {
+	nsName := fmt.Sprintf("%s/%s", cephOperation.Namespace, cephOperation.Name)
+	status := cephOperation.Status
+	if status.CompletedSteps >= len(steps) {
+		return reconcile.Result{}, r.complete(cephOperation, cephv1.CephOperationSucceeded, "")
+	}
+
+	next := steps[status.CompletedSteps]
+	if status.CurrentStep != next.description {
+		status.CurrentStep = next.description
+		status.Message = ""
+		if err := reporting.UpdateStatus(r.client, cephOperation); err != nil {
+			return reconcile.Result{}, errors.Wrapf(err, "failed to save the progress of CephOperation %q", nsName)
+		}
+	}
+
+	logger.Infof("CephOperation %q: running step %d/%d %q", nsName, status.CompletedSteps+1, len(steps), next.description)
+	done, err := next.run()
+	if err != nil {
+		if strings.Contains(err.Error(), opcontroller.UninitializedCephConfigError) {
+			logger.Info(opcontroller.OperatorNotInitializedMessage)
+			return opcontroller.WaitForRequeueIfOperatorNotInitialized, nil
+		}
+		r.recorder.Eventf(cephOperation, "Warning", "StepFailed", "step %q failed. %v", next.description, err)
+		return reconcile.Result{}, r.complete(cephOperation, cephv1.CephOperationFailed, fmt.Sprintf("step %q failed. %v", next.description, err))
+	}
+	if !done {
+		logger.Infof("CephOperation %q: step %q is waiting, retrying in %s", nsName, next.description, waitForStepResult.RequeueAfter.String())
+		if status.Message != next.waitMessage {
+			status.Message = next.waitMessage
+			if err := reporting.UpdateStatus(r.client, cephOperation); err != nil {
+				return reconcile.Result{}, errors.Wrapf(err, "failed to save the progress of CephOperation %q", nsName)
+			}
+		}
+		return waitForStepResult, nil
+	}
+
+	status.CompletedSteps++
+	status.Message = ""
+	if status.CompletedSteps >= len(steps) {
+		return reconcile.Result{}, r.complete(cephOperation, cephv1.CephOperationSucceeded, "")
+	}
+	if err := reporting.UpdateStatus(r.client, cephOperation); err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "failed to save the progress of CephOperation %q", nsName)
+	}
+	// run the next step in a new reconcile, so that a cancellation is seen before it starts
+	return opcontroller.ImmediateRetryResultNoBackoff, nil
+}
+
+// complete records the final phase of the operation
+func (r *ReconcileCephOperation) complete(cephOperation *cephv1.CephOperation, phase cephv1.CephOperationPhase, message string) error {
+	if cephOperation.Status == nil {
+		cephOperation.Status = &cephv1.CephOperationStatus{}
+	}
+	now := metav1.Now()
+	cephOperation.Status.Phase = phase
+	cephOperation.Status.Message = message
+	cephOperation.Status.CurrentStep = ""
+	cephOperation.Status.CompletionTime = &now
+	if err := reporting.UpdateStatus(r.client, cephOperation); err != nil {
+		return errors.Wrapf(err, "failed to set CephOperation %q to %s", cephOperation.Name, phase)
+	}
+	logger.Infof("CephOperation %q %s", fmt.Sprintf("%s/%s", cephOperation.Namespace, cephOperation.Name), strings.ToLower(string(phase)))
+	return nil
+}
+
+func isDone(status *cephv1.CephOperationStatus) bool {
+	return status != nil && status.Phase != "" && status.Phase != cephv1.CephOperationRunning
+}
@@ -0,0 +1,257 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operation
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	testop "github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestCephOperationController(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "compact", Namespace: namespace}}
+	// the compactions complete in the reconcile that starts them
+	oldRunInBackground := runInBackground
+	runInBackground = func(command func()) { command() }
+	defer func() { runInBackground = oldRunInBackground }()
+
+	cephCluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: namespace, Namespace: namespace},
+		Status: cephv1.ClusterStatus{
+			Phase:       cephv1.ConditionReady,
+			CephVersion: &cephv1.ClusterVersion{Version: "17.2.5-0"},
+			CephStatus:  &cephv1.CephStatus{Health: "HEALTH_OK"},
+		},
+	}
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-mon", Namespace: namespace},
+		Data: map[string][]byte{
+			"fsid":         []byte("fsid"),
+			"mon-secret":   []byte("monsecret"),
+			"admin-secret": []byte("adminsecret"),
+		},
+		Type: k8sutil.RookType,
+	}
+
+	setup := func(t *testing.T, cephOperation *cephv1.CephOperation) (*ReconcileCephOperation, *[]string) {
+		commands := []string{}
+		executor := &exectest.MockExecutor{
+			MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+				commands = append(commands, strings.Join(args[:3], " "))
+				return "", nil
+			},
+		}
+		clientset := testop.New(t, 1)
+		_, err := clientset.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{})
+		assert.NoError(t, err)
+
+		s := scheme.Scheme
+		s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephOperation{}, &cephv1.CephOperationList{}, &cephv1.CephCluster{}, &cephv1.CephClusterList{})
+		cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects([]runtime.Object{cephOperation, cephCluster}...).Build()
+		c := &clusterd.Context{Executor: executor, Clientset: clientset, Client: cl}
+		r := &ReconcileCephOperation{client: cl, scheme: s, context: c, opManagerContext: ctx, recorder: record.NewFakeRecorder(5)}
+		return r, &commands
+	}
+
+	getOperation := func(t *testing.T, r *ReconcileCephOperation) *cephv1.CephOperation {
+		cephOperation := &cephv1.CephOperation{}
+		assert.NoError(t, r.client.Get(ctx, req.NamespacedName, cephOperation))
+		return cephOperation
+	}
+
+	t.Run("the steps run one per reconcile and the progress is saved", func(t *testing.T) {
+		r, commands := setup(t, &cephv1.CephOperation{
+			ObjectMeta: metav1.ObjectMeta{Name: req.Name, Namespace: namespace},
+			Spec: cephv1.CephOperationSpec{
+				Compaction: &cephv1.CompactionOperationSpec{Daemons: []string{"osd.0", "mon.a"}},
+			},
+		})
+
+		res, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.True(t, res.Requeue)
+		assert.Equal(t, []string{"tell osd.0 compact"}, *commands)
+		status := getOperation(t, r).Status
+		assert.Equal(t, cephv1.CephOperationRunning, status.Phase)
+		assert.Equal(t, 2, status.TotalSteps)
+		assert.Equal(t, 1, status.CompletedSteps)
+		assert.NotNil(t, status.StartTime)
+
+		res, err = r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.False(t, res.Requeue)
+		assert.Equal(t, []string{"tell osd.0 compact", "tell mon.a compact"}, *commands)
+		status = getOperation(t, r).Status
+		assert.Equal(t, cephv1.CephOperationSucceeded, status.Phase)
+		assert.Equal(t, 2, status.CompletedSteps)
+		assert.NotNil(t, status.CompletionTime)
+
+		// the operations that are done are not run again
+		_, err = r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Len(t, *commands, 2)
+	})
+
+	t.Run("an operation resumes from its next step", func(t *testing.T) {
+		r, commands := setup(t, &cephv1.CephOperation{
+			ObjectMeta: metav1.ObjectMeta{Name: req.Name, Namespace: namespace},
+			Spec: cephv1.CephOperationSpec{
+				Compaction: &cephv1.CompactionOperationSpec{Daemons: []string{"osd.0", "osd.1", "osd.2"}},
+			},
+			Status: &cephv1.CephOperationStatus{Phase: cephv1.CephOperationRunning, TotalSteps: 3, CompletedSteps: 1},
+		})
+
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"tell osd.1 compact"}, *commands)
+		assert.Equal(t, 2, getOperation(t, r).Status.CompletedSteps)
+	})
+
+	t.Run("a cancelled operation does not run its next step", func(t *testing.T) {
+		r, commands := setup(t, &cephv1.CephOperation{
+			ObjectMeta: metav1.ObjectMeta{Name: req.Name, Namespace: namespace},
+			Spec: cephv1.CephOperationSpec{
+				Compaction: &cephv1.CompactionOperationSpec{Daemons: []string{"osd.0", "osd.1"}},
+				Cancel:     true,
+			},
+			Status: &cephv1.CephOperationStatus{Phase: cephv1.CephOperationRunning, TotalSteps: 2, CompletedSteps: 1},
+		})
+
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Empty(t, *commands)
+		status := getOperation(t, r).Status
+		assert.Equal(t, cephv1.CephOperationCancelled, status.Phase)
+		assert.Equal(t, 1, status.CompletedSteps)
+	})
+
+	t.Run("a new operation does not poll the command of a cancelled one", func(t *testing.T) {
+		runInBackground = oldRunInBackground
+		defer func() { runInBackground = func(command func()) { command() } }()
+
+		r, _ := setup(t, &cephv1.CephOperation{
+			ObjectMeta: metav1.ObjectMeta{Name: req.Name, Namespace: namespace, UID: "uid-1"},
+			Spec: cephv1.CephOperationSpec{
+				Compaction: &cephv1.CompactionOperationSpec{Daemons: []string{"osd.0"}},
+			},
+		})
+		var mutex sync.Mutex
+		compactions := 0
+		releaseFirst, releaseSecond := make(chan struct{}), make(chan struct{})
+		r.context.Executor = &exectest.MockExecutor{
+			MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+				mutex.Lock()
+				compactions++
+				first := compactions == 1
+				mutex.Unlock()
+				if first {
+					<-releaseFirst
+					return "", errors.New("failed")
+				}
+				<-releaseSecond
+				return "", nil
+			},
+		}
+
+		started := func(count int) func() bool {
+			return func() bool {
+				mutex.Lock()
+				defer mutex.Unlock()
+				return compactions == count
+			}
+		}
+
+		res, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, waitForStepResult, res)
+		assert.Eventually(t, started(1), 5*time.Second, 10*time.Millisecond)
+
+		// the operation is cancelled while its compaction is running
+		cephOperation := getOperation(t, r)
+		cephOperation.Spec.Cancel = true
+		assert.NoError(t, r.client.Update(ctx, cephOperation))
+		_, err = r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, cephv1.CephOperationCancelled, getOperation(t, r).Status.Phase)
+		assert.Empty(t, r.tasks.tasks)
+
+		// a new operation compacts the same osd
+		assert.NoError(t, r.client.Delete(ctx, cephOperation))
+		assert.NoError(t, r.client.Create(ctx, &cephv1.CephOperation{
+			ObjectMeta: metav1.ObjectMeta{Name: req.Name, Namespace: namespace, UID: "uid-2"},
+			Spec: cephv1.CephOperationSpec{
+				Compaction: &cephv1.CompactionOperationSpec{Daemons: []string{"osd.0"}},
+			},
+		}))
+		res, err = r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, waitForStepResult, res)
+		assert.Eventually(t, started(2), 5*time.Second, 10*time.Millisecond)
+
+		// the failure of the compaction of the cancelled operation is not taken as the result of the new one
+		close(releaseFirst)
+		time.Sleep(10 * time.Millisecond)
+		res, err = r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, waitForStepResult, res)
+		assert.Equal(t, cephv1.CephOperationRunning, getOperation(t, r).Status.Phase)
+
+		close(releaseSecond)
+		assert.Eventually(t, func() bool {
+			_, err = r.Reconcile(ctx, req)
+			return getOperation(t, r).Status.Phase == cephv1.CephOperationSucceeded
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.NoError(t, err)
+		assert.True(t, started(2)())
+	})
+
+	t.Run("an invalid operation fails", func(t *testing.T) {
+		r, commands := setup(t, &cephv1.CephOperation{
+			ObjectMeta: metav1.ObjectMeta{Name: req.Name, Namespace: namespace},
+			Spec: cephv1.CephOperationSpec{
+				Compaction: &cephv1.CompactionOperationSpec{Daemons: []string{"mds.a"}},
+			},
+		})
+
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Empty(t, *commands)
+		status := getOperation(t, r).Status
+		assert.Equal(t, cephv1.CephOperationFailed, status.Phase)
+		assert.Contains(t, status.Message, "mds.a")
+	})
+}
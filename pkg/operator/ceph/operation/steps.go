@@ -0,0 +1,398 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operation
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	osddaemon "github.com/rook/rook/pkg/daemon/ceph/osd"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+	"github.com/rook/rook/pkg/util/exec"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var (
+	// compactionTimeout bounds the compaction of the database of a daemon, which can take a while for a large OSD
+	compactionTimeout = 30 * time.Minute
+	// dataMigrationTimeout bounds the copy of the data of an image to the target pool of its migration
+	dataMigrationTimeout = 24 * time.Hour
+	// orphanScanTimeout bounds the scan of the data pool of an object store for orphan objects
+	orphanScanTimeout = 24 * time.Hour
+
+	compactionDaemonRegex = regexp.MustCompile(`^(osd\.[0-9]+|mon\.[a-z0-9-]+)$`)
+
+	// runInBackground runs the long commands of the steps, overridden for unit testing
+	runInBackground = func(command func()) { go command() }
+)
+
+// the states of the migration of an rbd image reported by `rbd status`
+const (
+	migrationPrepared  = "prepared"
+	migrationExecuting = "executing"
+	migrationExecuted  = "executed"
+)
+
+// the number of orphan objects listed in the event of an orphan scan
+const maxReportedOrphans = 10
+
+// step is a step of an operation. A step interrupted by a restart of the operator runs again, so the steps must be
+// idempotent.
+type step struct {
+	description string
+	// waitMessage is reported while the step is waiting for the cluster
+	waitMessage string
+	// run returns false while the step is waiting for the cluster, in which case it runs again later
+	run func() (bool, error)
+}
+
+// backgroundTasks are the long commands of the steps running in the background. A step polls its command at each
+// reconcile instead of blocking the reconcile until the command completes. The commands are lost when the operator
+// restarts, in which case the step starts its command again. The commands are tracked per operation, so that an
+// operation never polls the command of another one.
+type backgroundTasks struct {
+	mutex sync.Mutex
+	tasks map[backgroundTaskKey]*backgroundTask
+}
+
+type backgroundTaskKey struct {
+	operation types.UID
+	command   string
+}
+
+type backgroundTask struct {
+	operation types.NamespacedName
+	done      bool
+	err       error
+}
+
+// poll starts the command of the operation unless it is already running, and returns whether the command completed
+// and its error. A completed command is forgotten, so that polling it again runs the command again.
+func (b *backgroundTasks) poll(cephOperation *cephv1.CephOperation, command string, run func() error) (bool, error) {
+	key := backgroundTaskKey{operation: cephOperation.UID, command: command}
+	b.mutex.Lock()
+	task, ok := b.tasks[key]
+	if !ok {
+		if b.tasks == nil {
+			b.tasks = map[backgroundTaskKey]*backgroundTask{}
+		}
+		task = &backgroundTask{operation: types.NamespacedName{Namespace: cephOperation.Namespace, Name: cephOperation.Name}}
+		b.tasks[key] = task
+		b.mutex.Unlock()
+		logger.Infof("running %q of CephOperation %q in the background", command, task.operation)
+		runInBackground(func() {
+			err := run()
+			b.mutex.Lock()
+			defer b.mutex.Unlock()
+			task.done, task.err = true, err
+		})
+		b.mutex.Lock()
+	}
+	defer b.mutex.Unlock()
+	if !task.done {
+		return false, nil
+	}
+	delete(b.tasks, key)
+	return true, task.err
+}
+
+// forget drops the commands of an operation that is cancelled or deleted. The ceph commands cannot be interrupted, so
+// a running command keeps running in the cluster until it completes, but its result is ignored.
+func (b *backgroundTasks) forget(operation types.NamespacedName) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for key, task := range b.tasks {
+		if task.operation == operation {
+			if !task.done {
+				logger.Infof("%q of CephOperation %q keeps running in the cluster until it completes", key.command, operation)
+			}
+			delete(b.tasks, key)
+		}
+	}
+}
+
+// operationSteps returns the steps of the operation. Adding a new kind of operation only requires a new spec and the
+// function that returns its steps.
+func (r *ReconcileCephOperation) operationSteps(cephOperation *cephv1.CephOperation) ([]step, error) {
+	spec := &cephOperation.Spec
+	operations := 0
+	steps := []step{}
+	if spec.OSDPurge != nil {
+		operations++
+		for _, osdID := range spec.OSDPurge.OSDIDs {
+			steps = append(steps, r.osdPurgeStep(spec.OSDPurge, osdID))
+		}
+	}
+	if spec.Compaction != nil {
+		operations++
+		for _, daemon := range spec.Compaction.Daemons {
+			if !compactionDaemonRegex.MatchString(daemon) {
+				return nil, errors.Errorf("cannot compact %q, only the osds and mons can be compacted, e.g. \"osd.0\" or \"mon.a\"", daemon)
+			}
+			steps = append(steps, r.compactionStep(cephOperation, daemon))
+		}
+	}
+	if spec.DataMigration != nil {
+		operations++
+		if spec.DataMigration.SourcePool == spec.DataMigration.TargetPool {
+			return nil, errors.Errorf("the images cannot be migrated to their own pool %q", spec.DataMigration.SourcePool)
+		}
+		for _, image := range spec.DataMigration.Images {
+			steps = append(steps, r.dataMigrationStep(cephOperation, spec.DataMigration, image))
+		}
+	}
+	if spec.OrphanScan != nil {
+		operations++
+		for _, store := range spec.OrphanScan.ObjectStores {
+			steps = append(steps, r.orphanScanStep(cephOperation, store))
+		}
+	}
+
+	if operations != 1 {
+		return nil, errors.New("exactly one operation must be set")
+	}
+	if len(steps) == 0 {
+		return nil, errors.New("the operation has no step")
+	}
+	return steps, nil
+}
+
+// osdPurgeStep purges a down OSD, once it is safe to destroy unless the purge is forced
+func (r *ReconcileCephOperation) osdPurgeStep(spec *cephv1.OSDPurgeOperationSpec, osdID int) step {
+	return step{
+		description: fmt.Sprintf("purge osd.%d", osdID),
+		waitMessage: fmt.Sprintf("waiting for osd.%d to be safe to destroy", osdID),
+		run: func() (bool, error) {
+			osdDump, err := cephclient.GetOSDDump(r.context, r.clusterInfo)
+			if err != nil {
+				return false, errors.Wrap(err, "failed to get osd dump")
+			}
+			if !osdExists(osdDump, osdID) {
+				logger.Infof("osd.%d does not exist, it is already purged", osdID)
+				return true, nil
+			}
+			up, in, err := osdDump.StatusByID(int64(osdID))
+			if err != nil {
+				return false, errors.Wrapf(err, "failed to get the status of osd.%d", osdID)
+			}
+			if up == 1 {
+				return false, errors.Errorf("osd.%d is up, it must be down to be purged", osdID)
+			}
+
+			if in == 1 {
+				logger.Infof("marking osd.%d out", osdID)
+				_, err = cephclient.NewCephCommand(r.context, r.clusterInfo, []string{"osd", "out", fmt.Sprintf("osd.%d", osdID)}).Run()
+				if err != nil {
+					return false, errors.Wrapf(err, "failed to mark osd.%d out", osdID)
+				}
+			}
+
+			safe, err := cephclient.OsdSafeToDestroy(r.context, r.clusterInfo, osdID)
+			if !spec.Force {
+				if err != nil {
+					logger.Warningf("failed to check if osd.%d is safe to destroy. %v", osdID, err)
+					return false, nil
+				}
+				if !safe {
+					logger.Infof("osd.%d is not safe to destroy yet", osdID)
+					return false, nil
+				}
+			} else if err != nil || !safe {
+				logger.Warningf("osd.%d is not safe to destroy but the purge is forced", osdID)
+			}
+
+			hostName, err := cephclient.GetCrushHostName(r.context, r.clusterInfo, osdID)
+			if err != nil {
+				logger.Warningf("failed to get the crush host of osd.%d. %v", osdID, err)
+			}
+			osddaemon.RemoveOSDDeployment(r.context, r.clusterInfo, osdID, spec.PreservePVC)
+			if err := osddaemon.PurgeOSD(r.context, r.clusterInfo, osdID, hostName); err != nil {
+				return false, err
+			}
+			return true, nil
+		},
+	}
+}
+
+// compactionStep compacts the database of an OSD or a mon. The compaction runs in the background and the step polls
+// it, since it can take a while for a large OSD.
+func (r *ReconcileCephOperation) compactionStep(cephOperation *cephv1.CephOperation, daemon string) step {
+	return step{
+		description: fmt.Sprintf("compact %s", daemon),
+		waitMessage: fmt.Sprintf("waiting for the compaction of %s", daemon),
+		run: func() (bool, error) {
+			context, clusterInfo := r.context, r.clusterInfo
+			return r.tasks.poll(cephOperation, fmt.Sprintf("compact %s", daemon), func() error {
+				_, err := cephclient.NewCephCommand(context, clusterInfo, []string{"tell", daemon, "compact"}).RunWithTimeout(compactionTimeout)
+				return errors.Wrapf(err, "failed to compact %s", daemon)
+			})
+		},
+	}
+}
+
+// dataMigrationStep live migrates an rbd image to the target pool. The migration is prepared, its data is copied in
+// the background, and it is committed once the data is copied. The state of the migration is read from the image at
+// each run, so the step resumes the migration after a restart of the operator.
+func (r *ReconcileCephOperation) dataMigrationStep(cephOperation *cephv1.CephOperation, spec *cephv1.DataMigrationOperationSpec, image string) step {
+	source := fmt.Sprintf("%s/%s", spec.SourcePool, image)
+	target := fmt.Sprintf("%s/%s", spec.TargetPool, image)
+	return step{
+		description: fmt.Sprintf("migrate image %s to pool %s", source, spec.TargetPool),
+		waitMessage: fmt.Sprintf("waiting for the data of image %s to be copied to pool %s", source, spec.TargetPool),
+		run: func() (bool, error) {
+			context, clusterInfo := r.context, r.clusterInfo
+			state, exists, err := imageMigrationState(r, target)
+			if err != nil {
+				return false, err
+			}
+			if !exists {
+				logger.Infof("preparing the migration of image %q to pool %q", source, spec.TargetPool)
+				if _, err := cephclient.NewRBDCommand(context, clusterInfo, []string{"migration", "prepare", source, target}).Run(); err != nil {
+					return false, errors.Wrapf(err, "failed to prepare the migration of image %q", source)
+				}
+				state = migrationPrepared
+			}
+
+			switch state {
+			case "":
+				logger.Infof("image %q is already migrated to pool %q", source, spec.TargetPool)
+				return true, nil
+			case migrationPrepared, migrationExecuting:
+				done, err := r.tasks.poll(cephOperation, fmt.Sprintf("migrate %s", target), func() error {
+					_, err := cephclient.NewRBDCommand(context, clusterInfo, []string{"migration", "execute", target}).RunWithTimeout(dataMigrationTimeout)
+					return errors.Wrapf(err, "failed to copy the data of image %q to pool %q", source, spec.TargetPool)
+				})
+				if err != nil || !done {
+					return false, err
+				}
+			case migrationExecuted:
+			default:
+				return false, errors.Errorf("unexpected migration state %q of image %q", state, target)
+			}
+
+			logger.Infof("committing the migration of image %q to pool %q", source, spec.TargetPool)
+			if _, err := cephclient.NewRBDCommand(context, clusterInfo, []string{"migration", "commit", target}).Run(); err != nil {
+				return false, errors.Wrapf(err, "failed to commit the migration of image %q", source)
+			}
+			return true, nil
+		},
+	}
+}
+
+type imageStatus struct {
+	Migration *struct {
+		State string `json:"state"`
+	} `json:"migration"`
+}
+
+// imageMigrationState returns the state of the migration of the image, which is empty if the image is not migrating,
+// and whether the image exists
+func imageMigrationState(r *ReconcileCephOperation, image string) (string, bool, error) {
+	cmd := cephclient.NewRBDCommand(r.context, r.clusterInfo, []string{"status", image})
+	cmd.JsonOutput = true
+	output, err := cmd.Run()
+	if err != nil {
+		if code, ok := exec.ExitStatus(err); ok && code == int(syscall.ENOENT) {
+			return "", false, nil
+		}
+		return "", false, errors.Wrapf(err, "failed to get the status of image %q", image)
+	}
+	var status imageStatus
+	if err := json.Unmarshal(output, &status); err != nil {
+		return "", false, errors.Wrapf(err, "failed to parse the status of image %q", image)
+	}
+	if status.Migration == nil {
+		return "", true, nil
+	}
+	return status.Migration.State, true, nil
+}
+
+// orphanScanStep scans the data pool of an object store for the RADOS objects that no bucket references, and reports
+// them in the events of the operation. The scan runs in the background since it reads all the objects of the pool.
+func (r *ReconcileCephOperation) orphanScanStep(cephOperation *cephv1.CephOperation, storeName string) step {
+	return step{
+		description: fmt.Sprintf("scan object store %s for orphans", storeName),
+		waitMessage: fmt.Sprintf("waiting for the scan of object store %s", storeName),
+		run: func() (bool, error) {
+			clusterInfo := r.clusterInfo
+			store := &cephv1.CephObjectStore{}
+			err := r.client.Get(r.opManagerContext, types.NamespacedName{Name: storeName, Namespace: clusterInfo.Namespace}, store)
+			if err != nil {
+				return false, errors.Wrapf(err, "failed to get object store %q", storeName)
+			}
+			if store.Spec.SharedPools.DataPoolName != "" {
+				return false, errors.Errorf("object store %q uses shared pools, which cannot be scanned for orphans", storeName)
+			}
+			objContext, err := object.NewMultisiteContext(r.context, clusterInfo, store)
+			if err != nil {
+				return false, errors.Wrapf(err, "failed to get the zone of object store %q", storeName)
+			}
+
+			pool := object.DataPoolName(storeName)
+			jobID := fmt.Sprintf("%s-%s", cephOperation.Name, storeName)
+			return r.tasks.poll(cephOperation, fmt.Sprintf("scan %s", pool), func() error {
+				output, err := object.RunAdminCommandWithTimeout(objContext, orphanScanTimeout, "orphans", "find", fmt.Sprintf("--pool=%s", pool), fmt.Sprintf("--job-id=%s", jobID), "--yes-i-really-mean-it")
+				if err != nil {
+					return errors.Wrapf(err, "failed to scan pool %q for orphans. %s", pool, output)
+				}
+				// the state of the scan is kept in the pool until the job is finished
+				if _, err := object.RunAdminCommandWithTimeout(objContext, exec.CephCommandsTimeout, "orphans", "finish", fmt.Sprintf("--job-id=%s", jobID), "--yes-i-really-mean-it"); err != nil {
+					logger.Warningf("failed to finish the orphan scan %q of pool %q. %v", jobID, pool, err)
+				}
+
+				orphans := parseOrphans(output)
+				count := len(orphans)
+				logger.Infof("found %d orphan objects in pool %q: %v", count, pool, orphans)
+				if count > maxReportedOrphans {
+					orphans = append(orphans[:maxReportedOrphans], "...")
+				}
+				r.recorder.Eventf(cephOperation, "Normal", "OrphansFound", "found %d orphan objects in pool %q %v", count, pool, orphans)
+				return nil
+			})
+		},
+	}
+}
+
+// parseOrphans returns the orphan objects listed by `radosgw-admin orphans find`
+func parseOrphans(output string) []string {
+	orphans := []string{}
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "leaked: ") {
+			orphans = append(orphans, strings.TrimPrefix(line, "leaked: "))
+		}
+	}
+	return orphans
+}
+
+func osdExists(osdDump *cephclient.OSDDump, osdID int) bool {
+	for _, osd := range osdDump.OSDs {
+		id, err := osd.OSD.Int64()
+		if err == nil && id == int64(osdID) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,321 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operation
+
+import (
+	"context"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	testop "github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestOperationSteps(t *testing.T) {
+	r := &ReconcileCephOperation{}
+
+	steps, err := r.operationSteps(&cephv1.CephOperation{Spec: cephv1.CephOperationSpec{
+		OSDPurge: &cephv1.OSDPurgeOperationSpec{OSDIDs: []int{1, 2}},
+	}})
+	assert.NoError(t, err)
+	assert.Len(t, steps, 2)
+	assert.Equal(t, "purge osd.2", steps[1].description)
+
+	steps, err = r.operationSteps(&cephv1.CephOperation{Spec: cephv1.CephOperationSpec{
+		Compaction: &cephv1.CompactionOperationSpec{Daemons: []string{"osd.10", "mon.b"}},
+	}})
+	assert.NoError(t, err)
+	assert.Len(t, steps, 2)
+	assert.Equal(t, "compact mon.b", steps[1].description)
+
+	_, err = r.operationSteps(&cephv1.CephOperation{})
+	assert.Error(t, err)
+
+	_, err = r.operationSteps(&cephv1.CephOperation{Spec: cephv1.CephOperationSpec{
+		OSDPurge:   &cephv1.OSDPurgeOperationSpec{OSDIDs: []int{1}},
+		Compaction: &cephv1.CompactionOperationSpec{Daemons: []string{"osd.1"}},
+	}})
+	assert.Error(t, err)
+
+	_, err = r.operationSteps(&cephv1.CephOperation{Spec: cephv1.CephOperationSpec{OSDPurge: &cephv1.OSDPurgeOperationSpec{}}})
+	assert.Error(t, err)
+
+	_, err = r.operationSteps(&cephv1.CephOperation{Spec: cephv1.CephOperationSpec{
+		Compaction: &cephv1.CompactionOperationSpec{Daemons: []string{"osd.a"}},
+	}})
+	assert.Error(t, err)
+
+	steps, err = r.operationSteps(&cephv1.CephOperation{Spec: cephv1.CephOperationSpec{
+		DataMigration: &cephv1.DataMigrationOperationSpec{SourcePool: "replicapool", TargetPool: "ecpool", Images: []string{"img1", "img2"}},
+	}})
+	assert.NoError(t, err)
+	assert.Len(t, steps, 2)
+	assert.Equal(t, "migrate image replicapool/img2 to pool ecpool", steps[1].description)
+
+	_, err = r.operationSteps(&cephv1.CephOperation{Spec: cephv1.CephOperationSpec{
+		DataMigration: &cephv1.DataMigrationOperationSpec{SourcePool: "replicapool", TargetPool: "replicapool", Images: []string{"img1"}},
+	}})
+	assert.Error(t, err)
+
+	steps, err = r.operationSteps(&cephv1.CephOperation{Spec: cephv1.CephOperationSpec{
+		OrphanScan: &cephv1.OrphanScanOperationSpec{ObjectStores: []string{"my-store"}},
+	}})
+	assert.NoError(t, err)
+	assert.Len(t, steps, 1)
+	assert.Equal(t, "scan object store my-store for orphans", steps[0].description)
+}
+
+func TestBackgroundTasks(t *testing.T) {
+	tasks := &backgroundTasks{}
+	operation := &cephv1.CephOperation{ObjectMeta: metav1.ObjectMeta{Name: "compact", Namespace: "rook-ceph", UID: "uid-1"}}
+	release := make(chan struct{})
+	runs := 0
+	command := func() error {
+		runs++
+		<-release
+		return errors.New("failed")
+	}
+
+	done, err := tasks.poll(operation, "task", command)
+	assert.NoError(t, err)
+	assert.False(t, done)
+
+	// the running command is not started again
+	done, err = tasks.poll(operation, "task", command)
+	assert.NoError(t, err)
+	assert.False(t, done)
+
+	close(release)
+	assert.Eventually(t, func() bool {
+		done, err = tasks.poll(operation, "task", command)
+		return done
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Error(t, err)
+	assert.Equal(t, 1, runs)
+	assert.Empty(t, tasks.tasks)
+
+	// the commands of an operation are not polled by another one, and are forgotten with their operation
+	blocked := make(chan struct{})
+	defer close(blocked)
+	wait := func() error {
+		<-blocked
+		return nil
+	}
+	other := operation.DeepCopy()
+	other.UID = "uid-2"
+	_, err = tasks.poll(operation, "task", wait)
+	assert.NoError(t, err)
+	_, err = tasks.poll(other, "task", wait)
+	assert.NoError(t, err)
+	assert.Len(t, tasks.tasks, 2)
+	tasks.forget(types.NamespacedName{Name: "compact", Namespace: "rook-ceph"})
+	assert.Empty(t, tasks.tasks)
+}
+
+func TestCompactionStep(t *testing.T) {
+	release := make(chan struct{})
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			assert.Equal(t, []string{"tell", "osd.0", "compact"}, args[:3])
+			<-release
+			return "", nil
+		},
+	}
+	clusterInfo := cephclient.AdminTestClusterInfo("rook-ceph")
+	clusterInfo.Context = context.TODO()
+	r := &ReconcileCephOperation{context: &clusterd.Context{Executor: executor}, clusterInfo: clusterInfo}
+	operation := &cephv1.CephOperation{ObjectMeta: metav1.ObjectMeta{Name: "operation", Namespace: "rook-ceph", UID: "uid-1"}}
+
+	// the step waits for the compaction instead of blocking
+	done, err := r.compactionStep(operation, "osd.0").run()
+	assert.NoError(t, err)
+	assert.False(t, done)
+
+	close(release)
+	assert.Eventually(t, func() bool {
+		done, err = r.compactionStep(operation, "osd.0").run()
+		return done
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.NoError(t, err)
+}
+
+func TestDataMigrationStep(t *testing.T) {
+	oldRunInBackground := runInBackground
+	runInBackground = func(command func()) { command() }
+	defer func() { runInBackground = oldRunInBackground }()
+
+	status := ""
+	commands := []string{}
+	execute := func(command string, args ...string) (string, error) {
+		commands = append(commands, strings.Join(args[:2], " "))
+		switch {
+		case args[0] == "status":
+			assert.Equal(t, "ecpool/img1", args[1])
+			if status == "" {
+				return "", exectest.MockExecCommandReturns(t, "", "", int(syscall.ENOENT))
+			}
+			return status, nil
+		case args[0] == "migration" && args[1] == "prepare":
+			assert.Equal(t, []string{"replicapool/img1", "ecpool/img1"}, args[2:4])
+		}
+		return "", nil
+	}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: execute,
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			return execute(command, args...)
+		},
+	}
+	clusterInfo := cephclient.AdminTestClusterInfo("rook-ceph")
+	clusterInfo.Context = context.TODO()
+	r := &ReconcileCephOperation{context: &clusterd.Context{Executor: executor}, clusterInfo: clusterInfo}
+	operation := &cephv1.CephOperation{ObjectMeta: metav1.ObjectMeta{Name: "operation", Namespace: "rook-ceph", UID: "uid-1"}}
+	spec := &cephv1.DataMigrationOperationSpec{SourcePool: "replicapool", TargetPool: "ecpool", Images: []string{"img1"}}
+
+	t.Run("a new migration is prepared, executed and committed", func(t *testing.T) {
+		done, err := r.dataMigrationStep(operation, spec, "img1").run()
+		assert.NoError(t, err)
+		assert.True(t, done)
+		assert.Equal(t, []string{"status ecpool/img1", "migration prepare", "migration execute", "migration commit"}, commands)
+	})
+
+	t.Run("an executed migration is committed", func(t *testing.T) {
+		commands = []string{}
+		status = `{"watchers":[],"migration":{"source_pool_name":"replicapool","state":"executed"}}`
+		done, err := r.dataMigrationStep(operation, spec, "img1").run()
+		assert.NoError(t, err)
+		assert.True(t, done)
+		assert.Equal(t, []string{"status ecpool/img1", "migration commit"}, commands)
+	})
+
+	t.Run("a committed migration is done", func(t *testing.T) {
+		commands = []string{}
+		status = `{"watchers":[]}`
+		done, err := r.dataMigrationStep(operation, spec, "img1").run()
+		assert.NoError(t, err)
+		assert.True(t, done)
+		assert.Equal(t, []string{"status ecpool/img1"}, commands)
+	})
+
+	t.Run("an aborting migration fails", func(t *testing.T) {
+		status = `{"watchers":[],"migration":{"state":"aborting"}}`
+		done, err := r.dataMigrationStep(operation, spec, "img1").run()
+		assert.Error(t, err)
+		assert.False(t, done)
+	})
+}
+
+func TestParseOrphans(t *testing.T) {
+	output := "2023-05-01 log line\nleaked: 1234.5678__shadow_obj1\n  leaked: 1234.5678__multipart_obj2\n"
+	assert.Equal(t, []string{"1234.5678__shadow_obj1", "1234.5678__multipart_obj2"}, parseOrphans(output))
+	assert.Empty(t, parseOrphans(""))
+}
+
+func TestOSDPurgeStep(t *testing.T) {
+	osdDump := `{"osds":[{"osd":1,"up":0,"in":1},{"osd":2,"up":1,"in":1}]}`
+	safeToDestroy := `{"safe_to_destroy":[],"active":[],"missing_stats":[],"stored_pgs":[1]}`
+	commands := []string{}
+	purgeFails := false
+	execute := func(command string, args ...string) (string, error) {
+		commands = append(commands, strings.Join(args[:2], " "))
+		switch {
+		case args[0] == "osd" && args[1] == "purge" && purgeFails:
+			return "", errors.New("purge failed")
+		case args[0] == "osd" && args[1] == "dump":
+			return osdDump, nil
+		case args[0] == "osd" && args[1] == "safe-to-destroy":
+			return safeToDestroy, nil
+		case args[0] == "osd" && args[1] == "find":
+			return `{"osd":1,"location":{"host":"node-a"}}`, nil
+		}
+		return "", nil
+	}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: execute,
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			return execute(command, args...)
+		},
+	}
+	c := &clusterd.Context{Executor: executor, Clientset: testop.New(t, 1)}
+	clusterInfo := cephclient.AdminTestClusterInfo("rook-ceph")
+	clusterInfo.Context = context.TODO()
+	r := &ReconcileCephOperation{context: c, clusterInfo: clusterInfo}
+	spec := &cephv1.OSDPurgeOperationSpec{OSDIDs: []int{1}}
+
+	t.Run("an up osd is not purged", func(t *testing.T) {
+		done, err := r.osdPurgeStep(spec, 2).run()
+		assert.Error(t, err)
+		assert.False(t, done)
+	})
+
+	t.Run("an osd that is not safe to destroy waits", func(t *testing.T) {
+		commands = []string{}
+		done, err := r.osdPurgeStep(spec, 1).run()
+		assert.NoError(t, err)
+		assert.False(t, done)
+		assert.Contains(t, commands, "osd out")
+		assert.NotContains(t, commands, "osd purge")
+	})
+
+	t.Run("a forced purge does not wait", func(t *testing.T) {
+		commands = []string{}
+		done, err := r.osdPurgeStep(&cephv1.OSDPurgeOperationSpec{OSDIDs: []int{1}, Force: true}, 1).run()
+		assert.NoError(t, err)
+		assert.True(t, done)
+		assert.Contains(t, commands, "osd purge")
+	})
+
+	t.Run("a safe osd is purged", func(t *testing.T) {
+		commands = []string{}
+		safeToDestroy = `{"safe_to_destroy":[1],"active":[],"missing_stats":[],"stored_pgs":[]}`
+		done, err := r.osdPurgeStep(spec, 1).run()
+		assert.NoError(t, err)
+		assert.True(t, done)
+		assert.Contains(t, commands, "osd purge")
+	})
+
+	t.Run("a failed purge fails the step", func(t *testing.T) {
+		purgeFails = true
+		defer func() { purgeFails = false }()
+		done, err := r.osdPurgeStep(spec, 1).run()
+		assert.Error(t, err)
+		assert.False(t, done)
+	})
+
+	t.Run("an osd that no longer exists is already purged", func(t *testing.T) {
+		commands = []string{}
+		osdDump = `{"osds":[{"osd":2,"up":1,"in":1}]}`
+		done, err := r.osdPurgeStep(spec, 1).run()
+		assert.NoError(t, err)
+		assert.True(t, done)
+		assert.Equal(t, []string{"osd dump"}, commands)
+	})
+}
+
+// TestMockExecHelperProcess is used by MockExecCommandReturns to simulate the exit code of the commands
+func TestMockExecHelperProcess(t *testing.T) {
+	exectest.TestMockExecHelperProcess(t)
+}
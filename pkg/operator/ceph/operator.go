@@ -133,6 +133,9 @@ func (o *Operator) runCRDManager() {
 	// content changes for ROOK_CURRENT_NAMESPACE_ONLY we must reload the operator CRD manager
 	o.namespaceToWatch(opManagerContext)
 
+	// Report the permissions that are missing for the watched namespace, which restricted environments may not grant
+	o.config.CanWatchSecrets = o.checkPermissions(opManagerContext)
+
 	// Pass the parent context to the cluster controller so that the monitoring go routines can
 	// consume it to terminate gracefully
 	o.clusterController.OpManagerCtx = opManagerContext
@@ -0,0 +1,75 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"context"
+
+	"github.com/rook/rook/pkg/operator/k8sutil"
+)
+
+const secretWatchFeature = "reconciling CephClients and CephObjectStoreUsers when their secrets are changed"
+
+// requiredPermissions returns the permissions of the operator with the features they are needed for. The child
+// resources of the ceph custom resources are checked in the watched namespace, which is all namespaces unless
+// ROOK_CURRENT_NAMESPACE_ONLY is set.
+func requiredPermissions(namespace string) []k8sutil.Permission {
+	permissions := []k8sutil.Permission{}
+	add := func(feature, group, resource, namespace string, verbs ...string) {
+		for _, verb := range verbs {
+			permissions = append(permissions, k8sutil.Permission{Group: group, Resource: resource, Verb: verb, Namespace: namespace, Feature: feature})
+		}
+	}
+
+	add("ceph keyrings and credentials", "", "secrets", namespace, "get", "create", "update", "delete")
+	add(secretWatchFeature, "", "secrets", namespace, "list", "watch")
+	add("ceph config and mon endpoints", "", "configmaps", namespace, "get", "list", "watch", "create", "update", "delete")
+	add("services of the mons, mgr, object stores and nfs servers", "", "services", namespace, "get", "create", "update", "delete")
+	add("ceph daemons", "apps", "deployments", namespace, "get", "list", "watch", "create", "update", "delete")
+	add("csi drivers", "apps", "daemonsets", namespace, "get", "create", "update", "delete")
+	add("osd provisioning", "batch", "jobs", namespace, "get", "create", "delete")
+	add("mons and osds on pvcs", "", "persistentvolumeclaims", namespace, "get", "list", "create", "delete")
+	add("events on the ceph custom resources", "", "events", namespace, "create", "patch")
+	add("managed pod disruption budgets", "policy", "poddisruptionbudgets", namespace, "get", "create", "update", "delete")
+	add("service monitors of the ceph metrics when monitoring is enabled", "monitoring.coreos.com", "servicemonitors", namespace, "get", "create", "update", "delete")
+	// nodes and storage classes are not namespaced
+	add("placement of the mons and osds", "", "nodes", "", "get", "list")
+	add("object bucket claims", "storage.k8s.io", "storageclasses", "", "get")
+	return permissions
+}
+
+// checkPermissions reports the permissions missing from the service account of the operator with the features they
+// break. It returns whether the secrets of the watched namespace can be watched.
+func (o *Operator) checkPermissions(ctx context.Context) bool {
+	missing, err := k8sutil.MissingPermissions(ctx, o.context.Clientset, requiredPermissions(o.config.NamespaceToWatch))
+	if err != nil {
+		logger.Warningf("failed to check the permissions of the operator. %v", err)
+		return true
+	}
+
+	canWatchSecrets := true
+	for _, p := range missing {
+		logger.Warningf("missing permission to %s. %s will not work", p.String(), p.Feature)
+		if p.Feature == secretWatchFeature {
+			canWatchSecrets = false
+		}
+	}
+	if len(missing) == 0 {
+		logger.Info("the operator has all the permissions it needs")
+	}
+	return canWatchSecrets
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rook/rook/pkg/clusterd"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/stretchr/testify/assert"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestCheckPermissions(t *testing.T) {
+	denied := map[string]bool{}
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		attributes := review.Spec.ResourceAttributes
+		review.Status.Allowed = !denied[attributes.Verb+" "+attributes.Resource]
+		return true, review, nil
+	})
+	o := &Operator{
+		context: &clusterd.Context{Clientset: clientset},
+		config:  &opcontroller.OperatorConfig{NamespaceToWatch: "rook-ceph"},
+	}
+
+	t.Run("all permissions", func(t *testing.T) {
+		assert.True(t, o.checkPermissions(context.TODO()))
+	})
+
+	t.Run("secrets cannot be watched", func(t *testing.T) {
+		denied["watch secrets"] = true
+		assert.False(t, o.checkPermissions(context.TODO()))
+	})
+
+	t.Run("missing permission of another feature", func(t *testing.T) {
+		denied = map[string]bool{"create servicemonitors": true}
+		assert.True(t, o.checkPermissions(context.TODO()))
+	})
+}
+
+func TestRequiredPermissions(t *testing.T) {
+	for _, p := range requiredPermissions("rook-ceph") {
+		assert.NotEmpty(t, p.Feature)
+		if p.Resource == "nodes" || p.Resource == "storageclasses" {
+			assert.Empty(t, p.Namespace)
+		} else {
+			assert.Equal(t, "rook-ceph", p.Namespace)
+		}
+	}
+}
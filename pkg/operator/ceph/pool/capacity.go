@@ -0,0 +1,148 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/util/display"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	targetSizeBytesProperty = "target_size_bytes"
+	// the default mon_osd_nearfull_ratio of ceph
+	defaultNearFullRatio = 0.85
+)
+
+var poolCapacityInsufficient = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "rook_ceph_pool_capacity_insufficient",
+	Help: "Set to 1 while a new pool is not created because its device class lacks the free capacity for the target size of the pool",
+}, []string{"namespace", "pool", "device_class"})
+
+func init() {
+	// the metrics are served by the metrics endpoint of the controller-runtime manager of the operator
+	metrics.Registry.MustRegister(poolCapacityInsufficient)
+}
+
+// ValidateNewPoolCapacity returns an error if the pool does not exist yet and its target size would take the
+// device class of the pool over the nearfull ratio, instead of creating a pool that immediately fills the OSDs of
+// the class. The pools without the target_size_bytes parameter and the existing pools are not checked.
+func ValidateNewPoolCapacity(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, p cephv1.NamedPoolSpec) error {
+	targetSize, err := poolTargetSizeBytes(p.PoolSpec)
+	if err != nil || targetSize == 0 {
+		return err
+	}
+	pools, err := cephclient.ListPoolSummaries(context, clusterInfo)
+	if err != nil {
+		return errors.Wrap(err, "failed to list pools")
+	}
+	for _, pool := range pools {
+		if pool.Name == p.Name {
+			return nil
+		}
+	}
+
+	stats, err := cephclient.GetPoolStats(context, clusterInfo)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the capacity of the cluster")
+	}
+	usage := stats.Stats
+	deviceClass := p.DeviceClass
+	if deviceClass != "" {
+		classUsage, ok := stats.StatsByClass[deviceClass]
+		if !ok {
+			return errors.Errorf("no capacity found for device class %q of pool %q", deviceClass, p.Name)
+		}
+		usage = classUsage
+	}
+	nearFullRatio := defaultNearFullRatio
+	osdDump, err := cephclient.GetOSDDump(context, clusterInfo)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the nearfull ratio of the osds")
+	}
+	if osdDump.NearFullRatio > 0 {
+		nearFullRatio = osdDump.NearFullRatio
+	}
+
+	labels := prometheus.Labels{"namespace": clusterInfo.Namespace, "pool": p.Name, "device_class": deviceClass}
+	if err := checkPoolCapacity(p, targetSize, usage, nearFullRatio); err != nil {
+		poolCapacityInsufficient.With(labels).Set(1)
+		return err
+	}
+	poolCapacityInsufficient.Delete(labels)
+	return nil
+}
+
+// DeletePoolCapacityMetrics deletes the capacity metrics of the pools, so that the pools of a deleted resource
+// are not reported forever
+func DeletePoolCapacityMetrics(namespace string, poolNames ...string) {
+	for _, poolName := range poolNames {
+		poolCapacityInsufficient.DeletePartialMatch(prometheus.Labels{"namespace": namespace, "pool": poolName})
+	}
+}
+
+// checkPoolCapacity returns an error if the raw capacity needed by the target size of the pool exceeds the capacity
+// of the device class left before the nearfull ratio
+func checkPoolCapacity(p cephv1.NamedPoolSpec, targetSize int64, usage cephclient.CephStorageUsage, nearFullRatio float64) error {
+	required := float64(targetSize) * rawCapacityFactor(p.PoolSpec)
+	available := nearFullRatio*float64(usage.TotalBytes) - float64(usage.TotalUsedRawBytes)
+	if required <= available {
+		return nil
+	}
+
+	location := "the cluster"
+	if p.DeviceClass != "" {
+		location = fmt.Sprintf("device class %q", p.DeviceClass)
+	}
+	if available < 0 {
+		available = 0
+	}
+	return errors.Errorf("pool %q needs %s of raw capacity for its target size of %s, but %s only has %s left before reaching the nearfull ratio %v. "+
+		"add OSDs to the device class or lower the target size of the pool",
+		p.Name, display.BytesToString(uint64(required)), display.BytesToString(uint64(targetSize)), location, display.BytesToString(uint64(available)), nearFullRatio)
+}
+
+// poolTargetSizeBytes returns the target size of the pool from the target_size_bytes parameter, or zero if not set
+func poolTargetSizeBytes(p cephv1.PoolSpec) (int64, error) {
+	value, ok := p.Parameters[targetSizeBytesProperty]
+	if !ok || value == "" {
+		return 0, nil
+	}
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid %s %q", targetSizeBytesProperty, value)
+	}
+	return quantity.Value(), nil
+}
+
+// rawCapacityFactor returns the raw capacity used by the pool for each byte of data
+func rawCapacityFactor(p cephv1.PoolSpec) float64 {
+	if p.IsErasureCoded() && p.ErasureCoded.DataChunks > 0 {
+		return float64(p.ErasureCoded.DataChunks+p.ErasureCoded.CodingChunks) / float64(p.ErasureCoded.DataChunks)
+	}
+	if p.Replicated.Size > 0 {
+		return float64(p.Replicated.Size)
+	}
+	return 1
+}
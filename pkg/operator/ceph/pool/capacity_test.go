@@ -0,0 +1,111 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// 100GiB of ssd with 40GiB used, and 1TiB of hdd with nothing used
+const testCephDF = `{"stats":{"total_bytes":1209462790553,"total_avail_bytes":1166513117593,"total_used_raw_bytes":42949672960},
+	"stats_by_class":{"ssd":{"total_bytes":107374182400,"total_avail_bytes":64424509440,"total_used_raw_bytes":42949672960},
+	"hdd":{"total_bytes":1099511627776,"total_avail_bytes":1099511627776,"total_used_raw_bytes":0}},"pools":[]}`
+
+func TestValidateNewPoolCapacity(t *testing.T) {
+	clusterInfo := cephclient.AdminTestClusterInfo("rook-ceph")
+	context := &clusterd.Context{Executor: &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			switch {
+			case args[0] == "osd" && args[1] == "lspools":
+				return `[{"poolnum":1,"poolname":"existing"}]`, nil
+			case args[0] == "df":
+				return testCephDF, nil
+			case args[0] == "osd" && args[1] == "dump":
+				return `{"nearfull_ratio":0.85}`, nil
+			}
+			return "", errors.Errorf("unexpected ceph command %q", args)
+		},
+	}}
+	newPool := func(name, deviceClass, targetSize string) cephv1.NamedPoolSpec {
+		return cephv1.NamedPoolSpec{Name: name, PoolSpec: cephv1.PoolSpec{
+			DeviceClass: deviceClass,
+			Replicated:  cephv1.ReplicatedSpec{Size: 3},
+			Parameters:  map[string]string{"target_size_bytes": targetSize},
+		}}
+	}
+
+	t.Run("no target size", func(t *testing.T) {
+		assert.NoError(t, ValidateNewPoolCapacity(&clusterd.Context{}, clusterInfo, cephv1.NamedPoolSpec{Name: "fast"}))
+	})
+
+	t.Run("existing pool", func(t *testing.T) {
+		assert.NoError(t, ValidateNewPoolCapacity(context, clusterInfo, newPool("existing", "ssd", "100Gi")))
+	})
+
+	t.Run("enough capacity", func(t *testing.T) {
+		// 3 x 10GiB of the 45GiB left on the ssds before the nearfull ratio
+		assert.NoError(t, ValidateNewPoolCapacity(context, clusterInfo, newPool("fast", "ssd", "10Gi")))
+		assert.NoError(t, ValidateNewPoolCapacity(context, clusterInfo, newPool("bulk", "hdd", "200Gi")))
+		assert.NoError(t, ValidateNewPoolCapacity(context, clusterInfo, newPool("any", "", "200Gi")))
+	})
+
+	t.Run("insufficient capacity", func(t *testing.T) {
+		err := ValidateNewPoolCapacity(context, clusterInfo, newPool("fast", "ssd", "20Gi"))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), `pool "fast" needs 60.00 GiB of raw capacity for its target size of 20.00 GiB, but device class "ssd" only has 45.00 GiB left`)
+		assert.Equal(t, float64(1), testutil.ToFloat64(poolCapacityInsufficient.WithLabelValues("rook-ceph", "fast", "ssd")))
+
+		// the metric is cleared when the pool fits
+		assert.NoError(t, ValidateNewPoolCapacity(context, clusterInfo, newPool("fast", "ssd", "10Gi")))
+		assert.Equal(t, 0, testutil.CollectAndCount(poolCapacityInsufficient))
+	})
+
+	t.Run("unknown device class", func(t *testing.T) {
+		assert.Error(t, ValidateNewPoolCapacity(context, clusterInfo, newPool("fast", "nvme", "1Gi")))
+	})
+
+	t.Run("invalid target size", func(t *testing.T) {
+		assert.Error(t, ValidateNewPoolCapacity(context, clusterInfo, newPool("fast", "ssd", "lots")))
+	})
+
+	t.Run("the metrics are deleted with the pools", func(t *testing.T) {
+		assert.Error(t, ValidateNewPoolCapacity(context, clusterInfo, newPool("fast", "ssd", "20Gi")))
+		assert.Error(t, ValidateNewPoolCapacity(context, clusterInfo, newPool("faster", "ssd", "20Gi")))
+		assert.Equal(t, 2, testutil.CollectAndCount(poolCapacityInsufficient))
+
+		DeletePoolCapacityMetrics("other-namespace", "fast")
+		assert.Equal(t, 2, testutil.CollectAndCount(poolCapacityInsufficient))
+		DeletePoolCapacityMetrics("rook-ceph", "fast", "unknown")
+		assert.Equal(t, 1, testutil.CollectAndCount(poolCapacityInsufficient))
+		DeletePoolCapacityMetrics("rook-ceph", "faster")
+		assert.Equal(t, 0, testutil.CollectAndCount(poolCapacityInsufficient))
+	})
+}
+
+func TestRawCapacityFactor(t *testing.T) {
+	assert.Equal(t, float64(3), rawCapacityFactor(cephv1.PoolSpec{Replicated: cephv1.ReplicatedSpec{Size: 3}}))
+	assert.Equal(t, 1.5, rawCapacityFactor(cephv1.PoolSpec{ErasureCoded: cephv1.ErasureCodedSpec{DataChunks: 4, CodingChunks: 2}}))
+	assert.Equal(t, float64(1), rawCapacityFactor(cephv1.PoolSpec{}))
+}
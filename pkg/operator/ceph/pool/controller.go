@@ -189,6 +189,7 @@ func (r *ReconcileCephBlockPool) reconcile(request reconcile.Request) (reconcile
 		if !cephBlockPool.GetDeletionTimestamp().IsZero() && !cephClusterExists {
 			// don't leak the health checker routine if we are force-deleting
 			r.cancelMirrorMonitoring(cephBlockPool)
+			DeletePoolCapacityMetrics(cephBlockPool.Namespace, cephBlockPool.ToNamedPoolSpec().Name)
 
 			// Remove finalizer
 			err = opcontroller.RemoveFinalizer(r.opManagerContext, r.client, cephBlockPool)
@@ -244,6 +245,7 @@ func (r *ReconcileCephBlockPool) reconcile(request reconcile.Request) (reconcile
 		if err != nil {
 			return opcontroller.ImmediateRetryResult, *cephBlockPool, errors.Wrapf(err, "failed to delete pool %q. ", cephBlockPool.Name)
 		}
+		DeletePoolCapacityMetrics(cephBlockPool.Namespace, poolSpec.Name)
 
 		// disable RBD stats collection if cephBlockPool was deleted
 		if err := configureRBDStats(r.context, clusterInfo, cephBlockPool.Name); err != nil {
@@ -269,9 +271,18 @@ func (r *ReconcileCephBlockPool) reconcile(request reconcile.Request) (reconcile
 			logger.Info(opcontroller.OperatorNotInitializedMessage)
 			return opcontroller.WaitForRequeueIfOperatorNotInitialized, *cephBlockPool, nil
 		}
+		reporting.ReportValidationFailed(r.recorder, cephBlockPool, err)
 		return opcontroller.ImmediateRetryResult, *cephBlockPool, errors.Wrapf(err, "invalid pool CR %q spec", cephBlockPool.Name)
 	}
 
+	// refuse to apply a spec that ceph cannot apply to the existing pool rather than ignoring it
+	conflict := validateImmutablePoolSettings(r.context, clusterInfo, cephBlockPool.ToNamedPoolSpec())
+	updateSpecConflictCondition(r.opManagerContext, r.client, request.NamespacedName, conflict)
+	if conflict != nil {
+		updateStatus(r.opManagerContext, r.client, request.NamespacedName, cephv1.ConditionFailure, nil, k8sutil.ObservedGenerationNotAvailable)
+		return opcontroller.ImmediateRetryResult, *cephBlockPool, errors.Wrapf(conflict, "invalid update of pool CR %q", cephBlockPool.Name)
+	}
+
 	// Get CephCluster version
 	cephVersion, err := opcontroller.GetImageVersion(cephCluster)
 	if err != nil {
@@ -379,6 +390,10 @@ func createPool(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo,
 		appName = "nfs"
 	}
 
+	if err := ValidateNewPoolCapacity(context, clusterInfo, *p); err != nil {
+		return err
+	}
+
 	// create the pool
 	logger.Infof("creating pool %q in namespace %q", p.Name, clusterInfo.Namespace)
 	if err := cephclient.CreatePool(context, clusterInfo, clusterSpec, *p, appName); err != nil {
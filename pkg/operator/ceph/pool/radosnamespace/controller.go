@@ -133,6 +133,10 @@ func (r *ReconcileCephBlockPoolRadosNamespace) reconcile(request reconcile.Reque
 		// Error reading the object - requeue the request.
 		return reconcile.Result{}, errors.Wrap(err, "failed to get cephBlockPoolRadosNamespace")
 	}
+	// update observedGeneration local variable with current generation value,
+	// because generation can be changed before reconcile got completed
+	// CR status will be updated at end of reconcile, so to reflect the reconcile has finished
+	observedGeneration := cephBlockPoolRadosNamespace.ObjectMeta.Generation
 
 	// Set a finalizer so we can do cleanup before the object goes away
 	err = opcontroller.AddFinalizerIfNotPresent(r.opManagerContext, r.client, cephBlockPoolRadosNamespace)
@@ -142,7 +146,7 @@ func (r *ReconcileCephBlockPoolRadosNamespace) reconcile(request reconcile.Reque
 
 	// The CR was just created, initializing status fields
 	if cephBlockPoolRadosNamespace.Status == nil {
-		r.updateStatus(r.client, request.NamespacedName, cephv1.ConditionProgressing)
+		r.updateStatus(r.client, k8sutil.ObservedGenerationNotAvailable, request.NamespacedName, cephv1.ConditionProgressing)
 	}
 
 	// Make sure a CephCluster is present otherwise do nothing
@@ -210,7 +214,7 @@ func (r *ReconcileCephBlockPoolRadosNamespace) reconcile(request reconcile.Reque
 		if err != nil {
 			return reconcile.Result{}, errors.Wrap(err, "failed to save cluster config")
 		}
-		r.updateStatus(r.client, namespacedName, cephv1.ConditionReady)
+		r.updateStatus(r.client, observedGeneration, namespacedName, cephv1.ConditionReady)
 		return reconcile.Result{}, nil
 	}
 	// Build the NamespacedName to fetch the CephBlockPool and make sure it exists, if not we cannot
@@ -238,7 +242,7 @@ func (r *ReconcileCephBlockPoolRadosNamespace) reconcile(request reconcile.Reque
 			logger.Info(opcontroller.OperatorNotInitializedMessage)
 			return opcontroller.WaitForRequeueIfOperatorNotInitialized, nil
 		}
-		r.updateStatus(r.client, request.NamespacedName, cephv1.ConditionFailure)
+		r.updateStatus(r.client, k8sutil.ObservedGenerationNotAvailable, request.NamespacedName, cephv1.ConditionFailure)
 		return reconcile.Result{}, errors.Wrapf(err, "failed to create or update ceph pool rados namespace %q", cephBlockPoolRadosNamespace.Name)
 	}
 
@@ -247,7 +251,7 @@ func (r *ReconcileCephBlockPoolRadosNamespace) reconcile(request reconcile.Reque
 		return reconcile.Result{}, errors.Wrap(err, "failed to save cluster config")
 	}
 
-	r.updateStatus(r.client, namespacedName, cephv1.ConditionReady)
+	r.updateStatus(r.client, observedGeneration, namespacedName, cephv1.ConditionReady)
 	// Return and do not requeue
 	logger.Debug("done reconciling cephBlockPoolRadosNamespace %q", namespacedName)
 	return reconcile.Result{}, nil
@@ -259,7 +263,7 @@ func (r *ReconcileCephBlockPoolRadosNamespace) updateClusterConfig(cephBlockPool
 	// config map, so no special care is needed in this controller
 	csiClusterConfigEntry := csi.CsiClusterConfigEntry{
 		Namespace: r.clusterInfo.Namespace,
-		Monitors:  csi.MonEndpoints(r.clusterInfo.Monitors, cephCluster.Spec.RequireMsgr2()),
+		Monitors:  csi.ClusterMonEndpoints(r.clusterInfo.Namespace, r.clusterInfo.Monitors, &cephCluster.Spec),
 		RBD: &csi.CsiRBDSpec{
 			RadosNamespace: cephBlockPoolRadosNamespace.Name,
 		},
@@ -311,7 +315,7 @@ func (r *ReconcileCephBlockPoolRadosNamespace) deleteRadosNamespace(cephBlockPoo
 }
 
 // updateStatus updates an object with a given status
-func (r *ReconcileCephBlockPoolRadosNamespace) updateStatus(client client.Client, name types.NamespacedName, status cephv1.ConditionType) {
+func (r *ReconcileCephBlockPoolRadosNamespace) updateStatus(client client.Client, observedGeneration int64, name types.NamespacedName, status cephv1.ConditionType) {
 	cephBlockPoolRadosNamespace := &cephv1.CephBlockPoolRadosNamespace{}
 	if err := client.Get(r.opManagerContext, name, cephBlockPoolRadosNamespace); err != nil {
 		if kerrors.IsNotFound(err) {
@@ -327,6 +331,9 @@ func (r *ReconcileCephBlockPoolRadosNamespace) updateStatus(client client.Client
 
 	cephBlockPoolRadosNamespace.Status.Phase = status
 	cephBlockPoolRadosNamespace.Status.Info = map[string]string{"clusterID": buildClusterID(cephBlockPoolRadosNamespace)}
+	if observedGeneration != k8sutil.ObservedGenerationNotAvailable {
+		cephBlockPoolRadosNamespace.Status.ObservedGeneration = observedGeneration
+	}
 	if err := reporting.UpdateStatus(client, cephBlockPoolRadosNamespace); err != nil {
 		logger.Errorf("failed to set ceph blockpool rados namespace %q status to %q. %v", name, status, err)
 		return
@@ -24,6 +24,7 @@ import (
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/operator/ceph/reporting"
 	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -58,6 +59,41 @@ func updateStatus(ctx context.Context, client client.Client, poolName types.Name
 	logger.Debugf("pool %q status updated to %q", poolName, status)
 }
 
+// updateSpecConflictCondition sets the SpecConflict condition on the pool if the spec requests a
+// change that Ceph cannot apply to the existing pool, and clears it once the conflict is resolved
+func updateSpecConflictCondition(ctx context.Context, client client.Client, poolName types.NamespacedName, conflict error) {
+	pool := &cephv1.CephBlockPool{}
+	if err := client.Get(ctx, poolName, pool); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephBlockPool resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve pool %q to update the spec conflict condition. %v", poolName, err)
+		return
+	}
+	if pool.Status == nil {
+		pool.Status = &cephv1.CephBlockPoolStatus{}
+	}
+
+	cond := cephv1.Condition{
+		Type:   cephv1.ConditionSpecConflict,
+		Status: v1.ConditionFalse,
+		Reason: cephv1.ImmutableSettingsUnchangedReason,
+	}
+	if conflict != nil {
+		cond.Status = v1.ConditionTrue
+		cond.Reason = cephv1.ImmutableSettingChangedReason
+		cond.Message = conflict.Error()
+	} else if cephv1.FindStatusCondition(pool.Status.Conditions, cephv1.ConditionSpecConflict) == nil {
+		// there is no conflict to clear
+		return
+	}
+
+	if err := reporting.UpdateStatusCondition(client, pool, cond); err != nil {
+		logger.Warningf("failed to update pool %q spec conflict condition. %v", poolName, err)
+	}
+}
+
 // updateStatusBucket updates an object with a given status
 func (c *mirrorChecker) updateStatusMirroring(mirrorStatus *cephv1.PoolMirroringStatusSummarySpec, mirrorInfo *cephv1.PoolMirroringInfo, snapSchedStatus []cephv1.SnapshotSchedulesSpec, details string) {
 	blockPool := &cephv1.CephBlockPool{}
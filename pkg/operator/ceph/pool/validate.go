@@ -18,10 +18,13 @@ limitations under the License.
 package pool
 
 import (
+	"time"
+
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/clusterd"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // validatePool Validate the pool arguments
@@ -40,9 +43,58 @@ func validatePool(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo
 	if err := ValidatePoolSpec(context, clusterInfo, clusterSpec, &p.Spec.PoolSpec); err != nil {
 		return err
 	}
+
+	// refuse new pools that would keep a single copy of the data unless explicitly allowed. The pools of the other
+	// resources are checked when they are created.
+	if err := cephclient.CheckUnsafePool(context, clusterInfo, clusterSpec, p.ToNamedPoolSpec()); err != nil {
+		return err
+	}
 	return nil
 }
 
+// validateImmutablePoolSettings returns an error if the spec requests a change to a setting that
+// Ceph cannot change on the existing pool, i.e. the pool type and the erasure coding chunks
+func validateImmutablePoolSettings(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, p cephv1.NamedPoolSpec) error {
+	pools, err := cephclient.ListPoolSummaries(context, clusterInfo)
+	if err != nil {
+		return errors.Wrap(err, "failed to list pools")
+	}
+	exists := false
+	for _, pool := range pools {
+		if pool.Name == p.Name {
+			exists = true
+			break
+		}
+	}
+	if !exists {
+		// the pool will be created with the settings in the spec
+		return nil
+	}
+
+	details, err := cephclient.GetPoolDetails(context, clusterInfo, p.Name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get details of pool %q", p.Name)
+	}
+
+	current := cephv1.PoolSpec{}
+	if details.ErasureCodeProfile != "" {
+		ecProfile, err := cephclient.GetErasureCodeProfileDetails(context, clusterInfo, details.ErasureCodeProfile)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get erasure code profile of pool %q", p.Name)
+		}
+		current.ErasureCoded.DataChunks = ecProfile.DataChunkCount
+		current.ErasureCoded.CodingChunks = ecProfile.CodingChunkCount
+		// the plugin is only compared if an algorithm is requested, otherwise ceph picks its default
+		if p.ErasureCoded.Algorithm != "" {
+			current.ErasureCoded.Algorithm = ecProfile.Plugin
+		}
+	} else {
+		current.Replicated.Size = details.Size
+	}
+
+	return cephv1.ValidatePoolSpecUpdate(&current, &p.PoolSpec)
+}
+
 // ValidatePoolSpec validates the Ceph block pool spec CR
 func ValidatePoolSpec(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, clusterSpec *cephv1.ClusterSpec, p *cephv1.PoolSpec) error {
 
@@ -141,6 +193,10 @@ func ValidatePoolSpec(context *clusterd.Context, clusterInfo *cephclient.Cluster
 		}
 	}
 
+	if err := validateScrubSettings(p.Scrub); err != nil {
+		return err
+	}
+
 	// validate pool compression mode if specified
 	if p.CompressionMode != "" {
 		logger.Warning("compressionMode is DEPRECATED, use Parameters instead")
@@ -215,3 +271,27 @@ func validateDeviceClassOSDs(context *clusterd.Context, clusterInfo *cephclient.
 
 	return nil
 }
+
+// validateScrubSettings validates the scrub intervals of the pool
+func validateScrubSettings(scrub *cephv1.PoolScrubSpec) error {
+	if scrub == nil {
+		return nil
+	}
+	intervals := []struct {
+		name     string
+		interval *metav1.Duration
+	}{
+		{"minInterval", scrub.MinInterval},
+		{"maxInterval", scrub.MaxInterval},
+		{"deepInterval", scrub.DeepInterval},
+	}
+	for _, i := range intervals {
+		if i.interval != nil && i.interval.Duration < time.Second {
+			return errors.Errorf("scrub %s %q must be at least one second", i.name, i.interval.Duration)
+		}
+	}
+	if scrub.MinInterval != nil && scrub.MaxInterval != nil && scrub.MinInterval.Duration > scrub.MaxInterval.Duration {
+		return errors.Errorf("scrub minInterval %q must not exceed maxInterval %q", scrub.MinInterval.Duration, scrub.MaxInterval.Duration)
+	}
+	return nil
+}
@@ -19,6 +19,7 @@ package pool
 
 import (
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
@@ -32,7 +33,7 @@ import (
 func TestValidatePool(t *testing.T) {
 	context := &clusterd.Context{Executor: &exectest.MockExecutor{}}
 	clusterInfo := &cephclient.ClusterInfo{Namespace: "myns"}
-	clusterSpec := &cephv1.ClusterSpec{}
+	clusterSpec := &cephv1.ClusterSpec{AllowUnsafePools: true}
 
 	t.Run("not specifying replication or EC settings is invalid", func(t *testing.T) {
 		p := cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: "mypool", Namespace: clusterInfo.Namespace}}
@@ -81,6 +82,45 @@ func TestValidatePool(t *testing.T) {
 		assert.Error(t, err)
 	})
 
+	t.Run("fail with unsafe pools unless allowed", func(t *testing.T) {
+		clusterSpec := &cephv1.ClusterSpec{}
+		clusterInfo := cephclient.AdminTestClusterInfo("myns")
+		context := &clusterd.Context{Executor: &exectest.MockExecutor{
+			MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+				if args[0] == "osd" && args[1] == "lspools" {
+					return `[{"poolnum":1,"poolname":"existing"}]`, nil
+				}
+				return "", errors.Errorf("unexpected ceph command %q", args)
+			},
+		}}
+		p := cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: "mypool", Namespace: clusterInfo.Namespace}}
+		p.Spec.Replicated.Size = 1
+		err := validatePool(context, clusterInfo, clusterSpec, &p)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "allowUnsafePools")
+
+		p.Spec.Replicated.Size = 0
+		p.Spec.ErasureCoded.DataChunks = 2
+		err = validatePool(context, clusterInfo, clusterSpec, &p)
+		assert.Error(t, err)
+
+		p.Spec.ErasureCoded.CodingChunks = 1
+		err = validatePool(context, clusterInfo, clusterSpec, &p)
+		assert.NoError(t, err)
+
+		p.Spec.ErasureCoded.DataChunks = 0
+		p.Spec.ErasureCoded.CodingChunks = 0
+		p.Spec.Replicated.Size = 2
+		err = validatePool(context, clusterInfo, clusterSpec, &p)
+		assert.NoError(t, err)
+
+		// the existing unsafe pools keep working
+		p.Spec.Replicated.Size = 1
+		p.Spec.Name = "existing"
+		err = validatePool(context, clusterInfo, clusterSpec, &p)
+		assert.NoError(t, err)
+	})
+
 	t.Run("succeed with ec settings", func(t *testing.T) {
 		p := cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: "mypool", Namespace: clusterInfo.Namespace}}
 		p.Spec.ErasureCoded.CodingChunks = 1
@@ -229,7 +269,7 @@ func TestValidateCrushProperties(t *testing.T) {
 			},
 		},
 	}
-	clusterSpec := &cephv1.ClusterSpec{}
+	clusterSpec := &cephv1.ClusterSpec{AllowUnsafePools: true}
 
 	err := validatePool(context, clusterInfo, clusterSpec, p)
 	assert.Nil(t, err)
@@ -329,3 +369,75 @@ func TestValidateDeviceClasses(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateImmutablePoolSettings(t *testing.T) {
+	clusterInfo := cephclient.AdminTestClusterInfo("mycluster")
+	poolDetails := `{"pool":"mypool","erasure_code_profile":"mypool_ecprofile"}{"pool":"mypool","size":3}`
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			logger.Infof("Command: %s %v", command, args)
+			if args[0] == "osd" && args[1] == "lspools" {
+				return `[{"poolnum":1,"poolname":"mypool"}]`, nil
+			}
+			if args[0] == "osd" && args[1] == "pool" && args[2] == "get" {
+				return poolDetails, nil
+			}
+			if args[0] == "osd" && args[1] == "erasure-code-profile" && args[2] == "get" {
+				return `{"k":"2","m":"1","plugin":"jerasure"}`, nil
+			}
+			return "", errors.Errorf("unexpected ceph command %q", args)
+		},
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	t.Run("new pool", func(t *testing.T) {
+		p := cephv1.NamedPoolSpec{Name: "newpool", PoolSpec: cephv1.PoolSpec{ErasureCoded: cephv1.ErasureCodedSpec{DataChunks: 4, CodingChunks: 2}}}
+		assert.NoError(t, validateImmutablePoolSettings(context, clusterInfo, p))
+	})
+
+	t.Run("erasure coded pool unchanged", func(t *testing.T) {
+		p := cephv1.NamedPoolSpec{Name: "mypool", PoolSpec: cephv1.PoolSpec{ErasureCoded: cephv1.ErasureCodedSpec{DataChunks: 2, CodingChunks: 1}}}
+		assert.NoError(t, validateImmutablePoolSettings(context, clusterInfo, p))
+
+		p.ErasureCoded.Algorithm = "jerasure"
+		assert.NoError(t, validateImmutablePoolSettings(context, clusterInfo, p))
+	})
+
+	t.Run("erasure coded chunks changed", func(t *testing.T) {
+		p := cephv1.NamedPoolSpec{Name: "mypool", PoolSpec: cephv1.PoolSpec{ErasureCoded: cephv1.ErasureCodedSpec{DataChunks: 4, CodingChunks: 2}}}
+		err := validateImmutablePoolSettings(context, clusterInfo, p)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), cephv1.ImmutablePoolSettingRemediation)
+	})
+
+	t.Run("erasure coded algorithm changed", func(t *testing.T) {
+		p := cephv1.NamedPoolSpec{Name: "mypool", PoolSpec: cephv1.PoolSpec{ErasureCoded: cephv1.ErasureCodedSpec{DataChunks: 2, CodingChunks: 1, Algorithm: "isa"}}}
+		assert.Error(t, validateImmutablePoolSettings(context, clusterInfo, p))
+	})
+
+	t.Run("erasure coded pool changed to replicated", func(t *testing.T) {
+		p := cephv1.NamedPoolSpec{Name: "mypool", PoolSpec: cephv1.PoolSpec{Replicated: cephv1.ReplicatedSpec{Size: 3}}}
+		assert.Error(t, validateImmutablePoolSettings(context, clusterInfo, p))
+	})
+
+	t.Run("replicated pool changed to erasure coded", func(t *testing.T) {
+		poolDetails = `{"pool":"mypool","size":3}`
+		p := cephv1.NamedPoolSpec{Name: "mypool", PoolSpec: cephv1.PoolSpec{ErasureCoded: cephv1.ErasureCodedSpec{DataChunks: 2, CodingChunks: 1}}}
+		assert.Error(t, validateImmutablePoolSettings(context, clusterInfo, p))
+
+		p = cephv1.NamedPoolSpec{Name: "mypool", PoolSpec: cephv1.PoolSpec{Replicated: cephv1.ReplicatedSpec{Size: 2}}}
+		assert.NoError(t, validateImmutablePoolSettings(context, clusterInfo, p))
+	})
+}
+
+func TestValidateScrubSettings(t *testing.T) {
+	duration := func(d time.Duration) *metav1.Duration { return &metav1.Duration{Duration: d} }
+	assert.NoError(t, validateScrubSettings(nil))
+	assert.NoError(t, validateScrubSettings(&cephv1.PoolScrubSpec{MinInterval: duration(24 * time.Hour), MaxInterval: duration(7 * 24 * time.Hour)}))
+	assert.NoError(t, validateScrubSettings(&cephv1.PoolScrubSpec{DeepInterval: duration(14 * 24 * time.Hour)}))
+
+	err := validateScrubSettings(&cephv1.PoolScrubSpec{MinInterval: duration(7 * 24 * time.Hour), MaxInterval: duration(24 * time.Hour)})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "must not exceed maxInterval")
+	assert.Error(t, validateScrubSettings(&cephv1.PoolScrubSpec{DeepInterval: duration(0)}))
+}
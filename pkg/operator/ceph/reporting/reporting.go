@@ -25,8 +25,11 @@ import (
 	"github.com/coreos/pkg/capnslog"
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
 	"github.com/rook/rook/pkg/util/dependents"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
@@ -158,6 +161,32 @@ func ReportReconcileResult(
 	return reconcileResponse, err
 }
 
+// ReportValidationFailed reports that the spec of an object was rejected as a warning event on the
+// object, so that the reason is shown by `kubectl describe` next to the failed reconcile.
+func ReportValidationFailed(recorder record.EventRecorder, obj client.Object, err error) {
+	kind := objKindOrBestGuess(obj)
+	nsName := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+	recorder.Eventf(obj, corev1.EventTypeWarning, string(cephv1.ValidationFailedReason), "invalid %s %q spec. %v", kind, nsName.String(), err)
+}
+
+// ReportCephClusterWarning reports a warning on the CephCluster of the cluster info, e.g. when a daemon is updated
+// although its safety checks failed, in 2 ways:
+// 1. to the given logger
+// 2. as a warning event on the CephCluster, if the operator has an event recorder
+func ReportCephClusterWarning(logger *capnslog.PackageLogger, clusterdContext *clusterd.Context, clusterInfo *cephclient.ClusterInfo, reason cephv1.ConditionReason, message string) {
+	logger.Warning(message)
+	if clusterdContext.Recorder == nil {
+		return
+	}
+	name := clusterInfo.NamespacedName()
+	cephCluster, err := clusterdContext.RookClientset.CephV1().CephClusters(name.Namespace).Get(clusterInfo.Context, name.Name, metav1.GetOptions{})
+	if err != nil {
+		logger.Errorf("failed to get ceph cluster %q to report event %q. %v", name.String(), reason, err)
+		return
+	}
+	clusterdContext.Recorder.Event(cephCluster, corev1.EventTypeWarning, string(reason), message)
+}
+
 // ReportDeletionBlockedDueToDependents reports that deletion of a Rook-Ceph object is blocked due
 // to the given dependents in 3 ways:
 // 1. to the given logger
@@ -19,6 +19,7 @@ package reporting
 
 import (
 	"bytes"
+	"context"
 	"strings"
 	"testing"
 	"time"
@@ -26,6 +27,9 @@ import (
 	"github.com/coreos/pkg/capnslog"
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	rookfake "github.com/rook/rook/pkg/client/clientset/versioned/fake"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -186,3 +190,47 @@ func TestReportReconcileResult(t *testing.T) {
 		assert.Equal(t, errorEvent, <-recorder.Events)
 	})
 }
+
+func TestReportValidationFailed(t *testing.T) {
+	recorder := record.NewFakeRecorder(1)
+	pool := &cephv1.CephBlockPool{
+		TypeMeta:   metav1.TypeMeta{Kind: "CephBlockPool"},
+		ObjectMeta: metav1.ObjectMeta{Name: "replicapool", Namespace: "rook-ceph"},
+	}
+
+	ReportValidationFailed(recorder, pool, errors.New("invalid replica size 0"))
+	assert.Len(t, recorder.Events, 1)
+	assert.Equal(t, `Warning ValidationFailed invalid CephBlockPool "rook-ceph/replicapool" spec. invalid replica size 0`, <-recorder.Events)
+}
+
+func TestReportCephClusterWarning(t *testing.T) {
+	logger := capnslog.NewPackageLogger("github.com/rook/rook", "")
+	logBuf := bytes.NewBuffer([]byte{})
+	capnslog.SetFormatter(capnslog.NewDefaultFormatter(logBuf))
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "rook-ceph"}}
+	clusterdContext := &clusterd.Context{RookClientset: rookfake.NewSimpleClientset(cephCluster)}
+	clusterInfo := cephclient.NewClusterInfo("rook-ceph", "my-cluster")
+	clusterInfo.Context = context.TODO()
+
+	t.Run("without recorder", func(t *testing.T) {
+		ReportCephClusterWarning(logger, clusterdContext, clusterInfo, cephv1.UpgradeChecksBypassedReason, "OSD 1 is not ok-to-stop")
+		assert.Contains(t, logBuf.String(), "OSD 1 is not ok-to-stop")
+	})
+
+	t.Run("with recorder", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(1)
+		clusterdContext.Recorder = recorder
+		ReportCephClusterWarning(logger, clusterdContext, clusterInfo, cephv1.UpgradeChecksBypassedReason, "OSD 1 is not ok-to-stop")
+		assert.Len(t, recorder.Events, 1)
+		assert.Equal(t, "Warning UpgradeChecksBypassed OSD 1 is not ok-to-stop", <-recorder.Events)
+	})
+
+	t.Run("missing cluster", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(1)
+		clusterdContext.Recorder = recorder
+		otherClusterInfo := cephclient.NewClusterInfo("other", "other")
+		otherClusterInfo.Context = context.TODO()
+		ReportCephClusterWarning(logger, clusterdContext, otherClusterInfo, cephv1.UpgradeChecksBypassedReason, "OSD 1 is not ok-to-stop")
+		assert.Len(t, recorder.Events, 0)
+	})
+}
@@ -19,14 +19,27 @@ package reporting
 import (
 	"context"
 	"fmt"
+	"reflect"
 
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
+var resourcePhase = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "rook_ceph_resource_phase",
+	Help: "Set to 1 for the current phase of the status of each Rook custom resource",
+}, []string{"kind", "namespace", "name", "phase"})
+
+func init() {
+	// the metrics are served by the metrics endpoint of the controller-runtime manager of the operator
+	metrics.Registry.MustRegister(resourcePhase)
+}
+
 // UpdateStatus updates an object with a given status. The object is updated with the latest version
 // from the server on a successful update.
 func UpdateStatus(client client.Client, obj client.Object) error {
@@ -35,6 +48,15 @@ func UpdateStatus(client client.Client, obj client.Object) error {
 		Name:      obj.GetName(),
 	}
 
+	// Report the phase as conditions as well, except for the CephCluster which manages its own conditions
+	if getter, ok := obj.(cephv1.StatusConditionGetter); ok {
+		if _, isCluster := obj.(*cephv1.CephCluster); !isCluster {
+			if phase := statusPhase(obj); phase != "" {
+				cephv1.SetPhaseConditions(getter.GetStatusConditions(), phase)
+			}
+		}
+	}
+
 	// Try to update the status
 	err := client.Status().Update(context.Background(), obj)
 	// If the object doesn't exist yet, we need to initialize it
@@ -45,9 +67,44 @@ func UpdateStatus(client client.Client, obj client.Object) error {
 		return errors.Wrapf(err, "failed to update object %q status", nsName.String())
 	}
 
+	if phase := statusPhase(obj); phase != "" {
+		DeleteResourcePhase(obj)
+		resourcePhase.WithLabelValues(objKindOrBestGuess(obj), obj.GetNamespace(), obj.GetName(), phase).Set(1)
+	}
+
 	return nil
 }
 
+// DeleteResourcePhase removes the phase metric of a resource, once the resource is deleted
+func DeleteResourcePhase(obj client.Object) {
+	resourcePhase.DeletePartialMatch(prometheus.Labels{
+		"kind":      objKindOrBestGuess(obj),
+		"namespace": obj.GetNamespace(),
+		"name":      obj.GetName(),
+	})
+}
+
+// statusPhase returns the phase of the status of a resource, or an empty string if its status has
+// no phase. The status types of the CRs differ, so the phase is found via golang reflection.
+func statusPhase(obj client.Object) string {
+	if objIsNil(obj) {
+		return ""
+	}
+	v := reflect.Indirect(reflect.ValueOf(obj))
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+	status := reflect.Indirect(v.FieldByName("Status"))
+	if status.Kind() != reflect.Struct {
+		return ""
+	}
+	phase := status.FieldByName("Phase")
+	if phase.Kind() != reflect.String {
+		return ""
+	}
+	return phase.String()
+}
+
 // UpdateStatusCondition updates (or adds to) the status condition to the given object. The object
 // is updated with the latest version from the server on a successful update.
 func UpdateStatusCondition(
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
 	"github.com/stretchr/testify/assert"
@@ -101,6 +102,32 @@ func TestUpdateStatus(t *testing.T) {
 	err = cl.Get(context.TODO(), nsName, getObj)
 	assert.NoError(t, err)
 	assert.Equal(t, cephv1.ConditionReady, getObj.Status.Phase)
+	readyCond := cephv1.FindStatusCondition(getObj.Status.Conditions, cephv1.ConditionReady)
+	assert.NotNil(t, readyCond)
+	assert.Equal(t, v1.ConditionTrue, readyCond.Status)
+
+	t.Run("phase metric", func(t *testing.T) {
+		resourcePhase.Reset()
+		getObj.Status.Phase = cephv1.ConditionProgressing
+		assert.NoError(t, UpdateStatus(cl, getObj))
+		assert.Equal(t, float64(1), testutil.ToFloat64(resourcePhase.WithLabelValues("CephBlockPool", "rook-ceph", "test", "Progressing")))
+
+		// only the current phase is reported
+		getObj.Status.Phase = cephv1.ConditionReady
+		assert.NoError(t, UpdateStatus(cl, getObj))
+		assert.Equal(t, 1, testutil.CollectAndCount(resourcePhase))
+		assert.Equal(t, float64(1), testutil.ToFloat64(resourcePhase.WithLabelValues("CephBlockPool", "rook-ceph", "test", "Ready")))
+
+		DeleteResourcePhase(getObj)
+		assert.Equal(t, 0, testutil.CollectAndCount(resourcePhase))
+	})
+}
+
+func TestStatusPhase(t *testing.T) {
+	assert.Equal(t, "", statusPhase(&cephv1.CephBlockPool{}))
+	assert.Equal(t, "Ready", statusPhase(&cephv1.CephBlockPool{Status: &cephv1.CephBlockPoolStatus{Phase: cephv1.ConditionReady}}))
+	assert.Equal(t, "Ready", statusPhase(&cephv1.CephCluster{Status: cephv1.ClusterStatus{Phase: cephv1.ConditionReady}}))
+	assert.Equal(t, "", statusPhase(&v1.ConfigMap{}))
 }
 
 func TestUpdateStatusCondition(t *testing.T) {
@@ -38,6 +38,7 @@ const (
 	serviceCephClusterPath                  = "/validate-ceph-rook-io-v1-cephcluster"
 	serviceCephBlockPoolPath                = "/validate-ceph-rook-io-v1-cephblockpool"
 	serviceCephObjectStorePath              = "/validate-ceph-rook-io-v1-cephobjectstore"
+	serviceCephFilesystemPath               = "/validate-ceph-rook-io-v1-cephfilesystem"
 	serviceCephBlockPoolRadosNamespacePath  = "/validate-ceph-rook-io-v1-cephblockpoolradosnamespace"
 	serviceCephFileSystemSubvolumeGroupPath = "/validate-ceph-rook-io-v1-cephfilesystemsubvolumegroup"
 )
@@ -163,6 +164,7 @@ func addValidatingWebhookConfig(ctx context.Context, clusterdContext *clusterd.C
 		fmt.Sprintf("cephobjectstore-wh-%s-%s.rook.io", admissionControllerAppName, namespace),
 		fmt.Sprintf("cephblockpoolradosnamespace-wh-%s-%s.rook.io", admissionControllerAppName, namespace),
 		fmt.Sprintf("cephfilesystemsubvolumegroup-wh-%s-%s.rook.io", admissionControllerAppName, namespace),
+		fmt.Sprintf("cephfilesystem-wh-%s-%s.rook.io", admissionControllerAppName, namespace),
 	}
 
 	resources := []string{
@@ -171,6 +173,7 @@ func addValidatingWebhookConfig(ctx context.Context, clusterdContext *clusterd.C
 		"cephobjectstores",
 		"cephblockpoolradosnamespaces",
 		"cephfilesystemsubvolumegroups",
+		"cephfilesystems",
 	}
 
 	resourcesServicePath := []string{
@@ -179,6 +182,7 @@ func addValidatingWebhookConfig(ctx context.Context, clusterdContext *clusterd.C
 		serviceCephObjectStorePath,
 		serviceCephBlockPoolRadosNamespacePath,
 		serviceCephFileSystemSubvolumeGroupPath,
+		serviceCephFilesystemPath,
 	}
 
 	logger.Infof("Creating webhook %s/%s.", namespace, webhookConfigName)
@@ -19,7 +19,10 @@ package k8sutil
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/util"
 	appsv1 "k8s.io/api/apps/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -78,3 +81,40 @@ func GetDaemonsets(ctx context.Context, clientset kubernetes.Interface, namespac
 	}
 	return daemonsets, nil
 }
+
+// WaitForDaemonSetReady waits until the daemonset runs an updated and ready pod on every node it is scheduled on. A
+// daemonset that is not scheduled on any node is never ready.
+func WaitForDaemonSetReady(ctx context.Context, clientset kubernetes.Interface, namespace, name string) error {
+	ds, err := clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get daemonset %q", name)
+	}
+
+	waitFunc := func() (done bool, err error) {
+		ds, err := clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to get daemonset %q", name)
+		}
+		if daemonSetIsReady(ds) {
+			logger.Infof("daemonset %q is ready on %d nodes", name, ds.Status.NumberReady)
+			return true, nil
+		}
+		logger.Debugf("waiting for daemonset %q to be ready. %d of %d pods updated, %d ready", name, ds.Status.UpdatedNumberScheduled, ds.Status.DesiredNumberScheduled, ds.Status.NumberReady)
+		return false, nil
+	}
+	// the rolling update may replace the pods one node at a time
+	nodes := ds.Status.DesiredNumberScheduled
+	if nodes < 1 {
+		nodes = 1
+	}
+	timeout := time.Duration(nodes) * waitForDeploymentTimeout
+	return util.RetryWithTimeout(waitFunc, waitForDeploymentPeriod, timeout, fmt.Sprintf("daemonset %q to be ready", name))
+}
+
+func daemonSetIsReady(ds *appsv1.DaemonSet) bool {
+	desired := ds.Status.DesiredNumberScheduled
+	return ds.Status.ObservedGeneration >= ds.Generation &&
+		desired > 0 &&
+		ds.Status.UpdatedNumberScheduled == desired &&
+		ds.Status.NumberReady == desired
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8sutil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestWaitForDaemonSetReady(t *testing.T) {
+	oldPeriod := waitForDeploymentPeriod
+	oldTimeout := waitForDeploymentTimeout
+	defer func() {
+		waitForDeploymentPeriod = oldPeriod
+		waitForDeploymentTimeout = oldTimeout
+	}()
+	waitForDeploymentPeriod = 1 * time.Millisecond
+	waitForDeploymentTimeout = 3 * time.Millisecond
+
+	ctx := context.TODO()
+	ds := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "rgw", Namespace: "ns", Generation: 2},
+		Status: appsv1.DaemonSetStatus{
+			ObservedGeneration:     2,
+			DesiredNumberScheduled: 3,
+			UpdatedNumberScheduled: 3,
+			NumberReady:            3,
+		},
+	}
+
+	t.Run("ready on all the nodes", func(t *testing.T) {
+		clientset := fake.NewSimpleClientset(ds.DeepCopy())
+		assert.NoError(t, WaitForDaemonSetReady(ctx, clientset, "ns", "rgw"))
+	})
+
+	t.Run("a pod is not ready", func(t *testing.T) {
+		notReady := ds.DeepCopy()
+		notReady.Status.NumberReady = 2
+		clientset := fake.NewSimpleClientset(notReady)
+		assert.Error(t, WaitForDaemonSetReady(ctx, clientset, "ns", "rgw"))
+	})
+
+	t.Run("the update is not observed yet", func(t *testing.T) {
+		notObserved := ds.DeepCopy()
+		notObserved.Generation = 3
+		clientset := fake.NewSimpleClientset(notObserved)
+		assert.Error(t, WaitForDaemonSetReady(ctx, clientset, "ns", "rgw"))
+	})
+
+	t.Run("not scheduled on any node", func(t *testing.T) {
+		noNodes := ds.DeepCopy()
+		noNodes.Status = appsv1.DaemonSetStatus{ObservedGeneration: 2}
+		clientset := fake.NewSimpleClientset(noNodes)
+		assert.Error(t, WaitForDaemonSetReady(ctx, clientset, "ns", "rgw"))
+	})
+
+	t.Run("missing daemonset", func(t *testing.T) {
+		clientset := fake.NewSimpleClientset()
+		assert.Error(t, WaitForDaemonSetReady(ctx, clientset, "ns", "rgw"))
+	})
+}
@@ -313,6 +313,55 @@ func UpdateMultipleDeploymentsAndWait(
 	return append(updateFailures, waitFailures...)
 }
 
+// WaitForDeploymentRollout waits until all the replicas of the deployment are updated and available. The rolling
+// update replaces the pods one at a time, and only moves on to the next pod after the updated pod is ready. A pod
+// that never becomes ready stops the rollout, which fails when the progress deadline of the deployment is exceeded.
+func WaitForDeploymentRollout(ctx context.Context, clientset kubernetes.Interface, namespace, name string) error {
+	deployment, err := clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get deployment %q", name)
+	}
+
+	waitFunc := func() (done bool, err error) {
+		d, err := clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to get deployment %q", name)
+		}
+		if err := progressDeadlineExceeded(d); err != nil {
+			return true, err
+		}
+		if deploymentRolloutIsComplete(d) {
+			logger.Infof("finished waiting for the rollout of deployment %q", name)
+			return true, nil
+		}
+		logger.Debugf("waiting for the rollout of deployment %q. %d of %d replicas updated, %d available", name, d.Status.UpdatedReplicas, deploymentReplicas(d), d.Status.AvailableReplicas)
+		return false, nil
+	}
+
+	// each replica is given the progress deadline to become available
+	progressDeadline := waitForDeploymentTimeout
+	if deployment.Spec.ProgressDeadlineSeconds != nil {
+		progressDeadline = time.Duration(*deployment.Spec.ProgressDeadlineSeconds) * time.Second
+	}
+	timeout := time.Duration(deploymentReplicas(deployment)) * progressDeadline
+	return util.RetryWithTimeout(waitFunc, waitForDeploymentPeriod, timeout, fmt.Sprintf("rollout of deployment %q", name))
+}
+
+func deploymentRolloutIsComplete(d *appsv1.Deployment) bool {
+	replicas := deploymentReplicas(d)
+	return d.Status.ObservedGeneration >= d.Generation &&
+		d.Status.UpdatedReplicas == replicas &&
+		d.Status.Replicas == replicas &&
+		d.Status.AvailableReplicas == replicas
+}
+
+func deploymentReplicas(d *appsv1.Deployment) int32 {
+	if d.Spec.Replicas == nil {
+		return 1
+	}
+	return *d.Spec.Replicas
+}
+
 func deploymentIsDoneUpdating(d *appsv1.Deployment, oldObservedGeneration int64) bool {
 	return d.Status.ObservedGeneration != oldObservedGeneration && d.Status.UpdatedReplicas > 0 && d.Status.ReadyReplicas > 0
 }
@@ -442,6 +442,65 @@ func TestWaitForDeploymentsToUpdate(t *testing.T) {
 	})
 }
 
+func TestWaitForDeploymentRollout(t *testing.T) {
+	oldPeriod := waitForDeploymentPeriod
+	oldTimeout := waitForDeploymentTimeout
+	defer func() {
+		waitForDeploymentPeriod = oldPeriod
+		waitForDeploymentTimeout = oldTimeout
+	}()
+	waitForDeploymentPeriod = 1 * time.Millisecond
+	waitForDeploymentTimeout = 10 * time.Millisecond
+
+	ctx := context.TODO()
+	replicas := int32(3)
+	newDeployment := func(status appsv1.DeploymentStatus) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "rgw", Namespace: "ns", Generation: 2},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status:     status,
+		}
+	}
+
+	t.Run("rollout complete", func(t *testing.T) {
+		clientset := fake.NewSimpleClientset(newDeployment(appsv1.DeploymentStatus{
+			ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 3,
+		}))
+		assert.NoError(t, WaitForDeploymentRollout(ctx, clientset, "ns", "rgw"))
+	})
+
+	t.Run("old replica still running", func(t *testing.T) {
+		// the updated replicas are available, but an old replica is still being stopped
+		clientset := fake.NewSimpleClientset(newDeployment(appsv1.DeploymentStatus{
+			ObservedGeneration: 2, Replicas: 4, UpdatedReplicas: 3, AvailableReplicas: 3,
+		}))
+		err := WaitForDeploymentRollout(ctx, clientset, "ns", "rgw")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "timed out")
+	})
+
+	t.Run("rollout not observed", func(t *testing.T) {
+		clientset := fake.NewSimpleClientset(newDeployment(appsv1.DeploymentStatus{
+			ObservedGeneration: 1, Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 3,
+		}))
+		assert.Error(t, WaitForDeploymentRollout(ctx, clientset, "ns", "rgw"))
+	})
+
+	t.Run("updated replica never ready", func(t *testing.T) {
+		clientset := fake.NewSimpleClientset(newDeployment(appsv1.DeploymentStatus{
+			ObservedGeneration: 2, Replicas: 4, UpdatedReplicas: 1, AvailableReplicas: 3,
+			Conditions: []appsv1.DeploymentCondition{{Type: appsv1.DeploymentProgressing, Reason: "ProgressDeadlineExceeded"}},
+		}))
+		err := WaitForDeploymentRollout(ctx, clientset, "ns", "rgw")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "ProgressDeadlineExceeded")
+	})
+
+	t.Run("deployment not found", func(t *testing.T) {
+		assert.Error(t, WaitForDeploymentRollout(ctx, fake.NewSimpleClientset(), "ns", "rgw"))
+	})
+}
+
 func Test_maxInt32Ptr(t *testing.T) {
 	t.Run("both nil", func(t *testing.T) {
 		assert.Nil(t, maxInt32Ptr(nil, nil))
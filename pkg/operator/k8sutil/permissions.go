@@ -0,0 +1,77 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8sutil
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Permission is an action on a kind of resource that the operator needs for a feature
+type Permission struct {
+	// Group is the api group of the resource, empty for the core group
+	Group    string
+	Resource string
+	Verb     string
+	// Namespace is the namespace the action is checked in. An empty namespace checks the action in all namespaces.
+	Namespace string
+	// Feature describes what does not work without the permission
+	Feature string
+}
+
+func (p Permission) String() string {
+	resource := p.Resource
+	if p.Group != "" {
+		resource = fmt.Sprintf("%s.%s", p.Resource, p.Group)
+	}
+	namespace := "all namespaces"
+	if p.Namespace != "" {
+		namespace = fmt.Sprintf("namespace %q", p.Namespace)
+	}
+	return fmt.Sprintf("%s %s in %s", p.Verb, resource, namespace)
+}
+
+// MissingPermissions returns the permissions that the service account of the operator is not allowed, checked with
+// self subject access reviews so that no permission is needed to run the check
+func MissingPermissions(ctx context.Context, clientset kubernetes.Interface, permissions []Permission) ([]Permission, error) {
+	missing := []Permission{}
+	for _, p := range permissions {
+		review := &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Group:     p.Group,
+					Resource:  p.Resource,
+					Verb:      p.Verb,
+					Namespace: p.Namespace,
+				},
+			},
+		}
+		result, err := clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to review permission to %s", p.String())
+		}
+		if !result.Status.Allowed {
+			missing = append(missing, p)
+		}
+	}
+	return missing, nil
+}
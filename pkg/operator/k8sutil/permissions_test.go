@@ -0,0 +1,48 @@
+/*
+Copyright 2023 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8sutil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestMissingPermissions(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	// only allow the actions in the namespace of the cluster
+	clientset.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		review.Status.Allowed = review.Spec.ResourceAttributes.Namespace == "rook-ceph"
+		return true, review, nil
+	})
+
+	namespaced := Permission{Resource: "secrets", Verb: "get", Namespace: "rook-ceph", Feature: "keyrings"}
+	clusterWide := Permission{Resource: "secrets", Verb: "watch", Feature: "secret changes"}
+	missing, err := MissingPermissions(context.TODO(), clientset, []Permission{namespaced, clusterWide})
+	assert.NoError(t, err)
+	assert.Equal(t, []Permission{clusterWide}, missing)
+
+	assert.Equal(t, `get secrets in namespace "rook-ceph"`, namespaced.String())
+	assert.Equal(t, "watch secrets in all namespaces", clusterWide.String())
+	assert.Equal(t, "create servicemonitors.monitoring.coreos.com in all namespaces", Permission{Group: "monitoring.coreos.com", Resource: "servicemonitors", Verb: "create"}.String())
+}
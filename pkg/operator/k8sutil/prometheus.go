@@ -86,3 +86,16 @@ func CreateOrUpdateServiceMonitor(ctx context.Context, serviceMonitorDefinition
 	}
 	return sm, nil
 }
+
+// DeleteServiceMonitor deletes the serviceMonitor if it exists
+func DeleteServiceMonitor(ctx context.Context, namespace, name string) error {
+	client, err := getMonitoringClient()
+	if err != nil {
+		return fmt.Errorf("failed to get monitoring client. %v", err)
+	}
+	err = client.MonitoringV1().ServiceMonitors(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete servicemonitor. %v", err)
+	}
+	return nil
+}
@@ -69,6 +69,9 @@ func UpdateService(
 	}
 	// ClusterIP is immutable for k8s services and cannot be left empty in k8s v1 API
 	serviceDefinition.Spec.ClusterIP = existing.Spec.ClusterIP
+	// the ip families must match the existing cluster ips, only a new service gets the requested families
+	serviceDefinition.Spec.IPFamilies = existing.Spec.IPFamilies
+	serviceDefinition.Spec.IPFamilyPolicy = existing.Spec.IPFamilyPolicy
 	// ResourceVersion required to update services in k8s v1 API to prevent race conditions
 	serviceDefinition.ResourceVersion = existing.ResourceVersion
 	return clientset.CoreV1().Services(namespace).Update(ctx, serviceDefinition, metav1.UpdateOptions{})
@@ -18,10 +18,13 @@ limitations under the License.
 package k8sutil
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 func TestParseServiceType(t *testing.T) {
@@ -35,3 +38,34 @@ func TestParseServiceType(t *testing.T) {
 		assert.Equal(t, v1.ServiceType(""), ParseServiceType(serviceType))
 	}
 }
+
+func TestUpdateServiceKeepsIPFamilies(t *testing.T) {
+	ctx := context.TODO()
+	clientset := fake.NewSimpleClientset()
+	singleStack := v1.IPFamilyPolicySingleStack
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-svc", Namespace: "ns"},
+		Spec: v1.ServiceSpec{
+			ClusterIP:      "10.0.0.1",
+			IPFamilyPolicy: &singleStack,
+			IPFamilies:     []v1.IPFamily{v1.IPv4Protocol},
+		},
+	}
+	_, err := CreateOrUpdateService(ctx, clientset, "ns", svc)
+	assert.NoError(t, err)
+
+	// the ip families of the existing service are immutable
+	dualStack := v1.IPFamilyPolicyPreferDualStack
+	update := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-svc", Namespace: "ns"},
+		Spec: v1.ServiceSpec{
+			IPFamilyPolicy: &dualStack,
+			IPFamilies:     []v1.IPFamily{v1.IPv6Protocol, v1.IPv4Protocol},
+		},
+	}
+	updated, err := CreateOrUpdateService(ctx, clientset, "ns", update)
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.1", updated.Spec.ClusterIP)
+	assert.Equal(t, v1.IPFamilyPolicySingleStack, *updated.Spec.IPFamilyPolicy)
+	assert.Equal(t, []v1.IPFamily{v1.IPv4Protocol}, updated.Spec.IPFamilies)
+}
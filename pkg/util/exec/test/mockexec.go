@@ -47,7 +47,7 @@ func (e *MockExecutor) ExecuteCommand(command string, arg ...string) error {
 
 // ExecuteCommandWithStdin starts a process, provides stdin and wait for its completion with timeout.
 func (e *MockExecutor) ExecuteCommandWithStdin(timeout time.Duration, command string, stdin *string, arg ...string) error {
-	if e.MockExecuteCommand != nil {
+	if e.MockExecuteCommandWithStdin != nil {
 		return e.MockExecuteCommandWithStdin(timeout, command, stdin, arg...)
 	}
 
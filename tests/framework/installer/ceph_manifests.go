@@ -148,6 +148,7 @@ spec:
     allowUnsupported: ` + strconv.FormatBool(m.settings.CephVersion.AllowUnsupported) + `
   skipUpgradeChecks: true
   continueUpgradeAfterChecksEvenIfNotHealthy: false
+  allowUnsafePools: true
   mgr:
     count: ` + strconv.Itoa(mgrCount) + `
     allowMultiplePerNode: true